@@ -117,8 +117,7 @@ type _commitMsg Commit
 // UnmarshalJSON implements json.Unmarshaller for Commit
 func (cm *Commit) UnmarshalJSON(data []byte) error {
 	// first check to see if this is a valid path ref
-	var path string
-	if err := json.Unmarshal(data, &path); err == nil {
+	if path, ok := unmarshalPathRef(data); ok {
 		*cm = Commit{Path: path}
 		return nil
 	}
@@ -29,6 +29,18 @@ func CompareDatasets(a, b *Dataset) error {
 	if a.BodyPath != b.BodyPath {
 		return fmt.Errorf("BodyPath: %s != %s", a.BodyPath, b.BodyPath)
 	}
+	if a.BodyIndexPath != b.BodyIndexPath {
+		return fmt.Errorf("BodyIndexPath: %s != %s", a.BodyIndexPath, b.BodyIndexPath)
+	}
+	if a.BodyPartitionsPath != b.BodyPartitionsPath {
+		return fmt.Errorf("BodyPartitionsPath: %s != %s", a.BodyPartitionsPath, b.BodyPartitionsPath)
+	}
+	if err := CompareNamedBodies(a.Bodies, b.Bodies); err != nil {
+		return fmt.Errorf("Bodies: %s", err.Error())
+	}
+	if !reflect.DeepEqual(a.FormatDefaults, b.FormatDefaults) {
+		return fmt.Errorf("FormatDefaults mismatch")
+	}
 	if err := CompareCommits(a.Commit, b.Commit); err != nil {
 		return fmt.Errorf("Commit: %s", err.Error())
 	}
@@ -100,6 +112,9 @@ func CompareMetas(a, b *Meta) error {
 	if err := CompareLicenses(a.License, b.License); err != nil {
 		return fmt.Errorf("License: %s", err)
 	}
+	if (a.Retention == nil) != (b.Retention == nil) || a.Retention != nil && *a.Retention != *b.Retention {
+		return fmt.Errorf("Retention: %v != %v", a.Retention, b.Retention)
+	}
 	if a.Version != b.Version {
 		return fmt.Errorf("Version: %s != %s", a.Version, b.Version)
 	}
@@ -147,6 +162,9 @@ func CompareStructures(a, b *Structure) error {
 	if a.Checksum != b.Checksum {
 		return fmt.Errorf("Checksum: %s != %s", a.Checksum, b.Checksum)
 	}
+	if !reflect.DeepEqual(a.ChunkChecksums, b.ChunkChecksums) {
+		return fmt.Errorf("ChunkChecksums mismatch")
+	}
 	if a.Depth != b.Depth {
 		return fmt.Errorf("Depth: %d != %d", a.Depth, b.Depth)
 	}
@@ -159,6 +177,9 @@ func CompareStructures(a, b *Structure) error {
 	if a.Compression != b.Compression {
 		return fmt.Errorf("Compression: %s != %s", a.Compression, b.Compression)
 	}
+	if !reflect.DeepEqual(a.Partition, b.Partition) {
+		return fmt.Errorf("Partition mismatch")
+	}
 
 	if (a.FormatConfig != nil && b.FormatConfig == nil) || (a.FormatConfig == nil && b.FormatConfig != nil) {
 		return fmt.Errorf("FormatConfig nil mismatch")
@@ -17,18 +17,28 @@ const (
 	Gzip
 	// Tar specifies tar compression
 	Tar
+	// Bzip2 specifies bzip2 compression
+	Bzip2
+	// Zstd specifies zstandard compression
+	Zstd
 )
 
 // Names maps the name of a hash to codes
 var Names = map[Type]string{
-	None: "",
-	Gzip: "gzip",
-	Tar:  "tar",
+	None:  "",
+	Gzip:  "gzip",
+	Tar:   "tar",
+	Bzip2: "bzip2",
+	Zstd:  "zstd",
 }
 
 // Codes maps a hash code to it's name
 var Codes = map[string]Type{
-	"": None,
+	"":      None,
+	"gzip":  Gzip,
+	"tar":   Tar,
+	"bzip2": Bzip2,
+	"zstd":  Zstd,
 }
 
 // ParseTypeString returns a compression type for a given string
@@ -0,0 +1,179 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// maxExactFloat is the largest magnitude float64 can hold every integer below
+const maxExactFloat = 1 << 53
+
+// CanonicalConfig normalizes a configuration map so semantically identical
+// configs encode the same way, keeping hashes of documents that record
+// configuration stable. Numbers are normalized regardless of how they were
+// decoded: whole numbers become int64 & others float64, so 1, 1.0 &
+// json.Number("1") are all int64(1). Nested maps become
+// map[string]interface{} and slices []interface{}. Times are written as
+// UTC RFC3339 strings & byte slices as base64 strings.
+//
+// Configs must be representable as JSON: keys must be non-empty strings, and
+// NaN, infinite numbers & values of other types are an error. Key order needs
+// no normalizing, JSON encoding sorts map keys
+func CanonicalConfig(cfg map[string]interface{}) (map[string]interface{}, error) {
+	return canonicalConfig(cfg, false)
+}
+
+// canonicalConfig normalizes cfg. with roundTrip set, values of unsupported
+// types are normalized from their JSON encoding instead of being an error
+func canonicalConfig(cfg map[string]interface{}, roundTrip bool) (map[string]interface{}, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	v, err := canonicalConfigValue("config", cfg, roundTrip)
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]interface{}), nil
+}
+
+// canonicalConfigValue normalizes a config value, path locates the value in
+// errors
+func canonicalConfigValue(path string, v interface{}, roundTrip bool) (interface{}, error) {
+	switch t := v.(type) {
+	case nil, bool, string:
+		return v, nil
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n, nil
+		}
+		f, err := t.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid number %q", path, t.String())
+		}
+		return canonicalFloat(path, f)
+	case float64:
+		return canonicalFloat(path, t)
+	case float32:
+		// format at 32 bit precision so float32(0.1) reads as 0.1
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(float64(t), 'g', -1, 32), 64)
+		return canonicalFloat(path, f)
+	case time.Time:
+		return t.UTC().Format(time.RFC3339Nano), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(t), nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			if key == "" {
+				return nil, fmt.Errorf("%s: config keys can't be empty", path)
+			}
+			cv, err := canonicalConfigValue(path+"."+key, val, roundTrip)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = cv
+		}
+		return m, nil
+	case []interface{}:
+		arr := make([]interface{}, len(t))
+		for i, val := range t {
+			cv, err := canonicalConfigValue(fmt.Sprintf("%s[%d]", path, i), val, roundTrip)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = cv
+		}
+		return arr, nil
+	}
+
+	// other numeric, map & slice types, like int, map[string]string or
+	// map[interface{}]interface{} from YAML decoders
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return u, nil
+		}
+		return int64(u), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Map:
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key()
+			if key.Kind() == reflect.Interface {
+				key = key.Elem()
+			}
+			if key.Kind() != reflect.String {
+				return nil, fmt.Errorf("%s: config keys must be strings, got %s", path, key.Kind())
+			}
+			m[key.String()] = iter.Value().Interface()
+		}
+		return canonicalConfigValue(path, m, roundTrip)
+	case reflect.Slice, reflect.Array:
+		arr := make([]interface{}, rv.Len())
+		for i := range arr {
+			arr[i] = rv.Index(i).Interface()
+		}
+		return canonicalConfigValue(path, arr, roundTrip)
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return canonicalConfigValue(path, rv.Elem().Interface(), roundTrip)
+	}
+	if roundTrip {
+		return roundTripConfigValue(path, v)
+	}
+	return nil, fmt.Errorf("%s: unsupported config value type %T", path, v)
+}
+
+// roundTripConfigValue normalizes a value of a type CanonicalConfig doesn't
+// know, like a struct, from its JSON encoding
+func roundTripConfigValue(path string, v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return canonicalConfigValue(path, decoded, false)
+}
+
+// canonicalFloat gives whole numbers as int64
+func canonicalFloat(path string, f float64) (interface{}, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%s: %v isn't a valid config number", path, f)
+	}
+	if f == math.Trunc(f) && math.Abs(f) <= maxExactFloat {
+		return int64(f), nil
+	}
+	return f, nil
+}
+
+// CanonicalizeConfig normalizes the transform's config in place, see
+// CanonicalConfig
+func (q *Transform) CanonicalizeConfig() error {
+	cfg, err := CanonicalConfig(q.Config)
+	if err != nil {
+		return err
+	}
+	q.Config = cfg
+	return nil
+}
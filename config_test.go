@@ -0,0 +1,127 @@
+package dataset
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCanonicalConfig(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*60*60))
+	cases := []struct {
+		in     map[string]interface{}
+		expect map[string]interface{}
+		err    string
+	}{
+		{nil, nil, ""},
+		{map[string]interface{}{}, map[string]interface{}{}, ""},
+		{map[string]interface{}{
+			"int":     1,
+			"float":   1.0,
+			"number":  json.Number("1.0"),
+			"frac":    json.Number("2.5"),
+			"uint8":   uint8(3),
+			"float32": float32(0.1),
+			"big":     1e21,
+		}, map[string]interface{}{
+			"int":     int64(1),
+			"float":   int64(1),
+			"number":  int64(1),
+			"frac":    2.5,
+			"uint8":   int64(3),
+			"float32": 0.1,
+			"big":     1e21,
+		}, ""},
+		{map[string]interface{}{
+			"nested": map[interface{}]interface{}{"a": []int{1, 2}},
+			"tags":   map[string]string{"b": "c"},
+			"when":   ts,
+			"raw":    []byte("hi"),
+			"null":   nil,
+		}, map[string]interface{}{
+			"nested": map[string]interface{}{"a": []interface{}{int64(1), int64(2)}},
+			"tags":   map[string]interface{}{"b": "c"},
+			"when":   "2020-01-02T08:04:05Z",
+			"raw":    "aGk=",
+			"null":   nil,
+		}, ""},
+		{map[string]interface{}{"": 1}, nil, "config: config keys can't be empty"},
+		{map[string]interface{}{"a": map[interface{}]interface{}{1: "b"}}, nil, "config.a: config keys must be strings, got int"},
+		{map[string]interface{}{"a": []interface{}{math.NaN()}}, nil, "config.a[0]: NaN isn't a valid config number"},
+		{map[string]interface{}{"a": struct{}{}}, nil, "config.a: unsupported config value type struct {}"},
+	}
+
+	for i, c := range cases {
+		got, err := CanonicalConfig(c.in)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("case %d result mismatch.\nexpected: %#v\ngot:      %#v", i, c.expect, got)
+		}
+	}
+}
+
+func TestTransformConfigHash(t *testing.T) {
+	decoded := &Transform{}
+	if err := json.Unmarshal([]byte(`{"config":{"limit":10.0,"ratio":0.5,"opts":{"b":2,"a":[1]}}}`), decoded); err != nil {
+		t.Fatal(err)
+	}
+	built := &Transform{Config: map[string]interface{}{
+		"opts":  map[string]interface{}{"a": []int{1}, "b": json.Number("2")},
+		"ratio": float32(0.5),
+		"limit": int64(10),
+	}}
+
+	a, err := JSONHash(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := JSONHash(built)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("expected equivalent configs to hash the same. %s != %s", a, b)
+	}
+
+	bad := &Transform{Config: map[string]interface{}{"limit": math.Inf(1)}}
+	if _, err := bad.MarshalJSON(); err == nil {
+		t.Error("expected an error marshaling an invalid config")
+	}
+
+	type opts struct {
+		A []int   `json:"a"`
+		B float64 `json:"b"`
+	}
+	typed := &Transform{Config: map[string]interface{}{
+		"opts":  opts{A: []int{1}, B: 2},
+		"ratio": 0.5,
+		"limit": 10,
+	}}
+	c, err := JSONHash(typed)
+	if err != nil {
+		t.Fatalf("expected unknown config value types to marshal from their JSON encoding, got: %s", err)
+	}
+	if a != c {
+		t.Errorf("expected a struct config value to hash like its JSON encoding. %s != %s", a, c)
+	}
+	if _, err := CanonicalConfig(typed.Config); err == nil {
+		t.Error("expected CanonicalConfig to reject unknown config value types")
+	}
+
+	unmarshalable := &Transform{Config: map[string]interface{}{"fn": func() {}}}
+	if _, err := unmarshalable.MarshalJSON(); err == nil {
+		t.Error("expected an error marshaling a config value JSON can't encode")
+	}
+
+	if err := built.CanonicalizeConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if built.Config["limit"] != int64(10) || built.Config["ratio"] != 0.5 {
+		t.Errorf("expected config to be canonicalized in place, got: %v", built.Config)
+	}
+}
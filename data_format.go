@@ -3,6 +3,7 @@ package dataset
 import (
 	"encoding/json"
 	"fmt"
+	"mime"
 )
 
 // ErrUnknownDataFormat is the expected error for
@@ -32,6 +33,38 @@ const (
 	XMLDataFormat
 	// XLSXDataFormat specifies microsoft excel formatted data
 	XLSXDataFormat
+	// NDJSONDataFormat specifies newline-delimited JSON, also known as JSON
+	// Lines, with one entry per line
+	NDJSONDataFormat
+	// ParquetDataFormat specifies apache parquet columnar data
+	ParquetDataFormat
+	// ArrowDataFormat specifies apache arrow IPC streams
+	ArrowDataFormat
+	// AvroDataFormat specifies apache avro object container files
+	AvroDataFormat
+	// TSVDataFormat specifies tab separated value-formatted data
+	TSVDataFormat
+	// YAMLDataFormat specifies YAML-formatted data
+	YAMLDataFormat
+	// HTMLDataFormat specifies tables in HyperText Markup Language documents
+	HTMLDataFormat
+	// MarkdownDataFormat specifies GitHub-flavored markdown tables
+	MarkdownDataFormat
+	// ProtobufDataFormat specifies length-prefixed protocol buffer messages
+	ProtobufDataFormat
+	// ODSDataFormat specifies OpenDocument spreadsheets
+	ODSDataFormat
+	// XLSDataFormat specifies legacy binary (BIFF8) microsoft excel
+	// spreadsheets, which are read-only
+	XLSDataFormat
+	// ORCDataFormat specifies apache ORC columnar files, which are
+	// write-only
+	ORCDataFormat
+	// GeoJSONDataFormat specifies GeoJSON FeatureCollections, which are
+	// read-only
+	GeoJSONDataFormat
+	// ShapefileDataFormat specifies ESRI shapefiles, which are read-only
+	ShapefileDataFormat
 )
 
 // SupportedDataFormats gives a slice of data formats that are
@@ -44,18 +77,46 @@ func SupportedDataFormats() []DataFormat {
 		JSONDataFormat,
 		CSVDataFormat,
 		XLSXDataFormat,
+		NDJSONDataFormat,
+		ParquetDataFormat,
+		ArrowDataFormat,
+		AvroDataFormat,
+		TSVDataFormat,
+		YAMLDataFormat,
+		HTMLDataFormat,
+		MarkdownDataFormat,
+		ProtobufDataFormat,
+		ODSDataFormat,
+		XLSDataFormat,
+		ORCDataFormat,
+		GeoJSONDataFormat,
+		ShapefileDataFormat,
 	}
 }
 
 // String implements stringer interface for DataFormat
 func (f DataFormat) String() string {
 	s, ok := map[DataFormat]string{
-		UnknownDataFormat: "",
-		CSVDataFormat:     "csv",
-		JSONDataFormat:    "json",
-		XMLDataFormat:     "xml",
-		XLSXDataFormat:    "xlsx",
-		CBORDataFormat:    "cbor",
+		UnknownDataFormat:   "",
+		CSVDataFormat:       "csv",
+		JSONDataFormat:      "json",
+		XMLDataFormat:       "xml",
+		XLSXDataFormat:      "xlsx",
+		CBORDataFormat:      "cbor",
+		NDJSONDataFormat:    "ndjson",
+		ParquetDataFormat:   "parquet",
+		ArrowDataFormat:     "arrow",
+		AvroDataFormat:      "avro",
+		TSVDataFormat:       "tsv",
+		YAMLDataFormat:      "yaml",
+		HTMLDataFormat:      "html",
+		MarkdownDataFormat:  "md",
+		ProtobufDataFormat:  "protobuf",
+		ODSDataFormat:       "ods",
+		XLSDataFormat:       "xls",
+		ORCDataFormat:       "orc",
+		GeoJSONDataFormat:   "geojson",
+		ShapefileDataFormat: "shapefile",
 	}[f]
 
 	if !ok {
@@ -69,17 +130,54 @@ func (f DataFormat) String() string {
 // TODO (b5): trim "." prefix, remove prefixed map keys
 func ParseDataFormatString(s string) (df DataFormat, err error) {
 	df, ok := map[string]DataFormat{
-		"":      UnknownDataFormat,
-		".csv":  CSVDataFormat,
-		"csv":   CSVDataFormat,
-		".json": JSONDataFormat,
-		"json":  JSONDataFormat,
-		".xml":  XMLDataFormat,
-		"xml":   XMLDataFormat,
-		".xlsx": XLSXDataFormat,
-		"xlsx":  XLSXDataFormat,
-		"cbor":  CBORDataFormat,
-		".cbor": CBORDataFormat,
+		"":          UnknownDataFormat,
+		".csv":      CSVDataFormat,
+		"csv":       CSVDataFormat,
+		".json":     JSONDataFormat,
+		"json":      JSONDataFormat,
+		".xml":      XMLDataFormat,
+		"xml":       XMLDataFormat,
+		".xlsx":     XLSXDataFormat,
+		"xlsx":      XLSXDataFormat,
+		"cbor":      CBORDataFormat,
+		".cbor":     CBORDataFormat,
+		"ndjson":    NDJSONDataFormat,
+		".ndjson":   NDJSONDataFormat,
+		"jsonl":     NDJSONDataFormat,
+		".jsonl":    NDJSONDataFormat,
+		"parquet":   ParquetDataFormat,
+		".parquet":  ParquetDataFormat,
+		"arrow":     ArrowDataFormat,
+		".arrow":    ArrowDataFormat,
+		".arrows":   ArrowDataFormat,
+		"avro":      AvroDataFormat,
+		".avro":     AvroDataFormat,
+		"tsv":       TSVDataFormat,
+		".tsv":      TSVDataFormat,
+		"yaml":      YAMLDataFormat,
+		".yaml":     YAMLDataFormat,
+		"yml":       YAMLDataFormat,
+		".yml":      YAMLDataFormat,
+		"html":      HTMLDataFormat,
+		".html":     HTMLDataFormat,
+		".htm":      HTMLDataFormat,
+		"md":        MarkdownDataFormat,
+		".md":       MarkdownDataFormat,
+		"markdown":  MarkdownDataFormat,
+		"protobuf":  ProtobufDataFormat,
+		"pb":        ProtobufDataFormat,
+		".pb":       ProtobufDataFormat,
+		"ods":       ODSDataFormat,
+		".ods":      ODSDataFormat,
+		"xls":       XLSDataFormat,
+		".xls":      XLSDataFormat,
+		"orc":       ORCDataFormat,
+		".orc":      ORCDataFormat,
+		"geojson":   GeoJSONDataFormat,
+		".geojson":  GeoJSONDataFormat,
+		"shapefile": ShapefileDataFormat,
+		"shp":       ShapefileDataFormat,
+		".shp":      ShapefileDataFormat,
 	}[s]
 	if !ok {
 		err = fmt.Errorf("invalid data format: `%s`", s)
@@ -89,6 +187,70 @@ func ParseDataFormatString(s string) (df DataFormat, err error) {
 	return
 }
 
+// mimeTypes maps data formats to their preferred media type
+var mimeTypes = map[DataFormat]string{
+	CSVDataFormat:       "text/csv",
+	JSONDataFormat:      "application/json",
+	XMLDataFormat:       "application/xml",
+	XLSXDataFormat:      "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	CBORDataFormat:      "application/cbor",
+	NDJSONDataFormat:    "application/x-ndjson",
+	ParquetDataFormat:   "application/vnd.apache.parquet",
+	ArrowDataFormat:     "application/vnd.apache.arrow.stream",
+	AvroDataFormat:      "application/avro",
+	TSVDataFormat:       "text/tab-separated-values",
+	YAMLDataFormat:      "application/yaml",
+	HTMLDataFormat:      "text/html",
+	MarkdownDataFormat:  "text/markdown",
+	ProtobufDataFormat:  "application/x-protobuf",
+	ODSDataFormat:       "application/vnd.oasis.opendocument.spreadsheet",
+	XLSDataFormat:       "application/vnd.ms-excel",
+	ORCDataFormat:       "application/vnd.apache.orc",
+	GeoJSONDataFormat:   "application/geo+json",
+	ShapefileDataFormat: "x-gis/x-shapefile",
+}
+
+// mimeAliases are media types that aren't preferred for a format, but are
+// in common use
+var mimeAliases = map[string]DataFormat{
+	"application/csv":                   CSVDataFormat,
+	"text/json":                         JSONDataFormat,
+	"text/xml":                          XMLDataFormat,
+	"application/jsonl":                 NDJSONDataFormat,
+	"application/vnd.apache.arrow.file": ArrowDataFormat,
+	"avro/binary":                       AvroDataFormat,
+	"text/tsv":                          TSVDataFormat,
+	"application/x-yaml":                YAMLDataFormat,
+	"text/yaml":                         YAMLDataFormat,
+	"text/x-markdown":                   MarkdownDataFormat,
+	"application/protobuf":              ProtobufDataFormat,
+	"application/vnd.google.protobuf":   ProtobufDataFormat,
+}
+
+// MIMEType gives the preferred media type for a data format, returning an
+// empty string for UnknownDataFormat
+func (f DataFormat) MIMEType() string {
+	return mimeTypes[f]
+}
+
+// ParseMIME gives the data format for a media type, as found in Content-Type
+// & Accept headers. media type parameters like charset are ignored
+func ParseMIME(s string) (DataFormat, error) {
+	mt, _, err := mime.ParseMediaType(s)
+	if err != nil {
+		return UnknownDataFormat, fmt.Errorf("invalid media type: `%s`", s)
+	}
+	for df, t := range mimeTypes {
+		if t == mt {
+			return df, nil
+		}
+	}
+	if df, ok := mimeAliases[mt]; ok {
+		return df, nil
+	}
+	return UnknownDataFormat, fmt.Errorf("no data format for media type: `%s`", s)
+}
+
 // MarshalJSON satisfies the json.Marshaler interface
 func (f DataFormat) MarshalJSON() ([]byte, error) {
 	if f == UnknownDataFormat {
@@ -1,7 +1,11 @@
 package dataset
 
 import (
+	"encoding/base64"
 	"fmt"
+	"strings"
+
+	"github.com/qri-io/dataset/dsio/charset"
 )
 
 // FormatConfig is the interface for data format configurations
@@ -20,8 +24,28 @@ func ParseFormatConfigMap(f DataFormat, opts map[string]interface{}) (FormatConf
 		return NewCSVOptions(opts)
 	case JSONDataFormat:
 		return NewJSONOptions(opts)
-	case XLSXDataFormat:
+	case XLSXDataFormat, XLSDataFormat:
 		return NewXLSXOptions(opts)
+	case ParquetDataFormat:
+		return NewParquetOptions(opts)
+	case ArrowDataFormat:
+		return NewArrowOptions(opts)
+	case AvroDataFormat:
+		return NewAvroOptions(opts)
+	case TSVDataFormat:
+		return NewTSVOptions(opts)
+	case XMLDataFormat:
+		return NewXMLOptions(opts)
+	case HTMLDataFormat:
+		return NewHTMLOptions(opts)
+	case ProtobufDataFormat:
+		return NewProtobufOptions(opts)
+	case ODSDataFormat:
+		return NewODSOptions(opts)
+	case ORCDataFormat:
+		return NewORCOptions(opts)
+	case CBORDataFormat:
+		return NewCBOROptions(opts)
 	default:
 		return nil, fmt.Errorf("cannot parse configuration for format: %s", f.String())
 	}
@@ -51,12 +75,16 @@ func NewCSVOptions(opts map[string]interface{}) (*CSVOptions, error) {
 	}
 
 	if opts["separator"] != nil {
-		if sep, ok := opts["separator"].(string); ok {
+		switch sep := opts["separator"].(type) {
+		case string:
 			if len(sep) != 1 {
 				return nil, fmt.Errorf("separator must be a single character")
 			}
 			o.Separator = rune(sep[0])
-		} else {
+		case rune:
+			// Map gives separators as runes
+			o.Separator = sep
+		default:
 			return nil, fmt.Errorf("invalid separator value: %v", opts["separator"])
 		}
 	}
@@ -69,9 +97,134 @@ func NewCSVOptions(opts map[string]interface{}) (*CSVOptions, error) {
 		}
 	}
 
+	if opts["skipFooterRows"] != nil {
+		switch n := opts["skipFooterRows"].(type) {
+		case int:
+			o.SkipFooterRows = n
+		case float64:
+			o.SkipFooterRows = int(n)
+		default:
+			return nil, fmt.Errorf("invalid skipFooterRows value: %v", opts["skipFooterRows"])
+		}
+		if o.SkipFooterRows < 0 {
+			return nil, fmt.Errorf("skipFooterRows cannot be negative")
+		}
+	}
+
+	if opts["dateLayouts"] != nil {
+		layouts, err := parseDateLayouts(opts["dateLayouts"])
+		if err != nil {
+			return nil, err
+		}
+		o.DateLayouts = layouts
+	}
+
+	enc, fb, err := parseEncoding(opts)
+	if err != nil {
+		return nil, err
+	}
+	o.Encoding, o.EncodingFallback = enc, fb
+	if o.StripBOM, o.EmitBOM, err = parseBOMOptions(opts); err != nil {
+		return nil, err
+	}
+
+	if opts["columns"] != nil {
+		cols, err := parseCSVColumns(opts["columns"])
+		if err != nil {
+			return nil, err
+		}
+		o.Columns = cols
+	}
+
+	ff, err := parseFloatFormat(opts)
+	if err != nil {
+		return nil, err
+	}
+	o.FloatFormat = ff
+
+	bt, err := parseBooleanTokens(opts)
+	if err != nil {
+		return nil, err
+	}
+	o.BooleanTokens = bt
+
+	if opts["parseUnits"] != nil {
+		if pu, ok := opts["parseUnits"].(bool); ok {
+			o.ParseUnits = pu
+		} else {
+			return nil, fmt.Errorf("invalid parseUnits value: %v", opts["parseUnits"])
+		}
+	}
+
+	if opts["numberLocale"] != nil {
+		loc, ok := opts["numberLocale"].(string)
+		if !ok || !validNumberLocale(loc) {
+			return nil, fmt.Errorf("invalid numberLocale value: %v", opts["numberLocale"])
+		}
+		o.NumberLocale = loc
+	}
+
+	if opts["quote"] != nil {
+		q, _ := opts["quote"].(string)
+		switch q {
+		case CSVQuoteMinimal, CSVQuoteAll, CSVQuoteNonNumeric, CSVQuoteNone:
+			o.Quote = q
+		default:
+			return nil, fmt.Errorf("invalid quote value: %v", opts["quote"])
+		}
+	}
+
+	if opts["lineTerminator"] != nil {
+		lt, _ := opts["lineTerminator"].(string)
+		if lt != "\n" && lt != "\r\n" {
+			return nil, fmt.Errorf("invalid lineTerminator value: %#v", opts["lineTerminator"])
+		}
+		o.LineTerminator = lt
+	}
+
+	if opts["requiredColumns"] != nil {
+		if rc, ok := opts["requiredColumns"].(bool); ok {
+			o.RequiredColumns = rc
+		} else {
+			return nil, fmt.Errorf("invalid requiredColumns value: %v", opts["requiredColumns"])
+		}
+	}
+
 	return o, nil
 }
 
+// CSV quoting policies
+const (
+	// CSVQuoteMinimal quotes fields that contain the separator, a quote, a
+	// line break or leading space, matching encoding/csv
+	CSVQuoteMinimal = "minimal"
+	// CSVQuoteAll quotes every field
+	CSVQuoteAll = "all"
+	// CSVQuoteNonNumeric quotes every field that isn't a number, including
+	// header titles. null values are written as unquoted empty fields
+	CSVQuoteNonNumeric = "nonNumeric"
+	// CSVQuoteNone never quotes fields. Writing a field that needs quotes is
+	// an error
+	CSVQuoteNone = "none"
+)
+
+// NumberLocales are the number conventions ParseUnits reads, by name:
+//
+//	en: 1,234.56
+//	de: 1.234,56
+//	fr: 1 234,56
+//	ch: 1'234.56
+var NumberLocales = []string{"en", "de", "fr", "ch"}
+
+func validNumberLocale(loc string) bool {
+	for _, l := range NumberLocales {
+		if l == loc {
+			return true
+		}
+	}
+	return false
+}
+
 // CSVOptions specifies configuration details for csv files
 // This'll expand in the future to interoperate with okfn csv spec
 type CSVOptions struct {
@@ -88,6 +241,347 @@ type CSVOptions struct {
 	// VariadicFields sets permits records to have a variable number of fields
 	// avoid using this
 	VariadicFields bool `json:"variadicFields"`
+	// DateLayouts maps column titles to time.Parse layout strings. Columns with
+	// a layout are read as time.Time values & written in that layout
+	DateLayouts map[string]string `json:"dateLayouts,omitempty"`
+	// SkipFooterRows is a number of rows at the end of the file to exclude from
+	// the body, for trailing totals & summaries. skipped rows are available
+	// from the reader once the body has been read
+	SkipFooterRows int `json:"skipFooterRows,omitempty"`
+	// Encoding is the charset files are read & written in, one of "utf-8",
+	// "utf-16le", "utf-16be", "iso-8859-1", "windows-1252" or "shift_jis".
+	// defaults to utf-8
+	Encoding string `json:"encoding,omitempty"`
+	// EncodingFallback sets how characters the encoding can't represent are
+	// written, one of "error", "replace" or "transliterate". defaults to error
+	EncodingFallback string `json:"encodingFallback,omitempty"`
+	// StripBOM removes a UTF-8 or UTF-16 byte order mark from the start of
+	// read files, so it isn't read as part of the first header title. A
+	// UTF-16 mark also sets the encoding files are read in
+	StripBOM bool `json:"stripBOM,omitempty"`
+	// EmitBOM writes the byte order mark of the encoding at the start of
+	// files, which Excel uses to detect UTF-8. Encodings without a byte order
+	// mark are written without one
+	EmitBOM bool `json:"emitBOM,omitempty"`
+	// Columns sets the order & header titles of written columns, so files
+	// can match a required layout. Body columns that aren't listed aren't
+	// written. Entries are written as-is when Columns is empty
+	Columns []CSVColumn `json:"columns,omitempty"`
+	// FloatFormat controls how floating point numbers are written
+	FloatFormat
+	// BooleanTokens sets the text of boolean values
+	BooleanTokens
+	// ParseUnits reads number & integer fields with currency symbols,
+	// percent signs & thousands separators, like "$1,234.50" or "12,5 %".
+	// The unit & number locale found in each column are recorded as "unit"
+	// & "locale" properties of the column in the reader's schema
+	ParseUnits bool `json:"parseUnits,omitempty"`
+	// NumberLocale is the locale ParseUnits reads numbers in, one of
+	// NumberLocales. When empty the locale of each column is detected from
+	// it's values
+	NumberLocale string `json:"numberLocale,omitempty"`
+	// Quote is the quoting policy of written fields, one of CSVQuoteMinimal,
+	// CSVQuoteAll, CSVQuoteNonNumeric or CSVQuoteNone. defaults to minimal
+	Quote string `json:"quote,omitempty"`
+	// LineTerminator ends written records, "\n" or "\r\n". defaults to "\n"
+	LineTerminator string `json:"lineTerminator,omitempty"`
+	// RequiredColumns reads empty fields of object rows by the "required"
+	// list of the row schema: empty fields in required columns are errors,
+	// empty fields in other columns are read as null. Rows are read as
+	// objects when the schema's items are an object with properties
+	RequiredColumns bool `json:"requiredColumns,omitempty"`
+}
+
+// CSVColumn maps a column of the body to a written csv column. Columns are
+// read from the body by Key if it's set & by Index otherwise
+type CSVColumn struct {
+	// Key is the schema title of the column to write, or the property name
+	// of object entries
+	Key string `json:"key,omitempty"`
+	// Index is the zero-based position of the column to write in array
+	// entries, used when Key is empty
+	Index int `json:"index,omitempty"`
+	// Title is the header row title of the column, defaulting to the title
+	// of the column it's read from
+	Title string `json:"title,omitempty"`
+}
+
+// parseCSVColumns reads a columns list. Columns are given as objects with
+// key or index & title properties, or a string shorthand for a key column
+func parseCSVColumns(v interface{}) ([]CSVColumn, error) {
+	switch t := v.(type) {
+	case []CSVColumn:
+		return t, nil
+	case []interface{}:
+		cols := make([]CSVColumn, len(t))
+		for i, c := range t {
+			switch ct := c.(type) {
+			case string:
+				if ct == "" {
+					return nil, fmt.Errorf("column %d: key can't be empty", i)
+				}
+				cols[i].Key = ct
+			case map[string]interface{}:
+				for prop, val := range ct {
+					switch prop {
+					case "key", "title":
+						s, ok := val.(string)
+						if !ok || s == "" {
+							return nil, fmt.Errorf("column %d: invalid %s value: %v", i, prop, val)
+						}
+						if prop == "key" {
+							cols[i].Key = s
+						} else {
+							cols[i].Title = s
+						}
+					case "index":
+						n, err := intOption(ct, "index")
+						if err != nil {
+							return nil, fmt.Errorf("column %d: %w", i, err)
+						}
+						cols[i].Index = n
+					default:
+						return nil, fmt.Errorf("column %d: unknown property '%s'", i, prop)
+					}
+				}
+				_, hasKey := ct["key"]
+				_, hasIndex := ct["index"]
+				if hasKey == hasIndex {
+					return nil, fmt.Errorf("column %d: exactly one of key or index is required", i)
+				}
+			default:
+				return nil, fmt.Errorf("column %d: invalid column value: %v", i, c)
+			}
+		}
+		return cols, nil
+	default:
+		return nil, fmt.Errorf("invalid columns value: %v", v)
+	}
+}
+
+// FloatFormat controls how writers render floating point numbers. The zero
+// value writes the fewest digits that read back as the same number
+type FloatFormat struct {
+	// SignificantDigits rounds floats to a number of significant digits, so
+	// 0.1+0.2 is written as 0.3 instead of 0.30000000000000004
+	SignificantDigits int `json:"significantDigits,omitempty"`
+	// DecimalPlaces maps column titles to a fixed number of digits after the
+	// decimal point, overriding SignificantDigits for that column. object
+	// entries are matched by property name
+	DecimalPlaces map[string]int `json:"decimalPlaces,omitempty"`
+	// ScientificThreshold writes floats with a magnitude of at least 1e+N or
+	// less than 1e-N in scientific notation. 0 never uses scientific notation
+	ScientificThreshold int `json:"scientificThreshold,omitempty"`
+}
+
+// IsEmpty reports whether the float format uses default formatting
+func (f FloatFormat) IsEmpty() bool {
+	return f.SignificantDigits == 0 && len(f.DecimalPlaces) == 0 && f.ScientificThreshold == 0
+}
+
+// parseFloatFormat reads float formatting options from a format config map
+func parseFloatFormat(opts map[string]interface{}) (f FloatFormat, err error) {
+	if f.SignificantDigits, err = intOption(opts, "significantDigits"); err != nil {
+		return f, err
+	}
+	if f.ScientificThreshold, err = intOption(opts, "scientificThreshold"); err != nil {
+		return f, err
+	}
+	switch t := opts["decimalPlaces"].(type) {
+	case nil:
+	case map[string]int:
+		f.DecimalPlaces = t
+	case map[string]interface{}:
+		f.DecimalPlaces = make(map[string]int, len(t))
+		for col, v := range t {
+			n, err := intOption(t, col)
+			if err != nil {
+				return f, fmt.Errorf("invalid decimalPlaces value for column %s: %v", col, v)
+			}
+			f.DecimalPlaces[col] = n
+		}
+	default:
+		return f, fmt.Errorf("invalid decimalPlaces value: %v", t)
+	}
+	return f, nil
+}
+
+// intOption reads a non-negative whole number option, accepting the float64
+// values JSON decoding gives
+func intOption(opts map[string]interface{}, key string) (int, error) {
+	switch n := opts[key].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		if n >= 0 {
+			return n, nil
+		}
+	case float64:
+		if n >= 0 && n == float64(int(n)) {
+			return int(n), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid %s value: %v", key, opts[key])
+}
+
+// addToMap adds non-default float formatting options to a format config map
+func (f FloatFormat) addToMap(opt map[string]interface{}) {
+	if f.SignificantDigits > 0 {
+		opt["significantDigits"] = f.SignificantDigits
+	}
+	if len(f.DecimalPlaces) > 0 {
+		places := make(map[string]interface{}, len(f.DecimalPlaces))
+		for col, n := range f.DecimalPlaces {
+			places[col] = n
+		}
+		opt["decimalPlaces"] = places
+	}
+	if f.ScientificThreshold > 0 {
+		opt["scientificThreshold"] = f.ScientificThreshold
+	}
+}
+
+// BooleanTokens sets the text boolean values are read from & written as in
+// text formats, for files that use tokens like "Y"/"N", "1"/"0" or
+// "yes"/"no". Tokens are matched case-insensitively in columns typed
+// boolean. Writers use the first token of each list. When unset, the tokens
+// strconv.ParseBool accepts are read & "true"/"false" are written
+type BooleanTokens struct {
+	TrueTokens  []string `json:"trueTokens,omitempty"`
+	FalseTokens []string `json:"falseTokens,omitempty"`
+}
+
+// IsEmpty reports whether the default boolean tokens are used
+func (b BooleanTokens) IsEmpty() bool {
+	return len(b.TrueTokens) == 0 && len(b.FalseTokens) == 0
+}
+
+// parseBooleanTokens reads boolean token options from a format config map
+func parseBooleanTokens(opts map[string]interface{}) (b BooleanTokens, err error) {
+	if b.TrueTokens, err = stringsOption(opts, "trueTokens"); err != nil {
+		return b, err
+	}
+	if b.FalseTokens, err = stringsOption(opts, "falseTokens"); err != nil {
+		return b, err
+	}
+	if len(b.TrueTokens) == 0 != (len(b.FalseTokens) == 0) {
+		return b, fmt.Errorf("trueTokens and falseTokens must be set together")
+	}
+	seen := map[string]bool{}
+	for _, tok := range b.TrueTokens {
+		seen[strings.ToLower(tok)] = true
+	}
+	for _, tok := range b.FalseTokens {
+		if seen[strings.ToLower(tok)] {
+			return b, fmt.Errorf("boolean token %q can't be both true and false", tok)
+		}
+	}
+	return b, nil
+}
+
+// stringsOption reads a list of non-empty strings, accepting the
+// []interface{} values JSON decoding gives
+func stringsOption(opts map[string]interface{}, key string) ([]string, error) {
+	switch t := opts[key].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		for _, s := range t {
+			if s == "" {
+				return nil, fmt.Errorf("invalid %s value: tokens can't be empty", key)
+			}
+		}
+		return t, nil
+	case []interface{}:
+		strs := make([]string, len(t))
+		for i, v := range t {
+			s, ok := v.(string)
+			if !ok || s == "" {
+				return nil, fmt.Errorf("invalid %s value: %v", key, v)
+			}
+			strs[i] = s
+		}
+		return strs, nil
+	default:
+		return nil, fmt.Errorf("invalid %s value: %v", key, t)
+	}
+}
+
+// addToMap adds non-default boolean tokens to a format config map
+func (b BooleanTokens) addToMap(opt map[string]interface{}) {
+	for key, toks := range map[string][]string{"trueTokens": b.TrueTokens, "falseTokens": b.FalseTokens} {
+		if len(toks) == 0 {
+			continue
+		}
+		vals := make([]interface{}, len(toks))
+		for i, tok := range toks {
+			vals[i] = tok
+		}
+		opt[key] = vals
+	}
+}
+
+// parseDateLayouts reads a column title to layout map, accepting the
+// map[string]interface{} decoding JSON gives
+func parseDateLayouts(v interface{}) (map[string]string, error) {
+	switch t := v.(type) {
+	case map[string]string:
+		return t, nil
+	case map[string]interface{}:
+		layouts := make(map[string]string, len(t))
+		for col, l := range t {
+			layout, ok := l.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid dateLayouts value for column %s: %v", col, l)
+			}
+			layouts[col] = layout
+		}
+		return layouts, nil
+	default:
+		return nil, fmt.Errorf("invalid dateLayouts value: %v", v)
+	}
+}
+
+// parseEncoding reads the charset & fallback options of a format config map,
+// giving the canonical charset name
+func parseEncoding(opts map[string]interface{}) (enc, fb string, err error) {
+	if opts["encoding"] != nil {
+		s, ok := opts["encoding"].(string)
+		if !ok {
+			return "", "", fmt.Errorf("invalid encoding value: %v", opts["encoding"])
+		}
+		if enc, err = charset.Normalize(s); err != nil {
+			return "", "", err
+		}
+	}
+	if opts["encodingFallback"] != nil {
+		s, ok := opts["encodingFallback"].(string)
+		if !ok {
+			return "", "", fmt.Errorf("invalid encodingFallback value: %v", opts["encodingFallback"])
+		}
+		if _, err = charset.ParseFallback(s); err != nil {
+			return "", "", err
+		}
+		fb = s
+	}
+	return enc, fb, nil
+}
+
+// parseBOMOptions reads the byte order mark options of a format config map
+func parseBOMOptions(opts map[string]interface{}) (strip, emit bool, err error) {
+	if opts["stripBOM"] != nil {
+		var ok bool
+		if strip, ok = opts["stripBOM"].(bool); !ok {
+			return false, false, fmt.Errorf("invalid stripBOM value: %v", opts["stripBOM"])
+		}
+	}
+	if opts["emitBOM"] != nil {
+		var ok bool
+		if emit, ok = opts["emitBOM"].(bool); !ok {
+			return false, false, fmt.Errorf("invalid emitBOM value: %v", opts["emitBOM"])
+		}
+	}
+	return strip, emit, nil
 }
 
 // Format announces the CSV Data Format for the FormatConfig interface
@@ -113,6 +607,61 @@ func (o *CSVOptions) Map() map[string]interface{} {
 	if o.Separator != rune(0) {
 		opt["separator"] = o.Separator
 	}
+	if o.SkipFooterRows > 0 {
+		opt["skipFooterRows"] = o.SkipFooterRows
+	}
+	if len(o.DateLayouts) > 0 {
+		layouts := make(map[string]interface{}, len(o.DateLayouts))
+		for col, l := range o.DateLayouts {
+			layouts[col] = l
+		}
+		opt["dateLayouts"] = layouts
+	}
+	if o.Encoding != "" {
+		opt["encoding"] = o.Encoding
+	}
+	if o.EncodingFallback != "" {
+		opt["encodingFallback"] = o.EncodingFallback
+	}
+	if o.StripBOM {
+		opt["stripBOM"] = o.StripBOM
+	}
+	if o.EmitBOM {
+		opt["emitBOM"] = o.EmitBOM
+	}
+	if len(o.Columns) > 0 {
+		cols := make([]interface{}, len(o.Columns))
+		for i, c := range o.Columns {
+			col := map[string]interface{}{}
+			if c.Key != "" {
+				col["key"] = c.Key
+			} else {
+				col["index"] = c.Index
+			}
+			if c.Title != "" {
+				col["title"] = c.Title
+			}
+			cols[i] = col
+		}
+		opt["columns"] = cols
+	}
+	o.FloatFormat.addToMap(opt)
+	o.BooleanTokens.addToMap(opt)
+	if o.ParseUnits {
+		opt["parseUnits"] = o.ParseUnits
+	}
+	if o.NumberLocale != "" {
+		opt["numberLocale"] = o.NumberLocale
+	}
+	if o.Quote != "" {
+		opt["quote"] = o.Quote
+	}
+	if o.LineTerminator != "" {
+		opt["lineTerminator"] = o.LineTerminator
+	}
+	if o.RequiredColumns {
+		opt["requiredColumns"] = o.RequiredColumns
+	}
 	return opt
 }
 
@@ -122,13 +671,78 @@ func NewJSONOptions(opts map[string]interface{}) (*JSONOptions, error) {
 	if opts == nil {
 		return o, nil
 	}
+
+	if opts["escapeHTML"] != nil {
+		if eh, ok := opts["escapeHTML"].(bool); ok {
+			o.EscapeHTML = &eh
+		} else {
+			return nil, fmt.Errorf("invalid escapeHTML value: %v", opts["escapeHTML"])
+		}
+	}
+
+	if opts["asciiOnly"] != nil {
+		if ao, ok := opts["asciiOnly"].(bool); ok {
+			o.ASCIIOnly = ao
+		} else {
+			return nil, fmt.Errorf("invalid asciiOnly value: %v", opts["asciiOnly"])
+		}
+	}
+
+	enc, fb, err := parseEncoding(opts)
+	if err != nil {
+		return nil, err
+	}
+	o.Encoding, o.EncodingFallback = enc, fb
+	if o.StripBOM, o.EmitBOM, err = parseBOMOptions(opts); err != nil {
+		return nil, err
+	}
+
+	if opts["indent"] != nil {
+		indent, ok := opts["indent"].(string)
+		if !ok || strings.Trim(indent, " \t") != "" {
+			return nil, fmt.Errorf("invalid indent value: %v", opts["indent"])
+		}
+		o.Indent = indent
+	}
+
+	ff, err := parseFloatFormat(opts)
+	if err != nil {
+		return nil, err
+	}
+	o.FloatFormat = ff
+
 	return o, nil
 }
 
 // JSONOptions specifies configuration details for json file format
 type JSONOptions struct {
-	// TODO:
-	// Indent string
+	// EscapeHTML writes <, > and & in strings as \u escapes, making output safe
+	// to embed in HTML. defaults to true, set false to write them as-is
+	EscapeHTML *bool `json:"escapeHTML,omitempty"`
+	// ASCIIOnly writes all non-ASCII characters in strings as \u escapes
+	ASCIIOnly bool `json:"asciiOnly"`
+	// Encoding is the charset files are read & written in, one of "utf-8",
+	// "utf-16le", "utf-16be", "iso-8859-1", "windows-1252" or "shift_jis".
+	// defaults to utf-8
+	Encoding string `json:"encoding,omitempty"`
+	// EncodingFallback sets how characters the encoding can't represent are
+	// written, one of "error", "replace" or "transliterate". defaults to error
+	EncodingFallback string `json:"encodingFallback,omitempty"`
+	// StripBOM removes a UTF-8 or UTF-16 byte order mark from the start of
+	// read files, which would otherwise be an invalid token. A UTF-16 mark
+	// also sets the encoding files are read in
+	StripBOM bool `json:"stripBOM,omitempty"`
+	// EmitBOM writes the byte order mark of the encoding at the start of
+	// files. Encodings without a byte order mark are written without one
+	EmitBOM bool `json:"emitBOM,omitempty"`
+	// Indent writes bodies pretty-printed, one entry per line with nested
+	// values indented by this string, which must be spaces or tabs. Keys of
+	// nested objects are written in sorted order, keeping output stable to
+	// diff.
+	// Indented bodies are much larger, so it's best kept to small datasets
+	Indent string `json:"indent,omitempty"`
+	// FloatFormat controls how floating point numbers are written
+	FloatFormat
 }
 
 // Format announces the JSON Data Format for the FormatConfig interface
@@ -141,12 +755,40 @@ func (o *JSONOptions) Map() map[string]interface{} {
 	if o == nil {
 		return nil
 	}
-	return map[string]interface{}{}
+	opt := map[string]interface{}{}
+	if o.EscapeHTML != nil {
+		opt["escapeHTML"] = *o.EscapeHTML
+	}
+	if o.ASCIIOnly {
+		opt["asciiOnly"] = o.ASCIIOnly
+	}
+	if o.Encoding != "" {
+		opt["encoding"] = o.Encoding
+	}
+	if o.EncodingFallback != "" {
+		opt["encodingFallback"] = o.EncodingFallback
+	}
+	if o.StripBOM {
+		opt["stripBOM"] = o.StripBOM
+	}
+	if o.EmitBOM {
+		opt["emitBOM"] = o.EmitBOM
+	}
+	if o.Indent != "" {
+		opt["indent"] = o.Indent
+	}
+	o.FloatFormat.addToMap(opt)
+	return opt
 }
 
 // XLSXOptions specifies configuraiton details for the xlsx file format
 type XLSXOptions struct {
 	SheetName string `json:"sheetName,omitempty"`
+	// SheetIndex selects the sheet to read by it's zero-based position in
+	// the workbook, in place of SheetName
+	SheetIndex *int `json:"sheetIndex,omitempty"`
+	// BooleanTokens sets the text of boolean values read from text cells
+	BooleanTokens
 }
 
 // NewXLSXOptions creates a XLSXOptions pointer from a map
@@ -164,6 +806,23 @@ func NewXLSXOptions(opts map[string]interface{}) (FormatConfig, error) {
 		}
 	}
 
+	if opts["sheetIndex"] != nil {
+		i, err := intOption(opts, "sheetIndex")
+		if err != nil {
+			return nil, err
+		}
+		if o.SheetName != "" {
+			return nil, fmt.Errorf("sheetName and sheetIndex can't both be set")
+		}
+		o.SheetIndex = &i
+	}
+
+	bt, err := parseBooleanTokens(opts)
+	if err != nil {
+		return nil, err
+	}
+	o.BooleanTokens = bt
+
 	return o, nil
 }
 
@@ -181,6 +840,775 @@ func (o *XLSXOptions) Map() map[string]interface{} {
 	if o.SheetName != "" {
 		opt["sheetName"] = o.SheetName
 	}
+	if o.SheetIndex != nil {
+		opt["sheetIndex"] = *o.SheetIndex
+	}
+	o.BooleanTokens.addToMap(opt)
+
+	return opt
+}
+
+// Parquet compression codecs
+const (
+	ParquetUncompressed = "uncompressed"
+	ParquetSnappy       = "snappy"
+	ParquetGzip         = "gzip"
+)
+
+// ParquetOptions specifies configuration details for the parquet file format
+type ParquetOptions struct {
+	// Compression is the codec column chunks are written with, one of
+	// "uncompressed", "snappy" or "gzip". defaults to snappy
+	Compression string `json:"compression,omitempty"`
+	// RowGroupSize is the number of rows written to each row group, defaults
+	// to 10000
+	RowGroupSize int `json:"rowGroupSize,omitempty"`
+	// Columns are codec & encoding hints for columns by title. Column
+	// compression overrides Compression
+	Columns map[string]ColumnCodec `json:"columns,omitempty"`
+}
+
+// NewParquetOptions creates a ParquetOptions pointer from a map
+func NewParquetOptions(opts map[string]interface{}) (*ParquetOptions, error) {
+	o := &ParquetOptions{}
+	if opts == nil {
+		return o, nil
+	}
+
+	if opts["compression"] != nil {
+		codec, ok := opts["compression"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid compression value: %v", opts["compression"])
+		}
+		switch codec {
+		case ParquetUncompressed, ParquetSnappy, ParquetGzip:
+			o.Compression = codec
+		default:
+			return nil, fmt.Errorf("unsupported parquet compression: %s", codec)
+		}
+	}
+
+	if opts["rowGroupSize"] != nil {
+		switch n := opts["rowGroupSize"].(type) {
+		case int:
+			o.RowGroupSize = n
+		case float64:
+			o.RowGroupSize = int(n)
+		default:
+			return nil, fmt.Errorf("invalid rowGroupSize value: %v", opts["rowGroupSize"])
+		}
+		if o.RowGroupSize < 0 {
+			return nil, fmt.Errorf("rowGroupSize cannot be negative")
+		}
+	}
+
+	cols, err := parseColumnCodecs(opts, func(cc ColumnCodec) error {
+		switch cc.Compression {
+		case "", ParquetUncompressed, ParquetSnappy, ParquetGzip:
+		default:
+			return fmt.Errorf("unsupported parquet compression: %s", cc.Compression)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	o.Columns = cols
+
+	return o, nil
+}
+
+// Format announces the Parquet data format for the FormatConfig interface
+func (*ParquetOptions) Format() DataFormat {
+	return ParquetDataFormat
+}
+
+// Map structures ParquetOptions as a map of string keys to values
+func (o *ParquetOptions) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	opt := map[string]interface{}{}
+	if o.Compression != "" {
+		opt["compression"] = o.Compression
+	}
+	if o.RowGroupSize != 0 {
+		opt["rowGroupSize"] = o.RowGroupSize
+	}
+	if len(o.Columns) > 0 {
+		opt["columns"] = columnCodecsMap(o.Columns)
+	}
+
+	return opt
+}
+
+// Column value encodings
+const (
+	// ColumnEncodingPlain writes each value in full
+	ColumnEncodingPlain = "plain"
+	// ColumnEncodingDictionary writes the distinct values of a column once,
+	// replacing values with indexes into them. Suited to low-cardinality
+	// strings like categories & country codes
+	ColumnEncodingDictionary = "dictionary"
+	// ColumnEncodingDelta writes differences between consecutive integers,
+	// suited to sorted & slowly changing values like timestamps & ids
+	ColumnEncodingDelta = "delta"
+)
+
+// ColumnCodec hints how a column of a columnar format is written. Unset
+// fields fall back to the format's defaults
+type ColumnCodec struct {
+	// Compression is the codec the column is compressed with, valid codecs
+	// depend on the format
+	Compression string `json:"compression,omitempty"`
+	// Encoding is the column's value encoding, one of "plain", "dictionary"
+	// or "delta"
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// parseColumnCodecs reads the "columns" option, a map of column titles to
+// codec hints. check validates format-specific hint values
+func parseColumnCodecs(opts map[string]interface{}, check func(ColumnCodec) error) (map[string]ColumnCodec, error) {
+	if opts["columns"] == nil {
+		return nil, nil
+	}
+	cols, ok := opts["columns"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid columns value: %v", opts["columns"])
+	}
+
+	codecs := make(map[string]ColumnCodec, len(cols))
+	for title, v := range cols {
+		hint, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("column %s: invalid codec hint: %v", title, v)
+		}
+		cc := ColumnCodec{}
+		for key, val := range hint {
+			s, ok := val.(string)
+			if !ok {
+				return nil, fmt.Errorf("column %s: invalid %s value: %v", title, key, val)
+			}
+			switch key {
+			case "compression":
+				cc.Compression = s
+			case "encoding":
+				cc.Encoding = s
+			default:
+				return nil, fmt.Errorf("column %s: unknown codec hint '%s'", title, key)
+			}
+		}
+		switch cc.Encoding {
+		case "", ColumnEncodingPlain, ColumnEncodingDictionary, ColumnEncodingDelta:
+		default:
+			return nil, fmt.Errorf("column %s: unsupported encoding: %s", title, cc.Encoding)
+		}
+		if err := check(cc); err != nil {
+			return nil, fmt.Errorf("column %s: %w", title, err)
+		}
+		codecs[title] = cc
+	}
+	return codecs, nil
+}
+
+// columnCodecsMap structures column codec hints as a map of string keys to
+// values
+func columnCodecsMap(codecs map[string]ColumnCodec) map[string]interface{} {
+	cols := make(map[string]interface{}, len(codecs))
+	for title, cc := range codecs {
+		hint := map[string]interface{}{}
+		if cc.Compression != "" {
+			hint["compression"] = cc.Compression
+		}
+		if cc.Encoding != "" {
+			hint["encoding"] = cc.Encoding
+		}
+		cols[title] = hint
+	}
+	return cols
+}
+
+// ArrowOptions specifies configuration details for the arrow IPC format
+type ArrowOptions struct {
+	// Columns are encoding hints for columns by name. Arrow supports
+	// dictionary encoding string columns, compression & delta encoding
+	// aren't available per column
+	Columns map[string]ColumnCodec `json:"columns,omitempty"`
+}
+
+// NewArrowOptions creates an ArrowOptions pointer from a map
+func NewArrowOptions(opts map[string]interface{}) (*ArrowOptions, error) {
+	o := &ArrowOptions{}
+	if opts == nil {
+		return o, nil
+	}
+
+	cols, err := parseColumnCodecs(opts, func(cc ColumnCodec) error {
+		if cc.Compression != "" {
+			return fmt.Errorf("arrow doesn't support per-column compression")
+		}
+		if cc.Encoding == ColumnEncodingDelta {
+			return fmt.Errorf("arrow doesn't support delta encoding")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	o.Columns = cols
+
+	return o, nil
+}
+
+// Format announces the Arrow data format for the FormatConfig interface
+func (*ArrowOptions) Format() DataFormat {
+	return ArrowDataFormat
+}
+
+// Map structures ArrowOptions as a map of string keys to values
+func (o *ArrowOptions) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	opt := map[string]interface{}{}
+	if len(o.Columns) > 0 {
+		opt["columns"] = columnCodecsMap(o.Columns)
+	}
+
+	return opt
+}
+
+// Avro block codecs
+const (
+	AvroNull    = "null"
+	AvroDeflate = "deflate"
+	AvroSnappy  = "snappy"
+)
 
+// AvroOptions specifies configuration details for the avro file format
+type AvroOptions struct {
+	// Codec is the codec data blocks are written with, one of "null",
+	// "deflate" or "snappy". defaults to null
+	Codec string `json:"codec,omitempty"`
+}
+
+// NewAvroOptions creates an AvroOptions pointer from a map
+func NewAvroOptions(opts map[string]interface{}) (*AvroOptions, error) {
+	o := &AvroOptions{}
+	if opts == nil {
+		return o, nil
+	}
+
+	if opts["codec"] != nil {
+		codec, ok := opts["codec"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid codec value: %v", opts["codec"])
+		}
+		switch codec {
+		case AvroNull, AvroDeflate, AvroSnappy:
+			o.Codec = codec
+		default:
+			return nil, fmt.Errorf("unsupported avro codec: %s", codec)
+		}
+	}
+
+	return o, nil
+}
+
+// Format announces the Avro data format for the FormatConfig interface
+func (*AvroOptions) Format() DataFormat {
+	return AvroDataFormat
+}
+
+// Map structures AvroOptions as a map of string keys to values
+func (o *AvroOptions) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	opt := map[string]interface{}{}
+	if o.Codec != "" {
+		opt["codec"] = o.Codec
+	}
+
+	return opt
+}
+
+// TSV escape styles
+const (
+	TSVEscapeBackslash = "backslash"
+	TSVEscapeQuote     = "quote"
+	TSVEscapeNone      = "none"
+)
+
+// TSVOptions specifies configuration details for tsv files
+type TSVOptions struct {
+	// HeaderRow specifies weather this tsv file has a header row or not
+	HeaderRow bool `json:"headerRow"`
+	// Escape sets how tabs & line breaks in fields are represented, one of
+	// "backslash", "quote" or "none". backslash uses the \t, \n, \r & \\
+	// escape sequences, quote wraps fields in double quotes the way csv does.
+	// fields can't contain tabs or line breaks with none. defaults to
+	// backslash
+	Escape string `json:"escape,omitempty"`
+	// VariadicFields permits records to have a variable number of fields
+	VariadicFields bool `json:"variadicFields"`
+	// BooleanTokens sets the text of boolean values
+	BooleanTokens
+}
+
+// NewTSVOptions creates a TSVOptions pointer from a map
+func NewTSVOptions(opts map[string]interface{}) (*TSVOptions, error) {
+	o := &TSVOptions{}
+	if opts == nil {
+		return o, nil
+	}
+
+	if opts["headerRow"] != nil {
+		if headerRow, ok := opts["headerRow"].(bool); ok {
+			o.HeaderRow = headerRow
+		} else {
+			return nil, fmt.Errorf("invalid headerRow value: %v", opts["headerRow"])
+		}
+	}
+
+	if opts["escape"] != nil {
+		escape, ok := opts["escape"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid escape value: %v", opts["escape"])
+		}
+		switch escape {
+		case TSVEscapeBackslash, TSVEscapeQuote, TSVEscapeNone:
+			o.Escape = escape
+		default:
+			return nil, fmt.Errorf("unsupported tsv escape: %s", escape)
+		}
+	}
+
+	if opts["variadicFields"] != nil {
+		if vf, ok := opts["variadicFields"].(bool); ok {
+			o.VariadicFields = vf
+		} else {
+			return nil, fmt.Errorf("invalid variadicFields value: %v", opts["variadicFields"])
+		}
+	}
+
+	bt, err := parseBooleanTokens(opts)
+	if err != nil {
+		return nil, err
+	}
+	o.BooleanTokens = bt
+
+	return o, nil
+}
+
+// Format announces the TSV data format for the FormatConfig interface
+func (*TSVOptions) Format() DataFormat {
+	return TSVDataFormat
+}
+
+// Map structures TSVOptions as a map of string keys to values
+func (o *TSVOptions) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	opt := map[string]interface{}{}
+	if o.HeaderRow {
+		opt["headerRow"] = o.HeaderRow
+	}
+	if o.Escape != "" {
+		opt["escape"] = o.Escape
+	}
+	if o.VariadicFields {
+		opt["variadicFields"] = o.VariadicFields
+	}
+	o.BooleanTokens.addToMap(opt)
+
+	return opt
+}
+
+// XMLOptions specifies configuration details for xml files
+type XMLOptions struct {
+	// Element is the name of the repeating element read as an entry. elements
+	// with this name are read at any depth of the document
+	Element string `json:"element"`
+	// AttributePrefix is prepended to the names of attributes to give their
+	// field names, eg: "@" reads id="1" as the field "@id". defaults to no
+	// prefix, where attributes & child elements with the same name clash
+	AttributePrefix string `json:"attributePrefix,omitempty"`
+	// TextField is the field name for the text of elements that also have
+	// attributes or child elements. defaults to "#text"
+	TextField string `json:"textField,omitempty"`
+}
+
+// NewXMLOptions creates a XMLOptions pointer from a map
+func NewXMLOptions(opts map[string]interface{}) (*XMLOptions, error) {
+	o := &XMLOptions{}
+	if opts == nil {
+		return o, nil
+	}
+
+	for _, f := range []struct {
+		key string
+		dst *string
+	}{
+		{"element", &o.Element},
+		{"attributePrefix", &o.AttributePrefix},
+		{"textField", &o.TextField},
+	} {
+		if opts[f.key] == nil {
+			continue
+		}
+		str, ok := opts[f.key].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value: %v", f.key, opts[f.key])
+		}
+		*f.dst = str
+	}
+
+	return o, nil
+}
+
+// Format announces the XML data format for the FormatConfig interface
+func (*XMLOptions) Format() DataFormat {
+	return XMLDataFormat
+}
+
+// Map structures XMLOptions as a map of string keys to values
+func (o *XMLOptions) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	opt := map[string]interface{}{}
+	if o.Element != "" {
+		opt["element"] = o.Element
+	}
+	if o.AttributePrefix != "" {
+		opt["attributePrefix"] = o.AttributePrefix
+	}
+	if o.TextField != "" {
+		opt["textField"] = o.TextField
+	}
+
+	return opt
+}
+
+// HTMLOptions specifies configuration details for reading tables from html
+// documents
+type HTMLOptions struct {
+	// Selector is a CSS selector tables must match to be read, eg:
+	// "#prices", "table.data" or "div.report table". Selectors support tag
+	// names, ids, classes & descendant combinators. defaults to all tables
+	Selector string `json:"selector,omitempty"`
+	// Table is the zero-indexed position of the table to read among tables
+	// matching the selector
+	Table int `json:"table,omitempty"`
+	// HeaderRow specifies weather the table's first row is a header row
+	HeaderRow bool `json:"headerRow"`
+}
+
+// NewHTMLOptions creates a HTMLOptions pointer from a map
+func NewHTMLOptions(opts map[string]interface{}) (*HTMLOptions, error) {
+	o := &HTMLOptions{}
+	if opts == nil {
+		return o, nil
+	}
+
+	if opts["selector"] != nil {
+		sel, ok := opts["selector"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid selector value: %v", opts["selector"])
+		}
+		o.Selector = sel
+	}
+
+	table, err := intOption(opts, "table")
+	if err != nil {
+		return nil, err
+	}
+	o.Table = table
+
+	if opts["headerRow"] != nil {
+		if headerRow, ok := opts["headerRow"].(bool); ok {
+			o.HeaderRow = headerRow
+		} else {
+			return nil, fmt.Errorf("invalid headerRow value: %v", opts["headerRow"])
+		}
+	}
+
+	return o, nil
+}
+
+// Format announces the HTML data format for the FormatConfig interface
+func (*HTMLOptions) Format() DataFormat {
+	return HTMLDataFormat
+}
+
+// Map structures HTMLOptions as a map of string keys to values
+func (o *HTMLOptions) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	opt := map[string]interface{}{}
+	if o.Selector != "" {
+		opt["selector"] = o.Selector
+	}
+	if o.Table > 0 {
+		opt["table"] = o.Table
+	}
+	if o.HeaderRow {
+		opt["headerRow"] = o.HeaderRow
+	}
+
+	return opt
+}
+
+// ProtobufOptions specifies configuration details for reading & writing
+// length-prefixed protocol buffer messages
+type ProtobufOptions struct {
+	// Descriptor is a base64-encoded FileDescriptorSet, as written by
+	// protoc --descriptor_set_out --include_imports
+	Descriptor string `json:"descriptor,omitempty"`
+	// Message is the fully-qualified name of the message type entries are
+	// encoded as, eg: "weather.Reading". Optional when the descriptor set
+	// defines one top-level message
+	Message string `json:"message,omitempty"`
+}
+
+// NewProtobufOptions creates a ProtobufOptions pointer from a map
+func NewProtobufOptions(opts map[string]interface{}) (*ProtobufOptions, error) {
+	o := &ProtobufOptions{}
+	if opts == nil {
+		return o, nil
+	}
+
+	if opts["descriptor"] != nil {
+		desc, ok := opts["descriptor"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid descriptor value: %v", opts["descriptor"])
+		}
+		if _, err := base64.StdEncoding.DecodeString(desc); err != nil {
+			return nil, fmt.Errorf("descriptor must be base64-encoded: %w", err)
+		}
+		o.Descriptor = desc
+	}
+
+	if opts["message"] != nil {
+		msg, ok := opts["message"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid message value: %v", opts["message"])
+		}
+		o.Message = strings.TrimPrefix(msg, ".")
+	}
+
+	return o, nil
+}
+
+// Format announces the Protobuf data format for the FormatConfig interface
+func (*ProtobufOptions) Format() DataFormat {
+	return ProtobufDataFormat
+}
+
+// Map structures ProtobufOptions as a map of string keys to values
+func (o *ProtobufOptions) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	opt := map[string]interface{}{}
+	if o.Descriptor != "" {
+		opt["descriptor"] = o.Descriptor
+	}
+	if o.Message != "" {
+		opt["message"] = o.Message
+	}
+
+	return opt
+}
+
+// ODSOptions specifies configuration details for the OpenDocument
+// spreadsheet format
+type ODSOptions struct {
+	// SheetName is the name of the sheet to read or write. Reading defaults to
+	// the first sheet, writing to "Sheet1"
+	SheetName string `json:"sheetName,omitempty"`
+}
+
+// NewODSOptions creates a ODSOptions pointer from a map
+func NewODSOptions(opts map[string]interface{}) (*ODSOptions, error) {
+	o := &ODSOptions{}
+	if opts == nil {
+		return o, nil
+	}
+
+	if opts["sheetName"] != nil {
+		if sheetName, ok := opts["sheetName"].(string); ok {
+			o.SheetName = sheetName
+		} else {
+			return nil, fmt.Errorf("invalid sheetName value: %v", opts["sheetName"])
+		}
+	}
+
+	return o, nil
+}
+
+// Format announces the ODS data format for the FormatConfig interface
+func (*ODSOptions) Format() DataFormat {
+	return ODSDataFormat
+}
+
+// Map structures ODSOptions as a map of string keys to values
+func (o *ODSOptions) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	opt := map[string]interface{}{}
+	if o.SheetName != "" {
+		opt["sheetName"] = o.SheetName
+	}
+
+	return opt
+}
+
+// ORC compression codecs
+const (
+	ORCNone   = "none"
+	ORCZlib   = "zlib"
+	ORCSnappy = "snappy"
+)
+
+// ORCOptions specifies configuration details for the ORC file format
+type ORCOptions struct {
+	// Compression is the codec streams are compressed with, one of "none",
+	// "zlib" or "snappy". defaults to zlib
+	Compression string `json:"compression,omitempty"`
+	// StripeSize is the number of rows written to each stripe, defaults to
+	// 10000
+	StripeSize int `json:"stripeSize,omitempty"`
+	// Columns are encoding hints for columns by title. ORC compresses whole
+	// files with one codec, so per-column compression isn't available
+	Columns map[string]ColumnCodec `json:"columns,omitempty"`
+}
+
+// NewORCOptions creates an ORCOptions pointer from a map
+func NewORCOptions(opts map[string]interface{}) (*ORCOptions, error) {
+	o := &ORCOptions{}
+	if opts == nil {
+		return o, nil
+	}
+
+	if opts["compression"] != nil {
+		codec, ok := opts["compression"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid compression value: %v", opts["compression"])
+		}
+		switch codec {
+		case ORCNone, ORCZlib, ORCSnappy:
+			o.Compression = codec
+		default:
+			return nil, fmt.Errorf("unsupported orc compression: %s", codec)
+		}
+	}
+
+	stripeSize, err := intOption(opts, "stripeSize")
+	if err != nil {
+		return nil, err
+	}
+	o.StripeSize = stripeSize
+
+	cols, err := parseColumnCodecs(opts, func(cc ColumnCodec) error {
+		if cc.Compression != "" {
+			return fmt.Errorf("orc doesn't support per-column compression")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	o.Columns = cols
+
+	return o, nil
+}
+
+// Format announces the ORC data format for the FormatConfig interface
+func (*ORCOptions) Format() DataFormat {
+	return ORCDataFormat
+}
+
+// Map structures ORCOptions as a map of string keys to values
+func (o *ORCOptions) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	opt := map[string]interface{}{}
+	if o.Compression != "" {
+		opt["compression"] = o.Compression
+	}
+	if o.StripeSize != 0 {
+		opt["stripeSize"] = o.StripeSize
+	}
+	if len(o.Columns) > 0 {
+		opt["columns"] = columnCodecsMap(o.Columns)
+	}
+
+	return opt
+}
+
+// CBOROptions specifies configuration details for the CBOR file format
+type CBOROptions struct {
+	// Indefinite streams entries as they're written in an indefinite-length
+	// container, without knowing the number of entries up front. Object keys
+	// are written in the order entries are. By default entries are buffered
+	// until the writer is closed & written canonically, in a definite-length
+	// container with sorted keys. Readers accept both
+	Indefinite bool `json:"indefinite,omitempty"`
+	// KeyDictionary writes each distinct object key once, with later uses
+	// referring back to it by index. Bodies of wide, sparse entries, with
+	// thousands of possible keys & few set per entry, shrink to a fraction of
+	// their size. Keys are written with the CBOR stringref extension (tags 256
+	// & 25), which readers expand transparently
+	KeyDictionary bool `json:"keyDictionary,omitempty"`
+}
+
+// NewCBOROptions creates a CBOROptions pointer from a map
+func NewCBOROptions(opts map[string]interface{}) (*CBOROptions, error) {
+	o := &CBOROptions{}
+	if opts == nil {
+		return o, nil
+	}
+
+	if opts["indefinite"] != nil {
+		if ind, ok := opts["indefinite"].(bool); ok {
+			o.Indefinite = ind
+		} else {
+			return nil, fmt.Errorf("invalid indefinite value: %v", opts["indefinite"])
+		}
+	}
+	if opts["keyDictionary"] != nil {
+		if kd, ok := opts["keyDictionary"].(bool); ok {
+			o.KeyDictionary = kd
+		} else {
+			return nil, fmt.Errorf("invalid keyDictionary value: %v", opts["keyDictionary"])
+		}
+	}
+
+	return o, nil
+}
+
+// Format announces the CBOR data format for the FormatConfig interface
+func (*CBOROptions) Format() DataFormat {
+	return CBORDataFormat
+}
+
+// Map structures CBOROptions as a map of string keys to values
+func (o *CBOROptions) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	opt := map[string]interface{}{}
+	if o.Indefinite {
+		opt["indefinite"] = o.Indefinite
+	}
+	if o.KeyDictionary {
+		opt["keyDictionary"] = o.KeyDictionary
+	}
 	return opt
 }
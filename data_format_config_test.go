@@ -2,6 +2,7 @@ package dataset
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -31,6 +32,17 @@ func TestParseFormatConfigMap(t *testing.T) {
 		{CSVDataFormat, map[string]interface{}{}, &CSVOptions{}, ""},
 		{JSONDataFormat, map[string]interface{}{}, &JSONOptions{}, ""},
 		{XLSXDataFormat, map[string]interface{}{}, &XLSXOptions{}, ""},
+		{ParquetDataFormat, map[string]interface{}{}, &ParquetOptions{}, ""},
+		{ArrowDataFormat, map[string]interface{}{}, &ArrowOptions{}, ""},
+		{AvroDataFormat, map[string]interface{}{}, &AvroOptions{}, ""},
+		{TSVDataFormat, map[string]interface{}{}, &TSVOptions{}, ""},
+		{XMLDataFormat, map[string]interface{}{}, &XMLOptions{}, ""},
+		{HTMLDataFormat, map[string]interface{}{}, &HTMLOptions{}, ""},
+		{ProtobufDataFormat, map[string]interface{}{}, &ProtobufOptions{}, ""},
+		{ODSDataFormat, map[string]interface{}{}, &ODSOptions{}, ""},
+		{ORCDataFormat, map[string]interface{}{}, &ORCOptions{}, ""},
+		{CBORDataFormat, map[string]interface{}{}, &CBOROptions{}, ""},
+		{XLSDataFormat, map[string]interface{}{"sheetName": "Sheet2"}, &XLSXOptions{SheetName: "Sheet2"}, ""},
 	}
 
 	for i, c := range cases {
@@ -59,10 +71,45 @@ func TestNewCSVOptions(t *testing.T) {
 		{map[string]interface{}{"lazyQuotes": true}, &CSVOptions{LazyQuotes: true}, ""},
 		{map[string]interface{}{"lazyQuotes": "foo"}, nil, "invalid lazyQuotes value: foo"},
 		{map[string]interface{}{"separator": "\t"}, &CSVOptions{Separator: '\t'}, ""},
+		{map[string]interface{}{"separator": ';'}, &CSVOptions{Separator: ';'}, ""},
 		{map[string]interface{}{"separator": "\t\t"}, nil, "separator must be a single character"},
 		{map[string]interface{}{"separator": true}, nil, "invalid separator value: true"},
 		{map[string]interface{}{"variadicFields": true}, &CSVOptions{VariadicFields: true}, ""},
 		{map[string]interface{}{"variadicFields": "foo"}, nil, "invalid variadicFields value: foo"},
+		{map[string]interface{}{"dateLayouts": map[string]interface{}{"date": "2006-01-02"}}, &CSVOptions{DateLayouts: map[string]string{"date": "2006-01-02"}}, ""},
+		{map[string]interface{}{"dateLayouts": map[string]interface{}{"date": 5}}, nil, "invalid dateLayouts value for column date: 5"},
+		{map[string]interface{}{"dateLayouts": "foo"}, nil, "invalid dateLayouts value: foo"},
+		{map[string]interface{}{"skipFooterRows": float64(2)}, &CSVOptions{SkipFooterRows: 2}, ""},
+		{map[string]interface{}{"skipFooterRows": -1}, nil, "skipFooterRows cannot be negative"},
+		{map[string]interface{}{"skipFooterRows": "2"}, nil, "invalid skipFooterRows value: 2"},
+		{map[string]interface{}{"encoding": "CP1252", "encodingFallback": "transliterate"}, &CSVOptions{Encoding: "windows-1252", EncodingFallback: "transliterate"}, ""},
+		{map[string]interface{}{"encoding": "ebcdic"}, nil, "unsupported charset: ebcdic"},
+		{map[string]interface{}{"stripBOM": true, "emitBOM": true}, &CSVOptions{StripBOM: true, EmitBOM: true}, ""},
+		{map[string]interface{}{"stripBOM": 1}, nil, "invalid stripBOM value: 1"},
+		{map[string]interface{}{"encoding": 5}, nil, "invalid encoding value: 5"},
+		{map[string]interface{}{"encodingFallback": "ignore"}, nil, "invalid charset fallback: ignore"},
+		{map[string]interface{}{"decimalPlaces": map[string]interface{}{"price": float64(2)}}, &CSVOptions{FloatFormat: FloatFormat{DecimalPlaces: map[string]int{"price": 2}}}, ""},
+		{map[string]interface{}{"significantDigits": "4"}, nil, "invalid significantDigits value: 4"},
+		{map[string]interface{}{"trueTokens": []interface{}{"yes", "y"}, "falseTokens": []string{"no", "n"}}, &CSVOptions{BooleanTokens: BooleanTokens{TrueTokens: []string{"yes", "y"}, FalseTokens: []string{"no", "n"}}}, ""},
+		{map[string]interface{}{"trueTokens": []interface{}{"1"}}, nil, "trueTokens and falseTokens must be set together"},
+		{map[string]interface{}{"columns": []interface{}{"id", map[string]interface{}{"index": float64(2), "title": "Amount"}}}, &CSVOptions{Columns: []CSVColumn{{Key: "id"}, {Index: 2, Title: "Amount"}}}, ""},
+		{map[string]interface{}{"columns": "id"}, nil, "invalid columns value: id"},
+		{map[string]interface{}{"columns": []interface{}{5}}, nil, "column 0: invalid column value: 5"},
+		{map[string]interface{}{"columns": []interface{}{""}}, nil, "column 0: key can't be empty"},
+		{map[string]interface{}{"columns": []interface{}{map[string]interface{}{"key": "id", "index": 1}}}, nil, "column 0: exactly one of key or index is required"},
+		{map[string]interface{}{"columns": []interface{}{map[string]interface{}{"title": "ID"}}}, nil, "column 0: exactly one of key or index is required"},
+		{map[string]interface{}{"columns": []interface{}{"id", map[string]interface{}{"index": -1}}}, nil, "column 1: invalid index value: -1"},
+		{map[string]interface{}{"columns": []interface{}{map[string]interface{}{"key": 5}}}, nil, "column 0: invalid key value: 5"},
+		{map[string]interface{}{"columns": []interface{}{map[string]interface{}{"name": "id"}}}, nil, "column 0: unknown property 'name'"},
+		{map[string]interface{}{"parseUnits": true, "numberLocale": "de"}, &CSVOptions{ParseUnits: true, NumberLocale: "de"}, ""},
+		{map[string]interface{}{"parseUnits": "yes"}, nil, "invalid parseUnits value: yes"},
+		{map[string]interface{}{"numberLocale": "de_DE"}, nil, "invalid numberLocale value: de_DE"},
+		{map[string]interface{}{"numberLocale": 1}, nil, "invalid numberLocale value: 1"},
+		{map[string]interface{}{"quote": "nonNumeric", "lineTerminator": "\r\n"}, &CSVOptions{Quote: CSVQuoteNonNumeric, LineTerminator: "\r\n"}, ""},
+		{map[string]interface{}{"quote": "always"}, nil, "invalid quote value: always"},
+		{map[string]interface{}{"lineTerminator": "\r"}, nil, `invalid lineTerminator value: "\r"`},
+		{map[string]interface{}{"requiredColumns": true}, &CSVOptions{RequiredColumns: true}, ""},
+		{map[string]interface{}{"requiredColumns": "yes"}, nil, "invalid requiredColumns value: yes"},
 	}
 
 	for i, c := range cases {
@@ -76,10 +123,82 @@ func TestNewCSVOptions(t *testing.T) {
 				t.Errorf("case %d HeaderRow expected: %t, got: %t", i, got.HeaderRow, c.res.HeaderRow)
 				continue
 			}
+			if got.SkipFooterRows != c.res.SkipFooterRows {
+				t.Errorf("case %d SkipFooterRows expected: %d, got: %d", i, c.res.SkipFooterRows, got.SkipFooterRows)
+				continue
+			}
+			if len(got.DateLayouts) != len(c.res.DateLayouts) {
+				t.Errorf("case %d DateLayouts expected: %v, got: %v", i, c.res.DateLayouts, got.DateLayouts)
+				continue
+			}
+			if !reflect.DeepEqual(got.FloatFormat, c.res.FloatFormat) {
+				t.Errorf("case %d FloatFormat expected: %v, got: %v", i, c.res.FloatFormat, got.FloatFormat)
+				continue
+			}
+			if got.Encoding != c.res.Encoding || got.EncodingFallback != c.res.EncodingFallback {
+				t.Errorf("case %d encoding expected: %s/%s, got: %s/%s", i, c.res.Encoding, c.res.EncodingFallback, got.Encoding, got.EncodingFallback)
+				continue
+			}
+			if got.StripBOM != c.res.StripBOM || got.EmitBOM != c.res.EmitBOM {
+				t.Errorf("case %d BOM options expected: %t/%t, got: %t/%t", i, c.res.StripBOM, c.res.EmitBOM, got.StripBOM, got.EmitBOM)
+				continue
+			}
+			if !reflect.DeepEqual(got.Columns, c.res.Columns) {
+				t.Errorf("case %d Columns expected: %v, got: %v", i, c.res.Columns, got.Columns)
+				continue
+			}
+			if !reflect.DeepEqual(got.BooleanTokens, c.res.BooleanTokens) {
+				t.Errorf("case %d BooleanTokens expected: %v, got: %v", i, c.res.BooleanTokens, got.BooleanTokens)
+				continue
+			}
+			if got.ParseUnits != c.res.ParseUnits || got.NumberLocale != c.res.NumberLocale {
+				t.Errorf("case %d units expected: %t/%s, got: %t/%s", i, c.res.ParseUnits, c.res.NumberLocale, got.ParseUnits, got.NumberLocale)
+				continue
+			}
+			if got.Quote != c.res.Quote || got.LineTerminator != c.res.LineTerminator {
+				t.Errorf("case %d quoting expected: %s/%q, got: %s/%q", i, c.res.Quote, c.res.LineTerminator, got.Quote, got.LineTerminator)
+				continue
+			}
 		}
 	}
 }
 
+func TestParseBooleanTokens(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  BooleanTokens
+		err  string
+	}{
+		{map[string]interface{}{}, BooleanTokens{}, ""},
+		{map[string]interface{}{"trueTokens": []interface{}{"Y", "yes"}, "falseTokens": []interface{}{"N", "no"}}, BooleanTokens{TrueTokens: []string{"Y", "yes"}, FalseTokens: []string{"N", "no"}}, ""},
+		{map[string]interface{}{"falseTokens": []interface{}{"0"}}, BooleanTokens{}, "trueTokens and falseTokens must be set together"},
+		{map[string]interface{}{"trueTokens": "1", "falseTokens": "0"}, BooleanTokens{}, "invalid trueTokens value: 1"},
+		{map[string]interface{}{"trueTokens": []interface{}{"1"}, "falseTokens": []interface{}{0}}, BooleanTokens{}, "invalid falseTokens value: 0"},
+		{map[string]interface{}{"trueTokens": []interface{}{""}, "falseTokens": []interface{}{"0"}}, BooleanTokens{}, "invalid trueTokens value: "},
+		{map[string]interface{}{"trueTokens": []string{"1", ""}, "falseTokens": []string{"0"}}, BooleanTokens{}, "invalid trueTokens value: tokens can't be empty"},
+		{map[string]interface{}{"trueTokens": []interface{}{"Yes"}, "falseTokens": []interface{}{"YES"}}, BooleanTokens{}, `boolean token "YES" can't be both true and false`},
+	}
+
+	for i, c := range cases {
+		got, err := parseBooleanTokens(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.err == "" && !reflect.DeepEqual(got, c.res) {
+			t.Errorf("case %d expected: %v, got: %v", i, c.res, got)
+		}
+	}
+
+	bt := BooleanTokens{TrueTokens: []string{"Y"}, FalseTokens: []string{"N"}}
+	opt := map[string]interface{}{}
+	bt.addToMap(opt)
+	expect := map[string]interface{}{"trueTokens": []interface{}{"Y"}, "falseTokens": []interface{}{"N"}}
+	if !reflect.DeepEqual(opt, expect) {
+		t.Errorf("map mismatch. expected: %v, got: %v", expect, opt)
+	}
+}
+
 func TestCSVOptionsMap(t *testing.T) {
 	cases := []struct {
 		opt *CSVOptions
@@ -87,6 +206,11 @@ func TestCSVOptionsMap(t *testing.T) {
 	}{
 		{nil, nil},
 		{&CSVOptions{HeaderRow: true}, map[string]interface{}{"headerRow": true}},
+		{&CSVOptions{Encoding: "shift_jis", EncodingFallback: "replace"}, map[string]interface{}{"encoding": "shift_jis", "encodingFallback": "replace"}},
+		{&CSVOptions{FloatFormat: FloatFormat{SignificantDigits: 4}}, map[string]interface{}{"significantDigits": 4}},
+		{&CSVOptions{ParseUnits: true, NumberLocale: "fr"}, map[string]interface{}{"parseUnits": true, "numberLocale": "fr"}},
+		{&CSVOptions{Quote: CSVQuoteAll, LineTerminator: "\r\n"}, map[string]interface{}{"quote": "all", "lineTerminator": "\r\n"}},
+		{&CSVOptions{RequiredColumns: true}, map[string]interface{}{"requiredColumns": true}},
 	}
 
 	for i, c := range cases {
@@ -97,9 +221,26 @@ func TestCSVOptionsMap(t *testing.T) {
 			}
 		}
 	}
+
+	opt := &CSVOptions{Columns: []CSVColumn{{Key: "amount", Title: "Amount"}, {Index: 0}}}
+	expect := []interface{}{
+		map[string]interface{}{"key": "amount", "title": "Amount"},
+		map[string]interface{}{"index": 0},
+	}
+	if got := opt.Map()["columns"]; !reflect.DeepEqual(got, expect) {
+		t.Errorf("columns mismatch. expected: %v, got: %v", expect, got)
+	}
+	parsed, err := NewCSVOptions(opt.Map())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.Columns, opt.Columns) {
+		t.Errorf("columns round trip mismatch. expected: %v, got: %v", opt.Columns, parsed.Columns)
+	}
 }
 
 func TestNewJSONOptions(t *testing.T) {
+	escape, noEscape := true, false
 	cases := []struct {
 		opts map[string]interface{}
 		res  *JSONOptions
@@ -107,24 +248,49 @@ func TestNewJSONOptions(t *testing.T) {
 	}{
 		{nil, &JSONOptions{}, ""},
 		{map[string]interface{}{}, &JSONOptions{}, ""},
+		{map[string]interface{}{"escapeHTML": true, "asciiOnly": true}, &JSONOptions{EscapeHTML: &escape, ASCIIOnly: true}, ""},
+		{map[string]interface{}{"escapeHTML": false}, &JSONOptions{EscapeHTML: &noEscape}, ""},
+		{map[string]interface{}{"escapeHTML": "foo"}, nil, "invalid escapeHTML value: foo"},
+		{map[string]interface{}{"asciiOnly": 1}, nil, "invalid asciiOnly value: 1"},
+		{map[string]interface{}{"significantDigits": float64(6), "scientificThreshold": 9, "decimalPlaces": map[string]interface{}{"price": float64(2)}}, &JSONOptions{FloatFormat: FloatFormat{SignificantDigits: 6, ScientificThreshold: 9, DecimalPlaces: map[string]int{"price": 2}}}, ""},
+		{map[string]interface{}{"significantDigits": 1.5}, nil, "invalid significantDigits value: 1.5"},
+		{map[string]interface{}{"scientificThreshold": -1}, nil, "invalid scientificThreshold value: -1"},
+		{map[string]interface{}{"decimalPlaces": map[string]interface{}{"price": "2"}}, nil, "invalid decimalPlaces value for column price: 2"},
+		{map[string]interface{}{"decimalPlaces": 2}, nil, "invalid decimalPlaces value: 2"},
+		{map[string]interface{}{"encoding": "UTF16LE", "encodingFallback": "replace"}, &JSONOptions{Encoding: "utf-16le", EncodingFallback: "replace"}, ""},
+		{map[string]interface{}{"encoding": 8}, nil, "invalid encoding value: 8"},
+		{map[string]interface{}{"stripBOM": true, "emitBOM": true}, &JSONOptions{StripBOM: true, EmitBOM: true}, ""},
+		{map[string]interface{}{"indent": "\t"}, &JSONOptions{Indent: "\t"}, ""},
+		{map[string]interface{}{"indent": "--"}, nil, "invalid indent value: --"},
+		{map[string]interface{}{"indent": 2}, nil, "invalid indent value: 2"},
+		{map[string]interface{}{"emitBOM": "yes"}, nil, "invalid emitBOM value: yes"},
 	}
 
 	for i, c := range cases {
-		_, err := NewJSONOptions(c.opts)
+		got, err := NewJSONOptions(c.opts)
 		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
-			t.Errorf("case %d error expected: '%s', got: '%s'", i, c.err, err.Error())
+			t.Errorf("case %d error expected: '%s', got: '%v'", i, c.err, err)
 			continue
 		}
+		if c.err == "" && !reflect.DeepEqual(got, c.res) {
+			t.Errorf("case %d result expected: %v, got: %v", i, c.res, got)
+		}
 	}
 }
 
 func TestJSONOptionsMap(t *testing.T) {
+	escape, noEscape := true, false
 	cases := []struct {
 		opt *JSONOptions
 		res map[string]interface{}
 	}{
 		{nil, nil},
 		{&JSONOptions{}, map[string]interface{}{}},
+		{&JSONOptions{EscapeHTML: &escape, ASCIIOnly: true}, map[string]interface{}{"escapeHTML": true, "asciiOnly": true}},
+		{&JSONOptions{EscapeHTML: &noEscape}, map[string]interface{}{"escapeHTML": false}},
+		{&JSONOptions{Indent: "  "}, map[string]interface{}{"indent": "  "}},
+		{&JSONOptions{FloatFormat: FloatFormat{SignificantDigits: 6, ScientificThreshold: 9}}, map[string]interface{}{"significantDigits": 6, "scientificThreshold": 9}},
+		{&JSONOptions{Encoding: "iso-8859-1", EncodingFallback: "transliterate"}, map[string]interface{}{"encoding": "iso-8859-1", "encodingFallback": "transliterate"}},
 	}
 
 	for i, c := range cases {
@@ -147,6 +313,11 @@ func TestNewXLSXOptions(t *testing.T) {
 		{map[string]interface{}{}, &XLSXOptions{}, ""},
 		{map[string]interface{}{"sheetName": "foo"}, &XLSXOptions{SheetName: "foo"}, ""},
 		{map[string]interface{}{"sheetName": true}, nil, "invalid sheetName value: true"},
+		{map[string]interface{}{"sheetIndex": float64(2)}, &XLSXOptions{SheetIndex: intPtr(2)}, ""},
+		{map[string]interface{}{"sheetIndex": 0}, &XLSXOptions{SheetIndex: intPtr(0)}, ""},
+		{map[string]interface{}{"sheetIndex": -1}, nil, "invalid sheetIndex value: -1"},
+		{map[string]interface{}{"sheetIndex": 1, "sheetName": "foo"}, nil, "sheetName and sheetIndex can't both be set"},
+		{map[string]interface{}{"trueTokens": []interface{}{"Y"}, "falseTokens": []interface{}{"y"}}, nil, `boolean token "y" can't be both true and false`},
 	}
 
 	for i, c := range cases {
@@ -166,10 +337,18 @@ func TestNewXLSXOptions(t *testing.T) {
 				t.Errorf("case %d SheetName expected: %s, got: %s", i, xlsxo.SheetName, c.res.SheetName)
 				continue
 			}
+			if !reflect.DeepEqual(xlsxo.SheetIndex, c.res.SheetIndex) {
+				t.Errorf("case %d SheetIndex expected: %v, got: %v", i, c.res.SheetIndex, xlsxo.SheetIndex)
+				continue
+			}
 		}
 	}
 }
 
+func intPtr(i int) *int {
+	return &i
+}
+
 func TestXLSXOptionsMap(t *testing.T) {
 	cases := []struct {
 		opt *XLSXOptions
@@ -178,6 +357,7 @@ func TestXLSXOptionsMap(t *testing.T) {
 		{nil, nil},
 		{&XLSXOptions{}, map[string]interface{}{}},
 		{&XLSXOptions{SheetName: "foo"}, map[string]interface{}{"sheetName": "foo"}},
+		{&XLSXOptions{SheetIndex: intPtr(0)}, map[string]interface{}{"sheetIndex": 0}},
 	}
 
 	for i, c := range cases {
@@ -189,3 +369,543 @@ func TestXLSXOptionsMap(t *testing.T) {
 		}
 	}
 }
+
+func TestNewParquetOptions(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  *ParquetOptions
+		err  string
+	}{
+		{nil, &ParquetOptions{}, ""},
+		{map[string]interface{}{}, &ParquetOptions{}, ""},
+		{map[string]interface{}{"compression": "gzip", "rowGroupSize": float64(500)}, &ParquetOptions{Compression: "gzip", RowGroupSize: 500}, ""},
+		{map[string]interface{}{"compression": "lzo"}, nil, "unsupported parquet compression: lzo"},
+		{map[string]interface{}{"compression": 1}, nil, "invalid compression value: 1"},
+		{map[string]interface{}{"rowGroupSize": "big"}, nil, "invalid rowGroupSize value: big"},
+		{map[string]interface{}{"rowGroupSize": -1}, nil, "rowGroupSize cannot be negative"},
+		{map[string]interface{}{"columns": map[string]interface{}{
+			"country": map[string]interface{}{"encoding": "dictionary"},
+			"ts":      map[string]interface{}{"encoding": "delta", "compression": "gzip"},
+		}}, &ParquetOptions{Columns: map[string]ColumnCodec{
+			"country": {Encoding: ColumnEncodingDictionary},
+			"ts":      {Encoding: ColumnEncodingDelta, Compression: ParquetGzip},
+		}}, ""},
+		{map[string]interface{}{"columns": []interface{}{"country"}}, nil, "invalid columns value: [country]"},
+		{map[string]interface{}{"columns": map[string]interface{}{"a": "dictionary"}}, nil, "column a: invalid codec hint: dictionary"},
+		{map[string]interface{}{"columns": map[string]interface{}{"a": map[string]interface{}{"encoding": 1}}}, nil, "column a: invalid encoding value: 1"},
+		{map[string]interface{}{"columns": map[string]interface{}{"a": map[string]interface{}{"level": "9"}}}, nil, "column a: unknown codec hint 'level'"},
+		{map[string]interface{}{"columns": map[string]interface{}{"a": map[string]interface{}{"encoding": "rle"}}}, nil, "column a: unsupported encoding: rle"},
+		{map[string]interface{}{"columns": map[string]interface{}{"a": map[string]interface{}{"compression": "lzo"}}}, nil, "column a: unsupported parquet compression: lzo"},
+	}
+
+	for i, c := range cases {
+		got, err := NewParquetOptions(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.res == nil {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.res) {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestParquetOptionsMap(t *testing.T) {
+	cases := []struct {
+		opt *ParquetOptions
+		res map[string]interface{}
+	}{
+		{nil, nil},
+		{&ParquetOptions{}, map[string]interface{}{}},
+		{&ParquetOptions{Compression: "snappy", RowGroupSize: 10}, map[string]interface{}{"compression": "snappy", "rowGroupSize": 10}},
+		{&ParquetOptions{Columns: map[string]ColumnCodec{"ts": {Encoding: "delta", Compression: "gzip"}}}, map[string]interface{}{
+			"columns": map[string]interface{}{"ts": map[string]interface{}{"encoding": "delta", "compression": "gzip"}},
+		}},
+	}
+
+	for i, c := range cases {
+		got := c.opt.Map()
+		if len(got) != len(c.res) {
+			t.Errorf("case %d length mismatch. expected: %d, got: %d", i, len(c.res), len(got))
+		}
+		for key, val := range c.res {
+			if !reflect.DeepEqual(got[key], val) {
+				t.Errorf("case %d, key '%s' expected: '%v' got:'%v'", i, key, val, got[key])
+			}
+		}
+	}
+}
+
+func TestNewArrowOptions(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  *ArrowOptions
+		err  string
+	}{
+		{nil, &ArrowOptions{}, ""},
+		{map[string]interface{}{}, &ArrowOptions{}, ""},
+		{map[string]interface{}{"columns": map[string]interface{}{"country": map[string]interface{}{"encoding": "dictionary"}}}, &ArrowOptions{Columns: map[string]ColumnCodec{"country": {Encoding: "dictionary"}}}, ""},
+		{map[string]interface{}{"columns": map[string]interface{}{"ts": map[string]interface{}{"encoding": "delta"}}}, nil, "column ts: arrow doesn't support delta encoding"},
+		{map[string]interface{}{"columns": map[string]interface{}{"ts": map[string]interface{}{"compression": "gzip"}}}, nil, "column ts: arrow doesn't support per-column compression"},
+	}
+
+	for i, c := range cases {
+		got, err := NewArrowOptions(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.res == nil {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.res) {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestArrowOptionsMap(t *testing.T) {
+	cases := []struct {
+		opt *ArrowOptions
+		res map[string]interface{}
+	}{
+		{nil, nil},
+		{&ArrowOptions{}, map[string]interface{}{}},
+		{&ArrowOptions{Columns: map[string]ColumnCodec{"country": {Encoding: "dictionary"}}}, map[string]interface{}{
+			"columns": map[string]interface{}{"country": map[string]interface{}{"encoding": "dictionary"}},
+		}},
+	}
+
+	for i, c := range cases {
+		got := c.opt.Map()
+		if !reflect.DeepEqual(got, c.res) && len(got)+len(c.res) > 0 {
+			t.Errorf("case %d mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestNewAvroOptions(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  *AvroOptions
+		err  string
+	}{
+		{nil, &AvroOptions{}, ""},
+		{map[string]interface{}{}, &AvroOptions{}, ""},
+		{map[string]interface{}{"codec": "deflate"}, &AvroOptions{Codec: "deflate"}, ""},
+		{map[string]interface{}{"codec": "lz4"}, nil, "unsupported avro codec: lz4"},
+		{map[string]interface{}{"codec": 1}, nil, "invalid codec value: 1"},
+	}
+
+	for i, c := range cases {
+		got, err := NewAvroOptions(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.res == nil {
+			continue
+		}
+		if *got != *c.res {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestAvroOptionsMap(t *testing.T) {
+	cases := []struct {
+		opt *AvroOptions
+		res map[string]interface{}
+	}{
+		{nil, nil},
+		{&AvroOptions{}, map[string]interface{}{}},
+		{&AvroOptions{Codec: "snappy"}, map[string]interface{}{"codec": "snappy"}},
+	}
+
+	for i, c := range cases {
+		got := c.opt.Map()
+		if len(got) != len(c.res) {
+			t.Errorf("case %d length mismatch. expected: %d, got: %d", i, len(c.res), len(got))
+		}
+		for key, val := range c.res {
+			if got[key] != val {
+				t.Errorf("case %d, key '%s' expected: '%v' got:'%v'", i, key, val, got[key])
+			}
+		}
+	}
+}
+
+func TestNewTSVOptions(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  *TSVOptions
+		err  string
+	}{
+		{nil, &TSVOptions{}, ""},
+		{map[string]interface{}{}, &TSVOptions{}, ""},
+		{map[string]interface{}{"headerRow": true, "escape": "quote", "variadicFields": true}, &TSVOptions{HeaderRow: true, Escape: "quote", VariadicFields: true}, ""},
+		{map[string]interface{}{"escape": "html"}, nil, "unsupported tsv escape: html"},
+		{map[string]interface{}{"escape": 1}, nil, "invalid escape value: 1"},
+		{map[string]interface{}{"headerRow": "yes"}, nil, "invalid headerRow value: yes"},
+		{map[string]interface{}{"variadicFields": 1}, nil, "invalid variadicFields value: 1"},
+		{map[string]interface{}{"trueTokens": []interface{}{"Y"}, "falseTokens": []interface{}{"N"}}, &TSVOptions{BooleanTokens: BooleanTokens{TrueTokens: []string{"Y"}, FalseTokens: []string{"N"}}}, ""},
+	}
+
+	for i, c := range cases {
+		got, err := NewTSVOptions(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.res == nil {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.res) {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestTSVOptionsMap(t *testing.T) {
+	cases := []struct {
+		opt *TSVOptions
+		res map[string]interface{}
+	}{
+		{nil, nil},
+		{&TSVOptions{}, map[string]interface{}{}},
+		{&TSVOptions{HeaderRow: true, Escape: "none", VariadicFields: true}, map[string]interface{}{"headerRow": true, "escape": "none", "variadicFields": true}},
+	}
+
+	for i, c := range cases {
+		got := c.opt.Map()
+		if len(got) != len(c.res) {
+			t.Errorf("case %d length mismatch. expected: %d, got: %d", i, len(c.res), len(got))
+		}
+		for key, val := range c.res {
+			if got[key] != val {
+				t.Errorf("case %d, key '%s' expected: '%v' got:'%v'", i, key, val, got[key])
+			}
+		}
+	}
+}
+
+func TestNewXMLOptions(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  *XMLOptions
+		err  string
+	}{
+		{nil, &XMLOptions{}, ""},
+		{map[string]interface{}{}, &XMLOptions{}, ""},
+		{map[string]interface{}{"element": "item", "attributePrefix": "@", "textField": "value"}, &XMLOptions{Element: "item", AttributePrefix: "@", TextField: "value"}, ""},
+		{map[string]interface{}{"element": 1}, nil, "invalid element value: 1"},
+		{map[string]interface{}{"textField": true}, nil, "invalid textField value: true"},
+	}
+
+	for i, c := range cases {
+		got, err := NewXMLOptions(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.res == nil {
+			continue
+		}
+		if *got != *c.res {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestXMLOptionsMap(t *testing.T) {
+	cases := []struct {
+		opt *XMLOptions
+		res map[string]interface{}
+	}{
+		{nil, nil},
+		{&XMLOptions{}, map[string]interface{}{}},
+		{&XMLOptions{Element: "item", AttributePrefix: "@", TextField: "value"}, map[string]interface{}{"element": "item", "attributePrefix": "@", "textField": "value"}},
+	}
+
+	for i, c := range cases {
+		got := c.opt.Map()
+		if len(got) != len(c.res) {
+			t.Errorf("case %d length mismatch. expected: %d, got: %d", i, len(c.res), len(got))
+		}
+		for key, val := range c.res {
+			if got[key] != val {
+				t.Errorf("case %d, key '%s' expected: '%v' got:'%v'", i, key, val, got[key])
+			}
+		}
+	}
+}
+
+func TestNewHTMLOptions(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  *HTMLOptions
+		err  string
+	}{
+		{nil, &HTMLOptions{}, ""},
+		{map[string]interface{}{}, &HTMLOptions{}, ""},
+		{map[string]interface{}{"selector": "#prices", "table": 1, "headerRow": true}, &HTMLOptions{Selector: "#prices", Table: 1, HeaderRow: true}, ""},
+		{map[string]interface{}{"table": float64(2)}, &HTMLOptions{Table: 2}, ""},
+		{map[string]interface{}{"selector": 1}, nil, "invalid selector value: 1"},
+		{map[string]interface{}{"table": "first"}, nil, "invalid table value: first"},
+		{map[string]interface{}{"headerRow": "yes"}, nil, "invalid headerRow value: yes"},
+	}
+
+	for i, c := range cases {
+		got, err := NewHTMLOptions(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.res == nil {
+			continue
+		}
+		if *got != *c.res {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestHTMLOptionsMap(t *testing.T) {
+	cases := []struct {
+		opt *HTMLOptions
+		res map[string]interface{}
+	}{
+		{nil, nil},
+		{&HTMLOptions{}, map[string]interface{}{}},
+		{&HTMLOptions{Selector: "table.data", Table: 2, HeaderRow: true}, map[string]interface{}{"selector": "table.data", "table": 2, "headerRow": true}},
+	}
+
+	for i, c := range cases {
+		got := c.opt.Map()
+		if len(got) != len(c.res) {
+			t.Errorf("case %d length mismatch. expected: %d, got: %d", i, len(c.res), len(got))
+		}
+		for key, val := range c.res {
+			if got[key] != val {
+				t.Errorf("case %d, key '%s' expected: '%v' got:'%v'", i, key, val, got[key])
+			}
+		}
+	}
+}
+
+func TestNewProtobufOptions(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  *ProtobufOptions
+		err  string
+	}{
+		{nil, &ProtobufOptions{}, ""},
+		{map[string]interface{}{}, &ProtobufOptions{}, ""},
+		{map[string]interface{}{"descriptor": "CgE=", "message": "weather.Reading"}, &ProtobufOptions{Descriptor: "CgE=", Message: "weather.Reading"}, ""},
+		{map[string]interface{}{"message": ".weather.Reading"}, &ProtobufOptions{Message: "weather.Reading"}, ""},
+		{map[string]interface{}{"descriptor": 1}, nil, "invalid descriptor value: 1"},
+		{map[string]interface{}{"descriptor": "not base64!"}, nil, "descriptor must be base64-encoded: illegal base64 data at input byte 3"},
+		{map[string]interface{}{"message": true}, nil, "invalid message value: true"},
+	}
+
+	for i, c := range cases {
+		got, err := NewProtobufOptions(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.res == nil {
+			continue
+		}
+		if *got != *c.res {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestProtobufOptionsMap(t *testing.T) {
+	cases := []struct {
+		opt *ProtobufOptions
+		res map[string]interface{}
+	}{
+		{nil, nil},
+		{&ProtobufOptions{}, map[string]interface{}{}},
+		{&ProtobufOptions{Descriptor: "CgE=", Message: "weather.Reading"}, map[string]interface{}{"descriptor": "CgE=", "message": "weather.Reading"}},
+	}
+
+	for i, c := range cases {
+		got := c.opt.Map()
+		if len(got) != len(c.res) {
+			t.Errorf("case %d length mismatch. expected: %d, got: %d", i, len(c.res), len(got))
+		}
+		for key, val := range c.res {
+			if got[key] != val {
+				t.Errorf("case %d, key '%s' expected: '%v' got:'%v'", i, key, val, got[key])
+			}
+		}
+	}
+}
+
+func TestNewODSOptions(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  *ODSOptions
+		err  string
+	}{
+		{nil, &ODSOptions{}, ""},
+		{map[string]interface{}{}, &ODSOptions{}, ""},
+		{map[string]interface{}{"sheetName": "Tabelle1"}, &ODSOptions{SheetName: "Tabelle1"}, ""},
+		{map[string]interface{}{"sheetName": 1}, nil, "invalid sheetName value: 1"},
+	}
+
+	for i, c := range cases {
+		got, err := NewODSOptions(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.res == nil {
+			continue
+		}
+		if *got != *c.res {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestODSOptionsMap(t *testing.T) {
+	cases := []struct {
+		opt *ODSOptions
+		res map[string]interface{}
+	}{
+		{nil, nil},
+		{&ODSOptions{}, map[string]interface{}{}},
+		{&ODSOptions{SheetName: "Tabelle1"}, map[string]interface{}{"sheetName": "Tabelle1"}},
+	}
+
+	for i, c := range cases {
+		got := c.opt.Map()
+		if len(got) != len(c.res) {
+			t.Errorf("case %d length mismatch. expected: %d, got: %d", i, len(c.res), len(got))
+		}
+		for key, val := range c.res {
+			if got[key] != val {
+				t.Errorf("case %d, key '%s' expected: '%v' got:'%v'", i, key, val, got[key])
+			}
+		}
+	}
+}
+
+func TestNewORCOptions(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  *ORCOptions
+		err  string
+	}{
+		{nil, &ORCOptions{}, ""},
+		{map[string]interface{}{}, &ORCOptions{}, ""},
+		{map[string]interface{}{"compression": "snappy", "stripeSize": float64(500)}, &ORCOptions{Compression: ORCSnappy, StripeSize: 500}, ""},
+		{map[string]interface{}{"compression": "lzo"}, nil, "unsupported orc compression: lzo"},
+		{map[string]interface{}{"compression": 1}, nil, "invalid compression value: 1"},
+		{map[string]interface{}{"stripeSize": "big"}, nil, "invalid stripeSize value: big"},
+		{map[string]interface{}{"stripeSize": -1}, nil, "invalid stripeSize value: -1"},
+		{map[string]interface{}{"columns": map[string]interface{}{
+			"country": map[string]interface{}{"encoding": "dictionary"},
+			"ts":      map[string]interface{}{"encoding": "delta"},
+		}}, &ORCOptions{Columns: map[string]ColumnCodec{
+			"country": {Encoding: ColumnEncodingDictionary},
+			"ts":      {Encoding: ColumnEncodingDelta},
+		}}, ""},
+		{map[string]interface{}{"columns": map[string]interface{}{"a": map[string]interface{}{"compression": "zlib"}}}, nil, "column a: orc doesn't support per-column compression"},
+	}
+
+	for i, c := range cases {
+		got, err := NewORCOptions(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.res == nil {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.res) {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestORCOptionsMap(t *testing.T) {
+	cases := []struct {
+		opt *ORCOptions
+		res map[string]interface{}
+	}{
+		{nil, nil},
+		{&ORCOptions{}, map[string]interface{}{}},
+		{&ORCOptions{Compression: "none", StripeSize: 10}, map[string]interface{}{"compression": "none", "stripeSize": 10}},
+		{&ORCOptions{Columns: map[string]ColumnCodec{"ts": {Encoding: "delta"}}}, map[string]interface{}{
+			"columns": map[string]interface{}{"ts": map[string]interface{}{"encoding": "delta"}},
+		}},
+	}
+
+	for i, c := range cases {
+		got := c.opt.Map()
+		if len(got) != len(c.res) {
+			t.Errorf("case %d length mismatch. expected: %d, got: %d", i, len(c.res), len(got))
+		}
+		for key, val := range c.res {
+			if !reflect.DeepEqual(got[key], val) {
+				t.Errorf("case %d, key '%s' expected: '%v' got:'%v'", i, key, val, got[key])
+			}
+		}
+	}
+}
+
+func TestNewCBOROptions(t *testing.T) {
+	cases := []struct {
+		opts map[string]interface{}
+		res  *CBOROptions
+		err  string
+	}{
+		{nil, &CBOROptions{}, ""},
+		{map[string]interface{}{}, &CBOROptions{}, ""},
+		{map[string]interface{}{"indefinite": true}, &CBOROptions{Indefinite: true}, ""},
+		{map[string]interface{}{"indefinite": "yes"}, nil, "invalid indefinite value: yes"},
+		{map[string]interface{}{"keyDictionary": true}, &CBOROptions{KeyDictionary: true}, ""},
+		{map[string]interface{}{"keyDictionary": 1}, nil, "invalid keyDictionary value: 1"},
+	}
+
+	for i, c := range cases {
+		got, err := NewCBOROptions(c.opts)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if c.res == nil {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.res) {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
+
+func TestCBOROptionsMap(t *testing.T) {
+	cases := []struct {
+		opt *CBOROptions
+		res map[string]interface{}
+	}{
+		{nil, nil},
+		{&CBOROptions{}, map[string]interface{}{}},
+		{&CBOROptions{Indefinite: true}, map[string]interface{}{"indefinite": true}},
+		{&CBOROptions{KeyDictionary: true}, map[string]interface{}{"keyDictionary": true}},
+	}
+
+	for i, c := range cases {
+		if got := c.opt.Map(); !reflect.DeepEqual(got, c.res) {
+			t.Errorf("case %d expected: %v, got: %v", i, c.res, got)
+		}
+	}
+}
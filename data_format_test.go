@@ -11,6 +11,20 @@ func TestSupportedDataFormats(t *testing.T) {
 		JSONDataFormat,
 		CSVDataFormat,
 		XLSXDataFormat,
+		NDJSONDataFormat,
+		ParquetDataFormat,
+		ArrowDataFormat,
+		AvroDataFormat,
+		TSVDataFormat,
+		YAMLDataFormat,
+		HTMLDataFormat,
+		MarkdownDataFormat,
+		ProtobufDataFormat,
+		ODSDataFormat,
+		XLSDataFormat,
+		ORCDataFormat,
+		GeoJSONDataFormat,
+		ShapefileDataFormat,
 	}
 
 	for i, f := range SupportedDataFormats() {
@@ -31,6 +45,20 @@ func TestDataFormatString(t *testing.T) {
 		{XMLDataFormat, "xml"},
 		{XLSXDataFormat, "xlsx"},
 		{CBORDataFormat, "cbor"},
+		{NDJSONDataFormat, "ndjson"},
+		{ParquetDataFormat, "parquet"},
+		{ArrowDataFormat, "arrow"},
+		{AvroDataFormat, "avro"},
+		{TSVDataFormat, "tsv"},
+		{YAMLDataFormat, "yaml"},
+		{HTMLDataFormat, "html"},
+		{MarkdownDataFormat, "md"},
+		{ProtobufDataFormat, "protobuf"},
+		{ODSDataFormat, "ods"},
+		{XLSDataFormat, "xls"},
+		{ORCDataFormat, "orc"},
+		{GeoJSONDataFormat, "geojson"},
+		{ShapefileDataFormat, "shapefile"},
 	}
 
 	for i, c := range cases {
@@ -58,6 +86,29 @@ func TestParseDataFormatString(t *testing.T) {
 		{"xlsx", XLSXDataFormat, ""},
 		{"cbor", CBORDataFormat, ""},
 		{".cbor", CBORDataFormat, ""},
+		{"ndjson", NDJSONDataFormat, ""},
+		{".jsonl", NDJSONDataFormat, ""},
+		{".parquet", ParquetDataFormat, ""},
+		{".arrows", ArrowDataFormat, ""},
+		{".avro", AvroDataFormat, ""},
+		{"tsv", TSVDataFormat, ""},
+		{".tsv", TSVDataFormat, ""},
+		{"yaml", YAMLDataFormat, ""},
+		{".yml", YAMLDataFormat, ""},
+		{".htm", HTMLDataFormat, ""},
+		{"md", MarkdownDataFormat, ""},
+		{"markdown", MarkdownDataFormat, ""},
+		{".pb", ProtobufDataFormat, ""},
+		{".ods", ODSDataFormat, ""},
+		{"xls", XLSDataFormat, ""},
+		{".xls", XLSDataFormat, ""},
+		{"orc", ORCDataFormat, ""},
+		{".orc", ORCDataFormat, ""},
+		{"geojson", GeoJSONDataFormat, ""},
+		{".geojson", GeoJSONDataFormat, ""},
+		{"shapefile", ShapefileDataFormat, ""},
+		{"shp", ShapefileDataFormat, ""},
+		{".shp", ShapefileDataFormat, ""},
 	}
 
 	for i, c := range cases {
@@ -126,3 +177,66 @@ func TestDataFormatUnmarshalJSON(t *testing.T) {
 
 	}
 }
+
+func TestDataFormatMIMEType(t *testing.T) {
+	for _, f := range SupportedDataFormats() {
+		mt := f.MIMEType()
+		if mt == "" {
+			t.Errorf("supported format %s has no media type", f)
+			continue
+		}
+		got, err := ParseMIME(mt)
+		if err != nil {
+			t.Errorf("format %s unexpected error: %s", f, err)
+			continue
+		}
+		if got != f {
+			t.Errorf("format %s round trip mismatch. got: %s", f, got)
+		}
+	}
+	if UnknownDataFormat.MIMEType() != "" {
+		t.Errorf("expected unknown format to have no media type")
+	}
+}
+
+func TestParseMIME(t *testing.T) {
+	cases := []struct {
+		s      string
+		expect DataFormat
+		err    string
+	}{
+		{"text/csv; charset=utf-8", CSVDataFormat, ""},
+		{"application/CSV", CSVDataFormat, ""},
+		{"text/json", JSONDataFormat, ""},
+		{"text/xml", XMLDataFormat, ""},
+		{"application/jsonl", NDJSONDataFormat, ""},
+		{"application/vnd.apache.arrow.stream", ArrowDataFormat, ""},
+		{"avro/binary", AvroDataFormat, ""},
+		{"text/tab-separated-values", TSVDataFormat, ""},
+		{"text/tsv", TSVDataFormat, ""},
+		{"application/x-yaml", YAMLDataFormat, ""},
+		{"text/html; charset=utf-8", HTMLDataFormat, ""},
+		{"text/markdown", MarkdownDataFormat, ""},
+		{"text/x-markdown", MarkdownDataFormat, ""},
+		{"application/x-protobuf", ProtobufDataFormat, ""},
+		{"application/protobuf", ProtobufDataFormat, ""},
+		{"application/vnd.oasis.opendocument.spreadsheet", ODSDataFormat, ""},
+		{"application/vnd.ms-excel", XLSDataFormat, ""},
+		{"application/vnd.apache.orc", ORCDataFormat, ""},
+		{"application/geo+json", GeoJSONDataFormat, ""},
+		{"x-gis/x-shapefile", ShapefileDataFormat, ""},
+		{"text/plain", UnknownDataFormat, "no data format for media type: `text/plain`"},
+		{"", UnknownDataFormat, "invalid media type: ``"},
+	}
+
+	for i, c := range cases {
+		got, err := ParseMIME(c.s)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("case %d mismatch. expected: %s, got: %s", i, c.expect, got)
+		}
+	}
+}
@@ -52,6 +52,19 @@ type Dataset struct {
 	BodyBytes []byte `json:"bodyBytes,omitempty"`
 	// BodyPath is the path to the hash of raw data as it resolves on the network
 	BodyPath string `json:"bodyPath,omitempty"`
+	// BodyIndexPath is the path to an index of entry positions in the body,
+	// used to read pages of large bodies without reading from the start
+	BodyIndexPath string `json:"bodyIndexPath,omitempty"`
+	// BodyPartitionsPath is the path to a manifest of the shards of a body
+	// split by it's structure's partition
+	BodyPartitionsPath string `json:"bodyPartitionsPath,omitempty"`
+	// Bodies are additional body files published with the dataset, keyed by
+	// name
+	Bodies map[string]*NamedBody `json:"bodies,omitempty"`
+	// FormatDefaults are output configurations keyed by data format name,
+	// used when the body is converted or exported to that format. eg:
+	// {"csv": {"headerRow": true, "separator": ";"}}
+	FormatDefaults map[string]map[string]interface{} `json:"formatDefaults,omitempty"`
 
 	// Commit contains author & change message information that describes this
 	// version of a dataset
@@ -90,6 +103,10 @@ func (ds *Dataset) IsEmpty() bool {
 	return ds.Body == nil &&
 		ds.BodyBytes == nil &&
 		ds.BodyPath == "" &&
+		ds.BodyIndexPath == "" &&
+		ds.BodyPartitionsPath == "" &&
+		len(ds.Bodies) == 0 &&
+		len(ds.FormatDefaults) == 0 &&
 		ds.Commit == nil &&
 		ds.Meta == nil &&
 		ds.Name == "" &&
@@ -225,6 +242,24 @@ func (ds *Dataset) Assign(datasets ...*Dataset) {
 		if d.BodyPath != "" {
 			ds.BodyPath = d.BodyPath
 		}
+		if d.BodyIndexPath != "" {
+			ds.BodyIndexPath = d.BodyIndexPath
+		}
+		if d.BodyPartitionsPath != "" {
+			ds.BodyPartitionsPath = d.BodyPartitionsPath
+		}
+		for name, b := range d.Bodies {
+			if ds.Bodies == nil {
+				ds.Bodies = map[string]*NamedBody{}
+			}
+			ds.Bodies[name] = b
+		}
+		for format, opts := range d.FormatDefaults {
+			if ds.FormatDefaults == nil {
+				ds.FormatDefaults = map[string]map[string]interface{}{}
+			}
+			ds.FormatDefaults[format] = opts
+		}
 
 		if ds.Commit == nil && d.Commit != nil {
 			ds.Commit = d.Commit
@@ -288,14 +323,35 @@ func (ds *Dataset) MarshalJSON() ([]byte, error) {
 	return json.Marshal(_dataset(*ds))
 }
 
+// MarshalJSONPolicy encodes a dataset, writing empty fields of the dataset &
+// it's structure according to p. MarshalJSONPolicy(OmitEmpty) gives the
+// same encoding as MarshalJSON
+func (ds *Dataset) MarshalJSONPolicy(p EmptyPolicy) ([]byte, error) {
+	if ds.Path != "" && ds.IsEmpty() {
+		return json.Marshal(ds.Path)
+	}
+
+	values := map[string]interface{}{}
+	if ds.Qri == "" {
+		values["qri"] = KindDataset.String()
+	}
+	if ds.Structure != nil {
+		st, err := ds.Structure.MarshalJSONPolicy(p)
+		if err != nil {
+			return nil, err
+		}
+		values["structure"] = json.RawMessage(st)
+	}
+	return marshalFields(_dataset(*ds), p, values)
+}
+
 // internal struct for json unmarshaling
 type _dataset Dataset
 
 // UnmarshalJSON implements json.Unmarshaller
 func (ds *Dataset) UnmarshalJSON(data []byte) error {
 	// first check to see if this is a valid path ref
-	var path string
-	if err := json.Unmarshal(data, &path); err == nil {
+	if path, ok := unmarshalPathRef(data); ok {
 		*ds = Dataset{Path: path}
 		return nil
 	}
@@ -51,6 +51,9 @@ func TestDatasetAssign(t *testing.T) {
 		{&Dataset{Transform: &Transform{ScriptPath: "some_transform_script.star"}}},
 		{&Dataset{Commit: &Commit{Title: "foo"}}},
 		{&Dataset{BodyPath: "foo"}},
+		{&Dataset{BodyIndexPath: "foo"}},
+		{&Dataset{Bodies: map[string]*NamedBody{"errata": {Path: "/errata"}}}},
+		{&Dataset{FormatDefaults: map[string]map[string]interface{}{"csv": {"headerRow": true}}}},
 		{&Dataset{PreviousPath: "stuff"}},
 		{&Dataset{Meta: &Meta{Title: "foo"}}},
 		{&Dataset{Viz: &Viz{Qri: KindViz.String()}}},
@@ -224,6 +227,9 @@ func TestDatasetIsEmpty(t *testing.T) {
 	}{
 		{&Dataset{Commit: &Commit{}}},
 		{&Dataset{BodyPath: "foo"}},
+		{&Dataset{BodyIndexPath: "foo"}},
+		{&Dataset{Bodies: map[string]*NamedBody{"errata": {}}}},
+		{&Dataset{FormatDefaults: map[string]map[string]interface{}{"csv": {}}}},
 		{&Dataset{Meta: &Meta{}}},
 		{&Dataset{PreviousPath: "nope"}},
 		{&Dataset{Structure: &Structure{}}},
@@ -0,0 +1,19 @@
+package detect
+
+import (
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// AvroSchema determines a schema from the writer schema of an avro object
+// container file. Only the file header is read
+func AvroSchema(r *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	ar, err := dsio.NewAvroReader(r, data)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, 0, err
+	}
+	return ar.Schema(), int(ar.BytesProcessed()), nil
+}
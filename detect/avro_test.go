@@ -0,0 +1,53 @@
+package detect
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+func TestAvroSchema(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "avro",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "name", "type": "string"},
+					map[string]interface{}{"title": "count", "type": "integer"},
+					map[string]interface{}{"title": "ratio", "type": "number"},
+					map[string]interface{}{"title": "ok", "type": "boolean"},
+				},
+			},
+		},
+	}
+	buf := &bytes.Buffer{}
+	w, err := dsio.NewAvroWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(dsio.Entry{Value: []interface{}{"a", 1, 0.5, true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := AvroSchema(&dataset.Structure{Format: "avro"}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, _ := json.Marshal(st.Schema)
+	gotData, _ := json.Marshal(got)
+	if !bytes.Equal(expect, gotData) {
+		t.Errorf("schema mismatch.\nexpected: %s\ngot:      %s", expect, gotData)
+	}
+
+	if _, _, err := AvroSchema(&dataset.Structure{Format: "avro"}, bytes.NewReader([]byte("a,b,c\n"))); err == nil {
+		t.Error("expected error reading non-avro data")
+	}
+}
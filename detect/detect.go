@@ -7,9 +7,12 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	logger "github.com/ipfs/go-log"
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/compression"
+	"github.com/qri-io/dataset/dsio"
 )
 
 var (
@@ -19,7 +22,8 @@ var (
 )
 
 // FromFile takes a filepath & tries to work out the corresponding dataset
-// for the sake of speed, it only works with files that have a recognized extension
+// for the sake of speed, it only works with files that have a recognized extension.
+// files ending in .gz, .bz2, or .zst are decompressed, setting the structure's compression
 func FromFile(path string) (st *dataset.Structure, err error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -27,15 +31,42 @@ func FromFile(path string) (st *dataset.Structure, err error) {
 	}
 	defer f.Close()
 
+	compression, path := ExtensionCompression(path)
 	format, err := ExtensionDataFormat(path)
 	if err != nil {
 		return nil, err
 	}
 
-	st, _, err = FromReader(format, f)
+	r, err := dsio.Decompress(compression, f)
+	if err != nil {
+		return nil, err
+	}
+
+	st, _, err = FromReader(format, r)
+	if st != nil {
+		st.Compression = compression
+	}
 	return st, err
 }
 
+// ExtensionCompression returns the compression of a path with a compressed
+// file extension, and the path with that extension removed. Paths without a
+// compressed extension return an empty compression & the path unchanged
+func ExtensionCompression(path string) (name, trimmed string) {
+	ext := filepath.Ext(path)
+	switch ext {
+	case ".gz":
+		name = compression.Gzip.String()
+	case ".bz2":
+		name = compression.Bzip2.String()
+	case ".zst":
+		name = compression.Zstd.String()
+	default:
+		return "", path
+	}
+	return name, strings.TrimSuffix(path, ext)
+}
+
 // FromReader detects a dataset structure from a reader and data format, returning a detected dataset
 // structure, the number of bytes read from the reader, and any error
 func FromReader(format dataset.DataFormat, data io.Reader) (st *dataset.Structure, n int, err error) {
@@ -61,6 +92,34 @@ func ExtensionDataFormat(path string) (format dataset.DataFormat, err error) {
 		return dataset.XMLDataFormat, nil
 	case ".xlsx":
 		return dataset.XLSXDataFormat, nil
+	case ".ndjson", ".jsonl":
+		return dataset.NDJSONDataFormat, nil
+	case ".parquet":
+		return dataset.ParquetDataFormat, nil
+	case ".arrow", ".arrows":
+		return dataset.ArrowDataFormat, nil
+	case ".avro":
+		return dataset.AvroDataFormat, nil
+	case ".tsv":
+		return dataset.TSVDataFormat, nil
+	case ".yaml", ".yml":
+		return dataset.YAMLDataFormat, nil
+	case ".html", ".htm":
+		return dataset.HTMLDataFormat, nil
+	case ".md", ".markdown":
+		return dataset.MarkdownDataFormat, nil
+	case ".pb":
+		return dataset.ProtobufDataFormat, nil
+	case ".ods":
+		return dataset.ODSDataFormat, nil
+	case ".xls":
+		return dataset.XLSDataFormat, nil
+	case ".orc":
+		return dataset.ORCDataFormat, nil
+	case ".geojson":
+		return dataset.GeoJSONDataFormat, nil
+	case ".shp":
+		return dataset.ShapefileDataFormat, nil
 	case "":
 		return dataset.UnknownDataFormat, errors.New("no file extension provided")
 	default:
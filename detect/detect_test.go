@@ -86,6 +86,21 @@ func TestExtensionDataFormat(t *testing.T) {
 		{"foo/bar/baz.json", dataset.JSONDataFormat, ""},
 		{"foo/bar/baz.xml", dataset.XMLDataFormat, ""},
 		{"foo/bar/baz.xlsx", dataset.XLSXDataFormat, ""},
+		{"foo/bar/baz.jsonl", dataset.NDJSONDataFormat, ""},
+		{"foo/bar/baz.parquet", dataset.ParquetDataFormat, ""},
+		{"foo/bar/baz.arrows", dataset.ArrowDataFormat, ""},
+		{"foo/bar/baz.avro", dataset.AvroDataFormat, ""},
+		{"foo/bar/baz.tsv", dataset.TSVDataFormat, ""},
+		{"foo/bar/baz.yaml", dataset.YAMLDataFormat, ""},
+		{"foo/bar/baz.yml", dataset.YAMLDataFormat, ""},
+		{"foo/bar/baz.html", dataset.HTMLDataFormat, ""},
+		{"foo/bar/README.md", dataset.MarkdownDataFormat, ""},
+		{"foo/bar/baz.pb", dataset.ProtobufDataFormat, ""},
+		{"foo/bar/baz.ods", dataset.ODSDataFormat, ""},
+		{"foo/bar/baz.xls", dataset.XLSDataFormat, ""},
+		{"foo/bar/baz.orc", dataset.ORCDataFormat, ""},
+		{"foo/bar/baz.geojson", dataset.GeoJSONDataFormat, ""},
+		{"foo/bar/baz.shp", dataset.ShapefileDataFormat, ""},
 		{"foo/bar/baz.cbor", dataset.CBORDataFormat, ""},
 		{"foo/bar/baz", dataset.UnknownDataFormat, "no file extension provided"},
 		{"foo/bar/baz.jpg", dataset.UnknownDataFormat, "unsupported file type: '.jpg'"},
@@ -103,3 +118,49 @@ func TestExtensionDataFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestFromFileCompressed(t *testing.T) {
+	expect, err := FromFile("testdata/hours-with-header.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		path, compression string
+	}{
+		{"testdata/hours-with-header.csv.gz", "gzip"},
+		{"testdata/hours-with-header.csv.bz2", "bzip2"},
+		{"testdata/hours-with-header.csv.zst", "zstd"},
+	}
+	for i, c := range cases {
+		st, err := FromFile(c.path)
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if st.Compression != c.compression {
+			t.Errorf("case %d compression mismatch. expected: %s, got: %s", i, c.compression, st.Compression)
+		}
+		st.Compression = ""
+		if err := dataset.CompareStructures(expect, st); err != nil {
+			t.Errorf("case %d structure mismatch: %s", i, err)
+		}
+	}
+}
+
+func TestExtensionCompression(t *testing.T) {
+	cases := []struct {
+		path, compression, trimmed string
+	}{
+		{"foo/bar/baz.csv", "", "foo/bar/baz.csv"},
+		{"foo/bar/baz.csv.gz", "gzip", "foo/bar/baz.csv"},
+		{"foo/bar/baz.json.bz2", "bzip2", "foo/bar/baz.json"},
+		{"foo/bar/baz.ndjson.zst", "zstd", "foo/bar/baz.ndjson"},
+		{"foo/bar/baz.gz", "gzip", "foo/bar/baz"},
+	}
+	for i, c := range cases {
+		compression, trimmed := ExtensionCompression(c.path)
+		if compression != c.compression || trimmed != c.trimmed {
+			t.Errorf("case %d mismatch. expected: %s %s, got: %s %s", i, c.compression, c.trimmed, compression, trimmed)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package detect
 import (
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
@@ -36,6 +37,28 @@ func Schema(r *dataset.Structure, data io.Reader) (schema map[string]interface{}
 		return CSVSchema(r, data)
 	case dataset.XLSXDataFormat:
 		return XLSXSchema(r, data)
+	case dataset.NDJSONDataFormat:
+		return NDJSONSchema(r, data)
+	case dataset.ParquetDataFormat:
+		return ParquetSchema(r, data)
+	case dataset.AvroDataFormat:
+		return AvroSchema(r, data)
+	case dataset.TSVDataFormat:
+		return TSVSchema(r, data)
+	case dataset.YAMLDataFormat:
+		return YAMLSchema(r, data)
+	case dataset.HTMLDataFormat:
+		return HTMLSchema(r, data)
+	case dataset.ProtobufDataFormat:
+		return ProtobufSchema(r, data)
+	case dataset.ODSDataFormat:
+		return ODSSchema(r, data)
+	case dataset.XLSDataFormat:
+		return XLSSchema(r, data)
+	case dataset.GeoJSONDataFormat:
+		return GeoJSONSchema(r, data)
+	case dataset.ShapefileDataFormat:
+		return ShapefileSchema(r, data)
 	default:
 		err = fmt.Errorf("'%s' is not supported for field detection", r.Format)
 		return
@@ -62,9 +85,16 @@ func CSVSchema(resource *dataset.Structure, data io.Reader) (schema map[string]i
 	}
 	resource.FormatConfig = opt
 
-	header, err := r.Read()
+	sch, err := recordsSchema("csv", r.Read, opt)
+	return sch, tr.BytesRead(), err
+}
+
+// recordsSchema determines the field names and types of delimited records,
+// setting headerRow & variadicFields format options as they're detected
+func recordsSchema(format string, read func() ([]string, error), opt map[string]interface{}) (map[string]interface{}, error) {
+	header, err := read()
 	if err != nil {
-		return nil, tr.BytesRead(), err
+		return nil, err
 	}
 
 	fields := make([]*field, len(header))
@@ -92,16 +122,16 @@ func CSVSchema(resource *dataset.Structure, data io.Reader) (schema map[string]i
 
 	count := 0
 	for {
-		rec, err := r.Read()
+		rec, err := read()
 		// max out at 2000 reads
 		if count > 2000 {
 			break
 		}
 		if err != nil {
-			if err.Error() == "EOF" {
+			if errors.Is(err, io.EOF) {
 				break
 			}
-			return nil, tr.BytesRead(), fmt.Errorf("error reading csv file: %s", err.Error())
+			return nil, fmt.Errorf("error reading %s file: %s", format, err.Error())
 		}
 
 		if len(rec) == len(types) {
@@ -125,16 +155,16 @@ func CSVSchema(resource *dataset.Structure, data io.Reader) (schema map[string]i
 	// TODO - lol what a hack. fix everything, put it in jsonschema.
 	items, err := json.Marshal(fields)
 	if err != nil {
-		return nil, tr.BytesRead(), fmt.Errorf("error marshaling csv fields to json: %s", err.Error())
+		return nil, fmt.Errorf("error marshaling %s fields to json: %s", format, err.Error())
 	}
 	schstr := fmt.Sprintf(`{"type":"array","items":{"type":"array","items":%s}}`, string(items))
 
 	sch := map[string]interface{}{}
 	if err := json.Unmarshal([]byte(schstr), &sch); err != nil {
-		return nil, tr.BytesRead(), err
+		return nil, err
 	}
 
-	return sch, tr.BytesRead(), nil
+	return sch, nil
 }
 
 // PossibleHeaderRow makes an educated guess about weather or not this csv file has a header row.
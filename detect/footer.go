@@ -0,0 +1,78 @@
+package detect
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/qri-io/dataset/dsio/replacecr"
+)
+
+// maxFooterRows caps the number of trailing rows CSVFooterRows reports
+const maxFooterRows = 10
+
+// footerLabelRegex matches labels that start summary rows
+var footerLabelRegex = regexp.MustCompile(`(?i)^((grand |sub)?totals?|sum|count|average|balance|end of (report|file))\b`)
+
+// CSVFooterRows guesses the number of summary rows at the end of CSV-formatted
+// data, like the totals common in bank & ERP exports. Trailing rows are counted
+// as footer rows if they're blank, start with a label like "Total", or have
+// fewer fields than most rows. The result is suitable for the skipFooterRows
+// CSV format config option
+func CSVFooterRows(data io.Reader) (int, error) {
+	r := csv.NewReader(replacecr.Reader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	// tail holds the last rows read, widths counts rows by number of fields
+	var tail [][]string
+	widths := map[int]int{}
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, fmt.Errorf("error reading csv data: %w", err)
+		}
+		widths[len(rec)]++
+		tail = append(tail, rec)
+		if len(tail) > maxFooterRows+1 {
+			tail = tail[1:]
+		}
+	}
+
+	width, most := 0, 0
+	for w, n := range widths {
+		if n > most || (n == most && w > width) {
+			width, most = w, n
+		}
+	}
+
+	n := 0
+	for i := len(tail) - 1; i >= 0 && n < maxFooterRows; i-- {
+		if !footerRow(tail[i], width) {
+			break
+		}
+		n++
+	}
+	// a file that's all "footer" has no body to separate it from
+	if n == len(tail) {
+		return 0, nil
+	}
+	return n, nil
+}
+
+// footerRow checks if a record looks like a summary row in data where most
+// rows have width fields
+func footerRow(rec []string, width int) bool {
+	label := ""
+	for _, f := range rec {
+		if f = strings.TrimSpace(f); f != "" {
+			label = f
+			break
+		}
+	}
+	return label == "" || footerLabelRegex.MatchString(label) || len(rec) < width
+}
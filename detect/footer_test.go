@@ -0,0 +1,31 @@
+package detect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCSVFooterRows(t *testing.T) {
+	cases := []struct {
+		data   string
+		expect int
+	}{
+		{"a,b\n1,2\n3,4\n", 0},
+		{"a,b\n1,2\n3,4\nTotal,6\n", 1},
+		{"a,b\n1,2\n3,4\nSubtotal,6\nGrand Total,6\n", 2},
+		{"date,amount,memo\n1,2,x\n3,4,y\nReport generated 2019-01-01\n", 1},
+		{"a,b\n1,2\n3,4\n,\n", 1},
+		{"Total,6\n", 0},
+	}
+
+	for i, c := range cases {
+		got, err := CSVFooterRows(strings.NewReader(c.data))
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("case %d expected %d footer rows, got: %d", i, c.expect, got)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package detect
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// geoJSONSampleFeatures is the number of features GeoJSONSchema reads to
+// infer property types
+const geoJSONSampleFeatures = 1000
+
+// GeoJSONSchema determines a schema for the features of a GeoJSON
+// FeatureCollection, inferring property types from the first features of the
+// collection. Properties that are null or missing in some features are
+// nullable, and properties with conflicting types accept each observed type
+func GeoJSONSchema(r *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	st := &dataset.Structure{Format: dataset.GeoJSONDataFormat.String(), Schema: dataset.BaseSchemaArray}
+	rdr, err := dsio.NewGeoJSONReader(st, data)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, 0, err
+	}
+
+	var (
+		keys  []string
+		types = map[string]map[string]bool{}
+		count int
+	)
+	for count < geoJSONSampleFeatures {
+		ent, err := rdr.ReadEntry()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			log.Debug(err.Error())
+			return nil, int(rdr.BytesProcessed()), fmt.Errorf("error reading geojson features: %w", err)
+		}
+		count++
+
+		props, _ := ent.Value.(map[string]interface{})["properties"].(map[string]interface{})
+		for key, v := range props {
+			t := jsonType(v)
+			if t == "" {
+				continue
+			}
+			if types[key] == nil {
+				// a property missing from earlier features is nullable
+				types[key] = map[string]bool{}
+				if count > 1 {
+					types[key]["null"] = true
+				}
+				keys = append(keys, key)
+			}
+			types[key][t] = true
+		}
+		for _, key := range keys {
+			if _, ok := props[key]; !ok {
+				types[key]["null"] = true
+			}
+		}
+	}
+
+	properties := map[string]interface{}{}
+	for _, key := range keys {
+		def := map[string]interface{}{}
+		if t := typeValue(widenTypes(nil, sortedTypes(types[key]))); t != nil {
+			def["type"] = t
+		}
+		properties[key] = def
+	}
+
+	schema = map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"geometry": map[string]interface{}{"type": []interface{}{"null", "object"}},
+				"properties": map[string]interface{}{
+					"type":       []interface{}{"null", "object"},
+					"properties": properties,
+				},
+			},
+			"required": []interface{}{"geometry", "properties"},
+		},
+	}
+	return schema, int(rdr.BytesProcessed()), nil
+}
@@ -0,0 +1,53 @@
+package detect
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestGeoJSONSchema(t *testing.T) {
+	data := `{"type":"FeatureCollection","features":[
+  {"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a","pop":1,"ok":true,"tags":["x"]}},
+  {"type":"Feature","geometry":null,"properties":{"name":"b","pop":2.5,"ok":null,"extra":{"k":1}}},
+  {"type":"Feature","geometry":null,"properties":{"name":3,"pop":4,"ok":false,"tags":[],"extra":{}}}
+]}`
+	st := &dataset.Structure{Format: "geojson"}
+	sch, n, err := Schema(st, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) {
+		t.Errorf("expected %d bytes read, got: %d", len(data), n)
+	}
+
+	expect := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"geometry": map[string]interface{}{"type": []interface{}{"null", "object"}},
+				"properties": map[string]interface{}{
+					"type": []interface{}{"null", "object"},
+					"properties": map[string]interface{}{
+						"name":  map[string]interface{}{"type": []interface{}{"integer", "string"}},
+						"pop":   map[string]interface{}{"type": "number"},
+						"ok":    map[string]interface{}{"type": []interface{}{"boolean", "null"}},
+						"tags":  map[string]interface{}{"type": []interface{}{"array", "null"}},
+						"extra": map[string]interface{}{"type": []interface{}{"null", "object"}},
+					},
+				},
+			},
+			"required": []interface{}{"geometry", "properties"},
+		},
+	}
+	if !reflect.DeepEqual(sch, expect) {
+		t.Errorf("schema mismatch.\nexpected: %#v\ngot:      %#v", expect, sch)
+	}
+
+	if _, _, err := GeoJSONSchema(st, strings.NewReader(`{"type":"Topology"}`)); err == nil {
+		t.Error("expected error for a non-FeatureCollection document")
+	}
+}
@@ -0,0 +1,53 @@
+package detect
+
+import (
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// HTMLSchema determines the field names and types of the table a structure's
+// HTMLOptions select from a html document, returning a json schema. The
+// selector & table index of the structure's format config are kept
+func HTMLSchema(resource *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	opts, err := dataset.NewHTMLOptions(resource.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, 0, err
+	}
+	opt := map[string]interface{}{}
+	if opts.Selector != "" {
+		opt["selector"] = opts.Selector
+	}
+	if opts.Table != 0 {
+		opt["table"] = opts.Table
+	}
+	resource.FormatConfig = opt
+
+	// rows are read as strings from a schema without columns
+	tr := dsio.NewTrackedReader(data)
+	st := &dataset.Structure{Format: dataset.HTMLDataFormat.String(), FormatConfig: opts.Map(), Schema: dataset.BaseSchemaArray}
+	st.FormatConfig["headerRow"] = false
+	r, err := dsio.NewHTMLTableReader(st, tr)
+	if err != nil {
+		return nil, 0, err
+	}
+	read := func() ([]string, error) {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			return nil, err
+		}
+		vals := ent.Value.([]interface{})
+		rec := make([]string, len(vals))
+		for i, v := range vals {
+			rec[i] = v.(string)
+		}
+		return rec, nil
+	}
+
+	sch, err := recordsSchema("html", read, opt)
+	// html rows are commonly ragged, detection doesn't set variadicFields
+	delete(opt, "variadicFields")
+	return sch, tr.BytesRead(), err
+}
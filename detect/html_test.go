@@ -0,0 +1,48 @@
+package detect
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestHTMLSchema(t *testing.T) {
+	page := `<html><body>
+<table><tr><td>nav</td></tr></table>
+<table id="scores">
+  <tr><th>Name</th><th>Score</th></tr>
+  <tr><td>ada</td><td>12</td></tr>
+  <tr><td>grace</td><td>9</td></tr>
+</table>
+</body></html>`
+
+	st := &dataset.Structure{Format: "html", FormatConfig: map[string]interface{}{"selector": "#scores"}}
+	got, _, err := Schema(st, strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "name", "type": "string"},
+				map[string]interface{}{"title": "score", "type": "integer"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("schema mismatch.\nexpected: %v\ngot:      %v", expect, got)
+	}
+	expectCfg := map[string]interface{}{"selector": "#scores", "headerRow": true}
+	if !reflect.DeepEqual(st.FormatConfig, expectCfg) {
+		t.Errorf("format config mismatch.\nexpected: %v\ngot:      %v", expectCfg, st.FormatConfig)
+	}
+
+	st = &dataset.Structure{Format: "html", FormatConfig: map[string]interface{}{"table": 2}}
+	if _, _, err := Schema(st, strings.NewReader(page)); err == nil {
+		t.Error("expected an error reading a missing table")
+	}
+}
@@ -1,6 +1,7 @@
 package detect
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 
@@ -52,3 +53,28 @@ func JSONSchema(resource *dataset.Structure, data io.Reader) (schema map[string]
 		}
 	}
 }
+
+// NDJSONSchema checks an io.Reader of newline-delimited JSON starts with a JSON
+// value, returning a generic array schema. NDJSON bodies are always arrays
+func NDJSONSchema(resource *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	rdr := bufio.NewReader(data)
+	for {
+		b, rerr := rdr.ReadByte()
+		if rerr != nil {
+			if rerr != io.EOF {
+				log.Debugf(rerr.Error())
+				return nil, n, fmt.Errorf("error reading data: %s", rerr.Error())
+			}
+			return nil, n, fmt.Errorf("invalid ndjson data")
+		}
+		n++
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '[', '{', '"', '-', 't', 'f', 'n', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			return dataset.BaseSchemaArray, n, nil
+		default:
+			return nil, n, fmt.Errorf("invalid ndjson data")
+		}
+	}
+}
@@ -45,3 +45,28 @@ func TestJSONSchema(t *testing.T) {
 		}
 	}
 }
+
+func TestNDJSONSchema(t *testing.T) {
+	cases := []struct {
+		data   string
+		expect map[string]interface{}
+		err    string
+	}{
+		{"", nil, "invalid ndjson data"},
+		{"<xml>", nil, "invalid ndjson data"},
+		{"{\"a\":1}\n{\"a\":2}\n", dataset.BaseSchemaArray, ""},
+		{"\n\n[1,2]\n", dataset.BaseSchemaArray, ""},
+		{"5\n", dataset.BaseSchemaArray, ""},
+	}
+
+	for i, c := range cases {
+		got, _, err := NDJSONSchema(&dataset.Structure{}, strings.NewReader(c.data))
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+		if err := dataset.CompareSchemas(got, c.expect); err != nil {
+			t.Errorf("case %d returned schema mismatch: %s", i, err)
+		}
+	}
+}
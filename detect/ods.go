@@ -0,0 +1,13 @@
+package detect
+
+import (
+	"io"
+
+	"github.com/qri-io/dataset"
+)
+
+// ODSSchema determines any schema information for an OpenDocument spreadsheet
+// TODO: currently unimplemented, like XLSXSchema
+func ODSSchema(r *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	return dataset.BaseSchemaArray, 0, nil
+}
@@ -0,0 +1,19 @@
+package detect
+
+import (
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// ParquetSchema determines a tabular schema from the columns of a parquet
+// file. The file is read in full
+func ParquetSchema(r *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	pr, err := dsio.NewParquetReader(r, data)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, 0, err
+	}
+	return pr.Schema(), int(pr.BytesProcessed()), nil
+}
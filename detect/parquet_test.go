@@ -0,0 +1,57 @@
+package detect
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+func TestParquetSchema(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "parquet",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "name", "type": "string"},
+					map[string]interface{}{"title": "count", "type": "integer"},
+					map[string]interface{}{"title": "ratio", "type": "number"},
+					map[string]interface{}{"title": "ok", "type": "boolean"},
+				},
+			},
+		},
+	}
+	buf := &bytes.Buffer{}
+	w, err := dsio.NewParquetWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(dsio.Entry{Value: []interface{}{"a", 1, 0.5, true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	size := buf.Len()
+	got, n, err := ParquetSchema(&dataset.Structure{Format: "parquet"}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != size {
+		t.Errorf("bytes read mismatch. expected: %d, got: %d", size, n)
+	}
+	expect, _ := json.Marshal(st.Schema)
+	gotData, _ := json.Marshal(got)
+	if !bytes.Equal(expect, gotData) {
+		t.Errorf("schema mismatch.\nexpected: %s\ngot:      %s", expect, gotData)
+	}
+
+	if _, _, err := ParquetSchema(&dataset.Structure{Format: "parquet"}, bytes.NewReader([]byte("a,b,c\n"))); err == nil {
+		t.Error("expected error reading non-parquet data")
+	}
+}
@@ -0,0 +1,19 @@
+package detect
+
+import (
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// ProtobufSchema derives a schema from the message type named by a
+// structure's ProtobufOptions. The schema comes from the descriptor, no data
+// is read
+func ProtobufSchema(r *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	sch, err := dsio.ProtobufSchema(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return sch, 0, nil
+}
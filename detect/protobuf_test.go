@@ -0,0 +1,45 @@
+package detect
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+// pointDescriptor is a FileDescriptorSet for:
+//
+//	syntax = "proto3";
+//	message Point { int32 x = 1; string label = 2; }
+const pointDescriptor = "CjgKC3BvaW50LnByb3RvIiEKBVBvaW50EgkKAXgYASABKAUSDQoFbGFiZWwYAiABKAliBnByb3RvMw=="
+
+func TestProtobufSchema(t *testing.T) {
+	st := &dataset.Structure{Format: "protobuf", FormatConfig: map[string]interface{}{"descriptor": pointDescriptor}}
+	got, n, err := Schema(st, strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("expected no bytes read, got: %d", n)
+	}
+	expect := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":  "object",
+			"title": "Point",
+			"properties": map[string]interface{}{
+				"x":     map[string]interface{}{"type": "integer"},
+				"label": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("schema mismatch.\nexpected: %v\ngot:      %v", expect, got)
+	}
+
+	st = &dataset.Structure{Format: "protobuf"}
+	if _, _, err := Schema(st, strings.NewReader("")); err == nil {
+		t.Error("expected an error without a descriptor")
+	}
+}
@@ -0,0 +1,524 @@
+package detect
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// SchemaChangeKind names a kind of schema refinement
+type SchemaChangeKind string
+
+const (
+	// ChangeWiden marks a column type widened to accept observed values, like
+	// integer to number, or a type added to a column's type list
+	ChangeWiden SchemaChangeKind = "widen"
+	// ChangeNullable marks a column that observed null values
+	ChangeNullable SchemaChangeKind = "nullable"
+	// ChangeEnum marks observed values added to a column's enum, or an enum
+	// added to a column
+	ChangeEnum SchemaChangeKind = "enum"
+	// ChangeAddColumn marks a column added for observed values the schema
+	// doesn't define
+	ChangeAddColumn SchemaChangeKind = "add"
+)
+
+// SchemaChange describes a single refinement to a column of a schema
+type SchemaChange struct {
+	// Column is the title or property name of the changed column
+	Column string
+	Kind   SchemaChangeKind
+	// From & To are the column's type or enum before & after the change. From
+	// is nil for added columns, To is the added column definition
+	From interface{}
+	To   interface{}
+}
+
+// String formats a change for display
+func (c SchemaChange) String() string {
+	data, _ := json.Marshal(c.To)
+	if c.Kind == ChangeAddColumn {
+		return fmt.Sprintf("%s: add column %s", c.Column, data)
+	}
+	from, _ := json.Marshal(c.From)
+	return fmt.Sprintf("%s: %s %s -> %s", c.Column, c.Kind, from, data)
+}
+
+// SchemaRefinement is a proposed update to a schema
+type SchemaRefinement struct {
+	// Schema is the refined schema. it's a copy, the schema being refined is
+	// never modified
+	Schema map[string]interface{}
+	// Changes lists each refinement in column order
+	Changes []SchemaChange
+	// Entries is the number of entries observed
+	Entries int
+}
+
+// Changed reports whether the refinement proposes any changes
+func (r *SchemaRefinement) Changed() bool {
+	return len(r.Changes) > 0
+}
+
+// RefineConfig encapsulates configuration for schema refinement
+type RefineConfig struct {
+	// EnumLimit proposes an enum for string columns with at most this many
+	// distinct observed values. zero disables proposing new enums, observed
+	// values are always added to existing enums
+	EnumLimit int
+}
+
+// maxObservedValues caps the distinct values recorded for a column
+const maxObservedValues = 1000
+
+// SchemaLearner refines a tabular schema from observed entries, widening
+// column types to fit drifted values instead of rejecting them. Feed it the
+// entries of each new version, then propose an update with Refinement.
+// Schemas without column definitions accept any value & are never refined
+type SchemaLearner struct {
+	cfg      *RefineConfig
+	schema   map[string]interface{}
+	objects  bool
+	columns  []*columnLearner
+	byKey    map[string]*columnLearner
+	observed int
+}
+
+// columnLearner tracks the values observed for a column
+type columnLearner struct {
+	key string
+	def map[string]interface{}
+	// added is true for columns the schema doesn't define
+	added bool
+	types map[string]bool
+	// values are distinct observed scalars keyed by their JSON encoding, nil
+	// once there are too many to be useful. columns with an enum record up to
+	// maxObservedValues
+	values map[string]interface{}
+}
+
+// NewSchemaLearner creates a learner that refines schema
+func NewSchemaLearner(schema map[string]interface{}, configs ...func(cfg *RefineConfig)) *SchemaLearner {
+	cfg := &RefineConfig{}
+	for _, config := range configs {
+		config(cfg)
+	}
+	l := &SchemaLearner{
+		cfg:    cfg,
+		schema: schema,
+		byKey:  map[string]*columnLearner{},
+	}
+
+	items, _ := schema["items"].(map[string]interface{})
+	if schema["type"] != "array" || items == nil {
+		return l
+	}
+	switch items["type"] {
+	case "array":
+		cols, _ := items["items"].([]interface{})
+		for i, c := range cols {
+			def, _ := c.(map[string]interface{})
+			title, _ := def["title"].(string)
+			if title == "" {
+				title = dataset.AbstractColumnName(i)
+			}
+			l.addColumn(title, def, false)
+		}
+	case "object":
+		l.objects = true
+		props, _ := items["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(props))
+		for key := range props {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			def, _ := props[key].(map[string]interface{})
+			l.addColumn(key, def, false)
+		}
+	}
+	return l
+}
+
+func (l *SchemaLearner) addColumn(key string, def map[string]interface{}, added bool) *columnLearner {
+	col := &columnLearner{
+		key:    key,
+		def:    def,
+		added:  added,
+		types:  map[string]bool{},
+		values: map[string]interface{}{},
+	}
+	l.columns = append(l.columns, col)
+	l.byKey[key] = col
+	return col
+}
+
+// refinable reports whether the schema defines columns to refine
+func (l *SchemaLearner) refinable() bool {
+	return len(l.columns) > 0
+}
+
+// Observe records the values of an entry. entries that don't match the shape
+// of the schema are ignored
+func (l *SchemaLearner) Observe(ent dsio.Entry) {
+	if !l.refinable() {
+		return
+	}
+	switch v := ent.Value.(type) {
+	case []interface{}:
+		if l.objects {
+			return
+		}
+		for i, val := range v {
+			if i >= len(l.columns) {
+				l.addColumn(dataset.AbstractColumnName(i), nil, true)
+			}
+			l.columns[i].observe(val, l.cfg)
+		}
+	case map[string]interface{}:
+		if !l.objects {
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			col := l.byKey[key]
+			if col == nil {
+				col = l.addColumn(key, nil, true)
+			}
+			col.observe(v[key], l.cfg)
+		}
+	default:
+		return
+	}
+	l.observed++
+}
+
+func (c *columnLearner) observe(v interface{}, cfg *RefineConfig) {
+	t := jsonType(v)
+	if t == "" {
+		return
+	}
+	c.types[t] = true
+
+	if c.values == nil || t == "array" || t == "object" {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	c.values[string(data)] = v
+	// existing enums record every value, new enums are capped by the limit
+	if _, ok := c.def["enum"]; !ok && len(c.values) > cfg.EnumLimit || len(c.values) > maxObservedValues {
+		c.values = nil
+	}
+}
+
+// jsonType gives the JSON schema type of a value. whole number floats are
+// integers
+func jsonType(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case float32:
+		return jsonType(float64(n))
+	case float64:
+		if n == math.Trunc(n) && !math.IsInf(n, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return ""
+}
+
+// Refinement proposes an update to the schema that accepts every observed
+// entry
+func (l *SchemaLearner) Refinement() *SchemaRefinement {
+	ref := &SchemaRefinement{
+		Schema:  copySchema(l.schema).(map[string]interface{}),
+		Entries: l.observed,
+	}
+	if !l.refinable() {
+		return ref
+	}
+
+	items := ref.Schema["items"].(map[string]interface{})
+	var (
+		cols  []interface{}
+		props map[string]interface{}
+	)
+	if l.objects {
+		props, _ = items["properties"].(map[string]interface{})
+	} else {
+		cols, _ = items["items"].([]interface{})
+	}
+
+	for i, col := range l.columns {
+		if col.added {
+			def := col.proposeNew(l.cfg)
+			if l.objects {
+				props[col.key] = def
+			} else {
+				def["title"] = col.key
+				cols = append(cols, def)
+			}
+			ref.Changes = append(ref.Changes, SchemaChange{Column: col.key, Kind: ChangeAddColumn, To: def})
+			continue
+		}
+
+		var def map[string]interface{}
+		if l.objects {
+			def, _ = props[col.key].(map[string]interface{})
+		} else {
+			def, _ = cols[i].(map[string]interface{})
+		}
+		if def == nil {
+			continue
+		}
+		ref.Changes = append(ref.Changes, col.refine(def, l.cfg)...)
+	}
+	if !l.objects {
+		items["items"] = cols
+	}
+	return ref
+}
+
+// proposeNew gives a definition for a column the schema doesn't define
+func (c *columnLearner) proposeNew(cfg *RefineConfig) map[string]interface{} {
+	def := map[string]interface{}{}
+	types := []string{}
+	for t := range c.types {
+		types = append(types, t)
+	}
+	if t := typeValue(widenTypes(nil, types)); t != nil {
+		def["type"] = t
+	}
+	if enum := c.proposeEnum(cfg); enum != nil {
+		def["enum"] = enum
+	}
+	return def
+}
+
+// refine updates a column definition in place, returning the changes made
+func (c *columnLearner) refine(def map[string]interface{}, cfg *RefineConfig) (changes []SchemaChange) {
+	from := typeList(def["type"])
+	if len(from) > 0 {
+		var widened, nullable bool
+		to := append([]string{}, from...)
+		for _, t := range sortedTypes(c.types) {
+			if t == "null" {
+				if !containsType(to, "null") {
+					to = append(to, "null")
+					nullable = true
+				}
+				continue
+			}
+			if accepts(to, t) {
+				continue
+			}
+			to = widenTypes(to, []string{t})
+			widened = true
+		}
+		if widened || nullable {
+			// a widened type list is sorted, a nullable type keeps it's order
+			// with null appended
+			if widened {
+				sort.Strings(to)
+			}
+			kind := ChangeWiden
+			if !widened {
+				kind = ChangeNullable
+			}
+			def["type"] = typeValue(to)
+			changes = append(changes, SchemaChange{Column: c.key, Kind: kind, From: typeValue(from), To: def["type"]})
+		}
+	}
+
+	if enum, ok := def["enum"].([]interface{}); ok && c.values != nil {
+		existing := map[string]bool{}
+		for _, v := range enum {
+			data, _ := json.Marshal(v)
+			existing[string(data)] = true
+		}
+		added := append([]interface{}{}, enum...)
+		for _, key := range sortedValueKeys(c.values) {
+			if !existing[key] {
+				added = append(added, c.values[key])
+			}
+		}
+		if len(added) > len(enum) {
+			def["enum"] = added
+			changes = append(changes, SchemaChange{Column: c.key, Kind: ChangeEnum, From: enum, To: added})
+		}
+	} else if !ok {
+		if enum := c.proposeEnum(cfg); enum != nil {
+			def["enum"] = enum
+			changes = append(changes, SchemaChange{Column: c.key, Kind: ChangeEnum, To: enum})
+		}
+	}
+	return changes
+}
+
+// proposeEnum gives an enum of observed values for columns that only hold
+// strings, nil if there are too many distinct values
+func (c *columnLearner) proposeEnum(cfg *RefineConfig) []interface{} {
+	if cfg.EnumLimit <= 0 || c.values == nil || !c.types["string"] {
+		return nil
+	}
+	for t := range c.types {
+		if t != "string" && t != "null" {
+			return nil
+		}
+	}
+	enum := []interface{}{}
+	for _, key := range sortedValueKeys(c.values) {
+		enum = append(enum, c.values[key])
+	}
+	return enum
+}
+
+// accepts reports whether a type list accepts values of type t
+func accepts(types []string, t string) bool {
+	return containsType(types, t) || t == "integer" && containsType(types, "number")
+}
+
+// widenTypes adds observed types to a type list, replacing integer with
+// number when numbers are observed
+func widenTypes(types []string, observed []string) []string {
+	for _, t := range observed {
+		if t == "null" || accepts(types, t) {
+			continue
+		}
+		if t == "number" && containsType(types, "integer") {
+			for i, tt := range types {
+				if tt == "integer" {
+					types[i] = "number"
+				}
+			}
+			continue
+		}
+		types = append(types, t)
+	}
+	if len(observed) > 0 && containsType(observed, "null") {
+		types = append(types, "null")
+	}
+	sort.Strings(types)
+	return types
+}
+
+func containsType(types []string, t string) bool {
+	for _, tt := range types {
+		if tt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// typeList gives the types of a JSON schema "type" value
+func typeList(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, tt := range t {
+			if s, ok := tt.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	case []string:
+		return append([]string{}, t...)
+	}
+	return nil
+}
+
+// typeValue gives the JSON schema "type" value for a type list
+func typeValue(types []string) interface{} {
+	switch len(types) {
+	case 0:
+		return nil
+	case 1:
+		return types[0]
+	}
+	v := make([]interface{}, len(types))
+	for i, t := range types {
+		v[i] = t
+	}
+	return v
+}
+
+func sortedTypes(m map[string]bool) []string {
+	types := make([]string, 0, len(m))
+	for t := range m {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func sortedValueKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// copySchema deep copies the maps & slices of a schema
+func copySchema(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[key] = copySchema(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			s[i] = copySchema(val)
+		}
+		return s
+	}
+	return v
+}
+
+// RefineSchema reads every entry of r, proposing an update to a structure's
+// schema that accepts them. Use it to absorb drift in a new version's body
+// instead of failing validation. The structure isn't modified
+func RefineSchema(st *dataset.Structure, r dsio.EntryReader, configs ...func(cfg *RefineConfig)) (*SchemaRefinement, error) {
+	if st == nil || st.Schema == nil {
+		return nil, fmt.Errorf("structure has no schema to refine")
+	}
+	l := NewSchemaLearner(st.Schema, configs...)
+	err := dsio.EachEntry(r, func(_ int, ent dsio.Entry, _ error) error {
+		l.Observe(ent)
+		return nil
+	})
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	return l.Refinement(), nil
+}
@@ -0,0 +1,150 @@
+package detect
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+func TestRefineSchemaTabular(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "amount", "type": "integer"},
+					map[string]interface{}{"title": "status", "type": "string", "enum": []interface{}{"open", "closed"}},
+					map[string]interface{}{"title": "note", "type": "string"},
+					map[string]interface{}{"title": "code", "type": "integer"},
+					map[string]interface{}{"title": "score", "type": "number"},
+				},
+			},
+		},
+	}
+	body := []byte(`[
+		[1, 10, "open", "a", 1, 1],
+		[2, 10.5, "pending", null, "A1", 2.5],
+		[3, 11, "closed", "b", 3, 3, true]
+	]`)
+	r, err := dsio.NewJSONReader(st, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := json.Marshal(st.Schema)
+
+	ref, err := RefineSchema(st, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := json.Marshal(st.Schema); string(before) != string(after) {
+		t.Error("expected structure schema to be left unmodified")
+	}
+	if ref.Entries != 3 {
+		t.Errorf("expected 3 entries observed, got: %d", ref.Entries)
+	}
+	if !ref.Changed() {
+		t.Fatal("expected refinement to propose changes")
+	}
+
+	expect := []string{
+		`amount: widen "integer" -> "number"`,
+		`status: enum ["open","closed"] -> ["open","closed","pending"]`,
+		`note: nullable "string" -> ["string","null"]`,
+		`code: widen "integer" -> ["integer","string"]`,
+		`g: add column {"title":"g","type":"boolean"}`,
+	}
+	got := make([]string, len(ref.Changes))
+	for i, c := range ref.Changes {
+		got[i] = c.String()
+	}
+	if !reflect.DeepEqual(expect, got) {
+		t.Errorf("changes mismatch.\nexpected: %q\ngot:      %q", expect, got)
+	}
+
+	// the refined schema accepts the body
+	cols := ref.Schema["items"].(map[string]interface{})["items"].([]interface{})
+	if len(cols) != 7 {
+		t.Errorf("expected 7 columns, got: %d", len(cols))
+	}
+
+	// refining again with the refined schema proposes nothing
+	r, _ = dsio.NewJSONReader(&dataset.Structure{Format: "json", Schema: ref.Schema}, bytes.NewReader(body))
+	again, err := RefineSchema(&dataset.Structure{Schema: ref.Schema}, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Changed() {
+		t.Errorf("expected refined schema to need no changes, got: %v", again.Changes)
+	}
+}
+
+func TestSchemaLearnerObjects(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"city": map[string]interface{}{"type": "string"},
+				"pop":  map[string]interface{}{"type": "integer"},
+			},
+		},
+	}
+	l := NewSchemaLearner(schema, func(cfg *RefineConfig) {
+		cfg.EnumLimit = 2
+	})
+	// entries from two versions
+	for _, v := range []map[string]interface{}{
+		{"city": "a", "pop": 1},
+		{"city": "b", "pop": 2, "region": "north"},
+	} {
+		l.Observe(dsio.Entry{Value: v})
+	}
+	l.Observe(dsio.Entry{Value: map[string]interface{}{"city": "c", "pop": 3.25, "region": nil}})
+	l.Observe(dsio.Entry{Value: []interface{}{"ignored"}})
+
+	ref := l.Refinement()
+	if ref.Entries != 3 {
+		t.Errorf("expected 3 entries observed, got: %d", ref.Entries)
+	}
+	expect := []string{
+		`pop: widen "integer" -> "number"`,
+		`region: add column {"enum":["north",null],"type":["null","string"]}`,
+	}
+	got := make([]string, len(ref.Changes))
+	for i, c := range ref.Changes {
+		got[i] = c.String()
+	}
+	if !reflect.DeepEqual(expect, got) {
+		t.Errorf("changes mismatch.\nexpected: %q\ngot:      %q", expect, got)
+	}
+	props := ref.Schema["items"].(map[string]interface{})["properties"].(map[string]interface{})
+	if props["region"] == nil {
+		t.Error("expected region property to be added")
+	}
+	if props["city"].(map[string]interface{})["enum"] != nil {
+		t.Error("expected city not to get an enum with more distinct values than the limit")
+	}
+}
+
+func TestSchemaLearnerGeneric(t *testing.T) {
+	l := NewSchemaLearner(dataset.BaseSchemaArray)
+	l.Observe(dsio.Entry{Value: []interface{}{1, "a"}})
+	ref := l.Refinement()
+	if ref.Changed() {
+		t.Errorf("expected no changes to a schema without columns, got: %v", ref.Changes)
+	}
+	if !reflect.DeepEqual(ref.Schema, dataset.BaseSchemaArray) {
+		t.Errorf("expected schema to be unchanged, got: %v", ref.Schema)
+	}
+
+	if _, err := RefineSchema(&dataset.Structure{}, nil); err == nil {
+		t.Error("expected error refining a structure without a schema")
+	}
+}
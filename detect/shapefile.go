@@ -0,0 +1,20 @@
+package detect
+
+import (
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// ShapefileSchema determines a schema for shapefile entries from the
+// attribute columns of the shapefile's .dbf file. Only file headers are decoded
+func ShapefileSchema(r *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	st := &dataset.Structure{Format: dataset.ShapefileDataFormat.String(), Schema: dataset.BaseSchemaArray}
+	sr, err := dsio.NewShapefileReader(st, data)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, 0, err
+	}
+	return sr.Schema(), int(sr.BytesProcessed()), nil
+}
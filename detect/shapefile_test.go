@@ -0,0 +1,48 @@
+package detect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestShapefileSchema(t *testing.T) {
+	// a bare .shp file header, with no records & no attribute table
+	shp := make([]byte, 100)
+	binary.BigEndian.PutUint32(shp, 9994)
+	binary.BigEndian.PutUint32(shp[24:], 50)
+	binary.LittleEndian.PutUint32(shp[28:], 1000)
+
+	st := &dataset.Structure{Format: "shapefile"}
+	sch, n, err := Schema(st, bytes.NewReader(shp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(shp) {
+		t.Errorf("expected %d bytes read, got: %d", len(shp), n)
+	}
+	expect := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"geometry": map[string]interface{}{"type": []interface{}{"null", "object"}},
+				"properties": map[string]interface{}{
+					"type":       []interface{}{"null", "object"},
+					"properties": map[string]interface{}{},
+				},
+			},
+			"required": []interface{}{"geometry", "properties"},
+		},
+	}
+	if !reflect.DeepEqual(sch, expect) {
+		t.Errorf("schema mismatch.\nexpected: %#v\ngot:      %#v", expect, sch)
+	}
+
+	if _, _, err := ShapefileSchema(st, bytes.NewReader([]byte("nope"))); err == nil {
+		t.Error("expected error for invalid shapefile")
+	}
+}
@@ -0,0 +1,49 @@
+package detect
+
+import (
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// TSVSchema determines the field names and types of an io.Reader of
+// TSV-formatted data, returning a json schema. Fields are read with the
+// structure's escape style, which defaults to backslash escapes
+func TSVSchema(resource *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	opts, err := dataset.NewTSVOptions(resource.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, 0, err
+	}
+	opt := map[string]interface{}{}
+	// records are read as strings, with any number of fields
+	readOpt := map[string]interface{}{"variadicFields": true}
+	if opts.Escape != "" {
+		opt["escape"] = opts.Escape
+		readOpt["escape"] = opts.Escape
+	}
+	resource.FormatConfig = opt
+
+	tr := dsio.NewTrackedReader(data)
+	st := &dataset.Structure{Format: dataset.TSVDataFormat.String(), FormatConfig: readOpt}
+	r, err := dsio.NewTSVReader(st, tr)
+	if err != nil {
+		return nil, 0, err
+	}
+	read := func() ([]string, error) {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			return nil, err
+		}
+		vals := ent.Value.([]interface{})
+		rec := make([]string, len(vals))
+		for i, v := range vals {
+			rec[i] = v.(string)
+		}
+		return rec, nil
+	}
+
+	sch, err := recordsSchema("tsv", read, opt)
+	return sch, tr.BytesRead(), err
+}
@@ -0,0 +1,46 @@
+package detect
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestTSVSchema(t *testing.T) {
+	data := "name\tcount\tnote\na\t1\tx\\ty\nb\t2\tz\nc\t3\n"
+	st := &dataset.Structure{Format: "tsv"}
+	got, n, err := Schema(st, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) {
+		t.Errorf("expected %d bytes read, got: %d", len(data), n)
+	}
+
+	expect := `{"items":{"items":[{"title":"name","type":"string"},{"title":"count","type":"integer"},{"title":"note","type":"string"}],"type":"array"},"type":"array"}`
+	data2, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data2) != expect {
+		t.Errorf("schema mismatch. expected:\n%s\ngot:\n%s", expect, string(data2))
+	}
+	if st.FormatConfig["headerRow"] != true || st.FormatConfig["variadicFields"] != true {
+		t.Errorf("format config mismatch, got: %v", st.FormatConfig)
+	}
+
+	st = &dataset.Structure{Format: "tsv", FormatConfig: map[string]interface{}{"escape": "quote"}}
+	if _, _, err := TSVSchema(st, strings.NewReader("\"a\tb\"\t1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if st.FormatConfig["escape"] != "quote" {
+		t.Errorf("expected escape to be kept, got: %v", st.FormatConfig)
+	}
+
+	st = &dataset.Structure{Format: "tsv", FormatConfig: map[string]interface{}{"escape": "nope"}}
+	if _, _, err := TSVSchema(st, strings.NewReader("")); err == nil {
+		t.Error("expected an invalid escape to error")
+	}
+}
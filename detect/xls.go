@@ -0,0 +1,13 @@
+package detect
+
+import (
+	"io"
+
+	"github.com/qri-io/dataset"
+)
+
+// XLSSchema determines any schema information for a legacy excel spreadsheet
+// TODO: currently unimplemented, like XLSXSchema
+func XLSSchema(r *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	return dataset.BaseSchemaArray, 0, nil
+}
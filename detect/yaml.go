@@ -0,0 +1,33 @@
+package detect
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"gopkg.in/yaml.v2"
+)
+
+// YAMLSchema determines the top-level type of an io.Reader of YAML-formatted
+// data, returning a generic array schema for sequences & object schema for
+// mappings
+func YAMLSchema(resource *dataset.Structure, data io.Reader) (schema map[string]interface{}, n int, err error) {
+	tr := dsio.NewTrackedReader(data)
+	var doc interface{}
+	if err = yaml.NewDecoder(tr).Decode(&doc); err != nil {
+		log.Debugf(err.Error())
+		return nil, tr.BytesRead(), fmt.Errorf("invalid yaml data")
+	}
+
+	switch doc.(type) {
+	case []interface{}:
+		return dataset.BaseSchemaArray, tr.BytesRead(), nil
+	case map[interface{}]interface{}, map[string]interface{}:
+		return dataset.BaseSchemaObject, tr.BytesRead(), nil
+	default:
+		err = fmt.Errorf("invalid top-level type for YAML data. yaml datasets must be either a sequence or mapping")
+		log.Debugf(err.Error())
+		return nil, tr.BytesRead(), err
+	}
+}
@@ -0,0 +1,33 @@
+package detect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestYAMLSchema(t *testing.T) {
+	cases := []struct {
+		data   string
+		expect map[string]interface{}
+		err    string
+	}{
+		{`["a", "b"]`, dataset.BaseSchemaArray, ""},
+		{`{"a": 1}`, dataset.BaseSchemaObject, ""},
+		{`"a"`, nil, "invalid top-level type for YAML data. yaml datasets must be either a sequence or mapping"},
+		{``, nil, "invalid yaml data"},
+	}
+
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "yaml"}
+		got, _, err := Schema(st, strings.NewReader(c.data))
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if c.expect != nil && got["type"] != c.expect["type"] {
+			t.Errorf("case %d schema mismatch. expected: %v, got: %v", i, c.expect, got)
+		}
+	}
+}
@@ -1,61 +1,236 @@
 package dsfs
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 
 	"github.com/qri-io/qfs/cafs"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/dslog"
 	"github.com/qri-io/qfs"
 )
 
+type bodyIndexKey struct{}
+
+// WithBodyIndex gives a context that has WriteDatasetContext &
+// CreateDatasetContext save an index next to bodies in formats that support
+// checkpoints, with interval entries between checkpoints. LoadRows uses
+// indexes to start reading near the requested offset
+func WithBodyIndex(ctx context.Context, interval int) context.Context {
+	return context.WithValue(ctx, bodyIndexKey{}, interval)
+}
+
+// bodyIndexInterval reads the interval set by WithBodyIndex, zero when
+// bodies aren't indexed
+func bodyIndexInterval(ctx context.Context) int {
+	interval, _ := ctx.Value(bodyIndexKey{}).(int)
+	return interval
+}
+
 // LoadBody loads the data this dataset points to from the store
 func LoadBody(store cafs.Filestore, ds *dataset.Dataset) (qfs.File, error) {
-	return store.Get(ds.BodyPath)
+	return LoadBodyContext(context.Background(), store, ds)
 }
 
-// LoadRows loads a slice of raw bytes inside a limit/offset row range
-func LoadRows(store cafs.Filestore, ds *dataset.Dataset, limit, offset int) ([]byte, error) {
+// LoadBodyContext is LoadBody, checking the body as it's read if ctx is set
+// WithVerifiedBodies
+func LoadBodyContext(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset) (qfs.File, error) {
+	file, err := getFile(store, ds.BodyPath)
+	if err != nil || !verifyBodies(ctx) {
+		return file, err
+	}
+	return VerifyBody(file, ds.Structure), nil
+}
 
-	datafile, err := LoadBody(store, ds)
+// LoadNamedBody loads a named body of a dataset from the store
+func LoadNamedBody(store cafs.Filestore, ds *dataset.Dataset, name string) (qfs.File, error) {
+	return LoadNamedBodyContext(context.Background(), store, ds, name)
+}
+
+// LoadNamedBodyContext is LoadNamedBody, checking the body as it's read if
+// ctx is set WithVerifiedBodies
+func LoadNamedBodyContext(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset, name string) (qfs.File, error) {
+	nb, ok := ds.Bodies[name]
+	if !ok || nb == nil {
+		return nil, fmt.Errorf("dataset has no body named %s", name)
+	}
+	if nb.Path == "" {
+		return nil, fmt.Errorf("body %s has no path", name)
+	}
+	file, err := getFile(store, nb.Path)
+	if err != nil || !verifyBodies(ctx) {
+		return file, err
+	}
+	return VerifyBody(file, nb.Structure), nil
+}
+
+// LoadBodyIndex loads the body index of a dataset from the store
+func LoadBodyIndex(store cafs.Filestore, ds *dataset.Dataset) (*dsio.BodyIndex, error) {
+	if ds.BodyIndexPath == "" {
+		return nil, fmt.Errorf("dataset has no body index")
+	}
+	data, err := fileBytes(getFile(store, ds.BodyIndexPath))
 	if err != nil {
 		log.Debug(err.Error())
-		return nil, fmt.Errorf("error loading dataset data: %s", err.Error())
+		return nil, fmt.Errorf("error loading body index: %w", err)
 	}
+	idx := &dsio.BodyIndex{}
+	if err := json.Unmarshal(data, idx); err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error decoding body index: %w", err)
+	}
+	return idx, nil
+}
 
-	added := 0
-	buf, err := dsio.NewEntryBuffer(ds.Structure)
+// LoadBodyPartitions loads the partition manifest of a dataset from the store
+func LoadBodyPartitions(store cafs.Filestore, ds *dataset.Dataset) (*dsio.PartitionManifest, error) {
+	if ds.BodyPartitionsPath == "" {
+		return nil, fmt.Errorf("dataset body isn't partitioned")
+	}
+	data, err := fileBytes(getFile(store, ds.BodyPartitionsPath))
 	if err != nil {
 		log.Debug(err.Error())
-		return nil, fmt.Errorf("error loading dataset data: %s", err.Error())
+		return nil, fmt.Errorf("error loading partition manifest: %w", err)
+	}
+	m := &dsio.PartitionManifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error decoding partition manifest: %w", err)
+	}
+	return m, nil
+}
+
+// LoadPartitions reads the entries of partitions with keys between from & to
+// inclusive, only loading the shards in range. an empty bound leaves that end
+// of the range open. Entries are read in key order, callers must close the
+// returned reader
+func LoadPartitions(store cafs.Filestore, ds *dataset.Dataset, from, to string) (dsio.EntryReader, error) {
+	m, err := LoadBodyPartitions(store, ds)
+	if err != nil {
+		return nil, err
+	}
+	if ds.Structure == nil {
+		return nil, fmt.Errorf("dataset has no structure")
+	}
+	open := func(shard *dsio.PartitionShard) (io.Reader, error) {
+		return getFile(store, shard.Path)
+	}
+	return dsio.NewPartitionReader(ds.Structure, m.Range(from, to), open), nil
+}
+
+// seekBody positions a body file at the closest indexed entry at or before
+// offset, giving a reader for the remaining body & the number of entries to
+// skip. Chunk checksums aren't checked when starting part way into a body
+func seekBody(store cafs.Filestore, ds *dataset.Dataset, file qfs.File, offset int) (dsio.EntryReader, int, error) {
+	if ds.BodyIndexPath == "" || offset == 0 {
+		rr, err := dsio.NewEntryReader(ds.Structure, file)
+		return rr, offset, err
 	}
 
-	rr, err := dsio.NewEntryReader(ds.Structure, datafile)
+	idx, err := LoadBodyIndex(store, ds)
+	if err != nil {
+		return nil, 0, err
+	}
+	token, skip := idx.Seek(offset)
+	if token == "" {
+		rr, err := dsio.NewEntryReader(ds.Structure, file)
+		return rr, skip, err
+	}
+	off, err := idx.Offset(token)
+	if err != nil {
+		return nil, 0, err
+	}
+	if sk, ok := file.(io.Seeker); ok {
+		_, err = sk.Seek(off, io.SeekStart)
+	} else {
+		_, err = io.CopyN(ioutil.Discard, file, off)
+	}
 	if err != nil {
 		log.Debug(err.Error())
-		return nil, fmt.Errorf("error loading dataset data: %s", err.Error())
+		return nil, 0, fmt.Errorf("error seeking body: %w", err)
 	}
-	err = dsio.EachEntry(rr, func(i int, ent dsio.Entry, err error) error {
-		if err != nil {
-			log.Debugf("error reading entry: %s", err.Error())
-			return err
-		}
+	rr, err := dsio.ResumeEntryReader(ds.Structure, file, token)
+	return rr, skip, err
+}
 
-		if i < offset {
-			return nil
-		} else if limit > 0 && added == limit {
-			return io.EOF
-		}
-		buf.WriteEntry(ent)
-		added++
-		return nil
-	})
+// LoadRows loads a slice of raw bytes inside a limit/offset row range. Use
+// LoadRowsReader to stream large ranges instead of holding them in memory
+func LoadRows(store cafs.Filestore, ds *dataset.Dataset, limit, offset int) ([]byte, error) {
+	return LoadRowsContext(context.Background(), store, ds, limit, offset)
+}
 
+// LoadRowsContext is LoadRows, checking the body as it's read if ctx is set
+// WithVerifiedBodies
+func LoadRowsContext(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset, limit, offset int) ([]byte, error) {
+	rc, err := LoadRowsReaderContext(ctx, store, ds, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("error reading dataset data: %s", err.Error())
+		return nil, err
 	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+// LoadRowsReader streams raw bytes inside a limit/offset row range, encoded
+// in the dataset's structure format. Entries are read from the store as the
+// returned reader is consumed, callers must close the reader
+func LoadRowsReader(store cafs.Filestore, ds *dataset.Dataset, limit, offset int) (io.ReadCloser, error) {
+	return LoadRowsReaderContext(context.Background(), store, ds, limit, offset)
+}
+
+// LoadRowsReaderContext is LoadRowsReader, checking the body as it's read if
+// ctx is set WithVerifiedBodies
+func LoadRowsReaderContext(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset, limit, offset int) (io.ReadCloser, error) {
+	datafile, err := LoadBodyContext(ctx, store, ds)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error loading dataset data: %w", err)
+	}
+
+	rr, skip, err := seekBody(store, ds, datafile, offset)
+	if err != nil {
+		datafile.Close()
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error loading dataset data: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	w, err := dsio.NewEntryWriter(ds.Structure, pw)
+	if err != nil {
+		rr.Close()
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error loading dataset data: %w", err)
+	}
+
+	go func() {
+		defer rr.Close()
+		added := 0
+		err := dsio.EachEntry(rr, func(i int, ent dsio.Entry, err error) error {
+			if err != nil {
+				log.Debugw("error reading entry: "+err.Error(), dslog.F("path", ds.BodyPath), dslog.F("index", i))
+				return err
+			}
+
+			if i < skip {
+				return nil
+			} else if limit > 0 && added == limit {
+				return io.EOF
+			}
+			added++
+			return w.WriteEntry(ent)
+		})
+		if err != nil {
+			pw.CloseWithError(fmt.Errorf("error reading dataset data: %w", err))
+			return
+		}
+		// closing the writer closes the pipe, ending the stream
+		if err := w.Close(); err != nil {
+			pw.CloseWithError(err)
+		}
+	}()
 
-	err = buf.Close()
-	return buf.Bytes(), err
+	return pr, nil
 }
@@ -2,8 +2,17 @@ package dsfs
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/dstest"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
 )
 
 func TestLoadBody(t *testing.T) {
@@ -81,3 +90,210 @@ chatham,35000,65.25,true
 		}
 	}
 }
+
+func TestLoadRowsIndexed(t *testing.T) {
+	datasets, store, err := makeFilestore()
+	if err != nil {
+		t.Fatalf("error creating test filestore: %s", err.Error())
+	}
+	ds, err := LoadDataset(store, datasets["cities"])
+	if err != nil {
+		t.Fatalf("error loading dataset: %s", err.Error())
+	}
+
+	indexed, store2, err := makeFilestoreContext(WithBodyIndex(context.Background(), 2))
+	if err != nil {
+		t.Fatalf("error creating test filestore: %s", err.Error())
+	}
+	ids, err := LoadDataset(store2, indexed["cities"])
+	if err != nil {
+		t.Fatalf("error loading dataset: %s", err.Error())
+	}
+	if ids.BodyIndexPath == "" {
+		t.Fatal("expected dataset to have a body index")
+	}
+	idx, err := LoadBodyIndex(store2, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.Interval != 2 || len(idx.Checkpoints) == 0 {
+		t.Errorf("unexpected index: %v", idx)
+	}
+
+	for offset := 0; offset < 6; offset++ {
+		expect, err := LoadRows(store, ds, 2, offset)
+		if err != nil {
+			t.Fatalf("offset %d unexpected error: %s", offset, err)
+		}
+		got, err := LoadRows(store2, ids, 2, offset)
+		if err != nil {
+			t.Fatalf("offset %d unexpected error: %s", offset, err)
+		}
+		if !bytes.Equal(expect, got) {
+			t.Errorf("offset %d mismatch. expected: %s, got: %s", offset, expect, got)
+		}
+	}
+}
+
+func TestLoadRowsReader(t *testing.T) {
+	datasets, store, err := makeFilestore()
+	if err != nil {
+		t.Fatalf("error creating test filestore: %s", err.Error())
+	}
+	ds, err := LoadDataset(store, datasets["cities"])
+	if err != nil {
+		t.Fatalf("error loading dataset: %s", err.Error())
+	}
+
+	rc, err := LoadRowsReader(store, ds, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expect := "city,pop,avg_age,in_usa\nchicago,300000,44.4,true\nchatham,35000,65.25,true\n"
+	if string(data) != expect {
+		t.Errorf("data mismatch. expected: %s, got: %s", expect, string(data))
+	}
+
+	// closing before reading everything mustn't block the loader
+	rc, err = LoadRowsReader(store, ds, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc.Close()
+}
+
+func TestLoadNamedBody(t *testing.T) {
+	store := cafs.NewMapstore()
+	tc, err := dstest.NewTestCaseFromDir("testdata/cities")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := tc.Input
+	ds.SetBodyFile(tc.BodyFile())
+	errata := &dataset.NamedBody{Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}}
+	errata.SetBodyFile(qfs.NewMemfileBytes("errata.json", []byte(`[["toronto","pop",40000000,2800000]]`)))
+	ds.Bodies = map[string]*dataset.NamedBody{"errata": errata}
+
+	path, err := WriteDataset(store, ds, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadDataset(store, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nb := got.Bodies["errata"]
+	if nb == nil || nb.Path == "" {
+		t.Fatalf("expected loaded dataset to have an errata body path, got: %v", got.Bodies)
+	}
+	if nb.Structure == nil || nb.Structure.Format != "json" {
+		t.Errorf("expected errata structure to be saved, got: %v", nb.Structure)
+	}
+
+	data, err := fileBytes(LoadNamedBody(store, got, "errata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[["toronto","pop",40000000,2800000]]` {
+		t.Errorf("errata body mismatch, got: %s", data)
+	}
+
+	if _, err := LoadNamedBody(store, got, "codebook"); err == nil || err.Error() != "dataset has no body named codebook" {
+		t.Errorf("expected missing body error, got: %v", err)
+	}
+
+	bad := &dataset.NamedBody{}
+	bad.SetBodyFile(qfs.NewMemfileBytes("bad", []byte("[]")))
+	ds = &dataset.Dataset{Meta: &dataset.Meta{Title: "bad"}, Bodies: map[string]*dataset.NamedBody{"../up": bad}}
+	if _, err := WriteDataset(store, ds, true); err == nil {
+		t.Error("expected error writing invalid body name")
+	}
+}
+
+func TestLoadPartitions(t *testing.T) {
+	store := cafs.NewMapstore()
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "day", "type": "string"},
+					map[string]interface{}{"title": "reading", "type": "integer"},
+				},
+			},
+		},
+		Partition: &dataset.Partition{Column: "day", Granularity: dataset.PartitionMonth},
+	}
+	body := "day,reading\n2020-01-05,1\n2020-02-01,2\n2020-01-20,3\n2020-03-09T10:00:00Z,4\n"
+	ds := &dataset.Dataset{Meta: &dataset.Meta{Title: "readings"}, Structure: st}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte(body)))
+
+	path, err := WriteDataset(store, ds, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadDataset(store, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.BodyPartitionsPath == "" {
+		t.Fatal("expected dataset to have a partition manifest")
+	}
+	if got.Structure.Partition == nil || got.Structure.Partition.Column != "day" {
+		t.Errorf("expected structure partition to be saved, got: %v", got.Structure.Partition)
+	}
+	data, err := fileBytes(LoadBody(store, got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != body {
+		t.Errorf("expected the whole body to be saved, got: %s", data)
+	}
+
+	m, err := LoadBodyPartitions(store, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{}
+	for _, s := range m.Shards {
+		keys = append(keys, s.Key)
+		if s.Path == "" {
+			t.Errorf("shard %s has no path", s.Key)
+		}
+	}
+	if strings.Join(keys, ",") != "2020-01,2020-02,2020-03" {
+		t.Errorf("shard keys mismatch, got: %v", keys)
+	}
+
+	r, err := LoadPartitions(store, got, "2020-01", "2020-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	readings := []interface{}{}
+	err = dsio.EachEntry(r, func(i int, ent dsio.Entry, err error) error {
+		readings = append(readings, ent.Value.([]interface{})[1])
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]interface{}{int64(1), int64(3), int64(2)}, readings) {
+		t.Errorf("readings mismatch, got: %#v", readings)
+	}
+
+	if _, err := LoadBodyPartitions(store, &dataset.Dataset{}); err == nil {
+		t.Error("expected error loading partitions of an unpartitioned dataset")
+	}
+}
@@ -0,0 +1,286 @@
+package dsfs
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/multiformats/go-multihash"
+	"github.com/qri-io/dataset/dslog"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// BundleVersion is the version of the bundle format ExportBundle writes
+const BundleVersion = 1
+
+// maxBundleHeaderSize caps the length of bundle, block & footer headers
+const maxBundleHeaderSize = 1 << 20
+
+// A bundle is a self-contained copy of a dataset version's files that can be
+// moved between stores that can't reach each other. It's a sequence of
+// frames, each a uvarint length followed by that many bytes:
+//
+//	bundle header (json)
+//	block header (json), block data, block hash (json)   ... one per file
+//	empty frame
+//
+// blocks are listed in manifest order, dataset document first. Block hashes
+// follow block data so blocks can be written as they're read from the store.
+// A bundle is identified by it's dataset path, which importing recomputes
+
+// bundleHeader opens a bundle
+type bundleHeader struct {
+	Version int    `json:"version"`
+	Path    string `json:"path"`
+}
+
+// BundleBlock describes a file in a bundle
+type BundleBlock struct {
+	ManifestFile
+}
+
+// blockTrailer follows a block's data
+type blockTrailer struct {
+	// Hash is the base58 sha2-256 multihash of the block's data
+	Hash string `json:"hash"`
+}
+
+// ExportBundle writes the dataset version at path to w as a bundle, returning
+// the path of the exported dataset. Files are streamed from the store. The
+// path should travel separately from the bundle so ImportBundle can check the
+// bundle is the dataset it's expected to be
+func ExportBundle(store cafs.Filestore, path string, w io.Writer) (string, error) {
+	m, err := Manifest(store, path)
+	if err != nil {
+		return "", err
+	}
+	bw := bufio.NewWriter(w)
+	if err := writeJSONFrame(bw, bundleHeader{Version: BundleVersion, Path: m.Path}); err != nil {
+		return "", fmt.Errorf("error writing bundle header: %w", err)
+	}
+
+	for _, f := range m.Files {
+		if err := writeJSONFrame(bw, &BundleBlock{ManifestFile: *f}); err != nil {
+			return "", fmt.Errorf("error writing block header: %w", err)
+		}
+		hash, err := writeBlock(bw, store, f)
+		if err != nil {
+			return "", err
+		}
+		if err := writeJSONFrame(bw, blockTrailer{Hash: hash}); err != nil {
+			return "", fmt.Errorf("error writing block hash: %w", err)
+		}
+	}
+
+	if err := writeFrame(bw, nil); err != nil {
+		return "", err
+	}
+	if err := bw.Flush(); err != nil {
+		return "", err
+	}
+	return m.Path, nil
+}
+
+// writeBlock streams a file's data frame to w, returning the hash of it's
+// contents
+func writeBlock(w io.Writer, store cafs.Filestore, f *ManifestFile) (string, error) {
+	file, err := getFile(store, f.Path)
+	if err != nil {
+		log.Debugw(err.Error(), dslog.F("path", f.Path))
+		return "", fmt.Errorf("error reading %s file: %w", f.Component, err)
+	}
+	defer file.Close()
+
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(f.Size))
+	if _, err := w.Write(buf[:n]); err != nil {
+		return "", fmt.Errorf("error writing %s block: %w", f.Component, err)
+	}
+	h := sha256.New()
+	// files must be the size the manifest lists, a short copy errors
+	if _, err := io.CopyN(io.MultiWriter(w, h), file, f.Size); err != nil {
+		log.Debugw(err.Error(), dslog.F("path", f.Path))
+		return "", fmt.Errorf("error writing %s block: %w", f.Component, err)
+	}
+	return hashString(h.Sum(nil))
+}
+
+// ImportBundle reads a bundle from r into store, returning the path of the
+// imported dataset. Every block is checked against it's hash before anything
+// is written to the store, and the imported dataset's path must be the path
+// the bundle was exported from, which fails for bundles altered in transit.
+// If path is non-empty the bundle must have been exported from it. Blocks are
+// held in a temporary file while the bundle is checked
+func ImportBundle(store cafs.Filestore, r io.Reader, path string, pin bool) (string, error) {
+	br := bufio.NewReader(r)
+	header := bundleHeader{}
+	if err := readJSONFrame(br, &header); err != nil {
+		return "", fmt.Errorf("error reading bundle header: %w", err)
+	}
+	if header.Version != BundleVersion {
+		return "", fmt.Errorf("unsupported bundle version: %d", header.Version)
+	}
+	expect, err := normalizePath(header.Path)
+	if err != nil {
+		return "", fmt.Errorf("error reading bundle header: %w", err)
+	}
+	if path != "" {
+		if path, err = normalizePath(path); err != nil {
+			return "", err
+		}
+		if path != expect {
+			return "", fmt.Errorf("bundle path mismatch. expected: %s, got: %s", path, expect)
+		}
+	}
+
+	spool, err := ioutil.TempFile("", "dataset-bundle")
+	if err != nil {
+		return "", fmt.Errorf("error creating bundle spool file: %w", err)
+	}
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+
+	var (
+		m       = &DatasetManifest{Path: header.Path}
+		offsets = map[string]int64{}
+		sizes   = map[string]int64{}
+		offset  int64
+	)
+	for {
+		frame, err := readFrame(br)
+		if err != nil {
+			return "", fmt.Errorf("error reading block header: %w", err)
+		}
+		if len(frame) == 0 {
+			break
+		}
+		b := &BundleBlock{}
+		if err := json.Unmarshal(frame, b); err != nil {
+			return "", fmt.Errorf("error reading block header: %w", err)
+		}
+		if len(m.Files) == 0 && b.Component != ManifestDataset {
+			return "", fmt.Errorf("bundle doesn't start with a dataset block")
+		}
+		if err := copyBlock(spool, br, b); err != nil {
+			return "", err
+		}
+		f := b.ManifestFile
+		m.Files = append(m.Files, &f)
+		m.Size += f.Size
+		offsets[f.Path] = offset
+		sizes[f.Path] = f.Size
+		offset += f.Size
+	}
+	if len(m.Files) == 0 {
+		return "", fmt.Errorf("bundle has no blocks")
+	}
+
+	get := func(p string) (qfs.File, error) {
+		off, ok := offsets[p]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		return qfs.NewMemfileReader(filepath.Base(p), io.NewSectionReader(spool, off, sizes[p])), nil
+	}
+	c := &copier{get: get, dst: store, pin: pin, paths: map[string]string{}, res: &CopyResult{}}
+	got, err := c.copyVersion(m, false)
+	if err != nil {
+		return "", err
+	}
+	if got, err = normalizePath(got); err != nil {
+		return "", err
+	}
+	if got != expect {
+		return "", fmt.Errorf("imported dataset path mismatch. expected: %s, got: %s", expect, got)
+	}
+	return got, nil
+}
+
+// copyBlock copies a block's data frame to w, checking it's length & the
+// hash that follows it
+func copyBlock(w io.Writer, r *bufio.Reader, b *BundleBlock) error {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("error reading %s block: %w", b.Component, err)
+	}
+	if int64(size) != b.Size {
+		return fmt.Errorf("%s block size mismatch. expected: %d, got: %d", b.Component, b.Size, size)
+	}
+	h := sha256.New()
+	if _, err := io.CopyN(io.MultiWriter(w, h), r, int64(size)); err != nil {
+		return fmt.Errorf("error reading %s block: %w", b.Component, err)
+	}
+	got, err := hashString(h.Sum(nil))
+	if err != nil {
+		return err
+	}
+	trailer := blockTrailer{}
+	if err := readJSONFrame(r, &trailer); err != nil {
+		return fmt.Errorf("error reading %s block hash: %w", b.Component, err)
+	}
+	if got != trailer.Hash {
+		return fmt.Errorf("%s block hash mismatch. expected: %s, got: %s", b.Component, trailer.Hash, got)
+	}
+	return nil
+}
+
+// hashString gives the base58 multihash of a sha2-256 sum
+func hashString(sum []byte) (string, error) {
+	mh, err := multihash.Encode(sum, multihash.SHA2_256)
+	if err != nil {
+		return "", err
+	}
+	return multihash.Multihash(mh).B58String(), nil
+}
+
+func writeFrame(w io.Writer, data []byte) error {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(data)))
+	if _, err := w.Write(buf[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func writeJSONFrame(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeFrame(w, data)
+}
+
+// readFrame reads a header frame, which can't be longer than
+// maxBundleHeaderSize
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxBundleHeaderSize {
+		return nil, fmt.Errorf("header is too long: %d bytes", size)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func readJSONFrame(r *bufio.Reader, v interface{}) error {
+	data, err := readFrame(r)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
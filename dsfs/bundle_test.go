@@ -0,0 +1,92 @@
+package dsfs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+func TestBundle(t *testing.T) {
+	src := cafs.NewMapstore()
+	ds := &dataset.Dataset{
+		Meta: &dataset.Meta{Title: "readings"},
+		Structure: &dataset.Structure{
+			Format:       "csv",
+			FormatConfig: map[string]interface{}{"headerRow": true},
+			Schema:       dataset.BaseSchemaArray,
+		},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte("a,b\n1,2\n")))
+	path, err := WriteDataset(src, ds, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	exported, err := ExportBundle(src, path, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := buf.Bytes()
+
+	dst := cafs.NewMapstore()
+	got, err := ImportBundle(dst, bytes.NewReader(bundle), exported, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != exported {
+		t.Errorf("path mismatch. expected: %s, got: %s", exported, got)
+	}
+	imported, err := LoadDataset(dst, got)
+	if err != nil {
+		t.Fatalf("loading imported dataset: %s", err)
+	}
+	data, err := fileBytes(LoadBody(dst, imported))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "a,b\n1,2\n" {
+		t.Errorf("body mismatch, got: %q", data)
+	}
+	if imported.Meta == nil || imported.Meta.Title != "readings" {
+		t.Errorf("expected meta to be imported, got: %v", imported.Meta)
+	}
+
+	// a bundle of another dataset, with blocks that match their hashes,
+	// claiming to be the exported dataset
+	other := &dataset.Dataset{Meta: &dataset.Meta{Title: "other"}, Structure: ds.Structure}
+	other.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte("a,b\n9,9\n")))
+	otherPath, err := WriteDataset(src, other, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf = &bytes.Buffer{}
+	if _, err := ExportBundle(src, otherPath, buf); err != nil {
+		t.Fatal(err)
+	}
+	forged := bytes.Replace(buf.Bytes(), []byte(otherPath), []byte(exported), 1)
+
+	cases := []struct {
+		description string
+		bundle      []byte
+		path        string
+		err         string
+	}{
+		{"wrong path", bundle, otherPath, "bundle path mismatch"},
+		{"altered block", bytes.Replace(bundle, []byte("1,2"), []byte("9,9"), 1), "", "block hash mismatch"},
+		{"forged header", forged, "", "imported dataset path mismatch"},
+		{"forged header with path", forged, exported, "imported dataset path mismatch"},
+		{"truncated", bundle[:len(bundle)/2], "", "error reading"},
+		{"empty", []byte{}, "", "error reading bundle header"},
+	}
+	for _, c := range cases {
+		_, err := ImportBundle(cafs.NewMapstore(), bytes.NewReader(c.bundle), c.path, false)
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: expected error containing %q, got: %v", c.description, c.err, err)
+		}
+	}
+}
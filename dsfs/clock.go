@@ -0,0 +1,65 @@
+package dsfs
+
+import (
+	"context"
+	"time"
+)
+
+// Clock gives the current time. Times dsfs records MUST be in UTC
+type Clock func() time.Time
+
+// SystemClock reads the system clock in UTC
+func SystemClock() time.Time {
+	return time.Now().UTC()
+}
+
+// FixedClock gives a Clock that's always at t, pinning the timestamps of
+// datasets created with it
+func FixedClock(t time.Time) Clock {
+	t = t.UTC()
+	return func() time.Time { return t }
+}
+
+type (
+	clockKey        struct{}
+	reproducibleKey struct{}
+)
+
+// WithClock gives a context that has CreateDatasetContext read commit
+// timestamps from clock in place of Timestamp
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// WithReproducible gives a context that has CreateDatasetContext create
+// datasets in reproducible mode: values that change every time a dataset is
+// created are zeroed before the dataset is hashed & signed, so the same input
+// always gives the same path. The commit timestamp is the only such value
+func WithReproducible(ctx context.Context) context.Context {
+	return context.WithValue(ctx, reproducibleKey{}, true)
+}
+
+// createOptions configures creating a dataset
+type createOptions struct {
+	now          Clock
+	reproducible bool
+}
+
+// createOptionsFromContext reads create options set by WithClock &
+// WithReproducible, defaulting to Timestamp
+func createOptionsFromContext(ctx context.Context) createOptions {
+	opts := createOptions{now: Timestamp}
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok && clock != nil {
+		opts.now = clock
+	}
+	opts.reproducible, _ = ctx.Value(reproducibleKey{}).(bool)
+	return opts
+}
+
+// timestamp gives the commit timestamp of a dataset being created
+func (o createOptions) timestamp() time.Time {
+	if o.reproducible {
+		return time.Time{}
+	}
+	return o.now().UTC()
+}
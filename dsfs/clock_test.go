@@ -0,0 +1,58 @@
+package dsfs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-crypto"
+	"github.com/qri-io/dataset/dstest"
+	"github.com/qri-io/qfs/cafs"
+)
+
+func TestCreateDatasetClock(t *testing.T) {
+	privKey, err := crypto.UnmarshalPrivateKey(testPk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	create := func(ctx context.Context) string {
+		tc, err := dstest.NewTestCaseFromDir("testdata/cities")
+		if err != nil {
+			t.Fatal(err)
+		}
+		store := cafs.NewMapstore()
+		path, err := CreateDatasetContext(ctx, store, tc.Input, nil, privKey, false, false, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds, err := LoadDataset(store, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ds.Commit.Timestamp.Format(time.RFC3339) + " " + path
+	}
+
+	pinned := time.Date(2010, 6, 1, 12, 0, 0, 0, time.FixedZone("", 3600))
+	ctx := WithClock(context.Background(), FixedClock(pinned))
+	a, b := create(ctx), create(ctx)
+	if a != b {
+		t.Errorf("expected pinned clock to give the same dataset, got: %s & %s", a, b)
+	}
+	if expect := "2010-06-01T11:00:00Z"; a[:len(expect)] != expect {
+		t.Errorf("timestamp mismatch. expected: %s, got: %s", expect, a)
+	}
+
+	prev := Timestamp
+	defer func() { Timestamp = prev }()
+	ctx = WithReproducible(context.Background())
+	Timestamp = FixedClock(time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC))
+	a = create(ctx)
+	Timestamp = FixedClock(time.Date(2002, 2, 2, 0, 0, 0, 0, time.UTC))
+	b = create(ctx)
+	if a != b {
+		t.Errorf("expected reproducible mode to give the same dataset, got: %s & %s", a, b)
+	}
+	if expect := "0001-01-01T00:00:00Z"; a[:len(expect)] != expect {
+		t.Errorf("expected zero timestamp in reproducible mode, got: %s", a)
+	}
+}
@@ -12,23 +12,26 @@ func SaveCommit(store cafs.Filestore, s *dataset.Commit, pin bool) (path string,
 	file, err := JSONFile(PackageFileCommit.String(), s)
 	if err != nil {
 		log.Debug(err.Error())
-		return "", fmt.Errorf("error saving json commit file: %s", err.Error())
+		return "", fmt.Errorf("error saving json commit file: %w", err)
 	}
 	return store.Put(file, pin)
 }
 
 // LoadCommit loads a commit from a given path in a store
 func LoadCommit(store cafs.Filestore, path string) (st *dataset.Commit, err error) {
+	if path, err = normalizePath(path); err != nil {
+		return nil, err
+	}
 	path = PackageFilepath(store, path, PackageFileCommit)
 	return loadCommit(store, path)
 }
 
 // loadCommit assumes the provided path is valid
 func loadCommit(store cafs.Filestore, path string) (st *dataset.Commit, err error) {
-	data, err := fileBytes(store.Get(path))
+	data, err := fileBytes(getFile(store, path))
 	if err != nil {
 		log.Debug(err.Error())
-		return nil, fmt.Errorf("error loading commit file: %s", err.Error())
+		return nil, fmt.Errorf("error loading commit file: %w", err)
 	}
 	return dataset.UnmarshalCommit(data)
 }
@@ -0,0 +1,364 @@
+package dsfs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/dslog"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// CopyOptions configures CopyDataset
+type CopyOptions struct {
+	// Pin files written to the destination
+	Pin bool
+	// History copies every previous version of the dataset as well. Without
+	// it the copied version's previous path refers to the source store
+	History bool
+}
+
+// CopyResult describes a completed CopyDataset
+type CopyResult struct {
+	// Path of the dataset in the destination store
+	Path string
+	// FilesCopied & BytesCopied count files written to the destination,
+	// FilesSkipped counts files the destination already had
+	FilesCopied  int
+	FilesSkipped int
+	BytesCopied  int64
+}
+
+// CopyDataset copies the dataset version at path from src to dst, file by
+// file from the version's manifest. Files dst already has aren't copied, so
+// copying between stores that share versions only moves what's missing.
+// When the stores give files different paths, references between files are
+// rewritten & the copied dataset has a different path
+func CopyDataset(src, dst cafs.Filestore, path string, opts CopyOptions) (*CopyResult, error) {
+	path, err := normalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+	get := func(p string) (qfs.File, error) { return getFile(src, p) }
+	c := &copier{get: get, dst: dst, pin: opts.Pin, paths: map[string]string{}, res: &CopyResult{}}
+
+	versions := []string{path}
+	if opts.History {
+		// walk back to the first version, or the newest one dst already has
+		for p := path; ; {
+			ds, err := LoadDatasetRefs(src, p)
+			if err != nil {
+				return nil, err
+			}
+			if ds.PreviousPath == "" {
+				break
+			}
+			if p, err = normalizePath(ds.PreviousPath); err != nil {
+				return nil, err
+			}
+			has, err := hasPath(dst, PackageFilepath(src, p, PackageFileDataset))
+			if err != nil {
+				return nil, err
+			}
+			if has {
+				break
+			}
+			versions = append(versions, p)
+		}
+	}
+
+	// copy oldest versions first, so previous paths can be rewritten
+	for i := len(versions) - 1; i >= 0; i-- {
+		m, err := Manifest(src, versions[i])
+		if err != nil {
+			return nil, err
+		}
+		if c.res.Path, err = c.copyVersion(m, opts.History); err != nil {
+			return nil, err
+		}
+	}
+	return c.res, nil
+}
+
+// copier copies files to a store, tracking the destination path of every
+// source path it's copied. get reads source files
+type copier struct {
+	get   func(path string) (qfs.File, error)
+	dst   cafs.Filestore
+	pin   bool
+	paths map[string]string
+	res   *CopyResult
+}
+
+// mapped gives the destination path of a source path, which is the path
+// itself if it hasn't been copied
+func (c *copier) mapped(p string) string {
+	if dp, ok := c.paths[p]; ok {
+		return dp
+	}
+	return p
+}
+
+// copyVersion copies the files of a dataset version's manifest, returning
+// it's path in the destination store
+func (c *copier) copyVersion(m *DatasetManifest, history bool) (string, error) {
+	path := m.Path
+	has, err := hasPath(c.dst, m.Files[0].Path)
+	if err != nil {
+		return "", err
+	}
+	if has {
+		c.res.FilesSkipped += len(m.Files)
+		c.paths[path] = path
+		return path, nil
+	}
+
+	// files that reference other files are copied after everything else
+	var partitions, transform, viz *ManifestFile
+	for _, f := range m.Files[1:] {
+		switch f.Component {
+		case ManifestBodyPartitions:
+			partitions = f
+		case ManifestTransform:
+			transform = f
+		case ManifestViz:
+			viz = f
+		default:
+			if err := c.copyFile(f); err != nil {
+				return "", err
+			}
+		}
+	}
+	if partitions != nil {
+		if err := c.copyRewritten(partitions, c.rewritePartitions); err != nil {
+			return "", err
+		}
+	}
+	if transform != nil {
+		if err := c.copyRewritten(transform, c.rewriteTransform); err != nil {
+			return "", err
+		}
+	}
+	if viz != nil {
+		if err := c.copyRewritten(viz, c.rewriteViz); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := fileBytes(c.get(m.Files[0].Path))
+	if err != nil {
+		return "", fmt.Errorf("error reading dataset file: %w", err)
+	}
+	ds, err := dataset.UnmarshalDataset(data)
+	if err != nil {
+		return "", fmt.Errorf("error unmarshaling %s file: %w", PackageFileDataset.String(), err)
+	}
+	// documents without rewritten references are copied as-is, keeping the
+	// dataset's path when the stores hash files alike
+	if c.rewriteDataset(ds, history) {
+		ds.DropTransientValues()
+		if data, err = json.Marshal(ds); err != nil {
+			return "", fmt.Errorf("error marshaling dataset to json: %w", err)
+		}
+	}
+	dp, err := c.addDatasetFile(data)
+	if err != nil {
+		return "", err
+	}
+	c.paths[path] = dp
+	c.res.FilesCopied++
+	c.res.BytesCopied += int64(len(data))
+	return dp, nil
+}
+
+// copyFile copies a file as-is, unless dst already has it
+func (c *copier) copyFile(f *ManifestFile) error {
+	if _, ok := c.paths[f.Path]; ok {
+		// files with the same contents are listed once per component
+		return nil
+	}
+	has, err := hasPath(c.dst, f.Path)
+	if err != nil {
+		return err
+	}
+	if has {
+		c.paths[f.Path] = f.Path
+		c.res.FilesSkipped++
+		return nil
+	}
+
+	file, err := c.get(f.Path)
+	if err != nil {
+		log.Debugw(err.Error(), dslog.F("path", f.Path))
+		return fmt.Errorf("error reading %s file: %w", f.Component, err)
+	}
+	defer file.Close()
+	dp, err := c.dst.Put(file, c.pin)
+	if err != nil {
+		log.Debugw(err.Error(), dslog.F("path", f.Path))
+		return fmt.Errorf("error writing %s file: %w", f.Component, err)
+	}
+	c.paths[f.Path] = dp
+	c.res.FilesCopied++
+	c.res.BytesCopied += f.Size
+	return nil
+}
+
+// copyRewritten copies a file that references other files. rewrite gives
+// the file with references replaced by destination paths, or nil if none of
+// them changed, in which case the file is copied as-is
+func (c *copier) copyRewritten(f *ManifestFile, rewrite func(data []byte) ([]byte, error)) error {
+	data, err := fileBytes(c.get(f.Path))
+	if err != nil {
+		return fmt.Errorf("error reading %s file: %w", f.Component, err)
+	}
+	rewritten, err := rewrite(data)
+	if err != nil {
+		return fmt.Errorf("error rewriting %s file: %w", f.Component, err)
+	}
+	if rewritten == nil {
+		return c.copyFile(f)
+	}
+
+	dp, err := c.dst.Put(qfs.NewMemfileBytes(filepath.Base(f.Path), rewritten), c.pin)
+	if err != nil {
+		return fmt.Errorf("error writing %s file: %w", f.Component, err)
+	}
+	c.paths[f.Path] = dp
+	c.res.FilesCopied++
+	c.res.BytesCopied += int64(len(rewritten))
+	return nil
+}
+
+func (c *copier) rewritePartitions(data []byte) ([]byte, error) {
+	m := &dsio.PartitionManifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	changed := false
+	for _, shard := range m.Shards {
+		if dp := c.mapped(shard.Path); dp != shard.Path {
+			shard.Path = dp
+			changed = true
+		}
+	}
+	if !changed {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+func (c *copier) rewriteTransform(data []byte) ([]byte, error) {
+	t, err := dataset.UnmarshalTransform(data)
+	if err != nil {
+		return nil, err
+	}
+	dp := c.mapped(t.ScriptPath)
+	if dp == t.ScriptPath {
+		return nil, nil
+	}
+	t.ScriptPath = dp
+	return json.Marshal(t)
+}
+
+func (c *copier) rewriteViz(data []byte) ([]byte, error) {
+	vz, err := dataset.UnmarshalViz(data)
+	if err != nil {
+		return nil, err
+	}
+	script, rendered := c.mapped(vz.ScriptPath), c.mapped(vz.RenderedPath)
+	if script == vz.ScriptPath && rendered == vz.RenderedPath {
+		return nil, nil
+	}
+	vz.ScriptPath, vz.RenderedPath = script, rendered
+	return json.Marshal(vz)
+}
+
+// rewriteDataset replaces the component references of a dataset document
+// with their destination paths, reporting whether any of them changed
+func (c *copier) rewriteDataset(ds *dataset.Dataset, history bool) bool {
+	changed := false
+	mapped := func(p string) string {
+		dp := c.mapped(p)
+		if dp != p {
+			changed = true
+		}
+		return dp
+	}
+	if ds.Meta != nil && ds.Meta.Path != "" {
+		ds.Meta = dataset.NewMetaRef(mapped(ds.Meta.Path))
+	}
+	if ds.Structure != nil && ds.Structure.Path != "" {
+		ds.Structure = dataset.NewStructureRef(mapped(ds.Structure.Path))
+	}
+	if ds.Commit != nil && ds.Commit.Path != "" {
+		ds.Commit = dataset.NewCommitRef(mapped(ds.Commit.Path))
+	}
+	if ds.Transform != nil && ds.Transform.Path != "" {
+		ds.Transform = dataset.NewTransformRef(mapped(ds.Transform.Path))
+	}
+	if ds.Viz != nil && ds.Viz.Path != "" {
+		ds.Viz = dataset.NewVizRef(mapped(ds.Viz.Path))
+	}
+	ds.BodyPath = mapped(ds.BodyPath)
+	ds.BodyIndexPath = mapped(ds.BodyIndexPath)
+	ds.BodyPartitionsPath = mapped(ds.BodyPartitionsPath)
+	for _, nb := range ds.Bodies {
+		if nb != nil {
+			nb.Path = mapped(nb.Path)
+		}
+	}
+	if history && ds.PreviousPath != "" {
+		if prev, err := normalizePath(ds.PreviousPath); err == nil {
+			if dp := mapped(prev); dp != prev {
+				ds.PreviousPath = dp
+			}
+		}
+	}
+	return changed
+}
+
+// addDatasetFile writes a dataset document to dst in a directory, as
+// WriteDataset does, returning the path of the directory
+func (c *copier) addDatasetFile(data []byte) (string, error) {
+	adder, err := c.dst.NewAdder(c.pin, true)
+	if err != nil {
+		return "", fmt.Errorf("error creating new adder: %w", err)
+	}
+	adder.AddFile(qfs.NewMemfileBytes(PackageFileDataset.String(), data))
+
+	var path string
+	done := make(chan error, 0)
+	go func() {
+		for ao := range adder.Added() {
+			path = ao.Path
+			if ao.Name == PackageFileDataset.String() {
+				if err := adder.Close(); err != nil {
+					done <- err
+					return
+				}
+			}
+		}
+		done <- nil
+	}()
+	if err := <-done; err != nil {
+		return "", fmt.Errorf("error writing dataset: %w", err)
+	}
+	return path, nil
+}
+
+// hasPath reports whether a store has a path
+func hasPath(store cafs.Filestore, path string) (bool, error) {
+	f, err := getFile(store, path)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	f.Close()
+	return true, nil
+}
@@ -0,0 +1,96 @@
+package dsfs
+
+import (
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+func TestCopyDataset(t *testing.T) {
+	src := cafs.NewMapstore()
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema:       dataset.BaseSchemaArray,
+	}
+
+	v1 := &dataset.Dataset{Meta: &dataset.Meta{Title: "readings"}, Structure: st}
+	v1.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte("a,b\n1,2\n")))
+	p1, err := WriteDataset(src, v1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2 := &dataset.Dataset{Meta: &dataset.Meta{Title: "readings"}, Structure: st, PreviousPath: p1}
+	v2.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte("a,b\n1,2\n3,4\n")))
+	p2, err := WriteDataset(src, v2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := cafs.NewMapstore()
+	res, err := CopyDataset(src, dst, p2, CopyOptions{Pin: true, History: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.FilesCopied == 0 || res.BytesCopied == 0 {
+		t.Errorf("expected files to be copied, got: %+v", res)
+	}
+	// stores that hash files alike keep the dataset's path
+	if res.Path != p2 {
+		t.Errorf("path mismatch. expected: %s, got: %s", p2, res.Path)
+	}
+
+	got, err := LoadDataset(dst, res.Path)
+	if err != nil {
+		t.Fatalf("loading copied dataset: %s", err)
+	}
+	data, err := fileBytes(LoadBody(dst, got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "a,b\n1,2\n3,4\n" {
+		t.Errorf("body mismatch, got: %q", data)
+	}
+	if got.Meta == nil || got.Meta.Title != "readings" {
+		t.Errorf("expected meta to be copied, got: %v", got.Meta)
+	}
+	prev, err := LoadDataset(dst, got.PreviousPath)
+	if err != nil {
+		t.Fatalf("loading copied previous version: %s", err)
+	}
+	if data, err = fileBytes(LoadBody(dst, prev)); err != nil || string(data) != "a,b\n1,2\n" {
+		t.Errorf("previous body mismatch, got: %q %v", data, err)
+	}
+
+	// a second copy writes nothing
+	again, err := CopyDataset(src, dst, p2, CopyOptions{History: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.FilesCopied != 0 || again.Path != p2 {
+		t.Errorf("expected nothing to be copied, got: %+v", again)
+	}
+	if again.FilesSkipped == 0 {
+		t.Errorf("expected files to be skipped, got: %+v", again)
+	}
+
+	// without history only the named version is copied
+	single, err := CopyDataset(src, cafs.NewMapstore(), p2, CopyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if single.FilesCopied >= res.FilesCopied {
+		t.Errorf("expected fewer files to be copied without history. history: %d, single: %d", res.FilesCopied, single.FilesCopied)
+	}
+
+	// versions dst already has aren't copied
+	skipped, err := CopyDataset(src, src, p2, CopyOptions{History: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped.FilesCopied != 0 || skipped.Path != p2 {
+		t.Errorf("expected nothing to be copied, got: %+v", skipped)
+	}
+}
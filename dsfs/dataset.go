@@ -2,18 +2,22 @@ package dsfs
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/libp2p/go-libp2p-crypto"
 	"github.com/multiformats/go-multihash"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/dslog"
+	"github.com/qri-io/dataset/dstrace"
 	"github.com/qri-io/dataset/dsviz"
 	"github.com/qri-io/dataset/validate"
 	"github.com/qri-io/dsdiff"
@@ -24,13 +28,24 @@ import (
 // LoadDataset reads a dataset from a cafs and dereferences structure, transform, and commitMsg if they exist,
 // returning a fully-hydrated dataset
 func LoadDataset(store cafs.Filestore, path string) (*dataset.Dataset, error) {
+	return loadDataset(context.Background(), store, path)
+}
+
+// loadDataset is LoadDataset, tracing reading refs & dereferencing components
+// as children of the span ctx carries
+func loadDataset(ctx context.Context, store cafs.Filestore, path string) (*dataset.Dataset, error) {
+	_, span := dstrace.StartSpan(ctx, "dsfs.LoadDatasetRefs", dstrace.Attr("dataset.path", path))
 	ds, err := LoadDatasetRefs(store, path)
+	span.End(err)
 	if err != nil {
-		log.Debug(err.Error())
-		return nil, fmt.Errorf("error loading dataset: %s", err.Error())
+		log.Debugw(err.Error(), dslog.F("path", path))
+		return nil, fmt.Errorf("error loading dataset: %w", err)
 	}
-	if err := DerefDataset(store, ds); err != nil {
-		log.Debug(err.Error())
+	_, span = dstrace.StartSpan(ctx, "dsfs.DerefDataset")
+	err = DerefDataset(store, ds)
+	span.End(err)
+	if err != nil {
+		log.Debugw(err.Error(), dslog.F("path", path))
 		return nil, err
 	}
 
@@ -40,28 +55,32 @@ func LoadDataset(store cafs.Filestore, path string) (*dataset.Dataset, error) {
 // LoadDatasetRefs reads a dataset from a content addressed filesystem without dereferencing
 // it's components
 func LoadDatasetRefs(store cafs.Filestore, path string) (*dataset.Dataset, error) {
+	path, err := normalizePath(path)
+	if err != nil {
+		return nil, err
+	}
 	ds := dataset.NewDatasetRef(path)
 
 	pathWithBasename := PackageFilepath(store, path, PackageFileDataset)
-	data, err := fileBytes(store.Get(pathWithBasename))
+	data, err := fileBytes(getFile(store, pathWithBasename))
 	// if err != nil {
-	// 	return nil, fmt.Errorf("error getting file bytes: %s", err.Error())
+	// 	return nil, fmt.Errorf("error getting file bytes: %w", err)
 	// }
 
 	// TODO - for some reason files are sometimes coming back empty from IPFS,
 	// every now & then. In the meantime, let's give a second try if data is empty
 	if err != nil || len(data) == 0 {
-		data, err = fileBytes(store.Get(pathWithBasename))
+		data, err = fileBytes(getFile(store, pathWithBasename))
 		if err != nil {
-			log.Debug(err.Error())
-			return nil, fmt.Errorf("error getting file bytes: %s", err.Error())
+			log.Debugw(err.Error(), dslog.F("path", path))
+			return nil, fmt.Errorf("error getting file bytes: %w", err)
 		}
 	}
 
 	ds, err = dataset.UnmarshalDataset(data)
 	if err != nil {
-		log.Debug(err.Error())
-		return nil, fmt.Errorf("error unmarshaling %s file: %s", PackageFileDataset.String(), err.Error())
+		log.Debugw(err.Error(), dslog.F("path", path))
+		return nil, fmt.Errorf("error unmarshaling %s file: %w", PackageFileDataset.String(), err)
 	}
 
 	// assign path to retain internal reference to the
@@ -95,7 +114,7 @@ func DerefDatasetStructure(store cafs.Filestore, ds *dataset.Dataset) error {
 		st, err := loadStructure(store, ds.Structure.Path)
 		if err != nil {
 			log.Debug(err.Error())
-			return fmt.Errorf("error loading dataset structure: %s", err.Error())
+			return fmt.Errorf("error loading dataset structure: %w", err)
 		}
 		// assign path to retain internal reference to path
 		// st.Assign(dataset.NewStructureRef(ds.Structure.Path))
@@ -111,7 +130,7 @@ func DerefDatasetViz(store cafs.Filestore, ds *dataset.Dataset) error {
 		st, err := loadViz(store, ds.Viz.Path)
 		if err != nil {
 			log.Debug(err.Error())
-			return fmt.Errorf("error loading dataset viz: %s", err.Error())
+			return fmt.Errorf("error loading dataset viz: %w", err)
 		}
 		// assign path to retain internal reference to path
 		// st.Assign(dataset.NewVizRef(ds.Viz.Path))
@@ -127,7 +146,7 @@ func DerefDatasetTransform(store cafs.Filestore, ds *dataset.Dataset) error {
 		t, err := loadTransform(store, ds.Transform.Path)
 		if err != nil {
 			log.Debug(err.Error())
-			return fmt.Errorf("error loading dataset transform: %s", err.Error())
+			return fmt.Errorf("error loading dataset transform: %w", err)
 		}
 		// assign path to retain internal reference to path
 		// t.Assign(dataset.NewTransformRef(ds.Transform.Path))
@@ -143,7 +162,7 @@ func DerefDatasetMeta(store cafs.Filestore, ds *dataset.Dataset) error {
 		md, err := loadMeta(store, ds.Meta.Path)
 		if err != nil {
 			log.Debug(err.Error())
-			return fmt.Errorf("error loading dataset metadata: %s", err.Error())
+			return fmt.Errorf("error loading dataset metadata: %w", err)
 		}
 		// assign path to retain internal reference to path
 		// md.Assign(dataset.NewMetaRef(ds.Meta.Path))
@@ -159,7 +178,7 @@ func DerefDatasetCommit(store cafs.Filestore, ds *dataset.Dataset) error {
 		cm, err := loadCommit(store, ds.Commit.Path)
 		if err != nil {
 			log.Debug(err.Error())
-			return fmt.Errorf("error loading dataset commit: %s", err.Error())
+			return fmt.Errorf("error loading dataset commit: %w", err)
 		}
 		// assign path to retain internal reference to path
 		cm.Assign(dataset.NewCommitRef(ds.Commit.Path))
@@ -174,7 +193,13 @@ func DerefDatasetCommit(store cafs.Filestore, ds *dataset.Dataset) error {
 // Pin the dataset if the underlying store supports the pinning interface
 // All streaming files (Body, Transform Script, Viz Script) Must be Resolved before calling if data their data is to be saved
 func CreateDataset(store cafs.Filestore, ds, dsPrev *dataset.Dataset, pk crypto.PrivKey, pin, force, shouldRender bool) (path string, err error) {
+	return createDataset(context.Background(), store, ds, dsPrev, pk, pin, force, shouldRender)
+}
 
+// createDataset is CreateDataset with create options read from ctx. The
+// dataset write is traced as a child of the span ctx carries
+func createDataset(ctx context.Context, store cafs.Filestore, ds, dsPrev *dataset.Dataset, pk crypto.PrivKey, pin, force, shouldRender bool) (path string, err error) {
+	opts := createOptionsFromContext(ctx)
 	if pk == nil {
 		err = fmt.Errorf("private key is required to create a dataset")
 		return
@@ -198,29 +223,28 @@ func CreateDataset(store cafs.Filestore, ds, dsPrev *dataset.Dataset, pk crypto.
 			return
 		}
 	}
-	_, err = prepareDataset(store, ds, dsPrev, pk, force, shouldRender)
+	_, err = prepareDataset(store, ds, dsPrev, pk, force, shouldRender, opts)
 	if err != nil {
 		log.Debug(err.Error())
 		return
 	}
 
-	path, err = WriteDataset(store, ds, pin)
+	path, err = WriteDatasetContext(ctx, store, ds, pin)
 	if err != nil {
 		log.Debug(err.Error())
-		err = fmt.Errorf("error writing dataset: %s", err.Error())
+		err = fmt.Errorf("error writing dataset: %w", err)
 	}
 	return
 }
 
-// Timestamp is an function for getting commit timestamps
-// timestamps MUST be stored in UTC time zone
-var Timestamp = func() time.Time {
-	return time.Now().UTC()
-}
+// Timestamp is the Clock commit timestamps are read from. Replace it to pin
+// timestamps package-wide, or use WithClock to pin them for one call to
+// CreateDatasetContext. timestamps MUST be stored in UTC time zone
+var Timestamp Clock = SystemClock
 
 // prepareDataset modifies a dataset in preparation for adding to a dsfs
 // it returns a new data file for use in WriteDataset
-func prepareDataset(store cafs.Filestore, ds, dsPrev *dataset.Dataset, privKey crypto.PrivKey, force, shouldRender bool) (string, error) {
+func prepareDataset(store cafs.Filestore, ds, dsPrev *dataset.Dataset, privKey crypto.PrivKey, force, shouldRender bool, opts createOptions) (string, error) {
 	var (
 		err error
 		// lock for parallel edits to ds pointer
@@ -279,7 +303,7 @@ func prepareDataset(store cafs.Filestore, ds, dsPrev *dataset.Dataset, privKey c
 	diffDescription, err := generateCommitMsg(ds, dsPrev, force)
 	if err != nil {
 		log.Debug(fmt.Errorf("error saving: %s", err))
-		return "", fmt.Errorf("error saving: %s", err)
+		return "", fmt.Errorf("error saving: %w", err)
 	}
 
 	cleanTitleAndMessage(&ds.Commit.Title, &ds.Commit.Message, diffDescription)
@@ -288,12 +312,12 @@ func prepareDataset(store cafs.Filestore, ds, dsPrev *dataset.Dataset, privKey c
 	// ignoring fields we know will change every time. Can only do this with a proper set
 	// of change deltas
 
-	ds.Commit.Timestamp = Timestamp()
+	ds.Commit.Timestamp = opts.timestamp()
 	sb, _ := ds.SignableBytes()
 	signedBytes, err := privKey.Sign(sb)
 	if err != nil {
 		log.Debug(err.Error())
-		return "", fmt.Errorf("error signing commit title: %s", err.Error())
+		return "", fmt.Errorf("error signing commit title: %w", err)
 	}
 	ds.Commit.Signature = base64.StdEncoding.EncodeToString(signedBytes)
 	ds.SetBodyFile(qfs.NewMemfileBytes("body."+ds.Structure.Format, buf.Bytes()))
@@ -303,7 +327,7 @@ func prepareDataset(store cafs.Filestore, ds, dsPrev *dataset.Dataset, privKey c
 		renderedFile, err := dsviz.Render(ds)
 		if err != nil {
 			log.Debug(err.Error())
-			return "", fmt.Errorf("error rendering visualization: %s", err.Error())
+			return "", fmt.Errorf("error rendering visualization: %w", err)
 		}
 		ds.Viz.SetRenderedFile(renderedFile)
 	}
@@ -318,14 +342,14 @@ func setErrCount(ds *dataset.Dataset, data qfs.File, mu *sync.Mutex, done chan e
 	er, err := dsio.NewEntryReader(ds.Structure, data)
 	if err != nil {
 		log.Debug(err.Error())
-		done <- fmt.Errorf("reading data values: %s", err.Error())
+		done <- fmt.Errorf("reading data values: %w", err)
 		return
 	}
 
 	validationErrors, err := validate.EntryReader(er)
 	if err != nil {
 		log.Debug(err.Error())
-		done <- fmt.Errorf("validating data: %s", err.Error())
+		done <- fmt.Errorf("validating data: %w", err)
 		return
 	}
 
@@ -343,7 +367,7 @@ func setDepthAndEntryCount(ds *dataset.Dataset, data qfs.File, mu *sync.Mutex, d
 	er, err := dsio.NewEntryReader(ds.Structure, data)
 	if err != nil {
 		log.Debug(err.Error())
-		done <- fmt.Errorf("error reading data values: %s", err.Error())
+		done <- fmt.Errorf("error reading data values: %w", err)
 		return
 	}
 
@@ -362,8 +386,8 @@ func setDepthAndEntryCount(ds *dataset.Dataset, data qfs.File, mu *sync.Mutex, d
 		}
 		entries++
 	}
-	if err.Error() != "EOF" {
-		done <- fmt.Errorf("error reading values at entry %d: %s", entries, err.Error())
+	if !errors.Is(err, io.EOF) {
+		done <- fmt.Errorf("error reading values at entry %d: %w", entries, err)
 		return
 	}
 
@@ -409,7 +433,7 @@ func setChecksumAndStats(ds *dataset.Dataset, data qfs.File, buf *bytes.Buffer,
 	shasum, err := multihash.Sum(buf.Bytes(), multihash.SHA2_256, -1)
 	if err != nil {
 		log.Debug(err.Error())
-		done <- fmt.Errorf("error calculating hash: %s", err.Error())
+		done <- fmt.Errorf("error calculating hash: %w", err)
 		return
 	}
 
@@ -431,7 +455,7 @@ func generateCommitMsg(ds, prev *dataset.Dataset, force bool) (string, error) {
 
 	diffMap, err := dsdiff.DiffDatasets(prev, ds, nil)
 	if err != nil {
-		err = fmt.Errorf("error diffing datasets: %s", err.Error())
+		err = fmt.Errorf("error diffing datasets: %w", err)
 		return "", err
 	}
 
@@ -488,22 +512,160 @@ func cleanTitleAndMessage(sTitle, sMsg *string, diffDescription string) {
 	*sMsg = sm
 }
 
+// errBodyNotRead aborts indexing a body that wasn't read to the end
+var errBodyNotRead = fmt.Errorf("body wasn't read to the end")
+
+// bodyIndexer builds an index of a body file from the bytes read from it
+type bodyIndexer struct {
+	qfs.File
+	w       *io.PipeWriter
+	indexed chan *dsio.BodyIndex
+}
+
+// indexBody wraps a body file, indexing it as it's read. reading is traced as
+// a child of the span ctx carries
+func indexBody(ctx context.Context, st *dataset.Structure, file qfs.File, interval int) *bodyIndexer {
+	_, span := dstrace.StartSpan(ctx, "dsfs.IndexBody")
+	r, w := io.Pipe()
+	b := &bodyIndexer{File: file, w: w, indexed: make(chan *dsio.BodyIndex, 1)}
+	go func() {
+		idx, err := dsio.BuildBodyIndex(st, r, interval)
+		// drain the pipe, reads of the body mustn't block on a failed index
+		io.Copy(ioutil.Discard, r)
+		span.End(err)
+		if err != nil {
+			log.Debugw("not indexing body", dslog.F("error", err))
+			idx = nil
+		} else if len(idx.Checkpoints) == 0 {
+			idx = nil
+		}
+		b.indexed <- idx
+	}()
+	return b
+}
+
+// Read implements the io.Reader interface, feeding bytes to the index
+func (b *bodyIndexer) Read(p []byte) (int, error) {
+	n, err := b.File.Read(p)
+	if n > 0 {
+		b.w.Write(p[:n])
+	}
+	if errors.Is(err, io.EOF) {
+		b.w.Close()
+	} else if err != nil {
+		b.w.CloseWithError(err)
+	}
+	return n, err
+}
+
+// Close implements the io.Closer interface
+func (b *bodyIndexer) Close() error {
+	b.abort()
+	return b.File.Close()
+}
+
+// abort stops indexing a body that hasn't been read to the end
+func (b *bodyIndexer) abort() {
+	b.w.CloseWithError(errBodyNotRead)
+}
+
+// index gives the body index once the body has been read. bodies that
+// can't be indexed, are too short to need an index or weren't read to the end
+// give a nil index
+func (b *bodyIndexer) index() *dsio.BodyIndex {
+	b.abort()
+	return <-b.indexed
+}
+
+// partitionBody splits a body file into shards by it's structure's partition,
+// returning a replacement for the consumed file, the shard manifest & shard
+// data
+func partitionBody(st *dataset.Structure, file qfs.File) (qfs.File, *dsio.PartitionManifest, [][]byte, error) {
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, nil, nil, err
+	}
+	file.Close()
+	file = qfs.NewMemfileBytes(file.FileName(), data)
+
+	m, shards, err := dsio.PartitionBody(st, bytes.NewReader(data))
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, nil, nil, err
+	}
+	return file, m, shards, nil
+}
+
+// partitionShardFilename gives the filename a partition shard is stored as
+func partitionShardFilename(i int, format string) string {
+	return fmt.Sprintf("body_partition_%d.%s", i, format)
+}
+
+// namedBodyFilename gives the filename a named body is stored as
+func namedBodyFilename(name string, nb *dataset.NamedBody) (string, error) {
+	if err := dataset.ValidBodyName(name); err != nil {
+		return "", err
+	}
+	if nb.Structure == nil {
+		return "", fmt.Errorf("body %s requires a structure to save", name)
+	}
+	return fmt.Sprintf("body_%s.%s", name, nb.Structure.Format), nil
+}
+
 // WriteDataset writes a dataset to a cafs, replacing subcomponents of a dataset with path references
 // during the write process. Directory structure is according to PackageFile naming conventions.
 // This method is currently exported, but 99% of use cases should use CreateDataset instead of this
 // lower-level function
 func WriteDataset(store cafs.Filestore, ds *dataset.Dataset, pin bool) (string, error) {
+	return writeDataset(context.Background(), store, ds, pin)
+}
 
+// writeDataset is WriteDataset, tracing body indexing & partitioning as
+// children of the span ctx carries
+func writeDataset(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset, pin bool) (string, error) {
 	if ds == nil || ds.IsEmpty() {
 		return "", fmt.Errorf("cannot save empty dataset")
 	}
 	name := ds.Name // preserve name for body file
 	bodyFile := ds.BodyFile()
+	var indexer *bodyIndexer
+	if interval := bodyIndexInterval(ctx); interval > 0 && bodyFile != nil && ds.Structure != nil {
+		indexer = indexBody(ctx, ds.Structure, bodyFile, interval)
+		defer indexer.abort()
+		bodyFile = indexer
+	}
+	var (
+		partitions *dsio.PartitionManifest
+		shardData  [][]byte
+	)
+	if bodyFile != nil && ds.Structure != nil && ds.Structure.Partition != nil {
+		var err error
+		_, span := dstrace.StartSpan(ctx, "dsfs.PartitionBody")
+		bodyFile, partitions, shardData, err = partitionBody(ds.Structure, bodyFile)
+		span.End(err)
+		if err != nil {
+			return "", fmt.Errorf("error partitioning body: %w", err)
+		}
+	}
+	// named bodies are written with the name in the filename, mapping added
+	// files back to their body
+	namedBodies := map[string]*dataset.NamedBody{}
+	for name, nb := range ds.Bodies {
+		if nb == nil || nb.BodyFile() == nil {
+			continue
+		}
+		filename, err := namedBodyFilename(name, nb)
+		if err != nil {
+			return "", err
+		}
+		namedBodies[filename] = nb
+	}
 	fileTasks := 0
 	addedDataset := false
 	adder, err := store.NewAdder(pin, true)
 	if err != nil {
-		return "", fmt.Errorf("error creating new adder: %s", err.Error())
+		return "", fmt.Errorf("error creating new adder: %w", err)
 	}
 
 	if ds.Viz != nil {
@@ -528,7 +690,7 @@ func WriteDataset(store cafs.Filestore, ds *dataset.Dataset, pin bool) (string,
 		} else {
 			vizdata, err := json.Marshal(ds.Viz)
 			if err != nil {
-				return "", fmt.Errorf("error marshalling dataset viz to json: %s", err.Error())
+				return "", fmt.Errorf("error marshalling dataset viz to json: %w", err)
 			}
 			adder.AddFile(qfs.NewMemfileBytes(PackageFileViz.String(), vizdata))
 		}
@@ -537,7 +699,7 @@ func WriteDataset(store cafs.Filestore, ds *dataset.Dataset, pin bool) (string,
 	if ds.Meta != nil {
 		mdf, err := JSONFile(PackageFileMeta.String(), ds.Meta)
 		if err != nil {
-			return "", fmt.Errorf("error marshaling metadata to json: %s", err.Error())
+			return "", fmt.Errorf("error marshaling metadata to json: %w", err)
 		}
 		fileTasks++
 		adder.AddFile(mdf)
@@ -565,7 +727,7 @@ func WriteDataset(store cafs.Filestore, ds *dataset.Dataset, pin bool) (string,
 		} else {
 			tfdata, err := json.Marshal(ds.Transform)
 			if err != nil {
-				return "", fmt.Errorf("error marshalling dataset transform to json: %s", err.Error())
+				return "", fmt.Errorf("error marshalling dataset transform to json: %w", err)
 			}
 
 			fileTasks++
@@ -577,7 +739,7 @@ func WriteDataset(store cafs.Filestore, ds *dataset.Dataset, pin bool) (string,
 		ds.Commit.DropTransientValues()
 		cmf, err := JSONFile(PackageFileCommit.String(), ds.Commit)
 		if err != nil {
-			return "", fmt.Errorf("error marshilng dataset commit message to json: %s", err.Error())
+			return "", fmt.Errorf("error marshilng dataset commit message to json: %w", err)
 		}
 		fileTasks++
 		adder.AddFile(cmf)
@@ -587,12 +749,45 @@ func WriteDataset(store cafs.Filestore, ds *dataset.Dataset, pin bool) (string,
 		ds.Structure.DropTransientValues()
 		stf, err := JSONFile(PackageFileStructure.String(), ds.Structure)
 		if err != nil {
-			return "", fmt.Errorf("error marshaling dataset structure to json: %s", err.Error())
+			return "", fmt.Errorf("error marshaling dataset structure to json: %w", err)
 		}
 		fileTasks++
 		adder.AddFile(stf)
 	}
 
+	for filename, nb := range namedBodies {
+		nb.Structure.DropTransientValues()
+		fileTasks++
+		adder.AddFile(qfs.NewMemfileReader(filename, nb.BodyFile()))
+	}
+
+	// the partition manifest is added once every shard has a path
+	partitionShards := map[string]*dsio.PartitionShard{}
+	pendingShards := 0
+	addPartitionManifest := func() error {
+		data, err := json.Marshal(partitions)
+		if err != nil {
+			return fmt.Errorf("error marshaling partition manifest to json: %w", err)
+		}
+		adder.AddFile(qfs.NewMemfileBytes(PackageFileBodyPartitions.String(), data))
+		return nil
+	}
+	if partitions != nil {
+		for i, shard := range partitions.Shards {
+			filename := partitionShardFilename(i, partitions.Format)
+			partitionShards[filename] = shard
+			fileTasks++
+			adder.AddFile(qfs.NewMemfileBytes(filename, shardData[i]))
+		}
+		fileTasks++
+		pendingShards = len(partitions.Shards)
+		if pendingShards == 0 {
+			if err := addPartitionManifest(); err != nil {
+				return "", err
+			}
+		}
+	}
+
 	fileTasks++
 	adder.AddFile(bodyFile)
 
@@ -612,8 +807,25 @@ func WriteDataset(store cafs.Filestore, ds *dataset.Dataset, pin bool) (string,
 				ds.Commit = dataset.NewCommitRef(ao.Path)
 			case PackageFileViz.String():
 				ds.Viz = dataset.NewVizRef(ao.Path)
+			case PackageFileBodyIndex.String():
+				ds.BodyIndexPath = ao.Path
+			case PackageFileBodyPartitions.String():
+				ds.BodyPartitionsPath = ao.Path
 			case bodyFile.FileName():
 				ds.BodyPath = ao.Path
+				// the body has been read, so its index is built
+				if indexer == nil {
+					break
+				}
+				if idx := indexer.index(); idx != nil {
+					data, err := json.Marshal(idx)
+					if err != nil {
+						done <- fmt.Errorf("error marshaling body index to json: %w", err)
+						return
+					}
+					fileTasks++
+					adder.AddFile(qfs.NewMemfileBytes(PackageFileBodyIndex.String(), data))
+				}
 				// ds.SetBodyFile(qfs.NewMemfileBytes(bodyFile.FileName(), bodyBytesBuf.Bytes()))
 			case transformScriptFilename:
 				ds.Transform.ScriptPath = ao.Path
@@ -638,6 +850,19 @@ func WriteDataset(store cafs.Filestore, ds *dataset.Dataset, pin bool) (string,
 				}
 				// Add the encoded transform file, decrementing the stray fileTasks from above
 				adder.AddFile(qfs.NewMemfileBytes(PackageFileViz.String(), vizdata))
+			default:
+				if nb, ok := namedBodies[ao.Name]; ok {
+					nb.Path = ao.Path
+				}
+				if shard, ok := partitionShards[ao.Name]; ok {
+					shard.Path = ao.Path
+					if pendingShards--; pendingShards == 0 {
+						if err := addPartitionManifest(); err != nil {
+							done <- err
+							return
+						}
+					}
+				}
 			}
 
 			fileTasks--
@@ -69,6 +69,8 @@ func TestLoadDataset(t *testing.T) {
 	}{
 		{dataset.NewDatasetRef("/bad/path"),
 			"error loading dataset: error getting file bytes: cafs: path not found"},
+		{dataset.NewDatasetRef("/map/../path"),
+			`error loading dataset: invalid dataset path: invalid path "/map/../path": paths can't contain relative segments`},
 		{&dataset.Dataset{
 			Meta: dataset.NewMetaRef("/bad/path"),
 		}, "error loading dataset metadata: error loading metadata file: cafs: path not found"},
@@ -2,7 +2,12 @@
 package dsfs
 
 import (
-	logger "github.com/ipfs/go-log"
+	"github.com/qri-io/dataset/dslog"
 )
 
-var log = logger.Logger("dsfs")
+var log = dslog.NewPackage("dsfs")
+
+// SetLogger sets the logger for package dsfs. By default nothing is logged
+func SetLogger(l dslog.Logger) {
+	log.SetLogger(l)
+}
@@ -2,11 +2,41 @@ package dsfs
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
+	"os"
 
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
 )
 
+// ErrNotFound is matched by errors for paths that don't exist in a store,
+// check with errors.Is. The store's own error is wrapped, not replaced
+var ErrNotFound = errors.New("not found")
+
+// notFoundError wraps a store error for a missing path
+type notFoundError struct {
+	err error
+}
+
+func (e notFoundError) Error() string { return e.err.Error() }
+
+// Is reports notFoundErrors as ErrNotFound
+func (e notFoundError) Is(target error) bool { return target == ErrNotFound }
+
+// Unwrap gives the store error
+func (e notFoundError) Unwrap() error { return e.err }
+
+// getFile fetches a path from a store, marking missing path errors with
+// ErrNotFound
+func getFile(store cafs.Filestore, path string) (qfs.File, error) {
+	f, err := store.Get(path)
+	if err != nil && (errors.Is(err, cafs.ErrNotFound) || errors.Is(err, os.ErrNotExist)) {
+		return nil, notFoundError{err}
+	}
+	return f, err
+}
+
 // JSONFile is a convenenience method for creating a file from a json.Marshaller
 func JSONFile(name string, m json.Marshaler) (qfs.File, error) {
 	data, err := m.MarshalJSON()
@@ -0,0 +1,19 @@
+package dsfs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/qri-io/qfs/cafs"
+)
+
+func TestLoadDatasetNotFound(t *testing.T) {
+	store := cafs.NewMapstore()
+	_, err := LoadDataset(store, "/map/QmNotHere")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected missing dataset to be ErrNotFound, got: %v", err)
+	}
+	if !errors.Is(err, cafs.ErrNotFound) {
+		t.Errorf("expected store error to be wrapped, got: %v", err)
+	}
+}
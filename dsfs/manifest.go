@@ -0,0 +1,144 @@
+package dsfs
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/qri-io/dataset/dslog"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// Manifest component names
+const (
+	ManifestDataset         = "dataset"
+	ManifestMeta            = "meta"
+	ManifestStructure       = "structure"
+	ManifestCommit          = "commit"
+	ManifestTransform       = "transform"
+	ManifestTransformScript = "transform_script"
+	ManifestViz             = "viz"
+	ManifestVizScript       = "viz_script"
+	ManifestRenderedViz     = "rendered_viz"
+	ManifestBody            = "body"
+	ManifestBodyIndex       = "body_index"
+	ManifestBodyPartitions  = "body_partitions"
+	ManifestBodyShard       = "body_shard"
+	ManifestNamedBody       = "named_body"
+)
+
+// DatasetManifest lists every stored file that makes up a dataset version
+type DatasetManifest struct {
+	// Path is the dataset path the manifest was made from
+	Path string `json:"path"`
+	// Files are the files of the version, dataset document first
+	Files []*ManifestFile `json:"files"`
+	// Size is the total size of Files in bytes
+	Size int64 `json:"size"`
+}
+
+// ManifestFile is a stored file of a dataset version
+type ManifestFile struct {
+	// Path is the store path of the file
+	Path string `json:"path"`
+	// Component is the part of the dataset the file holds, one of the
+	// Manifest component names
+	Component string `json:"component"`
+	// Name is the name of a named body, or the partition key of a body shard
+	Name string `json:"name,omitempty"`
+	// Size is the length of the file in bytes
+	Size int64 `json:"size"`
+}
+
+// Manifest lists the files of the dataset version at path with their sizes:
+// components, the body & it's index & shards, named bodies and scripts.
+// Datasets a transform reads as resources aren't part of the version & aren't
+// listed. Each file is read from the store to find it's size
+func Manifest(store cafs.Filestore, path string) (*DatasetManifest, error) {
+	ds, err := LoadDatasetRefs(store, path)
+	if err != nil {
+		return nil, err
+	}
+	m := &DatasetManifest{Path: ds.Path}
+	add := func(component, name, p string) {
+		if p != "" {
+			m.Files = append(m.Files, &ManifestFile{Path: p, Component: component, Name: name})
+		}
+	}
+
+	add(ManifestDataset, "", PackageFilepath(store, ds.Path, PackageFileDataset))
+	if ds.Meta != nil {
+		add(ManifestMeta, "", ds.Meta.Path)
+	}
+	if ds.Structure != nil {
+		add(ManifestStructure, "", ds.Structure.Path)
+	}
+	if ds.Commit != nil {
+		add(ManifestCommit, "", ds.Commit.Path)
+	}
+	if ds.Transform != nil {
+		t := ds.Transform
+		add(ManifestTransform, "", t.Path)
+		if t.IsEmpty() && t.Path != "" {
+			if t, err = loadTransform(store, t.Path); err != nil {
+				log.Debugw(err.Error(), dslog.F("path", path))
+				return nil, fmt.Errorf("error loading dataset transform: %w", err)
+			}
+		}
+		add(ManifestTransformScript, "", t.ScriptPath)
+	}
+	if ds.Viz != nil {
+		vz := ds.Viz
+		add(ManifestViz, "", vz.Path)
+		if vz.IsEmpty() && vz.Path != "" {
+			if vz, err = loadViz(store, vz.Path); err != nil {
+				log.Debugw(err.Error(), dslog.F("path", path))
+				return nil, fmt.Errorf("error loading dataset viz: %w", err)
+			}
+		}
+		add(ManifestVizScript, "", vz.ScriptPath)
+		add(ManifestRenderedViz, "", vz.RenderedPath)
+	}
+	add(ManifestBody, "", ds.BodyPath)
+	add(ManifestBodyIndex, "", ds.BodyIndexPath)
+	if ds.BodyPartitionsPath != "" {
+		add(ManifestBodyPartitions, "", ds.BodyPartitionsPath)
+		pm, err := LoadBodyPartitions(store, ds)
+		if err != nil {
+			return nil, err
+		}
+		for _, shard := range pm.Shards {
+			add(ManifestBodyShard, shard.Key, shard.Path)
+		}
+	}
+	names := make([]string, 0, len(ds.Bodies))
+	for name, nb := range ds.Bodies {
+		if nb != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(ManifestNamedBody, name, ds.Bodies[name].Path)
+	}
+
+	for _, f := range m.Files {
+		if f.Size, err = fileSize(store, f.Path); err != nil {
+			log.Debugw(err.Error(), dslog.F("path", f.Path))
+			return nil, fmt.Errorf("error reading %s file: %w", f.Component, err)
+		}
+		m.Size += f.Size
+	}
+	return m, nil
+}
+
+// fileSize gives the length of a stored file in bytes
+func fileSize(store cafs.Filestore, path string) (int64, error) {
+	f, err := getFile(store, path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(ioutil.Discard, f)
+}
@@ -0,0 +1,89 @@
+package dsfs
+
+import (
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+func TestManifest(t *testing.T) {
+	store := cafs.NewMapstore()
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "day", "type": "string"},
+					map[string]interface{}{"title": "reading", "type": "integer"},
+				},
+			},
+		},
+		Partition: &dataset.Partition{Column: "day", Granularity: dataset.PartitionMonth},
+	}
+	body := "day,reading\n2020-01-05,1\n2020-02-01,2\n2020-01-20,3\n"
+	ds := &dataset.Dataset{
+		Meta:      &dataset.Meta{Title: "readings"},
+		Structure: st,
+		Transform: &dataset.Transform{Syntax: "starlark"},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte(body)))
+	ds.Transform.SetScriptFile(qfs.NewMemfileBytes("transform.star", []byte("def transform(ds):\n  pass\n")))
+
+	path, err := WriteDataset(store, ds, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Manifest(store, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Path != path {
+		t.Errorf("path mismatch. expected: %s, got: %s", path, m.Path)
+	}
+	if len(m.Files) == 0 || m.Files[0].Component != ManifestDataset {
+		t.Fatalf("expected dataset document to be listed first, got: %v", m.Files)
+	}
+
+	counts := map[string]int{}
+	var size int64
+	for _, f := range m.Files {
+		counts[f.Component]++
+		size += f.Size
+		if f.Size == 0 {
+			t.Errorf("expected %s file %s to have a size", f.Component, f.Path)
+		}
+	}
+	expect := map[string]int{
+		ManifestDataset:         1,
+		ManifestMeta:            1,
+		ManifestStructure:       1,
+		ManifestTransform:       1,
+		ManifestTransformScript: 1,
+		ManifestBody:            1,
+		ManifestBodyPartitions:  1,
+		ManifestBodyShard:       2,
+	}
+	for component, n := range expect {
+		if counts[component] != n {
+			t.Errorf("expected %d %s files, got: %d", n, component, counts[component])
+		}
+	}
+	if size != m.Size {
+		t.Errorf("total size mismatch. expected: %d, got: %d", size, m.Size)
+	}
+	for _, f := range m.Files {
+		if f.Component == ManifestBody && f.Size != int64(len(body)) {
+			t.Errorf("body size mismatch. expected: %d, got: %d", len(body), f.Size)
+		}
+	}
+
+	if _, err := Manifest(store, "/map/QmNotAPath"); err == nil {
+		t.Error("expected missing dataset to error")
+	}
+}
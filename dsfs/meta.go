@@ -12,23 +12,26 @@ func SaveMeta(store cafs.Filestore, s *dataset.Meta, pin bool) (path string, err
 	file, err := JSONFile(PackageFileMeta.String(), s)
 	if err != nil {
 		log.Debug(err.Error())
-		return "", fmt.Errorf("error saving json metadata file: %s", err.Error())
+		return "", fmt.Errorf("error saving json metadata file: %w", err)
 	}
 	return store.Put(file, pin)
 }
 
 // LoadMeta loads a metadata from a given path in a store
 func LoadMeta(store cafs.Filestore, path string) (md *dataset.Meta, err error) {
+	if path, err = normalizePath(path); err != nil {
+		return nil, err
+	}
 	path = PackageFilepath(store, path, PackageFileMeta)
 	return loadMeta(store, path)
 }
 
 // loadMeta assumes the provided path is valid
 func loadMeta(store cafs.Filestore, path string) (md *dataset.Meta, err error) {
-	data, err := fileBytes(store.Get(path))
+	data, err := fileBytes(getFile(store, path))
 	if err != nil {
 		log.Debug(err.Error())
-		return nil, fmt.Errorf("error loading metadata file: %s", err.Error())
+		return nil, fmt.Errorf("error loading metadata file: %w", err)
 	}
 	return dataset.UnmarshalMeta(data)
 }
@@ -1,9 +1,11 @@
 package dsfs
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 
+	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs/cafs"
 )
 
@@ -52,6 +54,11 @@ const (
 	PackageFileViz
 	// PackageFileRenderedViz is the rendered visualization of the dataset
 	PackageFileRenderedViz
+	// PackageFileBodyIndex is an index of entry positions in the body
+	PackageFileBodyIndex
+	// PackageFileBodyPartitions is a manifest of the shards of a partitioned
+	// body
+	PackageFileBodyPartitions
 )
 
 // filenames maps PackageFile to their filename counterparts
@@ -67,6 +74,8 @@ var filenames = map[PackageFile]string{
 	PackageFileMeta:              "meta.json",
 	PackageFileViz:               "viz.json",
 	PackageFileRenderedViz:       "index.html",
+	PackageFileBodyIndex:         "body_index.json",
+	PackageFileBodyPartitions:    "body_partitions.json",
 }
 
 // String implements the io.Stringer interface for PackageFile
@@ -98,3 +107,13 @@ func PackageFilepath(store cafs.Filestore, path string, pf PackageFile) string {
 	}
 	return filepath.Join("/", prefix, GetHashBase(path, prefix), pf.String())
 }
+
+// normalizePath cleans a dataset path with dataset.NormalizeStorePath
+func normalizePath(path string) (string, error) {
+	path, err := dataset.NormalizeStorePath(path)
+	if err != nil {
+		log.Debug(err.Error())
+		return "", fmt.Errorf("invalid dataset path: %w", err)
+	}
+	return path, nil
+}
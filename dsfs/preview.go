@@ -0,0 +1,156 @@
+package dsfs
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/dslog"
+	"github.com/qri-io/dataset/vals"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// PreviewConfig encapsulates configuration for Preview
+type PreviewConfig struct {
+	// Sample spreads preview entries evenly across the body instead of taking
+	// the first entries. Datasets with a body index seek to each sampled
+	// entry, others are read in full
+	Sample bool
+}
+
+// Preview loads the dataset at path along with up to n entries of it's body,
+// the first n entries unless configured to sample. Entries of object bodies
+// are vals.ObjectValue values carrying the entry key
+func Preview(store cafs.Filestore, path string, n int, configs ...func(cfg *PreviewConfig)) (*dataset.Dataset, []vals.Value, error) {
+	cfg := &PreviewConfig{}
+	for _, config := range configs {
+		config(cfg)
+	}
+
+	ds, err := LoadDataset(store, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n <= 0 || ds.BodyPath == "" || ds.Structure == nil {
+		return ds, nil, nil
+	}
+
+	if !cfg.Sample {
+		vs, err := previewEntries(store, ds, []int{0}, n)
+		return ds, vs, err
+	}
+
+	entries, err := previewEntryCount(store, ds)
+	if err != nil {
+		return nil, nil, err
+	}
+	if entries <= n {
+		vs, err := previewEntries(store, ds, []int{0}, n)
+		return ds, vs, err
+	}
+
+	offsets := make([]int, n)
+	for i := range offsets {
+		offsets[i] = i * entries / n
+	}
+	if ds.BodyIndexPath == "" {
+		vs, err := previewEntries(store, ds, offsets, 1)
+		return ds, vs, err
+	}
+
+	vs := make([]vals.Value, 0, n)
+	for _, off := range offsets {
+		v, err := previewEntries(store, ds, []int{off}, 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		vs = append(vs, v...)
+	}
+	return ds, vs, nil
+}
+
+// previewEntryCount gives the number of entries in a dataset body, from the
+// structure or body index if either records it, counting entries if not
+func previewEntryCount(store cafs.Filestore, ds *dataset.Dataset) (int, error) {
+	if ds.Structure.Entries > 0 {
+		return ds.Structure.Entries, nil
+	}
+	if ds.BodyIndexPath != "" {
+		if idx, err := LoadBodyIndex(store, ds); err == nil {
+			return idx.Entries, nil
+		}
+	}
+
+	file, err := LoadBody(store, ds)
+	if err != nil {
+		log.Debug(err.Error())
+		return 0, fmt.Errorf("error loading dataset body: %w", err)
+	}
+	rr, err := dsio.NewEntryReader(ds.Structure, file)
+	if err != nil {
+		file.Close()
+		log.Debug(err.Error())
+		return 0, fmt.Errorf("error loading dataset body: %w", err)
+	}
+	defer rr.Close()
+	entries := 0
+	err = dsio.EachEntry(rr, func(int, dsio.Entry, error) error {
+		entries++
+		return nil
+	})
+	return entries, err
+}
+
+// previewEntries reads runs of entries from a dataset body, each run starting
+// at an entry offset. offsets must be ascending
+func previewEntries(store cafs.Filestore, ds *dataset.Dataset, offsets []int, run int) ([]vals.Value, error) {
+	file, err := LoadBody(store, ds)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error loading dataset body: %w", err)
+	}
+
+	seek := 0
+	if len(offsets) == 1 {
+		seek = offsets[0]
+	}
+	rr, skip, err := seekBody(store, ds, file, seek)
+	if err != nil {
+		file.Close()
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error loading dataset body: %w", err)
+	}
+	defer rr.Close()
+	if len(offsets) == 1 {
+		offsets = []int{skip}
+	}
+
+	vs := make([]vals.Value, 0, len(offsets)*run)
+	for i := 0; len(offsets) > 0; i++ {
+		ent, err := rr.ReadEntry()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			log.Debugw("error reading entry: "+err.Error(), dslog.F("path", ds.BodyPath), dslog.F("index", i))
+			return nil, fmt.Errorf("error reading dataset body: %w", err)
+		}
+		if i < offsets[0] {
+			continue
+		}
+
+		v, err := vals.ConvertDecoded(ent.Value)
+		if err != nil {
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("error decoding entry %d: %w", i, err)
+		}
+		if ent.Key != "" {
+			v = vals.NewObjectValue(ent.Key, v)
+		}
+		vs = append(vs, v)
+		if i+1-offsets[0] == run {
+			offsets = offsets[1:]
+		}
+	}
+	return vs, nil
+}
@@ -0,0 +1,64 @@
+package dsfs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/dataset/vals"
+	"github.com/qri-io/qfs/cafs"
+)
+
+func TestPreview(t *testing.T) {
+	datasets, store, err := makeFilestore()
+	if err != nil {
+		t.Fatalf("error creating test filestore: %s", err.Error())
+	}
+
+	indexed, istore, err := makeFilestoreContext(WithBodyIndex(context.Background(), 2))
+	if err != nil {
+		t.Fatalf("error creating test filestore: %s", err.Error())
+	}
+
+	cases := []struct {
+		description string
+		sample      bool
+		n           int
+		expect      []string
+	}{
+		{"first entries", false, 2, []string{"toronto", "new york"}},
+		{"n past the end", false, 10, []string{"toronto", "new york", "chicago", "chatham", "raleigh"}},
+		{"zero", false, 0, nil},
+		{"sample", true, 2, []string{"toronto", "chicago"}},
+		{"sample three", true, 3, []string{"toronto", "new york", "chatham"}},
+	}
+
+	for _, c := range cases {
+		for _, s := range []struct {
+			name  string
+			store cafs.Filestore
+			path  string
+		}{
+			{"unindexed", store, datasets["cities"]},
+			{"indexed", istore, indexed["cities"]},
+		} {
+			ds, vs, err := Preview(s.store, s.path, c.n, func(cfg *PreviewConfig) { cfg.Sample = c.sample })
+			if err != nil {
+				t.Errorf("%s %s unexpected error: %s", c.description, s.name, err)
+				continue
+			}
+			if ds.Structure == nil {
+				t.Errorf("%s %s expected dataset structure", c.description, s.name)
+			}
+			if len(vs) != len(c.expect) {
+				t.Errorf("%s %s expected %d entries, got: %d", c.description, s.name, len(c.expect), len(vs))
+				continue
+			}
+			for i, v := range vs {
+				city, _ := v.Index(0).(vals.String)
+				if string(city) != c.expect[i] {
+					t.Errorf("%s %s entry %d expected: %s, got: %#v", c.description, s.name, i, c.expect[i], v)
+				}
+			}
+		}
+	}
+}
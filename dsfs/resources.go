@@ -0,0 +1,147 @@
+package dsfs
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// Resource is a transform resource loaded for execution
+type Resource struct {
+	// Key is the resource's key in the transform's Resources map
+	Key string
+	// Dataset is the referenced dataset, with it's structure loaded
+	Dataset *dataset.Dataset
+	// Reader reads the referenced dataset's body. Callers must close it
+	Reader dsio.EntryReader
+}
+
+// ResourceCache keeps loaded resource datasets in memory by path, so
+// resources referenced by many transforms are only loaded once. It's safe for
+// concurrent use
+type ResourceCache struct {
+	store cafs.Filestore
+	lk    sync.Mutex
+	ds    map[string]*dataset.Dataset
+}
+
+// NewResourceCache creates a resource cache for a store
+func NewResourceCache(store cafs.Filestore) *ResourceCache {
+	return &ResourceCache{store: store, ds: map[string]*dataset.Dataset{}}
+}
+
+// Dataset loads the dataset at a path, using a cached copy if there is one
+func (c *ResourceCache) Dataset(path string) (*dataset.Dataset, error) {
+	c.lk.Lock()
+	ds, ok := c.ds[path]
+	c.lk.Unlock()
+	if ok {
+		return ds, nil
+	}
+
+	ds, err := LoadDataset(c.store, path)
+	if err != nil {
+		return nil, err
+	}
+	c.lk.Lock()
+	c.ds[path] = ds
+	c.lk.Unlock()
+	return ds, nil
+}
+
+// LoadResources loads the datasets a transform references, opening each
+// body as an EntryReader. Datasets are loaded in parallel, resources that share
+// a path are loaded once & get their own readers. If loading any resource
+// fails, readers that were opened are closed
+func (c *ResourceCache) LoadResources(t *dataset.Transform) (map[string]*Resource, error) {
+	if t == nil || len(t.Resources) == 0 {
+		return map[string]*Resource{}, nil
+	}
+
+	keys := make([]string, 0, len(t.Resources))
+	paths := map[string]bool{}
+	for key, r := range t.Resources {
+		if r == nil || r.Path == "" {
+			return nil, fmt.Errorf("transform resource %s requires a path to load", key)
+		}
+		keys = append(keys, key)
+		paths[r.Path] = true
+	}
+	sort.Strings(keys)
+
+	var (
+		wg   sync.WaitGroup
+		lk   sync.Mutex
+		errs = map[string]error{}
+	)
+	for path := range paths {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			if _, err := c.Dataset(path); err != nil {
+				lk.Lock()
+				errs[path] = err
+				lk.Unlock()
+			}
+		}(path)
+	}
+	wg.Wait()
+
+	res := map[string]*Resource{}
+	for _, key := range keys {
+		path := t.Resources[key].Path
+		if err := errs[path]; err != nil {
+			closeResources(res)
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("loading resource %s: %w", key, err)
+		}
+		ds, err := c.Dataset(path)
+		if err != nil {
+			closeResources(res)
+			return nil, fmt.Errorf("loading resource %s: %w", key, err)
+		}
+		r, err := openResourceBody(c.store, ds)
+		if err != nil {
+			closeResources(res)
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("opening resource %s body: %w", key, err)
+		}
+		res[key] = &Resource{Key: key, Dataset: ds, Reader: r}
+	}
+	return res, nil
+}
+
+// LoadResources loads the resources referenced by a dataset's transform,
+// see ResourceCache.LoadResources
+func LoadResources(store cafs.Filestore, ds *dataset.Dataset) (map[string]*Resource, error) {
+	if ds.Transform == nil {
+		return map[string]*Resource{}, nil
+	}
+	return NewResourceCache(store).LoadResources(ds.Transform)
+}
+
+func openResourceBody(store cafs.Filestore, ds *dataset.Dataset) (dsio.EntryReader, error) {
+	if ds.Structure == nil {
+		return nil, fmt.Errorf("dataset has no structure")
+	}
+	f, err := LoadBody(store, ds)
+	if err != nil {
+		return nil, err
+	}
+	r, err := dsio.NewEntryReader(ds.Structure, f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func closeResources(res map[string]*Resource) {
+	for _, r := range res {
+		r.Reader.Close()
+	}
+}
@@ -0,0 +1,50 @@
+package dsfs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestLoadResources(t *testing.T) {
+	datasets, store, err := makeFilestore()
+	if err != nil {
+		t.Fatalf("error creating test filestore: %s", err.Error())
+	}
+
+	ds := &dataset.Dataset{
+		Transform: &dataset.Transform{
+			Resources: map[string]*dataset.TransformResource{
+				"a": {Path: datasets["cities"]},
+				"b": {Path: datasets["movies"]},
+				"c": {Path: datasets["cities"]},
+			},
+		},
+	}
+
+	res, err := LoadResources(store, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 3 {
+		t.Fatalf("expected 3 resources, got: %d", len(res))
+	}
+	if res["a"].Dataset != res["c"].Dataset {
+		t.Error("expected resources with the same path to share a dataset")
+	}
+	for key, r := range res {
+		if r.Key != key {
+			t.Errorf("resource %s key mismatch: %s", key, r.Key)
+		}
+		if _, err := r.Reader.ReadEntry(); err != nil {
+			t.Errorf("resource %s read error: %s", key, err)
+		}
+		r.Reader.Close()
+	}
+
+	ds.Transform.Resources["d"] = &dataset.TransformResource{Path: "/map/missing"}
+	if _, err := LoadResources(store, ds); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected missing resource to error with ErrNotFound, got: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package dsfs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/libp2p/go-libp2p-crypto"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// ApplyRetention creates a new version of the dataset at path with every
+// body entry expired by the dataset's meta retention policy dropped. Entries
+// expire relative to Timestamp. It returns the path of the new version & the
+// number of entries dropped. when no entries have expired no version is
+// created, and path is returned as-is
+func ApplyRetention(store cafs.Filestore, path string, pk crypto.PrivKey, pin bool) (string, int, error) {
+	prev, err := LoadDataset(store, path)
+	if err != nil {
+		log.Debug(err.Error())
+		return "", 0, fmt.Errorf("loading dataset: %w", err)
+	}
+	if prev.Meta == nil || prev.Meta.Retention == nil {
+		return "", 0, fmt.Errorf("dataset has no retention policy")
+	}
+	if prev.Structure == nil {
+		return "", 0, fmt.Errorf("dataset has no structure")
+	}
+
+	body, err := LoadBody(store, prev)
+	if err != nil {
+		log.Debug(err.Error())
+		return "", 0, fmt.Errorf("loading body: %w", err)
+	}
+	defer body.Close()
+
+	er, err := dsio.NewEntryReader(prev.Structure, body)
+	if err != nil {
+		return "", 0, err
+	}
+	rr, err := dsio.NewRetentionReader(er, prev.Meta.Retention, Timestamp())
+	if err != nil {
+		return "", 0, err
+	}
+
+	// computed fields are recalculated when the new version is written
+	st := &dataset.Structure{}
+	st.Assign(prev.Structure)
+	st.Path = ""
+	st.Checksum = ""
+	st.Depth = 0
+	st.Entries = 0
+	st.ErrCount = 0
+	st.Length = 0
+	st.ChunkChecksums = nil
+
+	// bodies with chunk checksums get new sums of the same chunk size
+	buf := &bytes.Buffer{}
+	var (
+		out io.Writer = buf
+		cw  *dsio.ChunkChecksumWriter
+	)
+	if prev.Structure.ChunkChecksums != nil {
+		cw = dsio.NewChunkChecksumWriter(buf, prev.Structure.ChunkChecksums.ChunkSize)
+		out = cw
+	}
+	w, err := dsio.NewEntryWriter(st, out)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := dsio.Copy(rr, w); err != nil {
+		log.Debug(err.Error())
+		return "", 0, fmt.Errorf("dropping expired entries: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		log.Debug(err.Error())
+		return "", 0, fmt.Errorf("dropping expired entries: %w", err)
+	}
+	if rr.Dropped() == 0 {
+		return path, 0, nil
+	}
+	if cw != nil {
+		st.ChunkChecksums = cw.Checksums()
+	}
+
+	ds := &dataset.Dataset{
+		Peername:     prev.Peername,
+		Name:         prev.Name,
+		ProfileID:    prev.ProfileID,
+		Meta:         prev.Meta,
+		Structure:    st,
+		Transform:    prev.Transform,
+		Viz:          prev.Viz,
+		PreviousPath: prev.Path,
+		Commit: &dataset.Commit{
+			Title:   "drop entries expired by retention policy",
+			Message: fmt.Sprintf("dropped %d entries older than %s by %s", rr.Dropped(), prev.Meta.Retention.MaxAge, prev.Meta.Retention.Column),
+		},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body."+st.Format, buf.Bytes()))
+
+	newPath, err := CreateDataset(store, ds, prev, pk, pin, false, false)
+	if err != nil {
+		return "", 0, err
+	}
+	return newPath, rr.Dropped(), nil
+}
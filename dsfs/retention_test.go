@@ -0,0 +1,124 @@
+package dsfs
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-crypto"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+func TestApplyRetention(t *testing.T) {
+	prevTs := Timestamp
+	defer func() { Timestamp = prevTs }()
+	Timestamp = func() time.Time { return time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC) }
+
+	store := cafs.NewMapstore()
+	privKey, err := crypto.UnmarshalPrivateKey(testPk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := &dataset.Dataset{
+		Commit: &dataset.Commit{Title: "initial"},
+		Meta: &dataset.Meta{
+			Title:     "visits",
+			Retention: &dataset.Retention{Column: "visited", MaxAge: "P30D"},
+		},
+		Structure: &dataset.Structure{
+			Format:       "csv",
+			FormatConfig: map[string]interface{}{"headerRow": true},
+			Schema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "array",
+					"items": []interface{}{
+						map[string]interface{}{"title": "visitor", "type": "string"},
+						map[string]interface{}{"title": "visited", "type": "string"},
+					},
+				},
+			},
+		},
+	}
+	body := "visitor,visited\na,2020-01-01\nb,2020-03-20\nc,2020-02-01\n"
+	cw := dsio.NewChunkChecksumWriter(ioutil.Discard, 16)
+	cw.Write([]byte(body))
+	ds.Structure.ChunkChecksums = cw.Checksums()
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte(body)))
+	path, err := CreateDataset(store, ds, nil, privKey, false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newPath, dropped, err := ApplyRetention(store, path, privKey, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped != 2 {
+		t.Errorf("expected 2 dropped entries, got: %d", dropped)
+	}
+	if newPath == path {
+		t.Fatal("expected a new version")
+	}
+
+	got, err := LoadDataset(store, newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PreviousPath != path {
+		t.Errorf("previous path mismatch. expected: %s, got: %s", path, got.PreviousPath)
+	}
+	if got.Structure.Entries != 1 {
+		t.Errorf("expected 1 entry, got: %d", got.Structure.Entries)
+	}
+	if got.Commit.Title != "drop entries expired by retention policy" {
+		t.Errorf("commit title mismatch, got: %s", got.Commit.Title)
+	}
+	if got.Meta.Retention == nil || got.Meta.Retention.MaxAge != "P30D" {
+		t.Errorf("expected retention policy to be kept, got: %v", got.Meta.Retention)
+	}
+	f, err := LoadBody(store, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "visitor,visited\nb,2020-03-20\n"; string(data) != expect {
+		t.Errorf("body mismatch. expected:\n%s\ngot:\n%s", expect, string(data))
+	}
+
+	// nothing left to expire
+	again, dropped, err := ApplyRetention(store, newPath, privKey, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != newPath || dropped != 0 {
+		t.Errorf("expected no new version, got: %s, %d dropped", again, dropped)
+	}
+}
+
+func TestApplyRetentionNoPolicy(t *testing.T) {
+	store := cafs.NewMapstore()
+	privKey, err := crypto.UnmarshalPrivateKey(testPk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := &dataset.Dataset{
+		Commit:    &dataset.Commit{Title: "initial"},
+		Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[1,2,3]`)))
+	path, err := CreateDataset(store, ds, nil, privKey, false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ApplyRetention(store, path, privKey, false); err == nil || err.Error() != "dataset has no retention policy" {
+		t.Errorf("expected a missing policy error, got: %v", err)
+	}
+}
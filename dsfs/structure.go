@@ -12,23 +12,26 @@ func SaveStructure(store cafs.Filestore, s *dataset.Structure, pin bool) (path s
 	file, err := JSONFile(PackageFileStructure.String(), s)
 	if err != nil {
 		log.Debug(err.Error())
-		return "", fmt.Errorf("error saving json structure file: %s", err.Error())
+		return "", fmt.Errorf("error saving json structure file: %w", err)
 	}
 	return store.Put(file, pin)
 }
 
 // LoadStructure loads a structure from a given path in a store
 func LoadStructure(store cafs.Filestore, path string) (st *dataset.Structure, err error) {
+	if path, err = normalizePath(path); err != nil {
+		return nil, err
+	}
 	path = PackageFilepath(store, path, PackageFileStructure)
 	return loadStructure(store, path)
 }
 
 // loadStructure assumes path is valid
 func loadStructure(store cafs.Filestore, path string) (st *dataset.Structure, err error) {
-	data, err := fileBytes(store.Get(path))
+	data, err := fileBytes(getFile(store, path))
 	if err != nil {
 		log.Debug(err.Error())
-		return nil, fmt.Errorf("error loading structure file: %s", err.Error())
+		return nil, fmt.Errorf("error loading structure file: %w", err)
 	}
 	return dataset.UnmarshalStructure(data)
 }
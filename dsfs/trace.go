@@ -0,0 +1,53 @@
+package dsfs
+
+import (
+	"context"
+
+	"github.com/libp2p/go-libp2p-crypto"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dstrace"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// LoadDatasetContext is LoadDataset, traced if ctx carries a dstrace.Tracer
+func LoadDatasetContext(ctx context.Context, store cafs.Filestore, path string) (*dataset.Dataset, error) {
+	ctx, span := dstrace.StartSpan(ctx, "dsfs.LoadDataset", dstrace.Attr("dataset.path", path))
+	ds, err := loadDataset(ctx, store, path)
+	if err == nil {
+		span.SetAttributes(datasetAttrs(ds)...)
+	}
+	span.End(err)
+	return ds, err
+}
+
+// WriteDatasetContext is WriteDataset, traced if ctx carries a dstrace.Tracer
+func WriteDatasetContext(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset, pin bool) (string, error) {
+	ctx, span := dstrace.StartSpan(ctx, "dsfs.WriteDataset", datasetAttrs(ds)...)
+	path, err := writeDataset(ctx, store, ds, pin)
+	span.SetAttributes(dstrace.Attr("dataset.path", path))
+	span.End(err)
+	return path, err
+}
+
+// CreateDatasetContext is CreateDataset, traced if ctx carries a
+// dstrace.Tracer. Commit timestamps are read from a clock set with WithClock,
+// and zeroed if ctx is set WithReproducible
+func CreateDatasetContext(ctx context.Context, store cafs.Filestore, ds, dsPrev *dataset.Dataset, pk crypto.PrivKey, pin, force, shouldRender bool) (string, error) {
+	ctx, span := dstrace.StartSpan(ctx, "dsfs.CreateDataset")
+	path, err := createDataset(ctx, store, ds, dsPrev, pk, pin, force, shouldRender)
+	span.SetAttributes(append(datasetAttrs(ds), dstrace.Attr("dataset.path", path))...)
+	span.End(err)
+	return path, err
+}
+
+// datasetAttrs describes a dataset for tracing
+func datasetAttrs(ds *dataset.Dataset) []dstrace.Attribute {
+	if ds == nil || ds.Structure == nil {
+		return nil
+	}
+	return []dstrace.Attribute{
+		dstrace.Attr("structure.format", ds.Structure.Format),
+		dstrace.Attr("structure.entries", ds.Structure.Entries),
+		dstrace.Attr("structure.length", ds.Structure.Length),
+	}
+}
@@ -0,0 +1,64 @@
+package dsfs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dstrace"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+func TestTracedWriteLoad(t *testing.T) {
+	store := cafs.NewMapstore()
+	rec := &dstrace.Recorder{}
+	ctx := WithBodyIndex(dstrace.WithTracer(context.Background(), rec), 1)
+
+	ds := &dataset.Dataset{
+		Meta:      &dataset.Meta{Title: "traced"},
+		Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray, Entries: 2},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[1,2]`)))
+	path, err := WriteDatasetContext(ctx, store, ds, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDatasetContext(ctx, store, path); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := rec.Spans()
+	if len(spans) != 5 {
+		t.Fatalf("expected 5 spans, got: %d", len(spans))
+	}
+	if spans[0].Name != "dsfs.WriteDataset" || spans[0].Attrs["dataset.path"] != path || spans[0].Attrs["structure.entries"] != 2 {
+		t.Errorf("unexpected write span: %#v", spans[0])
+	}
+	if spans[2].Name != "dsfs.LoadDataset" || spans[2].Attrs["structure.format"] != "json" || !spans[2].Ended {
+		t.Errorf("unexpected load span: %#v", spans[2])
+	}
+	// callees are traced as children
+	children := []struct {
+		span   *dstrace.RecordedSpan
+		name   string
+		parent *dstrace.RecordedSpan
+	}{
+		{spans[1], "dsfs.IndexBody", spans[0]},
+		{spans[3], "dsfs.LoadDatasetRefs", spans[2]},
+		{spans[4], "dsfs.DerefDataset", spans[2]},
+	}
+	for _, c := range children {
+		s := c.span
+		if s.Name != c.name || s.Parent != c.parent || !s.Ended {
+			t.Errorf("unexpected child span. expected %s of %s, got: %#v", c.name, c.parent.Name, s)
+		}
+	}
+
+	if _, err := LoadDatasetContext(ctx, store, "/map/missing"); err == nil {
+		t.Fatal("expected missing dataset to error")
+	}
+	if s := rec.Spans()[5]; s.Err == nil {
+		t.Error("expected failed load span to record error")
+	}
+}
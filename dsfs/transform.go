@@ -10,16 +10,19 @@ import (
 
 // LoadTransform loads a transform from a given path in a store
 func LoadTransform(store cafs.Filestore, path string) (q *dataset.Transform, err error) {
+	if path, err = normalizePath(path); err != nil {
+		return nil, err
+	}
 	path = PackageFilepath(store, path, PackageFileTransform)
 	return loadTransform(store, path)
 }
 
 // loadTransform assumes the provided path is correct
 func loadTransform(store cafs.Filestore, path string) (q *dataset.Transform, err error) {
-	data, err := fileBytes(store.Get(path))
+	data, err := fileBytes(getFile(store, path))
 	if err != nil {
 		log.Debug(err.Error())
-		return nil, fmt.Errorf("error loading transform raw data: %s", err.Error())
+		return nil, fmt.Errorf("error loading transform raw data: %w", err)
 	}
 
 	return dataset.UnmarshalTransform(data)
@@ -36,7 +39,7 @@ func SaveTransform(store cafs.Filestore, q *dataset.Transform, pin bool) (path s
 	tf, err := JSONFile(PackageFileTransform.String(), save)
 	if err != nil {
 		log.Debug(err.Error())
-		return "", fmt.Errorf("error marshaling transform data to json: %s", err.Error())
+		return "", fmt.Errorf("error marshaling transform data to json: %w", err)
 	}
 
 	return store.Put(tf, pin)
@@ -58,5 +61,5 @@ func LoadTransformScript(store cafs.Filestore, dspath string) (qfs.File, error)
 		return nil, ErrNoTransform
 	}
 
-	return store.Get(ds.Transform.ScriptPath)
+	return getFile(store, ds.Transform.ScriptPath)
 }
@@ -0,0 +1,114 @@
+package dsfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/multiformats/go-multihash"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+type verifyBodiesKey struct{}
+
+// WithVerifiedBodies gives a context that has LoadBodyContext,
+// LoadNamedBodyContext & LoadRowsContext check bodies against the checksum &
+// length recorded in their structure as they're read. A mismatch is returned
+// by Read as a *ChecksumError in place of io.EOF. Bodies that aren't read to
+// the end can only be checked for being too long
+func WithVerifiedBodies(ctx context.Context) context.Context {
+	return context.WithValue(ctx, verifyBodiesKey{}, true)
+}
+
+// verifyBodies reads if bodies are checked, see WithVerifiedBodies
+func verifyBodies(ctx context.Context) bool {
+	verify, _ := ctx.Value(verifyBodiesKey{}).(bool)
+	return verify
+}
+
+// ChecksumError reports a body that doesn't match it's structure
+type ChecksumError struct {
+	Path string
+	// Expected & Got are base58 multihash checksums, empty when a length
+	// mismatch is found first
+	Expected, Got string
+	// ExpectedLength & Length are body lengths in bytes. Length is the bytes
+	// read when the error was found
+	ExpectedLength, Length int64
+}
+
+// Error implements the error interface
+func (e *ChecksumError) Error() string {
+	if e.Expected != e.Got {
+		return fmt.Sprintf("body %s checksum mismatch. expected: %s, got: %s", e.Path, e.Expected, e.Got)
+	}
+	return fmt.Sprintf("body %s length mismatch. expected: %d bytes, got: %d", e.Path, e.ExpectedLength, e.Length)
+}
+
+// VerifyBody wraps a body file, checking it against the checksum & length of
+// st as it's read. See WithVerifiedBodies
+func VerifyBody(file qfs.File, st *dataset.Structure) qfs.File {
+	if st == nil || (st.Checksum == "" && st.Length == 0) {
+		return file
+	}
+	return &verifiedFile{File: file, st: st, hash: sha256.New()}
+}
+
+// verifiedFile hashes a file as it's read. it deliberately doesn't implement
+// io.Seeker, so skipped bytes are still hashed
+type verifiedFile struct {
+	qfs.File
+	st   *dataset.Structure
+	hash hash.Hash
+	read int64
+	err  error
+}
+
+// Read implements the io.Reader interface
+func (f *verifiedFile) Read(p []byte) (int, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	n, err := f.File.Read(p)
+	f.hash.Write(p[:n])
+	f.read += int64(n)
+
+	if f.st.Length > 0 && f.read > int64(f.st.Length) {
+		f.err = &ChecksumError{Path: f.FullPath(), ExpectedLength: int64(f.st.Length), Length: f.read}
+		return n, f.err
+	}
+	if err == io.EOF {
+		if f.err = f.verify(); f.err != nil {
+			return n, f.err
+		}
+		f.err = io.EOF
+	}
+	return n, err
+}
+
+// verify checks a fully read body
+func (f *verifiedFile) verify() error {
+	if f.st.Length > 0 && f.read != int64(f.st.Length) {
+		return &ChecksumError{Path: f.FullPath(), ExpectedLength: int64(f.st.Length), Length: f.read}
+	}
+	if f.st.Checksum == "" {
+		return nil
+	}
+	sum, err := multihash.Encode(f.hash.Sum(nil), multihash.SHA2_256)
+	if err != nil {
+		return err
+	}
+	if got := multihash.Multihash(sum).B58String(); got != f.st.Checksum {
+		return &ChecksumError{
+			Path:           f.FullPath(),
+			Expected:       f.st.Checksum,
+			Got:            got,
+			ExpectedLength: int64(f.st.Length),
+			Length:         f.read,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package dsfs
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/multiformats/go-multihash"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestVerifyBody(t *testing.T) {
+	data := []byte("a,b\n1,2\n")
+	sum, err := multihash.Sum(data, multihash.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		st          *dataset.Structure
+		err         string
+	}{
+		{"match", &dataset.Structure{Checksum: sum.B58String(), Length: len(data)}, ""},
+		{"no checksum", &dataset.Structure{}, ""},
+		{"length only", &dataset.Structure{Length: len(data)}, ""},
+		{"checksum mismatch", &dataset.Structure{Checksum: "QmFoo", Length: len(data)}, "body /body.csv checksum mismatch. expected: QmFoo, got: " + sum.B58String()},
+		{"too short", &dataset.Structure{Checksum: sum.B58String(), Length: len(data) + 1}, "body /body.csv length mismatch. expected: 9 bytes, got: 8"},
+		{"too long", &dataset.Structure{Checksum: sum.B58String(), Length: 4}, "body /body.csv length mismatch. expected: 4 bytes, got: 8"},
+	}
+
+	for _, c := range cases {
+		f := VerifyBody(qfs.NewMemfileBytes("/body.csv", data), c.st)
+		got, err := ioutil.ReadAll(f)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("%s error mismatch. expected: '%s', got: '%v'", c.description, c.err, err)
+			continue
+		}
+		if err != nil {
+			var cerr *ChecksumError
+			if !errors.As(err, &cerr) {
+				t.Errorf("%s expected a *ChecksumError, got: %T", c.description, err)
+			}
+			continue
+		}
+		if string(got) != string(data) {
+			t.Errorf("%s data mismatch. expected: %q, got: %q", c.description, data, got)
+		}
+	}
+}
+
+func TestLoadRowsVerified(t *testing.T) {
+	datasets, store, err := makeFilestore()
+	if err != nil {
+		t.Fatalf("error creating test filestore: %s", err.Error())
+	}
+	ds, err := LoadDataset(store, datasets["cities"])
+	if err != nil {
+		t.Fatalf("error loading dataset: %s", err.Error())
+	}
+
+	ctx := WithVerifiedBodies(context.Background())
+
+	ds.Structure.Checksum = ""
+	ds.Structure.Length = 0
+	if _, err := LoadRowsContext(ctx, store, ds, 0, 0); err != nil {
+		t.Fatalf("unexpected error loading unchecksummed body: %s", err)
+	}
+
+	ds.Structure.Checksum = "QmFoo"
+	_, err = LoadRowsContext(ctx, store, ds, 0, 0)
+	var cerr *ChecksumError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("expected a checksum error, got: %v", err)
+	}
+	if cerr.Expected != "QmFoo" {
+		t.Errorf("expected checksum QmFoo, got: %s", cerr.Expected)
+	}
+
+	if _, err := LoadRows(store, ds, 0, 0); err != nil {
+		t.Errorf("expected loading without verification to ignore the checksum, got: %s", err)
+	}
+}
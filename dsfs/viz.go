@@ -13,23 +13,26 @@ func SaveViz(store cafs.Filestore, v *dataset.Viz, pin bool) (path string, err e
 	file, err := JSONFile(PackageFileViz.String(), v)
 	if err != nil {
 		log.Debug(err.Error())
-		return "", fmt.Errorf("error saving json viz file: %s", err.Error())
+		return "", fmt.Errorf("error saving json viz file: %w", err)
 	}
 	return store.Put(file, pin)
 }
 
 // LoadViz loads a viz from a given path in a store
 func LoadViz(store cafs.Filestore, path string) (st *dataset.Viz, err error) {
+	if path, err = normalizePath(path); err != nil {
+		return nil, err
+	}
 	path = PackageFilepath(store, path, PackageFileViz)
 	return loadViz(store, path)
 }
 
 // loadViz assumes the provided path is valid
 func loadViz(store cafs.Filestore, path string) (st *dataset.Viz, err error) {
-	data, err := fileBytes(store.Get(path))
+	data, err := fileBytes(getFile(store, path))
 	if err != nil {
 		log.Debug(err.Error())
-		return nil, fmt.Errorf("error loading viz file: %s", err.Error())
+		return nil, fmt.Errorf("error loading viz file: %w", err)
 	}
 	return dataset.UnmarshalViz(data)
 }
@@ -49,5 +52,5 @@ func LoadVizScript(store cafs.Filestore, dspath string) (qfs.File, error) {
 		return nil, ErrNoViz
 	}
 
-	return store.Get(ds.Viz.ScriptPath)
+	return getFile(store, ds.Viz.ScriptPath)
 }
@@ -0,0 +1,277 @@
+// Package dshttp provides http.Handlers for serving datasets from a store:
+// the dataset document, pages of the body in a negotiated format & the
+// rendered viz, so hosts don't each implement the same endpoints
+package dshttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/dslog"
+	"github.com/qri-io/qfs/cafs"
+)
+
+var log = dslog.NewPackage("dshttp")
+
+// SetLogger sets the logger for package dshttp. By default nothing is logged
+func SetLogger(l dslog.Logger) {
+	log.SetLogger(l)
+}
+
+// DefaultLimit is the number of body entries served when a request doesn't
+// set a limit & Handlers.DefaultLimit is zero
+const DefaultLimit = 100
+
+// Handlers creates handlers that serve datasets from Store
+type Handlers struct {
+	Store cafs.Filestore
+	// Path gives the store path of the dataset a request asks for, read from
+	// a url parameter or route variable. Errors are served as bad requests
+	Path func(r *http.Request) (string, error)
+	// DefaultLimit is the number of body entries served when a request
+	// doesn't set a limit, defaulting to DefaultLimit
+	DefaultLimit int
+	// MaxLimit caps the number of body entries served per request, zero
+	// allows any limit
+	MaxLimit int
+}
+
+// Dataset serves the dataset document as json
+func (h *Handlers) Dataset() http.Handler {
+	return h.handler(func(w http.ResponseWriter, r *http.Request, ds *dataset.Dataset) {
+		data, err := json.Marshal(ds)
+		if err != nil {
+			log.Debug(err.Error())
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", dataset.JSONDataFormat.MIMEType())
+		w.Write(data)
+	})
+}
+
+// Body serves a page of the body. The limit & offset url parameters select
+// the page. The format parameter or the Accept header choose the format the
+// page is encoded in, defaulting to the body's format & format config. A
+// limit of -1 requests all entries. Pages are encoded before they're
+// written, so errors reading the body are served with an error status
+func (h *Handlers) Body() http.Handler {
+	return h.handler(func(w http.ResponseWriter, r *http.Request, ds *dataset.Dataset) {
+		limit, offset, err := h.page(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		format, err := negotiateFormat(r, ds.Structure.DataFormat())
+		if err != nil {
+			writeError(w, http.StatusNotAcceptable, err)
+			return
+		}
+
+		file, err := dsfs.LoadBody(h.Store, ds)
+		if err != nil {
+			log.Debug(err.Error())
+			writeError(w, statusFor(err), fmt.Errorf("error loading body: %w", err))
+			return
+		}
+		er, err := dsio.NewEntryReader(ds.Structure, file)
+		if err != nil {
+			file.Close()
+			log.Debug(err.Error())
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		defer er.Close()
+
+		// bodies served in their stored format keep it's configuration
+		var opts dataset.FormatConfig
+		if format == ds.Structure.DataFormat() && ds.Structure.FormatConfig != nil {
+			if opts, err = dataset.ParseFormatConfigMap(format, ds.Structure.FormatConfig); err != nil {
+				log.Debug(err.Error())
+				opts = nil
+			}
+		}
+
+		buf := &bytes.Buffer{}
+		pr := &dsio.PagedReader{Reader: er, Limit: limit, Offset: offset}
+		if err := dsio.WriteDatasetFormat(ds, pr, format, opts, buf); err != nil {
+			log.Debug(err.Error())
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", format.MIMEType())
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		io.Copy(w, buf)
+	})
+}
+
+// Viz serves the rendered viz as html
+func (h *Handlers) Viz() http.Handler {
+	return h.handler(func(w http.ResponseWriter, r *http.Request, ds *dataset.Dataset) {
+		if ds.Viz == nil || ds.Viz.RenderedPath == "" {
+			writeError(w, http.StatusNotFound, fmt.Errorf("dataset has no rendered viz"))
+			return
+		}
+		file, err := h.Store.Get(ds.Viz.RenderedPath)
+		if err != nil {
+			log.Debug(err.Error())
+			writeError(w, statusFor(err), fmt.Errorf("error loading rendered viz: %w", err))
+			return
+		}
+		defer file.Close()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.Copy(w, file)
+	})
+}
+
+// handler loads the dataset a request asks for & passes it to serve. Only
+// GET & HEAD requests are allowed
+func (h *Handlers) handler(serve func(w http.ResponseWriter, r *http.Request, ds *dataset.Dataset)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		path, err := h.Path(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		ds, err := dsfs.LoadDataset(h.Store, path)
+		if err != nil {
+			log.Debug(err.Error())
+			writeError(w, statusFor(err), err)
+			return
+		}
+		if ds.Structure == nil {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("dataset has no structure"))
+			return
+		}
+		serve(w, r, ds)
+	})
+}
+
+// page reads the limit & offset url parameters of a request
+func (h *Handlers) page(r *http.Request) (limit, offset int, err error) {
+	limit = h.DefaultLimit
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if h.MaxLimit > 0 && limit > h.MaxLimit {
+		limit = h.MaxLimit
+	}
+	q := r.URL.Query()
+	if s := q.Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil || limit < -1 {
+			return 0, 0, fmt.Errorf("invalid limit: %s", s)
+		}
+	}
+	if s := q.Get("offset"); s != "" {
+		if offset, err = strconv.Atoi(s); err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("invalid offset: %s", s)
+		}
+	}
+	if h.MaxLimit > 0 && (limit < 0 || limit > h.MaxLimit) {
+		return 0, 0, fmt.Errorf("limit can't be more than %d", h.MaxLimit)
+	}
+	return limit, offset, nil
+}
+
+// readOnlyFormats are formats entries can't be written in
+var readOnlyFormats = map[dataset.DataFormat]bool{
+	dataset.XLSDataFormat:       true,
+	dataset.GeoJSONDataFormat:   true,
+	dataset.ShapefileDataFormat: true,
+}
+
+// negotiateFormat picks the format of a body response from the format url
+// parameter, or the most preferred media type of the Accept header that
+// names a writable format. Bodies stored in read-only formats are served as
+// json when any format is acceptable
+func negotiateFormat(r *http.Request, stored dataset.DataFormat) (dataset.DataFormat, error) {
+	if readOnlyFormats[stored] {
+		stored = dataset.JSONDataFormat
+	}
+	if s := r.URL.Query().Get("format"); s != "" {
+		f, err := dataset.ParseDataFormatString(s)
+		if err != nil || readOnlyFormats[f] {
+			return dataset.UnknownDataFormat, fmt.Errorf("can't serve body as %s", s)
+		}
+		return f, nil
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return stored, nil
+	}
+	for _, mt := range acceptedTypes(accept) {
+		switch mt {
+		case "*/*", "application/*", "text/*":
+			if mt == "*/*" || strings.HasPrefix(stored.MIMEType(), strings.TrimSuffix(mt, "*")) {
+				return stored, nil
+			}
+		default:
+			if f, err := dataset.ParseMIME(mt); err == nil && !readOnlyFormats[f] {
+				return f, nil
+			}
+		}
+	}
+	return dataset.UnknownDataFormat, fmt.Errorf("no acceptable format for: %s", accept)
+}
+
+// acceptedTypes gives the media types of an Accept header, most preferred
+// first. types with a quality of zero are dropped
+func acceptedTypes(accept string) []string {
+	type accepted struct {
+		mt string
+		q  float64
+	}
+	var types []accepted
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if s, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(s, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			types = append(types, accepted{mt, q})
+		}
+	}
+	sort.SliceStable(types, func(i, j int) bool { return types[i].q > types[j].q })
+
+	mts := make([]string, len(types))
+	for i, t := range types {
+		mts[i] = t.mt
+	}
+	return mts
+}
+
+// statusFor gives the response status of a store error
+func statusFor(err error) int {
+	if errors.Is(err, dsfs.ErrNotFound) || errors.Is(err, cafs.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	http.Error(w, err.Error(), status)
+}
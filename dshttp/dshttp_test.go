@@ -0,0 +1,163 @@
+package dshttp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+func testHandlers(t *testing.T) (*Handlers, string) {
+	ds := &dataset.Dataset{
+		Structure: &dataset.Structure{
+			Format:       "csv",
+			FormatConfig: map[string]interface{}{"headerRow": true},
+			Schema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "array",
+					"items": []interface{}{
+						map[string]interface{}{"title": "movie", "type": "string"},
+						map[string]interface{}{"title": "year", "type": "integer"},
+					},
+				},
+			},
+		},
+		Viz: &dataset.Viz{
+			ScriptPath:  "viz_script",
+			ScriptBytes: []byte("<html>template</html>\n"),
+		},
+	}
+	ds.Viz.OpenScriptFile(nil)
+	ds.Viz.SetRenderedFile(qfs.NewMemfileBytes("index.html", []byte("<html>rendered</html>\n")))
+	ds.SetBodyFile(qfs.NewMemfileBytes("movies.csv", []byte("movie,year\nup,2009\nthe incredibles,2004\ncoco,2017\n")))
+
+	store := cafs.NewMapstore()
+	path, err := dsfs.WriteDataset(store, ds, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &Handlers{
+		Store: store,
+		Path: func(r *http.Request) (string, error) {
+			p := r.URL.Query().Get("path")
+			if p == "" {
+				return "", fmt.Errorf("path is required")
+			}
+			return p, nil
+		},
+		MaxLimit: 10,
+	}
+	return h, path
+}
+
+func get(t *testing.T, h http.Handler, url, accept string) (int, string, string) {
+	req := httptest.NewRequest("GET", url, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	data, err := ioutil.ReadAll(rec.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, rec.Header().Get("Content-Type"), string(data)
+}
+
+func TestDatasetHandler(t *testing.T) {
+	h, path := testHandlers(t)
+	status, ct, body := get(t, h.Dataset(), "/?path="+path, "")
+	if status != http.StatusOK || ct != "application/json" {
+		t.Fatalf("expected json dataset, got: %d %s %s", status, ct, body)
+	}
+	if !strings.Contains(body, `"structure"`) {
+		t.Errorf("expected a dataset document, got: %s", body)
+	}
+
+	if status, _, _ := get(t, h.Dataset(), "/", ""); status != http.StatusBadRequest {
+		t.Errorf("expected bad request without a path, got: %d", status)
+	}
+	if status, _, _ := get(t, h.Dataset(), "/?path=/map/QmMissing", ""); status != http.StatusNotFound {
+		t.Errorf("expected not found for a missing dataset, got: %d", status)
+	}
+
+	req := httptest.NewRequest("POST", "/?path="+path, nil)
+	rec := httptest.NewRecorder()
+	h.Dataset().ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("expected method not allowed, got: %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestBodyHandler(t *testing.T) {
+	h, path := testHandlers(t)
+	cases := []struct {
+		query  string
+		accept string
+		status int
+		ct     string
+		body   string
+	}{
+		{"", "", 200, "text/csv", "movie,year\nup,2009\nthe incredibles,2004\ncoco,2017\n"},
+		{"&limit=1&offset=1", "", 200, "text/csv", "movie,year\nthe incredibles,2004\n"},
+		{"&limit=-1&offset=2", "", 400, "", ""},
+		{"&limit=11", "", 400, "", ""},
+		{"&offset=-1", "", 400, "", ""},
+		{"&limit=2", "application/json", 200, "application/json", `[["up",2009],["the incredibles",2004]]`},
+		{"&limit=1", "text/html;q=0.5, application/x-ndjson", 200, "application/x-ndjson", "[\"up\",2009]\n"},
+		{"&limit=1", "application/vnd.ms-excel, text/*;q=0.2", 200, "text/csv", "movie,year\nup,2009\n"},
+		{"&limit=1&format=ndjson", "text/csv", 200, "application/x-ndjson", "[\"up\",2009]\n"},
+		{"&format=xls", "", 406, "", ""},
+		{"", "image/png", 406, "", ""},
+	}
+	for i, c := range cases {
+		status, ct, body := get(t, h.Body(), "/?path="+path+c.query, c.accept)
+		if status != c.status {
+			t.Errorf("case %d: expected status %d, got: %d %s", i, c.status, status, body)
+			continue
+		}
+		if c.status != 200 {
+			continue
+		}
+		if ct != c.ct {
+			t.Errorf("case %d: expected content type %s, got: %s", i, c.ct, ct)
+		}
+		if strings.TrimSpace(body) != strings.TrimSpace(c.body) {
+			t.Errorf("case %d: body mismatch.\nexpected: %q\ngot:      %q", i, c.body, body)
+		}
+	}
+}
+
+func TestVizHandler(t *testing.T) {
+	h, path := testHandlers(t)
+	status, ct, body := get(t, h.Viz(), "/?path="+path, "")
+	if status != http.StatusOK || ct != "text/html; charset=utf-8" || body != "<html>rendered</html>\n" {
+		t.Errorf("expected rendered viz, got: %d %s %q", status, ct, body)
+	}
+
+	ds := &dataset.Dataset{Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("[1]")))
+	noViz, err := dsfs.WriteDataset(h.Store, ds, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, _, _ := get(t, h.Viz(), "/?path="+noViz, ""); status != http.StatusNotFound {
+		t.Errorf("expected not found without a viz, got: %d", status)
+	}
+}
+
+func TestAcceptedTypes(t *testing.T) {
+	got := acceptedTypes("text/html;q=0.1, application/json, text/csv;q=0.9, image/png;q=0, bad/;x")
+	expect := []string{"application/json", "text/csv", "text/html"}
+	if strings.Join(got, ",") != strings.Join(expect, ",") {
+		t.Errorf("expected %v, got: %v", expect, got)
+	}
+}
@@ -0,0 +1,116 @@
+package dsio
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/qri-io/dataset"
+)
+
+// ArrowType names an apache arrow logical type
+type ArrowType string
+
+const (
+	// ArrowNull is the arrow null type, used for columns with no type
+	ArrowNull ArrowType = "null"
+	// ArrowBool is the arrow boolean type
+	ArrowBool ArrowType = "bool"
+	// ArrowInt64 is the arrow signed 64-bit integer type
+	ArrowInt64 ArrowType = "int64"
+	// ArrowFloat64 is the arrow double-precision float type
+	ArrowFloat64 ArrowType = "float64"
+	// ArrowUTF8 is the arrow variable-length string type
+	ArrowUTF8 ArrowType = "utf8"
+	// ArrowList is the arrow variable-length list type
+	ArrowList ArrowType = "list"
+	// ArrowStruct is the arrow struct type
+	ArrowStruct ArrowType = "struct"
+)
+
+// ArrowField describes a column in an arrow schema. List fields have a single
+// child describing list elements, struct fields have one child per property
+type ArrowField struct {
+	Name     string       `json:"name"`
+	Type     ArrowType    `json:"type"`
+	Nullable bool         `json:"nullable"`
+	Children []ArrowField `json:"children,omitempty"`
+}
+
+// ArrowSchema converts a structure's schema to a list of arrow fields, one
+// per column. The structure must describe tabular data: an array of arrays
+// with column definitions, or an array of objects with properties
+func ArrowSchema(st *dataset.Structure) ([]ArrowField, error) {
+	if st == nil || st.Schema == nil {
+		return nil, fmt.Errorf("structure has no schema")
+	}
+	if st.Schema["type"] != "array" {
+		return nil, fmt.Errorf("arrow schemas require a top-level array type")
+	}
+	items, ok := st.Schema["items"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("arrow schemas require item definitions")
+	}
+
+	switch items["type"] {
+	case "array":
+		cols, ok := items["items"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("arrow schemas require column definitions")
+		}
+		fields := make([]ArrowField, len(cols))
+		for i, c := range cols {
+			col, _ := c.(map[string]interface{})
+			name, _ := col["title"].(string)
+			if name == "" {
+				name = dataset.AbstractColumnName(i)
+			}
+			fields[i] = arrowField(name, col)
+		}
+		return fields, nil
+	case "object":
+		return arrowField("", items).Children, nil
+	}
+	return nil, fmt.Errorf("arrow schemas require array or object items")
+}
+
+// arrowField maps a json schema definition to an arrow field
+func arrowField(name string, sch map[string]interface{}) ArrowField {
+	f := ArrowField{Name: name, Nullable: true}
+	switch sch["type"] {
+	case "boolean":
+		f.Type = ArrowBool
+	case "integer":
+		f.Type = ArrowInt64
+	case "number":
+		f.Type = ArrowFloat64
+	case "string":
+		f.Type = ArrowUTF8
+	case "array":
+		f.Type = ArrowList
+		items, _ := sch["items"].(map[string]interface{})
+		f.Children = []ArrowField{arrowField("item", items)}
+	case "object":
+		f.Type = ArrowStruct
+		props, _ := sch["properties"].(map[string]interface{})
+		for _, key := range sortedKeys(props) {
+			p, _ := props[key].(map[string]interface{})
+			f.Children = append(f.Children, arrowField(key, p))
+		}
+	default:
+		// untyped or multi-typed values are represented as strings
+		f.Type = ArrowUTF8
+		if sch == nil {
+			f.Type = ArrowNull
+		}
+	}
+	return f
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,321 @@
+package dsio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// arrow IPC message metadata is serialized as flatbuffers. fbTable & friends
+// model just enough of flatbuffers to read & write arrow messages without
+// generated code. see https://flatbuffers.dev/internals/
+
+// fbField is a flatbuffer table field in vtable slot. v is one of uint8,
+// bool, int16, int32, int64, string, fbTable, fbTables or fbStructs
+type fbField struct {
+	slot int
+	v    interface{}
+}
+
+// fbTable is a flatbuffer table to write
+type fbTable []fbField
+
+// fbTables is a vector of tables
+type fbTables []fbTable
+
+// fbStructs is a vector of 8-byte aligned structs, each element encoded as
+// bytes
+type fbStructs [][]byte
+
+var errFlatbufCorrupt = fmt.Errorf("corrupt flatbuffer data")
+
+// fbBuild serializes a root table. The builder writes front to back, placing
+// each vtable immediately before it's table & children after their parent
+func fbBuild(root fbTable) []byte {
+	w := &fbWriter{buf: make([]byte, 4)}
+	pos := w.table(root)
+	binary.LittleEndian.PutUint32(w.buf, uint32(pos))
+	return w.buf
+}
+
+type fbWriter struct {
+	buf []byte
+}
+
+// align pads the buffer until the next write of extra bytes ends on a
+// multiple of n
+func (w *fbWriter) align(n, extra int) {
+	for (len(w.buf)+extra)%n != 0 {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+func fbInlineSize(v interface{}) int {
+	switch v.(type) {
+	case uint8, bool:
+		return 1
+	case int16:
+		return 2
+	case int64:
+		return 8
+	}
+	// int32 & offsets
+	return 4
+}
+
+func (w *fbWriter) table(t fbTable) int {
+	// lay out fields largest first so every field is naturally aligned
+	fields := append(fbTable{}, t...)
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fbInlineSize(fields[i].v) > fbInlineSize(fields[j].v)
+	})
+	numSlots := 0
+	align := 4
+	for _, f := range fields {
+		if f.slot+1 > numSlots {
+			numSlots = f.slot + 1
+		}
+		if fbInlineSize(f.v) == 8 {
+			align = 8
+		}
+	}
+	offsets := make([]int, len(fields))
+	size := 4
+	for i, f := range fields {
+		n := fbInlineSize(f.v)
+		for size%n != 0 {
+			size++
+		}
+		offsets[i] = size
+		size += n
+	}
+
+	w.align(2, 0)
+	vtpos := len(w.buf)
+	vtable := make([]byte, 4+2*numSlots)
+	binary.LittleEndian.PutUint16(vtable, uint16(len(vtable)))
+	binary.LittleEndian.PutUint16(vtable[2:], uint16(size))
+	for i, f := range fields {
+		binary.LittleEndian.PutUint16(vtable[4+2*f.slot:], uint16(offsets[i]))
+	}
+	w.buf = append(w.buf, vtable...)
+
+	w.align(align, 0)
+	tpos := len(w.buf)
+	w.buf = append(w.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(w.buf[tpos:], uint32(tpos-vtpos))
+	for i, f := range fields {
+		p := tpos + offsets[i]
+		switch v := f.v.(type) {
+		case uint8:
+			w.buf[p] = v
+		case bool:
+			if v {
+				w.buf[p] = 1
+			}
+		case int16:
+			binary.LittleEndian.PutUint16(w.buf[p:], uint16(v))
+		case int32:
+			binary.LittleEndian.PutUint32(w.buf[p:], uint32(v))
+		case int64:
+			binary.LittleEndian.PutUint64(w.buf[p:], uint64(v))
+		default:
+			child := w.child(v)
+			binary.LittleEndian.PutUint32(w.buf[p:], uint32(child-p))
+		}
+	}
+	return tpos
+}
+
+// child writes a value referenced by offset, returning it's position
+func (w *fbWriter) child(v interface{}) int {
+	switch t := v.(type) {
+	case string:
+		w.align(4, 0)
+		pos := len(w.buf)
+		w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(len(t)))
+		w.buf = append(w.buf, t...)
+		w.buf = append(w.buf, 0)
+		return pos
+	case fbTable:
+		return w.table(t)
+	case fbTables:
+		w.align(4, 0)
+		pos := len(w.buf)
+		w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(len(t)))
+		w.buf = append(w.buf, make([]byte, 4*len(t))...)
+		for i, tbl := range t {
+			p := pos + 4 + 4*i
+			tpos := w.table(tbl)
+			binary.LittleEndian.PutUint32(w.buf[p:], uint32(tpos-p))
+		}
+		return pos
+	case fbStructs:
+		w.align(8, 4)
+		pos := len(w.buf)
+		w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(len(t)))
+		for _, s := range t {
+			w.buf = append(w.buf, s...)
+		}
+		return pos
+	}
+	panic(fmt.Sprintf("flatbuffers: unsupported value type %T", v))
+}
+
+// fbReader reads flatbuffer tables from a buffer. reads out of bounds give
+// zero values & set err
+type fbReader struct {
+	buf []byte
+	err error
+}
+
+// fbTableRef is the position of a table in a buffer
+type fbTableRef struct {
+	r   *fbReader
+	pos int
+}
+
+func (r *fbReader) check(pos, n int) bool {
+	if pos < 0 || n < 0 || pos+n > len(r.buf) || pos+n < pos {
+		r.err = errFlatbufCorrupt
+		return false
+	}
+	return true
+}
+
+func (r *fbReader) u32(pos int) int {
+	if !r.check(pos, 4) {
+		return 0
+	}
+	return int(binary.LittleEndian.Uint32(r.buf[pos:]))
+}
+
+// root gives the root table of the buffer
+func (r *fbReader) root() fbTableRef {
+	return fbTableRef{r: r, pos: r.u32(0)}
+}
+
+// field gives the position of a field in the table, 0 if the field is absent
+func (t fbTableRef) field(slot int) int {
+	r := t.r
+	if !r.check(t.pos, 4) {
+		return 0
+	}
+	vt := t.pos - int(int32(binary.LittleEndian.Uint32(r.buf[t.pos:])))
+	if !r.check(vt, 4) {
+		return 0
+	}
+	vtsize := int(binary.LittleEndian.Uint16(r.buf[vt:]))
+	off := 4 + 2*slot
+	if off+2 > vtsize || !r.check(vt+off, 2) {
+		return 0
+	}
+	fo := int(binary.LittleEndian.Uint16(r.buf[vt+off:]))
+	if fo == 0 {
+		return 0
+	}
+	return t.pos + fo
+}
+
+func (t fbTableRef) uint8(slot int) uint8 {
+	p := t.field(slot)
+	if p == 0 || !t.r.check(p, 1) {
+		return 0
+	}
+	return t.r.buf[p]
+}
+
+func (t fbTableRef) bool(slot int) bool {
+	return t.uint8(slot) != 0
+}
+
+func (t fbTableRef) int16(slot int) int16 {
+	p := t.field(slot)
+	if p == 0 || !t.r.check(p, 2) {
+		return 0
+	}
+	return int16(binary.LittleEndian.Uint16(t.r.buf[p:]))
+}
+
+func (t fbTableRef) int32(slot int) int32 {
+	p := t.field(slot)
+	if p == 0 || !t.r.check(p, 4) {
+		return 0
+	}
+	return int32(binary.LittleEndian.Uint32(t.r.buf[p:]))
+}
+
+func (t fbTableRef) int64(slot int) int64 {
+	p := t.field(slot)
+	if p == 0 || !t.r.check(p, 8) {
+		return 0
+	}
+	return int64(binary.LittleEndian.Uint64(t.r.buf[p:]))
+}
+
+// ref follows an offset field, giving the referenced position
+func (t fbTableRef) ref(slot int) (int, bool) {
+	p := t.field(slot)
+	if p == 0 {
+		return 0, false
+	}
+	off := t.r.u32(p)
+	if t.r.err != nil {
+		return 0, false
+	}
+	return p + off, true
+}
+
+func (t fbTableRef) table(slot int) (fbTableRef, bool) {
+	p, ok := t.ref(slot)
+	return fbTableRef{r: t.r, pos: p}, ok
+}
+
+func (t fbTableRef) str(slot int) string {
+	p, ok := t.ref(slot)
+	if !ok {
+		return ""
+	}
+	n := t.r.u32(p)
+	if !t.r.check(p+4, n) {
+		return ""
+	}
+	return string(t.r.buf[p+4 : p+4+n])
+}
+
+// tables gives the elements of a vector of tables
+func (t fbTableRef) tables(slot int) []fbTableRef {
+	p, ok := t.ref(slot)
+	if !ok {
+		return nil
+	}
+	n := t.r.u32(p)
+	if !t.r.check(p+4, 4*n) {
+		return nil
+	}
+	tbls := make([]fbTableRef, n)
+	for i := range tbls {
+		el := p + 4 + 4*i
+		tbls[i] = fbTableRef{r: t.r, pos: el + t.r.u32(el)}
+	}
+	return tbls
+}
+
+// structs gives the elements of a vector of structs of size bytes
+func (t fbTableRef) structs(slot, size int) [][]byte {
+	p, ok := t.ref(slot)
+	if !ok {
+		return nil
+	}
+	n := t.r.u32(p)
+	if n > len(t.r.buf)/size || !t.r.check(p+4, size*n) {
+		t.r.err = errFlatbufCorrupt
+		return nil
+	}
+	els := make([][]byte, n)
+	for i := range els {
+		start := p + 4 + size*i
+		els[i] = t.r.buf[start : start+size]
+	}
+	return els
+}
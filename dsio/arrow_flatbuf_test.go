@@ -0,0 +1,69 @@
+package dsio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlatbufRoundTrip(t *testing.T) {
+	buf := fbBuild(fbTable{
+		{0, int16(-3)},
+		{1, uint8(7)},
+		{2, fbTable{{0, "nested"}, {3, int64(1 << 40)}}},
+		{4, fbTables{
+			fbTable{{0, "a"}, {1, true}},
+			fbTable{{0, "b"}},
+		}},
+		{5, fbStructs{arrowStruct(1, 2), arrowStruct(3, 4)}},
+		{6, int32(42)},
+	})
+
+	r := &fbReader{buf: buf}
+	root := r.root()
+	if got := root.int16(0); got != -3 {
+		t.Errorf("int16 mismatch. got: %d", got)
+	}
+	if got := root.uint8(1); got != 7 {
+		t.Errorf("uint8 mismatch. got: %d", got)
+	}
+	if got := root.int32(6); got != 42 {
+		t.Errorf("int32 mismatch. got: %d", got)
+	}
+	if got := root.int32(3); got != 0 {
+		t.Errorf("expected absent field to be zero. got: %d", got)
+	}
+	if got := root.int32(20); got != 0 {
+		t.Errorf("expected field beyond the vtable to be zero. got: %d", got)
+	}
+
+	nested, ok := root.table(2)
+	if !ok {
+		t.Fatal("expected nested table")
+	}
+	if nested.str(0) != "nested" || nested.int64(3) != 1<<40 {
+		t.Errorf("nested table mismatch. got: %q %d", nested.str(0), nested.int64(3))
+	}
+	if nested.field(3)%8 != 0 {
+		t.Errorf("expected 8-byte field to be aligned, got position: %d", nested.field(3))
+	}
+
+	tbls := root.tables(4)
+	if len(tbls) != 2 || tbls[0].str(0) != "a" || !tbls[0].bool(1) || tbls[1].str(0) != "b" || tbls[1].bool(1) {
+		t.Errorf("table vector mismatch")
+	}
+
+	structs := root.structs(5, 16)
+	expect := [][]byte{arrowStruct(1, 2), arrowStruct(3, 4)}
+	if !reflect.DeepEqual(expect, structs) {
+		t.Errorf("struct vector mismatch. expected: %v, got: %v", expect, structs)
+	}
+	if r.err != nil {
+		t.Errorf("unexpected error: %s", r.err)
+	}
+
+	bad := &fbReader{buf: []byte{0xff, 0xff, 0, 0}}
+	bad.root().int32(0)
+	if bad.err == nil {
+		t.Error("expected error reading corrupt buffer")
+	}
+}
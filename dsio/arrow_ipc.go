@@ -0,0 +1,1168 @@
+package dsio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+
+	"github.com/qri-io/dataset"
+)
+
+// arrow IPC streams are a sequence of encapsulated messages: a schema followed
+// by record batches. see
+// https://arrow.apache.org/docs/format/Columnar.html#serialization-and-interprocess-communication-ipc
+
+const (
+	// arrowContinuation marks the start of an encapsulated message
+	arrowContinuation = 0xFFFFFFFF
+	// arrowMetadataV5 is the metadata version written by ArrowWriter
+	arrowMetadataV5 = 4
+	// arrowBatchSize is the number of rows ArrowWriter writes per record
+	// batch
+	arrowBatchSize = 1024
+)
+
+// arrow message header types
+const (
+	arrowHeaderSchema          = 1
+	arrowHeaderDictionaryBatch = 2
+	arrowHeaderRecordBatch     = 3
+)
+
+// arrow flatbuffer type ids
+const (
+	arrowTypeNull          = 1
+	arrowTypeInt           = 2
+	arrowTypeFloatingPoint = 3
+	arrowTypeBinary        = 4
+	arrowTypeUTF8          = 5
+	arrowTypeBool          = 6
+	arrowTypeList          = 12
+	arrowTypeStruct        = 13
+	arrowTypeLargeBinary   = 19
+	arrowTypeLargeUTF8     = 20
+)
+
+// arrowFileMagic starts arrow files, which wrap a stream with a footer
+var arrowFileMagic = []byte("ARROW1")
+
+// arrowIPCField is a field decoded from a schema message
+type arrowIPCField struct {
+	name      string
+	typ       uint8
+	bitWidth  int32
+	signed    bool
+	precision int16
+	children  []arrowIPCField
+	// index is set for dictionary encoded fields, describing the integer
+	// type of indexes into the dictionary with id dictID
+	index  *arrowIPCField
+	dictID int64
+}
+
+// ArrowReader implements the EntryReader interface for apache arrow IPC
+// streams. Arrow files are also read, ignoring the file footer. Entries of
+// bodies with object items are objects keyed by field name, other entries are
+// arrays of column values. Dictionary encoded fields are decoded to their
+// dictionary values, compressed batches aren't supported
+type ArrowReader struct {
+	st          *dataset.Structure
+	src         *TrackedReader
+	reader      *bufio.Reader
+	fields      []arrowIPCField
+	objects     bool
+	batch       [][]interface{}
+	row         int
+	batchRows   int
+	entriesRead int
+	// dictFields are the value fields of dictionaries by id, dicts the
+	// dictionary values read so far
+	dictFields map[int64]arrowIPCField
+	dicts      map[int64][]interface{}
+}
+
+var _ EntryReader = (*ArrowReader)(nil)
+
+// NewArrowReader creates a reader from a structure and read source, reading
+// the stream schema
+func NewArrowReader(st *dataset.Structure, r io.Reader) (*ArrowReader, error) {
+	src := NewTrackedReader(r)
+	rdr := &ArrowReader{
+		st:         st,
+		src:        src,
+		reader:     bufio.NewReader(src),
+		dictFields: map[int64]arrowIPCField{},
+		dicts:      map[int64][]interface{}{},
+	}
+	if items, ok := st.Schema["items"].(map[string]interface{}); ok {
+		rdr.objects = items["type"] == "object"
+	}
+
+	if magic, _ := rdr.reader.Peek(len(arrowFileMagic)); bytes.Equal(magic, arrowFileMagic) {
+		// skip magic & padding
+		rdr.reader.Discard(8)
+	}
+	typ, meta, _, err := rdr.readMessage()
+	if err != nil {
+		if err == io.EOF {
+			err = newKindError(ErrFormatMismatch, "arrow stream has no schema")
+		}
+		log.Debug(err.Error())
+		return nil, err
+	}
+	if typ != arrowHeaderSchema {
+		err := newKindError(ErrFormatMismatch, "arrow stream must start with a schema")
+		log.Debug(err.Error())
+		return nil, err
+	}
+	for _, f := range meta.tables(1) {
+		field, err := arrowReadField(f)
+		if err != nil {
+			log.Debug(err.Error())
+			return nil, err
+		}
+		rdr.addDictFields(field)
+		rdr.fields = append(rdr.fields, field)
+	}
+	if meta.r.err != nil {
+		log.Debug(meta.r.err.Error())
+		return nil, fmt.Errorf("error reading arrow schema: %w", meta.r.err)
+	}
+	return rdr, nil
+}
+
+// readMessage reads an encapsulated message, giving the message header type,
+// header table & body. io.EOF is returned at the end of the stream
+func (r *ArrowReader) readMessage() (uint8, fbTableRef, []byte, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r.reader, prefix[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = newKindError(ErrFormatMismatch, "unexpected end of arrow stream")
+		}
+		return 0, fbTableRef{}, nil, err
+	}
+	size := binary.LittleEndian.Uint32(prefix[:])
+	if size == arrowContinuation {
+		if _, err := io.ReadFull(r.reader, prefix[:]); err != nil {
+			return 0, fbTableRef{}, nil, newKindError(ErrFormatMismatch, "unexpected end of arrow stream")
+		}
+		size = binary.LittleEndian.Uint32(prefix[:])
+	}
+	if size == 0 {
+		return 0, fbTableRef{}, nil, io.EOF
+	}
+
+	meta, err := ioutil.ReadAll(io.LimitReader(r.reader, int64(size)))
+	if err != nil {
+		return 0, fbTableRef{}, nil, err
+	}
+	if len(meta) != int(size) {
+		return 0, fbTableRef{}, nil, newKindError(ErrFormatMismatch, "unexpected end of arrow stream")
+	}
+	fb := &fbReader{buf: meta}
+	msg := fb.root()
+	typ := msg.uint8(1)
+	header, ok := msg.table(2)
+	bodyLength := msg.int64(3)
+	if fb.err != nil || !ok || bodyLength < 0 {
+		return 0, fbTableRef{}, nil, newKindError(ErrFormatMismatch, "invalid arrow message")
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.reader, bodyLength))
+	if err != nil {
+		return 0, fbTableRef{}, nil, err
+	}
+	if int64(len(body)) != bodyLength {
+		return 0, fbTableRef{}, nil, newKindError(ErrFormatMismatch, "unexpected end of arrow stream")
+	}
+	return typ, header, body, nil
+}
+
+// addDictFields records the value fields of dictionary encoded fields
+func (r *ArrowReader) addDictFields(f arrowIPCField) {
+	if f.index != nil {
+		vf := f
+		vf.index = nil
+		r.dictFields[f.dictID] = vf
+	}
+	for _, child := range f.children {
+		r.addDictFields(child)
+	}
+}
+
+// arrowReadField decodes a schema field, rejecting integer widths arrow
+// doesn't define
+func arrowReadField(t fbTableRef) (arrowIPCField, error) {
+	f := arrowIPCField{name: t.str(0), typ: t.uint8(2)}
+	if dict, ok := t.table(4); ok {
+		// indexes default to signed 32-bit integers
+		index := arrowIPCField{typ: arrowTypeInt, bitWidth: 32, signed: true}
+		if it, ok := dict.table(1); ok {
+			index.bitWidth = it.int32(0)
+			index.signed = it.bool(1)
+		}
+		if !arrowValidBitWidth(index.bitWidth) {
+			return f, newKindError(ErrFormatMismatch, fmt.Sprintf("arrow field %s: invalid dictionary index bit width: %d", f.name, index.bitWidth))
+		}
+		f.index = &index
+		f.dictID = dict.int64(0)
+	}
+	if typ, ok := t.table(3); ok {
+		switch f.typ {
+		case arrowTypeInt:
+			f.bitWidth = typ.int32(0)
+			f.signed = typ.bool(1)
+		case arrowTypeFloatingPoint:
+			f.precision = typ.int16(0)
+		}
+	}
+	if f.typ == arrowTypeInt && !arrowValidBitWidth(f.bitWidth) {
+		return f, newKindError(ErrFormatMismatch, fmt.Sprintf("arrow field %s: invalid integer bit width: %d", f.name, f.bitWidth))
+	}
+	for _, child := range t.tables(5) {
+		cf, err := arrowReadField(child)
+		if err != nil {
+			return f, err
+		}
+		f.children = append(f.children, cf)
+	}
+	return f, nil
+}
+
+// arrowValidBitWidth reports whether w is an integer width arrow defines
+func arrowValidBitWidth(w int32) bool {
+	switch w {
+	case 8, 16, 32, 64:
+		return true
+	}
+	return false
+}
+
+// Structure gives this reader's structure
+func (r *ArrowReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry reads one row from the reader
+func (r *ArrowReader) ReadEntry() (Entry, error) {
+	for r.row >= r.batchRows {
+		if err := r.readBatch(); err != nil {
+			if err != io.EOF {
+				log.Debug(err.Error())
+				err = parseError("arrow", r.entriesRead, err)
+			}
+			return Entry{}, err
+		}
+	}
+
+	ent := Entry{Index: r.entriesRead}
+	if r.objects {
+		obj := make(map[string]interface{}, len(r.fields))
+		for i, f := range r.fields {
+			obj[f.name] = r.batch[i][r.row]
+		}
+		ent.Value = obj
+	} else {
+		row := make([]interface{}, len(r.fields))
+		for i := range r.fields {
+			row[i] = r.batch[i][r.row]
+		}
+		ent.Value = row
+	}
+	r.row++
+	r.entriesRead++
+	return ent, nil
+}
+
+// readBatch decodes the next record batch
+func (r *ArrowReader) readBatch() error {
+	typ, header, body, err := r.readMessage()
+	if err != nil {
+		return err
+	}
+	switch typ {
+	case arrowHeaderRecordBatch:
+	case arrowHeaderDictionaryBatch:
+		return r.readDictionary(header, body)
+	default:
+		return newKindError(ErrFormatMismatch, fmt.Sprintf("unexpected arrow message type: %d", typ))
+	}
+	if _, compressed := header.table(3); compressed {
+		return fmt.Errorf("compressed arrow record batches aren't supported")
+	}
+
+	d := &arrowBatchDecoder{
+		nodes:   header.structs(1, 16),
+		buffers: header.structs(2, 16),
+		body:    body,
+		dicts:   r.dicts,
+	}
+	length := header.int64(0)
+	if header.r.err != nil {
+		return newKindError(ErrFormatMismatch, "invalid arrow record batch")
+	}
+
+	batch := make([][]interface{}, len(r.fields))
+	for i, f := range r.fields {
+		vals, err := d.column(f)
+		if err != nil {
+			return fmt.Errorf("arrow column %s: %w", f.name, err)
+		}
+		if int64(len(vals)) < length {
+			return fmt.Errorf("arrow column %s: expected %d values, got %d", f.name, length, len(vals))
+		}
+		batch[i] = vals
+	}
+	r.batch = batch
+	r.batchRows = int(length)
+	r.row = 0
+	return nil
+}
+
+// readDictionary decodes a dictionary batch, replacing the dictionary or
+// appending to it for delta batches
+func (r *ArrowReader) readDictionary(header fbTableRef, body []byte) error {
+	id := header.int64(0)
+	data, ok := header.table(1)
+	isDelta := header.bool(2)
+	if header.r.err != nil || !ok {
+		return newKindError(ErrFormatMismatch, "invalid arrow dictionary batch")
+	}
+	f, ok := r.dictFields[id]
+	if !ok {
+		return fmt.Errorf("arrow dictionary batch for unknown dictionary: %d", id)
+	}
+	if _, compressed := data.table(3); compressed {
+		return fmt.Errorf("compressed arrow dictionary batches aren't supported")
+	}
+
+	d := &arrowBatchDecoder{
+		nodes:   data.structs(1, 16),
+		buffers: data.structs(2, 16),
+		body:    body,
+		dicts:   r.dicts,
+	}
+	vals, err := d.column(f)
+	if err != nil {
+		return fmt.Errorf("arrow dictionary %d: %w", id, err)
+	}
+	if isDelta {
+		r.dicts[id] = append(r.dicts[id], vals...)
+	} else {
+		r.dicts[id] = vals
+	}
+	return nil
+}
+
+// arrowBatchDecoder reads columns from a record batch body, consuming field
+// nodes & buffers in schema order
+type arrowBatchDecoder struct {
+	nodes   [][]byte
+	buffers [][]byte
+	body    []byte
+	dicts   map[int64][]interface{}
+}
+
+var errArrowCorrupt = newKindError(ErrFormatMismatch, "corrupt arrow record batch")
+
+func (d *arrowBatchDecoder) node() (length, nulls int, err error) {
+	if len(d.nodes) == 0 {
+		return 0, 0, errArrowCorrupt
+	}
+	n := d.nodes[0]
+	d.nodes = d.nodes[1:]
+	l := int64(binary.LittleEndian.Uint64(n))
+	nc := int64(binary.LittleEndian.Uint64(n[8:]))
+	// every value takes at least a bit
+	if l < 0 || nc < 0 || nc > l || l > int64(len(d.body))*8+1<<20 {
+		return 0, 0, errArrowCorrupt
+	}
+	return int(l), int(nc), nil
+}
+
+func (d *arrowBatchDecoder) buffer() ([]byte, error) {
+	if len(d.buffers) == 0 {
+		return nil, errArrowCorrupt
+	}
+	b := d.buffers[0]
+	d.buffers = d.buffers[1:]
+	off := int64(binary.LittleEndian.Uint64(b))
+	l := int64(binary.LittleEndian.Uint64(b[8:]))
+	if off < 0 || l < 0 || off+l > int64(len(d.body)) || off+l < off {
+		return nil, errArrowCorrupt
+	}
+	return d.body[off : off+l], nil
+}
+
+// column decodes a column of values, giving nil for null values
+func (d *arrowBatchDecoder) column(f arrowIPCField) ([]interface{}, error) {
+	if f.index != nil {
+		return d.dictColumn(f)
+	}
+	length, nulls, err := d.node()
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]interface{}, length)
+	if f.typ == arrowTypeNull {
+		return vals, nil
+	}
+
+	validity, err := d.buffer()
+	if err != nil {
+		return nil, err
+	}
+	if nulls > 0 && len(validity) < (length+7)/8 {
+		return nil, errArrowCorrupt
+	}
+	valid := func(i int) bool {
+		return nulls == 0 || len(validity) == 0 || validity[i/8]>>(i%8)&1 == 1
+	}
+
+	switch f.typ {
+	case arrowTypeBool:
+		data, err := d.buffer()
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < (length+7)/8 {
+			return nil, errArrowCorrupt
+		}
+		for i := range vals {
+			if valid(i) {
+				vals[i] = data[i/8]>>(i%8)&1 == 1
+			}
+		}
+	case arrowTypeInt, arrowTypeFloatingPoint:
+		data, err := d.buffer()
+		if err != nil {
+			return nil, err
+		}
+		width := int(f.bitWidth) / 8
+		if f.typ == arrowTypeFloatingPoint {
+			width = map[int16]int{1: 4, 2: 8}[f.precision]
+		}
+		if width == 0 {
+			return nil, fmt.Errorf("unsupported arrow numeric type")
+		}
+		if len(data) < length*width {
+			return nil, errArrowCorrupt
+		}
+		for i := range vals {
+			if valid(i) {
+				vals[i] = arrowNumber(f, data[i*width:(i+1)*width])
+			}
+		}
+	case arrowTypeUTF8, arrowTypeBinary, arrowTypeLargeUTF8, arrowTypeLargeBinary:
+		offsets, err := d.offsets(length, f.typ == arrowTypeLargeUTF8 || f.typ == arrowTypeLargeBinary)
+		if err != nil {
+			return nil, err
+		}
+		data, err := d.buffer()
+		if err != nil {
+			return nil, err
+		}
+		for i := range vals {
+			if valid(i) {
+				start, end := offsets[i], offsets[i+1]
+				if start < 0 || start > end || end > int64(len(data)) {
+					return nil, errArrowCorrupt
+				}
+				vals[i] = string(data[start:end])
+			}
+		}
+	case arrowTypeList:
+		if len(f.children) != 1 {
+			return nil, errArrowCorrupt
+		}
+		offsets, err := d.offsets(length, false)
+		if err != nil {
+			return nil, err
+		}
+		items, err := d.column(f.children[0])
+		if err != nil {
+			return nil, err
+		}
+		for i := range vals {
+			if valid(i) {
+				start, end := offsets[i], offsets[i+1]
+				if start < 0 || start > end || end > int64(len(items)) {
+					return nil, errArrowCorrupt
+				}
+				vals[i] = append([]interface{}{}, items[start:end]...)
+			}
+		}
+	case arrowTypeStruct:
+		children := make([][]interface{}, len(f.children))
+		for i, cf := range f.children {
+			if children[i], err = d.column(cf); err != nil {
+				return nil, err
+			}
+			if len(children[i]) < length {
+				return nil, errArrowCorrupt
+			}
+		}
+		for i := range vals {
+			if valid(i) {
+				obj := make(map[string]interface{}, len(f.children))
+				for j, cf := range f.children {
+					obj[cf.name] = children[j][i]
+				}
+				vals[i] = obj
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported arrow type: %d", f.typ)
+	}
+	return vals, nil
+}
+
+// dictColumn decodes a column of dictionary indexes to dictionary values
+func (d *arrowBatchDecoder) dictColumn(f arrowIPCField) ([]interface{}, error) {
+	idxs, err := d.column(*f.index)
+	if err != nil {
+		return nil, err
+	}
+	dict := d.dicts[f.dictID]
+	vals := make([]interface{}, len(idxs))
+	for i, idx := range idxs {
+		if idx == nil {
+			continue
+		}
+		n, ok := idx.(int)
+		if !ok || n < 0 || n >= len(dict) {
+			return nil, fmt.Errorf("dictionary index out of range: %v", idx)
+		}
+		vals[i] = dict[n]
+	}
+	return vals, nil
+}
+
+// offsets reads a buffer of length+1 value offsets
+func (d *arrowBatchDecoder) offsets(length int, large bool) ([]int64, error) {
+	data, err := d.buffer()
+	if err != nil {
+		return nil, err
+	}
+	width := 4
+	if large {
+		width = 8
+	}
+	if len(data) < (length+1)*width {
+		return nil, errArrowCorrupt
+	}
+	offsets := make([]int64, length+1)
+	for i := range offsets {
+		if large {
+			offsets[i] = int64(binary.LittleEndian.Uint64(data[i*8:]))
+		} else {
+			offsets[i] = int64(int32(binary.LittleEndian.Uint32(data[i*4:])))
+		}
+	}
+	return offsets, nil
+}
+
+// arrowNumber decodes an integer or floating point value
+func arrowNumber(f arrowIPCField, b []byte) interface{} {
+	if f.typ == arrowTypeFloatingPoint {
+		if len(b) == 4 {
+			return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+
+	var u uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		u = u<<8 | uint64(b[i])
+	}
+	if !f.signed {
+		if u > math.MaxInt64 {
+			return float64(u)
+		}
+		return int(u)
+	}
+	// sign extend
+	shift := 64 - 8*uint(len(b))
+	return int(int64(u<<shift) >> shift)
+}
+
+// Close finalizes the reader, closing the source if it's an io.Closer
+func (r *ArrowReader) Close() error {
+	return r.src.Close()
+}
+
+// EntriesRead gives the number of entries read
+func (r *ArrowReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes read. Record batches are read
+// whole
+func (r *ArrowReader) BytesProcessed() int64 {
+	return int64(r.src.BytesRead() - r.reader.Buffered())
+}
+
+// ArrowWriter implements the EntryWriter interface for apache arrow IPC
+// streams. Fields are derived from the structure's schema with ArrowSchema.
+// Rows are buffered & written as record batches of up to 1024 rows, the
+// schema message is written with the first batch.
+//
+// String columns hinted with dictionary encoding in ArrowOptions are written
+// as 32-bit indexes into a dictionary. Dictionary values new to a batch are
+// written as a delta dictionary batch before it
+type ArrowWriter struct {
+	st             *dataset.Structure
+	wr             *countingWriter
+	fields         []ArrowField
+	objects        bool
+	cols           []*arrowColumn
+	dicts          []*arrowDictionary
+	rows           int
+	wroteSchema    bool
+	entriesWritten int
+	closed         bool
+	// send replaces stream output for writers created with
+	// NewArrowMessageWriter
+	send func(ArrowMessage) error
+}
+
+var _ EntryWriter = (*ArrowWriter)(nil)
+
+// NewArrowWriter creates a writer from a structure and write destination
+func NewArrowWriter(st *dataset.Structure, w io.Writer) (*ArrowWriter, error) {
+	fields, err := ArrowSchema(st)
+	if err != nil {
+		err = newKindError(ErrBadSchema, err.Error())
+		log.Debug(err.Error())
+		return nil, err
+	}
+	opts, err := dataset.NewArrowOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	aw := &ArrowWriter{
+		st:     st,
+		wr:     &countingWriter{w: w},
+		fields: fields,
+		dicts:  make([]*arrowDictionary, len(fields)),
+	}
+	if items, ok := st.Schema["items"].(map[string]interface{}); ok {
+		aw.objects = items["type"] == "object"
+	}
+	for name, hint := range opts.Columns {
+		i := 0
+		for i < len(fields) && fields[i].Name != name {
+			i++
+		}
+		if i == len(fields) {
+			err := fmt.Errorf("arrow format config has hints for unknown column '%s'", name)
+			log.Debug(err.Error())
+			return nil, err
+		}
+		if hint.Encoding != dataset.ColumnEncodingDictionary {
+			continue
+		}
+		if fields[i].Type != ArrowUTF8 {
+			err := fmt.Errorf("column %s: arrow dictionary encoding requires a string column", name)
+			log.Debug(err.Error())
+			return nil, err
+		}
+		aw.dicts[i] = &arrowDictionary{id: int64(i), index: map[string]int32{}}
+	}
+	aw.resetColumns()
+	return aw, nil
+}
+
+// ArrowMessage is an arrow IPC message without stream framing: a flatbuffer
+// Message header & the message body. Arrow Flight carries streams as a
+// sequence of messages
+type ArrowMessage struct {
+	Header []byte
+	Body   []byte
+}
+
+// NewArrowMessageWriter creates a writer that passes each message of the
+// arrow IPC stream to send in place of writing a stream. No end of stream
+// marker is sent
+func NewArrowMessageWriter(st *dataset.Structure, send func(ArrowMessage) error) (*ArrowWriter, error) {
+	aw, err := NewArrowWriter(st, ioutil.Discard)
+	if err != nil {
+		return nil, err
+	}
+	aw.send = send
+	return aw, nil
+}
+
+// ArrowSchemaMessage encodes the schema message that starts the arrow IPC
+// stream of a structure's body, with stream framing
+func ArrowSchemaMessage(st *dataset.Structure) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	aw, err := NewArrowWriter(st, buf)
+	if err != nil {
+		return nil, err
+	}
+	if err := aw.writeBatch(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// arrowInt32 types the index columns of dictionary encoded fields. It's
+// only used for writing, schemas don't map to it
+const arrowInt32 ArrowType = "int32"
+
+// arrowDictionary accumulates the values of a dictionary encoded field.
+// written counts values already written to the stream
+type arrowDictionary struct {
+	id      int64
+	index   map[string]int32
+	values  []string
+	written int
+}
+
+// lookup gives the index of a value, adding it to the dictionary if it's new
+func (d *arrowDictionary) lookup(s string) int32 {
+	idx, ok := d.index[s]
+	if !ok {
+		idx = int32(len(d.values))
+		d.index[s] = idx
+		d.values = append(d.values, s)
+	}
+	return idx
+}
+
+func (w *ArrowWriter) resetColumns() {
+	w.cols = make([]*arrowColumn, len(w.fields))
+	for i, f := range w.fields {
+		if w.dicts[i] != nil {
+			f = ArrowField{Name: f.Name, Type: arrowInt32, Nullable: f.Nullable}
+		}
+		w.cols[i] = newArrowColumn(f)
+	}
+	w.rows = 0
+}
+
+// Structure gives this writer's structure
+func (w *ArrowWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// WriteEntry buffers a row, writing a record batch when a batch is full
+func (w *ArrowWriter) WriteEntry(ent Entry) error {
+	row := make([]interface{}, len(w.fields))
+	switch v := ent.Value.(type) {
+	case []interface{}:
+		if w.objects {
+			return fmt.Errorf("expected object value to write arrow row. got: %T", ent.Value)
+		}
+		if len(v) > len(w.fields) {
+			err := fmt.Errorf("entry %d has %d values, schema has %d columns", ent.Index, len(v), len(w.fields))
+			log.Debug(err.Error())
+			return err
+		}
+		copy(row, v)
+	case map[string]interface{}:
+		if !w.objects {
+			return fmt.Errorf("expected array value to write arrow row. got: %T", ent.Value)
+		}
+		for i, f := range w.fields {
+			row[i] = v[f.Name]
+		}
+	default:
+		return fmt.Errorf("expected array or object value to write arrow row. got: %T", ent.Value)
+	}
+
+	// convert the whole row before appending, so columns stay the same length
+	for i, f := range w.fields {
+		v, err := arrowValue(f, row[i])
+		if err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("entry %d column %s: %w", ent.Index, f.Name, err)
+		}
+		row[i] = v
+	}
+	for i, col := range w.cols {
+		if s, ok := row[i].(string); ok && w.dicts[i] != nil {
+			col.append(w.dicts[i].lookup(s))
+			continue
+		}
+		col.append(row[i])
+	}
+	w.rows++
+	w.entriesWritten++
+
+	if w.rows >= arrowBatchSize {
+		return w.writeBatch()
+	}
+	return nil
+}
+
+// arrowValue checks & converts a value to the go type stored for a field:
+// int64, float64, bool, string, or []interface{} of converted values for
+// lists & structs
+func arrowValue(f ArrowField, v interface{}) (interface{}, error) {
+	if v == nil || f.Type == ArrowNull {
+		return nil, nil
+	}
+	switch f.Type {
+	case ArrowBool:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected boolean value, got %T", v)
+	case ArrowInt64:
+		switch t := v.(type) {
+		case int:
+			return int64(t), nil
+		case int64:
+			return t, nil
+		case int32:
+			return int64(t), nil
+		case float64:
+			if t == math.Trunc(t) {
+				return int64(t), nil
+			}
+		}
+		return nil, fmt.Errorf("expected integer value, got %T", v)
+	case ArrowFloat64:
+		switch t := v.(type) {
+		case float64:
+			return t, nil
+		case float32:
+			return float64(t), nil
+		case int:
+			return float64(t), nil
+		case int64:
+			return float64(t), nil
+		}
+		return nil, fmt.Errorf("expected number value, got %T", v)
+	case ArrowUTF8:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		// untyped values are written as JSON text
+		data, err := json.Marshal(v)
+		return string(data), err
+	case ArrowList:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected array value, got %T", v)
+		}
+		items := make([]interface{}, len(arr))
+		for i, item := range arr {
+			cv, err := arrowValue(f.Children[0], item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = cv
+		}
+		return items, nil
+	case ArrowStruct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object value, got %T", v)
+		}
+		props := make([]interface{}, len(f.Children))
+		for i, cf := range f.Children {
+			cv, err := arrowValue(cf, obj[cf.Name])
+			if err != nil {
+				return nil, err
+			}
+			props[i] = cv
+		}
+		return props, nil
+	}
+	return nil, fmt.Errorf("unsupported arrow type: %s", f.Type)
+}
+
+// arrowColumn accumulates the buffers of a column
+type arrowColumn struct {
+	field    ArrowField
+	length   int
+	nulls    int
+	validity []byte
+	values   []byte
+	offsets  []byte
+	children []*arrowColumn
+}
+
+func newArrowColumn(f ArrowField) *arrowColumn {
+	c := &arrowColumn{field: f}
+	if f.Type == ArrowUTF8 || f.Type == ArrowList {
+		c.offsets = make([]byte, 4)
+	}
+	for _, cf := range f.Children {
+		c.children = append(c.children, newArrowColumn(cf))
+	}
+	return c
+}
+
+// appendBit sets bit n of a bitmap, growing the bitmap as needed
+func appendBit(bits []byte, n int, set bool) []byte {
+	if n%8 == 0 {
+		bits = append(bits, 0)
+	}
+	if set {
+		bits[n/8] |= 1 << (n % 8)
+	}
+	return bits
+}
+
+// append adds a value converted by arrowValue
+func (c *arrowColumn) append(v interface{}) {
+	i := c.length
+	c.length++
+	if v == nil {
+		c.nulls++
+	}
+	if c.field.Type == ArrowNull {
+		return
+	}
+	c.validity = appendBit(c.validity, i, v != nil)
+
+	switch c.field.Type {
+	case ArrowBool:
+		b, _ := v.(bool)
+		c.values = appendBit(c.values, i, b)
+	case ArrowInt64:
+		n, _ := v.(int64)
+		c.values = binary.LittleEndian.AppendUint64(c.values, uint64(n))
+	case arrowInt32:
+		n, _ := v.(int32)
+		c.values = binary.LittleEndian.AppendUint32(c.values, uint32(n))
+	case ArrowFloat64:
+		f, _ := v.(float64)
+		c.values = binary.LittleEndian.AppendUint64(c.values, math.Float64bits(f))
+	case ArrowUTF8:
+		s, _ := v.(string)
+		c.values = append(c.values, s...)
+		c.offsets = binary.LittleEndian.AppendUint32(c.offsets, uint32(len(c.values)))
+	case ArrowList:
+		items, _ := v.([]interface{})
+		for _, item := range items {
+			c.children[0].append(item)
+		}
+		c.offsets = binary.LittleEndian.AppendUint32(c.offsets, uint32(c.children[0].length))
+	case ArrowStruct:
+		props, _ := v.([]interface{})
+		for j, child := range c.children {
+			if props == nil {
+				child.append(nil)
+			} else {
+				child.append(props[j])
+			}
+		}
+	}
+}
+
+// arrowBatchEncoder accumulates the field nodes & buffers of a record batch
+type arrowBatchEncoder struct {
+	nodes   fbStructs
+	buffers fbStructs
+	body    []byte
+}
+
+func arrowStruct(a, b int) []byte {
+	s := binary.LittleEndian.AppendUint64(nil, uint64(a))
+	return binary.LittleEndian.AppendUint64(s, uint64(b))
+}
+
+func (e *arrowBatchEncoder) buffer(b []byte) {
+	e.buffers = append(e.buffers, arrowStruct(len(e.body), len(b)))
+	e.body = append(e.body, b...)
+	for len(e.body)%8 != 0 {
+		e.body = append(e.body, 0)
+	}
+}
+
+func (e *arrowBatchEncoder) column(c *arrowColumn) {
+	e.nodes = append(e.nodes, arrowStruct(c.length, c.nulls))
+	if c.field.Type == ArrowNull {
+		return
+	}
+	if c.nulls == 0 {
+		e.buffer(nil)
+	} else {
+		e.buffer(c.validity)
+	}
+	switch c.field.Type {
+	case ArrowBool, ArrowInt64, arrowInt32, ArrowFloat64:
+		e.buffer(c.values)
+	case ArrowUTF8:
+		e.buffer(c.offsets)
+		e.buffer(c.values)
+	case ArrowList:
+		e.buffer(c.offsets)
+	}
+	for _, child := range c.children {
+		e.column(child)
+	}
+}
+
+// arrowFieldTable gives the flatbuffer table for a schema field
+func arrowFieldTable(f ArrowField) fbTable {
+	var typ uint8
+	typTable := fbTable{}
+	switch f.Type {
+	case ArrowNull:
+		typ = arrowTypeNull
+	case ArrowBool:
+		typ = arrowTypeBool
+	case ArrowInt64:
+		typ = arrowTypeInt
+		typTable = fbTable{{0, int32(64)}, {1, true}}
+	case ArrowFloat64:
+		typ = arrowTypeFloatingPoint
+		// double precision
+		typTable = fbTable{{0, int16(2)}}
+	case ArrowUTF8:
+		typ = arrowTypeUTF8
+	case ArrowList:
+		typ = arrowTypeList
+	case ArrowStruct:
+		typ = arrowTypeStruct
+	}
+	children := fbTables{}
+	for _, cf := range f.Children {
+		children = append(children, arrowFieldTable(cf))
+	}
+	return fbTable{
+		{0, f.Name},
+		{1, f.Nullable},
+		{2, typ},
+		{3, typTable},
+		{5, children},
+	}
+}
+
+// writeMessage writes an encapsulated message. body must be padded to a
+// multiple of 8 bytes
+func (w *ArrowWriter) writeMessage(headerType uint8, header fbTable, body []byte) error {
+	meta := fbBuild(fbTable{
+		{0, int16(arrowMetadataV5)},
+		{1, headerType},
+		{2, header},
+		{3, int64(len(body))},
+	})
+	for len(meta)%8 != 0 {
+		meta = append(meta, 0)
+	}
+	if w.send != nil {
+		w.wr.n += int64(len(meta) + len(body))
+		return w.send(ArrowMessage{Header: meta, Body: body})
+	}
+	prefix := binary.LittleEndian.AppendUint32(nil, arrowContinuation)
+	prefix = binary.LittleEndian.AppendUint32(prefix, uint32(len(meta)))
+	for _, b := range [][]byte{prefix, meta, body} {
+		if _, err := w.wr.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBatch writes buffered rows as a record batch, writing the schema
+// message first if it hasn't been written
+func (w *ArrowWriter) writeBatch() error {
+	if !w.wroteSchema {
+		fields := fbTables{}
+		for i, f := range w.fields {
+			t := arrowFieldTable(f)
+			if d := w.dicts[i]; d != nil {
+				// signed 32-bit indexes
+				t = append(t, fbField{4, fbTable{{0, d.id}, {1, fbTable{{0, int32(32)}, {1, true}}}}})
+			}
+			fields = append(fields, t)
+		}
+		if err := w.writeMessage(arrowHeaderSchema, fbTable{{0, int16(0)}, {1, fields}}, nil); err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("error writing arrow schema: %w", err)
+		}
+		w.wroteSchema = true
+	}
+	if w.rows == 0 {
+		return nil
+	}
+
+	for _, d := range w.dicts {
+		if d == nil || d.written == len(d.values) {
+			continue
+		}
+		if err := w.writeDictionary(d); err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("error writing arrow dictionary batch: %w", err)
+		}
+	}
+
+	e := &arrowBatchEncoder{}
+	for _, col := range w.cols {
+		e.column(col)
+	}
+	header := fbTable{{0, int64(w.rows)}, {1, e.nodes}, {2, e.buffers}}
+	if err := w.writeMessage(arrowHeaderRecordBatch, header, e.body); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing arrow record batch: %w", err)
+	}
+	w.resetColumns()
+	return nil
+}
+
+// writeDictionary writes dictionary values added since the last dictionary
+// batch, as a delta batch once the dictionary's first batch is written
+func (w *ArrowWriter) writeDictionary(d *arrowDictionary) error {
+	col := newArrowColumn(ArrowField{Type: ArrowUTF8})
+	for _, s := range d.values[d.written:] {
+		col.append(s)
+	}
+	e := &arrowBatchEncoder{}
+	e.column(col)
+	header := fbTable{
+		{0, d.id},
+		{1, fbTable{{0, int64(col.length)}, {1, e.nodes}, {2, e.buffers}}},
+		{2, d.written > 0},
+	}
+	if err := w.writeMessage(arrowHeaderDictionaryBatch, header, e.body); err != nil {
+		return err
+	}
+	d.written = len(d.values)
+	return nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *ArrowWriter) EntriesWritten() int {
+	return w.entriesWritten
+}
+
+// BytesProcessed gives the number of bytes written. rows are buffered until a
+// record batch is written
+func (w *ArrowWriter) BytesProcessed() int64 {
+	return w.wr.n
+}
+
+// Close writes any buffered rows & the end of stream marker. The destination
+// is closed if it's an io.Closer, wrap it with KeepWriterOpen to leave it open
+func (w *ArrowWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.writeBatch(); err != nil {
+		return err
+	}
+	if w.send != nil {
+		return nil
+	}
+	eos := binary.LittleEndian.AppendUint32(nil, arrowContinuation)
+	eos = binary.LittleEndian.AppendUint32(eos, 0)
+	if _, err := w.wr.Write(eos); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing arrow end of stream: %w", err)
+	}
+	return w.wr.Close()
+}
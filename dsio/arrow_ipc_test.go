@@ -0,0 +1,443 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+var arrowTestStructure = &dataset.Structure{
+	Format: "arrow",
+	Schema: map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "id", "type": "integer"},
+				map[string]interface{}{"title": "score", "type": "number"},
+				map[string]interface{}{"title": "ok", "type": "boolean"},
+				map[string]interface{}{"title": "name", "type": "string"},
+				map[string]interface{}{"title": "tags", "type": "array", "items": map[string]interface{}{"type": "string"}},
+				map[string]interface{}{"title": "loc", "type": "object", "properties": map[string]interface{}{
+					"lat": map[string]interface{}{"type": "number"},
+					"lng": map[string]interface{}{"type": "number"},
+				}},
+				map[string]interface{}{"title": "any"},
+			},
+		},
+	},
+}
+
+func TestArrowRoundTrip(t *testing.T) {
+	rows := []interface{}{
+		[]interface{}{1, 0.5, true, "alpha", []interface{}{"a", "b"}, map[string]interface{}{"lat": 1.5, "lng": -2}, "x"},
+		[]interface{}{nil, nil, nil, nil, nil, nil, nil},
+		[]interface{}{-1 << 40, 3, false, "ラーメン", []interface{}{}, map[string]interface{}{"lat": nil}, 12},
+		[]interface{}{2, 1.25, true, "", []interface{}{nil, "c"}},
+	}
+	expect := []interface{}{
+		[]interface{}{1, 0.5, true, "alpha", []interface{}{"a", "b"}, map[string]interface{}{"lat": 1.5, "lng": -2.0}, "x"},
+		[]interface{}{nil, nil, nil, nil, nil, nil, nil},
+		[]interface{}{-1 << 40, 3.0, false, "ラーメン", []interface{}{}, map[string]interface{}{"lat": nil, "lng": nil}, "12"},
+		[]interface{}{2, 1.25, true, "", []interface{}{nil, "c"}, nil, nil},
+	}
+
+	buf := &bytes.Buffer{}
+	w, err := NewEntryWriter(arrowTestStructure, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		if err := w.WriteEntry(Entry{Index: i, Value: row}); err != nil {
+			t.Fatalf("entry %d: %s", i, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	size := buf.Len()
+	if w.(*ArrowWriter).BytesProcessed() != int64(size) {
+		t.Errorf("bytes processed mismatch. expected: %d, got: %d", size, w.(*ArrowWriter).BytesProcessed())
+	}
+	if size%8 != 0 {
+		t.Errorf("expected stream length to be a multiple of 8, got: %d", size)
+	}
+
+	r, err := NewEntryReader(arrowTestStructure, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ex := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("entry %d: %s", i, err)
+		}
+		if ent.Index != i {
+			t.Errorf("entry %d index mismatch. got: %d", i, ent.Index)
+		}
+		if !reflect.DeepEqual(ex, ent.Value) {
+			t.Errorf("entry %d mismatch.\nexpected: %#v\ngot:      %#v", i, ex, ent.Value)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+	if got := r.(*ArrowReader).BytesProcessed(); got != int64(size) {
+		t.Errorf("reader bytes processed mismatch. expected: %d, got: %d", size, got)
+	}
+}
+
+func TestArrowObjectRows(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "arrow",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"a": map[string]interface{}{"type": "integer"},
+					"b": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewArrowWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// write more rows than fit in a single record batch
+	n := arrowBatchSize*2 + 10
+	for i := 0; i < n; i++ {
+		if err := w.WriteEntry(Entry{Index: i, Value: map[string]interface{}{"a": i, "b": "row"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteEntry(Entry{Value: []interface{}{1}}); err == nil {
+		t.Error("expected writing an array row to an object schema to error")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewArrowReader(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	err = EachEntry(r, func(i int, ent Entry, err error) error {
+		expect := map[string]interface{}{"a": i, "b": "row"}
+		if !reflect.DeepEqual(expect, ent.Value) {
+			t.Errorf("entry %d mismatch. got: %v", i, ent.Value)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != n {
+		t.Errorf("expected %d entries, got: %d", n, count)
+	}
+}
+
+func TestArrowEmptyStream(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewArrowWriter(arrowTestStructure, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewArrowReader(arrowTestStructure, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.fields) != 7 {
+		t.Errorf("expected 7 schema fields, got: %d", len(r.fields))
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+}
+
+func TestArrowWriterErrors(t *testing.T) {
+	if _, err := NewArrowWriter(&dataset.Structure{Format: "arrow", Schema: dataset.BaseSchemaObject}, &bytes.Buffer{}); err == nil {
+		t.Error("expected error creating writer with an object schema")
+	}
+
+	cases := []struct {
+		val interface{}
+		err string
+	}{
+		{"nope", "expected array or object value to write arrow row. got: string"},
+		{map[string]interface{}{}, "expected array value to write arrow row. got: map[string]interface {}"},
+		{[]interface{}{1, 1, true, "a", nil, nil, nil, "extra"}, "entry 0 has 8 values, schema has 7 columns"},
+		{[]interface{}{"1"}, "entry 0 column id: expected integer value, got string"},
+		{[]interface{}{1, true}, "entry 0 column score: expected number value, got bool"},
+		{[]interface{}{1, 1, "true"}, "entry 0 column ok: expected boolean value, got string"},
+		{[]interface{}{1, 1, true, "a", "tags"}, "entry 0 column tags: expected array value, got string"},
+		{[]interface{}{1, 1, true, "a", nil, map[string]interface{}{"lat": "north"}}, "entry 0 column loc: expected number value, got string"},
+	}
+	for i, c := range cases {
+		w, err := NewArrowWriter(arrowTestStructure, &bytes.Buffer{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = w.WriteEntry(Entry{Value: c.val})
+		if err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+		if w.rows != 0 || w.cols[0].length != 0 {
+			t.Errorf("case %d expected failed write to leave columns empty", i)
+		}
+	}
+}
+
+func TestArrowReaderErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, _ := NewArrowWriter(arrowTestStructure, buf)
+	w.WriteEntry(Entry{Value: []interface{}{1}})
+	w.Close()
+	valid := buf.Bytes()
+
+	// schemaStream gives a stream with a single schema message for fields.
+	// bad widths were found fuzzing: a negative width used to panic decoding
+	// the first record batch
+	schemaStream := func(fields ...fbTable) []byte {
+		buf := &bytes.Buffer{}
+		w, _ := NewArrowWriter(arrowTestStructure, buf)
+		tables := fbTables{}
+		for _, f := range fields {
+			tables = append(tables, f)
+		}
+		w.writeMessage(arrowHeaderSchema, fbTable{{0, int16(0)}, {1, tables}}, nil)
+		return buf.Bytes()
+	}
+	intField := func(width int32) fbTable {
+		return fbTable{{0, "a"}, {2, uint8(arrowTypeInt)}, {3, fbTable{{0, width}, {1, true}}}}
+	}
+
+	cases := []struct {
+		data []byte
+		err  string
+	}{
+		{[]byte{}, "arrow stream has no schema"},
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0x10, 0, 0, 0, 1, 2}, "unexpected end of arrow stream"},
+		{valid[:len(valid)-20], ""},
+		{schemaStream(intField(-8)), "arrow field a: invalid integer bit width: -8"},
+		{schemaStream(intField(12)), "arrow field a: invalid integer bit width: 12"},
+		{schemaStream(fbTable{{0, "a"}, {2, uint8(arrowTypeInt)}}), "arrow field a: invalid integer bit width: 0"},
+		{schemaStream(fbTable{{0, "l"}, {2, uint8(arrowTypeList)}, {5, fbTables{intField(-64)}}}), "arrow field a: invalid integer bit width: -64"},
+		{schemaStream(fbTable{{0, "d"}, {2, uint8(arrowTypeUTF8)}, {4, fbTable{{0, int64(0)}, {1, fbTable{{0, int32(-32)}, {1, true}}}}}}), "arrow field d: invalid dictionary index bit width: -32"},
+	}
+	for i, c := range cases {
+		r, err := NewArrowReader(arrowTestStructure, bytes.NewReader(c.data))
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if _, err := r.ReadEntry(); err == nil || err == io.EOF {
+			t.Errorf("case %d expected error reading truncated batch, got: %v", i, err)
+		}
+	}
+}
+
+func TestArrowFileFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, _ := NewArrowWriter(arrowTestStructure, buf)
+	w.WriteEntry(Entry{Value: []interface{}{7}})
+	w.Close()
+
+	// arrow files wrap a stream with magic bytes & a footer
+	file := append([]byte("ARROW1\x00\x00"), buf.Bytes()...)
+	file = append(file, []byte("footer\x06\x00\x00\x00ARROW1")...)
+	r, err := NewArrowReader(arrowTestStructure, bytes.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent.Value.([]interface{})[0] != 7 {
+		t.Errorf("expected first value to be 7, got: %v", ent.Value)
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+}
+
+func TestArrowNumber(t *testing.T) {
+	cases := []struct {
+		f      arrowIPCField
+		b      []byte
+		expect interface{}
+	}{
+		{arrowIPCField{typ: arrowTypeInt, bitWidth: 8, signed: true}, []byte{0xff}, -1},
+		{arrowIPCField{typ: arrowTypeInt, bitWidth: 8}, []byte{0xff}, 255},
+		{arrowIPCField{typ: arrowTypeInt, bitWidth: 16, signed: true}, []byte{0x00, 0x80}, -32768},
+		{arrowIPCField{typ: arrowTypeInt, bitWidth: 32, signed: true}, []byte{0x01, 0, 0, 0}, 1},
+		{arrowIPCField{typ: arrowTypeInt, bitWidth: 64}, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, float64(1<<64 - 1)},
+		{arrowIPCField{typ: arrowTypeFloatingPoint, precision: 1}, []byte{0, 0, 0xc0, 0x3f}, 1.5},
+	}
+	for i, c := range cases {
+		if got := arrowNumber(c.f, c.b); got != c.expect {
+			t.Errorf("case %d mismatch. expected: %v, got: %v", i, c.expect, got)
+		}
+	}
+}
+
+func TestArrowDictionary(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "arrow",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "country", "type": "string"},
+				},
+			},
+		},
+	}
+	countries := []string{"Deutschland", "Österreich", "Schweiz", "Liechtenstein"}
+	row := func(i int) []interface{} {
+		// later batches add dictionary values
+		c := countries[i%2]
+		if i > arrowBatchSize {
+			c = countries[i%len(countries)]
+		}
+		if i%10 == 0 {
+			return []interface{}{i, nil}
+		}
+		return []interface{}{i, c}
+	}
+
+	write := func(opts map[string]interface{}) []byte {
+		st.FormatConfig = opts
+		buf := &bytes.Buffer{}
+		w, err := NewArrowWriter(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3000; i++ {
+			if err := w.WriteEntry(Entry{Index: i, Value: row(i)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := NewArrowReader(st, bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3000; i++ {
+			ent, err := r.ReadEntry()
+			if err != nil {
+				t.Fatalf("entry %d: %s", i, err)
+			}
+			if !reflect.DeepEqual(ent.Value, row(i)) {
+				t.Fatalf("entry %d mismatch. expected: %v, got: %v", i, row(i), ent.Value)
+			}
+		}
+		if _, err := r.ReadEntry(); err != io.EOF {
+			t.Errorf("expected io.EOF, got: %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	plain := write(nil)
+	dict := write(map[string]interface{}{"columns": map[string]interface{}{
+		"country": map[string]interface{}{"encoding": "dictionary"},
+	}})
+	if len(dict) >= len(plain) {
+		t.Errorf("expected dictionary encoding to shrink output. plain: %d bytes, dictionary: %d bytes", len(plain), len(dict))
+	}
+
+	errCases := []struct {
+		cols map[string]interface{}
+		err  string
+	}{
+		{map[string]interface{}{"missing": map[string]interface{}{"encoding": "dictionary"}}, "arrow format config has hints for unknown column 'missing'"},
+		{map[string]interface{}{"id": map[string]interface{}{"encoding": "dictionary"}}, "column id: arrow dictionary encoding requires a string column"},
+		{map[string]interface{}{"id": map[string]interface{}{"encoding": "delta"}}, "column id: arrow doesn't support delta encoding"},
+	}
+	for i, c := range errCases {
+		st.FormatConfig = map[string]interface{}{"columns": c.cols}
+		if _, err := NewArrowWriter(st, &bytes.Buffer{}); err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestArrowMessageWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewArrowWriter(arrowTestStructure, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []ArrowMessage
+	mw, err := NewArrowMessageWriter(arrowTestStructure, func(msg ArrowMessage) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < arrowBatchSize+10; i++ {
+		ent := Entry{Index: i, Value: []interface{}{i, 0.5, true, "name"}}
+		for _, ew := range []EntryWriter{w, mw} {
+			if err := ew.WriteEntry(ent); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a schema message & two record batches
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 messages, got: %d", len(msgs))
+	}
+	// framed, messages are the stream less the end of stream marker
+	framed := &bytes.Buffer{}
+	for _, msg := range msgs {
+		framed.Write([]byte{0xff, 0xff, 0xff, 0xff})
+		binary.Write(framed, binary.LittleEndian, uint32(len(msg.Header)))
+		framed.Write(msg.Header)
+		framed.Write(msg.Body)
+	}
+	stream := buf.Bytes()
+	if !bytes.Equal(framed.Bytes(), stream[:len(stream)-8]) {
+		t.Error("expected framed messages to match the stream")
+	}
+	if mw.BytesProcessed() >= w.BytesProcessed() {
+		t.Errorf("expected message bytes to exclude framing. stream: %d, messages: %d", w.BytesProcessed(), mw.BytesProcessed())
+	}
+
+	schema, err := ArrowSchemaMessage(arrowTestStructure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(stream, schema) {
+		t.Error("expected the schema message to start the stream")
+	}
+}
@@ -0,0 +1,54 @@
+package dsio
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestArrowSchema(t *testing.T) {
+	st := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "score", "type": "number"},
+					map[string]interface{}{"type": "boolean"},
+					map[string]interface{}{"title": "tags", "type": "array", "items": map[string]interface{}{"type": "string"}},
+					map[string]interface{}{"title": "loc", "type": "object", "properties": map[string]interface{}{
+						"lng": map[string]interface{}{"type": "number"},
+						"lat": map[string]interface{}{"type": "number"},
+					}},
+				},
+			},
+		},
+	}
+
+	expect := []ArrowField{
+		{Name: "id", Type: ArrowInt64, Nullable: true},
+		{Name: "score", Type: ArrowFloat64, Nullable: true},
+		{Name: "c", Type: ArrowBool, Nullable: true},
+		{Name: "tags", Type: ArrowList, Nullable: true, Children: []ArrowField{
+			{Name: "item", Type: ArrowUTF8, Nullable: true},
+		}},
+		{Name: "loc", Type: ArrowStruct, Nullable: true, Children: []ArrowField{
+			{Name: "lat", Type: ArrowFloat64, Nullable: true},
+			{Name: "lng", Type: ArrowFloat64, Nullable: true},
+		}},
+	}
+
+	got, err := ArrowSchema(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expect, got) {
+		t.Errorf("schema mismatch.\nexpected: %v\ngot:      %v", expect, got)
+	}
+
+	if _, err := ArrowSchema(&dataset.Structure{Schema: dataset.BaseSchemaObject}); err == nil {
+		t.Error("expected object schema to error")
+	}
+}
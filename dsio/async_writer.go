@@ -0,0 +1,184 @@
+package dsio
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/qri-io/dataset"
+)
+
+// AsyncConfig encapsulates configuration for an AsyncWriter
+type AsyncConfig struct {
+	// QueueSize is the number of entries that can be queued before WriteEntry
+	// blocks. default 128
+	QueueSize int
+	// Budget, if set, bounds the bytes held by queued entries. The budget can
+	// be shared with earlier stages, see MemoryBudget
+	Budget *MemoryBudget
+}
+
+// AsyncWriter wraps an EntryWriter, queuing entries to a bounded channel that's
+// drained by a background goroutine. WriteEntry only blocks when the queue is
+// full, which applies backpressure to producers without making them wait on
+// every write to the underlying writer
+type AsyncWriter struct {
+	w      EntryWriter
+	budget *MemoryBudget
+	queue  chan queuedEntry
+	done   chan struct{}
+	// written counts entries written by the background goroutine
+	written int64
+	// held counts budgeted bytes of queued entries
+	held int64
+
+	lk  sync.Mutex
+	err error
+	// closeLk guards sends against closing the queue
+	closeLk sync.RWMutex
+	closed  bool
+}
+
+var _ EntryWriter = (*AsyncWriter)(nil)
+
+// queuedEntry is an entry & the bytes of budget it holds
+type queuedEntry struct {
+	ent  Entry
+	size int64
+}
+
+// NewAsyncWriter starts a background goroutine writing to w
+func NewAsyncWriter(w EntryWriter, configs ...func(cfg *AsyncConfig)) *AsyncWriter {
+	cfg := &AsyncConfig{
+		QueueSize: 128,
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	if cfg.QueueSize < 0 {
+		cfg.QueueSize = 0
+	}
+
+	aw := &AsyncWriter{
+		w:      w,
+		budget: cfg.Budget,
+		queue:  make(chan queuedEntry, cfg.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go aw.drain()
+	return aw
+}
+
+func (aw *AsyncWriter) drain() {
+	defer close(aw.done)
+	for q := range aw.queue {
+		// release the entry's bytes as it's handed on, writers sharing the
+		// budget acquire their own
+		if aw.budget != nil {
+			aw.budget.releaseStage(q.size, &aw.held)
+		}
+		// after a failed write keep consuming so producers don't block forever
+		if aw.Err() == nil {
+			if err := aw.w.WriteEntry(q.ent); err != nil {
+				log.Debug(err.Error())
+				aw.setErr(fmt.Errorf("error writing entry %d: %w", q.ent.Index, err))
+			} else {
+				atomic.AddInt64(&aw.written, 1)
+			}
+		}
+	}
+}
+
+func (aw *AsyncWriter) setErr(err error) {
+	aw.lk.Lock()
+	if aw.err == nil {
+		aw.err = err
+	}
+	aw.lk.Unlock()
+}
+
+// Err returns the first error encountered by the background writer, if any
+func (aw *AsyncWriter) Err() error {
+	aw.lk.Lock()
+	defer aw.lk.Unlock()
+	return aw.err
+}
+
+// Structure gives the structure of the underlying writer
+func (aw *AsyncWriter) Structure() *dataset.Structure {
+	return aw.w.Structure()
+}
+
+// EntriesWritten gives the number of entries written to the underlying writer,
+// excluding queued entries
+func (aw *AsyncWriter) EntriesWritten() int {
+	return int(atomic.LoadInt64(&aw.written))
+}
+
+// Pending gives the number of entries queued but not yet written
+func (aw *AsyncWriter) Pending() int {
+	return len(aw.queue)
+}
+
+// Cap gives the size of the queue. When Pending equals Cap, WriteEntry blocks
+func (aw *AsyncWriter) Cap() int {
+	return cap(aw.queue)
+}
+
+// WriteEntry queues an entry for writing, blocking while the queue is full.
+// errors from earlier background writes are returned here as soon as
+// they're known
+func (aw *AsyncWriter) WriteEntry(ent Entry) error {
+	return aw.WriteEntryContext(context.Background(), ent)
+}
+
+// WriteEntryContext is WriteEntry, returning ctx's error if ctx is done
+// while waiting for budget or queue space. ConvertParallel & CopyContext
+// write with their context
+func (aw *AsyncWriter) WriteEntryContext(ctx context.Context, ent Entry) error {
+	if err := aw.Err(); err != nil {
+		return err
+	}
+	aw.closeLk.RLock()
+	defer aw.closeLk.RUnlock()
+	if aw.closed {
+		return fmt.Errorf("write to closed writer")
+	}
+	q := queuedEntry{ent: ent}
+	if aw.budget != nil {
+		q.size = aw.budget.clamp(EntrySize(ent))
+		if err := aw.budget.acquireStage(ctx, q.size, &aw.held); err != nil {
+			return err
+		}
+	}
+	select {
+	case aw.queue <- q:
+		return nil
+	case <-ctx.Done():
+		if aw.budget != nil {
+			aw.budget.releaseStage(q.size, &aw.held)
+		}
+		return ctx.Err()
+	}
+}
+
+// Close waits for all queued entries to be written & closes the underlying
+// writer, returning the first error encountered
+func (aw *AsyncWriter) Close() error {
+	aw.closeLk.Lock()
+	if aw.closed {
+		aw.closeLk.Unlock()
+		return aw.Err()
+	}
+	aw.closed = true
+	close(aw.queue)
+	aw.closeLk.Unlock()
+	<-aw.done
+
+	if err := aw.w.Close(); err != nil {
+		log.Debug(err.Error())
+		aw.setErr(err)
+	}
+	return aw.Err()
+}
@@ -0,0 +1,83 @@
+package dsio
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestAsyncWriter(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	buf, err := NewEntryBuffer(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewAsyncWriter(buf, func(cfg *AsyncConfig) { cfg.QueueSize = 4 })
+	if w.Cap() != 4 {
+		t.Errorf("expected cap 4, got: %d", w.Cap())
+	}
+	for i := 0; i < 100; i++ {
+		if err := w.WriteEntry(Entry{Index: i, Value: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.Pending() != 0 {
+		t.Errorf("expected no pending entries after close, got: %d", w.Pending())
+	}
+	if err := w.WriteEntry(Entry{}); err == nil {
+		t.Error("expected write after close to error")
+	}
+
+	i := 0
+	err = EachEntry(buf, func(_ int, ent Entry, err error) error {
+		if ent.Value != i {
+			return fmt.Errorf("entry %d mismatch: %v", i, ent.Value)
+		}
+		i++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i != 100 {
+		t.Errorf("expected 100 entries, got: %d", i)
+	}
+}
+
+type failingWriter struct {
+	EntryWriter
+	after int
+}
+
+func (w *failingWriter) WriteEntry(ent Entry) error {
+	if w.after == 0 {
+		return fmt.Errorf("disk full")
+	}
+	w.after--
+	return w.EntryWriter.WriteEntry(ent)
+}
+
+func TestAsyncWriterError(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	buf, err := NewEntryBuffer(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewAsyncWriter(&failingWriter{EntryWriter: buf, after: 3}, func(cfg *AsyncConfig) { cfg.QueueSize = 1 })
+	// producers must never deadlock on a failed writer
+	for i := 0; i < 50; i++ {
+		if err := w.WriteEntry(Entry{Index: i, Value: i}); err != nil {
+			break
+		}
+	}
+	err = w.Close()
+	if err == nil || err.Error() != "error writing entry 3: disk full" {
+		t.Errorf("expected write error on close, got: %v", err)
+	}
+}
@@ -0,0 +1,766 @@
+package dsio
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+
+	"github.com/qri-io/dataset"
+)
+
+// avro object container files are a header holding the writer's schema,
+// followed by blocks of binary encoded values. see
+// https://avro.apache.org/docs/current/specification/#object-container-files
+
+const (
+	// avroBlockSize is the number of encoded bytes AvroWriter buffers before
+	// writing a block
+	avroBlockSize = 64 * 1024
+	// avroMaxMetadataSize caps the size of a header metadata value
+	avroMaxMetadataSize = 1 << 24
+	// avroMaxDepth caps the nesting of decoded values
+	avroMaxDepth = 256
+)
+
+// avroMagic starts every avro object container file
+var avroMagic = []byte("Obj\x01")
+
+var errAvroCorrupt = fmt.Errorf("corrupt avro data")
+
+// AvroReader implements the EntryReader interface for avro object container
+// files. Values are decoded with the schema stored in the file. Records are
+// read as arrays of field values, or objects keyed by field name if the
+// structure's schema has object items. Values of other types are read as-is.
+// The null, deflate & snappy codecs are supported
+type AvroReader struct {
+	st          *dataset.Structure
+	src         *TrackedReader
+	reader      *bufio.Reader
+	schema      *avroType
+	codec       string
+	sync        []byte
+	objects     bool
+	block       avroDecoder
+	blockCount  int64
+	entriesRead int
+}
+
+var _ EntryReader = (*AvroReader)(nil)
+
+// NewAvroReader creates a reader from a structure and read source, reading
+// the file header
+func NewAvroReader(st *dataset.Structure, r io.Reader) (*AvroReader, error) {
+	src := NewTrackedReader(r)
+	rdr := &AvroReader{
+		st:     st,
+		src:    src,
+		reader: bufio.NewReader(src),
+	}
+	if items, ok := st.Schema["items"].(map[string]interface{}); ok {
+		rdr.objects = items["type"] == "object"
+	}
+	if err := rdr.readHeader(); err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	return rdr, nil
+}
+
+// readHeader reads the magic bytes, metadata & sync marker
+func (r *AvroReader) readHeader() error {
+	magic := make([]byte, len(avroMagic))
+	if _, err := io.ReadFull(r.reader, magic); err != nil || !bytes.Equal(magic, avroMagic) {
+		return newKindError(ErrFormatMismatch, "not an avro object container file")
+	}
+
+	meta := map[string][]byte{}
+	for {
+		count, err := binary.ReadVarint(r.reader)
+		if err != nil {
+			return fmt.Errorf("error reading avro header: %w", errAvroCorrupt)
+		}
+		if count == 0 {
+			break
+		}
+		if count < 0 {
+			count = -count
+			// skip the block's byte size
+			if _, err := binary.ReadVarint(r.reader); err != nil {
+				return fmt.Errorf("error reading avro header: %w", errAvroCorrupt)
+			}
+		}
+		for i := int64(0); i < count; i++ {
+			key, err := r.readHeaderBytes()
+			if err != nil {
+				return err
+			}
+			val, err := r.readHeaderBytes()
+			if err != nil {
+				return err
+			}
+			meta[string(key)] = val
+		}
+	}
+
+	r.sync = make([]byte, 16)
+	if _, err := io.ReadFull(r.reader, r.sync); err != nil {
+		return fmt.Errorf("error reading avro header: %w", errAvroCorrupt)
+	}
+
+	if meta["avro.schema"] == nil {
+		return newKindError(ErrFormatMismatch, "avro file has no schema")
+	}
+	schema, err := parseAvroSchema(meta["avro.schema"])
+	if err != nil {
+		return err
+	}
+	r.schema = schema
+
+	r.codec = string(meta["avro.codec"])
+	switch r.codec {
+	case "":
+		r.codec = dataset.AvroNull
+	case dataset.AvroNull, dataset.AvroDeflate, dataset.AvroSnappy:
+	default:
+		return fmt.Errorf("unsupported avro codec: %s", r.codec)
+	}
+	return nil
+}
+
+func (r *AvroReader) readHeaderBytes() ([]byte, error) {
+	n, err := binary.ReadVarint(r.reader)
+	if err != nil || n < 0 || n > avroMaxMetadataSize {
+		return nil, fmt.Errorf("error reading avro header: %w", errAvroCorrupt)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r.reader, data); err != nil {
+		return nil, fmt.Errorf("error reading avro header: %w", errAvroCorrupt)
+	}
+	return data, nil
+}
+
+// Structure gives this reader's structure
+func (r *AvroReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// Schema gives a JSON schema for the values of the file, see
+// JSONSchemaFromAvro
+func (r *AvroReader) Schema() map[string]interface{} {
+	return avroBodySchema(r.schema)
+}
+
+// ReadEntry reads one value from the reader
+func (r *AvroReader) ReadEntry() (Entry, error) {
+	for r.blockCount == 0 {
+		if err := r.readBlock(); err != nil {
+			if err != io.EOF {
+				log.Debug(err.Error())
+				err = parseError("avro", r.entriesRead, err)
+			}
+			return Entry{}, err
+		}
+	}
+
+	val, err := r.block.row(r.schema, r.objects)
+	if err != nil {
+		log.Debug(err.Error())
+		return Entry{}, parseError("avro", r.entriesRead, err)
+	}
+
+	ent := Entry{Index: r.entriesRead, Value: val}
+	r.blockCount--
+	r.entriesRead++
+	return ent, nil
+}
+
+// readBlock reads & decompresses the next block of values. io.EOF is
+// returned at the end of the file
+func (r *AvroReader) readBlock() error {
+	if _, err := r.reader.Peek(1); err == io.EOF {
+		return io.EOF
+	}
+	count, err := binary.ReadVarint(r.reader)
+	if err != nil || count < 0 {
+		return errAvroCorrupt
+	}
+	size, err := binary.ReadVarint(r.reader)
+	if err != nil || size < 0 {
+		return errAvroCorrupt
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r.reader, size))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errAvroCorrupt
+	}
+	sync := make([]byte, 16)
+	if _, err := io.ReadFull(r.reader, sync); err != nil || !bytes.Equal(sync, r.sync) {
+		return fmt.Errorf("avro block sync marker mismatch: %w", errAvroCorrupt)
+	}
+
+	switch r.codec {
+	case dataset.AvroDeflate:
+		if data, err = ioutil.ReadAll(flate.NewReader(bytes.NewReader(data))); err != nil {
+			return fmt.Errorf("error decompressing avro block: %w", err)
+		}
+	case dataset.AvroSnappy:
+		if len(data) < 4 {
+			return errAvroCorrupt
+		}
+		sum := binary.BigEndian.Uint32(data[len(data)-4:])
+		if data, err = snappyDecode(data[:len(data)-4]); err != nil {
+			return fmt.Errorf("error decompressing avro block: %w", err)
+		}
+		if crc32.ChecksumIEEE(data) != sum {
+			return fmt.Errorf("avro block checksum mismatch: %w", errAvroCorrupt)
+		}
+	}
+
+	r.block = avroDecoder{buf: data}
+	r.blockCount = count
+	return nil
+}
+
+// Close finalizes the reader, closing the source if it's an io.Closer
+func (r *AvroReader) Close() error {
+	return r.src.Close()
+}
+
+// EntriesRead gives the number of entries read
+func (r *AvroReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes read. blocks are read whole
+func (r *AvroReader) BytesProcessed() int64 {
+	return int64(r.src.BytesRead() - r.reader.Buffered())
+}
+
+// avroDecoder decodes binary encoded avro values from a block
+type avroDecoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *avroDecoder) long() (int64, error) {
+	v, n := binary.Varint(d.buf[d.pos:])
+	if n <= 0 {
+		return 0, errAvroCorrupt
+	}
+	d.pos += n
+	return v, nil
+}
+
+func (d *avroDecoder) next(n int64) ([]byte, error) {
+	if n < 0 || n > int64(len(d.buf)-d.pos) {
+		return nil, errAvroCorrupt
+	}
+	b := d.buf[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// blockCount reads the item count of an array or map block
+func (d *avroDecoder) blockCount() (int64, error) {
+	count, err := d.long()
+	if err != nil {
+		return 0, err
+	}
+	if count < 0 {
+		count = -count
+		if _, err := d.long(); err != nil {
+			return 0, err
+		}
+	}
+	// every item but null takes at least one byte
+	if count > int64(len(d.buf)-d.pos)+1<<16 {
+		return 0, errAvroCorrupt
+	}
+	return count, nil
+}
+
+// row decodes a value of the file schema. records are read as arrays of
+// column values unless objects is set
+func (d *avroDecoder) row(schema *avroType, objects bool) (interface{}, error) {
+	if schema.kind != "record" || objects {
+		return d.value(schema, 0)
+	}
+	row := make([]interface{}, len(schema.fields))
+	for i, f := range schema.fields {
+		v, err := d.value(f.typ, 1)
+		if err != nil {
+			return nil, err
+		}
+		row[i] = v
+	}
+	return row, nil
+}
+
+// value decodes a value of type t. ints & longs are read as int, floats &
+// doubles as float64, bytes, strings, fixed & enum symbols as string, arrays
+// as []interface{} and maps & records as map[string]interface{}
+func (d *avroDecoder) value(t *avroType, depth int) (interface{}, error) {
+	if depth > avroMaxDepth {
+		return nil, fmt.Errorf("avro values nested too deeply: %w", errAvroCorrupt)
+	}
+	switch t.kind {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		v, err := d.long()
+		return int(v), err
+	case "float":
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case "double":
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case "bytes", "string":
+		n, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(n)
+		return string(b), err
+	case "fixed":
+		b, err := d.next(int64(t.size))
+		return string(b), err
+	case "enum":
+		i, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(t.symbols)) {
+			return nil, errAvroCorrupt
+		}
+		return t.symbols[i], nil
+	case "union":
+		i, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(t.union)) {
+			return nil, errAvroCorrupt
+		}
+		return d.value(t.union[i], depth+1)
+	case "array":
+		arr := []interface{}{}
+		for {
+			count, err := d.blockCount()
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return arr, nil
+			}
+			for i := int64(0); i < count; i++ {
+				v, err := d.value(t.items, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+		}
+	case "map":
+		m := map[string]interface{}{}
+		for {
+			count, err := d.blockCount()
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return m, nil
+			}
+			for i := int64(0); i < count; i++ {
+				n, err := d.long()
+				if err != nil {
+					return nil, err
+				}
+				key, err := d.next(n)
+				if err != nil {
+					return nil, err
+				}
+				if m[string(key)], err = d.value(t.items, depth+1); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case "record":
+		m := make(map[string]interface{}, len(t.fields))
+		for _, f := range t.fields {
+			v, err := d.value(f.typ, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			m[f.name] = v
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("unsupported avro type: %s", t.kind)
+}
+
+// AvroWriter implements the EntryWriter interface for avro object container
+// files. The writer schema is derived from the structure's schema with
+// AvroSchema. Values are buffered & written in blocks of about 64KiB, the
+// file header is written with the first block. The codec is set with
+// AvroOptions
+type AvroWriter struct {
+	st             *dataset.Structure
+	wr             *countingWriter
+	schema         *avroType
+	codec          string
+	sync           []byte
+	objects        bool
+	block          []byte
+	blockCount     int
+	wroteHeader    bool
+	entriesWritten int
+	closed         bool
+}
+
+var _ EntryWriter = (*AvroWriter)(nil)
+
+// NewAvroWriter creates a writer from a structure and write destination
+func NewAvroWriter(st *dataset.Structure, w io.Writer) (*AvroWriter, error) {
+	schema, err := avroStructureType(st)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	aw := &AvroWriter{
+		st:     st,
+		wr:     &countingWriter{w: w},
+		schema: schema,
+		codec:  dataset.AvroNull,
+	}
+	if fcg, err := dataset.ParseFormatConfigMap(dataset.AvroDataFormat, st.FormatConfig); err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	} else if opts, ok := fcg.(*dataset.AvroOptions); ok && opts.Codec != "" {
+		aw.codec = opts.Codec
+	}
+	if items, ok := st.Schema["items"].(map[string]interface{}); ok {
+		aw.objects = items["type"] == "object"
+	}
+	return aw, nil
+}
+
+// Structure gives this writer's structure
+func (w *AvroWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// WriteEntry encodes a row, writing a block when the buffer is full
+func (w *AvroWriter) WriteEntry(ent Entry) error {
+	// encode the whole record before adding it to the block, so a bad value
+	// doesn't leave a partial record
+	buf, err := avroEncodeRow(nil, w.schema, w.objects, ent.Value, fmt.Sprintf("entry %d", ent.Index))
+	if err != nil {
+		return err
+	}
+	w.block = append(w.block, buf...)
+	w.blockCount++
+	w.entriesWritten++
+
+	if len(w.block) >= avroBlockSize {
+		return w.writeBlock()
+	}
+	return nil
+}
+
+// avroEncodeRow appends the binary encoding of a row to buf. rows are arrays
+// of column values, or objects for schemas with object rows. name describes
+// the row in errors
+func avroEncodeRow(buf []byte, schema *avroType, objects bool, val interface{}, name string) ([]byte, error) {
+	fields := schema.fields
+	row := make([]interface{}, len(fields))
+	switch v := val.(type) {
+	case []interface{}:
+		if objects {
+			return nil, fmt.Errorf("expected object value to write avro record. got: %T", val)
+		}
+		if len(v) > len(fields) {
+			err := fmt.Errorf("%s has %d values, schema has %d columns", name, len(v), len(fields))
+			log.Debug(err.Error())
+			return nil, err
+		}
+		copy(row, v)
+	case map[string]interface{}:
+		if !objects {
+			return nil, fmt.Errorf("expected array value to write avro record. got: %T", val)
+		}
+		for i, f := range fields {
+			row[i] = v[f.key]
+		}
+	default:
+		return nil, fmt.Errorf("expected array or object value to write avro record. got: %T", val)
+	}
+
+	var err error
+	for i, f := range fields {
+		if buf, err = avroEncode(buf, f.typ, row[i]); err != nil {
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("%s field %s: %w", name, f.key, err)
+		}
+	}
+	return buf, nil
+}
+
+// avroEncode appends the binary encoding of v as type t to buf
+func avroEncode(buf []byte, t *avroType, v interface{}) ([]byte, error) {
+	switch t.kind {
+	case "null":
+		if v != nil {
+			return nil, fmt.Errorf("expected null value, got %T", v)
+		}
+		return buf, nil
+	case "boolean":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected boolean value, got %T", v)
+		}
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case "int", "long":
+		switch n := v.(type) {
+		case int:
+			return binary.AppendVarint(buf, int64(n)), nil
+		case int64:
+			return binary.AppendVarint(buf, n), nil
+		case float64:
+			if n == math.Trunc(n) && math.Abs(n) < 1<<63 {
+				return binary.AppendVarint(buf, int64(n)), nil
+			}
+		}
+		return nil, fmt.Errorf("expected integer value, got %T", v)
+	case "float", "double":
+		var f float64
+		switch n := v.(type) {
+		case float64:
+			f = n
+		case int:
+			f = float64(n)
+		case int64:
+			f = float64(n)
+		default:
+			return nil, fmt.Errorf("expected number value, got %T", v)
+		}
+		if t.kind == "float" {
+			return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(f))), nil
+		}
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(f)), nil
+	case "bytes", "string":
+		s, ok := v.(string)
+		if !ok {
+			if t.kind == "bytes" {
+				return nil, fmt.Errorf("expected string value, got %T", v)
+			}
+			// values of untyped columns are written as JSON text
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			s = string(data)
+		}
+		buf = binary.AppendVarint(buf, int64(len(s)))
+		return append(buf, s...), nil
+	case "fixed":
+		s, ok := v.(string)
+		if !ok || len(s) != t.size {
+			return nil, fmt.Errorf("expected string of %d bytes, got %T", t.size, v)
+		}
+		return append(buf, s...), nil
+	case "enum":
+		s, _ := v.(string)
+		for i, sym := range t.symbols {
+			if sym == s {
+				return binary.AppendVarint(buf, int64(i)), nil
+			}
+		}
+		return nil, fmt.Errorf("value %v isn't a symbol of enum %s", v, t.name)
+	case "union":
+		// use the first branch that can encode the value
+		err := fmt.Errorf("no union branch matches %T", v)
+		for i, b := range t.union {
+			if (v == nil) != (b.kind == "null") {
+				continue
+			}
+			enc, berr := avroEncode(binary.AppendVarint(buf, int64(i)), b, v)
+			if berr == nil {
+				return enc, nil
+			}
+			err = berr
+		}
+		return nil, err
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected array value, got %T", v)
+		}
+		if len(arr) > 0 {
+			buf = binary.AppendVarint(buf, int64(len(arr)))
+			var err error
+			for _, item := range arr {
+				if buf, err = avroEncode(buf, t.items, item); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return append(buf, 0), nil
+	case "map":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object value, got %T", v)
+		}
+		if len(m) > 0 {
+			buf = binary.AppendVarint(buf, int64(len(m)))
+			keys := make([]string, 0, len(m))
+			for key := range m {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			var err error
+			for _, key := range keys {
+				buf = binary.AppendVarint(buf, int64(len(key)))
+				buf = append(buf, key...)
+				if buf, err = avroEncode(buf, t.items, m[key]); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return append(buf, 0), nil
+	case "record":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object value, got %T", v)
+		}
+		var err error
+		for _, f := range t.fields {
+			if buf, err = avroEncode(buf, f.typ, m[f.key]); err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.key, err)
+			}
+		}
+		return buf, nil
+	}
+	return nil, fmt.Errorf("unsupported avro type: %s", t.kind)
+}
+
+// writeHeader writes the file header. The sync marker is derived from the
+// schema, so a structure & body always produce the same file
+func (w *AvroWriter) writeHeader() error {
+	schema, err := json.Marshal(w.schema.schema(map[string]bool{}))
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(schema)
+	w.sync = sum[:16]
+
+	hdr := append([]byte{}, avroMagic...)
+	hdr = binary.AppendVarint(hdr, 2)
+	for _, kv := range [][2]string{{"avro.codec", w.codec}, {"avro.schema", string(schema)}} {
+		hdr = binary.AppendVarint(hdr, int64(len(kv[0])))
+		hdr = append(hdr, kv[0]...)
+		hdr = binary.AppendVarint(hdr, int64(len(kv[1])))
+		hdr = append(hdr, kv[1]...)
+	}
+	hdr = append(hdr, 0)
+	hdr = append(hdr, w.sync...)
+	if _, err := w.wr.Write(hdr); err != nil {
+		return fmt.Errorf("error writing avro header: %w", err)
+	}
+	w.wroteHeader = true
+	return nil
+}
+
+// writeBlock compresses & writes buffered values, writing the header first if
+// it hasn't been written
+func (w *AvroWriter) writeBlock() error {
+	if !w.wroteHeader {
+		if err := w.writeHeader(); err != nil {
+			log.Debug(err.Error())
+			return err
+		}
+	}
+	if w.blockCount == 0 {
+		return nil
+	}
+
+	data := w.block
+	switch w.codec {
+	case dataset.AvroDeflate:
+		buf := &bytes.Buffer{}
+		fw, err := flate.NewWriter(buf, flate.DefaultCompression)
+		if err != nil {
+			return err
+		}
+		fw.Write(data)
+		if err := fw.Close(); err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("error compressing avro block: %w", err)
+		}
+		data = buf.Bytes()
+	case dataset.AvroSnappy:
+		data = binary.BigEndian.AppendUint32(snappyEncode(data), crc32.ChecksumIEEE(data))
+	}
+
+	blk := binary.AppendVarint(nil, int64(w.blockCount))
+	blk = binary.AppendVarint(blk, int64(len(data)))
+	blk = append(blk, data...)
+	blk = append(blk, w.sync...)
+	if _, err := w.wr.Write(blk); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing avro block: %w", err)
+	}
+	w.block = w.block[:0]
+	w.blockCount = 0
+	return nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *AvroWriter) EntriesWritten() int {
+	return w.entriesWritten
+}
+
+// BytesProcessed gives the number of bytes written. values are buffered until
+// a block is written
+func (w *AvroWriter) BytesProcessed() int64 {
+	return w.wr.n
+}
+
+// Close writes any buffered values. The destination is closed if it's an
+// io.Closer, wrap it with KeepWriterOpen to leave it open
+func (w *AvroWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.writeBlock(); err != nil {
+		return err
+	}
+	return w.wr.Close()
+}
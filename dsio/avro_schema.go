@@ -0,0 +1,443 @@
+package dsio
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// avroType is a parsed avro schema. see
+// https://avro.apache.org/docs/current/specification/
+type avroType struct {
+	// kind is a primitive type name, or one of record, enum, array, map,
+	// union or fixed
+	kind string
+	// name is the full name of named types: records, enums & fixed
+	name    string
+	fields  []avroField
+	symbols []string
+	// items is the type of array items & map values
+	items *avroType
+	union []*avroType
+	size  int
+}
+
+// avroField is a field of a record
+type avroField struct {
+	name string
+	// key is the source title or property name a field is written from, which
+	// can differ from name when titles aren't valid avro names
+	key string
+	typ *avroType
+}
+
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+// avroNameRegex matches valid avro names
+var avroNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseAvroSchema parses an avro schema from JSON
+func parseAvroSchema(data []byte) (*avroType, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("invalid avro schema: %w", err)
+	}
+	return parseAvroType(v, map[string]*avroType{}, "")
+}
+
+func parseAvroType(v interface{}, names map[string]*avroType, namespace string) (*avroType, error) {
+	switch t := v.(type) {
+	case string:
+		if avroPrimitives[t] {
+			return &avroType{kind: t}, nil
+		}
+		// reference to a named type
+		if namespace != "" && !strings.Contains(t, ".") {
+			if named, ok := names[namespace+"."+t]; ok {
+				return named, nil
+			}
+		}
+		if named, ok := names[t]; ok {
+			return named, nil
+		}
+		return nil, fmt.Errorf("invalid avro schema: unknown type %q", t)
+	case []interface{}:
+		u := &avroType{kind: "union"}
+		for _, branch := range t {
+			bt, err := parseAvroType(branch, names, namespace)
+			if err != nil {
+				return nil, err
+			}
+			u.union = append(u.union, bt)
+		}
+		return u, nil
+	case map[string]interface{}:
+		kind, _ := t["type"].(string)
+		if kind == "" {
+			// {"type": {...}} wraps a complex type
+			return parseAvroType(t["type"], names, namespace)
+		}
+		switch kind {
+		case "record", "error", "enum", "fixed":
+			name, _ := t["name"].(string)
+			if name == "" {
+				return nil, fmt.Errorf("invalid avro schema: %s requires a name", kind)
+			}
+			ns, _ := t["namespace"].(string)
+			if ns == "" && !strings.Contains(name, ".") {
+				ns = namespace
+			}
+			fullname := name
+			if ns != "" && !strings.Contains(name, ".") {
+				fullname = ns + "." + name
+			}
+			if i := strings.LastIndex(fullname, "."); i > 0 {
+				ns = fullname[:i]
+			}
+
+			at := &avroType{kind: kind, name: fullname}
+			if kind == "error" {
+				at.kind = "record"
+			}
+			names[fullname] = at
+			switch kind {
+			case "enum":
+				syms, _ := t["symbols"].([]interface{})
+				for _, s := range syms {
+					sym, _ := s.(string)
+					at.symbols = append(at.symbols, sym)
+				}
+			case "fixed":
+				size, _ := t["size"].(float64)
+				if size < 0 {
+					return nil, fmt.Errorf("invalid avro schema: negative fixed size")
+				}
+				at.size = int(size)
+			default:
+				fields, _ := t["fields"].([]interface{})
+				for _, f := range fields {
+					fm, _ := f.(map[string]interface{})
+					fname, _ := fm["name"].(string)
+					if fname == "" {
+						return nil, fmt.Errorf("invalid avro schema: record %s field requires a name", name)
+					}
+					ft, err := parseAvroType(fm["type"], names, ns)
+					if err != nil {
+						return nil, err
+					}
+					at.fields = append(at.fields, avroField{name: fname, key: fname, typ: ft})
+				}
+			}
+			return at, nil
+		case "array":
+			items, err := parseAvroType(t["items"], names, namespace)
+			if err != nil {
+				return nil, err
+			}
+			return &avroType{kind: "array", items: items}, nil
+		case "map":
+			values, err := parseAvroType(t["values"], names, namespace)
+			if err != nil {
+				return nil, err
+			}
+			return &avroType{kind: "map", items: values}, nil
+		default:
+			// primitives with attributes like logicalType
+			return parseAvroType(kind, names, namespace)
+		}
+	}
+	return nil, fmt.Errorf("invalid avro schema: unexpected %T", v)
+}
+
+// schema gives the JSON representation of an avro type. named types are
+// defined the first time they're encountered & referenced by name after
+func (t *avroType) schema(defined map[string]bool) interface{} {
+	switch t.kind {
+	case "union":
+		branches := make([]interface{}, len(t.union))
+		for i, b := range t.union {
+			branches[i] = b.schema(defined)
+		}
+		return branches
+	case "array":
+		return map[string]interface{}{"type": "array", "items": t.items.schema(defined)}
+	case "map":
+		return map[string]interface{}{"type": "map", "values": t.items.schema(defined)}
+	case "record", "enum", "fixed":
+		if defined[t.name] {
+			return t.name
+		}
+		defined[t.name] = true
+		sch := map[string]interface{}{"type": t.kind, "name": t.name}
+		switch t.kind {
+		case "enum":
+			syms := make([]interface{}, len(t.symbols))
+			for i, s := range t.symbols {
+				syms[i] = s
+			}
+			sch["symbols"] = syms
+		case "fixed":
+			sch["size"] = t.size
+		default:
+			fields := make([]interface{}, len(t.fields))
+			for i, f := range t.fields {
+				fields[i] = map[string]interface{}{"name": f.name, "type": f.typ.schema(defined)}
+			}
+			sch["fields"] = fields
+		}
+		return sch
+	}
+	return t.kind
+}
+
+// AvroSchema converts a structure's schema to an avro record schema. The
+// structure must describe tabular data: an array of arrays with column
+// definitions, or an array of objects with properties. Every field is
+// nullable. Column titles that aren't valid avro names are converted,
+// replacing invalid characters with underscores
+func AvroSchema(st *dataset.Structure) (map[string]interface{}, error) {
+	at, err := avroStructureType(st)
+	if err != nil {
+		return nil, err
+	}
+	return at.schema(map[string]bool{}).(map[string]interface{}), nil
+}
+
+// avroStructureType builds the avro record type for a structure
+func avroStructureType(st *dataset.Structure) (*avroType, error) {
+	errTabular := newKindError(ErrBadSchema, "avro requires a tabular schema: an array of arrays with column definitions or an array of objects with properties")
+	if st == nil || st.Schema == nil || st.Schema["type"] != "array" {
+		return nil, errTabular
+	}
+	items, ok := st.Schema["items"].(map[string]interface{})
+	if !ok {
+		return nil, errTabular
+	}
+
+	b := &avroBuilder{names: map[string]bool{"Row": true}}
+	row := &avroType{kind: "record", name: "Row"}
+	switch items["type"] {
+	case "array":
+		cols, ok := items["items"].([]interface{})
+		if !ok || len(cols) == 0 {
+			return nil, errTabular
+		}
+		keys := make([]string, len(cols))
+		defs := make([]map[string]interface{}, len(cols))
+		for i, c := range cols {
+			col, _ := c.(map[string]interface{})
+			title, _ := col["title"].(string)
+			if title == "" {
+				title = dataset.AbstractColumnName(i)
+			}
+			keys[i], defs[i] = title, col
+		}
+		row.fields = b.fields(keys, defs)
+	case "object":
+		props, ok := items["properties"].(map[string]interface{})
+		if !ok || len(props) == 0 {
+			return nil, errTabular
+		}
+		keys := sortedKeys(props)
+		defs := make([]map[string]interface{}, len(keys))
+		for i, key := range keys {
+			defs[i], _ = props[key].(map[string]interface{})
+		}
+		row.fields = b.fields(keys, defs)
+	default:
+		return nil, errTabular
+	}
+	return row, nil
+}
+
+// avroBuilder converts JSON schema definitions to avro types, keeping record
+// names unique
+type avroBuilder struct {
+	names map[string]bool
+}
+
+// fields builds record fields for a list of keys & their definitions
+func (b *avroBuilder) fields(keys []string, defs []map[string]interface{}) []avroField {
+	used := map[string]bool{}
+	fields := make([]avroField, len(keys))
+	for i, key := range keys {
+		name := avroName(key)
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", avroName(key), n)
+		}
+		used[name] = true
+		fields[i] = avroField{
+			name: name,
+			key:  key,
+			typ:  &avroType{kind: "union", union: []*avroType{{kind: "null"}, b.valueType(name, defs[i])}},
+		}
+	}
+	return fields
+}
+
+// valueType gives the non-null avro type for a JSON schema definition
+func (b *avroBuilder) valueType(name string, sch map[string]interface{}) *avroType {
+	typ := sch["type"]
+	if ta, ok := typ.([]interface{}); ok {
+		// use the first non-null type of a type list
+		typ = nil
+		for _, t := range ta {
+			if t != "null" {
+				typ = t
+				break
+			}
+		}
+	}
+
+	switch typ {
+	case "boolean":
+		return &avroType{kind: "boolean"}
+	case "integer":
+		return &avroType{kind: "long"}
+	case "number":
+		return &avroType{kind: "double"}
+	case "array":
+		items, ok := sch["items"].(map[string]interface{})
+		if !ok {
+			break
+		}
+		return &avroType{kind: "array", items: &avroType{kind: "union", union: []*avroType{{kind: "null"}, b.valueType(name, items)}}}
+	case "object":
+		props, ok := sch["properties"].(map[string]interface{})
+		if !ok || len(props) == 0 {
+			break
+		}
+		recName := name + "_record"
+		for n := 2; b.names[recName]; n++ {
+			recName = fmt.Sprintf("%s_record_%d", name, n)
+		}
+		b.names[recName] = true
+		keys := sortedKeys(props)
+		defs := make([]map[string]interface{}, len(keys))
+		for i, key := range keys {
+			defs[i], _ = props[key].(map[string]interface{})
+		}
+		return &avroType{kind: "record", name: recName, fields: b.fields(keys, defs)}
+	}
+	// strings, and untyped values which are written as JSON text
+	return &avroType{kind: "string"}
+}
+
+// avroName converts a string to a valid avro name
+func avroName(s string) string {
+	if avroNameRegex.MatchString(s) {
+		return s
+	}
+	name := []byte(s)
+	for i, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || name[0] >= '0' && name[0] <= '9' {
+		name = append([]byte{'_'}, name...)
+	}
+	return string(name)
+}
+
+// JSONSchemaFromAvro converts an avro schema to a JSON schema describing a
+// dataset body of avro values. Record schemas become tabular schemas with a
+// column per field, other schemas describe an array of values
+func JSONSchemaFromAvro(data []byte) (map[string]interface{}, error) {
+	at, err := parseAvroSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	return avroBodySchema(at), nil
+}
+
+// avroBodySchema gives the JSON schema for a body of avro values
+func avroBodySchema(at *avroType) map[string]interface{} {
+	if at.kind == "record" {
+		cols := make([]interface{}, len(at.fields))
+		for i, f := range at.fields {
+			col := avroJSONSchema(f.typ, map[string]bool{at.name: true})
+			col["title"] = f.name
+			cols[i] = col
+		}
+		return map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "array", "items": cols},
+		}
+	}
+	return map[string]interface{}{
+		"type":  "array",
+		"items": avroJSONSchema(at, map[string]bool{}),
+	}
+}
+
+// avroJSONSchema converts an avro type to a JSON schema definition. seen holds
+// records being converted, recursive references become untyped definitions
+func avroJSONSchema(at *avroType, seen map[string]bool) map[string]interface{} {
+	switch at.kind {
+	case "null":
+		return map[string]interface{}{"type": "null"}
+	case "boolean":
+		return map[string]interface{}{"type": "boolean"}
+	case "int", "long":
+		return map[string]interface{}{"type": "integer"}
+	case "float", "double":
+		return map[string]interface{}{"type": "number"}
+	case "bytes", "string", "fixed":
+		return map[string]interface{}{"type": "string"}
+	case "enum":
+		syms := make([]interface{}, len(at.symbols))
+		for i, s := range at.symbols {
+			syms[i] = s
+		}
+		return map[string]interface{}{"type": "string", "enum": syms}
+	case "array":
+		return map[string]interface{}{"type": "array", "items": avroJSONSchema(at.items, seen)}
+	case "map":
+		return map[string]interface{}{"type": "object"}
+	case "record":
+		if seen[at.name] {
+			return map[string]interface{}{}
+		}
+		seen[at.name] = true
+		defer delete(seen, at.name)
+		props := make(map[string]interface{}, len(at.fields))
+		for _, f := range at.fields {
+			props[f.name] = avroJSONSchema(f.typ, seen)
+		}
+		return map[string]interface{}{"type": "object", "properties": props}
+	case "union":
+		var branches []*avroType
+		for _, b := range at.union {
+			if b.kind != "null" {
+				branches = append(branches, b)
+			}
+		}
+		// nullable types are described by their non-null type
+		if len(branches) == 1 {
+			return avroJSONSchema(branches[0], seen)
+		}
+		types := []string{}
+		for _, b := range at.union {
+			if t, ok := avroJSONSchema(b, seen)["type"].(string); ok {
+				types = append(types, t)
+			}
+		}
+		sort.Strings(types)
+		uniq := []interface{}{}
+		for i, t := range types {
+			if i == 0 || types[i-1] != t {
+				uniq = append(uniq, t)
+			}
+		}
+		return map[string]interface{}{"type": uniq}
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,101 @@
+package dsio
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestAvroSchema(t *testing.T) {
+	got, err := AvroSchema(avroTestStructure(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"fields":[` +
+		`{"name":"id","type":["null","long"]},` +
+		`{"name":"score","type":["null","double"]},` +
+		`{"name":"ok","type":["null","boolean"]},` +
+		`{"name":"full_name","type":["null","string"]},` +
+		`{"name":"tags","type":["null",{"items":["null","string"],"type":"array"}]},` +
+		`{"name":"loc","type":["null",{"fields":[{"name":"lat","type":["null","double"]},{"name":"lng","type":["null","double"]}],"name":"loc_record","type":"record"}]},` +
+		`{"name":"any","type":["null","string"]}` +
+		`],"name":"Row","type":"record"}`
+	data, _ := json.Marshal(got)
+	if string(data) != expect {
+		t.Errorf("schema mismatch.\nexpected: %s\ngot:      %s", expect, data)
+	}
+
+	if _, err := AvroSchema(&dataset.Structure{Schema: dataset.BaseSchemaArray}); err == nil {
+		t.Error("expected error converting a schema without column definitions")
+	}
+}
+
+func TestAvroName(t *testing.T) {
+	cases := []struct {
+		in, expect string
+	}{
+		{"name", "name"},
+		{"full name", "full_name"},
+		{"2020", "_2020"},
+		{"", "_"},
+		{"ラーメン", "____________"},
+	}
+	for i, c := range cases {
+		if got := avroName(c.in); got != c.expect {
+			t.Errorf("case %d mismatch. expected: '%s', got: '%s'", i, c.expect, got)
+		}
+	}
+
+	b := &avroBuilder{names: map[string]bool{}}
+	fields := b.fields([]string{"a b", "a_b"}, []map[string]interface{}{{}, {}})
+	if fields[0].name != "a_b" || fields[1].name != "a_b_2" {
+		t.Errorf("expected duplicate names to be suffixed, got: %s, %s", fields[0].name, fields[1].name)
+	}
+}
+
+func TestJSONSchemaFromAvro(t *testing.T) {
+	cases := []struct {
+		avro   string
+		expect string
+		err    string
+	}{
+		{`{"type":"record","name":"R","fields":[
+			{"name":"a","type":"int"},
+			{"name":"b","type":["null","double"]},
+			{"name":"c","type":{"type":"enum","name":"E","symbols":["X","Y"]}},
+			{"name":"d","type":{"type":"array","items":"string"}},
+			{"name":"e","type":{"type":"map","values":"long"}},
+			{"name":"f","type":["int","string"]},
+			{"name":"next","type":["null","R"]}
+		]}`,
+			`{"items":{"items":[` +
+				`{"title":"a","type":"integer"},` +
+				`{"title":"b","type":"number"},` +
+				`{"enum":["X","Y"],"title":"c","type":"string"},` +
+				`{"items":{"type":"string"},"title":"d","type":"array"},` +
+				`{"title":"e","type":"object"},` +
+				`{"title":"f","type":["integer","string"]},` +
+				`{"title":"next"}` +
+				`],"type":"array"},"type":"array"}`, ""},
+		{`"string"`, `{"items":{"type":"string"},"type":"array"}`, ""},
+		{`{"type":"record","name":"R","fields":[{"name":"a","type":"Missing"}]}`, "", `invalid avro schema: unknown type "Missing"`},
+		{`{`, "", "invalid avro schema: unexpected end of JSON input"},
+	}
+	for i, c := range cases {
+		got, err := JSONSchemaFromAvro([]byte(c.avro))
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		data, _ := json.Marshal(got)
+		if string(data) != c.expect {
+			t.Errorf("case %d mismatch.\nexpected: %s\ngot:      %s", i, c.expect, data)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func avroTestStructure(opts map[string]interface{}) *dataset.Structure {
+	return &dataset.Structure{
+		Format:       "avro",
+		FormatConfig: opts,
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "score", "type": "number"},
+					map[string]interface{}{"title": "ok", "type": "boolean"},
+					map[string]interface{}{"title": "full name", "type": "string"},
+					map[string]interface{}{"title": "tags", "type": "array", "items": map[string]interface{}{"type": "string"}},
+					map[string]interface{}{"title": "loc", "type": "object", "properties": map[string]interface{}{
+						"lat": map[string]interface{}{"type": "number"},
+						"lng": map[string]interface{}{"type": "number"},
+					}},
+					map[string]interface{}{"title": "any"},
+				},
+			},
+		},
+	}
+}
+
+func TestAvroRoundTrip(t *testing.T) {
+	rows := []interface{}{
+		[]interface{}{1, 0.5, true, "alpha", []interface{}{"a", "b"}, map[string]interface{}{"lat": 1.5, "lng": -2}, "x"},
+		[]interface{}{nil, nil, nil, nil, nil, nil, nil},
+		[]interface{}{-1 << 40, 3, false, "ラーメン", []interface{}{}, map[string]interface{}{"lat": nil}, 12},
+		[]interface{}{2, 1.25, true, "", []interface{}{nil, "c"}},
+	}
+	expect := []interface{}{
+		[]interface{}{1, 0.5, true, "alpha", []interface{}{"a", "b"}, map[string]interface{}{"lat": 1.5, "lng": -2.0}, "x"},
+		[]interface{}{nil, nil, nil, nil, nil, nil, nil},
+		[]interface{}{-1 << 40, 3.0, false, "ラーメン", []interface{}{}, map[string]interface{}{"lat": nil, "lng": nil}, "12"},
+		[]interface{}{2, 1.25, true, "", []interface{}{nil, "c"}, nil, nil},
+	}
+
+	for _, codec := range []string{"", "null", "deflate", "snappy"} {
+		st := avroTestStructure(nil)
+		if codec != "" {
+			st = avroTestStructure(map[string]interface{}{"codec": codec})
+		}
+		buf := &bytes.Buffer{}
+		w, err := NewEntryWriter(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, row := range rows {
+			if err := w.WriteEntry(Entry{Index: i, Value: row}); err != nil {
+				t.Fatalf("codec %q entry %d: %s", codec, i, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if w.(*AvroWriter).BytesProcessed() != int64(buf.Len()) {
+			t.Errorf("codec %q bytes processed mismatch. expected: %d, got: %d", codec, buf.Len(), w.(*AvroWriter).BytesProcessed())
+		}
+
+		r, err := NewEntryReader(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, ex := range expect {
+			ent, err := r.ReadEntry()
+			if err != nil {
+				t.Fatalf("codec %q entry %d: %s", codec, i, err)
+			}
+			if ent.Index != i {
+				t.Errorf("codec %q entry %d index mismatch. got: %d", codec, i, ent.Index)
+			}
+			if !reflect.DeepEqual(ex, ent.Value) {
+				t.Errorf("codec %q entry %d mismatch.\nexpected: %#v\ngot:      %#v", codec, i, ex, ent.Value)
+			}
+		}
+		if _, err := r.ReadEntry(); err != io.EOF {
+			t.Errorf("codec %q expected io.EOF, got: %v", codec, err)
+		}
+	}
+}
+
+func TestAvroDeterministic(t *testing.T) {
+	write := func() []byte {
+		buf := &bytes.Buffer{}
+		w, _ := NewAvroWriter(avroTestStructure(nil), buf)
+		w.WriteEntry(Entry{Value: []interface{}{1, 2.5}})
+		w.Close()
+		return buf.Bytes()
+	}
+	if !bytes.Equal(write(), write()) {
+		t.Error("expected writing the same entries to produce the same bytes")
+	}
+}
+
+func TestAvroObjectRows(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "avro",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"a": map[string]interface{}{"type": "integer"},
+					"b": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewAvroWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// write enough rows to fill several blocks
+	n := 20000
+	for i := 0; i < n; i++ {
+		if err := w.WriteEntry(Entry{Index: i, Value: map[string]interface{}{"a": i, "b": "row"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteEntry(Entry{Value: []interface{}{1}}); err == nil {
+		t.Error("expected writing an array row to an object schema to error")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewAvroReader(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	err = EachEntry(r, func(i int, ent Entry, err error) error {
+		expect := map[string]interface{}{"a": i, "b": "row"}
+		if !reflect.DeepEqual(expect, ent.Value) {
+			t.Errorf("entry %d mismatch. got: %v", i, ent.Value)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != n {
+		t.Errorf("expected %d entries, got: %d", n, count)
+	}
+}
+
+func TestAvroEmptyFile(t *testing.T) {
+	st := avroTestStructure(nil)
+	buf := &bytes.Buffer{}
+	w, err := NewAvroWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewAvroReader(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.schema.fields) != 7 {
+		t.Errorf("expected 7 schema fields, got: %d", len(r.schema.fields))
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+}
+
+// avroTestFile builds an object container file with the null codec from a
+// schema & a single block of encoded values
+func avroTestFile(schema string, count int, values []byte) []byte {
+	sync := []byte("0123456789abcdef")
+	f := append([]byte{}, avroMagic...)
+	f = binary.AppendVarint(f, 1)
+	f = binary.AppendVarint(f, int64(len("avro.schema")))
+	f = append(f, "avro.schema"...)
+	f = binary.AppendVarint(f, int64(len(schema)))
+	f = append(f, schema...)
+	f = append(f, 0)
+	f = append(f, sync...)
+	f = binary.AppendVarint(f, int64(count))
+	f = binary.AppendVarint(f, int64(len(values)))
+	f = append(f, values...)
+	return append(f, sync...)
+}
+
+func TestAvroReadTypes(t *testing.T) {
+	schema := `{
+		"type": "record", "name": "Event", "namespace": "com.example",
+		"fields": [
+			{"name": "n", "type": "int"},
+			{"name": "f", "type": "float"},
+			{"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["A", "B"]}},
+			{"name": "attrs", "type": {"type": "map", "values": "long"}},
+			{"name": "hash", "type": {"type": "fixed", "name": "Hash", "size": 2}},
+			{"name": "prev", "type": ["null", "Kind"]},
+			{"name": "ts", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+			{"name": "raw", "type": "bytes"}
+		]
+	}`
+	var v []byte
+	v = binary.AppendVarint(v, -3)
+	v = binary.LittleEndian.AppendUint32(v, math.Float32bits(0.5))
+	v = binary.AppendVarint(v, 1)
+	// a map block with a negative count is followed by it's byte size
+	v = binary.AppendVarint(v, -1)
+	v = binary.AppendVarint(v, 3)
+	v = binary.AppendVarint(v, 1)
+	v = append(v, 'k')
+	v = binary.AppendVarint(v, 9)
+	v = append(v, 0)
+	v = append(v, "hi"...)
+	v = binary.AppendVarint(v, 1)
+	v = binary.AppendVarint(v, 0)
+	v = binary.AppendVarint(v, 1500)
+	v = binary.AppendVarint(v, 2)
+	v = append(v, 0xff, 0x00)
+
+	st := &dataset.Structure{Format: "avro", Schema: dataset.BaseSchemaArray}
+	r, err := NewAvroReader(st, bytes.NewReader(avroTestFile(schema, 1, v)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []interface{}{-3, 0.5, "B", map[string]interface{}{"k": 9}, "hi", "A", 1500, "\xff\x00"}
+	if !reflect.DeepEqual(expect, ent.Value) {
+		t.Errorf("value mismatch.\nexpected: %#v\ngot:      %#v", expect, ent.Value)
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+
+	// non-record values are read as-is
+	r, err = NewAvroReader(st, bytes.NewReader(avroTestFile(`{"type":"array","items":"string"}`, 1, []byte{2, 2, 'a', 0})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent, err = r.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]interface{}{"a"}, ent.Value) {
+		t.Errorf("value mismatch. got: %#v", ent.Value)
+	}
+}
+
+func TestAvroWriterErrors(t *testing.T) {
+	if _, err := NewAvroWriter(&dataset.Structure{Format: "avro", Schema: dataset.BaseSchemaObject}, &bytes.Buffer{}); err == nil {
+		t.Error("expected error creating writer with an object schema")
+	}
+	if _, err := NewAvroWriter(avroTestStructure(map[string]interface{}{"codec": "lz4"}), &bytes.Buffer{}); err == nil {
+		t.Error("expected error creating writer with an unsupported codec")
+	}
+
+	cases := []struct {
+		val interface{}
+		err string
+	}{
+		{"nope", "expected array or object value to write avro record. got: string"},
+		{map[string]interface{}{}, "expected array value to write avro record. got: map[string]interface {}"},
+		{[]interface{}{1, 1, true, "a", nil, nil, nil, "extra"}, "entry 0 has 8 values, schema has 7 columns"},
+		{[]interface{}{"1"}, "entry 0 field id: expected integer value, got string"},
+		{[]interface{}{1.5}, "entry 0 field id: expected integer value, got float64"},
+		{[]interface{}{1, true}, "entry 0 field score: expected number value, got bool"},
+		{[]interface{}{1, 1, "true"}, "entry 0 field ok: expected boolean value, got string"},
+		{[]interface{}{1, 1, true, "a", "tags"}, "entry 0 field tags: expected array value, got string"},
+		{[]interface{}{1, 1, true, "a", nil, map[string]interface{}{"lat": "north"}}, "entry 0 field loc: field lat: expected number value, got string"},
+	}
+	for i, c := range cases {
+		w, err := NewAvroWriter(avroTestStructure(nil), &bytes.Buffer{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = w.WriteEntry(Entry{Value: c.val})
+		if err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+		if w.blockCount != 0 || len(w.block) != 0 {
+			t.Errorf("case %d expected failed write to leave the block empty", i)
+		}
+	}
+}
+
+func TestAvroReaderErrors(t *testing.T) {
+	st := avroTestStructure(nil)
+	buf := &bytes.Buffer{}
+	w, _ := NewAvroWriter(st, buf)
+	w.WriteEntry(Entry{Value: []interface{}{1, 2.5, true, "a"}})
+	w.Close()
+	valid := buf.Bytes()
+
+	badSync := append([]byte{}, valid...)
+	badSync[len(badSync)-1]++
+
+	cases := []struct {
+		data []byte
+		err  string
+	}{
+		{[]byte{}, "not an avro object container file"},
+		{[]byte("a,b,c\n"), "not an avro object container file"},
+		{avroTestFile(`"nope"`, 0, nil), `invalid avro schema: unknown type "nope"`},
+		{valid[:len(valid)-20], ""},
+		{badSync, ""},
+		{avroTestFile(`{"type":"enum","name":"E","symbols":["A"]}`, 1, []byte{4}), ""},
+		{avroTestFile(`"string"`, 1, []byte{40, 'a'}), ""},
+	}
+	for i, c := range cases {
+		r, err := NewAvroReader(st, bytes.NewReader(c.data))
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if _, err := r.ReadEntry(); err == nil || err == io.EOF {
+			t.Errorf("case %d expected error reading corrupt block, got: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package dsio
+
+import (
+	"fmt"
+	"io"
+)
+
+// maxBatchPrealloc caps the entries allocated up front for a batch, so large
+// batch sizes don't allocate for entries a reader doesn't have
+const maxBatchPrealloc = 1024
+
+// BatchReader is implemented by readers that read entries in batches, so
+// handing out many entries at once is cheaper than reading them one at a time
+type BatchReader interface {
+	// ReadEntries reads up to n entries. Fewer than n entries are returned at
+	// the end of the data, after which ReadEntries returns io.EOF. If reading
+	// fails the entries read before the error are returned with it
+	ReadEntries(n int) ([]Entry, error)
+}
+
+// ReadEntries reads up to n entries from r, using r's own ReadEntries method
+// if it's a BatchReader & reading entries one at a time otherwise. Fewer than
+// n entries are returned at the end of the data, after which ReadEntries
+// returns io.EOF. If reading fails the entries read before the error are
+// returned with it
+func ReadEntries(r EntryReader, n int) ([]Entry, error) {
+	if br, ok := r.(BatchReader); ok {
+		return br.ReadEntries(n)
+	}
+	return readEntries(r.ReadEntry, n)
+}
+
+// EntrySkipper is implemented by readers that can read past entries without
+// decoding them, making it cheap to start reading at an offset
+type EntrySkipper interface {
+	// SkipEntries reads past up to n entries, giving the number skipped.
+	// Skipped entries count toward EntriesRead. If the data ends before n
+	// entries are skipped SkipEntries returns io.EOF with the count
+	SkipEntries(n int) (int, error)
+}
+
+// SkipEntries reads past n entries of r, using r's own SkipEntries method if
+// it's an EntrySkipper & reading entries one at a time otherwise
+func SkipEntries(r EntryReader, n int) (int, error) {
+	if sk, ok := r.(EntrySkipper); ok {
+		return sk.SkipEntries(n)
+	}
+	return skipEntries(r.ReadEntry, n)
+}
+
+// skipEntries is the shared SkipEntries implementation, calling next once
+// per entry
+func skipEntries(next func() (Entry, error), n int) (int, error) {
+	skipped := 0
+	for skipped < n {
+		if _, err := next(); err != nil {
+			return skipped, err
+		}
+		skipped++
+	}
+	return skipped, nil
+}
+
+// readEntries is the shared ReadEntries implementation, calling next once per
+// entry & collecting entries into a single allocated batch
+func readEntries(next func() (Entry, error), n int) ([]Entry, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("batch size must be greater than zero, got: %d", n)
+	}
+
+	ents := make([]Entry, 0, batchPrealloc(n))
+	for len(ents) < n {
+		ent, err := next()
+		if err == io.EOF {
+			if len(ents) == 0 {
+				return nil, io.EOF
+			}
+			return ents, nil
+		} else if err != nil {
+			return ents, err
+		}
+		ents = append(ents, ent)
+	}
+	return ents, nil
+}
+
+// batchPrealloc gives the capacity to allocate for a batch of n entries
+func batchPrealloc(n int) int {
+	if n > maxBatchPrealloc {
+		return maxBatchPrealloc
+	}
+	return n
+}
@@ -0,0 +1,129 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestReadEntries(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewJSONReader(st, bytes.NewBufferString(`[1,2,3,4,5]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expect := range []int{2, 2, 1} {
+		ents, err := ReadEntries(r, 2)
+		if err != nil {
+			t.Fatalf("batch %d unexpected error: %s", i, err)
+		}
+		if len(ents) != expect {
+			t.Errorf("batch %d length mismatch. expected: %d, got: %d", i, expect, len(ents))
+		}
+	}
+	if ents, err := ReadEntries(r, 2); err != io.EOF || ents != nil {
+		t.Errorf("expected nil, io.EOF at end of data. got: %v, %v", ents, err)
+	}
+	if r.EntriesRead() != 5 {
+		t.Errorf("expected 5 entries read, got: %d", r.EntriesRead())
+	}
+
+	if _, err := ReadEntries(r, 0); err == nil {
+		t.Error("expected zero batch size to error")
+	}
+}
+
+func TestReadEntriesError(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewJSONReader(st, bytes.NewBufferString(`[1,2 3]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ents, err := ReadEntries(SafeReader(r), 10)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(ents) != 2 {
+		t.Errorf("expected entries read before the error to be returned, got: %v", ents)
+	}
+}
+
+func TestSkipEntries(t *testing.T) {
+	vals := []interface{}{
+		map[string]interface{}{"a": []interface{}{"]}", int64(1)}, "b": `"\`},
+		[]interface{}{true, nil, 1.5, map[string]interface{}{}},
+		"{[",
+		int64(-12),
+		"last",
+	}
+	encode := func(st *dataset.Structure) []byte {
+		buf := &bytes.Buffer{}
+		w, err := NewEntryWriter(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range vals {
+			if err := w.WriteEntry(Entry{Index: i, Value: v}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	cases := []struct {
+		st   *dataset.Structure
+		data []byte
+	}{
+		{&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}, nil},
+		{&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject}, []byte(`{"a" : {"x":"}"} , "b":[[1],[2]],"c":"[","d":-1e3,"e":"last"}`)},
+		{&dataset.Structure{Format: "cbor", Schema: dataset.BaseSchemaArray}, nil},
+		{&dataset.Structure{Format: "csv", Schema: dataset.BaseSchemaArray}, []byte("1,\"a,\"\"b\"\"\"\n2,c\n3,d\n4,e\n5,last\n")},
+	}
+
+	for _, c := range cases {
+		if c.data == nil {
+			c.data = encode(c.st)
+		}
+		r, err := NewEntryReader(c.st, bytes.NewReader(c.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := r.(EntrySkipper); !ok {
+			t.Errorf("%s: expected reader to be an EntrySkipper", c.st.Format)
+		}
+		n, err := SkipEntries(r, 4)
+		if err != nil || n != 4 {
+			t.Fatalf("%s: expected 4 entries skipped, got: %d, %v", c.st.Format, n, err)
+		}
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("%s: reading after skip: %s", c.st.Format, err)
+		}
+		got := ent.Value
+		if row, ok := got.([]interface{}); ok {
+			got = row[len(row)-1]
+		}
+		if got != "last" {
+			t.Errorf("%s: expected last entry after skipping, got: %v", c.st.Format, ent.Value)
+		}
+		if n, err := SkipEntries(r, 2); err != io.EOF || n != 0 {
+			t.Errorf("%s: expected 0, io.EOF skipping past the end, got: %d, %v", c.st.Format, n, err)
+		}
+	}
+
+	// readers that can't skip read entries one at a time
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewJSONReader(st, bytes.NewBufferString(`[1,2,3]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := SkipEntries(SafeReader(r), 5); err != io.EOF || n != 3 {
+		t.Errorf("expected 3, io.EOF, got: %d, %v", n, err)
+	}
+}
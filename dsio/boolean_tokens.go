@@ -0,0 +1,58 @@
+package dsio
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// booleanTokens reads & writes booleans in text formats using the tokens of
+// a format config. a nil booleanTokens uses the default tokens
+type booleanTokens struct {
+	values      map[string]bool
+	true, false string
+}
+
+// newBooleanTokens creates tokens from options, nil if bt sets no tokens
+func newBooleanTokens(bt dataset.BooleanTokens) *booleanTokens {
+	if bt.IsEmpty() {
+		return nil
+	}
+	b := &booleanTokens{values: map[string]bool{}}
+	for _, tok := range bt.TrueTokens {
+		b.values[strings.ToLower(tok)] = true
+	}
+	for _, tok := range bt.FalseTokens {
+		b.values[strings.ToLower(tok)] = false
+	}
+	if len(bt.TrueTokens) > 0 {
+		b.true = bt.TrueTokens[0]
+	}
+	if len(bt.FalseTokens) > 0 {
+		b.false = bt.FalseTokens[0]
+	}
+	return b
+}
+
+// parse reads a boolean token, ok is false if s isn't a token
+func (b *booleanTokens) parse(s string) (v, ok bool) {
+	if b == nil {
+		v, err := strconv.ParseBool(s)
+		return v, err == nil
+	}
+	v, ok = b.values[strings.ToLower(s)]
+	return v, ok
+}
+
+// format gives the token a boolean is written as
+func (b *booleanTokens) format(v bool) string {
+	switch {
+	case b == nil:
+		return strconv.FormatBool(v)
+	case v:
+		return b.true
+	default:
+		return b.false
+	}
+}
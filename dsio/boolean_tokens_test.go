@@ -0,0 +1,117 @@
+package dsio
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestBooleanTokens(t *testing.T) {
+	var def *booleanTokens
+	if v, ok := def.parse("TRUE"); !v || !ok {
+		t.Errorf("expected default tokens to parse TRUE")
+	}
+	if _, ok := def.parse("Y"); ok {
+		t.Errorf("expected default tokens not to parse Y")
+	}
+	if def.format(false) != "false" {
+		t.Errorf("expected default false token, got: %s", def.format(false))
+	}
+
+	if newBooleanTokens(dataset.BooleanTokens{}) != nil {
+		t.Errorf("expected empty options to use default tokens")
+	}
+	b := newBooleanTokens(dataset.BooleanTokens{TrueTokens: []string{"Y", "1"}, FalseTokens: []string{"N", "0"}})
+	cases := []struct {
+		in     string
+		val    bool
+		parsed bool
+	}{
+		{"Y", true, true},
+		{"y", true, true},
+		{"1", true, true},
+		{"N", false, true},
+		{"0", false, true},
+		{"true", false, false},
+		{"", false, false},
+	}
+	for _, c := range cases {
+		if v, ok := b.parse(c.in); v != c.val || ok != c.parsed {
+			t.Errorf("%q: expected %t %t, got: %t %t", c.in, c.val, c.parsed, v, ok)
+		}
+	}
+	if b.format(true) != "Y" || b.format(false) != "N" {
+		t.Errorf("expected first tokens to be written, got: %s %s", b.format(true), b.format(false))
+	}
+}
+
+func TestBooleanTokensReadWrite(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "name", "type": "string"},
+				map[string]interface{}{"title": "active", "type": "boolean"},
+			},
+		},
+	}
+	tokens := map[string]interface{}{"trueTokens": []interface{}{"yes", "Y"}, "falseTokens": []interface{}{"no", "N"}}
+	expect := [][]interface{}{{"a", true}, {"b", false}, {"c", true}, {"d", "maybe"}}
+
+	for _, format := range []string{"csv", "tsv"} {
+		sep := ","
+		if format == "tsv" {
+			sep = "\t"
+		}
+		body := strings.Join([]string{"a" + sep + "Yes", "b" + sep + "n", "c" + sep + "Y", "d" + sep + "maybe", ""}, "\n")
+		st := &dataset.Structure{Format: format, FormatConfig: tokens, Schema: schema}
+
+		r, err := NewEntryReader(st, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := &bytes.Buffer{}
+		w, err := NewEntryWriter(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, row := range expect {
+			ent, err := r.ReadEntry()
+			if err != nil {
+				t.Fatalf("%s entry %d: %s", format, i, err)
+			}
+			if !reflect.DeepEqual(ent.Value, row) {
+				t.Errorf("%s entry %d mismatch. expected: %v, got: %v", format, i, row, ent.Value)
+			}
+			if err := w.WriteEntry(ent); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		written := strings.Join([]string{"a" + sep + "yes", "b" + sep + "no", "c" + sep + "yes", "d" + sep + "maybe", ""}, "\n")
+		if buf.String() != written {
+			t.Errorf("%s written mismatch. expected: %q, got: %q", format, written, buf.String())
+		}
+	}
+}
+
+func TestDecodeSheetCellsBooleanTokens(t *testing.T) {
+	types := []string{"boolean", "boolean", "boolean"}
+	b := newBooleanTokens(dataset.BooleanTokens{TrueTokens: []string{"Y"}, FalseTokens: []string{"N"}})
+	got := decodeSheetCells(types, []string{"Y", "n", "TRUE"}, b)
+	expect := []interface{}{true, false, "TRUE"}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected: %v, got: %v", expect, got)
+	}
+	got = decodeSheetCells(types, []string{"Y", "false", "TRUE"}, nil)
+	expect = []interface{}{"Y", false, true}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected: %v, got: %v", expect, got)
+	}
+}
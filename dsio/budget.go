@@ -0,0 +1,161 @@
+package dsio
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// MemoryBudget caps the total number of bytes buffered by all readers &
+// writers it's shared with. Components that hold entries in memory acquire
+// an estimated size before buffering & release it once the entries are
+// gone, blocking when the budget is spent. A MemoryBudget is safe for
+// concurrent use.
+//
+// A budget can be shared by the stages of a pipeline, like a PrefetchReader
+// feeding ConvertParallel feeding an AsyncWriter. Each stage releases an
+// entry's bytes once it hands the entry on, and a stage that holds no bytes
+// never waits for the budget: the bytes it would wait on may be held by
+// stages waiting on it. Such a stage overdraws the budget instead, by at most
+// one entry
+type MemoryBudget struct {
+	limit int64
+
+	lk   sync.Mutex
+	used int64
+	// wait is closed & replaced each time bytes are released
+	wait chan struct{}
+}
+
+// NewMemoryBudget creates a budget of limit bytes
+func NewMemoryBudget(limit int64) *MemoryBudget {
+	if limit < 1 {
+		limit = 1
+	}
+	return &MemoryBudget{
+		limit: limit,
+		wait:  make(chan struct{}),
+	}
+}
+
+// Limit gives the total size of the budget in bytes
+func (b *MemoryBudget) Limit() int64 {
+	return b.limit
+}
+
+// Used gives the number of bytes currently acquired
+func (b *MemoryBudget) Used() int64 {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	return b.used
+}
+
+// clamp caps n to the size of the budget so a single value larger than the
+// budget can still be processed, on its own
+func (b *MemoryBudget) clamp(n int64) int64 {
+	if n > b.limit {
+		return b.limit
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Acquire blocks until n bytes are available or ctx is done. requests larger
+// than the budget wait for the entire budget
+func (b *MemoryBudget) Acquire(ctx context.Context, n int64) error {
+	return b.acquireStage(ctx, n, nil)
+}
+
+// acquireStage acquires n bytes for a pipeline stage, adding them to held,
+// the stage's count of acquired bytes. A stage holding nothing doesn't wait.
+// Stages decrement held before releasing bytes, so a waiting stage sees its
+// holds are gone when it's woken
+func (b *MemoryBudget) acquireStage(ctx context.Context, n int64, held *int64) error {
+	n = b.clamp(n)
+	for {
+		b.lk.Lock()
+		if b.used+n <= b.limit || (held != nil && atomic.LoadInt64(held) == 0) {
+			b.used += n
+			if held != nil {
+				atomic.AddInt64(held, n)
+			}
+			b.lk.Unlock()
+			return nil
+		}
+		wait := b.wait
+		b.lk.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// TryAcquire acquires n bytes without blocking, reporting success
+func (b *MemoryBudget) TryAcquire(n int64) bool {
+	n = b.clamp(n)
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	if b.used+n > b.limit {
+		return false
+	}
+	b.used += n
+	return true
+}
+
+// Release returns n bytes to the budget, waking any blocked Acquire calls.
+// Releasing more bytes than were acquired empties the budget
+func (b *MemoryBudget) Release(n int64) {
+	if n <= 0 {
+		return
+	}
+	b.lk.Lock()
+	b.used -= n
+	if b.used < 0 {
+		b.used = 0
+	}
+	close(b.wait)
+	b.wait = make(chan struct{})
+	b.lk.Unlock()
+}
+
+// releaseStage releases n bytes acquired by a stage with acquireStage. n is
+// the clamped size that was acquired
+func (b *MemoryBudget) releaseStage(n int64, held *int64) {
+	atomic.AddInt64(held, -n)
+	b.Release(n)
+}
+
+// EntrySize estimates the number of bytes an entry occupies in memory
+func EntrySize(ent Entry) int64 {
+	return int64(len(ent.Key)) + valueSize(ent.Value) + 16
+}
+
+func valueSize(v interface{}) int64 {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(x)) + 16
+	case []byte:
+		return int64(len(x)) + 24
+	case []interface{}:
+		size := int64(24)
+		for _, v := range x {
+			size += valueSize(v) + 16
+		}
+		return size
+	case map[string]interface{}:
+		size := int64(48)
+		for k, v := range x {
+			size += int64(len(k)) + 16 + valueSize(v) + 16
+		}
+		return size
+	default:
+		return 8
+	}
+}
@@ -0,0 +1,236 @@
+package dsio
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	b := NewMemoryBudget(100)
+	if !b.TryAcquire(60) {
+		t.Fatal("expected acquire within budget to succeed")
+	}
+	if b.TryAcquire(60) {
+		t.Fatal("expected acquire over budget to fail")
+	}
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- b.Acquire(context.Background(), 60)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("expected acquire to block until bytes are released")
+	case <-time.After(20 * time.Millisecond):
+	}
+	b.Release(60)
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+	if b.Used() != 60 {
+		t.Errorf("expected 60 bytes used, got: %d", b.Used())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Acquire(ctx, 50); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline error, got: %v", err)
+	}
+
+	// oversized requests wait for the whole budget
+	b.Release(60)
+	if !b.TryAcquire(1000) || b.Used() != 100 {
+		t.Errorf("expected oversized acquire to take the whole budget. used: %d", b.Used())
+	}
+	b.Release(1000)
+	if b.Used() != 0 {
+		t.Errorf("expected empty budget, got: %d", b.Used())
+	}
+}
+
+func TestConvertParallelBudget(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewEntryReader(st, strings.NewReader(`["a","b","c","d","e","f","g","h"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := NewEntryBuffer(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	budget := NewMemoryBudget(EntrySize(Entry{Value: "a"}) * 2)
+	err = ConvertParallel(context.Background(), r, buf, func(cfg *ParallelConfig) {
+		cfg.Workers = 4
+		cfg.Budget = budget
+		cfg.Transform = func(ent Entry) (Entry, error) {
+			if budget.Used() > budget.Limit() {
+				t.Errorf("budget exceeded: %d", budget.Used())
+			}
+			return ent, nil
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if budget.Used() != 0 {
+		t.Errorf("expected budget to be released, %d bytes still used", budget.Used())
+	}
+	if err := buf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf.Bytes()) != `["a","b","c","d","e","f","g","h"]` {
+		t.Errorf("output mismatch: %s", buf.Bytes())
+	}
+}
+
+func TestMemoryBudgetStages(t *testing.T) {
+	b := NewMemoryBudget(100)
+	var first, second int64
+	if err := b.acquireStage(context.Background(), 100, &first); err != nil {
+		t.Fatal(err)
+	}
+	// a stage holding nothing overdraws instead of waiting on other stages
+	if err := b.acquireStage(context.Background(), 40, &second); err != nil {
+		t.Fatal(err)
+	}
+	if b.Used() != 140 || second != 40 {
+		t.Errorf("expected an overdrawn budget. used: %d, held: %d", b.Used(), second)
+	}
+	// a stage holding bytes waits
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.acquireStage(ctx, 10, &second); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline error, got: %v", err)
+	}
+
+	// & stops waiting once its own holds are released
+	acquired := make(chan error)
+	go func() {
+		acquired <- b.acquireStage(context.Background(), 10, &second)
+	}()
+	b.releaseStage(40, &second)
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+	b.releaseStage(10, &second)
+	b.releaseStage(100, &first)
+	if b.Used() != 0 {
+		t.Errorf("expected empty budget, got: %d", b.Used())
+	}
+}
+
+func TestSharedBudgetPipeline(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	vals := make([]string, 2000)
+	for i := range vals {
+		vals[i] = fmt.Sprintf(`"entry %d"`, i)
+	}
+	body := "[" + strings.Join(vals, ",") + "]"
+
+	budget := NewMemoryBudget(1000)
+	r, err := NewEntryReader(st, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := NewPrefetchReader(r, func(cfg *PrefetchConfig) { cfg.Budget = budget })
+	buf, err := NewEntryBuffer(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aw := NewAsyncWriter(buf, func(cfg *AsyncConfig) {
+		cfg.QueueSize = 4
+		cfg.Budget = budget
+	})
+
+	// every stage shares one budget, converting mustn't deadlock
+	done := make(chan error)
+	go func() {
+		done <- ConvertParallel(context.Background(), pr, aw, func(cfg *ParallelConfig) {
+			cfg.Workers = 16
+			cfg.Budget = budget
+		})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out converting with a shared budget. %d bytes used", budget.Used())
+	}
+	if err := aw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if budget.Used() != 0 {
+		t.Errorf("expected budget to be released, %d bytes still used", budget.Used())
+	}
+	if string(buf.Bytes()) != body {
+		t.Errorf("output mismatch: %.60s...", buf.Bytes())
+	}
+}
+
+func TestSharedBudgetSQLWriter(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": []interface{}{map[string]interface{}{"title": "id", "type": "integer"}},
+			},
+		},
+	}
+	vals := make([]string, 500)
+	for i := range vals {
+		vals[i] = fmt.Sprintf("[%d]", i)
+	}
+	r, err := NewEntryReader(st, strings.NewReader("["+strings.Join(vals, ",")+"]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("dsio_fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	budget := NewMemoryBudget(500)
+	w, err := NewSQLWriter(st, db, "things", func(cfg *SQLWriterConfig) {
+		cfg.BatchSize = 1000
+		cfg.Budget = budget
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		done <- ConvertParallel(context.Background(), r, w, func(cfg *ParallelConfig) {
+			cfg.Workers = 4
+			cfg.Budget = budget
+		})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out converting with a shared budget. %d bytes used", budget.Used())
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if budget.Used() != 0 || w.EntriesWritten() != 500 {
+		t.Errorf("expected 500 entries & a released budget. entries: %d, used: %d", w.EntriesWritten(), budget.Used())
+	}
+}
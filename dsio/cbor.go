@@ -7,18 +7,27 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"time"
 
 	"github.com/qri-io/dataset"
 	"github.com/ugorji/go/codec"
 )
 
-// CBORReader implements the RowReader interface for the CBOR data format
+// CBORReader implements the RowReader interface for the CBOR data format.
+// Bodies can be definite or indefinite-length arrays & maps, as can the
+// values they contain. String references written by the KeyDictionary option
+// are expanded
 type CBORReader struct {
 	rowsRead int
 	rdr      *bufio.Reader
+	src      *TrackedReader
+	offset   int64
 	st       *dataset.Structure
 	topLevel byte
 	length   int
+	// refs is the string reference table of the namespace being read, nil
+	// outside a namespace
+	refs *[]interface{}
 }
 
 var _ EntryReader = (*CBORReader)(nil)
@@ -30,7 +39,7 @@ var (
 // NewCBORReader creates a reader from a structure and read source
 func NewCBORReader(st *dataset.Structure, r io.Reader) (*CBORReader, error) {
 	if st.Schema == nil {
-		err := fmt.Errorf("schema required for CBOR reader")
+		err := newKindError(ErrBadSchema, "schema required for CBOR reader")
 		log.Debug(err.Error())
 		return nil, err
 	}
@@ -47,9 +56,11 @@ func NewCBORReader(st *dataset.Structure, r io.Reader) (*CBORReader, error) {
 		topLevel = cborBaseMap
 	}
 
+	src := NewTrackedReader(r)
 	return &CBORReader{
 		st:       st,
-		rdr:      bufio.NewReader(r),
+		rdr:      bufio.NewReader(src),
+		src:      src,
 		topLevel: topLevel,
 	}, nil
 }
@@ -60,23 +71,56 @@ func (r *CBORReader) Structure() *dataset.Structure {
 }
 
 // ReadEntry reads one CBOR record from the reader
-func (r *CBORReader) ReadEntry() (ent Entry, err error) {
+func (r *CBORReader) ReadEntry() (Entry, error) {
+	ent, err := r.readEntry(false)
+	return ent, parseError("cbor", r.rowsRead, err)
+}
+
+// SkipEntries reads past up to n entries without decoding their values, see
+// EntrySkipper
+func (r *CBORReader) SkipEntries(n int) (int, error) {
+	skipped, err := skipEntries(func() (Entry, error) { return r.readEntry(true) }, n)
+	return skipped, parseError("cbor", r.rowsRead, err)
+}
+
+// readEntry reads the next entry, decoding it unless skip is set
+func (r *CBORReader) readEntry(skip bool) (ent Entry, err error) {
 	if r.rowsRead == 0 {
 		top, length, err := r.readTopLevel()
 		if err != nil {
 			return ent, err
 		}
 		if top != r.topLevel {
-			return ent, fmt.Errorf("Top-level type did not match")
+			return ent, newKindError(ErrFormatMismatch, "Top-level type did not match")
 		}
-		// TODO(dustmop): Length is not used right now, except for handling indefinite length
-		// streams. In the future, it should be used to check that max(r.rowsRead) == r.length
 		r.length = length
 	}
 
 	if r.length == indefiniteLength && r.readIndefiniteSequenceBreak() {
 		return ent, io.EOF
 	}
+	// indefinite containers end at a break, bodies that end before the break
+	// or before all entries of a definite container are read are truncated
+	if r.length == indefiniteLength || r.rowsRead < r.length {
+		defer func() {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+		}()
+	}
+
+	if skip {
+		if r.topLevel == cborBaseMap {
+			if err = r.skipValue(); err != nil {
+				return
+			}
+		}
+		if err = r.skipValue(); err != nil {
+			return
+		}
+		r.rowsRead++
+		return
+	}
 
 	if r.topLevel == cborBaseMap {
 		ent.Key, err = r.readStringKey()
@@ -96,10 +140,37 @@ func (r *CBORReader) ReadEntry() (ent Entry, err error) {
 	return
 }
 
+// EntriesRead gives the number of entries read, including entries before the
+// checkpoint a resumed reader started from
+func (r *CBORReader) EntriesRead() int {
+	return r.rowsRead
+}
+
+// BytesProcessed gives the number of bytes parsed, including bytes before the
+// checkpoint a resumed reader started from
+func (r *CBORReader) BytesProcessed() int64 {
+	return r.offset + int64(r.src.BytesRead()-r.rdr.Buffered())
+}
+
+// Checkpoint returns a token recording the position of the reader, for use with
+// ResumeEntryReader. Bodies written with a key dictionary can't be
+// checkpointed, a resumed reader wouldn't have the keys read before it
+func (r *CBORReader) Checkpoint() (string, error) {
+	if r.refs != nil {
+		return "", fmt.Errorf("checkpoints aren't supported for cbor bodies with a key dictionary")
+	}
+	cp := &Checkpoint{
+		Format:  dataset.CBORDataFormat.String(),
+		Offset:  r.BytesProcessed(),
+		Entries: r.rowsRead,
+		State:   map[string]int{"length": r.length},
+	}
+	return cp.Token()
+}
+
 // Close finalizes the reader
 func (r *CBORReader) Close() error {
-	// TODO (b5): check if underlying reader is an io.ReadCloser, call close here if so
-	return nil
+	return r.src.Close()
 }
 
 const (
@@ -136,12 +207,22 @@ const indefiniteLength int = -1
 
 const cborTypeMask byte = 0xe0
 
-// readTopLevel determines the top-level type, either "object" or "array"
+// readTopLevel determines the top-level type, either "object" or "array". A
+// stringref namespace enclosing the body is read for the rest of the body
 func (r *CBORReader) readTopLevel() (byte, int, error) {
 	b, err := r.rdr.ReadByte()
 	if err != nil {
 		return 0, 0, err
 	}
+	if b&cborTypeMask == cborBaseTag {
+		if tag, err := r.getVarLenInt(b); err != nil || tag != cborTagStringRefNamespace {
+			return 0, 0, fmt.Errorf("invalid top level type")
+		}
+		r.refs = &[]interface{}{}
+		if b, err = r.rdr.ReadByte(); err != nil {
+			return 0, 0, err
+		}
+	}
 
 	t := b & cborTypeMask
 	if t != cborBaseArray && t != cborBaseMap {
@@ -168,6 +249,21 @@ func (r *CBORReader) readStringKey() (string, error) {
 		return "", err
 	}
 
+	if b&cborTypeMask == cborBaseTag && r.refs != nil {
+		if tag, err := r.getVarLenInt(b); err != nil || tag != cborTagStringRef {
+			return "", fmt.Errorf("expected string for key")
+		}
+		ref, err := r.readStringRef()
+		if err != nil {
+			return "", err
+		}
+		key, ok := ref.(string)
+		if !ok {
+			return "", fmt.Errorf("expected string for key")
+		}
+		return key, nil
+	}
+
 	if b&cborTypeMask != cborBaseString {
 		return "", fmt.Errorf("expected string for key")
 	}
@@ -182,7 +278,9 @@ func (r *CBORReader) readStringKey() (string, error) {
 		return "", err
 	}
 
-	return string(buff), nil
+	key := string(buff)
+	r.addStringRef(key, len(buff))
+	return key, nil
 }
 
 // readValue reads a value of any type from the input stream
@@ -265,12 +363,18 @@ func (r *CBORReader) readValue() (interface{}, error) {
 			if err != nil {
 				return nil, err
 			}
-			return string(buff), nil
+			str := string(buff)
+			r.addStringRef(str, len(buff))
+			return str, nil
 		case cborBaseBytes:
 			buff, err := r.readLengthPrefixedBytes(b)
 			if err != nil {
 				return nil, err
 			}
+			if r.refs != nil {
+				// later references are copies of the table's value
+				r.addStringRef(append([]byte(nil), buff...), len(buff))
+			}
 			return buff, nil
 		case cborBaseArray:
 			length, err := r.getVarLenInt(b)
@@ -292,9 +396,40 @@ func (r *CBORReader) readValue() (interface{}, error) {
 				return nil, err
 			}
 			return assoc, nil
+		case cborBaseNegInt:
+			n, err := r.getVarLenInt(b)
+			if err != nil {
+				return nil, err
+			}
+			return -1 - n, nil
 		case cborBaseTag:
-			// TODO: Implement me
-			return nil, nil
+			tag, err := r.getVarLenInt(b)
+			if err != nil {
+				return nil, err
+			}
+			if tag == cborTagStringRef && r.refs != nil {
+				return r.readStringRef()
+			}
+			var val interface{}
+			if tag == cborTagStringRefNamespace {
+				err = r.inNamespace(func() (err error) {
+					val, err = r.readValue()
+					return err
+				})
+			} else {
+				val, err = r.readValue()
+			}
+			if err != nil {
+				return nil, err
+			}
+			// tag 0 is an RFC3339 date/time string, other tags are read as
+			// the value they enclose
+			if s, ok := val.(string); ok && tag == 0 {
+				if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+					return t, nil
+				}
+			}
+			return val, nil
 		case cborBaseSimple:
 			// TODO: Implement me
 			return nil, nil
@@ -304,6 +439,124 @@ func (r *CBORReader) readValue() (interface{}, error) {
 	}
 }
 
+// skipValue reads past a value of any type without decoding it
+func (r *CBORReader) skipValue() error {
+	b, err := r.rdr.ReadByte()
+	if err != nil {
+		return err
+	}
+	t := b & cborTypeMask
+
+	if b&0x1f == 0x1f {
+		// indefinite length strings are a sequence of definite length chunks,
+		// arrays & maps a sequence of values, all ending at a break
+		if t != cborBaseBytes && t != cborBaseString && t != cborBaseArray && t != cborBaseMap {
+			return fmt.Errorf("unknown cbor tag: %v", b)
+		}
+		for !r.readIndefiniteSequenceBreak() {
+			if t == cborBaseBytes || t == cborBaseString {
+				// chunks aren't added to string reference tables
+				cb, err := r.rdr.ReadByte()
+				if err != nil {
+					return err
+				}
+				length, err := r.getVarLenInt(cb)
+				if err != nil {
+					return err
+				}
+				if err := r.discard(length); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := r.skipValue(); err != nil {
+				return err
+			}
+			if t == cborBaseMap {
+				if err := r.skipValue(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	switch t {
+	case cborBaseUint, cborBaseNegInt:
+		_, err = r.getVarLenInt(b)
+		return err
+	case cborBaseBytes, cborBaseString:
+		length, err := r.getVarLenInt(b)
+		if err != nil {
+			return err
+		}
+		if r.refs != nil && length >= int64(cborStringRefMinLength(len(*r.refs))) {
+			// skipped strings can still be referenced
+			buff, err := r.readBytes(int(length))
+			if err != nil {
+				return err
+			}
+			if t == cborBaseString {
+				r.addStringRef(string(buff), len(buff))
+			} else {
+				r.addStringRef(buff, len(buff))
+			}
+			return nil
+		}
+		return r.discard(length)
+	case cborBaseArray, cborBaseMap:
+		length, err := r.getVarLenInt(b)
+		if err != nil {
+			return err
+		}
+		if t == cborBaseMap {
+			length *= 2
+		}
+		for i := int64(0); i < length; i++ {
+			if err := r.skipValue(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case cborBaseTag:
+		tag, err := r.getVarLenInt(b)
+		if err != nil {
+			return err
+		}
+		if tag == cborTagStringRefNamespace {
+			return r.inNamespace(r.skipValue)
+		}
+		return r.skipValue()
+	default:
+		// simple values & floats
+		switch b {
+		case cborBdExt:
+			return r.discard(1)
+		case cborBdFloat16:
+			return r.discard(2)
+		case cborBdFloat32:
+			return r.discard(4)
+		case cborBdFloat64:
+			return r.discard(8)
+		}
+		return nil
+	}
+}
+
+// discard reads past num bytes of the input stream
+func (r *CBORReader) discard(num int64) error {
+	if num < 0 || num > math.MaxInt32 {
+		return fmt.Errorf("invalid cbor length: %d", num)
+	}
+	if n, err := r.rdr.Discard(int(num)); err != nil {
+		if err == io.EOF && n < int(num) {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return nil
+}
+
 // readIndefiniteSequenceBreak returns true if the next byte is a sequence break
 func (r *CBORReader) readIndefiniteSequenceBreak() bool {
 	bytes, err := r.rdr.Peek(1)
@@ -407,61 +660,121 @@ func (r *CBORReader) readIntBytes(num int) (int64, error) {
 	return int64(binary.BigEndian.Uint64(data)), nil
 }
 
-// readFloatBytes returns a float by reading num bytes from the input stream
+// readFloatBytes returns a half, single or double precision float by reading
+// num bytes from the input stream
 func (r *CBORReader) readFloatBytes(num int) (float64, error) {
 	data, err := r.readBytes(num)
 	if err != nil {
 		return 0.0, err
 	}
-	if num < 8 {
-		data = bytes.Join([][]byte{bytes.Repeat([]byte{0}, 8-len(data)), data}, []byte{})
+	switch num {
+	case 2:
+		return cborHalfFloat(binary.BigEndian.Uint16(data)), nil
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), nil
 	}
 	return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
 }
 
-// readBytes reads a number of bytes from the input stream
-func (r *CBORReader) readBytes(num int) ([]byte, error) {
-	buff, err := r.rdr.Peek(num)
-	if err != nil {
-		return nil, err
+// cborHalfFloat converts IEEE 754 half precision bits to a float
+func cborHalfFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
 	}
-	_, err = r.rdr.Discard(num)
-	if err != nil {
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+// readBytes reads a number of bytes from the input stream. the returned
+// slice isn't shared with the reader's buffer
+func (r *CBORReader) readBytes(num int) ([]byte, error) {
+	if num < 0 {
+		return nil, fmt.Errorf("invalid cbor length: %d", num)
+	}
+	// copy rather than allocating num bytes up front, so corrupt lengths fail
+	// at the end of input
+	buf := bytes.NewBuffer(make([]byte, 0, min(num, 64*1024)))
+	if n, err := io.CopyN(buf, r.rdr, int64(num)); err != nil {
+		if err == io.EOF && n < int64(num) {
+			err = io.ErrUnexpectedEOF
+		}
 		return nil, err
 	}
-	return buff, nil
+	return buf.Bytes(), nil
 }
 
 // CBORWriter implements the RowWriter interface for
-// CBOR-formatted data
+// CBOR-formatted data. Entries are buffered & written canonically on close,
+// in a definite-length array or map with sorted keys. Structures with the
+// CBOROptions Indefinite option stream entries as they're written, in an
+// indefinite-length container. The KeyDictionary option writes object keys
+// once per body
 type CBORWriter struct {
-	rowsWritten int
-	tlt         string
-	st          *dataset.Structure
-	wr          io.Writer
-	arr         []interface{}
-	obj         map[string]interface{}
+	rowsWritten    int
+	entriesWritten int
+	tlt            string
+	st             *dataset.Structure
+	wr             *countingWriter
+	indefinite     bool
+	enc            *codec.Encoder
+	started        bool
+	keys           map[string]struct{}
+	arr            []interface{}
+	obj            map[string]interface{}
+	// dict encodes values in place of enc for the KeyDictionary option
+	dict *cborKeyDictionary
+	buf  bytes.Buffer
 }
 
 // NewCBORWriter creates a Writer from a structure and write destination
 func NewCBORWriter(st *dataset.Structure, w io.Writer) (*CBORWriter, error) {
 	if st.Schema == nil {
-		return nil, fmt.Errorf("schema required for CBOR writer")
+		return nil, newKindError(ErrBadSchema, "schema required for CBOR writer")
 	}
 
 	tlt, err := GetTopLevelType(st)
 	if err != nil {
 		return nil, err
 	}
+	opts, err := dataset.NewCBOROptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+
+	h := &codec.CborHandle{TimeRFC3339: true}
+	h.Canonical = true
 	cw := &CBORWriter{
-		st:  st,
-		wr:  w,
-		tlt: tlt,
+		st:         st,
+		wr:         &countingWriter{w: w},
+		tlt:        tlt,
+		indefinite: opts.Indefinite,
+	}
+	cw.enc = codec.NewEncoder(cw.wr, h)
+	if opts.KeyDictionary {
+		cw.dict = newCBORKeyDictionary()
 	}
 
-	if cw.tlt == "object" {
+	switch {
+	case cw.indefinite && cw.tlt == "object":
+		cw.keys = map[string]struct{}{}
+	case cw.tlt == "object":
 		cw.obj = map[string]interface{}{}
-	} else {
+	default:
 		cw.arr = []interface{}{}
 	}
 
@@ -480,31 +793,127 @@ func (w *CBORWriter) WriteEntry(ent Entry) error {
 	}()
 
 	if w.tlt == "object" {
-		if ent.Key == "" {
-			return fmt.Errorf("Key cannot be empty")
+		if err := dataset.ValidObjectKey(ent.Key); err != nil {
+			return err
 		}
+	}
+	if !w.indefinite {
+		return w.bufferEntry(ent)
+	}
 
+	if !w.started {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	if w.tlt == "object" {
+		if _, ok := w.keys[ent.Key]; ok {
+			return fmt.Errorf(`key already written: '%s'`, ent.Key)
+		}
+		w.keys[ent.Key] = struct{}{}
+		if err := w.encode(ent.Key); err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("error writing entry %d: %w", w.rowsWritten, err)
+		}
+	}
+	if err := w.encode(ent.Value); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing entry %d: %w", w.rowsWritten, err)
+	}
+	w.entriesWritten++
+	return nil
+}
+
+// bufferEntry holds an entry until a definite-length container is written
+func (w *CBORWriter) bufferEntry(ent Entry) error {
+	if w.tlt == "object" {
 		if _, ok := w.obj[ent.Key]; ok {
 			return fmt.Errorf(`key already written: '%s'`, ent.Key)
 		}
 		w.obj[ent.Key] = ent.Value
+		w.entriesWritten++
 		return nil
 	}
 
 	w.arr = append(w.arr, ent.Value)
+	w.entriesWritten++
 	return nil
 }
 
-// Close finalizes the writer, indicating no more records
-// will be written
-func (w *CBORWriter) Close() error {
-	h := &codec.CborHandle{TimeRFC3339: true}
-	h.Canonical = true
-	enc := codec.NewEncoder(w.wr, h)
+// encode writes a value, with the key dictionary if the writer has one
+func (w *CBORWriter) encode(v interface{}) error {
+	if w.dict == nil {
+		return w.enc.Encode(v)
+	}
+	w.buf.Reset()
+	if err := w.dict.write(&w.buf, v); err != nil {
+		return err
+	}
+	_, err := w.wr.Write(w.buf.Bytes())
+	return err
+}
+
+// writeNamespace starts the body's string reference namespace for writers
+// with a key dictionary
+func (w *CBORWriter) writeNamespace() error {
+	if w.dict == nil {
+		return nil
+	}
+	w.buf.Reset()
+	w.dict.namespace(&w.buf)
+	_, err := w.wr.Write(w.buf.Bytes())
+	return err
+}
 
+// writeHeader starts an indefinite-length container
+func (w *CBORWriter) writeHeader() error {
+	w.started = true
+	if err := w.writeNamespace(); err != nil {
+		return err
+	}
+	header := []byte{cborBdIndefiniteArray}
 	if w.tlt == "object" {
-		return enc.Encode(w.obj)
+		header[0] = cborBdIndefiniteMap
 	}
+	_, err := w.wr.Write(header)
+	return err
+}
 
-	return enc.Encode(w.arr)
+// EntriesWritten gives the number of entries written
+func (w *CBORWriter) EntriesWritten() int {
+	return w.entriesWritten
+}
+
+// BytesProcessed gives the number of bytes written. Writers without the
+// Indefinite option encode all entries on Close, so this is zero until
+// they're closed
+func (w *CBORWriter) BytesProcessed() int64 {
+	return w.wr.n
+}
+
+// Close finalizes the writer, indicating no more records
+// will be written
+func (w *CBORWriter) Close() error {
+	var err error
+	switch {
+	case !w.indefinite && w.tlt == "object":
+		if err = w.writeNamespace(); err == nil {
+			err = w.encode(w.obj)
+		}
+	case !w.indefinite:
+		if err = w.writeNamespace(); err == nil {
+			err = w.encode(w.arr)
+		}
+	default:
+		if !w.started {
+			err = w.writeHeader()
+		}
+		if err == nil {
+			_, err = w.wr.Write([]byte{cborBdBreak})
+		}
+	}
+	if cerr := w.wr.Close(); err == nil {
+		err = cerr
+	}
+	return err
 }
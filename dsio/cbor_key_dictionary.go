@@ -0,0 +1,258 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// CBOR stringref tags, see http://cbor.schmorp.de/stringref. A namespace tag
+// starts a string reference table for the value it encloses. Every definite
+// length text or byte string in the namespace that's long enough to be worth
+// referencing is added to the table, and a stringref tag enclosing an index
+// stands in for a string already in the table
+const (
+	cborTagStringRef          = 25
+	cborTagStringRefNamespace = 256
+)
+
+// cborStringRefMinLength gives the length a string must be to be added to a
+// string reference table at index, so a reference is never longer than the
+// string it replaces
+func cborStringRefMinLength(index int) int {
+	switch {
+	case index < 24:
+		return 3
+	case index < 256:
+		return 4
+	case index < 65536:
+		return 5
+	case index < 1<<32:
+		return 7
+	}
+	return 11
+}
+
+// cborKeyDictionary encodes values for writers with the KeyDictionary option.
+// Bodies are a single stringref namespace. object keys are written once &
+// referenced after, other strings are only counted to keep table indexes in
+// step with readers, so the dictionary grows with the number of distinct
+// keys, not the size of the body
+type cborKeyDictionary struct {
+	refs map[string]int
+	// size is the number of strings in the reader's table
+	size int
+	// added are the keys remembered by the value being written
+	added []string
+}
+
+func newCBORKeyDictionary() *cborKeyDictionary {
+	return &cborKeyDictionary{refs: map[string]int{}}
+}
+
+// namespace writes the tag starting the body's string reference namespace
+func (d *cborKeyDictionary) namespace(buf *bytes.Buffer) {
+	cborHead(buf, cborBaseTag, cborTagStringRefNamespace)
+}
+
+// write encodes v to buf. values are the native go types entries hold, other
+// types can't be written with a key dictionary. values that fail to encode
+// aren't written, so the dictionary is left as it was
+func (d *cborKeyDictionary) write(buf *bytes.Buffer, v interface{}) error {
+	size := d.size
+	d.added = d.added[:0]
+	if err := d.encode(buf, v); err != nil {
+		for _, key := range d.added {
+			delete(d.refs, key)
+		}
+		d.size = size
+		return err
+	}
+	return nil
+}
+
+func (d *cborKeyDictionary) encode(buf *bytes.Buffer, v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		buf.WriteByte(cborBdNil)
+	case bool:
+		if t {
+			buf.WriteByte(cborBdTrue)
+		} else {
+			buf.WriteByte(cborBdFalse)
+		}
+	case int:
+		cborInt(buf, int64(t))
+	case int8:
+		cborInt(buf, int64(t))
+	case int16:
+		cborInt(buf, int64(t))
+	case int32:
+		cborInt(buf, int64(t))
+	case int64:
+		cborInt(buf, t)
+	case uint:
+		cborHead(buf, cborBaseUint, uint64(t))
+	case uint8:
+		cborHead(buf, cborBaseUint, uint64(t))
+	case uint16:
+		cborHead(buf, cborBaseUint, uint64(t))
+	case uint32:
+		cborHead(buf, cborBaseUint, uint64(t))
+	case uint64:
+		cborHead(buf, cborBaseUint, t)
+	case float32:
+		buf.WriteByte(cborBdFloat32)
+		binary.Write(buf, binary.BigEndian, math.Float32bits(t))
+	case float64:
+		buf.WriteByte(cborBdFloat64)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(t))
+	case string:
+		d.value(buf, cborBaseString, t)
+	case []byte:
+		d.value(buf, cborBaseBytes, string(t))
+	case time.Time:
+		cborHead(buf, cborBaseTag, 0)
+		d.value(buf, cborBaseString, t.Format(time.RFC3339Nano))
+	case []interface{}:
+		cborHead(buf, cborBaseArray, uint64(len(t)))
+		for _, val := range t {
+			if err := d.encode(buf, val); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sortCBORKeys(keys)
+		cborHead(buf, cborBaseMap, uint64(len(keys)))
+		for _, key := range keys {
+			d.key(buf, key)
+			if err := d.encode(buf, t[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("can't write %T with a key dictionary", v)
+	}
+	return nil
+}
+
+// key writes an object key, referencing it if it's been written before
+func (d *cborKeyDictionary) key(buf *bytes.Buffer, key string) {
+	if i, ok := d.refs[key]; ok {
+		cborHead(buf, cborBaseTag, cborTagStringRef)
+		cborHead(buf, cborBaseUint, uint64(i))
+		return
+	}
+	if d.add(key) {
+		d.refs[key] = d.size - 1
+		d.added = append(d.added, key)
+	}
+	d.string(buf, cborBaseString, key)
+}
+
+// value writes a string value, counting it if it enters the reader's table
+func (d *cborKeyDictionary) value(buf *bytes.Buffer, major byte, s string) {
+	d.add(s)
+	d.string(buf, major, s)
+}
+
+// add counts a string in the reader's table if it's long enough to be added,
+// reporting whether it was
+func (d *cborKeyDictionary) add(s string) bool {
+	if len(s) < cborStringRefMinLength(d.size) {
+		return false
+	}
+	d.size++
+	return true
+}
+
+func (d *cborKeyDictionary) string(buf *bytes.Buffer, major byte, s string) {
+	cborHead(buf, major, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+// sortCBORKeys sorts object keys in canonical order, shorter keys first
+func sortCBORKeys(keys []string) {
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+func cborInt(buf *bytes.Buffer, n int64) {
+	if n < 0 {
+		cborHead(buf, cborBaseNegInt, uint64(-1-n))
+		return
+	}
+	cborHead(buf, cborBaseUint, uint64(n))
+}
+
+// cborHead writes the initial bytes of a data item, a major type & argument
+func cborHead(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(major | 0x18)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(major | 0x19)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	case n <= math.MaxUint32:
+		buf.WriteByte(major | 0x1a)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	default:
+		buf.WriteByte(major | 0x1b)
+		binary.Write(buf, binary.BigEndian, n)
+	}
+}
+
+// addStringRef adds a string read in a namespace to the reader's table if it's
+// long enough. val is the string or []byte value read
+func (r *CBORReader) addStringRef(val interface{}, length int) {
+	if r.refs != nil && length >= cborStringRefMinLength(len(*r.refs)) {
+		*r.refs = append(*r.refs, val)
+	}
+}
+
+// readStringRef reads the index enclosed by a stringref tag, giving the
+// string it references. []byte values are copied so entries don't share them
+func (r *CBORReader) readStringRef() (interface{}, error) {
+	b, err := r.rdr.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if b&cborTypeMask != cborBaseUint {
+		return nil, fmt.Errorf("invalid cbor string reference")
+	}
+	i, err := r.getVarLenInt(b)
+	if err != nil {
+		return nil, err
+	}
+	if i < 0 || i >= int64(len(*r.refs)) {
+		return nil, fmt.Errorf("cbor string reference out of range: %d", i)
+	}
+	if bs, ok := (*r.refs)[i].([]byte); ok {
+		return append([]byte(nil), bs...), nil
+	}
+	return (*r.refs)[i], nil
+}
+
+// inNamespace calls read with a new string reference table, restoring the
+// enclosing table after
+func (r *CBORReader) inNamespace(read func() error) error {
+	enclosing := r.refs
+	r.refs = &[]interface{}{}
+	err := read()
+	r.refs = enclosing
+	return err
+}
@@ -0,0 +1,261 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+// sparseEntries gives n wide, sparse entries: each sets a few of 500 possible
+// keys, so key indexes cross the stringref length thresholds
+func sparseEntries(n int) []interface{} {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	vals := make([]interface{}, n)
+	for i := range vals {
+		vals[i] = map[string]interface{}{
+			fmt.Sprintf("metric_%d", i%500):       float64(i) / 2,
+			fmt.Sprintf("metric_%d", (i*7+3)%500): int64(-i),
+			"host":                                fmt.Sprintf("host-%d", i%3),
+			"time":                                ts,
+			"tags":                                []interface{}{"metric_1", []byte("raw"), nil, true},
+			"nested": map[string]interface{}{
+				"host": "metric_2",
+			},
+		}
+	}
+	return vals
+}
+
+func TestCBORKeyDictionary(t *testing.T) {
+	vals := sparseEntries(2000)
+
+	cases := []struct {
+		description string
+		schema      map[string]interface{}
+		config      map[string]interface{}
+	}{
+		{"definite array", dataset.BaseSchemaArray, map[string]interface{}{"keyDictionary": true}},
+		{"streaming array", dataset.BaseSchemaArray, map[string]interface{}{"keyDictionary": true, "indefinite": true}},
+		{"definite object", dataset.BaseSchemaObject, map[string]interface{}{"keyDictionary": true}},
+		{"streaming object", dataset.BaseSchemaObject, map[string]interface{}{"keyDictionary": true, "indefinite": true}},
+	}
+
+	write := func(st *dataset.Structure) []byte {
+		buf := &bytes.Buffer{}
+		w, err := NewCBORWriter(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range vals {
+			if err := w.WriteEntry(Entry{Index: i, Key: fmt.Sprintf("entry_%04d", i), Value: v}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			st := &dataset.Structure{Format: "cbor", Schema: c.schema, FormatConfig: c.config}
+			data := write(st)
+			plain := write(&dataset.Structure{Format: "cbor", Schema: c.schema})
+			if len(data) >= len(plain) {
+				t.Errorf("expected a key dictionary to shrink the body. plain: %d bytes, with dictionary: %d bytes", len(plain), len(data))
+			}
+
+			r, err := NewCBORReader(st, bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]interface{}{}
+			for {
+				ent, err := r.ReadEntry()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatal(err)
+				}
+				key := ent.Key
+				if key == "" {
+					key = fmt.Sprintf("entry_%04d", ent.Index)
+				}
+				got[key] = ent.Value
+			}
+			if len(got) != len(vals) {
+				t.Fatalf("expected %d entries, got: %d", len(vals), len(got))
+			}
+			for i, v := range vals {
+				key := fmt.Sprintf("entry_%04d", i)
+				if !reflect.DeepEqual(v, got[key]) {
+					t.Fatalf("%s mismatch. expected: %v, got: %v", key, v, got[key])
+				}
+			}
+		})
+	}
+}
+
+func TestCBORKeyDictionaryEncoding(t *testing.T) {
+	// streamed, so entries are encoded as they're written
+	st := &dataset.Structure{Format: "cbor", Schema: dataset.BaseSchemaArray, FormatConfig: map[string]interface{}{"keyDictionary": true, "indefinite": true}}
+	buf := &bytes.Buffer{}
+	w, err := NewCBORWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range []string{"a", "b"} {
+		if err := w.WriteEntry(Entry{Index: i, Value: map[string]interface{}{"name": v}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 256([{"name": "a"}, {25(0): "b"}]), "a" & "b" are too short to reference
+	expect := "d901009fa1646e616d656161a1d819006162ff"
+	if got := hex.EncodeToString(buf.Bytes()); got != expect {
+		t.Errorf("encoding mismatch. expected: %s, got: %s", expect, got)
+	}
+
+	buf.Reset()
+	w, err = NewCBORWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Value: map[string]interface{}{"name": struct{}{}}}); err == nil {
+		t.Error("expected an error writing a type a key dictionary can't encode")
+	}
+	// keys of entries that fail to write aren't referenced
+	if err := w.WriteEntry(Entry{Value: map[string]interface{}{"name": "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewCBORReader(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent, err := r.ReadEntry(); err != nil || !reflect.DeepEqual(ent.Value, map[string]interface{}{"name": "a"}) {
+		t.Errorf("expected entry after a failed write to read back. got: %v, %v", ent.Value, err)
+	}
+}
+
+func TestCBORKeyDictionarySkipEntries(t *testing.T) {
+	st := &dataset.Structure{Format: "cbor", Schema: dataset.BaseSchemaArray, FormatConfig: map[string]interface{}{"keyDictionary": true}}
+	vals := sparseEntries(300)
+	buf := &bytes.Buffer{}
+	w, err := NewCBORWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range vals {
+		if err := w.WriteEntry(Entry{Index: i, Value: v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewCBORReader(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// keys first written in skipped entries are referenced by later ones
+	if n, err := r.SkipEntries(150); err != nil || n != 150 {
+		t.Fatalf("expected to skip 150 entries, got: %d, %v", n, err)
+	}
+	for i := 150; i < len(vals); i++ {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("entry %d: %s", i, err)
+		}
+		if !reflect.DeepEqual(vals[i], ent.Value) {
+			t.Fatalf("entry %d mismatch. expected: %v, got: %v", i, vals[i], ent.Value)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected EOF, got: %v", err)
+	}
+
+	if _, err := r.Checkpoint(); err == nil {
+		t.Error("expected an error checkpointing a body with a key dictionary")
+	}
+}
+
+func TestCBORReaderStringRefs(t *testing.T) {
+	cases := []struct {
+		description string
+		data        string
+		expect      interface{}
+		err         string
+	}{
+		// [256(["aaa", 25(0), 256(["bbb", 25(0)]), 25(0)])]
+		{"nested namespace", "81d901008463616161d81900d901008263626262d81900d81900", []interface{}{"aaa", "aaa", []interface{}{"bbb", "bbb"}, "aaa"}, ""},
+		// [25(0)], outside a namespace the enclosed index is read
+		{"no namespace", "81d81900", int64(0), ""},
+		// 256([25(1)])
+		{"out of range", "d9010081d81901", nil, "cbor string reference out of range: 1"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			data, err := hex.DecodeString(c.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := NewCBORReader(&dataset.Structure{Format: "cbor", Schema: dataset.BaseSchemaArray}, bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ent, err := r.ReadEntry()
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Errorf("error mismatch. expected: %s, got: %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(c.expect, ent.Value) {
+				t.Errorf("value mismatch. expected: %v, got: %v", c.expect, ent.Value)
+			}
+		})
+	}
+}
+
+func BenchmarkCBORWriterKeyDictionary(b *testing.B) {
+	vals := sparseEntries(1000)
+	for _, dict := range []bool{false, true} {
+		st := &dataset.Structure{Format: "cbor", Schema: dataset.BaseSchemaArray, FormatConfig: map[string]interface{}{"keyDictionary": dict}}
+		b.Run(fmt.Sprintf("keyDictionary_%t", dict), func(b *testing.B) {
+			buf := &bytes.Buffer{}
+			for n := 0; n < b.N; n++ {
+				buf.Reset()
+				w, err := NewCBORWriter(st, buf)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for i, v := range vals {
+					if err := w.WriteEntry(Entry{Index: i, Value: v}); err != nil {
+						b.Fatal(err)
+					}
+				}
+				w.Close()
+			}
+			b.ReportMetric(float64(buf.Len()), "body-bytes")
+		})
+	}
+}
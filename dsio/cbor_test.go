@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dstest"
@@ -33,9 +36,8 @@ var (
 	}}
 )
 
-// TODO(dustmop): Tag support.
+// TODO(dustmop): Tag support beyond date/time strings.
 // TODO(dustmop): Test illegal chunks.
-// TODO(dustmop): Move indefinite streams to their own test, test that 0xff correctly returns EOF.
 
 func TestCBORReaderOneArrayEntry(t *testing.T) {
 	arrCases := []struct {
@@ -53,14 +55,20 @@ func TestCBORReaderOneArrayEntry(t *testing.T) {
 		{`811901F4`, int64(500), ""},         // [500]
 		{`811A004C4B40`, int64(5000000), ""}, // [5000000]
 		{`8020`, int64(-1), ""},              // [-1]
-
-		{`81FB4028AE147AE147AE`, 12.34, ""},    // [12.34]
-		{`81FB402A1D1F601797CC`, 13.05688, ""}, // [13.05688]
-		{`8163666F6F`, "foo", ""},              // ["foo"]
-		{`81F5`, true, ""},                     // [true]
-		{`81F4`, false, ""},                    // [false]
-		{`81F6`, nil, ""},                      // [null]
-		{`81A0`, map[string]interface{}{}, ""}, // [{}]
+		{`8138FF`, int64(-256), ""},          // [-256]
+		{`813903E7`, int64(-1000), ""},       // [-1000]
+
+		{`81FB4028AE147AE147AE`, 12.34, ""},     // [12.34]
+		{`81FB402A1D1F601797CC`, 13.05688, ""},  // [13.05688]
+		{`81F93E00`, 1.5, ""},                   // [1.5] half precision
+		{`81F90001`, 5.960464477539063e-08, ""}, // [5.960464477539063e-08] half precision subnormal
+		{`81F9FC00`, math.Inf(-1), ""},          // [-Infinity] half precision
+		{`81FA3FC00000`, 1.5, ""},               // [1.5] single precision
+		{`8163666F6F`, "foo", ""},               // ["foo"]
+		{`81F5`, true, ""},                      // [true]
+		{`81F4`, false, ""},                     // [false]
+		{`81F6`, nil, ""},                       // [null]
+		{`81A0`, map[string]interface{}{}, ""},  // [{}]
 
 		// array - [[1,2,3]]
 		{`8183010203`, []interface{}{int64(1), int64(2), int64(3)}, ""},
@@ -84,6 +92,13 @@ func TestCBORReaderOneArrayEntry(t *testing.T) {
 
 		{`81782A286F72672C64617461746F6765746865722C292F616374697669746965732F68617276657374696E673E`, "(org,datatogether,)/activities/harvesting>", ""}, // ["(org,datatogether,)/activities/harvesting>"]
 
+		// date/time string tag - [0("2013-03-21T20:04:00Z")]
+		{`81C074323031332D30332D32315432303A30343A30305A`, time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC), ""},
+		// other tags read as their value - [1(1363896240)]
+		{`81C11A514B67B0`, int64(1363896240), ""},
+		// tagged values inside containers - [[1(1), 2]]
+		{`8182C10102`, []interface{}{int64(1), int64(2)}, ""},
+
 		// Top-level array of indetermine size
 		{`9f16ff`, int64(22), ""}, // [22]
 	}
@@ -112,6 +127,81 @@ func TestCBORReaderOneArrayEntry(t *testing.T) {
 	}
 }
 
+func TestCBORReaderIndefinite(t *testing.T) {
+	cases := []struct {
+		schema map[string]interface{}
+		data   string
+		expect []Entry
+		err    string
+	}{
+		{dataset.BaseSchemaArray, `9fff`, nil, ""},
+		{dataset.BaseSchemaObject, `bfff`, nil, ""},
+		{dataset.BaseSchemaArray, `9f0102ff`, []Entry{{Index: 0, Value: int64(1)}, {Index: 1, Value: int64(2)}}, ""},
+		{dataset.BaseSchemaObject, `bf616101616202ff`, []Entry{{Key: "a", Value: int64(1)}, {Key: "b", Value: int64(2)}}, ""},
+		// nested containers of both kinds - [[1], {"a": [2]}]
+		{dataset.BaseSchemaArray, `9f9f01ffbf61618102ffff`, []Entry{
+			{Index: 0, Value: []interface{}{int64(1)}},
+			{Index: 1, Value: map[string]interface{}{"a": []interface{}{int64(2)}}},
+		}, ""},
+		// truncated before the break
+		{dataset.BaseSchemaArray, `9f0102`, []Entry{{Index: 0, Value: int64(1)}, {Index: 1, Value: int64(2)}}, "unexpected EOF"},
+		// truncated before all definite entries are read
+		{dataset.BaseSchemaArray, `830102`, []Entry{{Index: 0, Value: int64(1)}, {Index: 1, Value: int64(2)}}, "unexpected EOF"},
+	}
+
+	for i, c := range cases {
+		d, err := hex.DecodeString(c.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rdr, err := NewCBORReader(&dataset.Structure{Format: "cbor", Schema: c.schema}, bytes.NewReader(d))
+		if err != nil {
+			t.Errorf("case %d error creating reader: %s", i, err)
+			continue
+		}
+		var got []Entry
+		for {
+			ent, err := rdr.ReadEntry()
+			if err != nil {
+				if !(err == io.EOF && c.err == "" || err.Error() == c.err) {
+					t.Errorf("case %d error mismatch. expected: %q got: %q", i, c.err, err)
+				}
+				break
+			}
+			got = append(got, ent)
+		}
+		if !reflect.DeepEqual(c.expect, got) {
+			t.Errorf("case %d entries mismatch. expected: %v got: %v", i, c.expect, got)
+		}
+	}
+}
+
+func TestCBORReaderLongString(t *testing.T) {
+	// strings longer than the reader's buffer
+	str := bytes.Repeat([]byte("x"), 10000)
+	d := append([]byte{0x81, 0x79, 0x27, 0x10}, str...)
+	rdr, err := NewCBORReader(&dataset.Structure{Format: "cbor", Schema: dataset.BaseSchemaArray}, bytes.NewReader(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := rdr.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent.Value != string(str) {
+		t.Errorf("long string mismatch, got %d bytes", len(ent.Value.(string)))
+	}
+
+	// a length longer than the input fails without allocating it
+	rdr, err = NewCBORReader(&dataset.Structure{Format: "cbor", Schema: dataset.BaseSchemaArray}, bytes.NewReader([]byte{0x81, 0x5b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rdr.ReadEntry(); err == nil {
+		t.Error("expected error reading truncated bytes")
+	}
+}
+
 func TestCBORReaderOneObjectEntry(t *testing.T) {
 
 	objCases := []struct {
@@ -268,6 +358,9 @@ func TestCBORReaderFile(t *testing.T) {
 func TestCBORWriter(t *testing.T) {
 	objst := &dataset.Structure{Schema: dataset.BaseSchemaObject}
 	arrst := &dataset.Structure{Schema: dataset.BaseSchemaArray}
+	indefinite := map[string]interface{}{"indefinite": true}
+	indobjst := &dataset.Structure{Schema: dataset.BaseSchemaObject, FormatConfig: indefinite}
+	indarrst := &dataset.Structure{Schema: dataset.BaseSchemaArray, FormatConfig: indefinite}
 
 	cases := []struct {
 		structure *dataset.Structure
@@ -277,12 +370,18 @@ func TestCBORWriter(t *testing.T) {
 	}{
 		{&dataset.Structure{}, []Entry{}, "[]", "schema required for CBOR writer"},
 		{&dataset.Structure{Schema: map[string]interface{}{"type": "boolean"}}, []Entry{}, "[]", "invalid schema. root must be either an array or object type"},
+		{&dataset.Structure{Schema: dataset.BaseSchemaArray, FormatConfig: map[string]interface{}{"indefinite": 1}}, []Entry{}, "[]", "invalid indefinite value: 1"},
 
 		{arrst, []Entry{}, "80", ""},
 		{objst, []Entry{}, "a0", ""},
 
 		{objst, []Entry{{Key: "a", Value: "hello"}, {Key: "b", Value: "world"}}, `a261616568656c6c6f616265776f726c64`, ""},
 		{arrst, []Entry{{Value: "hello"}, {Value: "world"}}, `826568656c6c6f65776f726c64`, ""},
+
+		{indarrst, []Entry{}, "9fff", ""},
+		{indobjst, []Entry{}, "bfff", ""},
+		{indobjst, []Entry{{Key: "b", Value: "world"}, {Key: "a", Value: "hello"}}, `bf616265776f726c6461616568656c6c6fff`, ""},
+		{indarrst, []Entry{{Value: "hello"}, {Value: "world"}}, `9f6568656c6c6f65776f726c64ff`, ""},
 	}
 
 	for i, c := range cases {
@@ -313,6 +412,44 @@ func TestCBORWriter(t *testing.T) {
 	}
 }
 
+func TestCBORWriterStreaming(t *testing.T) {
+	st := &dataset.Structure{Format: "cbor", Schema: dataset.BaseSchemaArray, FormatConfig: map[string]interface{}{"indefinite": true}}
+	buf := &bytes.Buffer{}
+	w, err := NewCBORWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vals := []interface{}{"a", int64(1), []interface{}{true}}
+	for i, v := range vals {
+		if err := w.WriteEntry(Entry{Index: i, Value: v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if buf.Len() == 0 {
+		t.Errorf("expected entries to be written before close")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewCBORReader(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range vals {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("entry %d: %s", i, err)
+		}
+		if !reflect.DeepEqual(v, ent.Value) {
+			t.Errorf("entry %d mismatch. expected: %v got: %v", i, v, ent.Value)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected EOF after the break, got: %v", err)
+	}
+}
+
 func TestCBORWriterNonObjectEntry(t *testing.T) {
 	buf := &bytes.Buffer{}
 	w, err := NewCBORWriter(&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject}, buf)
@@ -322,7 +459,7 @@ func TestCBORWriterNonObjectEntry(t *testing.T) {
 	}
 
 	err = w.WriteEntry(Entry{Value: false})
-	expect := `Key cannot be empty`
+	expect := `entry key cannot be empty`
 	if err.Error() != expect {
 		t.Errorf("error mismatch. expected: %s. got: %s", expect, err.Error())
 		return
@@ -0,0 +1,225 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// compound file layout & special sector numbers
+const (
+	cfbMaxRegSect  = 0xFFFFFFFA
+	cfbEndOfChain  = 0xFFFFFFFE
+	cfbHeaderSize  = 512
+	cfbDirSize     = 128
+	cfbStreamEntry = 2
+)
+
+// cfbSignature opens every compound file
+var cfbSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// cfbFile is a read-only compound file binary (OLE2 structured storage)
+// container, the file system-in-a-file legacy microsoft office documents
+// are stored in. The whole file is held in memory
+type cfbFile struct {
+	data           []byte
+	sectorSize     int
+	miniSectorSize int
+	miniCutoff     uint64
+	fat            []uint32
+	miniFAT        []uint32
+	miniStream     []byte
+	entries        []cfbEntry
+}
+
+// cfbEntry is a compound file directory entry
+type cfbEntry struct {
+	name  string
+	typ   byte
+	start uint32
+	size  uint64
+}
+
+// openCFB parses the header, allocation tables & directory of a compound file
+func openCFB(data []byte) (*cfbFile, error) {
+	if len(data) < cfbHeaderSize || !bytes.Equal(data[:8], cfbSignature) {
+		return nil, fmt.Errorf("missing compound file signature")
+	}
+	le := binary.LittleEndian
+	f := &cfbFile{
+		data:           data,
+		sectorSize:     1 << le.Uint16(data[0x1E:]),
+		miniSectorSize: 1 << le.Uint16(data[0x20:]),
+		miniCutoff:     uint64(le.Uint32(data[0x38:])),
+	}
+	if f.sectorSize != 512 && f.sectorSize != 4096 {
+		return nil, fmt.Errorf("invalid compound file sector size: %d", f.sectorSize)
+	}
+	if f.miniSectorSize != 64 {
+		return nil, fmt.Errorf("invalid compound file mini sector size: %d", f.miniSectorSize)
+	}
+
+	// the double-indirect FAT lists FAT sectors, the first 109 in the header
+	// & the rest in a chain of DIFAT sectors
+	difat := make([]uint32, 0, 109)
+	for i := 0; i < 109; i++ {
+		difat = append(difat, le.Uint32(data[0x4C+i*4:]))
+	}
+	next := le.Uint32(data[0x44:])
+	for n := 0; next <= cfbMaxRegSect; n++ {
+		if n > len(data)/f.sectorSize {
+			return nil, fmt.Errorf("compound file DIFAT chain is cyclic")
+		}
+		sect, err := f.sector(next)
+		if err != nil {
+			return nil, err
+		}
+		words := f.sectorSize/4 - 1
+		for i := 0; i < words; i++ {
+			difat = append(difat, le.Uint32(sect[i*4:]))
+		}
+		next = le.Uint32(sect[words*4:])
+	}
+
+	for _, sn := range difat[:min(len(difat), int(le.Uint32(data[0x2C:])))] {
+		sect, err := f.sector(sn)
+		if err != nil {
+			return nil, err
+		}
+		f.fat = append(f.fat, cfbWords(sect)...)
+	}
+
+	dir, err := f.readChain(le.Uint32(data[0x30:]), -1, false)
+	if err != nil {
+		return nil, fmt.Errorf("reading compound file directory: %w", err)
+	}
+	for i := 0; i+cfbDirSize <= len(dir); i += cfbDirSize {
+		f.entries = append(f.entries, f.parseEntry(dir[i:i+cfbDirSize]))
+	}
+	if len(f.entries) == 0 {
+		return nil, fmt.Errorf("compound file has no root entry")
+	}
+
+	if start := le.Uint32(data[0x3C:]); start <= cfbMaxRegSect {
+		mf, err := f.readChain(start, -1, false)
+		if err != nil {
+			return nil, fmt.Errorf("reading compound file mini FAT: %w", err)
+		}
+		f.miniFAT = cfbWords(mf)
+	}
+	root := f.entries[0]
+	if root.start <= cfbMaxRegSect {
+		if f.miniStream, err = f.readChain(root.start, int64(root.size), false); err != nil {
+			return nil, fmt.Errorf("reading compound file mini stream: %w", err)
+		}
+	}
+	return f, nil
+}
+
+// parseEntry reads a directory entry
+func (f *cfbFile) parseEntry(b []byte) cfbEntry {
+	le := binary.LittleEndian
+	e := cfbEntry{typ: b[0x42], start: le.Uint32(b[0x74:]), size: le.Uint64(b[0x78:])}
+	if f.sectorSize == 512 {
+		// version 3 files may leave garbage in the high size bits
+		e.size &= 0xFFFFFFFF
+	}
+	nameLen := int(le.Uint16(b[0x40:]))/2 - 1
+	if nameLen > 0 && nameLen <= 32 {
+		name := make([]uint16, nameLen)
+		for i := range name {
+			name[i] = le.Uint16(b[i*2:])
+		}
+		e.name = string(utf16.Decode(name))
+	}
+	return e
+}
+
+// sector gives the bytes of a regular sector
+func (f *cfbFile) sector(n uint32) ([]byte, error) {
+	off := (int64(n) + 1) * int64(f.sectorSize)
+	if n > cfbMaxRegSect || off >= int64(len(f.data)) {
+		return nil, fmt.Errorf("compound file sector out of range: %d", n)
+	}
+	end := off + int64(f.sectorSize)
+	if end > int64(len(f.data)) {
+		end = int64(len(f.data))
+	}
+	return f.data[off:end], nil
+}
+
+// readChain concatenates the sectors of a FAT chain, truncating to size if
+// size isn't negative. mini chains are read from the mini FAT & mini stream
+func (f *cfbFile) readChain(start uint32, size int64, mini bool) ([]byte, error) {
+	table, ss := f.fat, f.sectorSize
+	if mini {
+		table, ss = f.miniFAT, f.miniSectorSize
+	}
+
+	buf := &bytes.Buffer{}
+	for n, sn := 0, start; sn != cfbEndOfChain; n++ {
+		if size >= 0 && int64(buf.Len()) >= size {
+			break
+		}
+		if int(sn) >= len(table) || n > len(table) {
+			return nil, fmt.Errorf("invalid sector chain")
+		}
+		if mini {
+			off := int(sn) * ss
+			if off+ss > len(f.miniStream) {
+				return nil, fmt.Errorf("compound file mini sector out of range: %d", sn)
+			}
+			buf.Write(f.miniStream[off : off+ss])
+		} else {
+			sect, err := f.sector(sn)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(sect)
+		}
+		sn = table[sn]
+	}
+
+	data := buf.Bytes()
+	if size >= 0 {
+		if int64(len(data)) < size {
+			return nil, fmt.Errorf("stream is truncated")
+		}
+		data = data[:size]
+	}
+	return data, nil
+}
+
+// stream reads the stream entry with the given name, ignoring case like
+// compound files do
+func (f *cfbFile) stream(name string) ([]byte, bool, error) {
+	for _, e := range f.entries {
+		if e.typ != cfbStreamEntry || !strings.EqualFold(e.name, name) {
+			continue
+		}
+		if e.size == 0 {
+			return []byte{}, true, nil
+		}
+		if e.size < f.miniCutoff {
+			if len(f.miniFAT) == 0 {
+				return nil, true, fmt.Errorf("compound file has no mini FAT")
+			}
+			data, err := f.readChain(e.start, int64(e.size), true)
+			return data, true, err
+		}
+		data, err := f.readChain(e.start, int64(e.size), false)
+		return data, true, err
+	}
+	return nil, false, nil
+}
+
+// cfbWords reads a sector as little-endian sector numbers
+func cfbWords(b []byte) []uint32 {
+	words := make([]uint32, len(b)/4)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+	return words
+}
@@ -0,0 +1,145 @@
+package dsio
+
+import (
+	"io"
+
+	"github.com/qri-io/dataset"
+)
+
+// ChanReader is an EntryReader that reads entries from a channel
+type ChanReader struct {
+	st   *dataset.Structure
+	ch   <-chan Entry
+	read int
+}
+
+var _ EntryReader = (*ChanReader)(nil)
+
+// ReaderFromChan creates an EntryReader that reads entries sent on ch, the
+// reader returns io.EOF once ch is closed
+func ReaderFromChan(st *dataset.Structure, ch <-chan Entry) *ChanReader {
+	return &ChanReader{st: st, ch: ch}
+}
+
+// Structure gives the structure being read
+func (r *ChanReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry reads the next entry sent on the channel
+func (r *ChanReader) ReadEntry() (Entry, error) {
+	ent, ok := <-r.ch
+	if !ok {
+		return Entry{}, io.EOF
+	}
+	r.read++
+	return ent, nil
+}
+
+// EntriesRead gives the number of entries read
+func (r *ChanReader) EntriesRead() int {
+	return r.read
+}
+
+// Close finalizes the reader. The channel is owned by the sender & isn't
+// closed
+func (r *ChanReader) Close() error {
+	return nil
+}
+
+// ChanFromReader reads all entries from r in a new goroutine, sending them on
+// the returned entries channel, which is closed when reading stops. Reading
+// errors other than io.EOF are sent on the error channel, which is closed
+// after the entries channel. Callers must drain entries until it closes
+func ChanFromReader(r EntryReader) (<-chan Entry, <-chan error) {
+	entries := make(chan Entry)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(entries)
+		for {
+			ent, err := r.ReadEntry()
+			if err == io.EOF {
+				return
+			} else if err != nil {
+				log.Debug(err.Error())
+				errs <- err
+				return
+			}
+			entries <- ent
+		}
+	}()
+
+	return entries, errs
+}
+
+// ChanWriter is an EntryWriter that sends entries on a channel
+type ChanWriter struct {
+	st      *dataset.Structure
+	ch      chan<- Entry
+	written int
+}
+
+var _ EntryWriter = (*ChanWriter)(nil)
+
+// WriterFromChan creates an EntryWriter that sends written entries on ch.
+// Closing the writer closes ch
+func WriterFromChan(st *dataset.Structure, ch chan<- Entry) *ChanWriter {
+	return &ChanWriter{st: st, ch: ch}
+}
+
+// Structure gives the structure being written
+func (w *ChanWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// WriteEntry sends an entry on the channel, blocking until it's received
+func (w *ChanWriter) WriteEntry(ent Entry) error {
+	w.ch <- ent
+	w.written++
+	return nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *ChanWriter) EntriesWritten() int {
+	return w.written
+}
+
+// Close closes the channel, indicating all entries have been written
+func (w *ChanWriter) Close() error {
+	close(w.ch)
+	return nil
+}
+
+// ChanFromWriter writes entries sent on the returned entries channel to w in
+// a new goroutine. Closing the entries channel closes w, after which the
+// first write or close error (if any) is sent on the error channel & it's
+// closed. Entries sent after a write error are dropped, so senders don't block
+func ChanFromWriter(w EntryWriter) (chan<- Entry, <-chan error) {
+	entries := make(chan Entry)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		var werr error
+		for ent := range entries {
+			if werr != nil {
+				continue
+			}
+			if err := w.WriteEntry(ent); err != nil {
+				log.Debug(err.Error())
+				werr = err
+			}
+		}
+		if err := w.Close(); err != nil && werr == nil {
+			log.Debug(err.Error())
+			werr = err
+		}
+		if werr != nil {
+			errs <- werr
+		}
+	}()
+
+	return entries, errs
+}
@@ -0,0 +1,105 @@
+package dsio
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestReaderFromChan(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	ch := make(chan Entry)
+	go func() {
+		for i := 0; i < 3; i++ {
+			ch <- Entry{Index: i, Value: i}
+		}
+		close(ch)
+	}()
+
+	r := ReaderFromChan(st, ch)
+	count := 0
+	if err := EachEntry(r, func(i int, ent Entry, err error) error {
+		count++
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 || r.EntriesRead() != 3 {
+		t.Errorf("expected 3 entries, got: %d", count)
+	}
+}
+
+func TestChanFromReader(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewJSONReader(st, bytes.NewBufferString(`[1,2,3`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, errs := ChanFromReader(r)
+	count := 0
+	for range entries {
+		count++
+	}
+	if count != 3 {
+		t.Errorf("expected 3 entries, got: %d", count)
+	}
+	if err := <-errs; err == nil {
+		t.Error("expected read error for unterminated array")
+	}
+}
+
+func TestWriterFromChan(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	ch := make(chan Entry, 2)
+	w := WriterFromChan(st, ch)
+	w.WriteEntry(Entry{Value: 1})
+	w.WriteEntry(Entry{Value: 2})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := []interface{}{}
+	for ent := range ch {
+		got = append(got, ent.Value)
+	}
+	if len(got) != 2 || w.EntriesWritten() != 2 {
+		t.Errorf("expected 2 entries, got: %v", got)
+	}
+}
+
+func TestChanFromWriter(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	buf := &bytes.Buffer{}
+	w, err := NewJSONWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, errs := ChanFromWriter(w)
+	for i := 0; i < 3; i++ {
+		entries <- Entry{Index: i, Value: i}
+	}
+	close(entries)
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[0,1,2]" {
+		t.Errorf("output mismatch. got: %s", buf.String())
+	}
+
+	ow, err := NewJSONWriter(&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, errs = ChanFromWriter(ow)
+	// entries without keys can't be written to objects, the second send
+	// mustn't block
+	entries <- Entry{Value: 1}
+	entries <- Entry{Value: 2}
+	close(entries)
+	if err := <-errs; err == nil {
+		t.Error("expected write error")
+	}
+}
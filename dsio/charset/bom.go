@@ -0,0 +1,139 @@
+package charset
+
+import (
+	"bytes"
+	"io"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// BOM gives the byte order mark of a charset, nil for charsets that don't
+// have one
+func BOM(name string) []byte {
+	name, err := Normalize(name)
+	if err != nil {
+		return nil
+	}
+	switch name {
+	case UTF8:
+		return bomUTF8
+	case UTF16LE:
+		return bomUTF16LE
+	case UTF16BE:
+		return bomUTF16BE
+	default:
+		return nil
+	}
+}
+
+// BOMReader strips a byte order mark from the start of text & decodes the
+// text to UTF-8. A UTF-8 or UTF-16 byte order mark overrides the charset the
+// reader is created with
+type BOMReader struct {
+	r    io.Reader
+	name string
+	// dec reads the text after the mark, set by the first call to Read
+	dec io.Reader
+	n   int
+	err error
+}
+
+// NewBOMReader wraps r, stripping a byte order mark & decoding text in the
+// charset it marks, or in the named charset if r doesn't start with one
+func NewBOMReader(r io.Reader, name string) (*BOMReader, error) {
+	name, err := Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	return &BOMReader{r: r, name: name}, nil
+}
+
+// Read implements the io.Reader interface
+func (br *BOMReader) Read(p []byte) (int, error) {
+	if br.dec == nil && br.err == nil {
+		br.err = br.detect()
+	}
+	if br.err != nil {
+		return 0, br.err
+	}
+	return br.dec.Read(p)
+}
+
+// detect reads the byte order mark, if there is one
+func (br *BOMReader) detect() error {
+	var buf [3]byte
+	n, err := io.ReadFull(br.r, buf[:])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	head := buf[:n]
+	for _, name := range []string{UTF8, UTF16LE, UTF16BE} {
+		if bom := BOM(name); bytes.HasPrefix(head, bom) {
+			br.name = name
+			br.n = len(bom)
+			break
+		}
+	}
+	dec, err := NewReader(io.MultiReader(bytes.NewReader(head[br.n:]), br.r), br.name)
+	if err != nil {
+		return err
+	}
+	br.dec = dec
+	return nil
+}
+
+// Charset gives the charset text is decoded from. Until the first call to
+// Read it's the charset the reader was created with
+func (br *BOMReader) Charset() string {
+	return br.name
+}
+
+// Len gives the length in bytes of the stripped byte order mark, 0 if there
+// wasn't one or Read hasn't been called
+func (br *BOMReader) Len() int {
+	return br.n
+}
+
+// Close closes the underlying reader if it's an io.Closer
+func (br *BOMReader) Close() error {
+	if cl, ok := br.r.(io.Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
+
+// NewBOMWriter wraps w, writing the byte order mark of a charset ahead of the
+// first bytes written. For charsets without a byte order mark w is returned
+// unchanged
+func NewBOMWriter(w io.Writer, name string) (io.Writer, error) {
+	name, err := Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	bom := BOM(name)
+	if bom == nil {
+		return w, nil
+	}
+	return &bomWriter{w: w, bom: bom}, nil
+}
+
+// bomWriter writes a byte order mark before the first write
+type bomWriter struct {
+	w   io.Writer
+	bom []byte
+}
+
+// Write implements the io.Writer interface
+func (bw *bomWriter) Write(p []byte) (int, error) {
+	if bw.bom != nil {
+		if _, err := bw.w.Write(bw.bom); err != nil {
+			return 0, err
+		}
+		bw.bom = nil
+	}
+	return bw.w.Write(p)
+}
@@ -0,0 +1,313 @@
+// Package charset transcodes text between UTF-8 and legacy character sets,
+// for files written by & for consumers that can't use UTF-8
+package charset
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	// UTF8 is the default charset, text is passed through unchanged
+	UTF8 = "utf-8"
+	// UTF16LE is little endian UTF-16, common in exports from windows tools
+	UTF16LE = "utf-16le"
+	// UTF16BE is big endian UTF-16
+	UTF16BE = "utf-16be"
+	// ISO88591 is latin-1, which maps each byte to the first 256 unicode
+	// code points
+	ISO88591 = "iso-8859-1"
+	// Windows1252 is the western european windows code page
+	Windows1252 = "windows-1252"
+	// ShiftJIS is the japanese Shift_JIS charset
+	ShiftJIS = "shift_jis"
+)
+
+// aliases maps alternate charset names to canonical names
+var aliases = map[string]string{
+	"utf8":      UTF8,
+	"utf16le":   UTF16LE,
+	"utf16be":   UTF16BE,
+	"latin1":    ISO88591,
+	"latin-1":   ISO88591,
+	"iso8859-1": ISO88591,
+	"cp1252":    Windows1252,
+	"shift-jis": ShiftJIS,
+	"sjis":      ShiftJIS,
+}
+
+// Normalize gives the canonical name of a supported charset, names are case
+// insensitive
+func Normalize(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	switch name {
+	case "", UTF8:
+		return UTF8, nil
+	case UTF16LE, UTF16BE, ISO88591, Windows1252, ShiftJIS:
+		return name, nil
+	default:
+		return "", fmt.Errorf("unsupported charset: %s", name)
+	}
+}
+
+// Fallback is how characters a charset can't represent are handled
+type Fallback int
+
+const (
+	// FallbackError fails encoding with an *EncodeError
+	FallbackError Fallback = iota
+	// FallbackReplace writes a '?' in place of the character
+	FallbackReplace
+	// FallbackTransliterate writes the closest ASCII equivalent of the
+	// character, like "e" for "é" or "..." for "…", and '?' if there isn't one
+	FallbackTransliterate
+)
+
+// ParseFallback reads a fallback from "error", "replace" or "transliterate".
+// an empty string is FallbackError
+func ParseFallback(s string) (Fallback, error) {
+	switch s {
+	case "", "error":
+		return FallbackError, nil
+	case "replace":
+		return FallbackReplace, nil
+	case "transliterate":
+		return FallbackTransliterate, nil
+	default:
+		return FallbackError, fmt.Errorf("invalid charset fallback: %s", s)
+	}
+}
+
+// EncodeError is returned when a character can't be represented in a charset
+type EncodeError struct {
+	Charset string
+	Rune    rune
+	// Offset is the byte offset of the character in the UTF-8 input
+	Offset int64
+}
+
+// Error implements the error interface
+func (e *EncodeError) Error() string {
+	return fmt.Sprintf("character %q at byte offset %d can't be encoded in %s", e.Rune, e.Offset, e.Charset)
+}
+
+// encodeFunc appends the encoding of a rune to dst, reporting false if the
+// rune can't be encoded
+type encodeFunc func(dst []byte, r rune) ([]byte, bool)
+
+// NewWriter wraps w, encoding UTF-8 text written to it in a charset. For UTF8
+// w is returned unchanged
+func NewWriter(w io.Writer, name string, fb Fallback) (io.Writer, error) {
+	name, err := Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	var enc encodeFunc
+	switch name {
+	case UTF8:
+		return w, nil
+	case UTF16LE:
+		enc = encodeUTF16LE
+	case UTF16BE:
+		enc = encodeUTF16BE
+	case ISO88591:
+		enc = encodeISO88591
+	case Windows1252:
+		enc = encodeWindows1252
+	case ShiftJIS:
+		enc = encodeShiftJIS
+	}
+	return &Writer{w: w, name: name, enc: enc, fb: fb, ascii: !isUTF16(name)}, nil
+}
+
+// Writer encodes UTF-8 text in a charset. Characters may be split across
+// calls to Write
+type Writer struct {
+	w    io.Writer
+	name string
+	enc  encodeFunc
+	fb   Fallback
+	// ascii is set for charsets that write ASCII characters as single bytes
+	ascii bool
+	// partial holds the start of a character split across writes
+	partial []byte
+	buf     []byte
+	offset  int64
+}
+
+// Write implements the io.Writer interface
+func (cw *Writer) Write(p []byte) (int, error) {
+	in := p
+	if len(cw.partial) > 0 {
+		in = append(cw.partial, p...)
+	}
+	buf := cw.buf[:0]
+	i := 0
+	for i < len(in) {
+		c := in[i]
+		if c < utf8.RuneSelf && cw.ascii {
+			buf = append(buf, c)
+			i++
+			continue
+		}
+		if !utf8.FullRune(in[i:]) {
+			break
+		}
+		r, size := utf8.DecodeRune(in[i:])
+		var ok bool
+		if buf, ok = cw.enc(buf, r); !ok {
+			if buf, ok = cw.fallback(buf, r); !ok {
+				return 0, &EncodeError{Charset: cw.name, Rune: r, Offset: cw.offset + int64(i)}
+			}
+		}
+		i += size
+	}
+	cw.buf = buf
+	cw.partial = append(cw.partial[:0], in[i:]...)
+	cw.offset += int64(i)
+
+	if _, err := cw.w.Write(buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// fallback encodes a rune the charset can't represent
+func (cw *Writer) fallback(dst []byte, r rune) ([]byte, bool) {
+	switch cw.fb {
+	case FallbackReplace:
+		return cw.enc(dst, '?')
+	case FallbackTransliterate:
+		s, ok := Transliterate(r)
+		if !ok {
+			s = "?"
+		}
+		for _, c := range s {
+			if dst, ok = cw.enc(dst, c); !ok {
+				return dst, false
+			}
+		}
+		return dst, true
+	default:
+		return dst, false
+	}
+}
+
+// Close closes the underlying writer if it's an io.Closer, returning an error
+// if the text written ended part way through a character
+func (cw *Writer) Close() error {
+	var err error
+	if len(cw.partial) > 0 {
+		err = fmt.Errorf("incomplete UTF-8 character at end of input")
+	}
+	if cl, ok := cw.w.(io.Closer); ok {
+		if cerr := cl.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// windows1252 maps runes in the 0x80-0x9F range of windows-1252
+var windows1252 = map[rune]byte{
+	0x20ac: 0x80,
+	0x201a: 0x82,
+	0x0192: 0x83,
+	0x201e: 0x84,
+	0x2026: 0x85,
+	0x2020: 0x86,
+	0x2021: 0x87,
+	0x02c6: 0x88,
+	0x2030: 0x89,
+	0x0160: 0x8a,
+	0x2039: 0x8b,
+	0x0152: 0x8c,
+	0x017d: 0x8e,
+	0x2018: 0x91,
+	0x2019: 0x92,
+	0x201c: 0x93,
+	0x201d: 0x94,
+	0x2022: 0x95,
+	0x2013: 0x96,
+	0x2014: 0x97,
+	0x02dc: 0x98,
+	0x2122: 0x99,
+	0x0161: 0x9a,
+	0x203a: 0x9b,
+	0x0153: 0x9c,
+	0x017e: 0x9e,
+	0x0178: 0x9f,
+}
+
+// isUTF16 reports whether a canonical charset name is a UTF-16 charset
+func isUTF16(name string) bool {
+	return name == UTF16LE || name == UTF16BE
+}
+
+func encodeUTF16LE(dst []byte, r rune) ([]byte, bool) {
+	return appendUTF16(dst, r, false), true
+}
+
+func encodeUTF16BE(dst []byte, r rune) ([]byte, bool) {
+	return appendUTF16(dst, r, true), true
+}
+
+// appendUTF16 appends the UTF-16 code units of a rune, using a surrogate
+// pair for runes outside the basic multilingual plane
+func appendUTF16(dst []byte, r rune, bigEndian bool) []byte {
+	units := []uint16{uint16(r)}
+	if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+		units = []uint16{uint16(r1), uint16(r2)}
+	}
+	for _, u := range units {
+		if bigEndian {
+			dst = append(dst, byte(u>>8), byte(u))
+		} else {
+			dst = append(dst, byte(u), byte(u>>8))
+		}
+	}
+	return dst
+}
+
+func encodeISO88591(dst []byte, r rune) ([]byte, bool) {
+	if r <= 0xFF {
+		return append(dst, byte(r)), true
+	}
+	return dst, false
+}
+
+func encodeWindows1252(dst []byte, r rune) ([]byte, bool) {
+	if r < 0x80 || (r >= 0xA0 && r <= 0xFF) {
+		return append(dst, byte(r)), true
+	}
+	if b, ok := windows1252[r]; ok {
+		return append(dst, b), true
+	}
+	return dst, false
+}
+
+func encodeShiftJIS(dst []byte, r rune) ([]byte, bool) {
+	switch {
+	case r < 0x80:
+		return append(dst, byte(r)), true
+	case r >= 0xFF61 && r <= 0xFF9F:
+		// half width katakana
+		return append(dst, byte(r-0xFF61+0xA1)), true
+	case r == 0xA5:
+		// yen sign, which shares a code with backslash
+		return append(dst, 0x5C), true
+	case r == 0x203E:
+		// overline, which shares a code with tilde
+		return append(dst, 0x7E), true
+	}
+	if c, ok := shiftJISTable[r]; ok {
+		return append(dst, byte(c>>8), byte(c)), true
+	}
+	return dst, false
+}
@@ -0,0 +1,272 @@
+package charset
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		in, out, err string
+	}{
+		{"", UTF8, ""},
+		{"UTF-8", UTF8, ""},
+		{"cp1252", Windows1252, ""},
+		{"Shift-JIS", ShiftJIS, ""},
+		{"sjis", ShiftJIS, ""},
+		{"UTF-16LE", UTF16LE, ""},
+		{"latin1", ISO88591, ""},
+		{"ebcdic", "", "unsupported charset: ebcdic"},
+	}
+
+	for i, c := range cases {
+		got, err := Normalize(c.in)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if got != c.out {
+			t.Errorf("case %d expected: %s, got: %s", i, c.out, got)
+		}
+	}
+}
+
+func TestParseFallback(t *testing.T) {
+	cases := []struct {
+		in  string
+		fb  Fallback
+		err string
+	}{
+		{"", FallbackError, ""},
+		{"error", FallbackError, ""},
+		{"replace", FallbackReplace, ""},
+		{"transliterate", FallbackTransliterate, ""},
+		{"ignore", FallbackError, "invalid charset fallback: ignore"},
+	}
+
+	for i, c := range cases {
+		got, err := ParseFallback(c.in)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if got != c.fb {
+			t.Errorf("case %d expected: %d, got: %d", i, c.fb, got)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	cases := []struct {
+		charset string
+		fb      Fallback
+		in      string
+		out     []byte
+		err     string
+	}{
+		{UTF8, FallbackError, "naïve ☃", []byte("naïve ☃"), ""},
+		{Windows1252, FallbackError, "café €5 – “ok”", []byte("caf\xe9 \x805 \x96 \x93ok\x94"), ""},
+		{Windows1252, FallbackError, "a ☃", nil, "character '☃' at byte offset 2 can't be encoded in windows-1252"},
+		{Windows1252, FallbackReplace, "a ☃ Ł", []byte("a ? ?"), ""},
+		{Windows1252, FallbackTransliterate, "Łódź ☃", []byte("L\xf3dz ?"), ""},
+		{ShiftJIS, FallbackError, "abc", []byte("abc"), ""},
+		{ShiftJIS, FallbackError, "日本", []byte("\x93\xfa\x96\x7b"), ""},
+		{ShiftJIS, FallbackError, "ｱ¥", []byte("\xb1\x5c"), ""},
+		{ShiftJIS, FallbackTransliterate, "café…", []byte("cafe\x81\x63"), ""},
+		{ShiftJIS, FallbackError, "é", nil, "character 'é' at byte offset 0 can't be encoded in shift_jis"},
+		{UTF16LE, FallbackError, "a€😀", []byte("a\x00\xac\x20\x3d\xd8\x00\xde"), ""},
+		{UTF16BE, FallbackError, "a€😀", []byte("\x00a\x20\xac\xd8\x3d\xde\x00"), ""},
+		{ISO88591, FallbackError, "café", []byte("caf\xe9"), ""},
+		{ISO88591, FallbackError, "€", nil, "character '€' at byte offset 0 can't be encoded in iso-8859-1"},
+		{ISO88591, FallbackTransliterate, "€…", []byte("EUR..."), ""},
+	}
+
+	for i, c := range cases {
+		buf := &bytes.Buffer{}
+		w, err := NewWriter(buf, c.charset, c.fb)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		_, err = w.Write([]byte(c.in))
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if c.err == "" && !bytes.Equal(buf.Bytes(), c.out) {
+			t.Errorf("case %d expected: %q, got: %q", i, c.out, buf.Bytes())
+		}
+	}
+
+	var encErr *EncodeError
+	w, _ := NewWriter(&bytes.Buffer{}, ShiftJIS, FallbackError)
+	if _, err := w.Write([]byte("é")); !errors.As(err, &encErr) {
+		t.Errorf("expected EncodeError, got: %v", err)
+	}
+}
+
+func TestWriterSplitRunes(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, ShiftJIS, FallbackError)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := []byte("日本語")
+	for i := range in {
+		if _, err := w.Write(in[i : i+1]); err != nil {
+			t.Fatalf("write %d unexpected error: %s", i, err)
+		}
+	}
+	if err := w.(*Writer).Close(); err != nil {
+		t.Errorf("unexpected close error: %s", err)
+	}
+	expect := []byte("\x93\xfa\x96\x7b\x8c\xea")
+	if !bytes.Equal(buf.Bytes(), expect) {
+		t.Errorf("expected: %q, got: %q", expect, buf.Bytes())
+	}
+
+	w, _ = NewWriter(&bytes.Buffer{}, ShiftJIS, FallbackError)
+	w.Write(in[:1])
+	if err := w.(*Writer).Close(); err == nil {
+		t.Error("expected error closing mid-character")
+	}
+}
+
+func TestReader(t *testing.T) {
+	cases := []struct {
+		charset string
+		in      []byte
+		out     string
+	}{
+		{UTF8, []byte("naïve ☃"), "naïve ☃"},
+		{UTF16LE, []byte("a\x00\xac\x20\x3d\xd8\x00\xde"), "a€😀"},
+		{UTF16BE, []byte("\x00a\x20\xac\xd8\x3d\xde\x00"), "a€😀"},
+		{UTF16LE, []byte("\x00\xdca\x00\x3d\xd8"), "�a�"},
+		{UTF16LE, []byte("a\x00b"), "a�"},
+		{ISO88591, []byte("caf\xe9 \x80"), "café \u0080"},
+		{Windows1252, []byte("caf\xe9 \x805 \x96 \x93ok\x94 \x81"), "café €5 – “ok” \u0081"},
+		{ShiftJIS, []byte("\x93\xfa\x96\x7b\xb1\x5c"), "日本ｱ\\"},
+		{ShiftJIS, []byte("\x93 a\x93"), "� a�"},
+	}
+
+	for i, c := range cases {
+		r, err := NewReader(bytes.NewReader(c.in), c.charset)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if string(got) != c.out {
+			t.Errorf("case %d expected: %q, got: %q", i, c.out, got)
+		}
+	}
+
+	if _, err := NewReader(&bytes.Buffer{}, "ebcdic"); err == nil {
+		t.Error("expected error for unsupported charset")
+	}
+}
+
+func TestReaderSplitCharacters(t *testing.T) {
+	in := []byte("\x93\xfa\x96\x7b\x8c\xea")
+	r, err := NewReader(iotest.OneByteReader(bytes.NewReader(in)), ShiftJIS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "日本語" {
+		t.Errorf("expected: %q, got: %q", "日本語", got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	text := "Ünïcode ‘quotes’ & ½"
+	for _, name := range []string{UTF16LE, UTF16BE, ISO88591, Windows1252} {
+		if name == ISO88591 {
+			text = "Ünïcode & ½"
+		}
+		buf := &bytes.Buffer{}
+		w, _ := NewWriter(buf, name, FallbackError)
+		if _, err := w.Write([]byte(text)); err != nil {
+			t.Fatalf("%s unexpected error: %s", name, err)
+		}
+		r, _ := NewReader(buf, name)
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%s unexpected error: %s", name, err)
+		}
+		if string(got) != text {
+			t.Errorf("%s expected: %q, got: %q", name, text, got)
+		}
+	}
+}
+
+func TestBOMReader(t *testing.T) {
+	cases := []struct {
+		charset string
+		in      []byte
+		out     string
+		detect  string
+		n       int
+	}{
+		{UTF8, []byte("\xef\xbb\xbfa,b"), "a,b", UTF8, 3},
+		{UTF8, []byte("a,b"), "a,b", UTF8, 0},
+		{UTF8, []byte("\xff\xfea\x00,\x00"), "a,", UTF16LE, 2},
+		{UTF8, []byte("\xfe\xff\x00a\x00,"), "a,", UTF16BE, 2},
+		{Windows1252, []byte("\xef\xbb\xbfcaf\xc3\xa9"), "café", UTF8, 3},
+		{Windows1252, []byte("caf\xe9"), "café", Windows1252, 0},
+		{UTF8, []byte("\xef\xbb"), "\xef\xbb", UTF8, 0},
+		{UTF8, []byte{}, "", UTF8, 0},
+	}
+
+	for i, c := range cases {
+		r, err := NewBOMReader(iotest.OneByteReader(bytes.NewReader(c.in)), c.charset)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if string(got) != c.out {
+			t.Errorf("case %d expected: %q, got: %q", i, c.out, got)
+		}
+		if r.Charset() != c.detect {
+			t.Errorf("case %d expected charset: %s, got: %s", i, c.detect, r.Charset())
+		}
+		if r.Len() != c.n {
+			t.Errorf("case %d expected bom length: %d, got: %d", i, c.n, r.Len())
+		}
+	}
+}
+
+func TestBOMWriter(t *testing.T) {
+	cases := []struct {
+		charset string
+		out     string
+	}{
+		{UTF8, "\xef\xbb\xbfab"},
+		{UTF16LE, "\xff\xfeab"},
+		{UTF16BE, "\xfe\xffab"},
+		{Windows1252, "ab"},
+	}
+
+	for i, c := range cases {
+		buf := &bytes.Buffer{}
+		w, err := NewBOMWriter(buf, c.charset)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		w.Write([]byte("a"))
+		w.Write([]byte("b"))
+		if buf.String() != c.out {
+			t.Errorf("case %d expected: %q, got: %q", i, c.out, buf.String())
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package charset
+
+import (
+	"io"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// decodeFunc decodes the first character of src, giving it's rune & size in
+// bytes. A size of 0 means src ends part way through a character & more input
+// is needed. atEOF is set when no more input will follow src
+type decodeFunc func(src []byte, atEOF bool) (rune, int)
+
+// NewReader wraps r, decoding text read from it in a charset to UTF-8. For
+// UTF8 r is returned unchanged. Bytes that aren't valid in the charset are
+// read as the unicode replacement character
+func NewReader(r io.Reader, name string) (io.Reader, error) {
+	name, err := Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	var dec decodeFunc
+	switch name {
+	case UTF8:
+		return r, nil
+	case UTF16LE:
+		dec = decodeUTF16LE
+	case UTF16BE:
+		dec = decodeUTF16BE
+	case ISO88591:
+		dec = decodeISO88591
+	case Windows1252:
+		dec = decodeWindows1252
+	case ShiftJIS:
+		dec = decodeShiftJIS
+	}
+	return &Reader{r: r, dec: dec}, nil
+}
+
+// Reader decodes text in a charset to UTF-8
+type Reader struct {
+	r   io.Reader
+	dec decodeFunc
+	// in holds source bytes that haven't been decoded, out decoded bytes that
+	// haven't been read
+	in     []byte
+	out    []byte
+	outBuf []byte
+	err    error
+	buf    [4096]byte
+}
+
+// Read implements the io.Reader interface
+func (cr *Reader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for len(cr.out) == 0 {
+		if cr.err != nil {
+			return 0, cr.err
+		}
+		n, err := cr.r.Read(cr.buf[:])
+		cr.in = append(cr.in, cr.buf[:n]...)
+		cr.err = err
+		cr.decode(err != nil)
+	}
+	n := copy(p, cr.out)
+	cr.out = cr.out[n:]
+	return n, nil
+}
+
+// decode converts buffered source bytes to UTF-8, holding back a trailing
+// partial character unless atEOF is set
+func (cr *Reader) decode(atEOF bool) {
+	out := cr.outBuf[:0]
+	var enc [utf8.UTFMax]byte
+	i := 0
+	for i < len(cr.in) {
+		r, size := cr.dec(cr.in[i:], atEOF)
+		if size == 0 {
+			break
+		}
+		n := utf8.EncodeRune(enc[:], r)
+		out = append(out, enc[:n]...)
+		i += size
+	}
+	cr.in = append(cr.in[:0], cr.in[i:]...)
+	cr.outBuf = out
+	cr.out = out
+}
+
+// Close closes the underlying reader if it's an io.Closer
+func (cr *Reader) Close() error {
+	if cl, ok := cr.r.(io.Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
+
+func decodeUTF16LE(src []byte, atEOF bool) (rune, int) {
+	return decodeUTF16(src, atEOF, false)
+}
+
+func decodeUTF16BE(src []byte, atEOF bool) (rune, int) {
+	return decodeUTF16(src, atEOF, true)
+}
+
+// decodeUTF16 decodes a UTF-16 code unit, or a surrogate pair of them.
+// Unpaired surrogates are read as the replacement character
+func decodeUTF16(src []byte, atEOF, bigEndian bool) (rune, int) {
+	unit := func(b []byte) rune {
+		if bigEndian {
+			return rune(b[0])<<8 | rune(b[1])
+		}
+		return rune(b[1])<<8 | rune(b[0])
+	}
+	if len(src) < 2 {
+		if atEOF {
+			return utf8.RuneError, len(src)
+		}
+		return 0, 0
+	}
+	r1 := unit(src)
+	if !utf16.IsSurrogate(r1) {
+		return r1, 2
+	}
+	if r1 >= 0xDC00 {
+		// a low surrogate without a high surrogate before it
+		return utf8.RuneError, 2
+	}
+	if len(src) < 4 {
+		if atEOF {
+			return utf8.RuneError, len(src)
+		}
+		return 0, 0
+	}
+	if r := utf16.DecodeRune(r1, unit(src[2:])); r != utf8.RuneError {
+		return r, 4
+	}
+	return utf8.RuneError, 2
+}
+
+func decodeISO88591(src []byte, atEOF bool) (rune, int) {
+	return rune(src[0]), 1
+}
+
+var (
+	decodeTablesOnce sync.Once
+	// windows1252Runes & shiftJISRunes are the inverse of the encoding tables
+	windows1252Runes map[byte]rune
+	shiftJISRunes    map[uint16]rune
+)
+
+func buildDecodeTables() {
+	windows1252Runes = make(map[byte]rune, len(windows1252))
+	for r, b := range windows1252 {
+		windows1252Runes[b] = r
+	}
+	shiftJISRunes = make(map[uint16]rune, len(shiftJISTable))
+	for r, c := range shiftJISTable {
+		shiftJISRunes[c] = r
+	}
+}
+
+// decodeWindows1252 decodes a windows-1252 byte. The five bytes windows-1252
+// leaves undefined are read as the C1 control characters with their value
+func decodeWindows1252(src []byte, atEOF bool) (rune, int) {
+	b := src[0]
+	if b < 0x80 || b >= 0xA0 {
+		return rune(b), 1
+	}
+	decodeTablesOnce.Do(buildDecodeTables)
+	if r, ok := windows1252Runes[b]; ok {
+		return r, 1
+	}
+	return rune(b), 1
+}
+
+// decodeShiftJIS decodes a Shift_JIS character. 0x5C & 0x7E are read as the
+// ASCII backslash & tilde
+func decodeShiftJIS(src []byte, atEOF bool) (rune, int) {
+	b := src[0]
+	switch {
+	case b < 0x80:
+		return rune(b), 1
+	case b >= 0xA1 && b <= 0xDF:
+		// half width katakana
+		return rune(b) - 0xA1 + 0xFF61, 1
+	case (b >= 0x81 && b <= 0x9F) || (b >= 0xE0 && b <= 0xFC):
+		if len(src) < 2 {
+			if atEOF {
+				return utf8.RuneError, 1
+			}
+			return 0, 0
+		}
+		decodeTablesOnce.Do(buildDecodeTables)
+		if r, ok := shiftJISRunes[uint16(b)<<8|uint16(src[1])]; ok {
+			return r, 2
+		}
+		return utf8.RuneError, 1
+	default:
+		return utf8.RuneError, 1
+	}
+}
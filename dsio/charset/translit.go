@@ -0,0 +1,61 @@
+package charset
+
+// punctuation maps common non-ASCII symbols to ASCII
+var punctuation = map[rune]string{
+	' ': " ",
+	'©': "(c)",
+	'«': "<<",
+	'®': "(R)",
+	'·': ".",
+	'»': ">>",
+	'Æ': "AE",
+	'Ð': "D",
+	'×': "x",
+	'Ø': "O",
+	'Þ': "Th",
+	'ß': "ss",
+	'æ': "ae",
+	'ð': "d",
+	'ø': "o",
+	'þ': "th",
+	'Đ': "D",
+	'đ': "d",
+	'ı': "i",
+	'Ł': "L",
+	'ł': "l",
+	'Œ': "OE",
+	'œ': "oe",
+	'‐': "-",
+	'‑': "-",
+	'‒': "-",
+	'–': "-",
+	'—': "-",
+	'―': "-",
+	'‘': "'",
+	'’': "'",
+	'‚': "'",
+	'‛': "'",
+	'“': `"`,
+	'”': `"`,
+	'„': `"`,
+	'‟': `"`,
+	'•': "*",
+	'…': "...",
+	'‹': "<",
+	'›': ">",
+	'€': "EUR",
+	'™': "TM",
+}
+
+// Transliterate gives the closest ASCII equivalent of a rune, reporting false
+// if there isn't one
+func Transliterate(r rune) (string, bool) {
+	if r < 0x80 {
+		return string(r), true
+	}
+	if s, ok := punctuation[r]; ok {
+		return s, true
+	}
+	s, ok := decomposedLatin[r]
+	return s, ok
+}
@@ -0,0 +1,107 @@
+package dsio
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+)
+
+// Checkpointer is implemented by readers that can record their position in a
+// body. JSON, NDJSON, CBOR & CSV readers are Checkpointers
+type Checkpointer interface {
+	// Checkpoint returns an opaque token for the position after the last
+	// entry read. Checkpoints are only valid between successful reads
+	Checkpoint() (string, error)
+}
+
+// Checkpoint is the decoded form of a checkpoint token
+type Checkpoint struct {
+	// Format is the data format of the checkpointed body
+	Format string `json:"format"`
+	// Offset is the number of bytes of the body consumed
+	Offset int64 `json:"offset"`
+	// Entries is the number of entries read
+	Entries int `json:"entries"`
+	// State holds format-specific parser state
+	State map[string]int `json:"state,omitempty"`
+}
+
+// Token encodes a checkpoint as a url-safe string
+func (cp *Checkpoint) Token() (string, error) {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// ParseCheckpoint decodes a checkpoint token
+func ParseCheckpoint(token string) (*Checkpoint, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint token: %w", err)
+	}
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint token: %w", err)
+	}
+	if cp.Offset < 0 || cp.Entries < 0 {
+		return nil, fmt.Errorf("invalid checkpoint token: negative position")
+	}
+	return cp, nil
+}
+
+// ResumeEntryReader creates a reader that continues from a checkpoint token. r
+// must read the body starting at the checkpoint's Offset, for example by
+// seeking a file or making an HTTP range request. Entry indexes continue from
+// the checkpoint
+func ResumeEntryReader(st *dataset.Structure, r io.Reader, token string) (EntryReader, error) {
+	cp, err := ParseCheckpoint(token)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	if cp.Format != st.DataFormat().String() {
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("checkpoint is for %s data, structure format is %s", cp.Format, st.DataFormat()))
+	}
+
+	switch st.DataFormat() {
+	case dataset.JSONDataFormat:
+		jr, err := NewJSONReader(st, r)
+		if err != nil {
+			return nil, err
+		}
+		jr.offset = cp.Offset
+		jr.entriesRead = cp.Entries
+		jr.initialized = cp.Entries > 0
+		return jr, nil
+	case dataset.NDJSONDataFormat:
+		nr, err := NewNDJSONReader(st, r)
+		if err != nil {
+			return nil, err
+		}
+		nr.offset = cp.Offset
+		nr.entriesRead = cp.Entries
+		return nr, nil
+	case dataset.CBORDataFormat:
+		cr, err := NewCBORReader(st, r)
+		if err != nil {
+			return nil, err
+		}
+		cr.offset = cp.Offset
+		cr.rowsRead = cp.Entries
+		cr.length = cp.State["length"]
+		return cr, nil
+	case dataset.CSVDataFormat:
+		cr := NewCSVReader(st, r)
+		cr.offset = cp.Offset
+		cr.entriesRead = cp.Entries
+		cr.readHeader = cp.State["header"] == 1
+		return cr, nil
+	default:
+		return nil, fmt.Errorf("resuming %s readers is not supported", st.DataFormat())
+	}
+}
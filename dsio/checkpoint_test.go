@@ -0,0 +1,131 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	csvSt := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "name", "type": "string"},
+					map[string]interface{}{"title": "n", "type": "integer"},
+				},
+			},
+		},
+	}
+	cborBody := &bytes.Buffer{}
+	cborSt := &dataset.Structure{Format: "cbor", Schema: dataset.BaseSchemaArray}
+	w, err := NewEntryWriter(cborSt, cborBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 6; i++ {
+		if err := w.WriteEntry(Entry{Index: i, Value: []interface{}{"row", int64(i)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		st   *dataset.Structure
+		body []byte
+	}{
+		{&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}, []byte(`[ {"a":1}, "two", 3, [4] , null, true ]`)},
+		{&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject}, []byte(`{"a":1, "b":"two", "c":3, "d":[4], "e":null}`)},
+		{cborSt, cborBody.Bytes()},
+		{csvSt, []byte("name,n\ra,1\rb,2\r\nc,3\n\"d\re\",4\rf,5\r")},
+	}
+
+	for _, c := range cases {
+		all := readAllEntries(t, c.st, c.body)
+
+		r, err := NewEntryReader(c.st, bytes.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			if _, err := r.ReadEntry(); err != nil {
+				t.Fatalf("%s: %s", c.st.Format, err)
+			}
+		}
+		token, err := r.(Checkpointer).Checkpoint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cp, err := ParseCheckpoint(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cp.Entries != 3 {
+			t.Errorf("%s: expected 3 entries in checkpoint, got: %d", c.st.Format, cp.Entries)
+		}
+
+		resumed, err := ResumeEntryReader(c.st, bytes.NewReader(c.body[cp.Offset:]), token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []Entry{}
+		for {
+			ent, err := resumed.ReadEntry()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s: resumed read error: %s", c.st.Format, err)
+			}
+			got = append(got, ent)
+		}
+		if !reflect.DeepEqual(all[3:], got) {
+			t.Errorf("%s: resumed entries mismatch.\nexpected: %v\ngot:      %v", c.st.Format, all[3:], got)
+		}
+
+		// checkpoints from resumed readers are positions in the whole body
+		token, err = resumed.(Checkpointer).Checkpoint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cp, _ = ParseCheckpoint(token); cp.Entries != len(all) {
+			t.Errorf("%s: expected %d entries in final checkpoint, got: %d", c.st.Format, len(all), cp.Entries)
+		}
+	}
+}
+
+func readAllEntries(t *testing.T, st *dataset.Structure, body []byte) []Entry {
+	r, err := NewEntryReader(st, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ents := []Entry{}
+	for {
+		ent, err := r.ReadEntry()
+		if err == io.EOF {
+			return ents
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		ents = append(ents, ent)
+	}
+}
+
+func TestResumeEntryReaderErrors(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	if _, err := ResumeEntryReader(st, bytes.NewReader(nil), "not a token!"); err == nil {
+		t.Error("expected invalid token to error")
+	}
+	token, _ := (&Checkpoint{Format: "csv", Offset: 10, Entries: 1}).Token()
+	if _, err := ResumeEntryReader(st, bytes.NewReader(nil), token); err == nil {
+		t.Error("expected format mismatch to error")
+	}
+}
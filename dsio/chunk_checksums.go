@@ -0,0 +1,266 @@
+package dsio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/qri-io/dataset"
+)
+
+// DefaultChunkSize is the chunk size used for chunk checksums when none is
+// specified
+const DefaultChunkSize = 1 << 20
+
+// ChunkError reports a chunk of a body that failed checksum verification
+type ChunkError struct {
+	// Chunk is the zero-indexed number of the chunk
+	Chunk int
+	// Offset is the position of the first byte of the chunk in the body
+	Offset int64
+	// Reason describes the failure
+	Reason string
+}
+
+// Error implements the error interface
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("chunk %d at byte offset %d: %s", e.Chunk, e.Offset, e.Reason)
+}
+
+// ChunkChecksumWriter passes all writes to an underlying writer, recording a
+// checksum for every ChunkSize bytes
+type ChunkChecksumWriter struct {
+	w    io.Writer
+	size int
+	h    hash.Hash
+	n    int
+	sums []string
+}
+
+// NewChunkChecksumWriter wraps w, recording checksums of chunkSize chunks
+func NewChunkChecksumWriter(w io.Writer, chunkSize int) *ChunkChecksumWriter {
+	if chunkSize < 1 {
+		chunkSize = DefaultChunkSize
+	}
+	return &ChunkChecksumWriter{
+		w:    w,
+		size: chunkSize,
+		h:    sha256.New(),
+	}
+}
+
+// Write implements the io.Writer interface
+func (cw *ChunkChecksumWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	written := p[:n]
+	for len(written) > 0 {
+		take := cw.size - cw.n
+		if take > len(written) {
+			take = len(written)
+		}
+		cw.h.Write(written[:take])
+		cw.n += take
+		written = written[take:]
+		if cw.n == cw.size {
+			cw.sums = append(cw.sums, hex.EncodeToString(cw.h.Sum(nil)))
+			cw.h.Reset()
+			cw.n = 0
+		}
+	}
+	return n, err
+}
+
+// Close closes the underlying writer if it's an io.Closer
+func (cw *ChunkChecksumWriter) Close() error {
+	return closeUnderlying(cw.w)
+}
+
+// Checksums gives the checksums of all bytes written so far. a trailing
+// partial chunk is included
+func (cw *ChunkChecksumWriter) Checksums() *dataset.ChunkChecksums {
+	sums := append([]string(nil), cw.sums...)
+	if cw.n > 0 {
+		sums = append(sums, hex.EncodeToString(cw.h.Sum(nil)))
+	}
+	return &dataset.ChunkChecksums{
+		Algorithm: dataset.ChunkChecksumSHA256,
+		ChunkSize: cw.size,
+		Sums:      sums,
+	}
+}
+
+// ChunkChecksumReader verifies a body against chunk checksums as it's read.
+// each chunk is buffered & checked before any of it is returned, so a
+// corrupt chunk is never passed to the consumer
+type ChunkChecksumReader struct {
+	r      io.Reader
+	cs     *dataset.ChunkChecksums
+	buf    []byte
+	pos    int
+	chunk  int
+	offset int64
+	err    error
+}
+
+// NewChunkChecksumReader creates a reader that checks r against cs
+func NewChunkChecksumReader(r io.Reader, cs *dataset.ChunkChecksums) (*ChunkChecksumReader, error) {
+	if err := validateChunkChecksums(cs); err != nil {
+		return nil, err
+	}
+	return &ChunkChecksumReader{
+		r:   r,
+		cs:  cs,
+		buf: make([]byte, 0, cs.ChunkSize),
+	}, nil
+}
+
+// Read implements the io.Reader interface
+func (cr *ChunkChecksumReader) Read(p []byte) (int, error) {
+	if cr.pos == len(cr.buf) {
+		if cr.err != nil {
+			return 0, cr.err
+		}
+		if cr.err = cr.nextChunk(); cr.err != nil {
+			return 0, cr.err
+		}
+	}
+	n := copy(p, cr.buf[cr.pos:])
+	cr.pos += n
+	return n, nil
+}
+
+// Close closes the underlying reader if it's an io.Closer
+func (cr *ChunkChecksumReader) Close() error {
+	return closeUnderlying(cr.r)
+}
+
+// nextChunk reads & verifies the next chunk into the buffer
+func (cr *ChunkChecksumReader) nextChunk() error {
+	cr.offset += int64(len(cr.buf))
+	cr.buf = cr.buf[:cr.cs.ChunkSize]
+	cr.pos = 0
+	n, err := io.ReadFull(cr.r, cr.buf)
+	if err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	if err == nil {
+		err = verifyChunk(cr.cs, cr.chunk, cr.offset, cr.buf[:n])
+	} else if err == io.EOF && cr.chunk < len(cr.cs.Sums) {
+		err = &ChunkError{Chunk: cr.chunk, Offset: cr.offset, Reason: "missing, body is truncated"}
+	}
+	if err != nil {
+		cr.buf = cr.buf[:0]
+		return err
+	}
+	cr.buf = cr.buf[:n]
+	cr.chunk++
+	return nil
+}
+
+func validateChunkChecksums(cs *dataset.ChunkChecksums) error {
+	if cs.Algorithm != "" && cs.Algorithm != dataset.ChunkChecksumSHA256 {
+		return fmt.Errorf("unsupported chunk checksum algorithm: %s", cs.Algorithm)
+	}
+	if cs.ChunkSize < 1 {
+		return fmt.Errorf("chunk checksums require a positive chunk size")
+	}
+	return nil
+}
+
+func verifyChunk(cs *dataset.ChunkChecksums, chunk int, offset int64, data []byte) error {
+	if chunk >= len(cs.Sums) {
+		return &ChunkError{Chunk: chunk, Offset: offset, Reason: "unexpected data past the last chunk"}
+	}
+	sum := sha256.Sum256(data)
+	expect, err := hex.DecodeString(cs.Sums[chunk])
+	if err != nil || !bytes.Equal(sum[:], expect) {
+		return &ChunkError{Chunk: chunk, Offset: offset, Reason: "checksum mismatch"}
+	}
+	return nil
+}
+
+// VerifyChunkChecksums reads all of r, returning an error for each chunk that
+// fails verification
+func VerifyChunkChecksums(r io.Reader, cs *dataset.ChunkChecksums) ([]*ChunkError, error) {
+	if err := validateChunkChecksums(cs); err != nil {
+		return nil, err
+	}
+
+	var (
+		errs   []*ChunkError
+		buf    = make([]byte, cs.ChunkSize)
+		offset int64
+	)
+	for chunk := 0; ; chunk++ {
+		n, err := io.ReadFull(r, buf)
+		if err == io.EOF {
+			for ; chunk < len(cs.Sums); chunk++ {
+				errs = append(errs, &ChunkError{Chunk: chunk, Offset: offset, Reason: "missing, body is truncated"})
+			}
+			return errs, nil
+		} else if err != nil && err != io.ErrUnexpectedEOF {
+			return errs, err
+		}
+
+		if err := verifyChunk(cs, chunk, offset, buf[:n]); err != nil {
+			errs = append(errs, err.(*ChunkError))
+		}
+		offset += int64(n)
+	}
+}
+
+// checksumEntryWriter records chunk checksums on it's structure when closed
+type checksumEntryWriter struct {
+	EntryWriter
+	cw *ChunkChecksumWriter
+}
+
+// Close closes the underlying writer & sets the structure's chunk checksums
+func (w *checksumEntryWriter) Close() error {
+	if err := w.EntryWriter.Close(); err != nil {
+		return err
+	}
+	w.Structure().ChunkChecksums = w.cw.Checksums()
+	return nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *checksumEntryWriter) EntriesWritten() int {
+	return entriesWritten(w.EntryWriter)
+}
+
+// BytesProcessed gives the bytes written
+func (w *checksumEntryWriter) BytesProcessed() int64 {
+	return bytesProcessed(w.EntryWriter)
+}
+
+// checksumEntryReader surfaces checksum errors that parsers would otherwise
+// wrap in their own errors
+type checksumEntryReader struct {
+	EntryReader
+	cr *ChunkChecksumReader
+}
+
+// ReadEntry reads one entry from the underlying reader
+func (r *checksumEntryReader) ReadEntry() (Entry, error) {
+	ent, err := r.EntryReader.ReadEntry()
+	if err != nil && err != io.EOF {
+		if cerr, ok := r.cr.err.(*ChunkError); ok {
+			return ent, cerr
+		}
+	}
+	return ent, err
+}
+
+// EntriesRead gives the number of entries read
+func (r *checksumEntryReader) EntriesRead() int {
+	return entriesRead(r.EntryReader)
+}
+
+// BytesProcessed gives the bytes processed by the underlying reader
+func (r *checksumEntryReader) BytesProcessed() int64 {
+	return bytesProcessed(r.EntryReader)
+}
@@ -0,0 +1,76 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestChunkChecksums(t *testing.T) {
+	st := &dataset.Structure{
+		Format:         "json",
+		Schema:         dataset.BaseSchemaArray,
+		ChunkChecksums: &dataset.ChunkChecksums{ChunkSize: 8},
+	}
+	body := &bytes.Buffer{}
+	w, err := NewEntryWriter(st, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := w.WriteEntry(Entry{Index: i, Value: "value"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cs := st.ChunkChecksums
+	if expect := (body.Len() + 7) / 8; len(cs.Sums) != expect {
+		t.Fatalf("expected %d chunk checksums, got: %d", expect, len(cs.Sums))
+	}
+
+	read := func(data []byte) (int, error) {
+		r, err := NewEntryReader(st, bytes.NewReader(data))
+		if err != nil {
+			return 0, err
+		}
+		n := 0
+		for {
+			if _, err := r.ReadEntry(); err == io.EOF {
+				return n, nil
+			} else if err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+
+	if n, err := read(body.Bytes()); err != nil || n != 10 {
+		t.Fatalf("expected 10 verified entries, got: %d, %v", n, err)
+	}
+
+	corrupt := append([]byte(nil), body.Bytes()...)
+	corrupt[20] = 'X'
+	_, err = read(corrupt)
+	if cerr, ok := err.(*ChunkError); !ok || cerr.Chunk != 2 || cerr.Offset != 16 {
+		t.Errorf("expected checksum error in chunk 2, got: %#v", err)
+	}
+
+	errs, err := VerifyChunkChecksums(bytes.NewReader(corrupt[:40]), cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != len(cs.Sums)-4 {
+		t.Fatalf("expected %d chunk errors, got: %d", len(cs.Sums)-4, len(errs))
+	}
+	if errs[0].Chunk != 2 || errs[0].Reason != "checksum mismatch" {
+		t.Errorf("expected chunk 2 mismatch, got: %s", errs[0])
+	}
+	if errs[1].Chunk != 5 || errs[1].Reason != "missing, body is truncated" {
+		t.Errorf("expected chunk 5 to be missing, got: %s", errs[1])
+	}
+}
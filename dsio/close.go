@@ -0,0 +1,25 @@
+package dsio
+
+import "io"
+
+// KeepOpen wraps r to hide it's Close method. Readers close sources that are
+// io.Closers when the reader is closed, wrapping a source with KeepOpen
+// leaves it open
+func KeepOpen(r io.Reader) io.Reader {
+	return struct{ io.Reader }{r}
+}
+
+// KeepWriterOpen wraps w to hide it's Close method. Writers flush & close
+// destinations that are io.Closers when the writer is closed, wrapping a
+// destination with KeepWriterOpen leaves it open
+func KeepWriterOpen(w io.Writer) io.Writer {
+	return struct{ io.Writer }{w}
+}
+
+// closeUnderlying closes v if it's an io.Closer
+func closeUnderlying(v interface{}) error {
+	if c, ok := v.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
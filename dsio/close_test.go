@@ -0,0 +1,87 @@
+package dsio
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+type closeCounter struct {
+	*bytes.Buffer
+	closed int
+}
+
+func (c *closeCounter) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestReadersCloseSource(t *testing.T) {
+	cases := []struct {
+		st   *dataset.Structure
+		data string
+	}{
+		{&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}, `[1]`},
+		{&dataset.Structure{Format: "csv", Schema: dataset.BaseSchemaArray}, "a,b\n"},
+	}
+
+	for i, c := range cases {
+		src := &closeCounter{Buffer: bytes.NewBufferString(c.data)}
+		r, err := NewEntryReader(c.st, src)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		r.Close()
+		r.Close()
+		if src.closed != 1 {
+			t.Errorf("case %d expected source to be closed once, closed: %d", i, src.closed)
+		}
+
+		src = &closeCounter{Buffer: bytes.NewBufferString(c.data)}
+		if r, err = NewEntryReader(c.st, KeepOpen(src)); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		r.Close()
+		if src.closed != 0 {
+			t.Errorf("case %d expected KeepOpen source to be left open", i)
+		}
+	}
+}
+
+func TestWritersCloseDestination(t *testing.T) {
+	structures := []*dataset.Structure{
+		{Format: "json", Schema: dataset.BaseSchemaArray},
+		{Format: "csv", Schema: dataset.BaseSchemaArray},
+		{Format: "cbor", Schema: dataset.BaseSchemaArray},
+	}
+
+	for i, st := range structures {
+		dst := &closeCounter{Buffer: &bytes.Buffer{}}
+		w, err := NewEntryWriter(st, dst)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if err := w.WriteEntry(Entry{Value: []interface{}{"a"}}); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if dst.closed != 1 {
+			t.Errorf("case %d expected destination to be closed once, closed: %d", i, dst.closed)
+		}
+		if dst.Len() == 0 {
+			t.Errorf("case %d expected output to be flushed before closing", i)
+		}
+
+		dst = &closeCounter{Buffer: &bytes.Buffer{}}
+		if w, err = NewEntryWriter(st, KeepWriterOpen(dst)); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		w.Close()
+		if dst.closed != 0 {
+			t.Errorf("case %d expected KeepWriterOpen destination to be left open", i)
+		}
+	}
+}
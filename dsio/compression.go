@@ -0,0 +1,122 @@
+package dsio
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset/compression"
+)
+
+// Decompress wraps r in a reader that decompresses it's bytes. name is a
+// compression type name, "gzip", "bzip2" & "zstd" streams are decompressed.
+// Other names, including "" & containers like "tar", return r's bytes
+// unchanged. Closing the returned reader closes r if it's an io.Closer
+func Decompress(name string, r io.Reader) (io.ReadCloser, error) {
+	var dr io.Reader
+	switch compression.Codes[name] {
+	case compression.Gzip:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("error reading gzip stream: %w", err)
+		}
+		dr = gz
+	case compression.Bzip2:
+		dr = bzip2.NewReader(r)
+	case compression.Zstd:
+		dr = newZstdReader(r)
+	default:
+		dr = r
+	}
+	return &decompressReader{Reader: dr, src: r}, nil
+}
+
+// decompressReader closes the compressed source when closed
+type decompressReader struct {
+	io.Reader
+	src io.Reader
+}
+
+// Close closes the compressed source if it's an io.Closer
+func (d *decompressReader) Close() error {
+	return closeUnderlying(d.src)
+}
+
+// Compress wraps w in a writer that compresses bytes written to it. name is
+// a compression type name, "gzip" & "zstd" streams are compressed. bzip2 can
+// be decompressed but not compressed. Other names, including "" & containers
+// like "tar", write bytes to w unchanged, matching Decompress. Closing the
+// returned writer finishes the compressed stream & closes w if it's an
+// io.Closer, closing more than once does nothing
+func Compress(name string, w io.Writer) (io.WriteCloser, error) {
+	var cw io.WriteCloser
+	switch compression.Codes[name] {
+	case compression.Gzip:
+		cw = gzip.NewWriter(w)
+	case compression.Zstd:
+		cw = newZstdWriter(w)
+	case compression.Bzip2:
+		err := fmt.Errorf("bzip2 compression is read-only, use gzip or zstd to write compressed bodies")
+		log.Debug(err.Error())
+		return nil, err
+	default:
+		cw = nopWriteCloser{w}
+	}
+	return &compressWriter{WriteCloser: cw, dst: w}, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// compressWriter closes the destination when the compressed stream is
+// finished
+type compressWriter struct {
+	io.WriteCloser
+	dst    io.Writer
+	closed bool
+}
+
+// Close finishes the compressed stream & closes the destination if it's an
+// io.Closer
+func (c *compressWriter) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	err := c.WriteCloser.Close()
+	if cerr := closeUnderlying(c.dst); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// compressEntryWriter finishes the compressed stream when closed, for writers
+// that don't close their destination
+type compressEntryWriter struct {
+	EntryWriter
+	cw io.Closer
+}
+
+// Close closes the underlying writer, then the compressed stream
+func (w *compressEntryWriter) Close() error {
+	err := w.EntryWriter.Close()
+	if cerr := w.cw.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// EntriesWritten gives the number of entries written
+func (w *compressEntryWriter) EntriesWritten() int {
+	return entriesWritten(w.EntryWriter)
+}
+
+// BytesProcessed gives the uncompressed bytes written
+func (w *compressEntryWriter) BytesProcessed() int64 {
+	return bytesProcessed(w.EntryWriter)
+}
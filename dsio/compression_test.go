@@ -0,0 +1,249 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestDecompressEntryReader(t *testing.T) {
+	expect := readCompressedCSV(t, "", "testdata/movies/body.csv")
+	cases := []struct {
+		path, compression string
+	}{
+		{"testdata/movies/body.csv.gz", "gzip"},
+		{"testdata/movies/body.csv.bz2", "bzip2"},
+		{"testdata/movies/body.csv.zst", "zstd"},
+	}
+	for _, c := range cases {
+		got := readCompressedCSV(t, c.compression, c.path)
+		if !reflect.DeepEqual(expect, got) {
+			t.Errorf("%s: entries mismatch. expected %d entries, got %d", c.path, len(expect), len(got))
+		}
+	}
+}
+
+// readCompressedCSV reads all entries of a csv file compressed with
+// compression, checking closing the reader closes the source
+func readCompressedCSV(t *testing.T, compression, path string) []Entry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &closeCounter{Buffer: bytes.NewBuffer(data)}
+	st := &dataset.Structure{Format: "csv", Compression: compression, Schema: dataset.BaseSchemaArray}
+	r, err := NewEntryReader(st, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []Entry
+	for {
+		ent, err := r.ReadEntry()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		entries = append(entries, ent)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if src.closed != 1 {
+		t.Errorf("%s: expected source to be closed once, closed: %d", path, src.closed)
+	}
+	return entries
+}
+
+func TestDecompressKeepOpen(t *testing.T) {
+	data, err := os.ReadFile("testdata/movies/body.csv.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &closeCounter{Buffer: bytes.NewBuffer(data)}
+	st := &dataset.Structure{Format: "csv", Compression: "gzip", Schema: dataset.BaseSchemaArray}
+	r, err := NewEntryReader(st, KeepOpen(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	if src.closed != 0 {
+		t.Errorf("expected KeepOpen source to stay open, closed: %d", src.closed)
+	}
+}
+
+func TestDecompressErrors(t *testing.T) {
+	cases := []struct {
+		compression, input, err string
+	}{
+		{"gzip", "", "error reading gzip stream: EOF"},
+		{"gzip", "not a gzip stream", "error reading gzip stream: gzip: invalid header"},
+	}
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "csv", Compression: c.compression, Schema: dataset.BaseSchemaArray}
+		_, err := NewEntryReader(st, strings.NewReader(c.input))
+		if err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: %q, got: %v", i, c.err, err)
+		}
+	}
+
+	st := &dataset.Structure{Format: "csv", Compression: "zstd", Schema: dataset.BaseSchemaArray}
+	r, err := NewEntryReader(st, strings.NewReader("not zstd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err == nil || !strings.Contains(err.Error(), "corrupt zstd data: invalid magic number") {
+		t.Errorf("expected corrupt zstd error, got: %v", err)
+	}
+}
+
+func TestCompressEntryWriter(t *testing.T) {
+	entries := readCompressedCSV(t, "", "testdata/movies/body.csv")
+	for _, compression := range []string{"gzip", "zstd"} {
+		st := &dataset.Structure{Format: "json", Compression: compression, Schema: dataset.BaseSchemaArray}
+		dst := &closeCounter{Buffer: &bytes.Buffer{}}
+		w, err := NewEntryWriter(st, dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ent := range entries {
+			if err := w.WriteEntry(ent); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if dst.closed != 1 {
+			t.Errorf("%s: expected destination to be closed once, closed: %d", compression, dst.closed)
+		}
+		if entriesWritten(w) != len(entries) {
+			t.Errorf("%s: expected %d entries written, got: %d", compression, len(entries), entriesWritten(w))
+		}
+
+		r, err := NewEntryReader(st, bytes.NewReader(dst.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []interface{}
+		for {
+			ent, err := r.ReadEntry()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s: unexpected error: %s", compression, err)
+			}
+			got = append(got, ent.Value)
+		}
+		if len(got) != len(entries) || !reflect.DeepEqual(got[0], entries[0].Value) {
+			t.Errorf("%s: round trip mismatch, read %d of %d entries", compression, len(got), len(entries))
+		}
+	}
+}
+
+func TestCompressChunkChecksums(t *testing.T) {
+	st := &dataset.Structure{
+		Format:         "json",
+		Compression:    "zstd",
+		Schema:         dataset.BaseSchemaArray,
+		ChunkChecksums: &dataset.ChunkChecksums{ChunkSize: 16},
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewEntryWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		w.WriteEntry(Entry{Index: i, Value: "entry"})
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// checksums cover the compressed bytes
+	if expect := (buf.Len() + 15) / 16; len(st.ChunkChecksums.Sums) != expect {
+		t.Errorf("expected %d chunk checksums, got: %d", expect, len(st.ChunkChecksums.Sums))
+	}
+
+	r, err := NewEntryReader(st, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := r.ReadEntry(); err != nil {
+			t.Fatalf("entry %d: %s", i, err)
+		}
+	}
+}
+
+func TestCompressErrors(t *testing.T) {
+	cases := []struct {
+		compression, err string
+	}{
+		{"bzip2", "bzip2 compression is read-only, use gzip or zstd to write compressed bodies"},
+	}
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "json", Compression: c.compression, Schema: dataset.BaseSchemaArray}
+		if _, err := NewEntryWriter(st, &bytes.Buffer{}); err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: %q, got: %v", i, c.err, err)
+		}
+	}
+}
+
+func TestCompressKeepWriterOpen(t *testing.T) {
+	dst := &closeCounter{Buffer: &bytes.Buffer{}}
+	cw, err := Compress("gzip", KeepWriterOpen(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cw.Write([]byte("data"))
+	cw.Close()
+	cw.Close()
+	if dst.closed != 0 {
+		t.Errorf("expected KeepWriterOpen destination to be left open")
+	}
+	rc, err := Decompress("gzip", dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := io.ReadAll(rc); err != nil || string(data) != "data" {
+		t.Errorf("expected round trip of data, got: %q %v", data, err)
+	}
+}
+
+func TestCompressionPassThrough(t *testing.T) {
+	// containers & unknown compressions are read & written unchanged
+	for _, name := range []string{"tar", "lzma"} {
+		st := &dataset.Structure{Format: "json", Compression: name, Schema: dataset.BaseSchemaArray}
+		buf := &bytes.Buffer{}
+		w, err := NewEntryWriter(st, buf)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if err := w.WriteEntry(Entry{Value: "a"}); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if buf.String() != `["a"]` {
+			t.Errorf("%s: expected uncompressed output, got: %q", name, buf.String())
+		}
+
+		r, err := NewEntryReader(st, buf)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if ent, err := r.ReadEntry(); err != nil || ent.Value != "a" {
+			t.Errorf("%s: expected entry 'a', got: %v, %v", name, ent.Value, err)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package dsio
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/qri-io/dataset"
+)
+
+// ConcurrentConfig encapsulates configuration for a ConcurrentWriter
+type ConcurrentConfig struct {
+	// Unordered writes entries in the order WriteEntry is called instead of
+	// by index
+	Unordered bool
+	// Start is the index of the first entry of ordered writes. default 0
+	Start int
+	// Window is the maximum number of entries an ordered writer holds while
+	// waiting for an earlier index. WriteEntry blocks while the window is
+	// full, except for the next entry to write. default 1024
+	Window int
+}
+
+// ConcurrentWriter wraps an EntryWriter, making it safe to call WriteEntry
+// from multiple goroutines. By default entries are written in order of their
+// Index, holding entries that arrive early until every earlier index is
+// written. Writers configured as unordered write entries as they arrive.
+// The wrapped writer is only ever called by one goroutine at a time
+type ConcurrentWriter struct {
+	w   EntryWriter
+	cfg ConcurrentConfig
+
+	lk   sync.Mutex
+	cond *sync.Cond
+	// next is the index of the next entry to write in order
+	next    int
+	pending map[int]Entry
+	written int
+	err     error
+	closed  bool
+}
+
+var _ EntryWriter = (*ConcurrentWriter)(nil)
+
+// NewConcurrentWriter wraps w for concurrent writes
+func NewConcurrentWriter(w EntryWriter, configs ...func(cfg *ConcurrentConfig)) *ConcurrentWriter {
+	cfg := &ConcurrentConfig{
+		Window: 1024,
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	if cfg.Window < 1 {
+		cfg.Window = 1
+	}
+
+	cw := &ConcurrentWriter{
+		w:       w,
+		cfg:     *cfg,
+		next:    cfg.Start,
+		pending: map[int]Entry{},
+	}
+	cw.cond = sync.NewCond(&cw.lk)
+	return cw
+}
+
+// Structure gives the structure of the underlying writer
+func (cw *ConcurrentWriter) Structure() *dataset.Structure {
+	return cw.w.Structure()
+}
+
+// WriteEntry writes an entry, or holds it until earlier entries are written
+// when writing in order. The first error from the underlying writer is
+// returned by every later call
+func (cw *ConcurrentWriter) WriteEntry(ent Entry) error {
+	cw.lk.Lock()
+	defer cw.lk.Unlock()
+
+	if cw.cfg.Unordered {
+		if err := cw.check(); err != nil {
+			return err
+		}
+		return cw.write(ent)
+	}
+
+	for {
+		if err := cw.check(); err != nil {
+			return err
+		}
+		if ent.Index < cw.next {
+			return fmt.Errorf("entry %d: index already written", ent.Index)
+		}
+		if _, ok := cw.pending[ent.Index]; ok {
+			return fmt.Errorf("entry %d: duplicate index", ent.Index)
+		}
+		if ent.Index == cw.next || len(cw.pending) < cw.cfg.Window {
+			break
+		}
+		cw.cond.Wait()
+	}
+
+	if ent.Index != cw.next {
+		cw.pending[ent.Index] = ent
+		return nil
+	}
+	if err := cw.write(ent); err != nil {
+		return err
+	}
+	for {
+		ent, ok := cw.pending[cw.next]
+		if !ok {
+			break
+		}
+		delete(cw.pending, cw.next)
+		if err := cw.write(ent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check errors if the writer can't accept entries. callers must hold the lock
+func (cw *ConcurrentWriter) check() error {
+	if cw.err != nil {
+		return cw.err
+	}
+	if cw.closed {
+		return fmt.Errorf("write to closed writer")
+	}
+	return nil
+}
+
+// write writes an entry to the underlying writer, waking writers waiting on
+// the window. callers must hold the lock
+func (cw *ConcurrentWriter) write(ent Entry) error {
+	defer cw.cond.Broadcast()
+	if err := cw.w.WriteEntry(ent); err != nil {
+		log.Debug(err.Error())
+		cw.err = fmt.Errorf("error writing entry %d: %w", ent.Index, err)
+		cw.pending = map[int]Entry{}
+		return cw.err
+	}
+	cw.written++
+	cw.next = ent.Index + 1
+	return nil
+}
+
+// EntriesWritten gives the number of entries written to the underlying
+// writer, excluding held entries
+func (cw *ConcurrentWriter) EntriesWritten() int {
+	cw.lk.Lock()
+	defer cw.lk.Unlock()
+	return cw.written
+}
+
+// Pending gives the number of entries held while waiting for an earlier index
+func (cw *ConcurrentWriter) Pending() int {
+	cw.lk.Lock()
+	defer cw.lk.Unlock()
+	return len(cw.pending)
+}
+
+// BytesProcessed gives the bytes written by the underlying writer
+func (cw *ConcurrentWriter) BytesProcessed() int64 {
+	cw.lk.Lock()
+	defer cw.lk.Unlock()
+	return bytesProcessed(cw.w)
+}
+
+// Close closes the underlying writer. Closing an ordered writer that holds
+// entries errors, reporting the first missing index. Writes blocked on the
+// window return an error
+func (cw *ConcurrentWriter) Close() error {
+	cw.lk.Lock()
+	defer cw.lk.Unlock()
+	if cw.closed {
+		return cw.err
+	}
+	cw.closed = true
+	cw.cond.Broadcast()
+
+	if len(cw.pending) > 0 && cw.err == nil {
+		cw.err = fmt.Errorf("missing entry %d, %d entries weren't written", cw.next, len(cw.pending))
+		log.Debug(cw.err.Error())
+	}
+	if err := cw.w.Close(); err != nil {
+		log.Debug(err.Error())
+		if cw.err == nil {
+			cw.err = err
+		}
+	}
+	return cw.err
+}
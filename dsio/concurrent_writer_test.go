@@ -0,0 +1,193 @@
+package dsio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestConcurrentWriterOrdered(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	buf, err := NewEntryBuffer(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewConcurrentWriter(buf, func(cfg *ConcurrentConfig) { cfg.Window = 8 })
+
+	// workers write interleaved indexes, so entries arrive out of order
+	const workers, count = 8, 400
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(start int) {
+			defer wg.Done()
+			for j := start; j < count; j += workers {
+				if err := w.WriteEntry(Entry{Index: j, Value: j}); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(workers - 1 - i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	if w.Pending() != 0 {
+		t.Errorf("expected no pending entries, got: %d", w.Pending())
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.EntriesWritten() != count {
+		t.Errorf("expected %d entries written, got: %d", count, w.EntriesWritten())
+	}
+
+	i := 0
+	err = EachEntry(buf, func(_ int, ent Entry, err error) error {
+		if ent.Value != i {
+			return fmt.Errorf("entry %d mismatch: %v", i, ent.Value)
+		}
+		i++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentWriterUnordered(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	buf, err := NewEntryBuffer(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewConcurrentWriter(buf, func(cfg *ConcurrentConfig) { cfg.Unordered = true })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				// unordered writers don't check indexes
+				if err := w.WriteEntry(Entry{Value: n*50 + j}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := []int{}
+	err = EachEntry(buf, func(_ int, ent Entry, err error) error {
+		got = append(got, ent.Value.(int))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Ints(got)
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("expected each value once, missing: %d", i)
+		}
+	}
+	if len(got) != 200 {
+		t.Errorf("expected 200 entries, got: %d", len(got))
+	}
+}
+
+func TestConcurrentWriterWindow(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	buf, err := NewEntryBuffer(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewConcurrentWriter(buf, func(cfg *ConcurrentConfig) {
+		cfg.Start = 10
+		cfg.Window = 2
+	})
+
+	for _, i := range []int{12, 11} {
+		if err := w.WriteEntry(Entry{Index: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	blocked := make(chan error)
+	go func() { blocked <- w.WriteEntry(Entry{Index: 13}) }()
+	select {
+	case err := <-blocked:
+		t.Fatalf("expected write to block on a full window, got: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// the next index is always accepted, releasing the window
+	if err := w.WriteEntry(Entry{Index: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-blocked; err != nil {
+		t.Fatal(err)
+	}
+	if w.EntriesWritten() != 4 {
+		t.Errorf("expected 4 entries written, got: %d", w.EntriesWritten())
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentWriterErrors(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	buf, err := NewEntryBuffer(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewConcurrentWriter(buf)
+	if err := w.WriteEntry(Entry{Index: 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Index: 0}); err == nil || err.Error() != "entry 0: index already written" {
+		t.Errorf("expected rewrite error, got: %v", err)
+	}
+	if err := w.WriteEntry(Entry{Index: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Index: 2}); err == nil || err.Error() != "entry 2: duplicate index" {
+		t.Errorf("expected duplicate error, got: %v", err)
+	}
+	expect := "missing entry 1, 1 entries weren't written"
+	if err := w.Close(); err == nil || err.Error() != expect {
+		t.Errorf("expected close error: %q, got: %v", expect, err)
+	}
+	if err := w.WriteEntry(Entry{Index: 1}); err == nil {
+		t.Error("expected write after close to error")
+	}
+
+	fw := NewConcurrentWriter(&failingWriter{EntryWriter: buf, after: 1})
+	if err := fw.WriteEntry(Entry{Index: 1}); err != nil {
+		t.Fatal(err)
+	}
+	// writing index 0 flushes index 1, which fails
+	if err := fw.WriteEntry(Entry{Index: 0}); err == nil || !strings.Contains(err.Error(), "error writing entry 1: disk full") {
+		t.Errorf("expected write error, got: %v", err)
+	}
+	if err := fw.WriteEntry(Entry{Index: 2}); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("expected sticky write error, got: %v", err)
+	}
+	if fw.EntriesWritten() != 1 {
+		t.Errorf("expected 1 entry written, got: %d", fw.EntriesWritten())
+	}
+}
@@ -0,0 +1,70 @@
+package dsio
+
+import "io"
+
+// EntryReadCounter is implemented by readers that count the entries they read
+type EntryReadCounter interface {
+	// EntriesRead gives the number of entries successfully read
+	EntriesRead() int
+}
+
+// EntryWriteCounter is implemented by writers that count the entries they write
+type EntryWriteCounter interface {
+	// EntriesWritten gives the number of entries successfully written
+	EntriesWritten() int
+}
+
+// ByteCounter is implemented by readers & writers that count the bytes of
+// encoded data they've handled
+type ByteCounter interface {
+	// BytesProcessed gives the number of encoded bytes consumed by a reader, or
+	// emitted by a writer. Readers that buffer input only count bytes they've
+	// parsed, writers that buffer output only count bytes they've flushed
+	BytesProcessed() int64
+}
+
+// countingWriter counts bytes written to an underlying writer
+type countingWriter struct {
+	w      io.Writer
+	n      int64
+	closed bool
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// Close closes the underlying writer if it's an io.Closer
+func (cw *countingWriter) Close() error {
+	if cw.closed {
+		return nil
+	}
+	cw.closed = true
+	return closeUnderlying(cw.w)
+}
+
+// entriesRead gives the count of entries read by r, if r counts entries
+func entriesRead(r EntryReader) int {
+	if c, ok := r.(EntryReadCounter); ok {
+		return c.EntriesRead()
+	}
+	return 0
+}
+
+// entriesWritten gives the count of entries written by w, if w counts entries
+func entriesWritten(w EntryWriter) int {
+	if c, ok := w.(EntryWriteCounter); ok {
+		return c.EntriesWritten()
+	}
+	return 0
+}
+
+// bytesProcessed gives the count of bytes processed by v, if v counts bytes
+func bytesProcessed(v interface{}) int64 {
+	if c, ok := v.(ByteCounter); ok {
+		return c.BytesProcessed()
+	}
+	return 0
+}
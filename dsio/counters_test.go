@@ -0,0 +1,67 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestCounters(t *testing.T) {
+	cases := []*dataset.Structure{
+		{Format: "json", Schema: dataset.BaseSchemaArray},
+		{Format: "cbor", Schema: dataset.BaseSchemaArray},
+		{Format: "csv", Schema: dataset.BaseSchemaArray},
+	}
+
+	for _, st := range cases {
+		buf := &bytes.Buffer{}
+		w, err := NewEntryWriter(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 5; i++ {
+			if err := w.WriteEntry(Entry{Index: i, Value: []interface{}{"a", int64(i)}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if n := w.(EntryWriteCounter).EntriesWritten(); n != 5 {
+			t.Errorf("%s: expected 5 entries written, got: %d", st.Format, n)
+		}
+		if n := w.(ByteCounter).BytesProcessed(); n != int64(buf.Len()) {
+			t.Errorf("%s: expected %d bytes written, got: %d", st.Format, buf.Len(), n)
+		}
+
+		size := buf.Len()
+		r, err := NewEntryReader(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.ReadEntry(); err != nil {
+			t.Fatal(err)
+		}
+		if n := r.(EntryReadCounter).EntriesRead(); n != 1 {
+			t.Errorf("%s: expected 1 entry read, got: %d", st.Format, n)
+		}
+		if n := r.(ByteCounter).BytesProcessed(); n <= 0 || n >= int64(size) {
+			t.Errorf("%s: expected partial bytes processed, got: %d of %d", st.Format, n, size)
+		}
+		for {
+			if _, err := r.ReadEntry(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if n := r.(EntryReadCounter).EntriesRead(); n != 5 {
+			t.Errorf("%s: expected 5 entries read, got: %d", st.Format, n)
+		}
+		if n := r.(ByteCounter).BytesProcessed(); n != int64(size) {
+			t.Errorf("%s: expected %d bytes processed, got: %d", st.Format, size, n)
+		}
+	}
+}
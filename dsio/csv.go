@@ -3,12 +3,18 @@ package dsio
 import (
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio/charset"
 	"github.com/qri-io/dataset/dsio/replacecr"
+	"github.com/qri-io/dataset/dslog"
 	"github.com/qri-io/dataset/vals"
 )
 
@@ -17,7 +23,42 @@ type CSVReader struct {
 	st         *dataset.Structure
 	readHeader bool
 	r          *csv.Reader
-	types      []string
+	src        *replacecr.CountingReader
+	// offset is the position in the body the source starts at, nonzero for
+	// resumed readers
+	offset      int64
+	entriesRead int
+	fieldDecoder
+	// footerRows is the number of trailing rows to hold back from the body.
+	// pending holds rows read ahead to find the footer, end is the source
+	// offset after the last row returned
+	footerRows int
+	pending    []csvRecord
+	footer     [][]string
+	end        int64
+	// annotated is the structure with units found by the ParseUnits option
+	// recorded in it's schema
+	annotated *dataset.Structure
+	// span is the source position of the last record read, see rawSpan
+	span [2]int64
+	// transcoded is set when the source is decoded from a charset other than
+	// utf-8, positions are then offsets in the decoded text
+	transcoded bool
+	// bom strips a byte order mark from the source when the StripBOM option
+	// is set
+	bom *charset.BOMReader
+	// objectRow reads records into objects for schemas with object rows
+	objectRow *csvObjectRow
+}
+
+// csvRecord is a read-ahead record & the source offsets around it
+type csvRecord struct {
+	fields []string
+	start  int64
+	end    int64
+	// err is set for records with the wrong number of fields, which is only
+	// an error if the record isn't part of the footer
+	err error
 }
 
 var _ EntryReader = (*CSVReader)(nil)
@@ -25,165 +66,772 @@ var _ EntryReader = (*CSVReader)(nil)
 // NewCSVReader creates a reader from a structure and read source
 func NewCSVReader(st *dataset.Structure, r io.Reader) *CSVReader {
 	// TODO - handle error
-	_, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+	titles, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+
+	var (
+		layouts    []string
+		bools      *booleanTokens
+		units      *numberUnits
+		footerRows int
+	)
+	opts, err := dataset.NewCSVOptions(st.FormatConfig)
+	if err != nil {
+		opts = nil
+	}
+	var bom *charset.BOMReader
+	transcoded := false
+	if opts != nil && opts.StripBOM {
+		// the byte order mark reader decodes text in the charset it finds
+		if br, err := charset.NewBOMReader(r, opts.Encoding); err == nil {
+			r = br
+			bom = br
+		}
+	} else if opts != nil && opts.Encoding != "" && opts.Encoding != charset.UTF8 {
+		if dec, err := charset.NewReader(r, opts.Encoding); err == nil {
+			r = dec
+			transcoded = true
+		}
+	}
 
-	csvr := csv.NewReader(replacecr.Reader(r))
+	src := replacecr.NewCountingReader(r)
+	csvr := csv.NewReader(src)
+	// decoded values never reference the record slice, so it's safe to reuse
+	csvr.ReuseRecord = true
 
-	if fopts, err := dataset.ParseFormatConfigMap(dataset.CSVDataFormat, st.FormatConfig); err == nil {
-		if opts, ok := fopts.(*dataset.CSVOptions); ok {
-			csvr.LazyQuotes = opts.LazyQuotes
-			if opts.VariadicFields == true {
-				csvr.FieldsPerRecord = -1
-			}
-			if opts.Separator != rune(0) {
-				csvr.Comma = opts.Separator
-			}
+	if opts != nil {
+		csvr.LazyQuotes = opts.LazyQuotes
+		if opts.VariadicFields == true {
+			csvr.FieldsPerRecord = -1
+		}
+		if opts.Separator != rune(0) {
+			csvr.Comma = opts.Separator
+		}
+		layouts = columnDateLayouts(titles, opts.DateLayouts)
+		bools = newBooleanTokens(opts.BooleanTokens)
+		units = newNumberUnits(opts)
+		if opts.SkipFooterRows > 0 {
+			footerRows = opts.SkipFooterRows
+			// read-ahead records are held, so can't share a slice
+			csvr.ReuseRecord = false
 		}
 	}
 
-	return &CSVReader{
-		st:    st,
-		r:     csvr,
-		types: types,
+	cr := &CSVReader{
+		st:           st,
+		r:            csvr,
+		src:          src,
+		fieldDecoder: fieldDecoder{types: types, layouts: layouts, bools: bools, units: units},
+		footerRows:   footerRows,
+		transcoded:   transcoded,
+		bom:          bom,
+		objectRow:    newCSVObjectRow(st, opts),
 	}
+	if cr.objectRow != nil && (opts == nil || !opts.HeaderRow) {
+		// without a header row fields are in sorted property order
+		_ = cr.setObjectKeys(sortedKeys(cr.objectRow.props))
+	}
+	return cr
 }
 
-// Structure gives this reader's structure
+// Structure gives this reader's structure. Readers with the ParseUnits option
+// record the unit & locale of number columns in the schema as they're found
 func (r *CSVReader) Structure() *dataset.Structure {
+	if r.units != nil && r.units.changed {
+		r.annotated = r.units.annotate(r.st)
+		r.units.changed = false
+	}
+	if r.annotated != nil {
+		return r.annotated
+	}
 	return r.st
 }
 
 // ReadEntry reads one CSV record from the reader
 func (r *CSVReader) ReadEntry() (Entry, error) {
+	ent, err := r.readEntry(false)
+	return ent, parseError("csv", r.entriesRead, err)
+}
+
+// SkipEntries reads past up to n records without decoding their values, see
+// EntrySkipper
+func (r *CSVReader) SkipEntries(n int) (int, error) {
+	skipped, err := skipEntries(func() (Entry, error) { return r.readEntry(true) }, n)
+	return skipped, parseError("csv", r.entriesRead, err)
+}
+
+// readEntry reads the next record, decoding it unless skip is set
+func (r *CSVReader) readEntry(skip bool) (Entry, error) {
+	data, err := r.next()
+	if err != nil {
+		return Entry{}, err
+	}
+	if skip {
+		r.entriesRead++
+		return Entry{}, nil
+	}
+
+	value, err := r.decodeRecord(&r.fieldDecoder, data)
+	if err != nil {
+		log.Debugw(err.Error(), dslog.F("index", r.entriesRead))
+		return Entry{}, err
+	}
+	r.entriesRead++
+	return Entry{Value: value}, nil
+}
+
+// next reads the next body record, reading the header row first
+func (r *CSVReader) next() ([]string, error) {
 	if !r.readHeader {
 		if HasHeaderRow(r.st) {
-			if _, err := r.r.Read(); err != nil {
-				if err.Error() != "EOF" {
+			header, err := r.read()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
 					log.Debug(err.Error())
 				}
-				return Entry{}, err
+				return nil, err
+			}
+			if r.objectRow != nil {
+				// records can reuse the header slice
+				if err := r.setObjectKeys(append([]string(nil), header...)); err != nil {
+					log.Debug(err.Error())
+					return nil, err
+				}
 			}
 		}
 		r.readHeader = true
 	}
 
-	data, err := r.r.Read()
-	if err != nil {
-		log.Debug(err.Error())
-		return Entry{}, err
+	data, err := r.readRecord()
+	if err != nil && err != io.EOF {
+		log.Debugw(err.Error(), dslog.F("index", r.entriesRead))
 	}
+	return data, err
+}
 
-	value, err := r.decode(data)
+// decodeRecord casts the fields of a record with fd, giving an object for
+// schemas with object rows. It doesn't modify the reader, so records can be
+// decoded on other goroutines with their own copy of the field decoder
+func (r *CSVReader) decodeRecord(fd *fieldDecoder, fields []string) (interface{}, error) {
+	value, err := fd.decode(fields)
 	if err != nil {
-		log.Debug(err.Error())
-		return Entry{}, err
+		return nil, err
+	}
+	if r.objectRow != nil {
+		return r.objectRow.object(fields, value)
 	}
+	return value, nil
+}
 
-	return Entry{Value: value}, nil
+// setObjectKeys sets the property names of record fields for object rows,
+// decoding each field with the type & date layout of it's property
+func (r *CSVReader) setObjectKeys(keys []string) error {
+	if err := r.objectRow.setKeys(keys); err != nil {
+		return err
+	}
+	r.types = r.objectRow.types()
+	r.kinds = nil
+	r.layouts = columnDateLayouts(keys, r.objectRow.dateLayouts)
+	return nil
+}
+
+// read reads one record from the source, recording it's span
+func (r *CSVReader) read() ([]string, error) {
+	start := r.src.SourceOffset(r.r.InputOffset())
+	rec, err := r.r.Read()
+	r.span = [2]int64{start, r.src.SourceOffset(r.r.InputOffset())}
+	return rec, err
+}
+
+// rawSpan gives the source position of the last record read. Positions in
+// transcoded text don't match the source, so transcoded readers give an
+// empty span
+func (r *CSVReader) rawSpan() (start, end int64) {
+	if r.isTranscoded() {
+		return 0, 0
+	}
+	bom := r.start() - r.offset
+	return bom + r.span[0], bom + r.span[1]
+}
+
+// readRecord reads the next body record, holding back footer rows
+func (r *CSVReader) readRecord() ([]string, error) {
+	if r.footerRows == 0 {
+		return r.read()
+	}
+	if r.footer != nil {
+		return nil, io.EOF
+	}
+
+	for len(r.pending) <= r.footerRows {
+		rec, err := r.read()
+		if err == io.EOF {
+			r.footer = make([][]string, len(r.pending))
+			for i, p := range r.pending {
+				r.footer[i] = p.fields
+			}
+			r.pending = nil
+			return nil, io.EOF
+		} else if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, err
+		}
+		r.pending = append(r.pending, csvRecord{fields: rec, start: r.span[0], end: r.span[1], err: err})
+	}
+
+	rec := r.pending[0]
+	r.pending = r.pending[1:]
+	r.span = [2]int64{rec.start, rec.end}
+	r.end = rec.end
+	if rec.err != nil {
+		return nil, rec.err
+	}
+	return rec.fields, nil
+}
+
+// Footer gives the rows excluded from the body by the SkipFooterRows option.
+// Footer is nil until the reader has read to the end of the body
+func (r *CSVReader) Footer() [][]string {
+	return r.footer
+}
+
+// ReadEntries reads up to n records, decoding them straight into the batch,
+// see BatchReader
+func (r *CSVReader) ReadEntries(n int) ([]Entry, error) {
+	ents, err := readEntries(func() (Entry, error) { return r.readEntry(false) }, n)
+	return ents, parseError("csv", r.entriesRead, err)
+}
+
+// EntriesRead gives the number of entries read, including entries before the
+// checkpoint a resumed reader started from
+func (r *CSVReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes parsed, including bytes before the
+// checkpoint a resumed reader started from. Bodies in a charset other than
+// utf-8 are counted in bytes of decoded utf-8 text
+func (r *CSVReader) BytesProcessed() int64 {
+	return r.start() + r.src.SourceOffset(r.r.InputOffset())
+}
+
+// recordEnd gives the position in the body after the last record returned.
+// Rows read ahead to find the footer haven't been returned yet, so it can be
+// behind BytesProcessed
+func (r *CSVReader) recordEnd() int64 {
+	if r.footerRows > 0 {
+		return r.start() + r.end
+	}
+	return r.BytesProcessed()
+}
+
+// start gives the position in the body the source starts at, after any
+// stripped byte order mark
+func (r *CSVReader) start() int64 {
+	if r.bom != nil {
+		return r.offset + int64(r.bom.Len())
+	}
+	return r.offset
+}
+
+// isTranscoded reports whether the source is decoded from a charset other
+// than utf-8
+func (r *CSVReader) isTranscoded() bool {
+	if r.bom != nil {
+		return r.bom.Charset() != charset.UTF8
+	}
+	return r.transcoded
+}
+
+// Checkpoint returns a token recording the position of the reader, for use with
+// ResumeEntryReader. Readers decoding a charset other than utf-8 can't be
+// checkpointed, nor can readers mapping object rows by header row titles,
+// which a resumed reader doesn't see
+func (r *CSVReader) Checkpoint() (string, error) {
+	if r.isTranscoded() {
+		return "", fmt.Errorf("checkpoints aren't supported for transcoded csv bodies")
+	}
+	if r.objectRow != nil && HasHeaderRow(r.st) {
+		return "", fmt.Errorf("checkpoints aren't supported for csv object rows with a header row")
+	}
+	header := 0
+	if r.readHeader {
+		header = 1
+	}
+	cp := &Checkpoint{
+		Format:  dataset.CSVDataFormat.String(),
+		Offset:  r.recordEnd(),
+		Entries: r.entriesRead,
+		State:   map[string]int{"header": header},
+	}
+	return cp.Token()
 }
 
 // Close finalizes the reader
 func (r *CSVReader) Close() error {
-	// TODO (b5): we should retain a reference to the underlying reader &
-	// check if it's an io.ReadCloser, calling close here if so
-	return nil
+	return r.src.Close()
+}
+
+// fieldDecoder casts the text fields of delimited records to the types of a
+// schema's columns
+type fieldDecoder struct {
+	types []string
+	// kinds are types resolved to vals.Type, stringKinds are used in place of
+	// kinds for records with more fields than the schema defines
+	kinds       []vals.Type
+	stringKinds []vals.Type
+	// layouts are date layouts by column index
+	layouts []string
+	// bools reads boolean columns, nil for the default tokens
+	bools *booleanTokens
+	// units reads numbers with units, nil unless the ParseUnits option is set
+	units *numberUnits
 }
 
 // decode uses specified types from structure's schema to cast csv string values to their
 // intended types. If casting fails because the data is invalid, it's left as a string instead
-// of causing an error.
-func (r *CSVReader) decode(strings []string) ([]interface{}, error) {
-	vs := make([]interface{}, len(strings))
-	types := r.types
-	if len(types) < len(strings) {
-		// TODO - fix. for now is types fails to parse we just assume all types
-		// are strings
-		types = make([]string, len(strings))
-		for i := range types {
-			types[i] = "string"
-		}
-	}
-	for i, str := range strings {
-		vs[i] = str
-
-		switch types[i] {
-		case "number":
-			if num, err := vals.ParseNumber([]byte(str)); err == nil {
+// of causing an error. Scalar values are parsed directly from the field strings the csv reader
+// produces, avoiding a []byte copy per field
+func (r *fieldDecoder) decode(fields []string) ([]interface{}, error) {
+	vs := make([]interface{}, len(fields))
+	kinds := r.columnKinds(len(fields))
+
+	for i, str := range fields {
+		if i < len(r.layouts) && r.layouts[i] != "" && str != "" {
+			if t, err := time.Parse(r.layouts[i], str); err == nil {
+				vs[i] = t
+				continue
+			}
+		}
+		switch kinds[i] {
+		case vals.TypeNumber:
+			text := str
+			if n, ok := r.units.parse(i, str); ok {
+				text = n
+			}
+			if num, err := strconv.ParseFloat(text, 64); err == nil {
 				vs[i] = num
+				continue
+			}
+		case vals.TypeInteger:
+			text := str
+			if n, ok := r.units.parse(i, str); ok {
+				text = n
 			}
-		case "integer":
-			if num, err := vals.ParseInteger([]byte(str)); err == nil {
+			if num, err := strconv.ParseInt(text, 10, 64); err == nil {
 				vs[i] = num
+				continue
 			}
-		case "boolean":
-			if b, err := vals.ParseBoolean([]byte(str)); err == nil {
+		case vals.TypeBoolean:
+			if b, ok := r.bools.parse(str); ok {
 				vs[i] = b
+				continue
 			}
-		case "object":
+		case vals.TypeObject:
 			v := map[string]interface{}{}
 			if err := json.Unmarshal([]byte(str), &v); err == nil {
 				vs[i] = v
+				continue
 			}
-		case "array":
+		case vals.TypeArray:
 			v := []interface{}{}
 			if err := json.Unmarshal([]byte(str), &v); err == nil {
 				vs[i] = v
+				continue
 			}
-		case "null":
-			vs[i] = nil
+		case vals.TypeNull:
+			continue
 		}
+		vs[i] = str
 	}
 
 	return vs, nil
 }
 
+// columnKinds gives the types to decode a record of n fields with. Schema
+// type names are resolved once & cached so decoding a field doesn't compare
+// type strings
+func (r *fieldDecoder) columnKinds(n int) []vals.Type {
+	if r.kinds == nil {
+		r.kinds = make([]vals.Type, len(r.types))
+		for i, t := range r.types {
+			r.kinds[i] = vals.TypeFromString(t)
+		}
+	}
+	if len(r.kinds) >= n {
+		return r.kinds
+	}
+	// TODO - fix. for now is types fails to parse we just assume all types
+	// are strings
+	if len(r.stringKinds) < n {
+		r.stringKinds = make([]vals.Type, n)
+		for i := range r.stringKinds {
+			r.stringKinds[i] = vals.TypeString
+		}
+	}
+	return r.stringKinds
+}
+
+// csvObjectRow reads records into objects for schemas with object rows, like
+// {"type":"array","items":{"type":"object","properties":{...}}}. Fields are
+// mapped to properties by header row title, or in sorted property order for
+// files without a header row
+type csvObjectRow struct {
+	props map[string]interface{}
+	// required is the row schema's "required" list
+	required map[string]bool
+	// enforce is the RequiredColumns option
+	enforce     bool
+	dateLayouts map[string]string
+	// keys are the property names of fields in record order, nil until the
+	// header row is read
+	keys []string
+}
+
+// newCSVObjectRow gives an object row reader for structures with object
+// rows, nil otherwise
+func newCSVObjectRow(st *dataset.Structure, opts *dataset.CSVOptions) *csvObjectRow {
+	items, _ := st.Schema["items"].(map[string]interface{})
+	if items == nil || items["type"] != "object" {
+		return nil
+	}
+	props, ok := items["properties"].(map[string]interface{})
+	if !ok || len(props) == 0 {
+		return nil
+	}
+
+	o := &csvObjectRow{props: props, required: map[string]bool{}}
+	switch req := items["required"].(type) {
+	case []interface{}:
+		for _, key := range req {
+			if s, ok := key.(string); ok {
+				o.required[s] = true
+			}
+		}
+	case []string:
+		for _, key := range req {
+			o.required[key] = true
+		}
+	}
+	if opts != nil {
+		o.enforce = opts.RequiredColumns
+		o.dateLayouts = opts.DateLayouts
+	}
+	return o
+}
+
+// setKeys sets the property names of record fields. When required columns
+// are enforced every required property must have a column
+func (o *csvObjectRow) setKeys(keys []string) error {
+	if o.enforce {
+		has := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			has[key] = true
+		}
+		var missing []string
+		for key := range o.required {
+			if !has[key] {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return newKindError(ErrMissingRequired, fmt.Sprintf("required columns missing from the header row: %s", strings.Join(missing, ", ")))
+		}
+	}
+	o.keys = keys
+	return nil
+}
+
+// types gives the schema types of fields, "string" for fields that aren't
+// properties of the row schema
+func (o *csvObjectRow) types() []string {
+	types := make([]string, len(o.keys))
+	for i, key := range o.keys {
+		field, _ := o.props[key].(map[string]interface{})
+		types[i] = columnType(field)
+	}
+	return types
+}
+
+// object maps the fields & decoded values of a record to an object. Fields
+// past the known columns are kept under abstract column names
+func (o *csvObjectRow) object(fields []string, vs []interface{}) (map[string]interface{}, error) {
+	if o.keys == nil {
+		return nil, fmt.Errorf("object row columns are unknown, the header row wasn't read")
+	}
+	obj := make(map[string]interface{}, len(vs))
+	for i, key := range o.keys {
+		if o.enforce && (i >= len(fields) || fields[i] == "") {
+			if o.required[key] {
+				return nil, newKindError(ErrMissingRequired, fmt.Sprintf("missing value for required column '%s'", key))
+			}
+			obj[key] = nil
+			continue
+		}
+		if i < len(vs) {
+			obj[key] = vs[i]
+		}
+	}
+	for i := len(o.keys); i < len(vs); i++ {
+		obj[dataset.AbstractColumnName(i)] = vs[i]
+	}
+	return obj, nil
+}
+
 // HasHeaderRow checks Structure for the presence of the HeaderRow flag
 func HasHeaderRow(st *dataset.Structure) bool {
-	if st.DataFormat() == dataset.CSVDataFormat && st.FormatConfig != nil {
-		if csvOpt, err := dataset.NewCSVOptions(st.FormatConfig); err == nil {
-			return csvOpt.HeaderRow
+	if st.FormatConfig == nil {
+		return false
+	}
+	switch st.DataFormat() {
+	case dataset.CSVDataFormat:
+		return partialCSVOptions(st.FormatConfig).HeaderRow
+	case dataset.TSVDataFormat:
+		if opts, err := dataset.NewTSVOptions(st.FormatConfig); err == nil {
+			return opts.HeaderRow
 		}
 	}
 	return false
 }
 
+// partialCSVOptions parses CSV options, keeping any settings that are valid
+// when others in the config aren't
+func partialCSVOptions(cfg map[string]interface{}) *dataset.CSVOptions {
+	opts, err := dataset.NewCSVOptions(cfg)
+	if err == nil {
+		return opts
+	}
+	log.Debugw("invalid csv format config, using valid settings only", dslog.F("error", err))
+
+	opts = &dataset.CSVOptions{}
+	for key, val := range cfg {
+		o, err := dataset.NewCSVOptions(map[string]interface{}{key: val})
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "headerRow":
+			opts.HeaderRow = o.HeaderRow
+		case "lazyQuotes":
+			opts.LazyQuotes = o.LazyQuotes
+		case "separator":
+			opts.Separator = o.Separator
+		case "variadicFields":
+			opts.VariadicFields = o.VariadicFields
+		case "dateLayouts":
+			opts.DateLayouts = o.DateLayouts
+		case "skipFooterRows":
+			opts.SkipFooterRows = o.SkipFooterRows
+		case "encoding":
+			opts.Encoding = o.Encoding
+		case "encodingFallback":
+			opts.EncodingFallback = o.EncodingFallback
+		case "stripBOM":
+			opts.StripBOM = o.StripBOM
+		case "emitBOM":
+			opts.EmitBOM = o.EmitBOM
+		case "columns":
+			opts.Columns = o.Columns
+		case "significantDigits":
+			opts.SignificantDigits = o.SignificantDigits
+		case "decimalPlaces":
+			opts.DecimalPlaces = o.DecimalPlaces
+		case "scientificThreshold":
+			opts.ScientificThreshold = o.ScientificThreshold
+		case "parseUnits":
+			opts.ParseUnits = o.ParseUnits
+		case "numberLocale":
+			opts.NumberLocale = o.NumberLocale
+		case "quote":
+			opts.Quote = o.Quote
+		case "lineTerminator":
+			opts.LineTerminator = o.LineTerminator
+		case "requiredColumns":
+			opts.RequiredColumns = o.RequiredColumns
+		}
+	}
+	// boolean tokens are only valid as a pair
+	if o, err := dataset.NewCSVOptions(map[string]interface{}{"trueTokens": cfg["trueTokens"], "falseTokens": cfg["falseTokens"]}); err == nil {
+		opts.BooleanTokens = o.BooleanTokens
+	}
+	return opts
+}
+
+// CSVHeaderTitles gives the header row titles for a structure, read from the
+// column titles of it's schema. Columns without a title are given an abstract
+// column name
+func CSVHeaderTitles(st *dataset.Structure) ([]string, error) {
+	titles, _, err := terribleHackToGetHeaderRowAndTypes(st)
+	if err != nil {
+		return nil, newKindError(ErrBadSchema, "schema doesn't define column titles")
+	}
+	for i, t := range titles {
+		if t == "" {
+			titles[i] = dataset.AbstractColumnName(i)
+		}
+	}
+	return titles, nil
+}
+
 // CSVWriter implements the RowWriter interface for
 // CSV-formatted data
 type CSVWriter struct {
 	rowsWritten int
-	w           *csv.Writer
+	w           *csvRecordWriter
+	out         *countingWriter
 	st          *dataset.Structure
 	types       []string
+	titles      []string
+	layouts     []string
+	floats      *floatFormatter
+	bools       *booleanTokens
+	// header is a requested header row that hasn't been written yet
+	header bool
+	// enc encodes output in the configured charset, nil for utf-8
+	enc io.Closer
+	// columns are the body columns written when the format config sets
+	// columns, in written order
+	columns []csvColumn
+}
+
+// csvColumn is a body column written by a CSVWriter. index is the column's
+// position in array entries, -1 if the schema has no column titled key
+type csvColumn struct {
+	key   string
+	index int
 }
 
 // NewCSVWriter creates a Writer from a structure and write destination
 func NewCSVWriter(st *dataset.Structure, w io.Writer) *CSVWriter {
 	// TODO - capture error
-	titles, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+	schemaTitles, types, _ := terribleHackToGetHeaderRowAndTypes(st)
 
-	writer := csv.NewWriter(w)
-	opts, err := dataset.NewCSVOptions(st.FormatConfig)
-	if opts != nil && err == nil {
-		if opts.Separator != rune(0) {
-			writer.Comma = opts.Separator
-		}
+	out := &countingWriter{w: w}
+	opts := partialCSVOptions(st.FormatConfig)
+	enc, err := csvEncoder(out, opts)
+	if err != nil {
+		log.Debugw("invalid csv encoding, writing utf-8", dslog.F("error", err))
+		enc = out
 	}
+	writer := newCSVRecordWriter(enc, opts)
 
 	wr := &CSVWriter{
-		st:    st,
-		w:     writer,
-		types: types,
+		st:      st,
+		w:       writer,
+		out:     out,
+		types:   types,
+		layouts: columnDateLayouts(schemaTitles, opts.DateLayouts),
+		floats:  newFloatFormatter(st, opts.FloatFormat),
+		bools:   newBooleanTokens(opts.BooleanTokens),
+	}
+	if cw, ok := enc.(*charset.Writer); ok {
+		wr.enc = cw
 	}
 
-	if opts != nil {
+	if len(opts.Columns) > 0 {
+		titles, sources := wr.setColumns(opts.Columns, schemaTitles)
+		wr.layouts = columnDateLayouts(sources, opts.DateLayouts)
+		wr.floats = newTitledFloatFormatter(sources, opts.FloatFormat)
 		if opts.HeaderRow {
-			writer.Write(titles)
+			wr.titles = titles
+			writer.Write(titles, nil)
+		}
+	} else if opts.HeaderRow {
+		titles, err := CSVHeaderTitles(st)
+		if err != nil {
+			// without schema titles the header is named from the first entry
+			log.Debugw("deferring csv header row", dslog.F("error", err))
+			wr.header = true
+		} else {
+			wr.titles = titles
+			writer.Write(titles, nil)
 		}
 	}
 
 	return wr
 }
 
+// setColumns resolves configured columns against schema titles, giving the
+// header titles of written columns & the titles of the columns they're read
+// from, which select date layouts & float formats
+func (w *CSVWriter) setColumns(cols []dataset.CSVColumn, schemaTitles []string) (titles, sources []string) {
+	w.columns = make([]csvColumn, len(cols))
+	titles = make([]string, len(cols))
+	sources = make([]string, len(cols))
+	for i, c := range cols {
+		col := csvColumn{key: c.Key, index: c.Index}
+		if c.Key != "" {
+			col.index = -1
+			for j, title := range schemaTitles {
+				if title == c.Key {
+					col.index = j
+					break
+				}
+			}
+			sources[i] = c.Key
+		} else if c.Index < len(schemaTitles) && schemaTitles[c.Index] != "" {
+			sources[i] = schemaTitles[c.Index]
+		} else {
+			sources[i] = dataset.AbstractColumnName(c.Index)
+		}
+
+		titles[i] = c.Title
+		if titles[i] == "" {
+			titles[i] = sources[i]
+		}
+		w.columns[i] = col
+	}
+	return titles, sources
+}
+
+// project picks the configured columns from an entry value, in written
+// order. Array entries are read by position & objects by key, missing
+// values are written as empty fields
+func (w *CSVWriter) project(v interface{}) ([]interface{}, error) {
+	row := make([]interface{}, len(w.columns))
+	switch t := v.(type) {
+	case []interface{}:
+		for i, c := range w.columns {
+			if c.index < 0 {
+				return nil, fmt.Errorf("column %d: schema has no column titled '%s'", i, c.key)
+			}
+			if c.index < len(t) {
+				row[i] = t[c.index]
+			}
+		}
+	case map[string]interface{}:
+		for i, c := range w.columns {
+			if c.key == "" {
+				return nil, fmt.Errorf("column %d: index columns can only be read from array entries", i)
+			}
+			row[i] = t[c.key]
+		}
+	default:
+		return nil, fmt.Errorf("expected array or object value to write csv row. got: %v", v)
+	}
+	return row, nil
+}
+
+// csvEncoder wraps w to encode output in the charset set by opts, after a
+// byte order mark if opts sets EmitBOM
+func csvEncoder(w io.Writer, opts *dataset.CSVOptions) (io.Writer, error) {
+	fb, err := charset.ParseFallback(opts.EncodingFallback)
+	if err != nil {
+		return nil, err
+	}
+	if opts.EmitBOM {
+		if w, err = charset.NewBOMWriter(w, opts.Encoding); err != nil {
+			return nil, err
+		}
+	}
+	return charset.NewWriter(w, opts.Encoding, fb)
+}
+
+// Titles gives the header row titles written by this writer, nil if no
+// header row has been written
+func (w *CSVWriter) Titles() []string {
+	return w.titles
+}
+
 // TODO - holy shit dis so bad. fix
 func terribleHackToGetHeaderRowAndTypes(st *dataset.Structure) ([]string, []string, error) {
 	sch := st.Schema
@@ -196,18 +844,7 @@ func terribleHackToGetHeaderRowAndTypes(st *dataset.Structure) ([]string, []stri
 					if title, ok := field["title"].(string); ok {
 						titles[i] = title
 					}
-
-					if ts, ok := field["type"].(string); ok {
-						types[i] = ts
-					} else if ta, ok := field["type"].([]interface{}); ok && len(ta) > 0 {
-						if st, ok := ta[0].(string); ok {
-							types[i] = st
-						} else {
-							types[i] = "string"
-						}
-					} else {
-						types[i] = "string"
-					}
+					types[i] = columnType(field)
 				}
 			}
 			return titles, types, nil
@@ -217,6 +854,19 @@ func terribleHackToGetHeaderRowAndTypes(st *dataset.Structure) ([]string, []stri
 	return nil, nil, fmt.Errorf("nope")
 }
 
+// columnType gives the type of a column schema, the first of a list of types
+// & "string" for columns without one
+func columnType(field map[string]interface{}) string {
+	if ts, ok := field["type"].(string); ok {
+		return ts
+	} else if ta, ok := field["type"].([]interface{}); ok && len(ta) > 0 {
+		if st, ok := ta[0].(string); ok {
+			return st
+		}
+	}
+	return "string"
+}
+
 // Structure gives this writer's structure
 func (w *CSVWriter) Structure() *dataset.Structure {
 	return w.st
@@ -224,19 +874,70 @@ func (w *CSVWriter) Structure() *dataset.Structure {
 
 // WriteEntry writes one CSV record to the writer
 func (w *CSVWriter) WriteEntry(ent Entry) error {
+	if w.columns != nil {
+		row, err := w.project(ent.Value)
+		if err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("error writing entry %d: %w", ent.Index, err)
+		}
+		ent.Value = row
+	}
 	if arr, ok := ent.Value.([]interface{}); ok {
-		strs, err := encode(arr)
+		strs, err := encode(arr, w.layouts, w.floats, w.bools)
 		if err != nil {
 			log.Debug(err.Error())
-			return fmt.Errorf("error encoding entry: %s", err.Error())
+			return fmt.Errorf("error encoding entry: %w", err)
+		}
+		if w.header {
+			w.header = false
+			w.titles = make([]string, len(strs))
+			for i := range w.titles {
+				w.titles[i] = dataset.AbstractColumnName(i)
+			}
+			if err := w.w.Write(w.titles, nil); err != nil {
+				return err
+			}
+		}
+		var numeric []bool
+		if w.w.quote == dataset.CSVQuoteNonNumeric {
+			numeric = numericFields(arr)
 		}
-		return w.w.Write(strs)
+		if err := w.w.Write(strs, numeric); err != nil {
+			return err
+		}
+		w.rowsWritten++
+		return nil
 	}
 	return fmt.Errorf("expected array value to write csv row. got: %v", ent)
 }
 
-// encode uses specified types from structure's schema to go values to strings
-func encode(vs []interface{}) ([]string, error) {
+// EntriesWritten gives the number of entries written
+func (w *CSVWriter) EntriesWritten() int {
+	return w.rowsWritten
+}
+
+// BytesProcessed gives the number of bytes flushed to the destination
+func (w *CSVWriter) BytesProcessed() int64 {
+	return w.out.n
+}
+
+// columnDateLayouts gives the date layout for each column title, nil if no
+// columns have a layout
+func columnDateLayouts(titles []string, dateLayouts map[string]string) []string {
+	if len(dateLayouts) == 0 {
+		return nil
+	}
+	layouts := make([]string, len(titles))
+	for i, title := range titles {
+		layouts[i] = dateLayouts[title]
+	}
+	return layouts
+}
+
+// encode uses specified types from structure's schema to go values to strings.
+// time values are formatted with the column's date layout, defaulting to RFC3339,
+// floats with the column's float format & booleans with bools
+func encode(vs []interface{}, layouts []string, floats *floatFormatter, bools *booleanTokens) ([]string, error) {
 	strings := make([]string, len(vs))
 
 	for i, v := range vs {
@@ -249,7 +950,7 @@ func encode(vs []interface{}) ([]string, error) {
 		case int64:
 			strings[i] = strconv.Itoa(int(t))
 		case float64:
-			strings[i] = strconv.FormatFloat(t, 'f', -1, 64)
+			strings[i] = floats.column(i, t)
 		case []interface{}:
 			if data, err := json.Marshal(t); err == nil {
 				strings[i] = string(data)
@@ -259,11 +960,13 @@ func encode(vs []interface{}) ([]string, error) {
 				strings[i] = string(data)
 			}
 		case bool:
-			if t {
-				strings[i] = "true"
-			} else {
-				strings[i] = "false"
+			strings[i] = bools.format(t)
+		case time.Time:
+			layout := time.RFC3339
+			if i < len(layouts) && layouts[i] != "" {
+				layout = layouts[i]
 			}
+			strings[i] = t.Format(layout)
 		case nil:
 			strings[i] = ""
 		}
@@ -275,6 +978,14 @@ func encode(vs []interface{}) ([]string, error) {
 // Close finalizes the writer, indicating no more records
 // will be written
 func (w *CSVWriter) Close() error {
-	w.w.Flush()
-	return nil
+	err := w.w.Flush()
+	if w.enc != nil {
+		if cerr := w.enc.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if cerr := w.out.Close(); err == nil {
+		err = cerr
+	}
+	return err
 }
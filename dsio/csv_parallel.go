@@ -0,0 +1,336 @@
+package dsio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dslog"
+)
+
+// csvBatchSize is the number of records a ParallelCSVReader worker decodes at
+// once. batching keeps channel overhead small next to the cost of decoding
+const csvBatchSize = 256
+
+// ParallelCSVReader reads csv bodies, casting fields to their schema types on
+// a pool of worker goroutines. One goroutine reads records from the source,
+// workers decode them in batches, and ReadEntry returns entries in body
+// order. Decoding in parallel pays off for large bodies with typed columns,
+// see BenchmarkParallelCSVReader. Reading starts when the reader is created
+// & runs ahead of ReadEntry by a bounded number of batches.
+//
+// Number units found by the ParseUnits option are shared across records, so
+// readers with ParseUnits set decode on the calling goroutine. Parallel
+// readers can't be checkpointed
+type ParallelCSVReader struct {
+	r *CSVReader
+	// sequential is set when records can't be decoded in parallel
+	sequential bool
+
+	results chan *csvBatch
+	slots   chan struct{}
+	done    chan struct{}
+	// readDone is closed when the reading goroutine exits
+	readDone  chan struct{}
+	closeOnce sync.Once
+
+	// pending holds batches decoded ahead of the one being returned
+	pending map[int]*csvBatch
+	seq     int
+	batch   *csvBatch
+	pos     int
+
+	entriesRead int
+	bytes       int64
+}
+
+// csvBatch is a run of records & the values decoded from them
+type csvBatch struct {
+	seq    int
+	fields [][]string
+	// ends is the position in the body after each record
+	ends []int64
+	vals []interface{}
+	// errs holds the error reading or decoding each record, nil for records
+	// that decoded
+	errs []error
+	// err is the error that ended the batch, io.EOF at the end of the body.
+	// batches without an error are full
+	err error
+	// end is the number of bytes the source read at the end of the body,
+	// which includes footer rows
+	end int64
+}
+
+var _ EntryReader = (*ParallelCSVReader)(nil)
+
+// NewParallelCSVReader creates a reader that decodes records on workers
+// goroutines. workers less than one uses the number of CPUs
+func NewParallelCSVReader(st *dataset.Structure, r io.Reader, workers int) *ParallelCSVReader {
+	pr := &ParallelCSVReader{r: NewCSVReader(st, r)}
+	if pr.r.units != nil {
+		pr.sequential = true
+		return pr
+	}
+
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	var (
+		batches = make(chan *csvBatch, workers)
+		wg      sync.WaitGroup
+	)
+	pr.results = make(chan *csvBatch, workers)
+	pr.slots = make(chan struct{}, workers*4)
+	pr.done = make(chan struct{})
+	pr.readDone = make(chan struct{})
+	pr.pending = map[int]*csvBatch{}
+
+	go pr.readBatches(batches)
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			pr.decodeBatches(batches)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(pr.results)
+	}()
+	return pr
+}
+
+// readBatches reads records into batches until the end of the body or an
+// error reading the source. Malformed records are passed on with their
+// error, as CSVReader reads past them. It's the only goroutine using the
+// underlying reader's source
+func (pr *ParallelCSVReader) readBatches(batches chan<- *csvBatch) {
+	defer close(pr.readDone)
+	defer close(batches)
+	for seq := 0; ; seq++ {
+		select {
+		case pr.slots <- struct{}{}:
+		case <-pr.done:
+			return
+		}
+
+		b := &csvBatch{
+			seq:    seq,
+			fields: make([][]string, 0, csvBatchSize),
+			ends:   make([]int64, 0, csvBatchSize),
+			errs:   make([]error, 0, csvBatchSize),
+		}
+		for len(b.fields) < csvBatchSize {
+			fields, err := pr.r.next()
+			if err != nil && !isCSVRecordError(err) {
+				b.err = err
+				if err == io.EOF {
+					b.end = pr.r.BytesProcessed()
+				}
+				break
+			}
+			if err != nil {
+				fields = nil
+			} else if pr.r.r.ReuseRecord {
+				fields = append([]string(nil), fields...)
+			}
+			b.fields = append(b.fields, fields)
+			b.ends = append(b.ends, pr.r.recordEnd())
+			b.errs = append(b.errs, err)
+		}
+
+		// decoders write to the batch once it's sent
+		err := b.err
+		select {
+		case batches <- b:
+		case <-pr.done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// isCSVRecordError reports whether err is a malformed record error, which
+// the csv reader continues reading after
+func isCSVRecordError(err error) bool {
+	var perr *csv.ParseError
+	return errors.As(err, &perr)
+}
+
+// decodeBatches decodes batches until batches is closed. Records that fail to
+// decode keep their error in place of a value
+func (pr *ParallelCSVReader) decodeBatches(batches <-chan *csvBatch) {
+	var (
+		fd     fieldDecoder
+		copied bool
+	)
+	for b := range batches {
+		// the header row sets column types before the first batch is sent
+		if !copied {
+			fd = pr.r.fieldDecoder
+			copied = true
+		}
+		b.vals = make([]interface{}, len(b.fields))
+		for i, fields := range b.fields {
+			if b.errs[i] != nil {
+				continue
+			}
+			if b.vals[i], b.errs[i] = pr.r.decodeRecord(&fd, fields); b.errs[i] != nil {
+				log.Debugw(b.errs[i].Error(), dslog.F("batch", b.seq), dslog.F("record", i))
+			}
+		}
+		b.fields = nil
+
+		select {
+		case pr.results <- b:
+		case <-pr.done:
+			return
+		}
+	}
+}
+
+// Structure gets this reader's structure
+func (pr *ParallelCSVReader) Structure() *dataset.Structure {
+	if pr.sequential {
+		return pr.r.Structure()
+	}
+	return pr.r.st
+}
+
+// ReadEntry reads one entry from the reader
+func (pr *ParallelCSVReader) ReadEntry() (Entry, error) {
+	if pr.sequential {
+		ent, err := pr.r.ReadEntry()
+		pr.entriesRead, pr.bytes = pr.r.EntriesRead(), pr.r.BytesProcessed()
+		return ent, err
+	}
+
+	for pr.batch == nil || pr.pos == len(pr.batch.vals) {
+		if pr.batch != nil && pr.batch.err != nil {
+			if pr.batch.err == io.EOF {
+				pr.bytes = pr.batch.end
+			}
+			return Entry{}, parseError("csv", pr.entriesRead, pr.batch.err)
+		}
+		b, err := pr.nextBatch()
+		if err != nil {
+			return Entry{}, err
+		}
+		pr.batch, pr.pos = b, 0
+	}
+
+	i := pr.pos
+	pr.pos++
+	pr.bytes = pr.batch.ends[i]
+	if err := pr.batch.errs[i]; err != nil {
+		return Entry{}, parseError("csv", pr.entriesRead, err)
+	}
+	value := pr.batch.vals[i]
+	pr.batch.vals[i] = nil
+	pr.entriesRead++
+	return Entry{Value: value}, nil
+}
+
+// nextBatch waits for the next batch in body order
+func (pr *ParallelCSVReader) nextBatch() (*csvBatch, error) {
+	for {
+		if b, ok := pr.pending[pr.seq]; ok {
+			delete(pr.pending, pr.seq)
+			pr.seq++
+			<-pr.slots
+			return b, nil
+		}
+		b, ok := <-pr.results
+		if !ok {
+			return nil, fmt.Errorf("reader is closed")
+		}
+		pr.pending[b.seq] = b
+	}
+}
+
+// Footer gives the rows excluded from the body by the SkipFooterRows option.
+// Footer is nil until ReadEntry has returned io.EOF
+func (pr *ParallelCSVReader) Footer() [][]string {
+	if !pr.sequential && (pr.batch == nil || pr.batch.err != io.EOF) {
+		return nil
+	}
+	return pr.r.Footer()
+}
+
+// ReadEntries reads up to n entries, taking runs of entries from decoded
+// batches at once, see BatchReader
+func (pr *ParallelCSVReader) ReadEntries(n int) ([]Entry, error) {
+	if pr.sequential {
+		ents, err := pr.r.ReadEntries(n)
+		pr.entriesRead, pr.bytes = pr.r.EntriesRead(), pr.r.BytesProcessed()
+		return ents, err
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("batch size must be greater than zero, got: %d", n)
+	}
+
+	ents := make([]Entry, 0, batchPrealloc(n))
+	for len(ents) < n {
+		if b := pr.batch; b != nil && pr.pos < len(b.vals) && b.errs[pr.pos] == nil {
+			// take the run of decoded values up to the next record error
+			end := pr.pos + n - len(ents)
+			if end > len(b.vals) {
+				end = len(b.vals)
+			}
+			i := pr.pos
+			for ; i < end && b.errs[i] == nil; i++ {
+				ents = append(ents, Entry{Value: b.vals[i]})
+				b.vals[i] = nil
+			}
+			pr.entriesRead += i - pr.pos
+			pr.bytes = b.ends[i-1]
+			pr.pos = i
+			continue
+		}
+
+		// moving to the next batch & record errors are left to ReadEntry
+		ent, err := pr.ReadEntry()
+		if err == io.EOF {
+			if len(ents) == 0 {
+				return nil, io.EOF
+			}
+			return ents, nil
+		} else if err != nil {
+			return ents, err
+		}
+		ents = append(ents, ent)
+	}
+	return ents, nil
+}
+
+// EntriesRead gives the number of entries read
+func (pr *ParallelCSVReader) EntriesRead() int {
+	return pr.entriesRead
+}
+
+// BytesProcessed gives the position in the body after the last entry read,
+// or the size of the body once ReadEntry has returned io.EOF. Records read
+// ahead of ReadEntry aren't counted
+func (pr *ParallelCSVReader) BytesProcessed() int64 {
+	return pr.bytes
+}
+
+// Close stops reading & decoding and closes the source. The source is closed
+// before waiting for the reading goroutine to exit, so a read blocked on a
+// source like a pipe returns
+func (pr *ParallelCSVReader) Close() error {
+	if pr.sequential {
+		return pr.r.Close()
+	}
+	pr.closeOnce.Do(func() { close(pr.done) })
+	err := pr.r.Close()
+	<-pr.readDone
+	return err
+}
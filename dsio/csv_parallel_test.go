@@ -0,0 +1,238 @@
+package dsio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+var parallelCSVSchema = map[string]interface{}{
+	"type": "array",
+	"items": map[string]interface{}{
+		"type": "array",
+		"items": []interface{}{
+			map[string]interface{}{"title": "a", "type": "integer"},
+			map[string]interface{}{"title": "b", "type": "number"},
+			map[string]interface{}{"title": "c", "type": "boolean"},
+			map[string]interface{}{"title": "d", "type": "string"},
+		},
+	},
+}
+
+// parallelCSVBody writes rows spanning several batches, with a short record
+// at badRow when badRow is positive
+func parallelCSVBody(header bool, rows, badRow int) string {
+	buf := &strings.Builder{}
+	if header {
+		buf.WriteString("a,b,c,d\n")
+	}
+	for i := 0; i < rows; i++ {
+		if i == badRow && badRow > 0 {
+			buf.WriteString("1,2\n")
+			continue
+		}
+		fmt.Fprintf(buf, "%d,%d.5,%t,row %d\n", i, i, i%2 == 0, i)
+	}
+	return buf.String()
+}
+
+func TestParallelCSVReader(t *testing.T) {
+	objectSchema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"a": map[string]interface{}{"type": "integer"},
+				"b": map[string]interface{}{"type": "number"},
+				"c": map[string]interface{}{"type": "boolean"},
+				"d": map[string]interface{}{"type": "string"},
+			},
+			"required": []interface{}{"a", "d"},
+		},
+	}
+
+	cases := []struct {
+		description string
+		config      map[string]interface{}
+		schema      map[string]interface{}
+		data        string
+		workers     int
+	}{
+		{"empty body", nil, parallelCSVSchema, "", 4},
+		{"one record", nil, parallelCSVSchema, parallelCSVBody(false, 1, 0), 4},
+		{"many batches", nil, parallelCSVSchema, parallelCSVBody(false, 2000, 0), 4},
+		{"single worker", nil, parallelCSVSchema, parallelCSVBody(false, 600, 0), 1},
+		{"header row", map[string]interface{}{"headerRow": true}, parallelCSVSchema, parallelCSVBody(true, 1000, 0), 3},
+		{"bad record", nil, parallelCSVSchema, parallelCSVBody(false, 1000, 700), 4},
+		{"bad records", nil, parallelCSVSchema, parallelCSVBody(false, 1000, 3) + "x,1.5,true,a\n1,2\"x,true,a\n" + parallelCSVBody(false, 300, 20), 4},
+		{"footer rows", map[string]interface{}{"skipFooterRows": 2}, parallelCSVSchema, parallelCSVBody(false, 600, 0) + "Total,1\nend,2\n", 4},
+		{"object rows", map[string]interface{}{"headerRow": true, "requiredColumns": true}, objectSchema, parallelCSVBody(true, 800, 0), 4},
+		{"missing required value", map[string]interface{}{"headerRow": true, "requiredColumns": true}, objectSchema, parallelCSVBody(true, 800, 0) + ",1,true,\n", 4},
+		{"units", map[string]interface{}{"parseUnits": true}, parallelCSVSchema, parallelCSVBody(false, 300, 0), 4},
+	}
+
+	// read reads to the end of the body, continuing past bad records
+	read := func(r EntryReader) (vals []interface{}, errs []string) {
+		for {
+			ent, err := r.ReadEntry()
+			if err == io.EOF {
+				return vals, errs
+			} else if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			vals = append(vals, ent.Value)
+		}
+	}
+	// readBatches is read, reading batches of entries
+	readBatches := func(r EntryReader) (vals []interface{}, errs []string) {
+		for {
+			ents, err := ReadEntries(r, 100)
+			for _, ent := range ents {
+				vals = append(vals, ent.Value)
+			}
+			if err == io.EOF {
+				return vals, errs
+			} else if err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			st := &dataset.Structure{Format: "csv", FormatConfig: c.config, Schema: c.schema}
+			sr := NewCSVReader(st, strings.NewReader(c.data))
+			expect, expectErrs := read(sr)
+
+			pr := NewParallelCSVReader(st, strings.NewReader(c.data), c.workers)
+			got, errs := read(pr)
+			if !reflect.DeepEqual(expectErrs, errs) {
+				t.Fatalf("errors mismatch. expected: %v, got: %v", expectErrs, errs)
+			}
+			if !reflect.DeepEqual(expect, got) {
+				t.Errorf("entries mismatch. expected %d entries, got %d", len(expect), len(got))
+			}
+			if sr.EntriesRead() != pr.EntriesRead() {
+				t.Errorf("entries read mismatch. expected: %d, got: %d", sr.EntriesRead(), pr.EntriesRead())
+			}
+			if sr.BytesProcessed() != pr.BytesProcessed() {
+				t.Errorf("bytes processed mismatch. expected: %d, got: %d", sr.BytesProcessed(), pr.BytesProcessed())
+			}
+			if !reflect.DeepEqual(sr.Footer(), pr.Footer()) {
+				t.Errorf("footer mismatch. expected: %v, got: %v", sr.Footer(), pr.Footer())
+			}
+			if err := pr.Close(); err != nil {
+				t.Error(err)
+			}
+
+			for _, r := range []EntryReader{
+				NewCSVReader(st, strings.NewReader(c.data)),
+				NewParallelCSVReader(st, strings.NewReader(c.data), c.workers),
+			} {
+				got, errs := readBatches(r)
+				if !reflect.DeepEqual(expectErrs, errs) {
+					t.Fatalf("%T batch errors mismatch. expected: %v, got: %v", r, expectErrs, errs)
+				}
+				if !reflect.DeepEqual(expect, got) {
+					t.Errorf("%T batch entries mismatch. expected %d entries, got %d", r, len(expect), len(got))
+				}
+				if r.(ByteCounter).BytesProcessed() != sr.BytesProcessed() {
+					t.Errorf("%T batch bytes processed mismatch. expected: %d, got: %d", r, sr.BytesProcessed(), r.(ByteCounter).BytesProcessed())
+				}
+				r.Close()
+			}
+		})
+	}
+}
+
+func TestParallelCSVReaderClose(t *testing.T) {
+	st := &dataset.Structure{Format: "csv", Schema: parallelCSVSchema}
+	r := NewParallelCSVReader(st, strings.NewReader(parallelCSVBody(false, 5000, 0)), 2)
+	ent, err := r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent.Value.([]interface{})[0] != int64(0) {
+		t.Errorf("expected first entry, got: %v", ent.Value)
+	}
+	// closing part way through a body stops the reading & decoding goroutines
+	if err := r.Close(); err != nil {
+		t.Error(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParallelCSVReaderClosePipe(t *testing.T) {
+	st := &dataset.Structure{Format: "csv", Schema: parallelCSVSchema}
+	pr, pw := io.Pipe()
+	r := NewParallelCSVReader(st, pr, 2)
+	// writes return once they're read, the pipe is left open so the reading
+	// goroutine blocks waiting for the rest of the batch
+	if _, err := pw.Write([]byte(parallelCSVBody(false, 10, 0))); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() { done <- r.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out closing a reader with a blocked source")
+	}
+}
+
+func TestNewEntryReaderDecodeWorkers(t *testing.T) {
+	st := &dataset.Structure{Format: "csv", Schema: parallelCSVSchema}
+	r, err := NewEntryReader(st, strings.NewReader(parallelCSVBody(false, 10, 0)), func(cfg *ReaderConfig) {
+		cfg.DecodeWorkers = 2
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.(*ParallelCSVReader); !ok {
+		t.Errorf("expected a *ParallelCSVReader, got: %T", r)
+	}
+	r.Close()
+}
+
+func BenchmarkParallelCSVReader(b *testing.B) {
+	st := &dataset.Structure{Format: "csv", Schema: parallelCSVSchema}
+	data := []byte(parallelCSVBody(false, 100000, 0))
+
+	b.Run("sequential", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for n := 0; n < b.N; n++ {
+			r := NewCSVReader(st, bytes.NewReader(data))
+			for {
+				if _, err := r.ReadEntry(); err != nil {
+					break
+				}
+			}
+		}
+	})
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("%d_workers", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for n := 0; n < b.N; n++ {
+				r := NewParallelCSVReader(st, bytes.NewReader(data), workers)
+				for {
+					if _, err := r.ReadEntry(); err != nil {
+						break
+					}
+				}
+				r.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package dsio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/qri-io/dataset"
+)
+
+// csvRecordWriter writes csv records with a quoting policy, which
+// encoding/csv doesn't offer. Minimal quoting matches encoding/csv output.
+// Write errors are sticky, a failed write fails all later writes
+type csvRecordWriter struct {
+	w     *bufio.Writer
+	comma rune
+	crlf  bool
+	quote string
+	err   error
+}
+
+func newCSVRecordWriter(w io.Writer, opts *dataset.CSVOptions) *csvRecordWriter {
+	rw := &csvRecordWriter{
+		w:     bufio.NewWriter(w),
+		comma: ',',
+		crlf:  opts.LineTerminator == "\r\n",
+		quote: opts.Quote,
+	}
+	if opts.Separator != rune(0) {
+		rw.comma = opts.Separator
+	}
+	return rw
+}
+
+// Write writes one record. numeric flags fields written from numbers, which
+// aren't quoted by the non-numeric policy. a nil numeric quotes every field
+// under that policy
+func (w *csvRecordWriter) Write(record []string, numeric []bool) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.quote == dataset.CSVQuoteNone {
+		for i, field := range record {
+			if w.fieldNeedsQuotes(field) {
+				w.err = fmt.Errorf("field %d: %q can't be written without quotes", i, field)
+				log.Debug(w.err.Error())
+				return w.err
+			}
+		}
+	}
+
+	for i, field := range record {
+		if i > 0 {
+			w.w.WriteRune(w.comma)
+		}
+		if w.quoted(field, i < len(numeric) && numeric[i]) {
+			w.writeQuoted(field)
+		} else {
+			w.w.WriteString(field)
+		}
+	}
+	// bufio.Writer errors are sticky, so the terminator write reports any
+	// failure writing the record
+	if w.crlf {
+		_, w.err = w.w.WriteString("\r\n")
+	} else {
+		w.err = w.w.WriteByte('\n')
+	}
+	return w.err
+}
+
+// Flush writes buffered records to the destination
+func (w *csvRecordWriter) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.err = w.w.Flush()
+	return w.err
+}
+
+// quoted reports whether a field is written in quotes under the policy
+func (w *csvRecordWriter) quoted(field string, numeric bool) bool {
+	switch w.quote {
+	case dataset.CSVQuoteAll:
+		return true
+	case dataset.CSVQuoteNonNumeric:
+		return !numeric || w.fieldNeedsQuotes(field)
+	case dataset.CSVQuoteNone:
+		return false
+	default:
+		return w.fieldNeedsQuotes(field)
+	}
+}
+
+// fieldNeedsQuotes reports whether a field can't be read back without
+// quotes, following the rules of encoding/csv
+func (w *csvRecordWriter) fieldNeedsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` || strings.ContainsRune(field, w.comma) || strings.ContainsAny(field, "\"\r\n") {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r)
+}
+
+// writeQuoted writes a field in quotes, doubling quotes within it. line
+// breaks are written with the record line terminator
+func (w *csvRecordWriter) writeQuoted(field string) {
+	w.w.WriteByte('"')
+	for _, r := range field {
+		switch r {
+		case '"':
+			w.w.WriteString(`""`)
+		case '\r':
+			if !w.crlf {
+				w.w.WriteByte('\r')
+			}
+		case '\n':
+			if w.crlf {
+				w.w.WriteString("\r\n")
+			} else {
+				w.w.WriteByte('\n')
+			}
+		default:
+			w.w.WriteRune(r)
+		}
+	}
+	w.w.WriteByte('"')
+}
+
+// numericFields flags the values of an entry written as numbers. nulls are
+// flagged too, so they're written as bare empty fields
+func numericFields(vs []interface{}) []bool {
+	numeric := make([]bool, len(vs))
+	for i, v := range vs {
+		switch v.(type) {
+		case int, int64, float64, nil:
+			numeric[i] = true
+		}
+	}
+	return numeric
+}
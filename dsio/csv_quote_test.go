@@ -0,0 +1,123 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestCSVWriterQuoting(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "id", "type": "integer"},
+				map[string]interface{}{"title": "name", "type": "string"},
+				map[string]interface{}{"title": "score", "type": "number"},
+				map[string]interface{}{"title": "ok", "type": "boolean"},
+				map[string]interface{}{"title": "note", "type": "string"},
+			},
+		},
+	}
+	entries := []Entry{
+		{Value: []interface{}{1, "a", 1.5, true, nil}},
+		{Value: []interface{}{2, "b", -2.25, false, "x"}},
+	}
+	cases := []struct {
+		cfg    map[string]interface{}
+		expect string
+	}{
+		{map[string]interface{}{"headerRow": true}, "id,name,score,ok,note\n1,a,1.5,true,\n2,b,-2.25,false,x\n"},
+		{map[string]interface{}{"quote": "minimal", "lineTerminator": "\r\n"}, "1,a,1.5,true,\r\n2,b,-2.25,false,x\r\n"},
+		{map[string]interface{}{"headerRow": true, "quote": "all"}, "\"id\",\"name\",\"score\",\"ok\",\"note\"\n\"1\",\"a\",\"1.5\",\"true\",\"\"\n\"2\",\"b\",\"-2.25\",\"false\",\"x\"\n"},
+		{map[string]interface{}{"headerRow": true, "quote": "nonNumeric"}, "\"id\",\"name\",\"score\",\"ok\",\"note\"\n1,\"a\",1.5,\"true\",\n2,\"b\",-2.25,\"false\",\"x\"\n"},
+		{map[string]interface{}{"quote": "none", "separator": ";", "lineTerminator": "\r\n"}, "1;a;1.5;true;\r\n2;b;-2.25;false;x\r\n"},
+	}
+
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "csv", FormatConfig: c.cfg, Schema: schema}
+		buf := &bytes.Buffer{}
+		w := NewCSVWriter(st, buf)
+		for _, ent := range entries {
+			if err := w.WriteEntry(ent); err != nil {
+				t.Fatalf("case %d: %s", i, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("case %d: %s", i, err)
+		}
+		if buf.String() != c.expect {
+			t.Errorf("case %d output mismatch.\nexpected: %q\ngot:      %q", i, c.expect, buf.String())
+		}
+	}
+}
+
+func TestCSVRecordWriter(t *testing.T) {
+	record := []string{"a,b", `say "hi"`, "two\nlines", " padded", `\.`, "", "Ünïcode"}
+	for _, quote := range []string{dataset.CSVQuoteMinimal, dataset.CSVQuoteAll, dataset.CSVQuoteNonNumeric} {
+		for _, term := range []string{"\n", "\r\n"} {
+			// minimal quoting must match encoding/csv
+			expect := &bytes.Buffer{}
+			cw := csv.NewWriter(expect)
+			cw.UseCRLF = term == "\r\n"
+			cw.Write(record)
+			cw.Flush()
+
+			buf := &bytes.Buffer{}
+			w := newCSVRecordWriter(buf, &dataset.CSVOptions{Quote: quote, LineTerminator: term})
+			if err := w.Write(record, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if quote == dataset.CSVQuoteMinimal && buf.String() != expect.String() {
+				t.Errorf("%s %q: expected encoding/csv output %q, got: %q", quote, term, expect.String(), buf.String())
+			}
+
+			// every policy must read back as the written record
+			r := csv.NewReader(buf)
+			got, err := r.Read()
+			if err != nil {
+				t.Fatalf("%s %q: %s", quote, term, err)
+			}
+			for i := range record {
+				if got[i] != record[i] {
+					t.Errorf("%s %q field %d: expected %q, got: %q", quote, term, i, record[i], got[i])
+				}
+			}
+		}
+	}
+}
+
+func TestCSVRecordWriterNoQuotes(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := newCSVRecordWriter(buf, &dataset.CSVOptions{Quote: dataset.CSVQuoteNone})
+	if err := w.Write([]string{"ok", "1"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	expect := `field 1: "a,b" can't be written without quotes`
+	if err := w.Write([]string{"ok", "a,b"}, nil); err == nil || err.Error() != expect {
+		t.Errorf("expected error %q, got: %v", expect, err)
+	}
+	// errors are sticky
+	if err := w.Write([]string{"ok"}, nil); err == nil || err.Error() != expect {
+		t.Errorf("expected error %q, got: %v", expect, err)
+	}
+	if err := w.Flush(); err == nil {
+		t.Error("expected flush to report the write error")
+	}
+
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"quote": "none"},
+		Schema:       dataset.BaseSchemaArray,
+	}
+	cw := NewCSVWriter(st, &bytes.Buffer{})
+	if err := cw.WriteEntry(Entry{Value: []interface{}{"line\nbreak"}}); err == nil {
+		t.Error("expected an error writing a field that needs quotes")
+	}
+}
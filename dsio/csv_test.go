@@ -2,8 +2,12 @@ package dsio
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/qri-io/dataset"
 )
@@ -286,3 +290,527 @@ func BenchmarkCSVReader(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkCSVReaderTyped(b *testing.B) {
+	st := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "a", "type": "integer"},
+					map[string]interface{}{"title": "b", "type": "number"},
+					map[string]interface{}{"title": "c", "type": "boolean"},
+					map[string]interface{}{"title": "d", "type": "number"},
+					map[string]interface{}{"title": "e", "type": "string"},
+				},
+			},
+		},
+	}
+	buf := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		buf.WriteString("12345,678.901,true,-0.5e3,text\n")
+	}
+	data := buf.Bytes()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		r := NewCSVReader(st, bytes.NewReader(data))
+		for {
+			if _, err := r.ReadEntry(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func TestCSVHeaderTitles(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "a", "type": "string"},
+					map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	}
+	titles, err := CSVHeaderTitles(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if titles[0] != "a" || titles[1] != dataset.AbstractColumnName(1) {
+		t.Errorf("titles mismatch. got: %v", titles)
+	}
+
+	_, err = CSVHeaderTitles(&dataset.Structure{Format: "csv", Schema: dataset.BaseSchemaArray})
+	if !errors.Is(err, ErrBadSchema) {
+		t.Errorf("expected ErrBadSchema, got: %v", err)
+	}
+}
+
+func TestCSVWriterPartialFormatConfig(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "csv",
+		FormatConfig: map[string]interface{}{
+			"headerRow": true,
+			"separator": "too long",
+		},
+		Schema: csvStruct.Schema,
+	}
+	buf := &bytes.Buffer{}
+	w := NewCSVWriter(st, buf)
+	if err := w.WriteEntry(Entry{Value: []interface{}{"a", 1.23, 4, false, nil, nil, nil}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expect := "col_a,col_b,col_c,col_d,col_e,col_f,col_g\na,1.23,4,false,,,\n"
+	if buf.String() != expect {
+		t.Errorf("output mismatch. expected: %q, got: %q", expect, buf.String())
+	}
+	if len(w.Titles()) != 7 {
+		t.Errorf("expected 7 titles, got: %v", w.Titles())
+	}
+}
+
+func TestCSVWriterHeaderWithoutTitles(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema:       dataset.BaseSchemaArray,
+	}
+	buf := &bytes.Buffer{}
+	w := NewCSVWriter(st, buf)
+	if w.Titles() != nil {
+		t.Errorf("expected no titles before first entry, got: %v", w.Titles())
+	}
+	if err := w.WriteEntry(Entry{Value: []interface{}{"a", "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expect := dataset.AbstractColumnName(0) + "," + dataset.AbstractColumnName(1) + "\na,b\n"
+	if buf.String() != expect {
+		t.Errorf("output mismatch. expected: %q, got: %q", expect, buf.String())
+	}
+}
+
+func TestCSVDateLayouts(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "csv",
+		FormatConfig: map[string]interface{}{
+			"headerRow":   true,
+			"dateLayouts": map[string]interface{}{"day": "2006-01-02"},
+		},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "day", "type": "string"},
+					map[string]interface{}{"title": "note", "type": "string"},
+				},
+			},
+		},
+	}
+
+	data := "day,note\n2019-03-04,2019-03-04\nnot a date,a\n"
+	r := NewCSVReader(st, bytes.NewBufferString(data))
+	ent, err := r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := ent.Value.([]interface{})
+	expect := time.Date(2019, 3, 4, 0, 0, 0, 0, time.UTC)
+	if ts, ok := row[0].(time.Time); !ok || !ts.Equal(expect) {
+		t.Errorf("expected date column to be read as time %s, got: %#v", expect, row[0])
+	}
+	if _, ok := row[1].(string); !ok {
+		t.Errorf("expected column without a layout to be a string, got: %#v", row[1])
+	}
+	ent, err = r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := ent.Value.([]interface{})[0].(string); !ok || s != "not a date" {
+		t.Errorf("expected unparsable date to be left as a string, got: %#v", ent.Value)
+	}
+
+	buf := &bytes.Buffer{}
+	w := NewCSVWriter(st, buf)
+	if err := w.WriteEntry(Entry{Value: []interface{}{expect, expect}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got := "day,note\n2019-03-04,2019-03-04T00:00:00Z\n"
+	if buf.String() != got {
+		t.Errorf("output mismatch. expected: %q, got: %q", got, buf.String())
+	}
+}
+
+func TestCSVWriterColumns(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "csv",
+		FormatConfig: map[string]interface{}{
+			"headerRow": true,
+			"columns": []interface{}{
+				map[string]interface{}{"key": "amount", "title": "Amount (EUR)"},
+				"id",
+				map[string]interface{}{"index": float64(3), "title": "Day"},
+				map[string]interface{}{"key": "note"},
+			},
+			"decimalPlaces": map[string]interface{}{"amount": float64(2)},
+			"dateLayouts":   map[string]interface{}{"day": "02.01.2006"},
+		},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "name", "type": "string"},
+					map[string]interface{}{"title": "amount", "type": "number"},
+					map[string]interface{}{"title": "day", "type": "string"},
+					map[string]interface{}{"title": "note", "type": "string"},
+				},
+			},
+		},
+	}
+	day := time.Date(2019, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	buf := &bytes.Buffer{}
+	w := NewCSVWriter(st, buf)
+	entries := []Entry{
+		{Value: []interface{}{1, "skipped", 9.5, day, "a, b"}},
+		{Value: []interface{}{2, "short", 0.126}},
+	}
+	for _, ent := range entries {
+		if err := w.WriteEntry(ent); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// index columns can't be read from objects
+	if err := w.WriteEntry(Entry{Index: 2, Value: map[string]interface{}{"id": 3}}); err == nil || err.Error() != "error writing entry 2: column 2: index columns can only be read from array entries" {
+		t.Errorf("error mismatch, got: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := "Amount (EUR),id,Day,note\n9.50,1,04.03.2019,\"a, b\"\n0.13,2,,\n"
+	if buf.String() != expect {
+		t.Errorf("output mismatch. expected: %q, got: %q", expect, buf.String())
+	}
+	if !reflect.DeepEqual(w.Titles(), []string{"Amount (EUR)", "id", "Day", "note"}) {
+		t.Errorf("titles mismatch, got: %v", w.Titles())
+	}
+
+	// object entries are read by key
+	st.FormatConfig = map[string]interface{}{"columns": []interface{}{"note", map[string]interface{}{"key": "id", "title": "ID"}}}
+	buf.Reset()
+	w = NewCSVWriter(st, buf)
+	if err := w.WriteEntry(Entry{Value: map[string]interface{}{"id": 7, "note": "x", "other": true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Value: "text"}); err == nil {
+		t.Error("expected writing a string entry to error")
+	}
+	w.Close()
+	if buf.String() != "x,7\n" {
+		t.Errorf("output mismatch. expected: %q, got: %q", "x,7\n", buf.String())
+	}
+
+	// array entries need schema titles for key columns
+	st.FormatConfig = map[string]interface{}{"columns": []interface{}{"missing"}}
+	w = NewCSVWriter(st, &bytes.Buffer{})
+	if err := w.WriteEntry(Entry{Value: []interface{}{1}}); err == nil || err.Error() != "error writing entry 0: column 0: schema has no column titled 'missing'" {
+		t.Errorf("error mismatch, got: %v", err)
+	}
+}
+
+func TestCSVWriterEncoding(t *testing.T) {
+	cases := []struct {
+		cfg    map[string]interface{}
+		expect string
+		err    string
+	}{
+		{map[string]interface{}{"encoding": "windows-1252"}, "caf\xe9,\x805\n", ""},
+		{map[string]interface{}{"encoding": "shift_jis"}, "", "character 'é' at byte offset 3 can't be encoded in shift_jis"},
+		{map[string]interface{}{"encoding": "shift_jis", "encodingFallback": "transliterate"}, "cafe,EUR5\n", ""},
+		{map[string]interface{}{"encoding": "shift_jis", "encodingFallback": "replace"}, "caf?,?5\n", ""},
+	}
+
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "csv", FormatConfig: c.cfg, Schema: dataset.BaseSchemaArray}
+		buf := &bytes.Buffer{}
+		w := NewCSVWriter(st, buf)
+		if err := w.WriteEntry(Entry{Value: []interface{}{"café", "€5"}}); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		err := w.Close()
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if c.err == "" && buf.String() != c.expect {
+			t.Errorf("case %d output mismatch. expected: %q, got: %q", i, c.expect, buf.String())
+		}
+		if c.err == "" && w.BytesProcessed() != int64(len(c.expect)) {
+			t.Errorf("case %d expected %d bytes processed, got: %d", i, len(c.expect), w.BytesProcessed())
+		}
+	}
+}
+
+func TestCSVReaderEncoding(t *testing.T) {
+	cases := []struct {
+		encoding string
+		data     string
+	}{
+		{"windows-1252", "name,price\ncaf\xe9,\x805\n"},
+		{"iso-8859-1", "name,price\ncaf\xe9,5\xa4\n"},
+		{"utf-16le", "n\x00a\x00m\x00e\x00,\x00p\x00\n\x00c\x00a\x00f\x00\xe9\x00,\x00\xac\x205\x00\n\x00"},
+		{"shift_jis", "name,price\n\x93\xfa\x96\x7b,\xb1\n"},
+	}
+	expect := map[string][]interface{}{
+		"windows-1252": {"café", "€5"},
+		"iso-8859-1":   {"café", "5¤"},
+		"utf-16le":     {"café", "€5"},
+		"shift_jis":    {"日本", "ｱ"},
+	}
+
+	for _, c := range cases {
+		st := &dataset.Structure{
+			Format:       "csv",
+			FormatConfig: map[string]interface{}{"headerRow": true, "encoding": c.encoding},
+			Schema:       dataset.BaseSchemaArray,
+		}
+		r := NewCSVReader(st, bytes.NewBufferString(c.data))
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("%s unexpected error: %s", c.encoding, err)
+		}
+		if !reflect.DeepEqual(ent.Value, expect[c.encoding]) {
+			t.Errorf("%s expected: %q, got: %q", c.encoding, expect[c.encoding], ent.Value)
+		}
+		if _, err := r.Checkpoint(); err == nil {
+			t.Errorf("%s expected checkpointing a transcoded reader to error", c.encoding)
+		}
+	}
+}
+
+func TestCSVReaderStripBOM(t *testing.T) {
+	cases := []struct {
+		data  string
+		title string
+		err   bool
+	}{
+		{"\xef\xbb\xbfname,price\ncafe,5\n", "name", false},
+		{"name,price\ncafe,5\n", "name", false},
+		{"\xff\xfen\x00a\x00m\x00e\x00,\x00p\x00\n\x00c\x00,\x005\x00\n\x00", "name", true},
+	}
+
+	for i, c := range cases {
+		st := &dataset.Structure{
+			Format:       "csv",
+			FormatConfig: map[string]interface{}{"stripBOM": true},
+			Schema:       dataset.BaseSchemaArray,
+		}
+		r := NewCSVReader(st, bytes.NewBufferString(c.data))
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if got := ent.Value.([]interface{})[0]; got != c.title {
+			t.Errorf("case %d expected first field: %q, got: %q", i, c.title, got)
+		}
+		if _, err := r.Checkpoint(); (err != nil) != c.err {
+			t.Errorf("case %d checkpoint error mismatch, got: %v", i, err)
+		}
+		if !c.err {
+			for {
+				if _, err := r.ReadEntry(); err == io.EOF {
+					break
+				}
+			}
+			if r.BytesProcessed() != int64(len(c.data)) {
+				t.Errorf("case %d expected %d bytes processed, got: %d", i, len(c.data), r.BytesProcessed())
+			}
+		}
+	}
+}
+
+func TestCSVWriterEmitBOM(t *testing.T) {
+	cases := []struct {
+		cfg    map[string]interface{}
+		expect string
+	}{
+		{map[string]interface{}{"emitBOM": true}, "\xef\xbb\xbfa,b\n"},
+		{map[string]interface{}{"emitBOM": true, "encoding": "utf-16le"}, "\xff\xfea\x00,\x00b\x00\n\x00"},
+		{map[string]interface{}{"emitBOM": true, "encoding": "windows-1252"}, "a,b\n"},
+	}
+
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "csv", FormatConfig: c.cfg, Schema: dataset.BaseSchemaArray}
+		buf := &bytes.Buffer{}
+		w := NewCSVWriter(st, buf)
+		if err := w.WriteEntry(Entry{Value: []interface{}{"a", "b"}}); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if buf.String() != c.expect {
+			t.Errorf("case %d output mismatch. expected: %q, got: %q", i, c.expect, buf.String())
+		}
+	}
+}
+
+func TestCSVReaderObjectRows(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id":    map[string]interface{}{"type": "integer"},
+				"name":  map[string]interface{}{"type": "string"},
+				"score": map[string]interface{}{"type": "number"},
+			},
+			"required": []interface{}{"id", "name"},
+		},
+	}
+	read := func(config map[string]interface{}, data string) ([]interface{}, error) {
+		st := &dataset.Structure{Format: "csv", FormatConfig: config, Schema: schema}
+		r := NewCSVReader(st, bytes.NewBufferString(data))
+		var vals []interface{}
+		for {
+			ent, err := r.ReadEntry()
+			if err == io.EOF {
+				return vals, nil
+			} else if err != nil {
+				return vals, err
+			}
+			vals = append(vals, ent.Value)
+		}
+	}
+
+	cases := []struct {
+		description string
+		config      map[string]interface{}
+		data        string
+		expect      []interface{}
+		err         string
+	}{
+		{"header titles map columns",
+			map[string]interface{}{"headerRow": true},
+			"score,id,name,extra\n1.5,1,a,x\n,2,,y\n",
+			[]interface{}{
+				map[string]interface{}{"id": int64(1), "name": "a", "score": 1.5, "extra": "x"},
+				map[string]interface{}{"id": int64(2), "name": "", "score": "", "extra": "y"},
+			}, ""},
+		{"no header row uses sorted properties",
+			nil,
+			"1,a,2.5\n",
+			[]interface{}{map[string]interface{}{"id": int64(1), "name": "a", "score": 2.5}}, ""},
+		{"optional columns read as null",
+			map[string]interface{}{"headerRow": true, "requiredColumns": true},
+			"id,name,score\n1,a,\n",
+			[]interface{}{map[string]interface{}{"id": int64(1), "name": "a", "score": nil}}, ""},
+		{"empty required column",
+			map[string]interface{}{"headerRow": true, "requiredColumns": true},
+			"id,name,score\n1,a,2\n2,,3\n",
+			[]interface{}{map[string]interface{}{"id": int64(1), "name": "a", "score": 2.0}},
+			"missing value for required column 'name'"},
+		{"required column missing from header",
+			map[string]interface{}{"headerRow": true, "requiredColumns": true},
+			"score\n1\n",
+			nil, "required columns missing from the header row: id, name"},
+	}
+
+	for _, c := range cases {
+		got, err := read(c.config, c.data)
+		if c.err == "" && err != nil {
+			t.Errorf("%s: unexpected error: %s", c.description, err)
+		} else if c.err != "" && (err == nil || err.Error() != c.err || !errors.Is(err, ErrMissingRequired)) {
+			t.Errorf("%s: error mismatch. expected: %q, got: %v", c.description, c.err, err)
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch.\nexpected: %#v\ngot:      %#v", c.description, c.expect, got)
+		}
+	}
+
+	st := &dataset.Structure{Format: "csv", FormatConfig: map[string]interface{}{"headerRow": true}, Schema: schema}
+	r := NewCSVReader(st, bytes.NewBufferString("id,name\n1,a\n"))
+	if _, err := r.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Checkpoint(); err == nil {
+		t.Error("expected checkpointing object rows with a header row to error")
+	}
+}
+
+func TestCSVReaderSkipFooterRows(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true, "skipFooterRows": 2},
+		Schema:       dataset.BaseSchemaArray,
+	}
+	data := "a,b\n1,2\n3,4\n5,6\nTotal,12\nend of report\n"
+
+	r := NewCSVReader(st, bytes.NewBufferString(data))
+	if r.Footer() != nil {
+		t.Error("expected no footer before reading")
+	}
+	count := 0
+	var token string
+	for {
+		ent, err := r.ReadEntry()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if ent.Value.([]interface{})[0] == "Total" {
+			t.Error("footer row read as an entry")
+		}
+		count++
+		if count == 1 {
+			if token, err = r.Checkpoint(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if count != 3 {
+		t.Errorf("expected 3 entries, got: %d", count)
+	}
+	footer := r.Footer()
+	if len(footer) != 2 || footer[0][0] != "Total" || footer[1][0] != "end of report" {
+		t.Errorf("footer mismatch. got: %v", footer)
+	}
+
+	// checkpoints are at the last returned entry, not the read-ahead rows
+	cp, err := ParseCheckpoint(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr, err := ResumeEntryReader(st, bytes.NewBufferString(data[cp.Offset:]), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := rr.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent.Value.([]interface{})[0] != "3" {
+		t.Errorf("expected resumed reader to read the second entry, got: %v", ent.Value)
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"io"
 
-	logger "github.com/ipfs/go-log"
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dslog"
 )
 
-var log = logger.Logger("dsio")
+var log = dslog.NewPackage("dsio")
+
+// SetLogger sets the logger for package dsio. By default nothing is logged
+func SetLogger(l dslog.Logger) {
+	log.SetLogger(l)
+}
 
 // EntryWriter is a generalized interface for writing structured data
 type EntryWriter interface {
@@ -47,8 +52,69 @@ type EntryReadWriter interface {
 	Bytes() []byte
 }
 
-// NewEntryReader allocates a EntryReader based on a given structure
-func NewEntryReader(st *dataset.Structure, r io.Reader) (EntryReader, error) {
+// NewEntryReader allocates a EntryReader based on a given structure. configs
+// can set limits on how much is read & enable lenient reading, see
+// ReaderConfig. If the structure has chunk checksums the body is verified as
+// it's read. Bodies with a structure compression are decompressed before
+// reading, see Decompress
+func NewEntryReader(st *dataset.Structure, r io.Reader, configs ...func(cfg *ReaderConfig)) (EntryReader, error) {
+	cfg := &ReaderConfig{}
+	for _, config := range configs {
+		config(cfg)
+	}
+
+	var cr *ChunkChecksumReader
+	if cs := st.ChunkChecksums; cs != nil && len(cs.Sums) > 0 {
+		var err error
+		if cr, err = NewChunkChecksumReader(r, cs); err != nil {
+			log.Debug(err.Error())
+			return nil, err
+		}
+		r = cr
+	}
+
+	// checksums cover stored bytes, limits bound decompressed bytes
+	if st.Compression != "" {
+		dr, err := Decompress(st.Compression, r)
+		if err != nil {
+			return nil, err
+		}
+		r = dr
+	}
+
+	var src *limitedReader
+	if cfg.enabled() {
+		src = &limitedReader{limiter: newLimiter(cfg), r: r}
+		r = src
+	}
+
+	var (
+		er  EntryReader
+		err error
+	)
+	if cfg.Errata != nil {
+		er, err = newErrataEntryReader(st, r, cfg.Errata)
+	} else if cfg.DecodeWorkers > 1 && st.DataFormat() == dataset.CSVDataFormat {
+		er = NewParallelCSVReader(st, r, cfg.DecodeWorkers)
+	} else {
+		er, err = newEntryReader(st, r)
+	}
+	if err != nil {
+		if src != nil && src.err != nil {
+			return nil, src.err
+		}
+		return nil, err
+	}
+	if cr != nil {
+		er = &checksumEntryReader{EntryReader: er, cr: cr}
+	}
+	if src != nil {
+		er = &limitedEntryReader{limiter: src.limiter, r: er, src: src}
+	}
+	return er, nil
+}
+
+func newEntryReader(st *dataset.Structure, r io.Reader) (EntryReader, error) {
 	switch st.DataFormat() {
 	case dataset.CBORDataFormat:
 		return NewCBORReader(st, r)
@@ -58,6 +124,36 @@ func NewEntryReader(st *dataset.Structure, r io.Reader) (EntryReader, error) {
 		return NewCSVReader(st, r), nil
 	case dataset.XLSXDataFormat:
 		return NewXLSXReader(st, r)
+	case dataset.NDJSONDataFormat:
+		return NewNDJSONReader(st, r)
+	case dataset.ParquetDataFormat:
+		return NewParquetReader(st, r)
+	case dataset.ArrowDataFormat:
+		return NewArrowReader(st, r)
+	case dataset.AvroDataFormat:
+		return NewAvroReader(st, r)
+	case dataset.TSVDataFormat:
+		return NewTSVReader(st, r)
+	case dataset.YAMLDataFormat:
+		return NewYAMLReader(st, r)
+	case dataset.XMLDataFormat:
+		return NewXMLReader(st, r)
+	case dataset.HTMLDataFormat:
+		return NewHTMLTableReader(st, r)
+	case dataset.ProtobufDataFormat:
+		return NewProtobufReader(st, r)
+	case dataset.ODSDataFormat:
+		return NewODSReader(st, r)
+	case dataset.XLSDataFormat:
+		return NewXLSReader(st, r)
+	case dataset.ORCDataFormat:
+		err := fmt.Errorf("orc is a write-only format")
+		log.Debug(err.Error())
+		return nil, err
+	case dataset.GeoJSONDataFormat:
+		return NewGeoJSONReader(st, r)
+	case dataset.ShapefileDataFormat:
+		return NewShapefileReader(st, r)
 	case dataset.UnknownDataFormat:
 		err := fmt.Errorf("structure must have a data format")
 		log.Debug(err.Error())
@@ -69,8 +165,46 @@ func NewEntryReader(st *dataset.Structure, r io.Reader) (EntryReader, error) {
 	}
 }
 
-// NewEntryWriter allocates a EntryWriter based on a given structure
+// NewEntryWriter allocates a EntryWriter based on a given structure. If the
+// structure has ChunkChecksums with a ChunkSize & no Sums, checksums of the
+// written body are recorded on the structure when the writer is closed.
+// Bodies with a structure compression are compressed as they're written,
+// see Compress
 func NewEntryWriter(st *dataset.Structure, w io.Writer) (EntryWriter, error) {
+	var cw *ChunkChecksumWriter
+	if cs := st.ChunkChecksums; cs != nil && len(cs.Sums) == 0 {
+		if err := validateChunkChecksums(cs); err != nil {
+			log.Debug(err.Error())
+			return nil, err
+		}
+		cw = NewChunkChecksumWriter(w, cs.ChunkSize)
+		w = cw
+	}
+
+	// checksums cover stored bytes, so compress before checksumming
+	var cmp io.WriteCloser
+	if st.Compression != "" {
+		var err error
+		if cmp, err = Compress(st.Compression, w); err != nil {
+			return nil, err
+		}
+		w = cmp
+	}
+
+	ew, err := newEntryWriter(st, w)
+	if err != nil {
+		return nil, err
+	}
+	if cmp != nil {
+		ew = &compressEntryWriter{EntryWriter: ew, cw: cmp}
+	}
+	if cw != nil {
+		ew = &checksumEntryWriter{EntryWriter: ew, cw: cw}
+	}
+	return ew, nil
+}
+
+func newEntryWriter(st *dataset.Structure, w io.Writer) (EntryWriter, error) {
 	switch st.DataFormat() {
 	case dataset.CBORDataFormat:
 		return NewCBORWriter(st, w)
@@ -80,6 +214,38 @@ func NewEntryWriter(st *dataset.Structure, w io.Writer) (EntryWriter, error) {
 		return NewCSVWriter(st, w), nil
 	case dataset.XLSXDataFormat:
 		return NewXLSXWriter(st, w)
+	case dataset.NDJSONDataFormat:
+		return NewNDJSONWriter(st, w)
+	case dataset.ParquetDataFormat:
+		return NewParquetWriter(st, w)
+	case dataset.ArrowDataFormat:
+		return NewArrowWriter(st, w)
+	case dataset.AvroDataFormat:
+		return NewAvroWriter(st, w)
+	case dataset.TSVDataFormat:
+		return NewTSVWriter(st, w)
+	case dataset.YAMLDataFormat:
+		return NewYAMLWriter(st, w)
+	case dataset.MarkdownDataFormat:
+		return NewMarkdownWriter(st, w)
+	case dataset.ProtobufDataFormat:
+		return NewProtobufWriter(st, w)
+	case dataset.ODSDataFormat:
+		return NewODSWriter(st, w)
+	case dataset.XLSDataFormat:
+		err := fmt.Errorf("xls is a read-only format, use xlsx to write spreadsheets")
+		log.Debug(err.Error())
+		return nil, err
+	case dataset.ORCDataFormat:
+		return NewORCWriter(st, w)
+	case dataset.GeoJSONDataFormat:
+		err := fmt.Errorf("geojson is a read-only format")
+		log.Debug(err.Error())
+		return nil, err
+	case dataset.ShapefileDataFormat:
+		err := fmt.Errorf("shapefile is a read-only format")
+		log.Debug(err.Error())
+		return nil, err
 	case dataset.UnknownDataFormat:
 		err := fmt.Errorf("structure must have a data format")
 		log.Debug(err.Error())
@@ -96,14 +262,14 @@ func NewEntryWriter(st *dataset.Structure, w io.Writer) (EntryWriter, error) {
 func GetTopLevelType(st *dataset.Structure) (string, error) {
 	// tlt := st.Schema.TopLevelType()
 	if st.Schema == nil {
-		return "", fmt.Errorf("a schema object is required")
+		return "", newKindError(ErrBadSchema, "a schema object is required")
 	}
 	tlt, ok := st.Schema["type"].(string)
 	if !ok {
-		return "", fmt.Errorf("schema top level 'type' value must be either 'array' or 'object'")
+		return "", newKindError(ErrBadSchema, "schema top level 'type' value must be either 'array' or 'object'")
 	}
 	if tlt != "array" && tlt != "object" {
-		return "", fmt.Errorf("invalid schema. root must be either an array or object type")
+		return "", newKindError(ErrBadSchema, "invalid schema. root must be either an array or object type")
 	}
 	return tlt, nil
 }
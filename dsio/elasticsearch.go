@@ -0,0 +1,146 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+)
+
+// ESBulkConfig encapsulates configuration for ESBulkWriter
+type ESBulkConfig struct {
+	// Index to write documents to. required
+	Index string
+	// Action is the bulk action for each document, one of "index" or
+	// "create". default is "index"
+	Action string
+	// IDField names the document field to use as the document _id. If empty
+	// entry keys are used for object bodies, and elasticsearch assigns ids
+	// for array bodies
+	IDField string
+}
+
+// ESBulkWriter implements the EntryWriter interface, writing entries as an
+// elasticsearch _bulk API payload. Each entry becomes an action line followed
+// by a document line. Array entries are converted to documents keyed by
+// column title
+type ESBulkWriter struct {
+	cfg *ESBulkConfig
+	st  *dataset.Structure
+	// lines of an entry are encoded to buf, which is written to out once
+	// both encode
+	buf    *bytes.Buffer
+	enc    *json.Encoder
+	out    *countingWriter
+	titles []string
+	count  int
+}
+
+var _ EntryWriter = (*ESBulkWriter)(nil)
+
+// NewESBulkWriter creates a writer from a structure and write destination
+func NewESBulkWriter(st *dataset.Structure, w io.Writer, configs ...func(cfg *ESBulkConfig)) (*ESBulkWriter, error) {
+	cfg := &ESBulkConfig{
+		Action: "index",
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	if cfg.Index == "" {
+		return nil, fmt.Errorf("elasticsearch index is required")
+	}
+	if cfg.Action != "index" && cfg.Action != "create" {
+		return nil, fmt.Errorf("invalid elasticsearch bulk action: %s", cfg.Action)
+	}
+
+	titles, _, _ := terribleHackToGetHeaderRowAndTypes(st)
+	buf := &bytes.Buffer{}
+	return &ESBulkWriter{
+		cfg:    cfg,
+		st:     st,
+		buf:    buf,
+		enc:    json.NewEncoder(buf),
+		out:    &countingWriter{w: w},
+		titles: titles,
+	}, nil
+}
+
+// Structure gives this writer's structure
+func (w *ESBulkWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// EntriesWritten gives the number of entries written
+func (w *ESBulkWriter) EntriesWritten() int {
+	return w.count
+}
+
+// BytesProcessed gives the number of bytes written
+func (w *ESBulkWriter) BytesProcessed() int64 {
+	return w.out.n
+}
+
+// WriteEntry writes the action & document lines for one entry. Entries that
+// fail to encode write neither line
+func (w *ESBulkWriter) WriteEntry(ent Entry) error {
+	doc, err := w.document(ent)
+	if err != nil {
+		return err
+	}
+
+	meta := map[string]interface{}{"_index": w.cfg.Index}
+	if w.cfg.IDField != "" {
+		if id, ok := doc[w.cfg.IDField]; ok && id != nil {
+			meta["_id"] = fmt.Sprintf("%v", id)
+		}
+	} else if ent.Key != "" {
+		meta["_id"] = ent.Key
+	}
+
+	w.buf.Reset()
+	if err := w.enc.Encode(map[string]interface{}{w.cfg.Action: meta}); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error encoding entry: %w", err)
+	}
+	if err := w.enc.Encode(doc); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error encoding entry: %w", err)
+	}
+	if _, err := w.out.Write(w.buf.Bytes()); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing entry: %w", err)
+	}
+	w.count++
+	return nil
+}
+
+// document converts an entry value to an elasticsearch document. scalar
+// values are wrapped in an object with a single "value" field
+func (w *ESBulkWriter) document(ent Entry) (map[string]interface{}, error) {
+	switch v := ent.Value.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case []interface{}:
+		doc := make(map[string]interface{}, len(v))
+		for i, val := range v {
+			title := ""
+			if i < len(w.titles) {
+				title = w.titles[i]
+			}
+			if title == "" {
+				title = dataset.AbstractColumnName(i)
+			}
+			doc[title] = val
+		}
+		return doc, nil
+	default:
+		return map[string]interface{}{"value": v}, nil
+	}
+}
+
+// Close finalizes the writer. bulk payloads need no trailer
+func (w *ESBulkWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,92 @@
+package dsio
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestESBulkWriter(t *testing.T) {
+	arrSt := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "name", "type": "string"},
+				},
+			},
+		},
+	}
+	objSt := &dataset.Structure{Schema: dataset.BaseSchemaObject}
+
+	cases := []struct {
+		st      *dataset.Structure
+		configs []func(*ESBulkConfig)
+		entries []Entry
+		expect  string
+	}{
+		{arrSt, []func(*ESBulkConfig){func(c *ESBulkConfig) { c.Index = "things"; c.IDField = "id" }},
+			[]Entry{{Index: 0, Value: []interface{}{1, "a"}}, {Index: 1, Value: []interface{}{2, "b"}}},
+			`{"index":{"_id":"1","_index":"things"}}
+{"id":1,"name":"a"}
+{"index":{"_id":"2","_index":"things"}}
+{"id":2,"name":"b"}
+`},
+		{objSt, []func(*ESBulkConfig){func(c *ESBulkConfig) { c.Index = "things"; c.Action = "create" }},
+			[]Entry{{Key: "a", Value: map[string]interface{}{"n": 1}}, {Key: "b", Value: "scalar"}},
+			`{"create":{"_id":"a","_index":"things"}}
+{"n":1}
+{"create":{"_id":"b","_index":"things"}}
+{"value":"scalar"}
+`},
+	}
+
+	for i, c := range cases {
+		buf := &bytes.Buffer{}
+		w, err := NewESBulkWriter(c.st, buf, c.configs...)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		for _, ent := range c.entries {
+			if err := w.WriteEntry(ent); err != nil {
+				t.Fatalf("case %d unexpected error writing: %s", i, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("case %d unexpected error closing: %s", i, err)
+		}
+		if buf.String() != c.expect {
+			t.Errorf("case %d output mismatch.\nexpected:\n%s\ngot:\n%s", i, c.expect, buf.String())
+		}
+	}
+
+	// entries that fail to encode leave no action line behind
+	buf := &bytes.Buffer{}
+	w, err := NewESBulkWriter(objSt, buf, func(c *ESBulkConfig) { c.Index = "things" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Key: "a", Value: map[string]interface{}{"n": math.NaN()}}); err == nil {
+		t.Error("expected unencodable document to error")
+	}
+	if err := w.WriteEntry(Entry{Key: "b", Value: map[string]interface{}{"n": 1}}); err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"index":{"_id":"b","_index":"things"}}
+{"n":1}
+`
+	if buf.String() != expect {
+		t.Errorf("output mismatch.\nexpected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+	if w.EntriesWritten() != 1 {
+		t.Errorf("expected 1 entry written, got: %d", w.EntriesWritten())
+	}
+
+	if _, err := NewESBulkWriter(arrSt, &bytes.Buffer{}); err == nil {
+		t.Error("expected missing index to error")
+	}
+}
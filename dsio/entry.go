@@ -3,6 +3,7 @@ package dsio
 import (
 	"fmt"
 	"io"
+	"time"
 )
 
 // Entry is a "row" of a dataset
@@ -16,6 +17,19 @@ type Entry struct {
 	Key string
 	// Value is information contained within the row
 	Value interface{}
+	// Provenance optionally records where the entry was read from, nil when
+	// it isn't tracked
+	Provenance *Provenance
+}
+
+// Provenance traces an entry back to it's origin
+type Provenance struct {
+	// Row is the entry's position in it's source, counting from zero
+	Row int
+	// Source names the file, shard or URL the entry was read from
+	Source string
+	// Fetched is when the source was fetched, zero if unknown
+	Fetched time.Time
 }
 
 // DataIteratorFunc is a function for each "row" of a resource's raw data
@@ -30,7 +44,7 @@ func EachEntry(rr EntryReader, fn DataIteratorFunc) error {
 			if err.Error() == io.EOF.Error() {
 				return nil
 			}
-			err := fmt.Errorf("error reading row %d: %s", num, err.Error())
+			err := fmt.Errorf("error reading row %d: %w", num, err)
 			log.Debug(err.Error())
 			return err
 		}
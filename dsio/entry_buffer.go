@@ -58,6 +58,16 @@ func (b *EntryBuffer) WriteEntry(e Entry) error {
 	return b.w.WriteEntry(e)
 }
 
+// EntriesRead gives the number of entries read from the buffer
+func (b *EntryBuffer) EntriesRead() int {
+	return entriesRead(b.r)
+}
+
+// EntriesWritten gives the number of entries written to the buffer
+func (b *EntryBuffer) EntriesWritten() int {
+	return entriesWritten(b.w)
+}
+
 // Close closes the writer portion of the buffer, which will affect
 // underlying contents.
 func (b *EntryBuffer) Close() error {
@@ -0,0 +1,136 @@
+package dsio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dslog"
+)
+
+// rawSpanReader is implemented by readers that can give the position in
+// their source of the record they last read, which lets NewEntryReader
+// capture the raw bytes of records that fail to parse. Positions count bytes
+// from the start of the source the reader was created with
+type rawSpanReader interface {
+	rawSpan() (start, end int64)
+}
+
+// rawRecorder keeps the bytes read from a source that come after a
+// position, so raw records can be copied after they've been parsed
+type rawRecorder struct {
+	r   io.Reader
+	buf []byte
+	// base is the source position of buf[0]
+	base int64
+	// err is the first error other than io.EOF reading the source
+	err error
+}
+
+// Read implements the io.Reader interface
+func (rec *rawRecorder) Read(p []byte) (int, error) {
+	n, err := rec.r.Read(p)
+	rec.buf = append(rec.buf, p[:n]...)
+	if err != nil && err != io.EOF && rec.err == nil {
+		rec.err = err
+	}
+	return n, err
+}
+
+// Close closes the underlying reader if it's an io.Closer
+func (rec *rawRecorder) Close() error {
+	return closeUnderlying(rec.r)
+}
+
+// bytes gives the recorded bytes between two source positions, clipped to
+// the bytes that haven't been discarded
+func (rec *rawRecorder) bytes(start, end int64) []byte {
+	if start < rec.base {
+		start = rec.base
+	}
+	if end > rec.base+int64(len(rec.buf)) {
+		end = rec.base + int64(len(rec.buf))
+	}
+	if end <= start {
+		return nil
+	}
+	return rec.buf[start-rec.base : end-rec.base]
+}
+
+// discard drops recorded bytes before a source position
+func (rec *rawRecorder) discard(pos int64) {
+	n := pos - rec.base
+	if n <= 0 {
+		return
+	}
+	if n > int64(len(rec.buf)) {
+		n = int64(len(rec.buf))
+	}
+	rec.buf = append(rec.buf[:0], rec.buf[n:]...)
+	rec.base += n
+}
+
+// errataEntryReader skips entries that fail to parse, writing their raw
+// bytes to an errata writer
+type errataEntryReader struct {
+	EntryReader
+	spans  rawSpanReader
+	rec    *rawRecorder
+	errata io.Writer
+}
+
+// newErrataEntryReader creates a lenient reader of st's format, reading
+// from a recorded source
+func newErrataEntryReader(st *dataset.Structure, r io.Reader, errata io.Writer) (EntryReader, error) {
+	rec := &rawRecorder{r: r}
+	er, err := newEntryReader(st, rec)
+	if err != nil {
+		return nil, err
+	}
+	spans, ok := er.(rawSpanReader)
+	if !ok {
+		er.Close()
+		err := fmt.Errorf("lenient reading isn't supported for %s bodies", st.Format)
+		log.Debug(err.Error())
+		return nil, err
+	}
+	return &errataEntryReader{EntryReader: er, spans: spans, rec: rec, errata: errata}, nil
+}
+
+// ReadEntry reads the next entry that parses. Records that fail to parse
+// are written to errata as-is, ending with a newline. Errors reading the
+// source end reading
+func (r *errataEntryReader) ReadEntry() (Entry, error) {
+	for {
+		ent, err := r.EntryReader.ReadEntry()
+		start, end := r.spans.rawSpan()
+
+		var perr *ParseError
+		if err == nil || !errors.As(err, &perr) || r.rec.err != nil || end <= start {
+			r.rec.discard(end)
+			return ent, err
+		}
+
+		log.Debugw("quarantining entry", dslog.F("index", perr.Index), dslog.F("error", perr.Err))
+		raw := r.rec.bytes(start, end)
+		if len(raw) > 0 && raw[len(raw)-1] != '\n' {
+			raw = append(raw[:len(raw):len(raw)], '\n')
+		}
+		if _, werr := r.errata.Write(raw); werr != nil {
+			log.Debug(werr.Error())
+			return Entry{}, fmt.Errorf("error writing errata: %w", werr)
+		}
+		r.rec.discard(end)
+	}
+}
+
+// EntriesRead gives the number of entries read
+func (r *errataEntryReader) EntriesRead() int {
+	return entriesRead(r.EntryReader)
+}
+
+// BytesProcessed gives the bytes processed by the underlying reader
+func (r *errataEntryReader) BytesProcessed() int64 {
+	return bytesProcessed(r.EntryReader)
+}
@@ -0,0 +1,200 @@
+package dsio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestReaderErrata(t *testing.T) {
+	cases := []struct {
+		description string
+		st          *dataset.Structure
+		body        string
+		values      []interface{}
+		errata      string
+	}{
+		{
+			"csv field count",
+			&dataset.Structure{Format: "csv", FormatConfig: map[string]interface{}{"headerRow": true}, Schema: dataset.BaseSchemaArray},
+			"a,b\n1,2\n3,4,5\n\"6\nsix\",7\n8\n9,10",
+			[]interface{}{
+				[]interface{}{"1", "2"},
+				[]interface{}{"6\nsix", "7"},
+				[]interface{}{"9", "10"},
+			},
+			"3,4,5\n8\n",
+		},
+		{
+			"csv bare quote",
+			&dataset.Structure{Format: "csv", Schema: dataset.BaseSchemaArray},
+			"1,2\r\n3,x\"y\r\n4,5\r\n",
+			[]interface{}{
+				[]interface{}{"1", "2"},
+				[]interface{}{"4", "5"},
+			},
+			"3,x\"y\r\n",
+		},
+		{
+			"csv footer rows",
+			&dataset.Structure{Format: "csv", FormatConfig: map[string]interface{}{"skipFooterRows": 1}, Schema: dataset.BaseSchemaArray},
+			"1,2\n3\n4,5\ntotal\n",
+			[]interface{}{
+				[]interface{}{"1", "2"},
+				[]interface{}{"4", "5"},
+			},
+			"3\n",
+		},
+		{
+			"tsv",
+			&dataset.Structure{Format: "tsv", Schema: dataset.BaseSchemaArray},
+			"1\t2\n\n3\n4\t5",
+			[]interface{}{
+				[]interface{}{"1", "2"},
+				[]interface{}{"4", "5"},
+			},
+			"3\n",
+		},
+		{
+			"quoted tsv",
+			&dataset.Structure{Format: "tsv", FormatConfig: map[string]interface{}{"escape": "quote"}, Schema: dataset.BaseSchemaArray},
+			"1\t2\n3\n4\t5\n",
+			[]interface{}{
+				[]interface{}{"1", "2"},
+				[]interface{}{"4", "5"},
+			},
+			"3\n",
+		},
+		{
+			"ndjson",
+			&dataset.Structure{Format: "ndjson", Schema: dataset.BaseSchemaArray},
+			"{\"a\":1}\n\n{\"a\":\n[1] [2]\r\n{\"a\":2}\n{oops}",
+			[]interface{}{
+				map[string]interface{}{"a": 1},
+				map[string]interface{}{"a": 2},
+			},
+			"{\"a\":\n[1] [2]\r\n{oops}\n",
+		},
+	}
+
+	for _, c := range cases {
+		errata := &bytes.Buffer{}
+		r, err := NewEntryReader(c.st, strings.NewReader(c.body), func(cfg *ReaderConfig) { cfg.Errata = errata })
+		if err != nil {
+			t.Fatalf("case '%s': %s", c.description, err)
+		}
+		var values []interface{}
+		for {
+			ent, err := r.ReadEntry()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("case '%s': %s", c.description, err)
+			}
+			values = append(values, ent.Value)
+		}
+		if !reflect.DeepEqual(values, c.values) {
+			t.Errorf("case '%s' values mismatch.\nexpected: %v\ngot:      %v", c.description, c.values, values)
+		}
+		if errata.String() != c.errata {
+			t.Errorf("case '%s' errata mismatch. expected: %q, got: %q", c.description, c.errata, errata.String())
+		}
+		if err := r.Close(); err != nil {
+			t.Errorf("case '%s' closing: %s", c.description, err)
+		}
+	}
+}
+
+func TestReaderErrataErrors(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	_, err := NewEntryReader(st, strings.NewReader("[]"), func(cfg *ReaderConfig) { cfg.Errata = &bytes.Buffer{} })
+	if err == nil || err.Error() != "lenient reading isn't supported for json bodies" {
+		t.Errorf("expected unsupported format error, got: %v", err)
+	}
+
+	// source errors aren't quarantined
+	st = &dataset.Structure{Format: "ndjson", Schema: dataset.BaseSchemaArray}
+	errata := &bytes.Buffer{}
+	src := io.MultiReader(strings.NewReader("1\n"), &failingReader{err: errors.New("disk on fire")})
+	r, err := NewEntryReader(st, src, func(cfg *ReaderConfig) { cfg.Errata = errata })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err == nil || !strings.Contains(err.Error(), "disk on fire") {
+		t.Errorf("expected source error, got: %v", err)
+	}
+	if errata.Len() != 0 {
+		t.Errorf("expected no errata, got: %q", errata.String())
+	}
+
+	// limits apply to entries that parse
+	r, err = NewEntryReader(st, strings.NewReader("1\nx\n2\n3\n"), func(cfg *ReaderConfig) {
+		cfg.Errata = &bytes.Buffer{}
+		cfg.MaxEntries = 2
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := r.ReadEntry(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := r.ReadEntry(); !errors.As(err, new(*ErrLimitExceeded)) {
+		t.Errorf("expected a limit error, got: %v", err)
+	}
+
+	r, err = NewEntryReader(st, strings.NewReader("x\n"), func(cfg *ReaderConfig) { cfg.Errata = failingByteWriter{} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err == nil || err.Error() != "error writing errata: write failed" {
+		t.Errorf("expected errata write error, got: %v", err)
+	}
+}
+
+func TestRawRecorder(t *testing.T) {
+	rec := &rawRecorder{r: strings.NewReader("abcdefgh")}
+	buf := make([]byte, 5)
+	if _, err := rec.Read(buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(rec.bytes(1, 3)); got != "bc" {
+		t.Errorf("expected bc, got: %q", got)
+	}
+	rec.discard(2)
+	if got := string(rec.bytes(0, 9)); got != "cde" {
+		t.Errorf("expected bytes clipped to cde, got: %q", got)
+	}
+	rec.discard(1)
+	rec.Read(buf)
+	if got := string(rec.bytes(4, 8)); got != "efgh" {
+		t.Errorf("expected efgh, got: %q", got)
+	}
+	rec.discard(20)
+	if rec.base != 8 || len(rec.bytes(0, 20)) != 0 {
+		t.Errorf("expected all bytes discarded, got base %d: %q", rec.base, rec.bytes(0, 20))
+	}
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+type failingByteWriter struct{}
+
+func (failingByteWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
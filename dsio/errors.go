@@ -0,0 +1,69 @@
+package dsio
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	// ErrBadSchema is matched by errors for structures that have a missing or
+	// invalid schema for reading or writing, check with errors.Is
+	ErrBadSchema = errors.New("bad schema")
+	// ErrFormatMismatch is matched by errors for data that doesn't match the
+	// format or top level type it's being read as, check with errors.Is
+	ErrFormatMismatch = errors.New("format mismatch")
+	// ErrMissingRequired is matched by errors for values missing from
+	// required columns, check with errors.Is
+	ErrMissingRequired = errors.New("missing required value")
+)
+
+// kindError is an error with it's own message that matches a sentinel error
+type kindError struct {
+	kind error
+	msg  string
+}
+
+func newKindError(kind error, msg string) error {
+	return &kindError{kind: kind, msg: msg}
+}
+
+// Error implements the error interface
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+// Unwrap gives the sentinel error
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// ParseError is returned by readers that fail to decode an entry
+type ParseError struct {
+	// Format of the data being read
+	Format string
+	// Index of the entry that failed to parse
+	Index int
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap gives the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseError wraps errors other than io.EOF in a *ParseError
+func parseError(format string, index int, err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	if _, ok := err.(*ParseError); ok {
+		return err
+	}
+	return &ParseError{Format: format, Index: index, Err: err}
+}
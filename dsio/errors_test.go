@@ -0,0 +1,53 @@
+package dsio
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestErrorKinds(t *testing.T) {
+	if _, err := NewJSONReader(&dataset.Structure{Format: "json"}, strings.NewReader("[]")); !errors.Is(err, ErrBadSchema) {
+		t.Errorf("expected missing schema to be ErrBadSchema, got: %v", err)
+	}
+
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewEntryReader(st, strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); !errors.Is(err, ErrFormatMismatch) {
+		t.Errorf("expected object body read as array to be ErrFormatMismatch, got: %v", err)
+	}
+
+	r, err = NewEntryReader(st, strings.NewReader(`[1 2]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.ReadEntry()
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError, got: %#v", err)
+	}
+	if perr.Format != "json" || perr.Index != 1 {
+		t.Errorf("unexpected parse error fields: %#v", perr)
+	}
+	if err.Error() != "Expected: separator ','" {
+		t.Errorf("expected parse errors to keep their message, got: %s", err)
+	}
+
+	csvSt := &dataset.Structure{Format: "csv", Schema: dataset.BaseSchemaArray}
+	r, err = NewEntryReader(csvSt, strings.NewReader("a,b\n\"c,d\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ReadEntry()
+	if _, err := r.ReadEntry(); !errors.As(err, &perr) || perr.Index != 1 {
+		t.Errorf("expected csv *ParseError at index 1, got: %#v", err)
+	}
+}
@@ -0,0 +1,778 @@
+package dsio
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+// FlattenConfig configures how nested entries are flattened into columns
+type FlattenConfig struct {
+	// Separator joins path elements into column titles, defaults to "."
+	Separator string
+	// Explode is a JSON Pointer to an array in each entry that's written as
+	// one row per element, repeating the entry's other columns. Other arrays
+	// are spread across indexed columns
+	Explode string
+	// EntryColumn titles the column exploded rows hold the index of their
+	// entry in, defaults to "_entry"
+	EntryColumn string
+}
+
+func newFlattenConfig(configs []func(cfg *FlattenConfig)) *FlattenConfig {
+	cfg := &FlattenConfig{Separator: ".", EntryColumn: "_entry"}
+	for _, config := range configs {
+		config(cfg)
+	}
+	return cfg
+}
+
+// FlattenStructure gives the structure of a body flattened according to the
+// schema of st. Object properties become columns titled by their path, so
+// {"a":{"b":1}} gives a column "a.b". Arrays are spread across indexed
+// columns when the schema lists their items or sets maxItems, values the
+// schema doesn't describe are written whole. Use ScanFlattenStructure for
+// bodies without a detailed schema.
+//
+// The flattened structure is a csv structure with a header row, and the
+// mapping from columns to nested values is recorded in it's FormatConfig
+// under the "flatten" key, see dataset.Flattening
+func FlattenStructure(st *dataset.Structure, configs ...func(cfg *FlattenConfig)) (*dataset.Structure, error) {
+	fc, err := newFlattenColumns(newFlattenConfig(configs))
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	items, _ := st.Schema["items"].(map[string]interface{})
+	fc.addSchema(items, nil, false)
+	return fc.structure(st), nil
+}
+
+// ScanFlattenStructure gives the structure of a body flattened into the
+// columns found by reading every entry of r, with column types taken from
+// the values read. r isn't closed
+func ScanFlattenStructure(r EntryReader, configs ...func(cfg *FlattenConfig)) (*dataset.Structure, error) {
+	fc, err := newFlattenColumns(newFlattenConfig(configs))
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	for {
+		ent, err := r.ReadEntry()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		fc.addValue(ent.Value, nil, false)
+	}
+	return fc.structure(r.Structure()), nil
+}
+
+// flattenColumns collects the columns of a flattening
+type flattenColumns struct {
+	cfg     *FlattenConfig
+	explode []string
+	cols    []dataset.FlattenColumn
+	schemas []map[string]interface{}
+	// empty marks columns that have only held nulls & empty containers,
+	// which are dropped if values are found beneath them
+	empty []bool
+	index map[string]int
+}
+
+func newFlattenColumns(cfg *FlattenConfig) (*flattenColumns, error) {
+	explode, err := parsePointer(cfg.Explode)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Explode != "" && len(explode) == 0 {
+		return nil, fmt.Errorf("can't explode the whole entry, explode must point to an array within entries")
+	}
+	return &flattenColumns{cfg: cfg, explode: explode, index: map[string]int{}}, nil
+}
+
+// isExplode reports weather path is the exploded array
+func (fc *flattenColumns) isExplode(path []interface{}, element bool) bool {
+	if element || len(fc.explode) == 0 || len(path) != len(fc.explode) {
+		return false
+	}
+	for i, p := range path {
+		if flattenPathString(p) != fc.explode[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// column gives the index of the column at path, adding it if it's new
+func (fc *flattenColumns) column(path []interface{}, element bool) int {
+	key := flattenColumnKey(path, element)
+	if i, ok := fc.index[key]; ok {
+		return i
+	}
+	p := make([]interface{}, len(path))
+	copy(p, path)
+
+	var parts []string
+	if element {
+		parts = append(parts, fc.explode...)
+	}
+	for _, e := range path {
+		parts = append(parts, flattenPathString(e))
+	}
+	title := strings.Join(parts, fc.cfg.Separator)
+	if len(parts) == 0 {
+		title = "value"
+	}
+
+	fc.index[key] = len(fc.cols)
+	fc.cols = append(fc.cols, dataset.FlattenColumn{Title: title, Path: p, Element: element})
+	fc.schemas = append(fc.schemas, map[string]interface{}{})
+	fc.empty = append(fc.empty, true)
+	return len(fc.cols) - 1
+}
+
+// addSchema adds the columns a schema describes
+func (fc *flattenColumns) addSchema(sch map[string]interface{}, path []interface{}, element bool) {
+	if fc.isExplode(path, element) {
+		items, _ := sch["items"].(map[string]interface{})
+		fc.addSchema(items, nil, true)
+		return
+	}
+	if props, ok := sch["properties"].(map[string]interface{}); ok && len(props) > 0 {
+		keys := make([]string, 0, len(props))
+		for k := range props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sub, _ := props[k].(map[string]interface{})
+			fc.addSchema(sub, append(path, k), element)
+		}
+		return
+	}
+	switch items := sch["items"].(type) {
+	case []interface{}:
+		if len(items) > 0 {
+			for i, it := range items {
+				sub, _ := it.(map[string]interface{})
+				fc.addSchema(sub, append(path, i), element)
+			}
+			return
+		}
+	case map[string]interface{}:
+		if max := schemaMaxItems(sch); max > 0 {
+			for i := 0; i < max; i++ {
+				fc.addSchema(items, append(path, i), element)
+			}
+			return
+		}
+	}
+
+	i := fc.column(path, element)
+	for k, v := range sch {
+		if k != "title" {
+			fc.schemas[i][k] = v
+		}
+	}
+	fc.empty[i] = false
+}
+
+// schemaMaxItems reads the maxItems of an array schema, 0 if it isn't set
+func schemaMaxItems(sch map[string]interface{}) int {
+	switch n := sch["maxItems"].(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
+// addValue adds the columns of a value
+func (fc *flattenColumns) addValue(v interface{}, path []interface{}, element bool) {
+	if fc.isExplode(path, element) {
+		if arr, ok := v.([]interface{}); ok {
+			for _, el := range arr {
+				fc.addValue(el, nil, true)
+			}
+		}
+		return
+	}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) > 0 {
+			keys := make([]string, 0, len(t))
+			for k := range t {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fc.addValue(t[k], append(path, k), element)
+			}
+			return
+		}
+	case []interface{}:
+		if len(t) > 0 {
+			for i, el := range t {
+				fc.addValue(el, append(path, i), element)
+			}
+			return
+		}
+	}
+
+	i := fc.column(path, element)
+	typ := flattenValueType(v)
+	if typ == "" {
+		return
+	}
+	sch := fc.schemas[i]
+	switch prev, _ := sch["type"].(string); {
+	case fc.empty[i] && prev == "":
+		sch["type"] = typ
+	case prev == typ:
+	case prev == "integer" && typ == "number" || prev == "number" && typ == "integer":
+		sch["type"] = "number"
+	default:
+		// columns holding mixed types accept any value
+		delete(sch, "type")
+	}
+	if typ != "object" && typ != "array" {
+		fc.empty[i] = false
+	}
+}
+
+// flattenValueType gives the schema type of a value, "" for null
+func flattenValueType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return ""
+	case string, time.Time:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case float32, float64:
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return ""
+	}
+}
+
+// structure builds the flattened structure, dropping empty columns that have
+// values beneath them
+func (fc *flattenColumns) structure(st *dataset.Structure) *dataset.Structure {
+	f := &dataset.Flattening{Separator: fc.cfg.Separator}
+	var items []interface{}
+	if len(fc.explode) > 0 {
+		f.Explode = fc.cfg.Explode
+		f.EntryColumn = fc.cfg.EntryColumn
+		items = append(items, map[string]interface{}{"title": f.EntryColumn, "type": "integer"})
+	}
+	for i, c := range fc.cols {
+		if fc.empty[i] && fc.hasDescendant(i) {
+			continue
+		}
+		f.Columns = append(f.Columns, c)
+		sch := fc.schemas[i]
+		sch["title"] = c.Title
+		items = append(items, sch)
+	}
+	if items == nil {
+		items = []interface{}{}
+	}
+
+	return &dataset.Structure{
+		Qri:    st.Qri,
+		Format: dataset.CSVDataFormat.String(),
+		FormatConfig: map[string]interface{}{
+			"headerRow":              true,
+			dataset.FlattenConfigKey: f.Map(),
+		},
+		Schema: map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "array", "items": items},
+		},
+	}
+}
+
+// hasDescendant reports weather any column is nested beneath column i
+func (fc *flattenColumns) hasDescendant(i int) bool {
+	prefix := fc.cols[i].Path
+	for j, c := range fc.cols {
+		if j == i || c.Element != fc.cols[i].Element || len(c.Path) <= len(prefix) {
+			continue
+		}
+		if flattenColumnKey(c.Path[:len(prefix)], c.Element) == flattenColumnKey(prefix, c.Element) {
+			return true
+		}
+	}
+	return false
+}
+
+func flattenColumnKey(path []interface{}, element bool) string {
+	key := "entry"
+	if element {
+		key = "element"
+	}
+	for _, e := range path {
+		// %#v keeps the key "0" & the index 0 apart
+		key += fmt.Sprintf("/%#v", e)
+	}
+	return key
+}
+
+func flattenPathString(e interface{}) string {
+	if i, ok := e.(int); ok {
+		return strconv.Itoa(i)
+	}
+	s, _ := e.(string)
+	return s
+}
+
+// resolveFlattenPath gives the value at a path of keys & indexes in v
+func resolveFlattenPath(v interface{}, path []interface{}) interface{} {
+	for _, e := range path {
+		switch key := e.(type) {
+		case string:
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			v = m[key]
+		case int:
+			arr, ok := v.([]interface{})
+			if !ok || key >= len(arr) {
+				return nil
+			}
+			v = arr[key]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// FlattenReader wraps a reader of nested entries, giving flat array entries
+// with one value per column of a flattening. Exploded arrays give one entry
+// per element, and an entry with empty element columns if the array is empty
+// or missing
+type FlattenReader struct {
+	Reader  EntryReader
+	st      *dataset.Structure
+	f       *dataset.Flattening
+	explode []string
+	pending []Entry
+	entries int
+	read    int
+}
+
+var _ EntryReader = (*FlattenReader)(nil)
+
+// NewFlattenReader creates a reader flattening the entries of r according to
+// the flattening recorded in fst, the structure given by FlattenStructure or
+// ScanFlattenStructure
+func NewFlattenReader(r EntryReader, fst *dataset.Structure) (*FlattenReader, error) {
+	f, explode, err := structureFlattening(fst)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	return &FlattenReader{Reader: r, st: fst, f: f, explode: explode}, nil
+}
+
+// structureFlattening reads the flattening recorded in a structure
+func structureFlattening(st *dataset.Structure) (*dataset.Flattening, []string, error) {
+	f, err := dataset.ParseFlattening(st.FormatConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if f == nil {
+		return nil, nil, fmt.Errorf("structure has no flattening")
+	}
+	explode, err := parsePointer(f.Explode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, explode, nil
+}
+
+// Structure gives the flattened structure
+func (r *FlattenReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry returns the next flattened entry
+func (r *FlattenReader) ReadEntry() (Entry, error) {
+	for len(r.pending) == 0 {
+		ent, err := r.Reader.ReadEntry()
+		if err != nil {
+			return ent, err
+		}
+		if r.pending, err = r.flatten(ent.Value); err != nil {
+			return Entry{}, err
+		}
+		r.entries++
+	}
+	ent := r.pending[0]
+	r.pending = r.pending[1:]
+	r.read++
+	return ent, nil
+}
+
+// flatten gives the rows of one nested value
+func (r *FlattenReader) flatten(v interface{}) ([]Entry, error) {
+	if len(r.explode) == 0 {
+		row := make([]interface{}, len(r.f.Columns))
+		for i, c := range r.f.Columns {
+			row[i] = resolveFlattenPath(v, c.Path)
+		}
+		return []Entry{{Index: r.read, Value: row}}, nil
+	}
+
+	var elems []interface{}
+	arr, _ := resolvePointer(v, r.explode)
+	switch t := arr.(type) {
+	case nil:
+	case []interface{}:
+		elems = t
+	default:
+		return nil, fmt.Errorf("entry %d: can't explode %s, expected an array, got: %T", r.entries, r.f.Explode, t)
+	}
+
+	n := len(elems)
+	if n == 0 {
+		n = 1
+	}
+	rows := make([]Entry, n)
+	for j := range rows {
+		row := make([]interface{}, len(r.f.Columns)+1)
+		row[0] = r.entries
+		for i, c := range r.f.Columns {
+			switch {
+			case !c.Element:
+				row[i+1] = resolveFlattenPath(v, c.Path)
+			case j < len(elems):
+				row[i+1] = resolveFlattenPath(elems[j], c.Path)
+			}
+		}
+		rows[j] = Entry{Index: r.read + j, Value: row}
+	}
+	return rows, nil
+}
+
+// EntriesRead gives the number of flattened entries returned by the reader
+func (r *FlattenReader) EntriesRead() int {
+	return r.read
+}
+
+// Close closes the wrapped reader
+func (r *FlattenReader) Close() error {
+	return r.Reader.Close()
+}
+
+// UnflattenStructure gives the structure of the nested entries a flattened
+// body holds, reading the flattening recorded in st. The nested schema is
+// built from the schemas of flattened columns. Unflattened bodies are json
+func UnflattenStructure(st *dataset.Structure) (*dataset.Structure, error) {
+	f, explode, err := structureFlattening(st)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	cols, _ := schemaColumns(st)
+	if f.Explode != "" && len(cols) > 0 {
+		cols = cols[1:]
+	}
+
+	var entry, element map[string]interface{}
+	for i, c := range f.Columns {
+		sch := map[string]interface{}{}
+		if i < len(cols) {
+			if col, ok := cols[i].(map[string]interface{}); ok {
+				for k, v := range col {
+					if k != "title" {
+						sch[k] = v
+					}
+				}
+			}
+		}
+		if c.Element {
+			element = setFlattenSchema(element, c.Path, sch)
+		} else {
+			entry = setFlattenSchema(entry, c.Path, sch)
+		}
+	}
+	if len(explode) > 0 {
+		arr := map[string]interface{}{"type": "array"}
+		if element != nil {
+			arr["items"] = element
+		}
+		path := make([]interface{}, len(explode))
+		for i, tok := range explode {
+			path[i] = tok
+		}
+		entry = setFlattenSchema(entry, path, arr)
+	}
+	if entry == nil {
+		entry = map[string]interface{}{}
+	}
+
+	return &dataset.Structure{
+		Qri:    st.Qri,
+		Format: dataset.JSONDataFormat.String(),
+		Schema: map[string]interface{}{"type": "array", "items": entry},
+	}, nil
+}
+
+// setFlattenSchema places the schema of a value at path in the schema of a
+// nested value, adding properties & array items along the way
+func setFlattenSchema(sch map[string]interface{}, path []interface{}, val map[string]interface{}) map[string]interface{} {
+	if len(path) == 0 {
+		if sch == nil {
+			return val
+		}
+		return sch
+	}
+	if sch == nil {
+		sch = map[string]interface{}{}
+	}
+	switch key := path[0].(type) {
+	case string:
+		sch["type"] = "object"
+		props, _ := sch["properties"].(map[string]interface{})
+		if props == nil {
+			props = map[string]interface{}{}
+			sch["properties"] = props
+		}
+		sub, _ := props[key].(map[string]interface{})
+		props[key] = setFlattenSchema(sub, path[1:], val)
+	case int:
+		sch["type"] = "array"
+		items, _ := sch["items"].([]interface{})
+		for len(items) <= key {
+			items = append(items, nil)
+		}
+		sub, _ := items[key].(map[string]interface{})
+		items[key] = setFlattenSchema(sub, path[1:], val)
+		for i, it := range items {
+			if it == nil {
+				items[i] = map[string]interface{}{}
+			}
+		}
+		sch["items"] = items
+	}
+	return sch
+}
+
+// UnflattenReader wraps a reader of a flattened body, rebuilding the nested
+// entries it was flattened from. Rows can be arrays of column values or
+// objects keyed by column title. Nulls & empty strings are left out of
+// rebuilt entries, formats like csv write both as empty fields, so treating
+// them as missing values keeps absent array elements & properties absent.
+// Consecutive rows with the same entry column value are combined into one
+// entry, a single row with no element values gives an empty array
+type UnflattenReader struct {
+	Reader  EntryReader
+	st      *dataset.Structure
+	f       *dataset.Flattening
+	explode []string
+	// next holds a row read past the end of an exploded entry
+	next []interface{}
+	done bool
+	read int
+}
+
+var _ EntryReader = (*UnflattenReader)(nil)
+
+// NewUnflattenReader creates a reader unflattening the entries of r, using
+// the flattening recorded in r's structure
+func NewUnflattenReader(r EntryReader) (*UnflattenReader, error) {
+	st, err := UnflattenStructure(r.Structure())
+	if err != nil {
+		return nil, err
+	}
+	f, explode, err := structureFlattening(r.Structure())
+	if err != nil {
+		return nil, err
+	}
+	return &UnflattenReader{Reader: r, st: st, f: f, explode: explode}, nil
+}
+
+// Structure gives the unflattened structure
+func (r *UnflattenReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry returns the next unflattened entry
+func (r *UnflattenReader) ReadEntry() (Entry, error) {
+	row := r.next
+	r.next = nil
+	if row == nil {
+		if r.done {
+			return Entry{}, io.EOF
+		}
+		var err error
+		if row, err = r.readRow(); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	v := r.build(row, false)
+	if len(r.explode) > 0 {
+		var elems []interface{}
+		for {
+			elems = append(elems, r.build(row, true))
+			next, err := r.readRow()
+			if err == io.EOF {
+				r.done = true
+				break
+			} else if err != nil {
+				return Entry{}, err
+			}
+			if fmt.Sprint(next[0]) != fmt.Sprint(row[0]) {
+				r.next = next
+				break
+			}
+			row = next
+		}
+		if len(elems) == 1 && elems[0] == nil {
+			elems = []interface{}{}
+		}
+		v = setFlattenPointer(v, r.explode, elems)
+	}
+
+	ent := Entry{Index: r.read, Value: v}
+	r.read++
+	return ent, nil
+}
+
+// readRow reads the column values of the next flattened entry
+func (r *UnflattenReader) readRow() ([]interface{}, error) {
+	ent, err := r.Reader.ReadEntry()
+	if err != nil {
+		return nil, err
+	}
+	n := len(r.f.Columns)
+	if len(r.explode) > 0 {
+		n++
+	}
+	switch t := ent.Value.(type) {
+	case []interface{}:
+		if len(t) < n {
+			row := make([]interface{}, n)
+			copy(row, t)
+			return row, nil
+		}
+		return t, nil
+	case map[string]interface{}:
+		row := make([]interface{}, 0, n)
+		if len(r.explode) > 0 {
+			row = append(row, t[r.f.EntryColumn])
+		}
+		for _, c := range r.f.Columns {
+			row = append(row, t[c.Title])
+		}
+		return row, nil
+	default:
+		return nil, fmt.Errorf("entry %d: expected an array or object row, got: %T", r.read, ent.Value)
+	}
+}
+
+// build sets the values of entry or element columns of a row in a new value
+func (r *UnflattenReader) build(row []interface{}, element bool) interface{} {
+	offset := 0
+	if len(r.explode) > 0 {
+		offset = 1
+	}
+	var v interface{}
+	for i, c := range r.f.Columns {
+		if c.Element != element {
+			continue
+		}
+		val := row[i+offset]
+		if val == nil || val == "" {
+			continue
+		}
+		v = setFlattenPath(v, c.Path, val)
+	}
+	return v
+}
+
+// setFlattenPath sets val at path in v, creating objects & arrays along the
+// way. Values that aren't the container a path expects are left as-is
+func setFlattenPath(v interface{}, path []interface{}, val interface{}) interface{} {
+	if len(path) == 0 {
+		if v == nil {
+			return val
+		}
+		return v
+	}
+	switch key := path[0].(type) {
+	case string:
+		if v == nil {
+			v = map[string]interface{}{}
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		m[key] = setFlattenPath(m[key], path[1:], val)
+	case int:
+		if v == nil {
+			v = []interface{}{}
+		}
+		arr, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		for len(arr) <= key {
+			arr = append(arr, nil)
+		}
+		arr[key] = setFlattenPath(arr[key], path[1:], val)
+		return arr
+	}
+	return v
+}
+
+// setFlattenPointer sets val at the location pointer tokens refer to in v,
+// creating objects for missing parents
+func setFlattenPointer(v interface{}, tokens []string, val interface{}) interface{} {
+	if len(tokens) == 0 {
+		return val
+	}
+	switch t := v.(type) {
+	case nil:
+		return map[string]interface{}{tokens[0]: setFlattenPointer(nil, tokens[1:], val)}
+	case map[string]interface{}:
+		t[tokens[0]] = setFlattenPointer(t[tokens[0]], tokens[1:], val)
+	case []interface{}:
+		if idx, ok := pointerIndex(tokens[0]); ok && idx < len(t) {
+			t[idx] = setFlattenPointer(t[idx], tokens[1:], val)
+		}
+	}
+	return v
+}
+
+// EntriesRead gives the number of unflattened entries returned by the reader
+func (r *UnflattenReader) EntriesRead() int {
+	return r.read
+}
+
+// Close closes the wrapped reader
+func (r *UnflattenReader) Close() error {
+	return r.Reader.Close()
+}
@@ -0,0 +1,273 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+var flattenSource = &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+
+const flattenSourceBody = `[
+	{"id": 1, "user": {"name": "a", "tags": ["x", "y"]}, "orders": [{"sku": "s1", "qty": 2}, {"sku": "s2", "qty": 1.5}]},
+	{"id": 2, "user": {"name": "b", "tags": []}, "orders": []},
+	{"id": 3, "user": {"name": "", "tags": ["z"]}}
+]`
+
+// flattenCSV scans the source body, flattening it into a csv body
+func flattenCSV(t *testing.T, configs ...func(cfg *FlattenConfig)) (*dataset.Structure, string) {
+	t.Helper()
+	r, err := NewJSONReader(flattenSource, strings.NewReader(flattenSourceBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fst, err := ScanFlattenStructure(r, configs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = NewJSONReader(flattenSource, strings.NewReader(flattenSourceBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr, err := NewFlattenReader(r, fst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewEntryWriter(fst, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := EachEntry(fr, func(_ int, ent Entry, err error) error {
+		if err != nil {
+			return err
+		}
+		return w.WriteEntry(ent)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return fst, buf.String()
+}
+
+// unflattenCSV reads a flattened csv body back into json
+func unflattenCSV(t *testing.T, st *dataset.Structure, body string) string {
+	t.Helper()
+	// the flattening is read from the recorded format config
+	data, err := json.Marshal(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rst := &dataset.Structure{}
+	if err := json.Unmarshal(data, rst); err != nil {
+		t.Fatal(err)
+	}
+
+	ur, err := NewUnflattenReader(NewCSVReader(rst, strings.NewReader(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vals []interface{}
+	for {
+		ent, err := ur.ReadEntry()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		vals = append(vals, ent.Value)
+	}
+	out, err := json.Marshal(vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestFlattenIndexed(t *testing.T) {
+	st, body := flattenCSV(t)
+	expect := `id,orders.0.qty,orders.0.sku,orders.1.qty,orders.1.sku,user.name,user.tags.0,user.tags.1
+1,2,s1,1.5,s2,a,x,y
+2,,,,,b,,
+3,,,,,,z,
+`
+	if body != expect {
+		t.Errorf("body mismatch.\nexpected:\n%s\ngot:\n%s", expect, body)
+	}
+	types := []interface{}{}
+	cols, _ := schemaColumns(st)
+	for _, c := range cols {
+		types = append(types, c.(map[string]interface{})["type"])
+	}
+	if !reflect.DeepEqual(types, []interface{}{"integer", "integer", "string", "number", "string", "string", "string", "string"}) {
+		t.Errorf("unexpected column types: %v", types)
+	}
+
+	got := unflattenCSV(t, st, body)
+	expectJSON := `[{"id":1,"orders":[{"qty":2,"sku":"s1"},{"qty":1.5,"sku":"s2"}],"user":{"name":"a","tags":["x","y"]}},{"id":2,"user":{"name":"b"}},{"id":3,"user":{"tags":["z"]}}]`
+	if got != expectJSON {
+		t.Errorf("unflattened mismatch.\nexpected: %s\ngot:      %s", expectJSON, got)
+	}
+}
+
+func TestFlattenExplode(t *testing.T) {
+	st, body := flattenCSV(t, func(cfg *FlattenConfig) {
+		cfg.Explode = "/orders"
+		cfg.Separator = "_"
+	})
+	expect := `_entry,id,orders_qty,orders_sku,user_name,user_tags_0,user_tags_1
+0,1,2,s1,a,x,y
+0,1,1.5,s2,a,x,y
+1,2,,,b,,
+2,3,,,,z,
+`
+	if body != expect {
+		t.Errorf("body mismatch.\nexpected:\n%s\ngot:\n%s", expect, body)
+	}
+
+	got := unflattenCSV(t, st, body)
+	expectJSON := `[{"id":1,"orders":[{"qty":2,"sku":"s1"},{"qty":1.5,"sku":"s2"}],"user":{"name":"a","tags":["x","y"]}},{"id":2,"orders":[],"user":{"name":"b"}},{"id":3,"orders":[],"user":{"tags":["z"]}}]`
+	if got != expectJSON {
+		t.Errorf("unflattened mismatch.\nexpected: %s\ngot:      %s", expectJSON, got)
+	}
+}
+
+func TestFlattenStructure(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{"type": "string"},
+					"point": map[string]interface{}{
+						"type":  "array",
+						"items": []interface{}{map[string]interface{}{"type": "number"}, map[string]interface{}{"type": "number"}},
+					},
+					"scores": map[string]interface{}{
+						"type":     "array",
+						"items":    map[string]interface{}{"type": "integer"},
+						"maxItems": 2,
+					},
+					"extra": map[string]interface{}{"type": "object"},
+				},
+			},
+		},
+	}
+	fst, err := FlattenStructure(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(fst.Schema)
+	expect := `{"items":{"items":[{"title":"extra","type":"object"},{"title":"name","type":"string"},{"title":"point.0","type":"number"},{"title":"point.1","type":"number"},{"title":"scores.0","type":"integer"},{"title":"scores.1","type":"integer"}],"type":"array"},"type":"array"}`
+	if string(data) != expect {
+		t.Errorf("schema mismatch.\nexpected: %s\ngot:      %s", expect, data)
+	}
+	if fst.Format != "csv" || fst.FormatConfig["headerRow"] != true {
+		t.Errorf("expected a csv structure with a header row, got: %s %v", fst.Format, fst.FormatConfig)
+	}
+
+	ust, err := UnflattenStructure(fst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = json.Marshal(ust.Schema)
+	expect = `{"items":{"properties":{"extra":{"type":"object"},"name":{"type":"string"},"point":{"items":[{"type":"number"},{"type":"number"}],"type":"array"},"scores":{"items":[{"type":"integer"},{"type":"integer"}],"type":"array"}},"type":"object"},"type":"array"}`
+	if string(data) != expect {
+		t.Errorf("unflattened schema mismatch.\nexpected: %s\ngot:      %s", expect, data)
+	}
+
+	r, err := NewJSONReader(st, strings.NewReader(`[{"name": "a", "point": [1.5, 2], "scores": [3], "extra": {"k": "v"}}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr, err := NewFlattenReader(r, fst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := fr.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := []interface{}{map[string]interface{}{"k": "v"}, "a", 1.5, 2, 3, nil}
+	if !reflect.DeepEqual(ent.Value, row) {
+		t.Errorf("row mismatch.\nexpected: %#v\ngot:      %#v", row, ent.Value)
+	}
+	if _, err := fr.ReadEntry(); err != io.EOF {
+		t.Errorf("expected EOF, got: %v", err)
+	}
+}
+
+func TestUnflattenObjectRows(t *testing.T) {
+	f := &dataset.Flattening{Separator: ".", Columns: []dataset.FlattenColumn{
+		{Title: "a.b", Path: []interface{}{"a", "b"}},
+		{Title: "a.c.1", Path: []interface{}{"a", "c", 1}},
+	}}
+	st := &dataset.Structure{
+		Format:       "json",
+		FormatConfig: map[string]interface{}{dataset.FlattenConfigKey: f.Map()},
+		Schema:       dataset.BaseSchemaArray,
+	}
+	r, err := NewJSONReader(st, strings.NewReader(`[{"a.b": 1, "a.c.1": true}, {"a.b": null}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ur, err := NewUnflattenReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []interface{}{
+		map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": []interface{}{nil, true}}},
+		nil,
+	}
+	for i, e := range expect {
+		ent, err := ur.ReadEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(e, ent.Value) {
+			t.Errorf("entry %d mismatch.\nexpected: %#v\ngot:      %#v", i, e, ent.Value)
+		}
+	}
+	if ur.EntriesRead() != 2 {
+		t.Errorf("expected 2 entries read, got: %d", ur.EntriesRead())
+	}
+}
+
+func TestFlattenErrors(t *testing.T) {
+	if _, err := FlattenStructure(flattenSource, func(cfg *FlattenConfig) { cfg.Explode = "orders" }); err == nil || err.Error() != `invalid JSON pointer "orders": must be empty or start with '/'` {
+		t.Errorf("expected pointer error, got: %v", err)
+	}
+
+	r, err := NewJSONReader(flattenSource, strings.NewReader(flattenSourceBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFlattenReader(r, flattenSource); err == nil || err.Error() != "structure has no flattening" {
+		t.Errorf("expected missing flattening error, got: %v", err)
+	}
+	if _, err := NewUnflattenReader(r); err == nil || err.Error() != "structure has no flattening" {
+		t.Errorf("expected missing flattening error, got: %v", err)
+	}
+
+	fst, err := FlattenStructure(flattenSource, func(cfg *FlattenConfig) { cfg.Explode = "/user" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr, err := NewFlattenReader(r, fst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fr.ReadEntry(); err == nil || err.Error() != "entry 0: can't explode /user, expected an array, got: map[string]interface {}" {
+		t.Errorf("expected explode error, got: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package dsio
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/qri-io/dataset"
+)
+
+// floatFormatter renders floats with a writer's float formatting options.
+// a nil formatter gives default formatting
+type floatFormatter struct {
+	dataset.FloatFormat
+	// columns are the decimal places of each tabular column, -1 for columns
+	// without fixed decimal places
+	columns []int
+}
+
+// newFloatFormatter creates a formatter for a structure's columns, nil if ff
+// uses default formatting
+func newFloatFormatter(st *dataset.Structure, ff dataset.FloatFormat) *floatFormatter {
+	titles, _, _ := terribleHackToGetHeaderRowAndTypes(st)
+	return newTitledFloatFormatter(titles, ff)
+}
+
+// newTitledFloatFormatter creates a formatter for columns with the given
+// titles, nil if ff uses default formatting
+func newTitledFloatFormatter(titles []string, ff dataset.FloatFormat) *floatFormatter {
+	if ff.IsEmpty() {
+		return nil
+	}
+	f := &floatFormatter{FloatFormat: ff}
+	if len(ff.DecimalPlaces) > 0 && titles != nil {
+		f.columns = make([]int, len(titles))
+		for i, title := range titles {
+			f.columns[i] = -1
+			if n, ok := ff.DecimalPlaces[title]; ok {
+				f.columns[i] = n
+			}
+		}
+	}
+	return f
+}
+
+// format renders a float, fixed to decimals places when decimals isn't -1
+func (f *floatFormatter) format(v float64, decimals int) string {
+	if f == nil {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	if decimals >= 0 {
+		return strconv.FormatFloat(v, 'f', decimals, 64)
+	}
+
+	if f.SignificantDigits > 0 {
+		// round by formatting, reading the rounded value back gives the
+		// shortest representation of the rounded number
+		v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'e', f.SignificantDigits-1, 64), 64)
+	}
+	if f.ScientificThreshold > 0 && v != 0 {
+		exp := math.Floor(math.Log10(math.Abs(v)))
+		if exp >= float64(f.ScientificThreshold) || exp < -float64(f.ScientificThreshold) {
+			return strconv.FormatFloat(v, 'e', -1, 64)
+		}
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// column renders a float in a tabular column
+func (f *floatFormatter) column(i int, v float64) string {
+	if f != nil && i < len(f.columns) {
+		return f.format(v, f.columns[i])
+	}
+	return f.format(v, -1)
+}
+
+// property renders a float in an object property
+func (f *floatFormatter) property(key string, v float64) string {
+	if f != nil {
+		if n, ok := f.DecimalPlaces[key]; ok {
+			return f.format(v, n)
+		}
+	}
+	return f.format(v, -1)
+}
+
+// jsonValue replaces floats in an entry value with formatted json.Numbers.
+// columns of tabular entries & properties of object entries get their fixed
+// decimal places, nested values are formatted without
+func (f *floatFormatter) jsonValue(v interface{}, top bool) interface{} {
+	switch t := v.(type) {
+	case float64:
+		if math.IsNaN(t) || math.IsInf(t, 0) {
+			// left for the encoder to reject
+			return t
+		}
+		return json.Number(f.format(t, -1))
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, x := range t {
+			if fl, ok := x.(float64); ok && top && !math.IsNaN(fl) && !math.IsInf(fl, 0) {
+				out[i] = json.Number(f.column(i, fl))
+				continue
+			}
+			out[i] = f.jsonValue(x, false)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for key, x := range t {
+			if fl, ok := x.(float64); ok && top && !math.IsNaN(fl) && !math.IsInf(fl, 0) {
+				out[key] = json.Number(f.property(key, fl))
+				continue
+			}
+			out[key] = f.jsonValue(x, false)
+		}
+		return out
+	default:
+		return v
+	}
+}
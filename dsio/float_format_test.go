@@ -0,0 +1,120 @@
+package dsio
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+// noisy is 0.1+0.2 computed at run time, constant expressions are exact
+var noisy = func(a, b float64) float64 { return a + b }(0.1, 0.2)
+
+func TestFloatFormatterFormat(t *testing.T) {
+	cases := []struct {
+		ff       dataset.FloatFormat
+		v        float64
+		decimals int
+		expect   string
+	}{
+		{dataset.FloatFormat{}, noisy, -1, "0.30000000000000004"},
+		{dataset.FloatFormat{SignificantDigits: 15}, noisy, -1, "0.3"},
+		{dataset.FloatFormat{SignificantDigits: 3}, 123456, -1, "123000"},
+		{dataset.FloatFormat{SignificantDigits: 3}, 0.000123456, -1, "0.000123"},
+		{dataset.FloatFormat{SignificantDigits: 3}, 2.5, 2, "2.50"},
+		{dataset.FloatFormat{ScientificThreshold: 6}, 123456789, -1, "1.23456789e+08"},
+		{dataset.FloatFormat{ScientificThreshold: 6}, 123456, -1, "123456"},
+		{dataset.FloatFormat{ScientificThreshold: 6}, 0.0000001, -1, "1e-07"},
+		{dataset.FloatFormat{ScientificThreshold: 6}, 0.000001, -1, "0.000001"},
+		{dataset.FloatFormat{ScientificThreshold: 6}, 0, -1, "0"},
+		{dataset.FloatFormat{SignificantDigits: 2, ScientificThreshold: 3}, 98765, -1, "9.9e+04"},
+		{dataset.FloatFormat{SignificantDigits: 2}, math.Inf(1), -1, "+Inf"},
+	}
+
+	for i, c := range cases {
+		f := newFloatFormatter(&dataset.Structure{}, c.ff)
+		if got := f.format(c.v, c.decimals); got != c.expect {
+			t.Errorf("case %d mismatch. expected: %s, got: %s", i, c.expect, got)
+		}
+	}
+}
+
+func floatStructure(format string, cfg map[string]interface{}) *dataset.Structure {
+	return &dataset.Structure{
+		Format:       format,
+		FormatConfig: cfg,
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "item", "type": "string"},
+					map[string]interface{}{"title": "price", "type": "number"},
+					map[string]interface{}{"title": "ratio", "type": "number"},
+				},
+			},
+		},
+	}
+}
+
+func TestFloatFormatWriters(t *testing.T) {
+	rows := []Entry{
+		{Index: 0, Value: []interface{}{"a", 2.5, noisy}},
+		{Index: 1, Value: []interface{}{"b", 10.0, 1.0 / 3}},
+	}
+	cfg := map[string]interface{}{
+		"significantDigits": float64(4),
+		"decimalPlaces":     map[string]interface{}{"price": float64(2)},
+	}
+	cases := []struct {
+		st     *dataset.Structure
+		expect string
+	}{
+		{floatStructure("csv", cfg), "a,2.50,0.3\nb,10.00,0.3333\n"},
+		{floatStructure("csv", nil), "a,2.5,0.30000000000000004\nb,10,0.3333333333333333\n"},
+		{floatStructure("json", cfg), `[["a",2.50,0.3],["b",10.00,0.3333]]`},
+		{floatStructure("json", nil), `[["a",2.5,0.30000000000000004],["b",10,0.3333333333333333]]`},
+	}
+
+	for i, c := range cases {
+		buf := &bytes.Buffer{}
+		w, err := NewEntryWriter(c.st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ent := range rows {
+			if err := w.WriteEntry(ent); err != nil {
+				t.Fatalf("case %d: %s", i, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != c.expect {
+			t.Errorf("case %d output mismatch.\nexpected: %s\ngot:      %s", i, c.expect, buf.String())
+		}
+	}
+
+	// object entries match decimal places by property name, nested values
+	// aren't fixed
+	st := &dataset.Structure{
+		Format:       "json",
+		FormatConfig: map[string]interface{}{"decimalPlaces": map[string]interface{}{"price": float64(1)}},
+		Schema:       dataset.BaseSchemaArray,
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewJSONWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Value: map[string]interface{}{"price": 3.0, "sub": map[string]interface{}{"price": 4.0}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if expect := `[{"price":3.0,"sub":{"price":4}}]`; buf.String() != expect {
+		t.Errorf("object output mismatch.\nexpected: %s\ngot:      %s", expect, buf.String())
+	}
+}
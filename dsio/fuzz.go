@@ -2,7 +2,9 @@ package dsio
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/qri-io/dataset"
 )
@@ -18,7 +20,7 @@ func Fuzz(data []byte) int {
 	for {
 		_, err = reader.ReadEntry()
 		if err != nil {
-			if err.Error() == "EOF" {
+			if errors.Is(err, io.EOF) {
 				break
 			}
 			fmt.Printf("Error: %s\n", err.Error())
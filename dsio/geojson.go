@@ -0,0 +1,208 @@
+package dsio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+)
+
+// GeoJSONReader implements the EntryReader interface for GeoJSON
+// FeatureCollections, streaming each member of the collection's features
+// array as an entry without loading the whole document. Entry values are
+// objects with "geometry" & "properties" keys, and an "id" key for features
+// that have one. GeoJSON bodies must have a top-level array schema
+type GeoJSONReader struct {
+	st          *dataset.Structure
+	src         *TrackedReader
+	dec         *json.Decoder
+	state       geoJSONState
+	entriesRead int
+}
+
+// geoJSONState tracks a GeoJSONReader's position in the document
+type geoJSONState int
+
+const (
+	geoJSONStart geoJSONState = iota
+	geoJSONFeatures
+	geoJSONDone
+)
+
+var _ EntryReader = (*GeoJSONReader)(nil)
+
+// NewGeoJSONReader creates a reader from a structure and read source
+func NewGeoJSONReader(st *dataset.Structure, r io.Reader) (*GeoJSONReader, error) {
+	if st.Schema == nil {
+		err := newKindError(ErrBadSchema, "schema required for GeoJSON")
+		log.Debug(err.Error())
+		return nil, err
+	}
+	tlt, err := GetTopLevelType(st)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	if tlt != "array" {
+		err := newKindError(ErrBadSchema, "GeoJSON requires a top-level array schema")
+		log.Debug(err.Error())
+		return nil, err
+	}
+	src := NewTrackedReader(r)
+	dec := json.NewDecoder(src)
+	dec.UseNumber()
+	return &GeoJSONReader{st: st, src: src, dec: dec}, nil
+}
+
+// Structure gives this reader's structure
+func (r *GeoJSONReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry reads one feature from the reader
+func (r *GeoJSONReader) ReadEntry() (Entry, error) {
+	ent, err := r.readEntry()
+	return ent, parseError("geojson", r.entriesRead, err)
+}
+
+func (r *GeoJSONReader) readEntry() (Entry, error) {
+	switch r.state {
+	case geoJSONStart:
+		if err := r.openFeatures(); err != nil {
+			return Entry{}, err
+		}
+	case geoJSONDone:
+		return Entry{}, io.EOF
+	}
+
+	if !r.dec.More() {
+		// consume the closing bracket of the features array & check the rest
+		// of the collection
+		if _, err := r.dec.Token(); err != nil {
+			return Entry{}, r.syntaxError(err)
+		}
+		if _, err := r.scanMembers(); err != nil {
+			return Entry{}, err
+		}
+		r.state = geoJSONDone
+		return Entry{}, io.EOF
+	}
+
+	var v interface{}
+	if err := r.dec.Decode(&v); err != nil {
+		return Entry{}, r.syntaxError(err)
+	}
+	feature, ok := v.(map[string]interface{})
+	if !ok {
+		return Entry{}, newKindError(ErrFormatMismatch, fmt.Sprintf("expected a Feature object, got: %T", v))
+	}
+	if t, _ := feature["type"].(string); t != "Feature" {
+		return Entry{}, newKindError(ErrFormatMismatch, fmt.Sprintf("expected a Feature, got type: %v", feature["type"]))
+	}
+
+	val := map[string]interface{}{
+		"geometry":   ndjsonValue(feature["geometry"]),
+		"properties": ndjsonValue(feature["properties"]),
+	}
+	if id, ok := feature["id"]; ok {
+		val["id"] = ndjsonValue(id)
+	}
+	ent := Entry{Index: r.entriesRead, Value: val}
+	r.entriesRead++
+	return ent, nil
+}
+
+// openFeatures reads the start of the FeatureCollection, up to the opening
+// bracket of the features array
+func (r *GeoJSONReader) openFeatures() error {
+	tok, err := r.dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			return newKindError(ErrFormatMismatch, "empty GeoJSON document")
+		}
+		return r.syntaxError(err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return newKindError(ErrFormatMismatch, "GeoJSON must be a FeatureCollection object")
+	}
+	found, err := r.scanMembers()
+	if err != nil {
+		return err
+	}
+	if !found {
+		return newKindError(ErrFormatMismatch, "FeatureCollection has no features")
+	}
+	if tok, err = r.dec.Token(); err != nil {
+		return r.syntaxError(err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return newKindError(ErrFormatMismatch, "FeatureCollection features must be an array")
+	}
+	r.state = geoJSONFeatures
+	return nil
+}
+
+// scanMembers reads members of the collection object, checking it's type &
+// skipping foreign members. scanMembers stops before the value of a features
+// member, reporting true, or after the end of the object
+func (r *GeoJSONReader) scanMembers() (features bool, err error) {
+	for r.dec.More() {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return false, r.syntaxError(err)
+		}
+		switch tok {
+		case "features":
+			if r.state != geoJSONStart {
+				return false, newKindError(ErrFormatMismatch, "FeatureCollection has more than one features member")
+			}
+			return true, nil
+		case "type":
+			var t interface{}
+			if err := r.dec.Decode(&t); err != nil {
+				return false, r.syntaxError(err)
+			}
+			if t != "FeatureCollection" {
+				return false, newKindError(ErrFormatMismatch, fmt.Sprintf("expected a FeatureCollection, got type: %v", t))
+			}
+		default:
+			var skip json.RawMessage
+			if err := r.dec.Decode(&skip); err != nil {
+				return false, r.syntaxError(err)
+			}
+		}
+	}
+	// consume the closing brace
+	if _, err := r.dec.Token(); err != nil {
+		return false, r.syntaxError(err)
+	}
+	return false, nil
+}
+
+// syntaxError reports invalid JSON, keeping errors from the source as-is
+func (r *GeoJSONReader) syntaxError(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if _, ok := err.(*json.SyntaxError); ok || err == io.ErrUnexpectedEOF {
+		return newKindError(ErrFormatMismatch, fmt.Sprintf("invalid GeoJSON: %s", err))
+	}
+	return err
+}
+
+// EntriesRead gives the number of features read
+func (r *GeoJSONReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes parsed
+func (r *GeoJSONReader) BytesProcessed() int64 {
+	return r.dec.InputOffset()
+}
+
+// Close finalizes the reader, closing the source if it's an io.Closer. wrap
+// the source with KeepOpen to leave it open
+func (r *GeoJSONReader) Close() error {
+	return r.src.Close()
+}
@@ -0,0 +1,143 @@
+package dsio
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+var geoJSONStructure = &dataset.Structure{
+	Format: "geojson",
+	Schema: dataset.BaseSchemaArray,
+}
+
+func TestGeoJSONReader(t *testing.T) {
+	data := `{
+  "bbox": [-10, -10, 10, 10],
+  "features": [
+    {"type": "Feature", "id": "a", "geometry": {"type": "Point", "coordinates": [102.5, 0.5]}, "properties": {"name": "dinagat", "pop": 12}},
+    {"type": "Feature", "geometry": null, "properties": null},
+    {"type": "Feature", "id": 3, "geometry": {"type": "LineString", "coordinates": [[1, 2], [3, 4]]}, "properties": {"name": "line", "pop": 2.5}}
+  ],
+  "type": "FeatureCollection",
+  "crs": {"type": "name"}
+}`
+	r, err := NewEntryReader(geoJSONStructure, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"id":         "a",
+			"geometry":   map[string]interface{}{"type": "Point", "coordinates": []interface{}{102.5, 0.5}},
+			"properties": map[string]interface{}{"name": "dinagat", "pop": 12},
+		},
+		map[string]interface{}{"geometry": nil, "properties": nil},
+		map[string]interface{}{
+			"id":         3,
+			"geometry":   map[string]interface{}{"type": "LineString", "coordinates": []interface{}{[]interface{}{1, 2}, []interface{}{3, 4}}},
+			"properties": map[string]interface{}{"name": "line", "pop": 2.5},
+		},
+	}
+	for i, ex := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("entry %d unexpected error: %s", i, err)
+		}
+		if ent.Index != i {
+			t.Errorf("entry %d index mismatch, got: %d", i, ent.Index)
+		}
+		if !reflect.DeepEqual(ent.Value, ex) {
+			t.Errorf("entry %d mismatch. expected: %#v, got: %#v", i, ex, ent.Value)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected repeated io.EOF, got: %v", err)
+	}
+
+	gr := r.(*GeoJSONReader)
+	if gr.EntriesRead() != len(expect) {
+		t.Errorf("expected %d entries read, got: %d", len(expect), gr.EntriesRead())
+	}
+	if gr.BytesProcessed() != int64(len(data)) {
+		t.Errorf("expected %d bytes processed, got: %d", len(data), gr.BytesProcessed())
+	}
+}
+
+func TestGeoJSONReaderStreams(t *testing.T) {
+	// a reader should give the first feature before the rest of the collection
+	// is available
+	pr, pw := io.Pipe()
+	go func() {
+		pw.Write([]byte(`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":null,"properties":{"a":1}},`))
+	}()
+	r, err := NewGeoJSONReader(geoJSONStructure, pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{"geometry": nil, "properties": map[string]interface{}{"a": 1}}
+	if !reflect.DeepEqual(ent.Value, expect) {
+		t.Errorf("entry mismatch. expected: %#v, got: %#v", expect, ent.Value)
+	}
+	pw.Close()
+}
+
+func TestGeoJSONReaderErrors(t *testing.T) {
+	cases := []struct {
+		st   *dataset.Structure
+		data string
+		err  string
+	}{
+		{&dataset.Structure{Format: "geojson"}, "", "schema required for GeoJSON"},
+		{&dataset.Structure{Format: "geojson", Schema: dataset.BaseSchemaObject}, "", "GeoJSON requires a top-level array schema"},
+		{geoJSONStructure, "", "empty GeoJSON document"},
+		{geoJSONStructure, `[]`, "GeoJSON must be a FeatureCollection object"},
+		{geoJSONStructure, `{"type":"Feature","geometry":null}`, "expected a FeatureCollection, got type: Feature"},
+		{geoJSONStructure, `{"type":"FeatureCollection"}`, "FeatureCollection has no features"},
+		{geoJSONStructure, `{"type":"FeatureCollection","features":{}}`, "FeatureCollection features must be an array"},
+		{geoJSONStructure, `{"features":[1]}`, "expected a Feature object, got: json.Number"},
+		{geoJSONStructure, `{"features":[{"type":"Point"}]}`, "expected a Feature, got type: Point"},
+		{geoJSONStructure, `{"features":[],"type":"Topology"}`, "expected a FeatureCollection, got type: Topology"},
+		{geoJSONStructure, `{"features":[],"features":[]}`, "FeatureCollection has more than one features member"},
+		{geoJSONStructure, `{"type":"FeatureCollection","features":[{"type":"Feature"`, "invalid GeoJSON: unexpected EOF"},
+		{geoJSONStructure, `{"features":[{nope}]}`, "invalid GeoJSON: invalid character 'n'"},
+	}
+
+	for i, c := range cases {
+		r, err := NewGeoJSONReader(c.st, strings.NewReader(c.data))
+		if err == nil {
+			for err == nil {
+				_, err = r.ReadEntry()
+			}
+		}
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+
+	r, err := NewGeoJSONReader(geoJSONStructure, strings.NewReader(`{"features":[{"type":"Point"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); !errors.Is(err, ErrFormatMismatch) {
+		t.Errorf("expected ErrFormatMismatch, got: %v", err)
+	}
+}
+
+func TestGeoJSONWriter(t *testing.T) {
+	if _, err := NewEntryWriter(geoJSONStructure, io.Discard); err == nil || err.Error() != "geojson is a read-only format" {
+		t.Errorf("expected read-only error, got: %v", err)
+	}
+}
@@ -0,0 +1,591 @@
+package dsio
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// HTMLTableReader implements the EntryReader interface for tables in HTML
+// documents, reading each row of the table picked by the structure's
+// HTMLOptions as an entry. The document is streamed, only the current row
+// is held in memory.
+//
+// Cell text has whitespace collapsed & is cast to the types of the schema's
+// columns. cells spanning columns are repeated for each column they span.
+// tables nested in cells are read as text of the cell. HTML bodies must have
+// a top-level array schema
+type HTMLTableReader struct {
+	fieldDecoder
+	st       *dataset.Structure
+	src      *TrackedReader
+	z        *htmlTokenizer
+	selector []htmlCompound
+	table    int
+	header   bool
+
+	// stack is the open elements enclosing the current position, tracked
+	// until the table is found
+	stack   []htmlElement
+	matched int
+	inTable bool
+	// nested counts tables open inside the table being read
+	nested int
+	inRow  bool
+	row    []string
+	cell   *strings.Builder
+	span   int
+	done   bool
+
+	rowsRead    int
+	entriesRead int
+}
+
+var _ EntryReader = (*HTMLTableReader)(nil)
+
+// NewHTMLTableReader creates a reader from a structure and read source
+func NewHTMLTableReader(st *dataset.Structure, r io.Reader) (*HTMLTableReader, error) {
+	if st.Schema == nil {
+		err := newKindError(ErrBadSchema, "schema required for HTML reader")
+		log.Debug(err.Error())
+		return nil, err
+	}
+	if tlt, err := GetTopLevelType(st); err != nil {
+		return nil, err
+	} else if tlt != "array" {
+		return nil, newKindError(ErrBadSchema, "HTML requires a top-level array schema")
+	}
+	opts, err := dataset.NewHTMLOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	sel, err := parseHTMLSelector(opts.Selector)
+	if err != nil {
+		return nil, err
+	}
+	_, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+
+	src := NewTrackedReader(r)
+	return &HTMLTableReader{
+		fieldDecoder: fieldDecoder{types: types},
+		st:           st,
+		src:          src,
+		z:            &htmlTokenizer{r: bufio.NewReader(src)},
+		selector:     sel,
+		table:        opts.Table,
+		header:       opts.HeaderRow,
+	}, nil
+}
+
+// Structure gives this reader's structure
+func (r *HTMLTableReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry reads one row of the table
+func (r *HTMLTableReader) ReadEntry() (Entry, error) {
+	for {
+		row, err := r.readRow()
+		if err != nil {
+			return Entry{}, parseError("html", r.entriesRead, err)
+		}
+		r.rowsRead++
+		if r.header && r.rowsRead == 1 {
+			continue
+		}
+		vals, err := r.decode(row)
+		if err != nil {
+			return Entry{}, err
+		}
+		ent := Entry{Index: r.entriesRead, Value: vals}
+		r.entriesRead++
+		return ent, nil
+	}
+}
+
+// readRow gives the text of each cell of the next row
+func (r *HTMLTableReader) readRow() ([]string, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+	for {
+		tok, err := r.z.next()
+		if err == io.EOF {
+			r.done = true
+			if !r.inTable {
+				return nil, r.notFound()
+			}
+			if row := r.endRow(); row != nil {
+				return row, nil
+			}
+			return nil, io.EOF
+		} else if err != nil {
+			return nil, err
+		}
+
+		if !r.inTable {
+			r.findTable(tok)
+			continue
+		}
+
+		switch tok.typ {
+		case htmlText:
+			if r.cell != nil {
+				r.cell.WriteString(tok.text)
+			}
+		case htmlStartTag:
+			if tok.name == "table" {
+				r.nested++
+				continue
+			}
+			if r.nested > 0 {
+				continue
+			}
+			switch tok.name {
+			case "tr":
+				row := r.endRow()
+				r.inRow = true
+				if row != nil {
+					return row, nil
+				}
+			case "td", "th":
+				r.endCell()
+				r.inRow = true
+				r.cell = &strings.Builder{}
+				r.span = 1
+				if n, err := strconv.Atoi(tok.attrs["colspan"]); err == nil && n > 1 {
+					r.span = n
+				}
+			case "br":
+				if r.cell != nil {
+					r.cell.WriteByte(' ')
+				}
+			}
+		case htmlEndTag:
+			if tok.name == "table" {
+				if r.nested > 0 {
+					r.nested--
+					continue
+				}
+				r.done = true
+				if row := r.endRow(); row != nil {
+					return row, nil
+				}
+				return nil, io.EOF
+			}
+			if r.nested > 0 {
+				continue
+			}
+			switch tok.name {
+			case "td", "th":
+				r.endCell()
+			case "tr":
+				if row := r.endRow(); row != nil {
+					return row, nil
+				}
+			}
+		}
+	}
+}
+
+// findTable tracks open elements until the table to read starts
+func (r *HTMLTableReader) findTable(tok htmlToken) {
+	switch tok.typ {
+	case htmlStartTag:
+		if htmlVoidElements[tok.name] || tok.selfClosing {
+			return
+		}
+		r.stack = append(r.stack, newHTMLElement(tok))
+		if tok.name == "table" && matchHTMLSelector(r.selector, r.stack) {
+			if r.matched == r.table {
+				r.inTable = true
+			}
+			r.matched++
+		}
+	case htmlEndTag:
+		// end tags close every element opened since their start tag, which
+		// handles elements with optional end tags like <p> & <li>
+		for i := len(r.stack) - 1; i >= 0; i-- {
+			if r.stack[i].tag == tok.name {
+				r.stack = r.stack[:i]
+				break
+			}
+		}
+	}
+}
+
+// notFound gives the error for a document without the table to read
+func (r *HTMLTableReader) notFound() error {
+	if len(r.selector) > 0 {
+		return fmt.Errorf("html document has %d tables matching selector, can't read table %d", r.matched, r.table)
+	}
+	return fmt.Errorf("html document has %d tables, can't read table %d", r.matched, r.table)
+}
+
+// endCell adds the open cell to the row
+func (r *HTMLTableReader) endCell() {
+	if r.cell == nil {
+		return
+	}
+	text := strings.Join(strings.Fields(r.cell.String()), " ")
+	for i := 0; i < r.span; i++ {
+		r.row = append(r.row, text)
+	}
+	r.cell = nil
+}
+
+// endRow closes the open row, returning nil for rows without cells
+func (r *HTMLTableReader) endRow() []string {
+	r.endCell()
+	row := r.row
+	r.row = nil
+	r.inRow = false
+	return row
+}
+
+// Close finalizes the reader, closing the source if it's an io.Closer. wrap
+// the source with KeepOpen to leave it open
+func (r *HTMLTableReader) Close() error {
+	return r.src.Close()
+}
+
+// EntriesRead gives the number of entries read
+func (r *HTMLTableReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes parsed
+func (r *HTMLTableReader) BytesProcessed() int64 {
+	return int64(r.src.BytesRead() - r.z.r.Buffered())
+}
+
+// htmlVoidElements are elements that never have content or an end tag
+var htmlVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// htmlElement is an open element, with the attributes selectors match
+type htmlElement struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+func newHTMLElement(tok htmlToken) htmlElement {
+	return htmlElement{
+		tag:     tok.name,
+		id:      tok.attrs["id"],
+		classes: strings.Fields(tok.attrs["class"]),
+	}
+}
+
+// htmlCompound is one step of a selector, like table#prices.data
+type htmlCompound struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+// parseHTMLSelector reads a selector of compound steps separated by
+// descendant combinators
+func parseHTMLSelector(sel string) ([]htmlCompound, error) {
+	if strings.ContainsAny(sel, ">+~,[]:*()") {
+		return nil, fmt.Errorf("unsupported html selector '%s': only tags, ids, classes & descendants are supported", sel)
+	}
+	var steps []htmlCompound
+	for _, part := range strings.Fields(sel) {
+		c := htmlCompound{}
+		// split into the tag & each #id or .class
+		start := 0
+		for i := 1; i <= len(part); i++ {
+			if i < len(part) && part[i] != '#' && part[i] != '.' {
+				continue
+			}
+			tok := part[start:i]
+			switch {
+			case tok == "#" || tok == ".":
+				return nil, fmt.Errorf("invalid html selector '%s'", sel)
+			case tok[0] == '#':
+				c.id = tok[1:]
+			case tok[0] == '.':
+				c.classes = append(c.classes, tok[1:])
+			default:
+				c.tag = strings.ToLower(tok)
+			}
+			start = i
+		}
+		steps = append(steps, c)
+	}
+	return steps, nil
+}
+
+// matches reports whether an element matches a compound step
+func (c htmlCompound) matches(el htmlElement) bool {
+	if c.tag != "" && c.tag != el.tag || c.id != "" && c.id != el.id {
+		return false
+	}
+	for _, class := range c.classes {
+		found := false
+		for _, ec := range el.classes {
+			if ec == class {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchHTMLSelector reports whether the last element of stack matches sel,
+// with the preceding steps of sel matching it's ancestors in order
+func matchHTMLSelector(sel []htmlCompound, stack []htmlElement) bool {
+	if len(sel) == 0 {
+		return true
+	}
+	if !sel[len(sel)-1].matches(stack[len(stack)-1]) {
+		return false
+	}
+	step := len(sel) - 2
+	for i := len(stack) - 2; i >= 0 && step >= 0; i-- {
+		if sel[step].matches(stack[i]) {
+			step--
+		}
+	}
+	return step < 0
+}
+
+// htmlTokenType enumerates the tokens of a html document
+type htmlTokenType int
+
+const (
+	htmlText htmlTokenType = iota
+	htmlStartTag
+	htmlEndTag
+)
+
+// htmlToken is text or a tag. tag & attribute names are lower case, text &
+// attribute values have entities decoded
+type htmlToken struct {
+	typ         htmlTokenType
+	name        string
+	attrs       map[string]string
+	selfClosing bool
+	text        string
+}
+
+// htmlTokenizer splits a html document into tokens. It's forgiving the way
+// browsers are, skipping comments, doctypes & the content of script & style
+// elements, and reading stray '<' characters as text
+type htmlTokenizer struct {
+	r *bufio.Reader
+	// inTag is set when a '<' has been read but not the tag that follows
+	inTag bool
+	// raw is the name of a script or style element whose content is skipped
+	raw string
+}
+
+func (z *htmlTokenizer) next() (htmlToken, error) {
+	for {
+		if z.raw != "" {
+			return z.skipRawText()
+		}
+		if !z.inTag {
+			text, err := z.r.ReadString('<')
+			if err != nil {
+				if err == io.EOF && text != "" {
+					return htmlToken{typ: htmlText, text: html.UnescapeString(text)}, nil
+				}
+				return htmlToken{}, err
+			}
+			z.inTag = true
+			if len(text) > 1 {
+				return htmlToken{typ: htmlText, text: html.UnescapeString(text[:len(text)-1])}, nil
+			}
+		}
+		z.inTag = false
+		tok, ok, err := z.tag()
+		if err != nil || ok {
+			return tok, err
+		}
+	}
+}
+
+// tag reads the tag after a '<', ok is false for skipped markup
+func (z *htmlTokenizer) tag() (tok htmlToken, ok bool, err error) {
+	b, err := z.r.ReadByte()
+	if err != nil {
+		if err == io.EOF {
+			return htmlToken{typ: htmlText, text: "<"}, true, nil
+		}
+		return tok, false, err
+	}
+	switch {
+	case b == '!' || b == '?':
+		if p, _ := z.r.Peek(2); b == '!' && string(p) == "--" {
+			z.r.Discard(2)
+			return tok, false, z.skipPast("-->")
+		}
+		return tok, false, z.skipPast(">")
+	case b == '/':
+		name := z.readName()
+		if err := z.skipPast(">"); err != nil && err != io.EOF {
+			return tok, false, err
+		}
+		if name == "" {
+			return tok, false, nil
+		}
+		return htmlToken{typ: htmlEndTag, name: name}, true, nil
+	case isASCIILetter(b):
+		z.r.UnreadByte()
+		tok, err = z.startTag()
+		return tok, err == nil, err
+	default:
+		z.r.UnreadByte()
+		return htmlToken{typ: htmlText, text: "<"}, true, nil
+	}
+}
+
+// startTag reads a start tag & it's attributes
+func (z *htmlTokenizer) startTag() (htmlToken, error) {
+	tok := htmlToken{typ: htmlStartTag, name: z.readName(), attrs: map[string]string{}}
+	for {
+		b, err := z.skipSpace()
+		if err != nil {
+			if err == io.EOF {
+				return tok, nil
+			}
+			return tok, err
+		}
+		switch b {
+		case '>':
+			if (tok.name == "script" || tok.name == "style") && !tok.selfClosing {
+				z.raw = tok.name
+			}
+			return tok, nil
+		case '/':
+			tok.selfClosing = true
+			continue
+		}
+		tok.selfClosing = false
+		z.r.UnreadByte()
+
+		name := z.readUntil(func(b byte) bool { return isHTMLSpace(b) || b == '=' || b == '>' || b == '/' })
+		b, err = z.skipSpace()
+		if err != nil {
+			return tok, nil
+		}
+		if b != '=' {
+			z.r.UnreadByte()
+			tok.attrs[strings.ToLower(name)] = ""
+			continue
+		}
+		if b, err = z.skipSpace(); err != nil {
+			return tok, nil
+		}
+		var val string
+		if b == '"' || b == '\'' {
+			quote := b
+			val = z.readUntil(func(b byte) bool { return b == quote })
+			z.r.ReadByte()
+		} else {
+			z.r.UnreadByte()
+			val = z.readUntil(func(b byte) bool { return isHTMLSpace(b) || b == '>' })
+		}
+		tok.attrs[strings.ToLower(name)] = html.UnescapeString(val)
+	}
+}
+
+// skipRawText skips the content of a script or style element, returning
+// it's end tag
+func (z *htmlTokenizer) skipRawText() (htmlToken, error) {
+	name := z.raw
+	z.raw = ""
+	end := "/" + name
+	for {
+		if _, err := z.r.ReadString('<'); err != nil {
+			return htmlToken{}, err
+		}
+		p, _ := z.r.Peek(len(end))
+		if strings.EqualFold(string(p), end) {
+			z.r.Discard(len(end))
+			if err := z.skipPast(">"); err != nil && err != io.EOF {
+				return htmlToken{}, err
+			}
+			return htmlToken{typ: htmlEndTag, name: name}, nil
+		}
+	}
+}
+
+// readName reads a lower cased tag name
+func (z *htmlTokenizer) readName() string {
+	return strings.ToLower(z.readUntil(func(b byte) bool {
+		return !(isASCIILetter(b) || b >= '0' && b <= '9' || b == '-' || b == ':' || b == '_')
+	}))
+}
+
+// readUntil reads bytes until stop returns true, leaving the stop byte unread
+func (z *htmlTokenizer) readUntil(stop func(b byte) bool) string {
+	s := &strings.Builder{}
+	for {
+		b, err := z.r.ReadByte()
+		if err != nil {
+			return s.String()
+		}
+		if stop(b) {
+			z.r.UnreadByte()
+			return s.String()
+		}
+		s.WriteByte(b)
+	}
+}
+
+// skipSpace discards whitespace, returning the first byte after it
+func (z *htmlTokenizer) skipSpace() (byte, error) {
+	for {
+		b, err := z.r.ReadByte()
+		if err != nil || !isHTMLSpace(b) {
+			return b, err
+		}
+	}
+}
+
+// skipPast discards bytes up to & including the first occurrence of s
+func (z *htmlTokenizer) skipPast(s string) error {
+	last := s[len(s)-1]
+	seen := &strings.Builder{}
+	for {
+		chunk, err := z.r.ReadString(last)
+		if err != nil {
+			return err
+		}
+		seen.WriteString(chunk)
+		if strings.HasSuffix(seen.String(), s) {
+			return nil
+		}
+		if seen.Len() > len(s) {
+			// only the tail can be part of the terminator
+			tail := seen.String()[seen.Len()-len(s):]
+			seen.Reset()
+			seen.WriteString(tail)
+		}
+	}
+}
+
+func isASCIILetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+func isHTMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
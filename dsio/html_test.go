@@ -0,0 +1,150 @@
+package dsio
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+const htmlPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>prices</title>
+  <style>table > td { color: red; }</style>
+  <script>if (a < b) { document.write("<table><tr><td>no</td></tr></table>") }</script>
+</head>
+<body>
+  <!-- <table><tr><td>commented out</td></tr></table> -->
+  <table class="nav"><tr><td>Home</td><td>About</td></tr></table>
+  <div class="report">
+    <table id="prices" class="data wide">
+      <thead>
+        <tr><th>Item</th><th>Price</th><th>In Stock</th></tr>
+      </thead>
+      <tbody>
+        <tr><td>Apples &amp; Pears</td><td>1.5</td><td>true</td></tr>
+        <tr>
+          <td>
+            Bread
+            <br>loaf
+          </td>
+          <td>2</td>
+          <td>false
+        <tr><td colspan=2>n/a</td><td><table><tr><td>nested</td></tr></table></td></tr>
+      </tbody>
+    </table>
+  </div>
+</body>
+</html>`
+
+var htmlPriceSchema = map[string]interface{}{
+	"type": "array",
+	"items": map[string]interface{}{
+		"type": "array",
+		"items": []interface{}{
+			map[string]interface{}{"title": "item", "type": "string"},
+			map[string]interface{}{"title": "price", "type": "number"},
+			map[string]interface{}{"title": "in_stock", "type": "boolean"},
+		},
+	},
+}
+
+func TestHTMLTableReader(t *testing.T) {
+	cases := []struct {
+		description string
+		cfg         map[string]interface{}
+		expect      []interface{}
+	}{
+		{"first table", nil, []interface{}{
+			[]interface{}{"Home", "About"},
+		}},
+		{"by index", map[string]interface{}{"table": 1, "headerRow": true}, []interface{}{
+			[]interface{}{"Apples & Pears", 1.5, true},
+			[]interface{}{"Bread loaf", float64(2), false},
+			[]interface{}{"n/a", "n/a", "nested"},
+		}},
+		{"by id", map[string]interface{}{"selector": "#prices"}, []interface{}{
+			[]interface{}{"Item", "Price", "In Stock"},
+			[]interface{}{"Apples & Pears", 1.5, true},
+			[]interface{}{"Bread loaf", float64(2), false},
+			[]interface{}{"n/a", "n/a", "nested"},
+		}},
+		{"by descendant classes", map[string]interface{}{"selector": "div.report table.wide.data", "headerRow": true}, []interface{}{
+			[]interface{}{"Apples & Pears", 1.5, true},
+			[]interface{}{"Bread loaf", float64(2), false},
+			[]interface{}{"n/a", "n/a", "nested"},
+		}},
+	}
+
+	for _, c := range cases {
+		st := &dataset.Structure{Format: "html", FormatConfig: c.cfg, Schema: htmlPriceSchema}
+		r, err := NewEntryReader(st, strings.NewReader(htmlPage))
+		if err != nil {
+			t.Fatalf("%s: %s", c.description, err)
+		}
+		for i, ex := range c.expect {
+			ent, err := r.ReadEntry()
+			if err != nil {
+				t.Fatalf("%s: entry %d unexpected error: %s", c.description, i, err)
+			}
+			if ent.Index != i {
+				t.Errorf("%s: entry %d index mismatch, got: %d", c.description, i, ent.Index)
+			}
+			if !reflect.DeepEqual(ent.Value, ex) {
+				t.Errorf("%s: entry %d mismatch.\nexpected: %#v\ngot:      %#v", c.description, i, ex, ent.Value)
+			}
+		}
+		if _, err := r.ReadEntry(); err != io.EOF {
+			t.Errorf("%s: expected io.EOF, got: %v", c.description, err)
+		}
+		if r.(*HTMLTableReader).EntriesRead() != len(c.expect) {
+			t.Errorf("%s: entries read mismatch. expected: %d, got: %d", c.description, len(c.expect), r.(*HTMLTableReader).EntriesRead())
+		}
+	}
+}
+
+func TestHTMLTableReaderErrors(t *testing.T) {
+	cases := []struct {
+		cfg    map[string]interface{}
+		schema map[string]interface{}
+		err    string
+	}{
+		{nil, dataset.BaseSchemaObject, "HTML requires a top-level array schema"},
+		{map[string]interface{}{"selector": "div > table"}, dataset.BaseSchemaArray, "unsupported html selector 'div > table': only tags, ids, classes & descendants are supported"},
+		{map[string]interface{}{"selector": "table."}, dataset.BaseSchemaArray, "invalid html selector 'table.'"},
+	}
+
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "html", FormatConfig: c.cfg, Schema: c.schema}
+		if _, err := NewHTMLTableReader(st, strings.NewReader(htmlPage)); err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+
+	missing := []struct {
+		cfg map[string]interface{}
+		err string
+	}{
+		{map[string]interface{}{"table": 3}, "html document has 3 tables, can't read table 3"},
+		{map[string]interface{}{"selector": "table#missing"}, "html document has 0 tables matching selector, can't read table 0"},
+	}
+	for i, c := range missing {
+		st := &dataset.Structure{Format: "html", FormatConfig: c.cfg, Schema: dataset.BaseSchemaArray}
+		r, err := NewHTMLTableReader(st, strings.NewReader(htmlPage))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = r.ReadEntry()
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("case %d expected a *ParseError, got: %v", i, err)
+			continue
+		}
+		if pe.Err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, pe.Err)
+		}
+	}
+}
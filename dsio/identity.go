@@ -28,6 +28,7 @@ type IdentityReader struct {
 	st      *dataset.Structure
 	done    bool
 	entries chan Entry
+	read    int
 }
 
 var _ EntryReader = (*IdentityReader)(nil)
@@ -43,9 +44,15 @@ func (r *IdentityReader) ReadEntry() (Entry, error) {
 		return Entry{}, io.EOF
 	}
 
+	r.read++
 	return <-r.entries, nil
 }
 
+// EntriesRead gives the number of entries read
+func (r *IdentityReader) EntriesRead() int {
+	return r.read
+}
+
 // Close finalizes the reader
 func (r *IdentityReader) Close() error {
 	if !r.done {
@@ -85,7 +92,8 @@ func (r *IdentityReader) iterateSlice(data []interface{}) chan Entry {
 
 // IdentityWriter is a dsio.EntryWriter that works with native go types
 type IdentityWriter struct {
-	st *dataset.Structure
+	st      *dataset.Structure
+	written int
 }
 
 // Structure gives the structure being written
@@ -95,9 +103,15 @@ func (w *IdentityWriter) Structure() *dataset.Structure {
 
 // WriteEntry writes one "row" of structured data to the Writer
 func (w *IdentityWriter) WriteEntry(e Entry) error {
+	w.written++
 	return nil
 }
 
+// EntriesWritten gives the number of entries written
+func (w *IdentityWriter) EntriesWritten() int {
+	return w.written
+}
+
 // Close finalizes the writer, indicating all entries
 // have been written
 func (w *IdentityWriter) Close() error {
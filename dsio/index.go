@@ -0,0 +1,91 @@
+package dsio
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+)
+
+// DefaultIndexInterval is the number of entries between body index
+// checkpoints when no interval is given
+const DefaultIndexInterval = 1000
+
+// BodyIndex maps entry numbers to checkpoints in an encoded body, letting
+// readers start part way through a body instead of reading from the start
+type BodyIndex struct {
+	// Format of the indexed body
+	Format string `json:"format"`
+	// Interval is the number of entries between checkpoints
+	Interval int `json:"interval"`
+	// Entries is the number of entries in the body
+	Entries int `json:"entries"`
+	// Checkpoints are tokens for the positions of every Interval'th entry,
+	// starting with entry Interval. Entries before the first checkpoint are
+	// read from the start of the body
+	Checkpoints []string `json:"checkpoints"`
+}
+
+// BuildBodyIndex reads a body, recording a checkpoint every interval entries.
+// The body's format must support checkpoints, see Checkpointer
+func BuildBodyIndex(st *dataset.Structure, r io.Reader, interval int) (*BodyIndex, error) {
+	if interval < 1 {
+		interval = DefaultIndexInterval
+	}
+	er, err := newEntryReader(st, r)
+	if err != nil {
+		return nil, err
+	}
+	cp, ok := er.(Checkpointer)
+	if !ok {
+		return nil, fmt.Errorf("indexing %s bodies is not supported", st.DataFormat())
+	}
+
+	idx := &BodyIndex{Format: st.DataFormat().String(), Interval: interval}
+	for {
+		if _, err := er.ReadEntry(); err == io.EOF {
+			break
+		} else if err != nil {
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("entry %d: %w", idx.Entries, err)
+		}
+		idx.Entries++
+		if idx.Entries%interval == 0 {
+			token, err := cp.Checkpoint()
+			if err != nil {
+				return nil, err
+			}
+			idx.Checkpoints = append(idx.Checkpoints, token)
+		}
+	}
+	return idx, nil
+}
+
+// Seek finds the closest checkpoint at or before an entry, giving the
+// checkpoint token & the number of entries to skip after resuming from it. An
+// empty token means reading should begin at the start of the body
+func (idx *BodyIndex) Seek(entry int) (token string, skip int) {
+	if idx == nil || idx.Interval < 1 || entry < idx.Interval {
+		return "", entry
+	}
+	i := entry/idx.Interval - 1
+	if i >= len(idx.Checkpoints) {
+		i = len(idx.Checkpoints) - 1
+	}
+	if i < 0 {
+		return "", entry
+	}
+	return idx.Checkpoints[i], entry - (i+1)*idx.Interval
+}
+
+// Offset gives the byte offset of a checkpoint token in the body
+func (idx *BodyIndex) Offset(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	cp, err := ParseCheckpoint(token)
+	if err != nil {
+		return 0, err
+	}
+	return cp.Offset, nil
+}
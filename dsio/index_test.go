@@ -0,0 +1,71 @@
+package dsio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestBodyIndex(t *testing.T) {
+	cases := []struct {
+		st   *dataset.Structure
+		data string
+	}{
+		{&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}, `[0,1,2,3,4,5,6]`},
+		{&dataset.Structure{Format: "csv", FormatConfig: map[string]interface{}{"headerRow": true}, Schema: dataset.BaseSchemaArray}, "a\n0\n1\n2\n3\n4\n5\n6\n"},
+	}
+
+	for i, c := range cases {
+		idx, err := BuildBodyIndex(c.st, bytes.NewBufferString(c.data), 3)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if idx.Entries != 7 || len(idx.Checkpoints) != 2 {
+			t.Fatalf("case %d expected 7 entries & 2 checkpoints, got: %d, %d", i, idx.Entries, len(idx.Checkpoints))
+		}
+
+		for entry := 0; entry < 7; entry++ {
+			token, skip := idx.Seek(entry)
+			off, err := idx.Offset(token)
+			if err != nil {
+				t.Fatalf("case %d entry %d unexpected error: %s", i, entry, err)
+			}
+
+			var r EntryReader
+			if token == "" {
+				r, err = NewEntryReader(c.st, bytes.NewBufferString(c.data))
+			} else {
+				r, err = ResumeEntryReader(c.st, bytes.NewBufferString(c.data[off:]), token)
+			}
+			if err != nil {
+				t.Fatalf("case %d entry %d unexpected error: %s", i, entry, err)
+			}
+			for j := 0; j < skip; j++ {
+				r.ReadEntry()
+			}
+			ent, err := r.ReadEntry()
+			if err != nil && err != io.EOF {
+				t.Fatalf("case %d entry %d unexpected error: %s", i, entry, err)
+			}
+			// entry values are their position in the body
+			val := ent.Value
+			if row, ok := val.([]interface{}); ok {
+				val = row[0]
+			}
+			if got := fmt.Sprint(val); got != strconv.Itoa(entry) {
+				t.Errorf("case %d seeking entry %d read entry %s", i, entry, got)
+			}
+		}
+	}
+}
+
+func TestBodyIndexUnsupported(t *testing.T) {
+	st := &dataset.Structure{Format: "xlsx", Schema: dataset.BaseSchemaArray}
+	if _, err := BuildBodyIndex(st, &bytes.Buffer{}, 10); err == nil {
+		t.Error("expected error indexing an unsupported format")
+	}
+}
@@ -0,0 +1,50 @@
+//go:build go1.23
+
+package dsio
+
+import (
+	"io"
+	"iter"
+)
+
+// All iterates the entries of r, for use in range-over-func loops:
+//
+//	for ent, err := range dsio.All(r) {
+//		if err != nil {
+//			return err
+//		}
+//	}
+//
+// Iteration ends at io.EOF, which isn't yielded. Other errors are yielded
+// once, ending iteration
+func All(r EntryReader) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		for {
+			ent, err := r.ReadEntry()
+			if err == io.EOF {
+				return
+			} else if err != nil {
+				yield(ent, err)
+				return
+			}
+			if !yield(ent, nil) {
+				return
+			}
+		}
+	}
+}
+
+// WriteSeq writes all entries of seq to w, stopping at the first error. w
+// isn't closed
+func WriteSeq(w EntryWriter, seq iter.Seq2[Entry, error]) error {
+	for ent, err := range seq {
+		if err != nil {
+			return err
+		}
+		if err := w.WriteEntry(ent); err != nil {
+			log.Debug(err.Error())
+			return err
+		}
+	}
+	return nil
+}
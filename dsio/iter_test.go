@@ -0,0 +1,71 @@
+//go:build go1.23
+
+package dsio
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestAll(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewJSONReader(st, bytes.NewBufferString(`[1,2,3]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for ent, err := range All(r) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ent.Index != count {
+			t.Errorf("expected index %d, got: %d", count, ent.Index)
+		}
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 || r.EntriesRead() != 2 {
+		t.Errorf("expected iteration to stop after 2 entries, read: %d", r.EntriesRead())
+	}
+
+	r, err = NewJSONReader(st, bytes.NewBufferString(`[1,2 3]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := 0
+	for _, err := range All(r) {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("expected 1 error, got: %d", errs)
+	}
+}
+
+func TestWriteSeq(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewJSONReader(st, bytes.NewBufferString(`[1,2,3]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewJSONWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSeq(w, All(r)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[1,2,3]" {
+		t.Errorf("output mismatch. got: %s", buf.String())
+	}
+}
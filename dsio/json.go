@@ -7,21 +7,44 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio/charset"
+	"github.com/qri-io/dataset/dslog"
+	"github.com/qri-io/dataset/vals"
 )
 
 // JSONReader implements the RowReader interface for the JSON data format
 type JSONReader struct {
 	entriesRead int
 	initialized bool
-	tlt         string
-	st          *dataset.Structure
-	objKey      string
-	reader      *bufio.Reader
-	prevSize    int // when buffer is extended, remember how much of the old buffer to discard
+	// done is set once the closing token is read
+	done   bool
+	tlt    string
+	st     *dataset.Structure
+	objKey string
+	reader *bufio.Reader
+	src    *TrackedReader
+	// offset is the position in the body the source starts at, nonzero for
+	// resumed readers
+	offset   int64
+	prevSize int // when buffer is extended, remember how much of the old buffer to discard
+	// arrayLenHint is the length of the last array read, used to pre-size
+	// the next one
+	arrayLenHint int
+	// transcoded is set when the source is decoded from a charset other than
+	// utf-8, positions are then offsets in the decoded text
+	transcoded bool
+	// bom strips a byte order mark from the source when the StripBOM option
+	// is set
+	bom *charset.BOMReader
 }
 
+// maxArrayLenHint caps pre-allocation for arrays
+const maxArrayLenHint = 1024
+
 var _ EntryReader = (*JSONReader)(nil)
 
 // NewJSONReader creates a reader from a structure and read source
@@ -33,20 +56,44 @@ func NewJSONReader(st *dataset.Structure, r io.Reader) (*JSONReader, error) {
 // NewJSONReaderSize creates a reader from a structure, read source, and buffer size
 func NewJSONReaderSize(st *dataset.Structure, r io.Reader, size int) (*JSONReader, error) {
 	if st.Schema == nil {
-		err := fmt.Errorf("schema required for JSON reader")
+		err := newKindError(ErrBadSchema, "schema required for JSON reader")
 		log.Debug(err.Error())
 		return nil, err
 	}
 
-	reader := bufio.NewReaderSize(r, size)
 	tlt, err := GetTopLevelType(st)
 	if err != nil {
 		return nil, err
 	}
+
+	// readers only use the encoding & byte order mark options, so other
+	// invalid settings are ignored
+	var bom *charset.BOMReader
+	transcoded := false
+	if opts, err := dataset.NewJSONOptions(st.FormatConfig); err == nil {
+		if opts.StripBOM {
+			// the byte order mark reader decodes text in the charset it finds
+			if bom, err = charset.NewBOMReader(r, opts.Encoding); err != nil {
+				return nil, err
+			}
+			r = bom
+		} else if opts.Encoding != "" && opts.Encoding != charset.UTF8 {
+			if r, err = charset.NewReader(r, opts.Encoding); err != nil {
+				return nil, err
+			}
+			transcoded = true
+		}
+	}
+
+	src := NewTrackedReader(r)
+	reader := bufio.NewReaderSize(src, size)
 	jr := &JSONReader{
-		st:     st,
-		reader: reader,
-		tlt:    tlt,
+		st:         st,
+		reader:     reader,
+		src:        src,
+		tlt:        tlt,
+		transcoded: transcoded,
+		bom:        bom,
 	}
 	return jr, nil
 }
@@ -60,7 +107,24 @@ const blockSize = 4096
 
 // ReadEntry reads one JSON record from the reader
 func (r *JSONReader) ReadEntry() (Entry, error) {
+	ent, err := r.readEntry(false)
+	return ent, parseError("json", r.entriesRead, err)
+}
+
+// SkipEntries reads past up to n entries without decoding their values, see
+// EntrySkipper. Skipped values are only checked for balanced brackets &
+// closed strings
+func (r *JSONReader) SkipEntries(n int) (int, error) {
+	skipped, err := skipEntries(func() (Entry, error) { return r.readEntry(true) }, n)
+	return skipped, parseError("json", r.entriesRead, err)
+}
+
+// readEntry reads the next entry, decoding it unless skip is set
+func (r *JSONReader) readEntry(skip bool) (Entry, error) {
 	ent := Entry{}
+	if r.done {
+		return ent, io.EOF
+	}
 
 	// Fill up buffer.
 	_, _ = r.reader.Peek(blockSize)
@@ -69,11 +133,11 @@ func (r *JSONReader) ReadEntry() (Entry, error) {
 	if !r.initialized {
 		if r.tlt == "object" {
 			if !r.readTokenChar('{') {
-				return ent, fmt.Errorf("Expected: opening object '{'")
+				return ent, newKindError(ErrFormatMismatch, "Expected: opening object '{'")
 			}
 		} else {
 			if !r.readTokenChar('[') {
-				return ent, fmt.Errorf("Expected: opening array '['")
+				return ent, newKindError(ErrFormatMismatch, "Expected: opening array '['")
 			}
 		}
 	}
@@ -81,10 +145,12 @@ func (r *JSONReader) ReadEntry() (Entry, error) {
 	// Close JSON container if it is complete, signaling EOF.
 	if r.tlt == "object" {
 		if r.readTokenChar('}') {
+			r.done = true
 			return ent, io.EOF
 		}
 	} else {
 		if r.readTokenChar(']') {
+			r.done = true
 			return ent, io.EOF
 		}
 	}
@@ -97,6 +163,22 @@ func (r *JSONReader) ReadEntry() (Entry, error) {
 	}
 	r.initialized = true
 
+	if skip {
+		if r.tlt == "object" {
+			if err := r.skipString(); err != nil {
+				return ent, err
+			}
+			if !r.readTokenChar(':') {
+				return ent, fmt.Errorf("Expected: ':' to separate key and value")
+			}
+		}
+		if err := r.skipValue(); err != nil {
+			return ent, err
+		}
+		r.entriesRead++
+		return ent, nil
+	}
+
 	// Read actual entry, format depends depends upon mode.
 	if r.tlt == "object" {
 		key, val, err := r.readKeyValuePair()
@@ -117,11 +199,51 @@ func (r *JSONReader) ReadEntry() (Entry, error) {
 	return ent, nil
 }
 
-// Close finalizes the reader
+// Close finalizes the reader, closing the source if it's an io.Closer. wrap
+// the source with KeepOpen to leave it open
 func (r *JSONReader) Close() error {
-	// TODO (b5): we should retain a reference to the underlying reader &
-	// check if it's an io.ReadCloser, calling close here if so
-	return nil
+	return r.src.Close()
+}
+
+// EntriesRead gives the number of entries read, including entries before the
+// checkpoint a resumed reader started from
+func (r *JSONReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes parsed, including bytes before the
+// checkpoint a resumed reader started from. Bodies in a charset other than
+// utf-8 are counted in bytes of decoded utf-8 text
+func (r *JSONReader) BytesProcessed() int64 {
+	n := r.offset + int64(r.src.BytesRead()-r.reader.Buffered())
+	if r.bom != nil {
+		n += int64(r.bom.Len())
+	}
+	return n
+}
+
+// isTranscoded reports whether the source is decoded from a charset other
+// than utf-8
+func (r *JSONReader) isTranscoded() bool {
+	if r.bom != nil {
+		return r.bom.Charset() != charset.UTF8
+	}
+	return r.transcoded
+}
+
+// Checkpoint returns a token recording the position of the reader, for use with
+// ResumeEntryReader. Readers decoding a charset other than utf-8 can't be
+// checkpointed
+func (r *JSONReader) Checkpoint() (string, error) {
+	if r.isTranscoded() {
+		return "", fmt.Errorf("checkpoints aren't supported for transcoded json bodies")
+	}
+	cp := &Checkpoint{
+		Format:  dataset.JSONDataFormat.String(),
+		Offset:  r.BytesProcessed(),
+		Entries: r.entriesRead,
+	}
+	return cp.Token()
 }
 
 func isWhitespace(ch byte) bool {
@@ -300,10 +422,21 @@ func (r *JSONReader) readNumber() (interface{}, error) {
 		}
 	}
 	if i > 0 {
+		// parse directly from the buffer to avoid allocating a string per number
+		var (
+			num interface{}
+			err error
+		)
 		if isFloat {
-			return strconv.ParseFloat(r.extractFromBuffer(buff, i), 64)
+			num, err = vals.ParseNumber(buff[:i])
+		} else {
+			var n int64
+			n, err = vals.ParseInteger(buff[:i])
+			num = int(n)
 		}
-		return strconv.Atoi(r.extractFromBuffer(buff, i))
+		_, _ = r.reader.Discard(i - r.prevSize)
+		r.prevSize = 0
+		return num, err
 	}
 	return 0, fmt.Errorf("Expected: number")
 }
@@ -342,7 +475,13 @@ func (r *JSONReader) readArray() ([]interface{}, error) {
 	if !r.readTokenChar('[') {
 		return nil, fmt.Errorf("Expected: opening '[' for array")
 	}
-	array := make([]interface{}, 0)
+	array := make([]interface{}, 0, r.arrayLenHint)
+	defer func() {
+		// rows tend to be the same length, size the next array to match
+		if len(array) <= maxArrayLenHint {
+			r.arrayLenHint = len(array)
+		}
+	}()
 	if r.readTokenChar(']') {
 		return array, nil
 	}
@@ -358,7 +497,7 @@ func (r *JSONReader) readArray() ([]interface{}, error) {
 			break
 		} else if !r.readTokenChar(',') {
 			buff := r.currentBuffer()
-			log.Error(string(buff))
+			log.Debugw("expected ',' to separate elements", dslog.F("index", r.entriesRead), dslog.F("next", string(buff)))
 			return nil, fmt.Errorf("Expected: ',' to separate elements")
 		}
 		val, err := r.readValue()
@@ -370,6 +509,79 @@ func (r *JSONReader) readArray() ([]interface{}, error) {
 	return array, nil
 }
 
+// skipValue reads past the next value without decoding it
+func (r *JSONReader) skipValue() error {
+	switch r.peekNextChar() {
+	case '"':
+		return r.skipString()
+	case '{', '[':
+		return r.skipContainer()
+	case 0:
+		return io.ErrUnexpectedEOF
+	}
+	// literals & numbers end at the next delimiter
+	for {
+		c, err := r.reader.ReadByte()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if c == ',' || c == ']' || c == '}' || c == ':' || isWhitespace(c) {
+			return r.reader.UnreadByte()
+		}
+	}
+}
+
+// skipString reads past a string without unquoting it
+func (r *JSONReader) skipString() error {
+	if r.peekNextChar() != '"' {
+		return fmt.Errorf("Expected: string")
+	}
+	_, _ = r.reader.Discard(1)
+	for {
+		c, err := r.reader.ReadByte()
+		if err != nil {
+			return fmt.Errorf("Expected: closing '\"' for string")
+		}
+		if c == '\\' {
+			if _, err := r.reader.ReadByte(); err != nil {
+				return fmt.Errorf("Expected: closing '\"' for string")
+			}
+		} else if c == '"' {
+			return nil
+		}
+	}
+}
+
+// skipContainer reads past an object or array, including everything nested
+// in it
+func (r *JSONReader) skipContainer() error {
+	depth := 0
+	for {
+		c, err := r.reader.ReadByte()
+		if err != nil {
+			return fmt.Errorf("Expected: closing bracket")
+		}
+		switch c {
+		case '"':
+			if err := r.reader.UnreadByte(); err != nil {
+				return err
+			}
+			if err := r.skipString(); err != nil {
+				return err
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
 func (r *JSONReader) readKeyValuePair() (string, interface{}, error) {
 	key, err := r.readString()
 	if err != nil {
@@ -388,17 +600,30 @@ func (r *JSONReader) readKeyValuePair() (string, interface{}, error) {
 // JSONWriter implements the RowWriter interface for
 // JSON-formatted data
 type JSONWriter struct {
-	rowsWritten int
-	tlt         string
-	st          *dataset.Structure
+	rowsWritten    int
+	entriesWritten int
+	tlt            string
+	st             *dataset.Structure
+	// wr is written to, out counts bytes written to the destination after
+	// encoding, they're the same writer for utf-8 output without a byte
+	// order mark
 	wr          io.Writer
+	out         *countingWriter
+	enc         *charset.Writer
 	keysWritten map[string]bool
+	escapeHTML  bool
+	asciiOnly   bool
+	indent      string
+	floats      *floatFormatter
+	// err is the first error writing to wr. entries that fail to encode in
+	// the output charset are lost, so Close reports it
+	err error
 }
 
 // NewJSONWriter creates a Writer from a structure and write destination
 func NewJSONWriter(st *dataset.Structure, w io.Writer) (*JSONWriter, error) {
 	if st.Schema == nil {
-		err := fmt.Errorf("schema required for JSON writer")
+		err := newKindError(ErrBadSchema, "schema required for JSON writer")
 		log.Debug(err.Error())
 		return nil, err
 	}
@@ -407,10 +632,45 @@ func NewJSONWriter(st *dataset.Structure, w io.Writer) (*JSONWriter, error) {
 	if err != nil {
 		return nil, err
 	}
+	out := &countingWriter{w: w}
 	jw := &JSONWriter{
 		st:  st,
-		wr:  w,
+		wr:  out,
+		out: out,
 		tlt: tlt,
+		// json.Marshal escapes HTML, which bodies have always been written with
+		escapeHTML: true,
+	}
+
+	if st.FormatConfig != nil {
+		opts, err := dataset.NewJSONOptions(st.FormatConfig)
+		if err != nil {
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("invalid json format config: %w", err)
+		}
+		if opts.EscapeHTML != nil {
+			jw.escapeHTML = *opts.EscapeHTML
+		}
+		jw.asciiOnly = opts.ASCIIOnly
+		jw.indent = opts.Indent
+		jw.floats = newFloatFormatter(st, opts.FloatFormat)
+
+		fb, err := charset.ParseFallback(opts.EncodingFallback)
+		if err != nil {
+			return nil, err
+		}
+		var dst io.Writer = out
+		if opts.EmitBOM {
+			if dst, err = charset.NewBOMWriter(out, opts.Encoding); err != nil {
+				return nil, err
+			}
+		}
+		if jw.wr, err = charset.NewWriter(dst, opts.Encoding, fb); err != nil {
+			return nil, err
+		}
+		if cw, ok := jw.wr.(*charset.Writer); ok {
+			jw.enc = cw
+		}
 	}
 
 	if jw.tlt == "object" {
@@ -436,7 +696,7 @@ func (w *JSONWriter) WriteEntry(ent Entry) error {
 		}
 		if _, err := w.wr.Write(open); err != nil {
 			log.Debug(err.Error())
-			return fmt.Errorf("error writing initial `%s`: %s", string(open), err.Error())
+			return fmt.Errorf("error writing initial `%s`: %w", string(open), err)
 		}
 	}
 
@@ -450,33 +710,58 @@ func (w *JSONWriter) WriteEntry(ent Entry) error {
 	if w.rowsWritten == 0 {
 		enc = []byte{}
 	}
+	if w.indent != "" {
+		enc = append(append(enc, '\n'), w.indent...)
+	}
 
-	_, err = w.wr.Write(append(enc, data...))
-	return err
+	if _, err = w.wr.Write(append(enc, data...)); err != nil {
+		if w.err == nil {
+			w.err = err
+		}
+		return err
+	}
+	w.entriesWritten++
+	return nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *JSONWriter) EntriesWritten() int {
+	return w.entriesWritten
+}
+
+// BytesProcessed gives the number of bytes written
+func (w *JSONWriter) BytesProcessed() int64 {
+	return w.out.n
 }
 
 func (w *JSONWriter) valBytes(ent Entry) ([]byte, error) {
+	if w.floats != nil {
+		ent.Value = w.floats.jsonValue(ent.Value, true)
+	}
 	if w.tlt == "array" {
 		// TODO - add test that checks this is recording values & not entries
-		return json.Marshal(ent.Value)
+		return w.marshal(ent.Value)
 	}
 
-	if ent.Key == "" {
-		log.Debug("write empty key")
-		return nil, fmt.Errorf("entry key cannot be empty")
+	if err := dataset.ValidObjectKey(ent.Key); err != nil {
+		log.Debug(err.Error())
+		return nil, err
 	} else if w.keysWritten[ent.Key] == true {
 		log.Debugf(`key already written: "%s"`, ent.Key)
 		return nil, fmt.Errorf(`key already written: "%s"`, ent.Key)
 	}
 	w.keysWritten[ent.Key] = true
 
-	data, err := json.Marshal(ent.Key)
+	data, err := w.marshal(ent.Key)
 	if err != nil {
 		log.Debug(err.Error())
 		return data, err
 	}
 	data = append(data, ':')
-	val, err := json.Marshal(ent.Value)
+	if w.indent != "" {
+		data = append(data, ' ')
+	}
+	val, err := w.marshal(ent.Value)
 	if err != nil {
 		log.Debug(err.Error())
 		return data, err
@@ -485,19 +770,91 @@ func (w *JSONWriter) valBytes(ent Entry) ([]byte, error) {
 	return data, nil
 }
 
+// marshal encodes a value using the writer's escaping options
+func (w *JSONWriter) marshal(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(w.escapeHTML)
+	if w.indent != "" {
+		// entries are nested one level inside the top level array or object
+		enc.SetIndent(w.indent, w.indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	// Encode terminates values with a newline
+	data := bytes.TrimSuffix(buf.Bytes(), []byte{'\n'})
+	if w.asciiOnly {
+		data = escapeNonASCII(data)
+	}
+	return data, nil
+}
+
+// escapeNonASCII replaces non-ASCII characters in encoded JSON with \u
+// escapes. Encoded JSON only has non-ASCII characters inside strings, so
+// escaping doesn't change the value
+func escapeNonASCII(data []byte) []byte {
+	i := 0
+	for i < len(data) && data[i] < utf8.RuneSelf {
+		i++
+	}
+	if i == len(data) {
+		return data
+	}
+
+	buf := make([]byte, 0, len(data)+len(data)/2)
+	buf = append(buf, data[:i]...)
+	for i < len(data) {
+		if data[i] < utf8.RuneSelf {
+			buf = append(buf, data[i])
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+			buf = append(buf, fmt.Sprintf(`\u%04x\u%04x`, r1, r2)...)
+		} else {
+			buf = append(buf, fmt.Sprintf(`\u%04x`, r)...)
+		}
+		i += size
+	}
+	return buf
+}
+
 // Close finalizes the writer, indicating no more records
-// will be written
+// will be written. The destination is closed if it's an io.Closer, wrap it
+// with KeepWriterOpen to leave it open
 func (w *JSONWriter) Close() error {
+	err := w.finish()
+	if w.err != nil {
+		err = w.err
+	}
+	if w.enc != nil {
+		if cerr := w.enc.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if cerr := w.out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// finish writes the closing token
+func (w *JSONWriter) finish() error {
 	// if WriteEntry is never called, write an empty array
 	if w.rowsWritten == 0 {
 		data := []byte("[]")
 		if w.tlt == "object" {
 			data = []byte("{}")
 		}
+		if w.indent != "" {
+			data = append(data, '\n')
+		}
 
 		if _, err := w.wr.Write(data); err != nil {
 			log.Debug(err.Error())
-			return fmt.Errorf("error writing empty closure '%s': %s", string(data), err.Error())
+			return fmt.Errorf("error writing empty closure '%s': %w", string(data), err)
 		}
 		return nil
 	}
@@ -506,10 +863,14 @@ func (w *JSONWriter) Close() error {
 	if w.tlt == "object" {
 		cloze = []byte{'}'}
 	}
+	if w.indent != "" {
+		// indented bodies end with a newline, as text files do
+		cloze = []byte{'\n', cloze[0], '\n'}
+	}
 	_, err := w.wr.Write(cloze)
 	if err != nil {
 		log.Debug(err.Error())
-		return fmt.Errorf("error closing writer: %s", err.Error())
+		return fmt.Errorf("error closing writer: %w", err)
 	}
 	return nil
 }
@@ -451,6 +451,18 @@ func TestJSONWriterNonObjectEntry(t *testing.T) {
 	}
 }
 
+func TestJSONWriterInvalidKey(t *testing.T) {
+	w, err := NewJSONWriter(&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.WriteEntry(Entry{Key: "bad\xff", Value: false})
+	expect := `invalid entry key "bad\xff": keys must be valid UTF-8`
+	if err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %s. got: %v", expect, err)
+	}
+}
+
 func TestJSONWriterDoubleKey(t *testing.T) {
 	buf := &bytes.Buffer{}
 	w, err := NewJSONWriter(&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject}, buf)
@@ -541,3 +553,212 @@ func BenchmarkJSONReader(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkJSONReaderNumeric(b *testing.B) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	buf := &bytes.Buffer{}
+	buf.WriteString("[")
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("[12345,678.901,-0.5e3,42,3.14159265358979]")
+	}
+	buf.WriteString("]")
+	data := buf.Bytes()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		r, err := NewJSONReader(st, bytes.NewReader(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for {
+			if _, err := r.ReadEntry(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func TestJSONWriterEscaping(t *testing.T) {
+	val := `<a href="x">&</a> café 😀`
+	cases := []struct {
+		config map[string]interface{}
+		expect string
+	}{
+		{nil, `["\u003ca href=\"x\"\u003e\u0026\u003c/a\u003e café 😀"]`},
+		{map[string]interface{}{"escapeHTML": true}, `["\u003ca href=\"x\"\u003e\u0026\u003c/a\u003e café 😀"]`},
+		{map[string]interface{}{"escapeHTML": false}, `["<a href=\"x\">&</a> café 😀"]`},
+		{map[string]interface{}{"escapeHTML": false, "asciiOnly": true}, `["<a href=\"x\">&</a> caf\u00e9 \ud83d\ude00"]`},
+	}
+
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "json", FormatConfig: c.config, Schema: dataset.BaseSchemaArray}
+		buf := &bytes.Buffer{}
+		w, err := NewJSONWriter(st, buf)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if err := w.WriteEntry(Entry{Value: val}); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if buf.String() != c.expect {
+			t.Errorf("case %d output mismatch. expected: %s, got: %s", i, c.expect, buf.String())
+		}
+
+		var got []string
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil || got[0] != val {
+			t.Errorf("case %d expected output to decode to original value, got: %v %v", i, got, err)
+		}
+	}
+
+	st := &dataset.Structure{Format: "json", FormatConfig: map[string]interface{}{"asciiOnly": "yes"}, Schema: dataset.BaseSchemaArray}
+	if _, err := NewJSONWriter(st, &bytes.Buffer{}); err == nil {
+		t.Error("expected invalid format config to error")
+	}
+}
+
+func TestJSONWriterIndent(t *testing.T) {
+	cases := []struct {
+		schema  map[string]interface{}
+		entries []Entry
+		expect  string
+	}{
+		{dataset.BaseSchemaArray, nil, "[]\n"},
+		{dataset.BaseSchemaArray, []Entry{
+			{Value: map[string]interface{}{"b": 2, "a": []interface{}{1, "x"}}},
+			{Value: "y"},
+		}, "[\n  {\n    \"a\": [\n      1,\n      \"x\"\n    ],\n    \"b\": 2\n  },\n  \"y\"\n]\n"},
+		{dataset.BaseSchemaObject, []Entry{
+			{Key: "z", Value: map[string]interface{}{"d": true, "c": nil}},
+			{Key: "a", Value: 1},
+		}, "{\n  \"z\": {\n    \"c\": null,\n    \"d\": true\n  },\n  \"a\": 1\n}\n"},
+	}
+
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "json", FormatConfig: map[string]interface{}{"indent": "  "}, Schema: c.schema}
+		buf := &bytes.Buffer{}
+		w, err := NewJSONWriter(st, buf)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		for _, ent := range c.entries {
+			if err := w.WriteEntry(ent); err != nil {
+				t.Fatalf("case %d unexpected error: %s", i, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if buf.String() != c.expect {
+			t.Errorf("case %d output mismatch. expected:\n%s\ngot:\n%s", i, c.expect, buf.String())
+		}
+		if !json.Valid(buf.Bytes()) {
+			t.Errorf("case %d expected valid json", i)
+		}
+	}
+}
+
+func TestJSONEncoding(t *testing.T) {
+	for _, enc := range []string{"utf-16le", "utf-16be", "windows-1252"} {
+		st := &dataset.Structure{Format: "json", FormatConfig: map[string]interface{}{"encoding": enc}, Schema: dataset.BaseSchemaArray}
+		buf := &bytes.Buffer{}
+		w, err := NewJSONWriter(st, buf)
+		if err != nil {
+			t.Fatalf("%s unexpected error: %s", enc, err)
+		}
+		if err := w.WriteEntry(Entry{Value: "café €5"}); err != nil {
+			t.Fatalf("%s unexpected error: %s", enc, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s unexpected error: %s", enc, err)
+		}
+		if w.BytesProcessed() != int64(buf.Len()) {
+			t.Errorf("%s expected %d bytes processed, got: %d", enc, buf.Len(), w.BytesProcessed())
+		}
+		if bytes.Contains(buf.Bytes(), []byte("café")) {
+			t.Errorf("%s expected output not to be utf-8, got: %q", enc, buf.Bytes())
+		}
+
+		r, err := NewJSONReader(st, buf)
+		if err != nil {
+			t.Fatalf("%s unexpected error: %s", enc, err)
+		}
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("%s unexpected error: %s", enc, err)
+		}
+		if ent.Value != "café €5" {
+			t.Errorf("%s expected: %q, got: %q", enc, "café €5", ent.Value)
+		}
+		if _, err := r.Checkpoint(); err == nil {
+			t.Errorf("%s expected checkpointing a transcoded reader to error", enc)
+		}
+	}
+
+	st := &dataset.Structure{Format: "json", FormatConfig: map[string]interface{}{"encoding": "iso-8859-1"}, Schema: dataset.BaseSchemaArray}
+	w, err := NewJSONWriter(st, &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Value: "€"}); err == nil {
+		t.Error("expected writing an entry that can't be encoded to error")
+	}
+	if err := w.Close(); err == nil || err.Error() != "character '€' at byte offset 2 can't be encoded in iso-8859-1" {
+		t.Errorf("error mismatch, got: %v", err)
+	}
+}
+
+func TestJSONByteOrderMarks(t *testing.T) {
+	st := &dataset.Structure{Format: "json", FormatConfig: map[string]interface{}{"emitBOM": true, "stripBOM": true}, Schema: dataset.BaseSchemaArray}
+	buf := &bytes.Buffer{}
+	w, err := NewJSONWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Value: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expect := "\xef\xbb\xbf[\"a\"]"
+	if buf.String() != expect {
+		t.Errorf("output mismatch. expected: %q, got: %q", expect, buf.String())
+	}
+	if w.BytesProcessed() != int64(len(expect)) {
+		t.Errorf("expected %d bytes processed, got: %d", len(expect), w.BytesProcessed())
+	}
+
+	r, err := NewJSONReader(st, bytes.NewBufferString(expect))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent.Value != "a" {
+		t.Errorf("expected: %q, got: %v", "a", ent.Value)
+	}
+	if _, err := r.Checkpoint(); err != nil {
+		t.Errorf("unexpected checkpoint error: %s", err)
+	}
+
+	// utf-16 marks set the encoding text is read in
+	r, err = NewJSONReader(st, bytes.NewBufferString("\xfe\xff\x00[\x00\"\x00a\x00\"\x00]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent, err = r.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	if ent.Value != "a" {
+		t.Errorf("expected: %q, got: %v", "a", ent.Value)
+	}
+}
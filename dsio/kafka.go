@@ -0,0 +1,368 @@
+package dsio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/qri-io/dataset"
+	"github.com/ugorji/go/codec"
+)
+
+// KafkaMessage is a single record read from or written to a kafka topic
+type KafkaMessage struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+}
+
+// KafkaConsumer is the subset of a kafka consumer client KafkaReader depends
+// on. Wrapping a concrete client library in this interface keeps dsio free of
+// a kafka client dependency
+type KafkaConsumer interface {
+	// ReadMessage returns the next message from subscribed topics, returning
+	// io.EOF when no more messages should be read
+	ReadMessage() (*KafkaMessage, error)
+	// CommitOffsets records consumed positions for a topic, keyed by
+	// partition. Offsets are the offset of the next message to read
+	CommitOffsets(topic string, offsets map[int32]int64) error
+}
+
+// KafkaProducer is the subset of a kafka producer client KafkaWriter depends on
+type KafkaProducer interface {
+	// WriteMessage publishes a message
+	WriteMessage(msg *KafkaMessage) error
+	// Flush blocks until all published messages are delivered
+	Flush() error
+}
+
+// MessageCodec serializes entry values to & from message payloads.
+// JSONMessageCodec, MsgpackMessageCodec and AvroMessageCodec are provided,
+// other formats can be supported by implementing this interface
+type MessageCodec interface {
+	Encode(v interface{}) ([]byte, error)
+	Decode(data []byte) (interface{}, error)
+}
+
+// JSONMessageCodec encodes message payloads as JSON
+type JSONMessageCodec struct{}
+
+// Encode implements the MessageCodec interface
+func (JSONMessageCodec) Encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode implements the MessageCodec interface
+func (JSONMessageCodec) Decode(data []byte) (v interface{}, err error) {
+	err = json.Unmarshal(data, &v)
+	return
+}
+
+// MsgpackMessageCodec encodes message payloads as msgpack
+type MsgpackMessageCodec struct{}
+
+func (MsgpackMessageCodec) handle() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	h.RawToString = true
+	h.WriteExt = true
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	return h
+}
+
+// Encode implements the MessageCodec interface
+func (c MsgpackMessageCodec) Encode(v interface{}) ([]byte, error) {
+	var data []byte
+	err := codec.NewEncoderBytes(&data, c.handle()).Encode(v)
+	return data, err
+}
+
+// Decode implements the MessageCodec interface
+func (c MsgpackMessageCodec) Decode(data []byte) (v interface{}, err error) {
+	err = codec.NewDecoderBytes(data, c.handle()).Decode(&v)
+	return
+}
+
+// AvroMessageCodec encodes message payloads as avro binary records, using the
+// schema AvroSchema derives from a structure. Payloads don't carry the
+// schema, consumers must use the same structure, or a registry to look it up
+type AvroMessageCodec struct {
+	schema  *avroType
+	objects bool
+}
+
+// NewAvroMessageCodec creates an avro codec from a structure. Entry values are
+// arrays of column values, or objects if the structure's rows are objects
+func NewAvroMessageCodec(st *dataset.Structure) (*AvroMessageCodec, error) {
+	schema, err := avroStructureType(st)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	c := &AvroMessageCodec{schema: schema}
+	if items, ok := st.Schema["items"].(map[string]interface{}); ok {
+		c.objects = items["type"] == "object"
+	}
+	return c, nil
+}
+
+// Encode implements the MessageCodec interface
+func (c *AvroMessageCodec) Encode(v interface{}) ([]byte, error) {
+	return avroEncodeRow(nil, c.schema, c.objects, v, "message")
+}
+
+// Decode implements the MessageCodec interface
+func (c *AvroMessageCodec) Decode(data []byte) (interface{}, error) {
+	d := &avroDecoder{buf: data}
+	v, err := d.row(c.schema, c.objects)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, fmt.Errorf("avro message has %d unread bytes", len(data)-d.pos)
+	}
+	return v, nil
+}
+
+// KafkaConfig encapsulates configuration for kafka readers & writers
+type KafkaConfig struct {
+	// Topic to write to. readers commit offsets for the topic of each message
+	// read, or this topic for messages that don't give one. required
+	Topic string
+	// Codec for message payloads. default is JSONMessageCodec
+	Codec MessageCodec
+	// CommitInterval is the number of entries read between offset commits.
+	// default of zero only commits offsets on Close
+	CommitInterval int
+}
+
+func newKafkaConfig(configs []func(cfg *KafkaConfig)) *KafkaConfig {
+	cfg := &KafkaConfig{
+		Codec: JSONMessageCodec{},
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	return cfg
+}
+
+// KafkaReader implements the EntryReader interface for messages consumed from
+// a kafka topic. Each message is read as one entry. For structures with a
+// top-level object type message keys are used as entry keys
+type KafkaReader struct {
+	cfg     *KafkaConfig
+	st      *dataset.Structure
+	c       KafkaConsumer
+	isObj   bool
+	idx     int
+	offsets map[string]map[int32]int64
+	// pending lists topics with offsets that haven't been committed
+	pending map[string]bool
+	unread  int
+	bytes   int64
+}
+
+var _ EntryReader = (*KafkaReader)(nil)
+
+// NewKafkaReader creates a reader from a structure and consumer
+func NewKafkaReader(st *dataset.Structure, c KafkaConsumer, configs ...func(cfg *KafkaConfig)) (*KafkaReader, error) {
+	cfg := newKafkaConfig(configs)
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("kafka topic is required")
+	}
+	tlt, err := GetTopLevelType(st)
+	if err != nil {
+		return nil, err
+	}
+	return &KafkaReader{
+		cfg:     cfg,
+		st:      st,
+		c:       c,
+		isObj:   tlt == "object",
+		offsets: map[string]map[int32]int64{},
+		pending: map[string]bool{},
+	}, nil
+}
+
+// Structure gives this reader's structure
+func (r *KafkaReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// EntriesRead gives the number of entries read
+func (r *KafkaReader) EntriesRead() int {
+	return r.idx
+}
+
+// BytesProcessed gives the total size of the keys & values of messages read
+func (r *KafkaReader) BytesProcessed() int64 {
+	return r.bytes
+}
+
+// ReadEntry consumes & decodes one message
+func (r *KafkaReader) ReadEntry() (Entry, error) {
+	msg, err := r.c.ReadMessage()
+	if err != nil {
+		if err != io.EOF {
+			log.Debug(err.Error())
+		}
+		return Entry{}, err
+	}
+
+	v, err := r.cfg.Codec.Decode(msg.Value)
+	if err != nil {
+		log.Debug(err.Error())
+		return Entry{}, fmt.Errorf("decoding message at partition %d offset %d: %w", msg.Partition, msg.Offset, err)
+	}
+
+	ent := Entry{Value: v}
+	if r.isObj {
+		ent.Key = string(msg.Key)
+	} else {
+		ent.Index = r.idx
+	}
+	r.idx++
+	r.bytes += int64(len(msg.Key) + len(msg.Value))
+
+	topic := msg.Topic
+	if topic == "" {
+		topic = r.cfg.Topic
+	}
+	if r.offsets[topic] == nil {
+		r.offsets[topic] = map[int32]int64{}
+	}
+	r.offsets[topic][msg.Partition] = msg.Offset + 1
+	r.pending[topic] = true
+	r.unread++
+	if r.cfg.CommitInterval > 0 && r.unread >= r.cfg.CommitInterval {
+		if err := r.Commit(); err != nil {
+			return ent, err
+		}
+	}
+	return ent, nil
+}
+
+// Offsets gives the offset of the next message to read for each topic &
+// partition consumed so far. Offsets can be stored alongside a dataset
+// version to resume consuming from a snapshot
+func (r *KafkaReader) Offsets() map[string]map[int32]int64 {
+	offsets := make(map[string]map[int32]int64, len(r.offsets))
+	for topic := range r.offsets {
+		offsets[topic] = r.topicOffsets(topic)
+	}
+	return offsets
+}
+
+func (r *KafkaReader) topicOffsets(topic string) map[int32]int64 {
+	offsets := make(map[int32]int64, len(r.offsets[topic]))
+	for p, o := range r.offsets[topic] {
+		offsets[p] = o
+	}
+	return offsets
+}
+
+// Commit checkpoints consumed offsets with the consumer, once for each topic
+// with offsets that haven't been committed
+func (r *KafkaReader) Commit() error {
+	topics := make([]string, 0, len(r.pending))
+	for topic := range r.pending {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	for _, topic := range topics {
+		if err := r.c.CommitOffsets(topic, r.topicOffsets(topic)); err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("committing offsets for topic %s: %w", topic, err)
+		}
+		delete(r.pending, topic)
+	}
+	r.unread = 0
+	return nil
+}
+
+// Close commits any uncommitted offsets. Close does not close the underlying
+// consumer
+func (r *KafkaReader) Close() error {
+	return r.Commit()
+}
+
+// KafkaWriter implements the EntryWriter interface, publishing each entry as
+// a message to a kafka topic. Entry keys, or indexes for array bodies, are
+// used as message keys
+type KafkaWriter struct {
+	cfg   *KafkaConfig
+	st    *dataset.Structure
+	p     KafkaProducer
+	isObj bool
+	count int
+	bytes int64
+}
+
+var _ EntryWriter = (*KafkaWriter)(nil)
+
+// NewKafkaWriter creates a writer from a structure and producer
+func NewKafkaWriter(st *dataset.Structure, p KafkaProducer, configs ...func(cfg *KafkaConfig)) (*KafkaWriter, error) {
+	cfg := newKafkaConfig(configs)
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("kafka topic is required")
+	}
+	tlt, err := GetTopLevelType(st)
+	if err != nil {
+		return nil, err
+	}
+	return &KafkaWriter{
+		cfg:   cfg,
+		st:    st,
+		p:     p,
+		isObj: tlt == "object",
+	}, nil
+}
+
+// Structure gives this writer's structure
+func (w *KafkaWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// EntriesWritten gives the number of entries written
+func (w *KafkaWriter) EntriesWritten() int {
+	return w.count
+}
+
+// BytesProcessed gives the total size of the keys & values of messages written
+func (w *KafkaWriter) BytesProcessed() int64 {
+	return w.bytes
+}
+
+// WriteEntry encodes & publishes one entry
+func (w *KafkaWriter) WriteEntry(ent Entry) error {
+	data, err := w.cfg.Codec.Encode(ent.Value)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error encoding entry: %w", err)
+	}
+
+	key := ent.Key
+	if !w.isObj {
+		key = fmt.Sprintf("%d", ent.Index)
+	}
+
+	err = w.p.WriteMessage(&KafkaMessage{
+		Topic: w.cfg.Topic,
+		Key:   []byte(key),
+		Value: data,
+	})
+	if err != nil {
+		return err
+	}
+	w.count++
+	w.bytes += int64(len(key) + len(data))
+	return nil
+}
+
+// Close flushes the producer. Close does not close the underlying producer
+func (w *KafkaWriter) Close() error {
+	return w.p.Flush()
+}
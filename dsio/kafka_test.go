@@ -0,0 +1,196 @@
+package dsio
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+// memTopic is an in-memory kafka topic that implements both KafkaConsumer &
+// KafkaProducer
+type memTopic struct {
+	msgs      []*KafkaMessage
+	pos       int
+	committed map[string]map[int32]int64
+	flushed   bool
+}
+
+func (t *memTopic) ReadMessage() (*KafkaMessage, error) {
+	if t.pos >= len(t.msgs) {
+		return nil, io.EOF
+	}
+	msg := t.msgs[t.pos]
+	t.pos++
+	return msg, nil
+}
+
+func (t *memTopic) CommitOffsets(topic string, offsets map[int32]int64) error {
+	if t.committed == nil {
+		t.committed = map[string]map[int32]int64{}
+	}
+	t.committed[topic] = offsets
+	return nil
+}
+
+func (t *memTopic) WriteMessage(msg *KafkaMessage) error {
+	msg.Offset = int64(len(t.msgs))
+	t.msgs = append(t.msgs, msg)
+	return nil
+}
+
+func (t *memTopic) Flush() error {
+	t.flushed = true
+	return nil
+}
+
+func TestKafkaReadWrite(t *testing.T) {
+	st := &dataset.Structure{
+		Schema: dataset.BaseSchemaArray,
+	}
+	topic := &memTopic{}
+	config := func(cfg *KafkaConfig) {
+		cfg.Topic = "events"
+		cfg.CommitInterval = 2
+	}
+
+	w, err := NewKafkaWriter(st, topic, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vals := []interface{}{"a", float64(1), map[string]interface{}{"b": true}}
+	for i, v := range vals {
+		if err := w.WriteEntry(Entry{Index: i, Value: v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !topic.flushed {
+		t.Error("expected close to flush producer")
+	}
+	if string(topic.msgs[2].Key) != "2" {
+		t.Errorf("expected index message key. got: %s", topic.msgs[2].Key)
+	}
+
+	r, err := NewKafkaReader(st, topic, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range vals {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ent.Value, v) {
+			t.Errorf("entry %d mismatch. expected: %v, got: %v", i, v, ent.Value)
+		}
+		if i == 1 && topic.committed["events"][0] != 2 {
+			t.Errorf("expected offsets to be committed after interval. got: %v", topic.committed)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected EOF, got: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if topic.committed["events"][0] != 3 {
+		t.Errorf("expected close to commit final offset. got: %v", topic.committed)
+	}
+
+	if _, err := NewKafkaReader(st, topic); err == nil {
+		t.Error("expected missing topic to error")
+	}
+}
+
+func TestKafkaReaderTopicOffsets(t *testing.T) {
+	st := &dataset.Structure{Schema: dataset.BaseSchemaArray}
+	topic := &memTopic{msgs: []*KafkaMessage{
+		{Topic: "a", Partition: 0, Offset: 4, Value: []byte(`1`)},
+		{Topic: "b", Partition: 0, Offset: 9, Value: []byte(`2`)},
+		{Topic: "a", Partition: 1, Offset: 2, Value: []byte(`3`)},
+		{Partition: 0, Offset: 0, Value: []byte(`4`)},
+	}}
+	r, err := NewKafkaReader(st, topic, func(cfg *KafkaConfig) { cfg.Topic = "events" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, err := r.ReadEntry(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]map[int32]int64{
+		"a":      {0: 5, 1: 3},
+		"b":      {0: 10},
+		"events": {0: 1},
+	}
+	if !reflect.DeepEqual(expect, r.Offsets()) {
+		t.Errorf("offsets mismatch. expected: %v, got: %v", expect, r.Offsets())
+	}
+	if !reflect.DeepEqual(expect, topic.committed) {
+		t.Errorf("committed offsets mismatch. expected: %v, got: %v", expect, topic.committed)
+	}
+}
+
+func TestKafkaMessageCodecs(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "avro",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "name", "type": "string"},
+					map[string]interface{}{"title": "score", "type": "number"},
+				},
+			},
+		},
+	}
+	avro, err := NewAvroMessageCodec(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		codec       MessageCodec
+		value       interface{}
+	}{
+		{"json", JSONMessageCodec{}, map[string]interface{}{"a": "b"}},
+		{"msgpack", MsgpackMessageCodec{}, map[string]interface{}{"a": "b"}},
+		{"avro", avro, []interface{}{1, "a", 1.5}},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			data, err := c.codec.Encode(c.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := c.codec.Decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(c.value, got) {
+				t.Errorf("value mismatch. expected: %#v, got: %#v", c.value, got)
+			}
+		})
+	}
+
+	if _, err := avro.Encode([]interface{}{"one", "a", 1.5}); err == nil {
+		t.Error("expected an error encoding a value that doesn't match the schema")
+	}
+	if _, err := avro.Decode([]byte{0x02, 0x02, 0x61, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f, 0x00}); err == nil {
+		t.Error("expected an error decoding a message with trailing bytes")
+	}
+}
@@ -0,0 +1,167 @@
+package dsio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+// ReaderConfig encapsulates limits enforced by readers created with
+// NewEntryReader. zero values disable a limit
+type ReaderConfig struct {
+	// MaxEntries is the maximum number of entries that can be read
+	MaxEntries int
+	// MaxBytes is the maximum number of bytes read from the source
+	MaxBytes int64
+	// MaxDuration is the maximum time reading can take, measured from the
+	// creation of the reader
+	MaxDuration time.Duration
+	// Context, if set, stops reading when done
+	Context context.Context
+	// Errata, if set, reads csv, tsv & ndjson bodies leniently. Records that
+	// fail to parse are skipped & their raw bytes written to Errata, one
+	// record per line, so bad rows can be inspected & reprocessed. errata
+	// never includes a header row
+	Errata io.Writer
+	// DecodeWorkers, if greater than one, reads csv bodies with a
+	// ParallelCSVReader decoding records on this many goroutines. Parallel
+	// decoding is faster for large bodies with typed columns, but can't be
+	// combined with Errata
+	DecodeWorkers int
+}
+
+// ErrLimitExceeded is returned by readers that hit a configured limit
+type ErrLimitExceeded struct {
+	// Limit is the name of the limit that was exceeded, one of "entries",
+	// "bytes", or "duration"
+	Limit string
+	// Max is the configured value of the limit
+	Max int64
+}
+
+// Error implements the error interface
+func (e *ErrLimitExceeded) Error() string {
+	if e.Limit == "duration" {
+		return fmt.Sprintf("read limit exceeded: max duration %s", time.Duration(e.Max))
+	}
+	return fmt.Sprintf("read limit exceeded: max %s %d", e.Limit, e.Max)
+}
+
+// enabled reports whether any limit is set
+func (cfg *ReaderConfig) enabled() bool {
+	return cfg.MaxEntries > 0 || cfg.MaxBytes > 0 || cfg.MaxDuration > 0 || cfg.Context != nil
+}
+
+// limiter checks the time & context limits of a reader config
+type limiter struct {
+	cfg      *ReaderConfig
+	deadline time.Time
+}
+
+func newLimiter(cfg *ReaderConfig) *limiter {
+	l := &limiter{cfg: cfg}
+	if cfg.MaxDuration > 0 {
+		l.deadline = time.Now().Add(cfg.MaxDuration)
+	}
+	return l
+}
+
+func (l *limiter) check() error {
+	if !l.deadline.IsZero() && time.Now().After(l.deadline) {
+		return &ErrLimitExceeded{Limit: "duration", Max: int64(l.cfg.MaxDuration)}
+	}
+	if l.cfg.Context != nil {
+		return l.cfg.Context.Err()
+	}
+	return nil
+}
+
+// limitedReader enforces byte & time limits on a raw byte stream
+type limitedReader struct {
+	*limiter
+	r    io.Reader
+	read int64
+	err  error
+}
+
+// Read implements the io.Reader interface
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if lr.err != nil {
+		return 0, lr.err
+	}
+	if lr.err = lr.check(); lr.err != nil {
+		return 0, lr.err
+	}
+	if max := lr.cfg.MaxBytes; max > 0 && int64(len(p)) > max-lr.read+1 {
+		// read at most one byte past the limit to detect overflow
+		p = p[:max-lr.read+1]
+	}
+	n, err := lr.r.Read(p)
+	lr.read += int64(n)
+	if lr.cfg.MaxBytes > 0 && lr.read > lr.cfg.MaxBytes {
+		lr.err = &ErrLimitExceeded{Limit: "bytes", Max: lr.cfg.MaxBytes}
+		return n - int(lr.read-lr.cfg.MaxBytes), lr.err
+	}
+	return n, err
+}
+
+// Close closes the underlying reader if it's an io.Closer
+func (lr *limitedReader) Close() error {
+	return closeUnderlying(lr.r)
+}
+
+// limitedEntryReader enforces all reader limits on an EntryReader
+type limitedEntryReader struct {
+	*limiter
+	r    EntryReader
+	src  *limitedReader
+	read int
+}
+
+// Structure gives the structure of the underlying reader
+func (r *limitedEntryReader) Structure() *dataset.Structure {
+	return r.r.Structure()
+}
+
+// ReadEntry reads one entry, returning an *ErrLimitExceeded once a limit
+// is hit
+func (r *limitedEntryReader) ReadEntry() (Entry, error) {
+	if err := r.check(); err != nil {
+		return Entry{}, err
+	}
+	if r.cfg.MaxEntries > 0 && r.read >= r.cfg.MaxEntries {
+		// only an error if there's more to read
+		if _, err := r.r.ReadEntry(); err == io.EOF {
+			return Entry{}, io.EOF
+		}
+		return Entry{}, &ErrLimitExceeded{Limit: "entries", Max: int64(r.cfg.MaxEntries)}
+	}
+
+	ent, err := r.r.ReadEntry()
+	if err != nil && r.src.err != nil {
+		// parsers wrap read errors, surface the limit error directly
+		return ent, r.src.err
+	}
+	if err == nil {
+		r.read++
+	}
+	return ent, err
+}
+
+// EntriesRead gives the number of entries read
+func (r *limitedEntryReader) EntriesRead() int {
+	return r.read
+}
+
+// BytesProcessed gives the bytes processed by the underlying reader
+func (r *limitedEntryReader) BytesProcessed() int64 {
+	return bytesProcessed(r.r)
+}
+
+// Close closes the underlying reader
+func (r *limitedEntryReader) Close() error {
+	return r.r.Close()
+}
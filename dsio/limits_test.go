@@ -0,0 +1,84 @@
+package dsio
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestReaderLimits(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	body := `[1,2,3,4,5,6,7,8,9,10]`
+
+	cases := []struct {
+		description string
+		config      func(cfg *ReaderConfig)
+		read        int
+		limit       string
+	}{
+		{"no limits", func(cfg *ReaderConfig) {}, 10, ""},
+		{"max entries not hit", func(cfg *ReaderConfig) { cfg.MaxEntries = 10 }, 10, ""},
+		{"max entries", func(cfg *ReaderConfig) { cfg.MaxEntries = 3 }, 3, "entries"},
+		{"max bytes not hit", func(cfg *ReaderConfig) { cfg.MaxBytes = int64(len(body)) }, 10, ""},
+		{"max bytes", func(cfg *ReaderConfig) { cfg.MaxBytes = 8 }, 4, "bytes"},
+		{"max duration", func(cfg *ReaderConfig) { cfg.MaxDuration = time.Nanosecond }, 0, "duration"},
+	}
+
+	for _, c := range cases {
+		r, err := NewEntryReader(st, strings.NewReader(body), c.config)
+		if err != nil {
+			t.Fatalf("case '%s': %s", c.description, err)
+		}
+		time.Sleep(time.Millisecond)
+
+		read := 0
+		for {
+			if _, err = r.ReadEntry(); err != nil {
+				break
+			}
+			read++
+		}
+		if err == io.EOF {
+			err = nil
+		}
+		if read != c.read {
+			t.Errorf("case '%s': expected %d entries read, got: %d", c.description, c.read, read)
+		}
+		if c.limit == "" {
+			if err != nil {
+				t.Errorf("case '%s': unexpected error: %s", c.description, err)
+			}
+			continue
+		}
+		lerr, ok := err.(*ErrLimitExceeded)
+		if !ok {
+			t.Errorf("case '%s': expected *ErrLimitExceeded, got: %#v", c.description, err)
+			continue
+		}
+		if lerr.Limit != c.limit {
+			t.Errorf("case '%s': expected %s limit, got: %s", c.description, c.limit, lerr.Limit)
+		}
+	}
+}
+
+func TestReaderLimitsContext(t *testing.T) {
+	st := &dataset.Structure{Format: "csv", Schema: dataset.BaseSchemaArray}
+	ctx, cancel := context.WithCancel(context.Background())
+	r, err := NewEntryReader(st, strings.NewReader("a\nb\nc\n"), func(cfg *ReaderConfig) {
+		cfg.Context = ctx
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, err := r.ReadEntry(); err != context.Canceled {
+		t.Errorf("expected canceled error, got: %v", err)
+	}
+}
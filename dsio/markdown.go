@@ -0,0 +1,192 @@
+package dsio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// MarkdownWriter implements the EntryWriter interface, rendering a body as a
+// GitHub-flavored markdown table for previews in readmes & issues.
+//
+// Columns are named by the titles of a tabular schema, with number & integer
+// columns right-aligned. Without column titles, columns are named from the
+// first entry: object keys in order, or abstract column names for arrays.
+// Entries of object bodies get a leading "key" column. Nested values are
+// written as JSON
+type MarkdownWriter struct {
+	st      *dataset.Structure
+	out     *countingWriter
+	w       *bufio.Writer
+	tlt     string
+	titles  []string
+	numeric []bool
+	// header is set once the header row has been written
+	header         bool
+	entriesWritten int
+}
+
+var _ EntryWriter = (*MarkdownWriter)(nil)
+
+// NewMarkdownWriter creates a writer from a structure and write destination
+func NewMarkdownWriter(st *dataset.Structure, w io.Writer) (*MarkdownWriter, error) {
+	if st.Schema == nil {
+		err := newKindError(ErrBadSchema, "schema required for Markdown writer")
+		log.Debug(err.Error())
+		return nil, err
+	}
+	tlt, err := GetTopLevelType(st)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &countingWriter{w: w}
+	mw := &MarkdownWriter{st: st, out: out, w: bufio.NewWriter(out), tlt: tlt}
+	if titles, err := CSVHeaderTitles(st); err == nil {
+		_, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+		mw.titles = titles
+		mw.numeric = make([]bool, len(types))
+		for i, t := range types {
+			mw.numeric[i] = t == "number" || t == "integer"
+		}
+	}
+	return mw, nil
+}
+
+// Structure gives this writer's structure
+func (w *MarkdownWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// WriteEntry writes one table row
+func (w *MarkdownWriter) WriteEntry(ent Entry) error {
+	if w.titles == nil {
+		w.titles = entryTitles(ent.Value)
+	}
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	var cells []interface{}
+	switch v := ent.Value.(type) {
+	case []interface{}:
+		cells = v
+	case map[string]interface{}:
+		cells = make([]interface{}, len(w.titles))
+		for i, title := range w.titles {
+			cells[i] = v[title]
+		}
+	default:
+		cells = []interface{}{v}
+	}
+	if w.tlt == "object" {
+		cells = append([]interface{}{ent.Key}, cells...)
+	}
+
+	strs, err := encode(cells, nil, nil, nil)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error encoding entry: %w", err)
+	}
+	if err := w.writeRow(strs); err != nil {
+		return err
+	}
+	w.entriesWritten++
+	return nil
+}
+
+// entryTitles names columns from an entry without schema titles
+func entryTitles(v interface{}) []string {
+	switch t := v.(type) {
+	case []interface{}:
+		titles := make([]string, len(t))
+		for i := range titles {
+			titles[i] = dataset.AbstractColumnName(i)
+		}
+		return titles
+	case map[string]interface{}:
+		titles := make([]string, 0, len(t))
+		for key := range t {
+			titles = append(titles, key)
+		}
+		sort.Strings(titles)
+		return titles
+	default:
+		return []string{"value"}
+	}
+}
+
+// writeHeader writes the header & delimiter rows once columns are known
+func (w *MarkdownWriter) writeHeader() error {
+	if w.header || w.titles == nil {
+		return nil
+	}
+	w.header = true
+
+	titles, numeric := w.titles, w.numeric
+	if w.tlt == "object" {
+		titles = append([]string{"key"}, titles...)
+		numeric = append([]bool{false}, numeric...)
+	}
+	delims := make([]string, len(titles))
+	for i := range delims {
+		delims[i] = "---"
+		if i < len(numeric) && numeric[i] {
+			delims[i] = "---:"
+		}
+	}
+	if err := w.writeRow(titles); err != nil {
+		return err
+	}
+	return w.writeLine(delims)
+}
+
+// writeRow writes cells, escaping characters that would break the table
+func (w *MarkdownWriter) writeRow(cells []string) error {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		escaped[i] = markdownCellReplacer.Replace(c)
+	}
+	return w.writeLine(escaped)
+}
+
+var markdownCellReplacer = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+func (w *MarkdownWriter) writeLine(cells []string) error {
+	if _, err := w.w.WriteString("| " + strings.Join(cells, " | ") + " |\n"); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing markdown: %w", err)
+	}
+	return nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *MarkdownWriter) EntriesWritten() int {
+	return w.entriesWritten
+}
+
+// BytesProcessed gives the number of bytes written
+func (w *MarkdownWriter) BytesProcessed() int64 {
+	return w.out.n
+}
+
+// Close finalizes the writer, writing the header of bodies without entries.
+// The destination is closed if it's an io.Closer, wrap it with
+// KeepWriterOpen to leave it open
+func (w *MarkdownWriter) Close() error {
+	if w.out.closed {
+		return nil
+	}
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	if err := w.w.Flush(); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing markdown: %w", err)
+	}
+	return w.out.Close()
+}
@@ -0,0 +1,78 @@
+package dsio
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestMarkdownWriter(t *testing.T) {
+	tabular := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "name", "type": "string"},
+				map[string]interface{}{"title": "score", "type": "number"},
+				map[string]interface{}{"title": "tags", "type": "array"},
+			},
+		},
+	}
+
+	cases := []struct {
+		description string
+		schema      map[string]interface{}
+		entries     []Entry
+		expect      string
+	}{
+		{"tabular", tabular, []Entry{
+			{Value: []interface{}{"ada", 1.5, []interface{}{"a", "b"}}},
+			{Value: []interface{}{"pipes | and\nnewlines", nil, nil}},
+		}, "| name | score | tags |\n| --- | ---: | --- |\n| ada | 1.5 | [\"a\",\"b\"] |\n| pipes \\| and<br>newlines |  |  |\n"},
+		{"tabular without entries", tabular, nil, "| name | score | tags |\n| --- | ---: | --- |\n"},
+		{"objects without titles", dataset.BaseSchemaArray, []Entry{
+			{Value: map[string]interface{}{"b": true, "a": int64(2)}},
+			{Value: map[string]interface{}{"a": "x"}},
+		}, "| a | b |\n| --- | --- |\n| 2 | true |\n| x |  |\n"},
+		{"arrays without titles", dataset.BaseSchemaArray, []Entry{
+			{Value: []interface{}{"a", "b"}},
+		}, "| a | b |\n| --- | --- |\n| a | b |\n"},
+		{"object body", dataset.BaseSchemaObject, []Entry{
+			{Key: "first", Value: "one"},
+			{Key: "second", Value: 2},
+		}, "| key | value |\n| --- | --- |\n| first | one |\n| second | 2 |\n"},
+		{"empty body without titles", dataset.BaseSchemaArray, nil, ""},
+	}
+
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		st := &dataset.Structure{Format: "md", Schema: c.schema}
+		w, err := NewEntryWriter(st, buf)
+		if err != nil {
+			t.Fatalf("%s: %s", c.description, err)
+		}
+		for _, ent := range c.entries {
+			if err := w.WriteEntry(ent); err != nil {
+				t.Fatalf("%s: %s", c.description, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: %s", c.description, err)
+		}
+		if buf.String() != c.expect {
+			t.Errorf("%s: output mismatch.\nexpected:\n%s\ngot:\n%s", c.description, c.expect, buf.String())
+		}
+		mw := w.(*MarkdownWriter)
+		if mw.EntriesWritten() != len(c.entries) {
+			t.Errorf("%s: entries written mismatch. expected: %d, got: %d", c.description, len(c.entries), mw.EntriesWritten())
+		}
+		if mw.BytesProcessed() != int64(buf.Len()) {
+			t.Errorf("%s: bytes processed mismatch. expected: %d, got: %d", c.description, buf.Len(), mw.BytesProcessed())
+		}
+	}
+
+	if _, err := NewMarkdownWriter(&dataset.Structure{Format: "md"}, &bytes.Buffer{}); err == nil {
+		t.Error("expected an error creating a writer without a schema")
+	}
+}
@@ -0,0 +1,212 @@
+package dsio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/qri-io/dataset"
+)
+
+// NDJSONReader implements the EntryReader interface for newline-delimited
+// JSON (JSON Lines), where each line of the body is one entry. Blank lines
+// are skipped. NDJSON bodies must have a top-level array schema
+type NDJSONReader struct {
+	st          *dataset.Structure
+	reader      *bufio.Reader
+	src         *TrackedReader
+	entriesRead int
+	// offset is the position in the body the source starts at, nonzero for
+	// resumed readers
+	offset int64
+	// span is the source position of the last line read, see rawSpan
+	span [2]int64
+}
+
+var _ EntryReader = (*NDJSONReader)(nil)
+
+// NewNDJSONReader creates a reader from a structure and read source
+func NewNDJSONReader(st *dataset.Structure, r io.Reader) (*NDJSONReader, error) {
+	if err := checkNDJSONSchema(st); err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	src := NewTrackedReader(r)
+	return &NDJSONReader{
+		st:     st,
+		reader: bufio.NewReaderSize(src, 256*1024),
+		src:    src,
+	}, nil
+}
+
+// checkNDJSONSchema errors if a structure doesn't describe an NDJSON body
+func checkNDJSONSchema(st *dataset.Structure) error {
+	if st.Schema == nil {
+		return newKindError(ErrBadSchema, "schema required for NDJSON")
+	}
+	tlt, err := GetTopLevelType(st)
+	if err != nil {
+		return err
+	}
+	if tlt != "array" {
+		return newKindError(ErrBadSchema, "NDJSON requires a top-level array schema")
+	}
+	return nil
+}
+
+// Structure gives this reader's structure
+func (r *NDJSONReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry reads one line from the reader
+func (r *NDJSONReader) ReadEntry() (Entry, error) {
+	ent, err := r.readEntry()
+	return ent, parseError("ndjson", r.entriesRead, err)
+}
+
+func (r *NDJSONReader) readEntry() (Entry, error) {
+	for {
+		start := r.BytesProcessed() - r.offset
+		line, err := r.reader.ReadBytes('\n')
+		r.span = [2]int64{start, r.BytesProcessed() - r.offset}
+		if err != nil && err != io.EOF {
+			return Entry{}, err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err == io.EOF {
+				return Entry{}, io.EOF
+			}
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		var v interface{}
+		if derr := dec.Decode(&v); derr != nil {
+			return Entry{}, newKindError(ErrFormatMismatch, fmt.Sprintf("invalid JSON line: %s", derr))
+		}
+		if dec.More() {
+			return Entry{}, newKindError(ErrFormatMismatch, "invalid JSON line: more than one value")
+		}
+
+		ent := Entry{Index: r.entriesRead, Value: ndjsonValue(v)}
+		r.entriesRead++
+		return ent, nil
+	}
+}
+
+// rawSpan gives the source position of the last line read
+func (r *NDJSONReader) rawSpan() (start, end int64) {
+	return r.span[0], r.span[1]
+}
+
+// ndjsonValue converts decoded json.Number values to int or float64, matching
+// the values JSONReader gives
+func ndjsonValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(t), 10, 64); err == nil {
+			return int(i)
+		}
+		f, _ := t.Float64()
+		return f
+	case []interface{}:
+		for i, val := range t {
+			t[i] = ndjsonValue(val)
+		}
+	case map[string]interface{}:
+		for key, val := range t {
+			t[key] = ndjsonValue(val)
+		}
+	}
+	return v
+}
+
+// Close finalizes the reader, closing the source if it's an io.Closer. wrap
+// the source with KeepOpen to leave it open
+func (r *NDJSONReader) Close() error {
+	return r.src.Close()
+}
+
+// EntriesRead gives the number of entries read, including entries before the
+// checkpoint a resumed reader started from
+func (r *NDJSONReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes parsed, including bytes before the
+// checkpoint a resumed reader started from
+func (r *NDJSONReader) BytesProcessed() int64 {
+	return r.offset + int64(r.src.BytesRead()-r.reader.Buffered())
+}
+
+// Checkpoint returns a token recording the position of the reader, for use with
+// ResumeEntryReader
+func (r *NDJSONReader) Checkpoint() (string, error) {
+	cp := &Checkpoint{
+		Format:  dataset.NDJSONDataFormat.String(),
+		Offset:  r.BytesProcessed(),
+		Entries: r.entriesRead,
+	}
+	return cp.Token()
+}
+
+// NDJSONWriter implements the EntryWriter interface for newline-delimited
+// JSON, writing each entry value on it's own line
+type NDJSONWriter struct {
+	st             *dataset.Structure
+	wr             *countingWriter
+	enc            *json.Encoder
+	entriesWritten int
+}
+
+var _ EntryWriter = (*NDJSONWriter)(nil)
+
+// NewNDJSONWriter creates a writer from a structure and write destination
+func NewNDJSONWriter(st *dataset.Structure, w io.Writer) (*NDJSONWriter, error) {
+	if err := checkNDJSONSchema(st); err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	wr := &countingWriter{w: w}
+	enc := json.NewEncoder(wr)
+	enc.SetEscapeHTML(false)
+	return &NDJSONWriter{st: st, wr: wr, enc: enc}, nil
+}
+
+// Structure gives this writer's structure
+func (w *NDJSONWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// WriteEntry writes an entry value as one line
+func (w *NDJSONWriter) WriteEntry(ent Entry) error {
+	// Encode terminates each value with a newline
+	if err := w.enc.Encode(ent.Value); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error encoding entry: %w", err)
+	}
+	w.entriesWritten++
+	return nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *NDJSONWriter) EntriesWritten() int {
+	return w.entriesWritten
+}
+
+// BytesProcessed gives the number of bytes written
+func (w *NDJSONWriter) BytesProcessed() int64 {
+	return w.wr.n
+}
+
+// Close finalizes the writer. The destination is closed if it's an
+// io.Closer, wrap it with KeepWriterOpen to leave it open
+func (w *NDJSONWriter) Close() error {
+	return w.wr.Close()
+}
@@ -0,0 +1,142 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+var ndjsonStructure = &dataset.Structure{
+	Format: "ndjson",
+	Schema: dataset.BaseSchemaArray,
+}
+
+func TestNDJSONReader(t *testing.T) {
+	data := "{\"a\":1,\"b\":[2.5,\"x\"]}\n\n[1,2]\r\n\"str\"\nnull\n12"
+	r, err := NewNDJSONReader(ndjsonStructure, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []interface{}{
+		map[string]interface{}{"a": 1, "b": []interface{}{2.5, "x"}},
+		[]interface{}{1, 2},
+		"str",
+		nil,
+		12,
+	}
+	for i, ex := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("entry %d unexpected error: %s", i, err)
+		}
+		if ent.Index != i {
+			t.Errorf("entry %d index mismatch, got: %d", i, ent.Index)
+		}
+		if !reflect.DeepEqual(ent.Value, ex) {
+			t.Errorf("entry %d mismatch. expected: %#v, got: %#v", i, ex, ent.Value)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+	if r.BytesProcessed() != int64(len(data)) {
+		t.Errorf("expected %d bytes processed, got: %d", len(data), r.BytesProcessed())
+	}
+}
+
+func TestNDJSONReaderErrors(t *testing.T) {
+	cases := []struct {
+		st   *dataset.Structure
+		data string
+		err  string
+	}{
+		{&dataset.Structure{Format: "ndjson"}, "", "schema required for NDJSON"},
+		{&dataset.Structure{Format: "ndjson", Schema: dataset.BaseSchemaObject}, "", "NDJSON requires a top-level array schema"},
+		{ndjsonStructure, "[1,2]\n{nope}\n", "invalid JSON line: invalid character 'n' looking for beginning of object key string"},
+		{ndjsonStructure, "1 2\n", "invalid JSON line: more than one value"},
+	}
+
+	for i, c := range cases {
+		r, err := NewNDJSONReader(c.st, strings.NewReader(c.data))
+		if err == nil {
+			for err == nil {
+				_, err = r.ReadEntry()
+			}
+		}
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewEntryWriter(ndjsonStructure, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vals := []interface{}{
+		map[string]interface{}{"a": "<b>"},
+		[]interface{}{1, 2.5},
+		nil,
+	}
+	for _, v := range vals {
+		if err := w.WriteEntry(Entry{Value: v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expect := "{\"a\":\"<b>\"}\n[1,2.5]\nnull\n"
+	if buf.String() != expect {
+		t.Errorf("output mismatch. expected: %q, got: %q", expect, buf.String())
+	}
+
+	r, err := NewEntryReader(ndjsonStructure, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	if err := EachEntry(r, func(int, Entry, error) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != len(vals) {
+		t.Errorf("expected %d entries round trip, got: %d", len(vals), count)
+	}
+}
+
+func TestNDJSONCheckpoint(t *testing.T) {
+	data := "1\n2\n3\n4\n"
+	r, err := NewNDJSONReader(ndjsonStructure, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ReadEntry()
+	r.ReadEntry()
+	token, err := r.Checkpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp, _ := ParseCheckpoint(token)
+
+	rr, err := ResumeEntryReader(ndjsonStructure, strings.NewReader(data[cp.Offset:]), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := rr.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent.Index != 2 || ent.Value != 3 {
+		t.Errorf("expected entry 2 with value 3, got: %#v", ent)
+	}
+}
@@ -0,0 +1,291 @@
+package dsio
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/qri-io/dataset"
+)
+
+// numberLocale is a convention for writing numbers, with a decimal separator
+// & the separators it allows between thousands
+type numberLocale struct {
+	name    string
+	decimal rune
+	groups  string
+}
+
+// numberLocales are the locales of dataset.NumberLocales, in the order
+// ambiguous values are read with
+var numberLocales = []*numberLocale{
+	{name: "en", decimal: '.', groups: ","},
+	{name: "de", decimal: ',', groups: "."},
+	{name: "fr", decimal: ',', groups: " \u00a0\u202f"},
+	{name: "ch", decimal: '.', groups: "'’"},
+}
+
+// currencySymbols are the currency signs read as units, longer signs first
+var currencySymbols = []string{"US$", "NZ$", "HK$", "A$", "C$", "R$", "S$", "$", "€", "£", "¥", "₹", "₩", "₽", "₺", "₪", "₫", "฿", "₴", "₦", "¢"}
+
+// numberUnits reads number fields with units for the ParseUnits option,
+// tracking the unit & locale found in each column. a nil numberUnits reads
+// no units
+type numberUnits struct {
+	// locale is set by the NumberLocale option, nil to detect locales
+	locale  *numberLocale
+	columns []numberColumn
+	// changed is set when a column's unit or locale is found
+	changed bool
+}
+
+type numberColumn struct {
+	unit   string
+	locale *numberLocale
+}
+
+// newNumberUnits creates a units reader from csv options, nil if units
+// aren't parsed
+func newNumberUnits(opts *dataset.CSVOptions) *numberUnits {
+	if !opts.ParseUnits {
+		return nil
+	}
+	u := &numberUnits{}
+	for _, loc := range numberLocales {
+		if loc.name == opts.NumberLocale {
+			u.locale = loc
+		}
+	}
+	return u
+}
+
+// parse reads a field of column i, giving the field's number in the form
+// strconv parses. ok is false if the field isn't a number, or has a unit
+// that differs from the column's. Values that fit several locales, like
+// 1,234, are read in the column's locale once it's known & the first
+// locale of numberLocales they fit until then
+func (u *numberUnits) parse(i int, s string) (num string, ok bool) {
+	if u == nil {
+		return "", false
+	}
+	for len(u.columns) <= i {
+		u.columns = append(u.columns, numberColumn{})
+	}
+	col := &u.columns[i]
+
+	digits, unit, neg, ok := splitNumberUnit(s)
+	if !ok || (unit != "" && col.unit != "" && unit != col.unit) {
+		return "", false
+	}
+
+	locales := numberLocales
+	if u.locale != nil {
+		locales = []*numberLocale{u.locale}
+	} else if col.locale != nil {
+		locales = []*numberLocale{col.locale}
+	}
+	var found *numberLocale
+	matches := 0
+	for _, loc := range locales {
+		n, ok := loc.normalize(digits)
+		if !ok {
+			continue
+		}
+		if matches == 0 {
+			num, found = n, loc
+		}
+		matches++
+	}
+	if matches == 0 {
+		return "", false
+	}
+
+	if unit != "" && col.unit == "" {
+		col.unit = unit
+		u.changed = true
+	}
+	// a locale is found when it's the only one a value with separators reads
+	// in, plain digits don't show a locale
+	if matches == 1 && col.locale == nil && strings.IndexFunc(digits, isNotDigit) >= 0 {
+		col.locale = found
+		u.changed = true
+	}
+	if neg {
+		num = "-" + num
+	}
+	return num, true
+}
+
+// annotate gives a copy of st with the unit & locale of number columns set
+// as properties of the columns of it's schema. st is returned as-is if it's
+// schema doesn't list columns
+func (u *numberUnits) annotate(st *dataset.Structure) *dataset.Structure {
+	items, ok := st.Schema["items"].(map[string]interface{})
+	if !ok {
+		return st
+	}
+	cols, ok := items["items"].([]interface{})
+	if !ok {
+		return st
+	}
+
+	annotated := make([]interface{}, len(cols))
+	copy(annotated, cols)
+	for i, c := range u.columns {
+		if i >= len(cols) || (c.unit == "" && c.locale == nil) {
+			continue
+		}
+		col, ok := cols[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cp := make(map[string]interface{}, len(col)+2)
+		for k, v := range col {
+			cp[k] = v
+		}
+		if c.unit != "" {
+			cp["unit"] = c.unit
+		}
+		if c.locale != nil {
+			cp["locale"] = c.locale.name
+		}
+		annotated[i] = cp
+	}
+
+	sch := make(map[string]interface{}, len(st.Schema))
+	for k, v := range st.Schema {
+		sch[k] = v
+	}
+	itemsCp := make(map[string]interface{}, len(items))
+	for k, v := range items {
+		itemsCp[k] = v
+	}
+	itemsCp["items"] = annotated
+	sch["items"] = itemsCp
+
+	ast := &dataset.Structure{}
+	ast.Assign(st)
+	ast.Schema = sch
+	return ast
+}
+
+// splitNumberUnit separates a number from a currency symbol, currency code
+// or percent sign, reading negatives written with a minus sign or in
+// accounting parentheses like ($12.50)
+func splitNumberUnit(s string) (digits, unit string, neg, ok bool) {
+	s = strings.TrimSpace(s)
+	paren := strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")")
+	if paren {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	s, neg, signed := trimNumberSign(s)
+	if paren && signed {
+		return "", "", false, false
+	}
+
+	if unit = currencyPrefix(s); unit != "" {
+		s = strings.TrimSpace(s[len(unit):])
+		if !paren && !signed {
+			s, neg, signed = trimNumberSign(s)
+		}
+	} else if unit = currencySuffix(s); unit != "" {
+		s = strings.TrimSpace(s[:len(s)-len(unit)])
+	}
+	if s == "" {
+		return "", "", false, false
+	}
+	return s, unit, neg || paren, true
+}
+
+// trimNumberSign removes a leading plus or minus sign
+func trimNumberSign(s string) (string, bool, bool) {
+	r, size := utf8.DecodeRuneInString(s)
+	switch r {
+	case '-', '−':
+		return strings.TrimSpace(s[size:]), true, true
+	case '+':
+		return strings.TrimSpace(s[size:]), false, true
+	}
+	return s, false, false
+}
+
+func currencyPrefix(s string) string {
+	for _, sym := range currencySymbols {
+		if strings.HasPrefix(s, sym) {
+			return sym
+		}
+	}
+	if len(s) > 3 && isCurrencyCode(s[:3]) && !isUpper(s[3]) {
+		return s[:3]
+	}
+	return ""
+}
+
+func currencySuffix(s string) string {
+	if strings.HasSuffix(s, "%") {
+		return "%"
+	}
+	for _, sym := range currencySymbols {
+		if strings.HasSuffix(s, sym) {
+			return sym
+		}
+	}
+	if n := len(s); n > 3 && isCurrencyCode(s[n-3:]) && !isUpper(s[n-4]) {
+		return s[n-3:]
+	}
+	return ""
+}
+
+// isCurrencyCode reports whether s has the shape of an ISO 4217 code
+func isCurrencyCode(s string) bool {
+	return isUpper(s[0]) && isUpper(s[1]) && isUpper(s[2])
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func isNotDigit(r rune) bool {
+	return r < '0' || r > '9'
+}
+
+// normalize reads s as a number written in the locale, giving the number
+// with separators removed & a period decimal point. Thousands separators
+// must separate groups of three digits
+func (l *numberLocale) normalize(s string) (string, bool) {
+	intPart, frac := s, ""
+	if j := strings.IndexRune(s, l.decimal); j >= 0 {
+		intPart, frac = s[:j], s[j+utf8.RuneLen(l.decimal):]
+		if frac == "" || strings.IndexFunc(frac, isNotDigit) >= 0 {
+			return "", false
+		}
+	}
+
+	buf := &strings.Builder{}
+	// group counts digits since the last separator, -1 before the first
+	digits, group := 0, -1
+	for _, c := range intPart {
+		switch {
+		case c >= '0' && c <= '9':
+			buf.WriteRune(c)
+			digits++
+			if group >= 0 {
+				group++
+			}
+		case strings.ContainsRune(l.groups, c):
+			if digits == 0 || (group < 0 && digits > 3) || (group >= 0 && group != 3) {
+				return "", false
+			}
+			group = 0
+		default:
+			return "", false
+		}
+	}
+	if digits == 0 || (group >= 0 && group != 3) {
+		return "", false
+	}
+	if frac != "" {
+		buf.WriteByte('.')
+		buf.WriteString(frac)
+	}
+	return buf.String(), true
+}
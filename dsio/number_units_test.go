@@ -0,0 +1,137 @@
+package dsio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestNumberUnitsParse(t *testing.T) {
+	cases := []struct {
+		in     string
+		locale string
+		num    string
+		ok     bool
+	}{
+		{"1234", "", "1234", true},
+		{"$1,234.50", "", "1234.50", true},
+		{"-$1,234.50", "", "-1234.50", true},
+		{"$-12", "", "-12", true},
+		{"($12.50)", "", "-12.50", true},
+		{"(-12)", "", "", false},
+		{"1.234,56 €", "", "1234.56", true},
+		{"EUR 12,5", "", "12.5", true},
+		{"12,5 %", "", "12.5", true},
+		{"1 234,5", "", "1234.5", true},
+		{"1'234.5", "", "1234.5", true},
+		{"1,23,456", "", "", false},
+		{"12,", "", "", false},
+		{"$", "", "", false},
+		{"abc", "", "", false},
+		{"1,234", "", "1234", true},
+		{"1,234", "de", "1.234", true},
+		{"1.234", "de", "1234", true},
+		{"1,234.5", "de", "", false},
+	}
+	for i, c := range cases {
+		u := newNumberUnits(&dataset.CSVOptions{ParseUnits: true, NumberLocale: c.locale})
+		num, ok := u.parse(0, c.in)
+		if num != c.num || ok != c.ok {
+			t.Errorf("case %d %q: expected %q %t, got: %q %t", i, c.in, c.num, c.ok, num, ok)
+		}
+	}
+
+	if newNumberUnits(&dataset.CSVOptions{}) != nil {
+		t.Error("expected no units reader without ParseUnits")
+	}
+	var u *numberUnits
+	if _, ok := u.parse(0, "$12"); ok {
+		t.Error("expected a nil units reader not to parse units")
+	}
+}
+
+func TestNumberUnitsColumns(t *testing.T) {
+	u := newNumberUnits(&dataset.CSVOptions{ParseUnits: true})
+	steps := []struct {
+		col int
+		in  string
+		num string
+		ok  bool
+	}{
+		// ambiguous values are read as en until the column's locale is found
+		{0, "1.234", "1.234", true},
+		{0, "1.212,50 €", "1212.50", true},
+		{0, "1.234", "1234", true},
+		// units can't change within a column
+		{0, "$12", "", false},
+		{0, "12", "12", true},
+		{1, "50%", "50", true},
+		{1, "1,234", "1234", true},
+	}
+	for i, s := range steps {
+		num, ok := u.parse(s.col, s.in)
+		if num != s.num || ok != s.ok {
+			t.Errorf("step %d %q: expected %q %t, got: %q %t", i, s.in, s.num, s.ok, num, ok)
+		}
+	}
+	if u.columns[0].unit != "€" || u.columns[0].locale.name != "de" {
+		t.Errorf("expected column 0 to be € de, got: %s %v", u.columns[0].unit, u.columns[0].locale)
+	}
+	if u.columns[1].unit != "%" || u.columns[1].locale != nil {
+		t.Errorf("expected column 1 to be %% without a locale, got: %s %v", u.columns[1].unit, u.columns[1].locale)
+	}
+}
+
+func TestCSVReaderParseUnits(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true, "parseUnits": true},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "item", "type": "string"},
+					map[string]interface{}{"title": "price", "type": "number"},
+					map[string]interface{}{"title": "qty", "type": "integer"},
+					map[string]interface{}{"title": "discount", "type": "number"},
+				},
+			},
+		},
+	}
+	body := "item,price,qty,discount\n" +
+		"a,\"1.234,50 €\",1 000,10%\n" +
+		"b,\"(12,00 €)\",3,\"2,5 %\"\n" +
+		"c,$4,\"12,5\",n/a\n"
+	r := NewCSVReader(st, strings.NewReader(body))
+	expect := [][]interface{}{
+		{"a", 1234.5, int64(1000), float64(10)},
+		{"b", float64(-12), int64(3), 2.5},
+		{"c", "$4", "12,5", "n/a"},
+	}
+	for i, row := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ent.Value, row) {
+			t.Errorf("entry %d mismatch. expected: %#v, got: %#v", i, row, ent.Value)
+		}
+	}
+
+	cols := r.Structure().Schema["items"].(map[string]interface{})["items"].([]interface{})
+	got := []interface{}{cols[1], cols[2], cols[3]}
+	meta := []interface{}{
+		map[string]interface{}{"title": "price", "type": "number", "unit": "€", "locale": "de"},
+		map[string]interface{}{"title": "qty", "type": "integer", "locale": "fr"},
+		map[string]interface{}{"title": "discount", "type": "number", "unit": "%"},
+	}
+	if !reflect.DeepEqual(got, meta) {
+		t.Errorf("column metadata mismatch.\nexpected: %v\ngot:      %v", meta, got)
+	}
+	if _, ok := st.Schema["items"].(map[string]interface{})["items"].([]interface{})[1].(map[string]interface{})["unit"]; ok {
+		t.Error("expected the source structure's schema not to be modified")
+	}
+}
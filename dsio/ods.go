@@ -0,0 +1,588 @@
+package dsio
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// OpenDocument xml namespaces
+const (
+	odsOfficeNS   = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odsTableNS    = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsTextNS     = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	odsManifestNS = "urn:oasis:names:tc:opendocument:xmlns:manifest:1.0"
+)
+
+const (
+	// odsMimeType is the content of an ods file's mimetype entry
+	odsMimeType = "application/vnd.oasis.opendocument.spreadsheet"
+	// odsMaxColumns caps the width of rows, guarding against huge
+	// number-columns-repeated attributes
+	odsMaxColumns = 1 << 14
+	// odsMaxRepeat caps how many times a repeated row is read
+	odsMaxRepeat = 1 << 20
+)
+
+// ODSReader implements the EntryReader interface for OpenDocument
+// spreadsheets, reading rows of one sheet as arrays of cell values.
+//
+// Cell values are read as strings & cast to the types of the structure's
+// schema like XLSXReader. Numeric, date & boolean cells are read from their
+// stored value rather than displayed text, so formatting like currency
+// symbols & thousands separators doesn't leak into values. Blank rows &
+// cells that trail a sheet's content, which spreadsheet applications write
+// to fill sheets out to their full size, are dropped
+type ODSReader struct {
+	st        *dataset.Structure
+	src       *TrackedReader
+	content   io.ReadCloser
+	dec       *xml.Decoder
+	sheetName string
+	types     []string
+	idx       int
+	err       error
+	// row is a row spread over several sheet rows by number-rows-repeated,
+	// repeat counts the copies of row left to read
+	row    []string
+	repeat int
+	// blank counts blank rows that haven't been read, blank rows are only
+	// read if a row with content follows
+	blank int
+}
+
+var _ EntryReader = (*ODSReader)(nil)
+
+// NewODSReader creates a reader from a structure and read source. ods files
+// are zip archives, the source is read in full before the reader is
+// returned
+func NewODSReader(st *dataset.Structure, r io.Reader) (*ODSReader, error) {
+	opts, err := dataset.NewODSOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	_, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+
+	src := NewTrackedReader(r)
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error reading ods file: %w", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("not an ods file: %s", err))
+	}
+
+	rdr := &ODSReader{st: st, src: src, sheetName: opts.SheetName, types: types}
+	for _, f := range zr.File {
+		if f.Name == "content.xml" {
+			if rdr.content, err = f.Open(); err != nil {
+				log.Debug(err.Error())
+				return nil, fmt.Errorf("error reading ods content: %w", err)
+			}
+			break
+		}
+	}
+	if rdr.content == nil {
+		return nil, newKindError(ErrFormatMismatch, "not an ods file: missing content.xml")
+	}
+	rdr.dec = xml.NewDecoder(bufio.NewReader(rdr.content))
+
+	if err := rdr.findSheet(); err != nil {
+		log.Debug(err.Error())
+		rdr.content.Close()
+		return nil, err
+	}
+	return rdr, nil
+}
+
+// findSheet advances the decoder into the sheet to read
+func (r *ODSReader) findSheet() error {
+	for {
+		tok, err := r.dec.Token()
+		if err == io.EOF {
+			if r.sheetName != "" {
+				return fmt.Errorf("ods document has no sheet named '%s'", r.sheetName)
+			}
+			return fmt.Errorf("ods document has no sheets")
+		} else if err != nil {
+			return fmt.Errorf("error reading ods content: %w", err)
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok || !isODSElement(se.Name, odsTableNS, "table") {
+			continue
+		}
+		if r.sheetName == "" || odsAttr(se, odsTableNS, "name") == r.sheetName {
+			return nil
+		}
+		if err := r.dec.Skip(); err != nil {
+			return fmt.Errorf("error reading ods content: %w", err)
+		}
+	}
+}
+
+// Structure gives this reader's structure
+func (r *ODSReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// EntriesRead gives the number of entries read
+func (r *ODSReader) EntriesRead() int {
+	return r.idx
+}
+
+// BytesProcessed gives the size of the ods file, which is read in full when
+// the reader is created
+func (r *ODSReader) BytesProcessed() int64 {
+	return int64(r.src.BytesRead())
+}
+
+// ReadEntry reads one row of the sheet
+func (r *ODSReader) ReadEntry() (Entry, error) {
+	for r.err == nil {
+		if r.repeat > 0 && r.blank > 0 {
+			r.blank--
+			return r.entry([]interface{}{}), nil
+		}
+		if r.repeat > 0 {
+			r.repeat--
+			return r.entry(decodeSheetCells(r.types, r.row, nil)), nil
+		}
+
+		cells, n, err := r.readRow()
+		if err != nil {
+			r.err = err
+			break
+		}
+		if len(cells) == 0 {
+			r.blank += n
+			continue
+		}
+		r.row, r.repeat = cells, n
+	}
+	return Entry{}, r.err
+}
+
+func (r *ODSReader) entry(v []interface{}) Entry {
+	ent := Entry{Index: r.idx, Value: v}
+	r.idx++
+	return ent
+}
+
+// readRow reads the next table row of the sheet, giving cell values without
+// trailing blank cells & the number of times the row repeats. readRow
+// returns io.EOF at the end of the sheet
+func (r *ODSReader) readRow() ([]string, int, error) {
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return nil, 0, odsReadError(err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if isODSElement(t.Name, odsTableNS, "table-row") {
+				return r.readCells(t)
+			}
+			// rows can be nested in groups, all other elements are skipped
+			switch t.Name.Local {
+			case "table-header-rows", "table-row-group", "table-rows":
+				if t.Name.Space == odsTableNS {
+					continue
+				}
+			}
+			if err := r.dec.Skip(); err != nil {
+				return nil, 0, odsReadError(err)
+			}
+		case xml.EndElement:
+			if isODSElement(t.Name, odsTableNS, "table") {
+				return nil, 0, io.EOF
+			}
+		}
+	}
+}
+
+// readCells reads the cells of a row element
+func (r *ODSReader) readCells(row xml.StartElement) ([]string, int, error) {
+	repeat, err := odsRepeat(row, odsTableNS, "number-rows-repeated", odsMaxRepeat)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var cells []string
+	blank := 0
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return nil, 0, odsReadError(err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != odsTableNS || (t.Name.Local != "table-cell" && t.Name.Local != "covered-table-cell") {
+				if err := r.dec.Skip(); err != nil {
+					return nil, 0, odsReadError(err)
+				}
+				continue
+			}
+			n, err := odsRepeat(t, odsTableNS, "number-columns-repeated", odsMaxColumns)
+			if err != nil {
+				return nil, 0, err
+			}
+			val, err := r.readCell(t)
+			if err != nil {
+				return nil, 0, err
+			}
+			// blank cells are only kept if a cell with content follows
+			if val == "" {
+				blank += n
+				continue
+			}
+			if len(cells)+blank+n > odsMaxColumns {
+				return nil, 0, fmt.Errorf("ods row %d has more than %d columns", r.idx, odsMaxColumns)
+			}
+			for ; blank > 0; blank-- {
+				cells = append(cells, "")
+			}
+			for i := 0; i < n; i++ {
+				cells = append(cells, val)
+			}
+		case xml.EndElement:
+			return cells, repeat, nil
+		}
+	}
+}
+
+// readCell gives the value of a cell element. Typed cells are read from
+// their value attributes, all others from the cell's text
+func (r *ODSReader) readCell(cell xml.StartElement) (string, error) {
+	var attr string
+	switch odsAttr(cell, odsOfficeNS, "value-type") {
+	case "float", "percentage", "currency":
+		attr = "value"
+	case "boolean":
+		attr = "boolean-value"
+	case "date":
+		attr = "date-value"
+	case "time":
+		attr = "time-value"
+	default:
+		return r.readText()
+	}
+	if err := r.dec.Skip(); err != nil {
+		return "", odsReadError(err)
+	}
+	return odsAttr(cell, odsOfficeNS, attr), nil
+}
+
+// readText reads the text of a cell's paragraphs, joining paragraphs with
+// newlines. Comments attached to cells aren't part of their text
+func (r *ODSReader) readText() (string, error) {
+	buf := &strings.Builder{}
+	paragraphs, depth, inParagraph := 0, 0, 0
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return "", odsReadError(err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space == odsOfficeNS && t.Name.Local == "annotation" {
+				if err := r.dec.Skip(); err != nil {
+					return "", odsReadError(err)
+				}
+				continue
+			}
+			depth++
+			if t.Name.Space != odsTextNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "p", "h":
+				if paragraphs > 0 {
+					buf.WriteByte('\n')
+				}
+				paragraphs++
+				inParagraph++
+			case "s":
+				n, err := odsRepeat(t, odsTextNS, "c", odsMaxColumns)
+				if err != nil {
+					return "", err
+				}
+				buf.WriteString(strings.Repeat(" ", n))
+			case "tab":
+				buf.WriteByte('\t')
+			case "line-break":
+				buf.WriteByte('\n')
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				return buf.String(), nil
+			}
+			depth--
+			if t.Name.Space == odsTextNS && (t.Name.Local == "p" || t.Name.Local == "h") {
+				inParagraph--
+			}
+		case xml.CharData:
+			if inParagraph > 0 {
+				buf.Write(t)
+			}
+		}
+	}
+}
+
+// Close finalizes the reader, indicating no more records will be read
+func (r *ODSReader) Close() error {
+	err := r.content.Close()
+	if cerr := r.src.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func isODSElement(name xml.Name, space, local string) bool {
+	return name.Space == space && name.Local == local
+}
+
+// odsAttr gives the value of an element's attribute, or an empty string
+func odsAttr(se xml.StartElement, space, local string) string {
+	for _, a := range se.Attr {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// odsRepeat reads a repeat count attribute, which defaults to 1
+func odsRepeat(se xml.StartElement, space, local string, max int) (int, error) {
+	s := odsAttr(se, space, local)
+	if s == "" {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid ods %s value '%s'", local, s)
+	}
+	// large repeats are common for blank rows & cells that fill out a sheet,
+	// clamping them doesn't change the values read
+	if n > max {
+		n = max
+	}
+	return n, nil
+}
+
+func odsReadError(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	log.Debug(err.Error())
+	return fmt.Errorf("error reading ods content: %w", err)
+}
+
+// ODSWriter implements the EntryWriter interface for OpenDocument
+// spreadsheets, writing array entries as rows of one sheet. Numbers &
+// booleans are written as typed cells, nested values as JSON text. Rows are
+// streamed to the destination as they're written
+type ODSWriter struct {
+	st          *dataset.Structure
+	out         *countingWriter
+	zw          *zip.Writer
+	w           *bufio.Writer
+	rowsWritten int
+}
+
+var _ EntryWriter = (*ODSWriter)(nil)
+
+// NewODSWriter creates a writer from a structure and write destination
+func NewODSWriter(st *dataset.Structure, w io.Writer) (*ODSWriter, error) {
+	opts, err := dataset.NewODSOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	sheetName := opts.SheetName
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	}
+
+	out := &countingWriter{w: w}
+	wr := &ODSWriter{st: st, out: out, zw: zip.NewWriter(out)}
+	if err := wr.writeHeader(sheetName); err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error writing ods: %w", err)
+	}
+	return wr, nil
+}
+
+// writeHeader writes the archive entries preceding content, and the start of
+// content up to the sheet's rows
+func (w *ODSWriter) writeHeader(sheetName string) error {
+	// the mimetype entry must come first & be stored uncompressed, so
+	// applications can identify the file by its leading bytes
+	mt, err := w.zw.CreateRaw(&zip.FileHeader{
+		Name:               "mimetype",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE([]byte(odsMimeType)),
+		CompressedSize64:   uint64(len(odsMimeType)),
+		UncompressedSize64: uint64(len(odsMimeType)),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mt, odsMimeType); err != nil {
+		return err
+	}
+
+	manifest, err := w.zw.Create("META-INF/manifest.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(manifest, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<manifest:manifest xmlns:manifest="%s" manifest:version="1.2">`+
+		`<manifest:file-entry manifest:full-path="/" manifest:version="1.2" manifest:media-type="%s"/>`+
+		`<manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>`+
+		`</manifest:manifest>`, odsManifestNS, odsMimeType); err != nil {
+		return err
+	}
+
+	content, err := w.zw.Create("content.xml")
+	if err != nil {
+		return err
+	}
+	w.w = bufio.NewWriter(content)
+	fmt.Fprintf(w.w, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<office:document-content xmlns:office="%s" xmlns:table="%s" xmlns:text="%s" office:version="1.2">`+
+		`<office:body><office:spreadsheet><table:table table:name="`, odsOfficeNS, odsTableNS, odsTextNS)
+	xml.EscapeText(w.w, []byte(sheetName))
+	_, err = w.w.WriteString(`">`)
+	return err
+}
+
+// Structure gives this writer's structure
+func (w *ODSWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// EntriesWritten gives the number of entries written
+func (w *ODSWriter) EntriesWritten() int {
+	return w.rowsWritten
+}
+
+// BytesProcessed gives the number of bytes written. Content is compressed,
+// writes lag the entries written
+func (w *ODSWriter) BytesProcessed() int64 {
+	return w.out.n
+}
+
+// WriteEntry writes one row to the sheet
+func (w *ODSWriter) WriteEntry(ent Entry) error {
+	arr, ok := ent.Value.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected array value to write ods row. got: %v", ent)
+	}
+	strs, err := encodeStrings(arr)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error encoding entry: %w", err)
+	}
+
+	w.w.WriteString("<table:table-row>")
+	for i, v := range arr {
+		switch v.(type) {
+		case nil:
+			w.w.WriteString("<table:table-cell/>")
+			continue
+		case int, int64, float64:
+			fmt.Fprintf(w.w, `<table:table-cell office:value-type="float" office:value="%s">`, strs[i])
+		case bool:
+			fmt.Fprintf(w.w, `<table:table-cell office:value-type="boolean" office:boolean-value="%s">`, strs[i])
+		default:
+			w.w.WriteString(`<table:table-cell office:value-type="string">`)
+		}
+		writeODSText(w.w, strs[i])
+		w.w.WriteString("</table:table-cell>")
+	}
+	if _, err := w.w.WriteString("</table:table-row>"); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing ods: %w", err)
+	}
+	w.rowsWritten++
+	return nil
+}
+
+// writeODSText writes text as cell paragraphs, one per line. xml readers
+// collapse runs of whitespace in paragraphs, so spaces & tabs are written
+// as elements where they'd be collapsed
+func writeODSText(w *bufio.Writer, s string) {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	for _, line := range strings.Split(s, "\n") {
+		w.WriteString("<text:p>")
+		for i := 0; i < len(line); {
+			switch line[i] {
+			case ' ':
+				j := i
+				for j < len(line) && line[j] == ' ' {
+					j++
+				}
+				n := j - i
+				if i > 0 && j < len(line) {
+					w.WriteByte(' ')
+					n--
+				}
+				if n == 1 {
+					w.WriteString("<text:s/>")
+				} else if n > 1 {
+					fmt.Fprintf(w, `<text:s text:c="%d"/>`, n)
+				}
+				i = j
+			case '\t':
+				w.WriteString("<text:tab/>")
+				i++
+			default:
+				j := strings.IndexAny(line[i:], " \t")
+				if j < 0 {
+					j = len(line) - i
+				}
+				xml.EscapeText(w, []byte(line[i:i+j]))
+				i += j
+			}
+		}
+		w.WriteString("</text:p>")
+	}
+}
+
+// Close finalizes the writer, indicating no more records will be written.
+// The destination is closed if it's an io.Closer, wrap it with
+// KeepWriterOpen to leave it open
+func (w *ODSWriter) Close() error {
+	if w.out.closed {
+		return nil
+	}
+	// sheets must have at least one row, an empty sheet gets a blank row,
+	// which readers drop
+	if w.rowsWritten == 0 {
+		w.w.WriteString("<table:table-row><table:table-cell/></table:table-row>")
+	}
+	w.w.WriteString("</table:table></office:spreadsheet></office:body></office:document-content>")
+	if err := w.w.Flush(); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing ods: %w", err)
+	}
+	if err := w.zw.Close(); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing ods: %w", err)
+	}
+	return w.out.Close()
+}
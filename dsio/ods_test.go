@@ -0,0 +1,246 @@
+package dsio
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+// odsFile packs sheet xml into an ods archive
+func odsFile(t *testing.T, tables string) []byte {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	f, err := zw.Create("content.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(f, `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<office:body><office:spreadsheet>`+tables+`</office:spreadsheet></office:body></office:document-content>`)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// odsValues reads the values of all entries
+func odsValues(t *testing.T, r EntryReader) []interface{} {
+	vals := []interface{}{}
+	for {
+		ent, err := r.ReadEntry()
+		if err == io.EOF {
+			return vals
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		vals = append(vals, ent.Value)
+	}
+}
+
+const odsTestTables = `
+<table:table table:name="Notes">
+	<table:table-row><table:table-cell office:value-type="string"><text:p>not data</text:p></table:table-cell></table:table-row>
+</table:table>
+<table:table table:name="Data">
+	<table:table-column table:number-columns-repeated="1024"/>
+	<table:table-header-rows>
+		<table:table-row>
+			<table:table-cell office:value-type="string"><text:p>city</text:p></table:table-cell>
+			<table:table-cell office:value-type="string"><text:p>budget</text:p></table:table-cell>
+			<table:table-cell office:value-type="string"><text:p>open</text:p></table:table-cell>
+			<table:table-cell table:number-columns-repeated="1021"/>
+		</table:table-row>
+	</table:table-header-rows>
+	<table:table-row>
+		<table:table-cell office:value-type="string"><text:p>Gen<text:span>ève</text:span></text:p><office:annotation><dc:creator>a</dc:creator><text:p>comment</text:p></office:annotation></table:table-cell>
+		<table:table-cell office:value-type="currency" office:currency="EUR" office:value="1234.5"><text:p>1.234,50 €</text:p></table:table-cell>
+		<table:table-cell office:value-type="boolean" office:boolean-value="true"><text:p>WAHR</text:p></table:table-cell>
+		<table:table-cell table:number-columns-repeated="1021"/>
+	</table:table-row>
+	<table:table-row table:number-rows-repeated="2"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+	<table:table-row table:number-rows-repeated="2">
+		<table:table-cell office:value-type="string"><text:p><text:s/>a<text:s text:c="2"/>b<text:tab/>c</text:p><text:p>d<text:line-break/>e</text:p></table:table-cell>
+		<table:table-cell/>
+		<table:table-cell office:value-type="float" office:value="0.25" table:number-columns-repeated="2"><text:p>25%</text:p></table:table-cell>
+	</table:table-row>
+	<table:table-row>
+		<table:covered-table-cell/>
+		<table:table-cell office:value-type="date" office:date-value="2020-03-01"><text:p>01.03.2020</text:p></table:table-cell>
+	</table:table-row>
+	<table:table-row table:number-rows-repeated="1048571"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+</table:table>`
+
+func TestODSReader(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "ods",
+		FormatConfig: map[string]interface{}{"sheetName": "Data"},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "budget", "type": "number"},
+					map[string]interface{}{"title": "open", "type": "boolean"},
+					map[string]interface{}{"title": "extra", "type": "string"},
+				},
+			},
+		},
+	}
+
+	data := odsFile(t, odsTestTables)
+	rdr, err := NewEntryReader(st, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := odsValues(t, rdr)
+
+	text := " a  b\tc\nd\ne"
+	expect := []interface{}{
+		[]interface{}{"city", "budget", "open"},
+		[]interface{}{"Genève", 1234.5, true},
+		[]interface{}{},
+		[]interface{}{},
+		[]interface{}{text, "", "0.25", "0.25"},
+		[]interface{}{text, "", "0.25", "0.25"},
+		[]interface{}{"", "2020-03-01"},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("result mismatch.\nexpected: %#v\ngot:      %#v", expect, got)
+	}
+	if rdr.(*ODSReader).EntriesRead() != len(expect) {
+		t.Errorf("expected %d entries read, got %d", len(expect), rdr.(*ODSReader).EntriesRead())
+	}
+	if rdr.(*ODSReader).BytesProcessed() != int64(len(data)) {
+		t.Errorf("expected %d bytes processed, got %d", len(data), rdr.(*ODSReader).BytesProcessed())
+	}
+	if err := rdr.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestODSReaderSheets(t *testing.T) {
+	data := odsFile(t, odsTestTables)
+	st := &dataset.Structure{Format: "ods", Schema: dataset.BaseSchemaArray}
+	rdr, err := NewODSReader(st, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := odsValues(t, rdr)
+	expect := []interface{}{[]interface{}{"not data"}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected the first sheet to be read by default. expected: %v, got: %v", expect, got)
+	}
+
+	errCases := []struct {
+		data []byte
+		opts map[string]interface{}
+		err  string
+	}{
+		{data, map[string]interface{}{"sheetName": "Missing"}, "ods document has no sheet named 'Missing'"},
+		{odsFile(t, ""), nil, "ods document has no sheets"},
+		{[]byte("city,budget"), nil, "not an ods file: zip: not a valid zip file"},
+		{data, map[string]interface{}{"sheetName": 1}, "invalid sheetName value: 1"},
+	}
+	for i, c := range errCases {
+		st := &dataset.Structure{Format: "ods", FormatConfig: c.opts, Schema: dataset.BaseSchemaArray}
+		if _, err := NewODSReader(st, bytes.NewReader(c.data)); err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestODSWriter(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "ods",
+		FormatConfig: map[string]interface{}{"sheetName": "Budgets & Notes"},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "a", "type": "string"},
+					map[string]interface{}{"title": "b", "type": "number"},
+					map[string]interface{}{"title": "c", "type": "integer"},
+					map[string]interface{}{"title": "d", "type": "boolean"},
+					map[string]interface{}{"title": "e", "type": "array"},
+					map[string]interface{}{"title": "f", "type": "string"},
+				},
+			},
+		},
+	}
+	entries := []Entry{
+		{Value: []interface{}{"<a> & b", 1.5, 2, true, []interface{}{"x"}, nil}},
+		{Value: []interface{}{"  spaced  out ", -0.25, int64(30), false, []interface{}{}, "\tline\r\nbreak"}},
+	}
+
+	buf := &bytes.Buffer{}
+	w, err := NewEntryWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ent := range entries {
+		if err := w.WriteEntry(ent); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteEntry(Entry{Value: map[string]interface{}{}}); err == nil {
+		t.Error("expected writing an object entry to error")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.(*ODSWriter).EntriesWritten() != 2 {
+		t.Errorf("expected 2 entries written, got %d", w.(*ODSWriter).EntriesWritten())
+	}
+	if w.(*ODSWriter).BytesProcessed() != int64(buf.Len()) {
+		t.Errorf("expected %d bytes processed, got %d", buf.Len(), w.(*ODSWriter).BytesProcessed())
+	}
+
+	// applications identify ods files by a leading uncompressed mimetype
+	if !bytes.Equal(buf.Bytes()[30:38], []byte("mimetype")) || !bytes.Contains(buf.Bytes()[:100], []byte(odsMimeType)) {
+		t.Errorf("expected archive to begin with an uncompressed mimetype entry")
+	}
+
+	rdr, err := NewEntryReader(st, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := odsValues(t, rdr)
+	expect := []interface{}{
+		[]interface{}{"<a> & b", 1.5, int64(2), true, []interface{}{"x"}},
+		[]interface{}{"  spaced  out ", -0.25, int64(30), false, []interface{}{}, "\tline\nbreak"},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("round trip mismatch.\nexpected: %#v\ngot:      %#v", expect, got)
+	}
+}
+
+func TestODSWriterEmpty(t *testing.T) {
+	st := &dataset.Structure{Format: "ods", Schema: dataset.BaseSchemaArray}
+	buf := &bytes.Buffer{}
+	w, err := NewODSWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("expected closing twice not to error, got: %s", err)
+	}
+
+	st.FormatConfig = map[string]interface{}{"sheetName": "Sheet1"}
+	rdr, err := NewODSReader(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := odsValues(t, rdr)
+	if len(got) != 0 {
+		t.Errorf("expected no entries, got: %v", got)
+	}
+}
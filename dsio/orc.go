@@ -0,0 +1,835 @@
+package dsio
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/qri-io/dataset"
+)
+
+// orc support is write-only & limited to flat schemas: every column is a
+// field of the root struct. Files are written without row indexes or bloom
+// filters. see https://orc.apache.org/specification/ORCv1 for the file format
+
+var orcMagic = []byte("ORC")
+
+// orc type kinds
+const (
+	orcTypeBoolean = 0
+	orcTypeLong    = 4
+	orcTypeDouble  = 6
+	orcTypeString  = 7
+	orcTypeStruct  = 12
+)
+
+// orc stream kinds
+const (
+	orcStreamPresent        = 0
+	orcStreamData           = 1
+	orcStreamLength         = 2
+	orcStreamDictionaryData = 3
+)
+
+// orc column encodings
+const (
+	orcEncodingDirect       = 0
+	orcEncodingDirectV2     = 2
+	orcEncodingDictionaryV2 = 3
+)
+
+// orc compression kinds
+const (
+	orcCompressionNone   = 0
+	orcCompressionZlib   = 1
+	orcCompressionSnappy = 2
+)
+
+const (
+	defaultORCStripeSize = 10000
+	// orcBlockSize is the largest number of uncompressed bytes in a
+	// compression chunk
+	orcBlockSize = 256 * 1024
+	// orcMaxRun is the most values an integer RLE v2 run holds
+	orcMaxRun = 512
+)
+
+// orcColumn is a column of an ORC file
+type orcColumn struct {
+	name string
+	kind int
+	// json marks string columns holding JSON encoded values of types ORC
+	// columns aren't written as
+	json     bool
+	encoding string
+	// values & hasNull accumulate file-level column statistics
+	values  uint64
+	hasNull bool
+}
+
+// orcStream is an encoded stream of a stripe
+type orcStream struct {
+	kind   int
+	column int
+	data   []byte
+}
+
+// ORCWriter implements the EntryWriter interface for the ORC data format.
+// Entries must be arrays matching a tabular schema. Rows are buffered &
+// written a stripe at a time, the file footer is written on Close
+type ORCWriter struct {
+	st             *dataset.Structure
+	wr             *countingWriter
+	cols           []orcColumn
+	compression    int
+	stripeSize     int
+	rows           [][]interface{}
+	stripes        [][]byte
+	stripeStats    [][]byte
+	numRows        uint64
+	entriesWritten int
+	closed         bool
+}
+
+var _ EntryWriter = (*ORCWriter)(nil)
+
+// NewORCWriter creates a writer from a structure and write destination.
+// Columns are typed from the structure's schema: integer columns are written
+// as LONG, number as DOUBLE, boolean as BOOLEAN & string as STRING. Columns of
+// other types are written as JSON STRING columns. Encoding hints in
+// ORCOptions columns can dictionary encode string columns or delta encode
+// integer columns
+func NewORCWriter(st *dataset.Structure, w io.Writer) (*ORCWriter, error) {
+	titles, types, err := terribleHackToGetHeaderRowAndTypes(st)
+	if err != nil || len(titles) == 0 {
+		err = newKindError(ErrBadSchema, "orc requires a tabular schema with at least one column")
+		log.Debug(err.Error())
+		return nil, err
+	}
+
+	opts, err := dataset.NewORCOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	ow := &ORCWriter{
+		st:          st,
+		wr:          &countingWriter{w: w},
+		compression: orcCompression(opts.Compression),
+		stripeSize:  defaultORCStripeSize,
+	}
+	if opts.StripeSize > 0 {
+		ow.stripeSize = opts.StripeSize
+	}
+
+	for i, title := range titles {
+		if title == "" {
+			title = dataset.AbstractColumnName(i)
+		}
+		col := orcColumn{name: title, kind: orcTypeString, json: true}
+		switch types[i] {
+		case "integer":
+			col.kind, col.json = orcTypeLong, false
+		case "number":
+			col.kind, col.json = orcTypeDouble, false
+		case "boolean":
+			col.kind, col.json = orcTypeBoolean, false
+		case "string":
+			col.json = false
+		}
+		ow.cols = append(ow.cols, col)
+	}
+
+	if err := ow.applyColumnCodecs(opts.Columns); err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	return ow, nil
+}
+
+// applyColumnCodecs sets column encodings from format config hints
+func (w *ORCWriter) applyColumnCodecs(hints map[string]dataset.ColumnCodec) error {
+	for title, hint := range hints {
+		i := 0
+		for i < len(w.cols) && w.cols[i].name != title {
+			i++
+		}
+		if i == len(w.cols) {
+			return fmt.Errorf("orc format config has hints for unknown column '%s'", title)
+		}
+		col := &w.cols[i]
+		switch hint.Encoding {
+		case dataset.ColumnEncodingDictionary:
+			if col.kind != orcTypeString {
+				return fmt.Errorf("column %s: dictionary encoding requires a string column", title)
+			}
+		case dataset.ColumnEncodingDelta:
+			if col.kind != orcTypeLong {
+				return fmt.Errorf("column %s: delta encoding requires an integer column", title)
+			}
+		}
+		col.encoding = hint.Encoding
+	}
+	return nil
+}
+
+// orcCompression gives the compression kind for an ORCOptions compression
+// name, defaulting to zlib
+func orcCompression(name string) int {
+	switch name {
+	case dataset.ORCNone:
+		return orcCompressionNone
+	case dataset.ORCSnappy:
+		return orcCompressionSnappy
+	}
+	return orcCompressionZlib
+}
+
+// Structure gives this writer's structure
+func (w *ORCWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// WriteEntry buffers a row, writing a stripe when StripeSize rows are
+// buffered
+func (w *ORCWriter) WriteEntry(ent Entry) error {
+	arr, ok := ent.Value.([]interface{})
+	if !ok {
+		err := fmt.Errorf("expected array value to write orc row. got: %T", ent.Value)
+		log.Debug(err.Error())
+		return err
+	}
+	if len(arr) > len(w.cols) {
+		err := fmt.Errorf("entry %d has %d values, schema has %d columns", ent.Index, len(arr), len(w.cols))
+		log.Debug(err.Error())
+		return err
+	}
+
+	row := make([]interface{}, len(w.cols))
+	for i, v := range arr {
+		ov, err := orcValue(w.cols[i], v)
+		if err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("entry %d column %s: %w", ent.Index, w.cols[i].name, err)
+		}
+		row[i] = ov
+	}
+	w.rows = append(w.rows, row)
+	w.entriesWritten++
+
+	if len(w.rows) >= w.stripeSize {
+		return w.writeStripe()
+	}
+	return nil
+}
+
+// orcValue converts a value to the go type written for a column: int64,
+// float64, bool or []byte
+func orcValue(col orcColumn, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch col.kind {
+	case orcTypeLong:
+		switch t := v.(type) {
+		case int:
+			return int64(t), nil
+		case int64:
+			return t, nil
+		case int32:
+			return int64(t), nil
+		case float64:
+			if t == math.Trunc(t) {
+				return int64(t), nil
+			}
+		}
+		return nil, fmt.Errorf("expected integer value, got %T", v)
+	case orcTypeDouble:
+		switch t := v.(type) {
+		case float64:
+			return t, nil
+		case float32:
+			return float64(t), nil
+		case int:
+			return float64(t), nil
+		case int64:
+			return float64(t), nil
+		}
+		return nil, fmt.Errorf("expected number value, got %T", v)
+	case orcTypeBoolean:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected boolean value, got %T", v)
+	}
+
+	if !col.json {
+		if s, ok := v.(string); ok {
+			return []byte(s), nil
+		}
+		return nil, fmt.Errorf("expected string value, got %T", v)
+	}
+	return json.Marshal(v)
+}
+
+// writeStripe writes buffered rows as a stripe: the data streams of each
+// column followed by the stripe footer
+func (w *ORCWriter) writeStripe() error {
+	if w.wr.n == 0 {
+		if _, err := w.wr.Write(orcMagic); err != nil {
+			return err
+		}
+	}
+	if len(w.rows) == 0 {
+		return nil
+	}
+
+	offset := w.wr.n
+	var streams []orcStream
+	// the root struct has no nulls & no streams
+	encodings := [][]byte{orcAppendUint(nil, 1, orcEncodingDirect)}
+	stats := [][]byte{orcColumnStats(uint64(len(w.rows)), false)}
+	for i := range w.cols {
+		col := &w.cols[i]
+		vals := make([]interface{}, 0, len(w.rows))
+		present := make([]bool, len(w.rows))
+		for j, row := range w.rows {
+			if row[i] != nil {
+				vals = append(vals, row[i])
+				present[j] = true
+			}
+		}
+		hasNull := len(vals) < len(w.rows)
+		if hasNull {
+			streams = append(streams, orcStream{orcStreamPresent, i + 1, orcEncodeBooleans(present)})
+		}
+		col.values += uint64(len(vals))
+		col.hasNull = col.hasNull || hasNull
+		stats = append(stats, orcColumnStats(uint64(len(vals)), hasNull))
+
+		encoding := orcAppendUint(nil, 1, orcEncodingDirect)
+		switch col.kind {
+		case orcTypeLong:
+			ints := make([]int64, len(vals))
+			for j, v := range vals {
+				ints[j] = v.(int64)
+			}
+			data := orcEncodeInts(ints, true, col.encoding == dataset.ColumnEncodingDelta)
+			streams = append(streams, orcStream{orcStreamData, i + 1, data})
+			encoding = orcAppendUint(nil, 1, orcEncodingDirectV2)
+		case orcTypeDouble:
+			data := make([]byte, 0, len(vals)*8)
+			for _, v := range vals {
+				data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v.(float64)))
+			}
+			streams = append(streams, orcStream{orcStreamData, i + 1, data})
+		case orcTypeBoolean:
+			bs := make([]bool, len(vals))
+			for j, v := range vals {
+				bs[j] = v.(bool)
+			}
+			streams = append(streams, orcStream{orcStreamData, i + 1, orcEncodeBooleans(bs)})
+		case orcTypeString:
+			strs := make([][]byte, len(vals))
+			for j, v := range vals {
+				strs[j] = v.([]byte)
+			}
+			if col.encoding == dataset.ColumnEncodingDictionary {
+				dict, idxs := orcDictionary(strs)
+				var data []byte
+				lengths := make([]int64, len(dict))
+				for j, s := range dict {
+					data = append(data, s...)
+					lengths[j] = int64(len(s))
+				}
+				streams = append(streams,
+					orcStream{orcStreamData, i + 1, orcEncodeInts(idxs, false, false)},
+					orcStream{orcStreamDictionaryData, i + 1, data},
+					orcStream{orcStreamLength, i + 1, orcEncodeInts(lengths, false, false)},
+				)
+				encoding = orcAppendUint(nil, 1, orcEncodingDictionaryV2)
+				encoding = orcAppendUint(encoding, 2, uint64(len(dict)))
+			} else {
+				var data []byte
+				lengths := make([]int64, len(strs))
+				for j, s := range strs {
+					data = append(data, s...)
+					lengths[j] = int64(len(s))
+				}
+				streams = append(streams,
+					orcStream{orcStreamData, i + 1, data},
+					orcStream{orcStreamLength, i + 1, orcEncodeInts(lengths, false, false)},
+				)
+				encoding = orcAppendUint(nil, 1, orcEncodingDirectV2)
+			}
+		}
+		encodings = append(encodings, encoding)
+	}
+
+	var footer []byte
+	var dataLength uint64
+	for _, s := range streams {
+		data, err := w.compress(s.data)
+		if err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("error compressing orc stream: %w", err)
+		}
+		if _, err := w.wr.Write(data); err != nil {
+			return err
+		}
+		dataLength += uint64(len(data))
+
+		stream := orcAppendUint(nil, 1, uint64(s.kind))
+		stream = orcAppendUint(stream, 2, uint64(s.column))
+		stream = orcAppendUint(stream, 3, uint64(len(data)))
+		footer = orcAppendBytes(footer, 1, stream)
+	}
+	for _, enc := range encodings {
+		footer = orcAppendBytes(footer, 2, enc)
+	}
+	footer = orcAppendBytes(footer, 3, []byte("UTC"))
+	footer, err := w.compress(footer)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error compressing orc stripe footer: %w", err)
+	}
+	if _, err := w.wr.Write(footer); err != nil {
+		return err
+	}
+
+	info := orcAppendUint(nil, 1, uint64(offset))
+	info = orcAppendUint(info, 2, 0)
+	info = orcAppendUint(info, 3, dataLength)
+	info = orcAppendUint(info, 4, uint64(len(footer)))
+	info = orcAppendUint(info, 5, uint64(len(w.rows)))
+	w.stripes = append(w.stripes, info)
+
+	var stripeStats []byte
+	for _, s := range stats {
+		stripeStats = orcAppendBytes(stripeStats, 1, s)
+	}
+	w.stripeStats = append(w.stripeStats, stripeStats)
+
+	w.numRows += uint64(len(w.rows))
+	w.rows = w.rows[:0]
+	return nil
+}
+
+// compress splits a stream into chunks of up to orcBlockSize bytes, each
+// compressed with the file's codec & prefixed with a 3 byte header. Chunks
+// that don't shrink when compressed are written as-is
+func (w *ORCWriter) compress(data []byte) ([]byte, error) {
+	if w.compression == orcCompressionNone {
+		return data, nil
+	}
+
+	var out []byte
+	for start := 0; start < len(data); start += orcBlockSize {
+		chunk := data[start:min(start+orcBlockSize, len(data))]
+		var compressed []byte
+		switch w.compression {
+		case orcCompressionSnappy:
+			compressed = snappyEncode(chunk)
+		case orcCompressionZlib:
+			// zlib streams are raw deflate, without zlib headers
+			buf := &bytes.Buffer{}
+			fw, err := flate.NewWriter(buf, flate.DefaultCompression)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := fw.Write(chunk); err != nil {
+				return nil, err
+			}
+			if err := fw.Close(); err != nil {
+				return nil, err
+			}
+			compressed = buf.Bytes()
+		}
+
+		header := len(compressed) << 1
+		if len(compressed) >= len(chunk) {
+			compressed, header = chunk, len(chunk)<<1|1
+		}
+		out = append(out, byte(header), byte(header>>8), byte(header>>16))
+		out = append(out, compressed...)
+	}
+	return out, nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *ORCWriter) EntriesWritten() int {
+	return w.entriesWritten
+}
+
+// BytesProcessed gives the number of bytes written. rows are buffered until a
+// stripe is written
+func (w *ORCWriter) BytesProcessed() int64 {
+	return w.wr.n
+}
+
+// Close writes any buffered rows, stripe statistics & the file footer. The
+// destination is closed if it's an io.Closer, wrap it with KeepWriterOpen to
+// leave it open
+func (w *ORCWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.writeStripe(); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing orc stripe: %w", err)
+	}
+	contentLength := w.wr.n
+
+	var metadata []byte
+	for _, s := range w.stripeStats {
+		metadata = orcAppendBytes(metadata, 1, s)
+	}
+
+	root := orcAppendUint(nil, 1, orcTypeStruct)
+	var subtypes []byte
+	for i := range w.cols {
+		subtypes = binary.AppendUvarint(subtypes, uint64(i+1))
+	}
+	root = orcAppendBytes(root, 2, subtypes)
+	for _, col := range w.cols {
+		root = orcAppendBytes(root, 3, []byte(col.name))
+	}
+
+	footer := orcAppendUint(nil, 1, uint64(len(orcMagic)))
+	footer = orcAppendUint(footer, 2, uint64(contentLength))
+	for _, s := range w.stripes {
+		footer = orcAppendBytes(footer, 3, s)
+	}
+	footer = orcAppendBytes(footer, 4, root)
+	for _, col := range w.cols {
+		footer = orcAppendBytes(footer, 4, orcAppendUint(nil, 1, uint64(col.kind)))
+	}
+	footer = orcAppendUint(footer, 6, w.numRows)
+	footer = orcAppendBytes(footer, 7, orcColumnStats(w.numRows, false))
+	for _, col := range w.cols {
+		footer = orcAppendBytes(footer, 7, orcColumnStats(col.values, col.hasNull))
+	}
+	footer = orcAppendUint(footer, 8, 0)
+
+	metadata, err := w.compress(metadata)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error compressing orc metadata: %w", err)
+	}
+	if footer, err = w.compress(footer); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error compressing orc footer: %w", err)
+	}
+
+	// the postscript is never compressed, it's length is the last byte
+	ps := orcAppendUint(nil, 1, uint64(len(footer)))
+	ps = orcAppendUint(ps, 2, uint64(w.compression))
+	ps = orcAppendUint(ps, 3, orcBlockSize)
+	ps = orcAppendBytes(ps, 4, []byte{0, 12})
+	ps = orcAppendUint(ps, 5, uint64(len(metadata)))
+	ps = orcAppendBytes(ps, 8000, orcMagic)
+
+	tail := append(metadata, footer...)
+	tail = append(tail, ps...)
+	tail = append(tail, byte(len(ps)))
+	if _, err := w.wr.Write(tail); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing orc footer: %w", err)
+	}
+	return w.wr.Close()
+}
+
+// orcAppendUint appends a varint protobuf field
+func orcAppendUint(buf []byte, num int, v uint64) []byte {
+	buf = protoAppendTag(buf, num, protoVarint)
+	return binary.AppendUvarint(buf, v)
+}
+
+// orcAppendBytes appends a length-delimited protobuf field
+func orcAppendBytes(buf []byte, num int, b []byte) []byte {
+	return protoAppendBytes(protoAppendTag(buf, num, protoBytes), b)
+}
+
+// orcColumnStats encodes ColumnStatistics with value counts & null presence
+func orcColumnStats(values uint64, hasNull bool) []byte {
+	stats := orcAppendUint(nil, 1, values)
+	if hasNull {
+		return orcAppendUint(stats, 10, 1)
+	}
+	return orcAppendUint(stats, 10, 0)
+}
+
+// orcDictionary gives the distinct strings of a column in sorted order, and
+// the index of each value in the dictionary
+func orcDictionary(strs [][]byte) ([][]byte, []int64) {
+	seen := map[string]int64{}
+	var dict [][]byte
+	for _, s := range strs {
+		if _, ok := seen[string(s)]; !ok {
+			seen[string(s)] = 0
+			dict = append(dict, s)
+		}
+	}
+	sort.Slice(dict, func(i, j int) bool { return bytes.Compare(dict[i], dict[j]) < 0 })
+	for i, s := range dict {
+		seen[string(s)] = int64(i)
+	}
+	idxs := make([]int64, len(strs))
+	for i, s := range strs {
+		idxs[i] = seen[string(s)]
+	}
+	return dict, idxs
+}
+
+// orcEncodeBooleans bit-packs booleans, most significant bit first, then
+// byte run length encodes them
+func orcEncodeBooleans(bs []bool) []byte {
+	packed := make([]byte, (len(bs)+7)/8)
+	for i, b := range bs {
+		if b {
+			packed[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return orcEncodeBytes(packed)
+}
+
+// orcEncodeBytes encodes bytes with the byte run length encoding: runs of 3
+// to 130 repeated bytes & up to 128 literal bytes
+func orcEncodeBytes(vals []byte) []byte {
+	var buf, literals []byte
+	flush := func() {
+		if len(literals) > 0 {
+			buf = append(buf, byte(-len(literals)))
+			buf = append(buf, literals...)
+			literals = literals[:0]
+		}
+	}
+
+	for i := 0; i < len(vals); {
+		j := i + 1
+		for j < len(vals) && j-i < 130 && vals[j] == vals[i] {
+			j++
+		}
+		if j-i >= 3 {
+			flush()
+			buf = append(buf, byte(j-i-3), vals[i])
+			i = j
+			continue
+		}
+		literals = append(literals, vals[i])
+		if len(literals) == 128 {
+			flush()
+		}
+		i++
+	}
+	flush()
+	return buf
+}
+
+// orcEncodeInts encodes integers with the integer run length encoding v2.
+// signed values are zigzag encoded. Runs of 3 to 10 repeated values are
+// written as SHORT_REPEAT runs & other values as DIRECT runs. delta writes
+// monotonic runs of 3 or more values as DELTA runs
+func orcEncodeInts(vals []int64, signed, delta bool) []byte {
+	var buf []byte
+	var literals []int64
+	flush := func() {
+		for len(literals) > 0 {
+			n := min(len(literals), orcMaxRun)
+			buf = orcAppendDirect(buf, literals[:n], signed)
+			literals = literals[n:]
+		}
+	}
+
+	for i := 0; i < len(vals); {
+		if delta {
+			if n := orcDeltaRun(vals[i:]); n >= 3 {
+				flush()
+				buf = orcAppendDelta(buf, vals[i:i+n], signed)
+				i += n
+				continue
+			}
+		}
+		j := i + 1
+		for j < len(vals) && j-i < 10 && vals[j] == vals[i] {
+			j++
+		}
+		if j-i >= 3 {
+			flush()
+			buf = orcAppendShortRepeat(buf, vals[i], j-i, signed)
+			i = j
+			continue
+		}
+		literals = append(literals, vals[i])
+		i++
+	}
+	flush()
+	return buf
+}
+
+// orcZigzag maps signed integers to unsigned, small magnitudes to small
+// values
+func orcZigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// orcAppendShortRepeat appends a run of 3 to 10 repeats of a value
+func orcAppendShortRepeat(buf []byte, v int64, n int, signed bool) []byte {
+	u := uint64(v)
+	if signed {
+		u = orcZigzag(v)
+	}
+	width := max(1, (bits.Len64(u)+7)/8)
+	buf = append(buf, byte((width-1)<<3|(n-3)))
+	for b := width - 1; b >= 0; b-- {
+		buf = append(buf, byte(u>>(8*b)))
+	}
+	return buf
+}
+
+// orcAppendDirect appends up to orcMaxRun values bit-packed to the width of
+// the largest
+func orcAppendDirect(buf []byte, vals []int64, signed bool) []byte {
+	us := make([]uint64, len(vals))
+	width := 0
+	for i, v := range vals {
+		us[i] = uint64(v)
+		if signed {
+			us[i] = orcZigzag(v)
+		}
+		width = max(width, bits.Len64(us[i]))
+	}
+	width = orcClosestWidth(width)
+	n := len(vals) - 1
+	buf = append(buf, byte(1<<6|orcWidthCode(width)<<1|n>>8), byte(n))
+	return orcAppendPacked(buf, us, width)
+}
+
+// orcDeltaRun gives the length of the monotonic run at the start of vals,
+// up to orcMaxRun values. runs end where a difference overflows
+func orcDeltaRun(vals []int64) int {
+	if len(vals) < 2 {
+		return len(vals)
+	}
+	base, ok := orcDelta(vals[0], vals[1])
+	if !ok {
+		return 1
+	}
+	n := 2
+	for n < len(vals) && n < orcMaxRun {
+		d, ok := orcDelta(vals[n-1], vals[n])
+		if !ok || base < 0 && d > 0 || base >= 0 && d < 0 {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// orcDelta gives b - a, reporting if the difference & it's magnitude don't
+// overflow
+func orcDelta(a, b int64) (int64, bool) {
+	d := b - a
+	if (b^a) < 0 && (d^b) < 0 || d == math.MinInt64 {
+		return 0, false
+	}
+	return d, true
+}
+
+// orcAppendDelta appends a monotonic run of 3 to orcMaxRun values found by
+// orcDeltaRun. the first value & difference are written as varints &
+// following differences bit-packed by magnitude, or left out when every
+// difference is the same
+func orcAppendDelta(buf []byte, vals []int64, signed bool) []byte {
+	base := vals[1] - vals[0]
+	deltas := make([]uint64, len(vals)-2)
+	fixed := true
+	width := 0
+	for i := range deltas {
+		d := vals[i+2] - vals[i+1]
+		fixed = fixed && d == base
+		if d < 0 {
+			d = -d
+		}
+		deltas[i] = uint64(d)
+		width = max(width, bits.Len64(deltas[i]))
+	}
+
+	code := 0
+	if !fixed {
+		// a width code of 0 marks fixed runs, so 1 bit deltas are written 2
+		// bits wide
+		width = max(2, orcClosestWidth(width))
+		code = orcWidthCode(width)
+	}
+	n := len(vals) - 1
+	buf = append(buf, byte(3<<6|code<<1|n>>8), byte(n))
+	if signed {
+		buf = binary.AppendVarint(buf, vals[0])
+	} else {
+		buf = binary.AppendUvarint(buf, uint64(vals[0]))
+	}
+	buf = binary.AppendVarint(buf, base)
+	if fixed {
+		return buf
+	}
+	return orcAppendPacked(buf, deltas, width)
+}
+
+// orcAppendPacked bit-packs values big-endian, most significant bit first,
+// padding the last byte
+func orcAppendPacked(buf []byte, us []uint64, width int) []byte {
+	packed := make([]byte, (len(us)*width+7)/8)
+	for i, u := range us {
+		for b := 0; b < width; b++ {
+			if u>>(width-1-b)&1 == 1 {
+				bit := i*width + b
+				packed[bit/8] |= 0x80 >> (bit % 8)
+			}
+		}
+	}
+	return append(buf, packed...)
+}
+
+// orcClosestWidth rounds a bit width up to one RLE v2 can encode
+func orcClosestWidth(n int) int {
+	switch {
+	case n == 0:
+		return 1
+	case n <= 24:
+		return n
+	case n <= 26:
+		return 26
+	case n <= 28:
+		return 28
+	case n <= 30:
+		return 30
+	case n <= 32:
+		return 32
+	case n <= 40:
+		return 40
+	case n <= 48:
+		return 48
+	case n <= 56:
+		return 56
+	}
+	return 64
+}
+
+// orcWidthCode gives the 5 bit code of a width given by orcClosestWidth
+func orcWidthCode(width int) int {
+	switch {
+	case width <= 24:
+		return width - 1
+	case width <= 32:
+		return 24 + (width-26)/2
+	}
+	return 28 + (width-40)/8
+}
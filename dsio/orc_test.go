@@ -0,0 +1,667 @@
+package dsio
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+var orcTestStructure = &dataset.Structure{
+	Format: "orc",
+	Schema: map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "id", "type": "integer"},
+				map[string]interface{}{"title": "name", "type": "string"},
+				map[string]interface{}{"title": "score", "type": "number"},
+				map[string]interface{}{"title": "ok", "type": "boolean"},
+				map[string]interface{}{"title": "tags", "type": "array"},
+			},
+		},
+	},
+}
+
+// orcTestRows gives rows exercising runs, nulls & wide values
+func orcTestRows(n int) [][]interface{} {
+	names := []string{"apple", "banana", "cherry"}
+	rows := make([][]interface{}, n)
+	for i := range rows {
+		var id interface{} = int64(1000 + i*3)
+		if i%7 == 3 {
+			id = nil
+		}
+		if i == 5 {
+			id = int64(math.MinInt64)
+		}
+		var tags interface{}
+		if i%2 == 0 {
+			tags = []interface{}{"a", float64(i)}
+		}
+		rows[i] = []interface{}{id, names[i%3], float64(i) / 4, i%3 == 0, tags}
+	}
+	return rows
+}
+
+func writeORCTest(t *testing.T, st *dataset.Structure, rows [][]interface{}) []byte {
+	buf := &bytes.Buffer{}
+	w, err := NewORCWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		if err := w.WriteEntry(Entry{Index: i, Value: row}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.EntriesWritten() != len(rows) {
+		t.Errorf("expected %d entries written, got: %d", len(rows), w.EntriesWritten())
+	}
+	if w.BytesProcessed() != int64(buf.Len()) {
+		t.Errorf("expected %d bytes processed, got: %d", buf.Len(), w.BytesProcessed())
+	}
+	return buf.Bytes()
+}
+
+func TestORCWriter(t *testing.T) {
+	rows := orcTestRows(100)
+	cases := []struct {
+		config  map[string]interface{}
+		stripes int
+	}{
+		{nil, 1},
+		{map[string]interface{}{"compression": "none"}, 1},
+		{map[string]interface{}{"compression": "snappy", "stripeSize": 30}, 4},
+		{map[string]interface{}{"compression": "zlib", "stripeSize": 100}, 1},
+		{map[string]interface{}{"columns": map[string]interface{}{
+			"id":   map[string]interface{}{"encoding": "delta"},
+			"name": map[string]interface{}{"encoding": "dictionary"},
+		}}, 1},
+		{map[string]interface{}{"compression": "none", "columns": map[string]interface{}{
+			"id":   map[string]interface{}{"encoding": "delta"},
+			"tags": map[string]interface{}{"encoding": "dictionary"},
+		}}, 1},
+	}
+
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "orc", Schema: orcTestStructure.Schema, FormatConfig: c.config}
+		f, err := orcTestRead(writeORCTest(t, st, rows))
+		if err != nil {
+			t.Errorf("case %d: %s", i, err)
+			continue
+		}
+		if f.stripes != c.stripes {
+			t.Errorf("case %d: expected %d stripes, got: %d", i, c.stripes, f.stripes)
+		}
+		if !reflect.DeepEqual(f.names, []string{"id", "name", "score", "ok", "tags"}) {
+			t.Errorf("case %d: column names mismatch: %v", i, f.names)
+		}
+		if !reflect.DeepEqual(f.kinds, []int{orcTypeStruct, orcTypeLong, orcTypeString, orcTypeDouble, orcTypeBoolean, orcTypeString}) {
+			t.Errorf("case %d: column types mismatch: %v", i, f.kinds)
+		}
+		if len(f.rows) != len(rows) {
+			t.Errorf("case %d: expected %d rows, got: %d", i, len(rows), len(f.rows))
+			continue
+		}
+		for j, row := range rows {
+			expect := []interface{}{row[0], row[1], row[2], row[3], nil}
+			if row[4] != nil {
+				expect[4] = fmt.Sprintf(`["a",%d]`, j)
+			}
+			if !reflect.DeepEqual(f.rows[j], expect) {
+				t.Errorf("case %d row %d mismatch. expected: %v, got: %v", i, j, expect, f.rows[j])
+				break
+			}
+		}
+	}
+}
+
+func TestORCWriterEmpty(t *testing.T) {
+	f, err := orcTestRead(writeORCTest(t, orcTestStructure, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.stripes != 0 || len(f.rows) != 0 {
+		t.Errorf("expected no stripes or rows, got %d stripes, %d rows", f.stripes, len(f.rows))
+	}
+}
+
+func TestORCWriterErrors(t *testing.T) {
+	cases := []struct {
+		st  *dataset.Structure
+		err string
+	}{
+		{&dataset.Structure{Format: "orc", Schema: dataset.BaseSchemaObject}, "orc requires a tabular schema with at least one column"},
+		{&dataset.Structure{Format: "orc", Schema: orcTestStructure.Schema, FormatConfig: map[string]interface{}{"compression": "lz4"}}, "unsupported orc compression: lz4"},
+		{&dataset.Structure{Format: "orc", Schema: orcTestStructure.Schema, FormatConfig: map[string]interface{}{
+			"columns": map[string]interface{}{"nope": map[string]interface{}{"encoding": "plain"}},
+		}}, "orc format config has hints for unknown column 'nope'"},
+		{&dataset.Structure{Format: "orc", Schema: orcTestStructure.Schema, FormatConfig: map[string]interface{}{
+			"columns": map[string]interface{}{"id": map[string]interface{}{"encoding": "dictionary"}},
+		}}, "column id: dictionary encoding requires a string column"},
+		{&dataset.Structure{Format: "orc", Schema: orcTestStructure.Schema, FormatConfig: map[string]interface{}{
+			"columns": map[string]interface{}{"score": map[string]interface{}{"encoding": "delta"}},
+		}}, "column score: delta encoding requires an integer column"},
+	}
+	for i, c := range cases {
+		if _, err := NewORCWriter(c.st, ioutil.Discard); err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+
+	w, err := NewORCWriter(orcTestStructure, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Value: map[string]interface{}{}}); err == nil || !strings.Contains(err.Error(), "expected array value") {
+		t.Errorf("expected object entry to error, got: %v", err)
+	}
+	if err := w.WriteEntry(Entry{Index: 2, Value: []interface{}{"one"}}); err == nil || err.Error() != "entry 2 column id: expected integer value, got string" {
+		t.Errorf("expected type mismatch error, got: %v", err)
+	}
+
+	if _, err := NewEntryReader(&dataset.Structure{Format: "orc", Schema: dataset.BaseSchemaArray}, &bytes.Buffer{}); err == nil {
+		t.Error("expected creating an orc reader to error")
+	}
+}
+
+func TestORCEncodeInts(t *testing.T) {
+	cases := [][]int64{
+		{},
+		{7},
+		{1, 1, 1},
+		{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5},
+		{-1, 2, -3, 4, -5},
+		{math.MaxInt64, math.MinInt64, 0, math.MaxInt64},
+		{1, 2, 3, 4, 5, 6},
+		{10, 8, 6, 5, 5, 1, -30},
+		{0, 1, 2, 4, 5, 7},
+		{math.MinInt64, 0, math.MaxInt64, 0},
+		{3, 3, 2, 9, 9, 9, 9, 100},
+	}
+	long := make([]int64, 1500)
+	for i := range long {
+		long[i] = int64(i * i)
+	}
+	cases = append(cases, long)
+
+	for i, vals := range cases {
+		for _, delta := range []bool{false, true} {
+			got, err := orcTestDecodeInts(orcEncodeInts(vals, true, delta), true)
+			if err != nil {
+				t.Errorf("case %d delta %t: %s", i, delta, err)
+				continue
+			}
+			if len(vals) == 0 && len(got) == 0 {
+				continue
+			}
+			if !reflect.DeepEqual(got, vals) {
+				t.Errorf("case %d delta %t mismatch. expected: %v, got: %v", i, delta, vals, got)
+			}
+		}
+	}
+
+	unsigned := []int64{0, 1, 300, 300, 300, 70000, 1 << 40}
+	got, err := orcTestDecodeInts(orcEncodeInts(unsigned, false, false), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, unsigned) {
+		t.Errorf("unsigned mismatch. expected: %v, got: %v", unsigned, got)
+	}
+}
+
+// TestORCSpecExamples checks encoders & the test decoder against the
+// examples of the ORC specification
+func TestORCSpecExamples(t *testing.T) {
+	if got := orcEncodeInts([]int64{10000, 10000, 10000, 10000, 10000}, false, false); !bytes.Equal(got, []byte{0x0a, 0x27, 0x10}) {
+		t.Errorf("SHORT_REPEAT mismatch: %x", got)
+	}
+	if got := orcEncodeInts([]int64{23713, 43806, 57005, 48879}, false, false); !bytes.Equal(got, []byte{0x5e, 0x03, 0x5c, 0xa1, 0xab, 0x1e, 0xde, 0xad, 0xbe, 0xef}) {
+		t.Errorf("DIRECT mismatch: %x", got)
+	}
+	if got := orcEncodeBytes(make([]byte, 100)); !bytes.Equal(got, []byte{0x61, 0x00}) {
+		t.Errorf("byte run mismatch: %x", got)
+	}
+	if got := orcEncodeBytes([]byte{0x44, 0x45}); !bytes.Equal(got, []byte{0xfe, 0x44, 0x45}) {
+		t.Errorf("byte literals mismatch: %x", got)
+	}
+
+	// the specification packs deltas 4 bits wide where the writer uses 3
+	primes := []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}
+	got, err := orcTestDecodeInts([]byte{0xc6, 0x09, 0x02, 0x02, 0x22, 0x42, 0x42, 0x46}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, primes) {
+		t.Errorf("DELTA decode mismatch: %v", got)
+	}
+	if got := orcEncodeInts(primes, false, true); !bytes.Equal(got, []byte{0xc4, 0x09, 0x02, 0x02, 0x4a, 0x28, 0xa6}) {
+		t.Errorf("DELTA mismatch: %x", got)
+	}
+}
+
+func TestORCEncodeBooleans(t *testing.T) {
+	bs := make([]bool, 2000)
+	for i := range bs {
+		bs[i] = i%5 == 0 || i > 1200
+	}
+	got, err := orcTestDecodeBooleans(orcEncodeBooleans(bs), len(bs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, bs) {
+		t.Error("boolean round trip mismatch")
+	}
+}
+
+// orcTestFile is an ORC file read by orcTestRead
+type orcTestFile struct {
+	stripes int
+	kinds   []int
+	names   []string
+	rows    [][]interface{}
+}
+
+// orcTestRead decodes an ORC file written by ORCWriter, following the
+// specification independently of the writer
+func orcTestRead(data []byte) (*orcTestFile, error) {
+	if len(data) < 4 || !bytes.Equal(data[:3], orcMagic) {
+		return nil, fmt.Errorf("missing ORC header")
+	}
+	psLen := int(data[len(data)-1])
+	end := len(data) - 1 - psLen
+	ps, err := orcTestFields(data[end : len(data)-1])
+	if err != nil {
+		return nil, err
+	}
+	if string(ps[8000][0].([]byte)) != "ORC" {
+		return nil, fmt.Errorf("missing postscript magic")
+	}
+	compression := ps[2][0].(uint64)
+	footerLen := int(ps[1][0].(uint64))
+	metaLen := int(ps[5][0].(uint64))
+	if v := ps[4][0].([]byte); !bytes.Equal(v, []byte{0, 12}) {
+		return nil, fmt.Errorf("unexpected version: %v", v)
+	}
+
+	footerData, err := orcTestDecompress(compression, data[end-footerLen:end])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := orcTestDecompress(compression, data[end-footerLen-metaLen:end-footerLen]); err != nil {
+		return nil, fmt.Errorf("metadata: %w", err)
+	}
+	footer, err := orcTestFields(footerData)
+	if err != nil {
+		return nil, err
+	}
+	if footer[1][0].(uint64) != 3 {
+		return nil, fmt.Errorf("expected header length 3")
+	}
+
+	f := &orcTestFile{stripes: len(footer[3])}
+	for i, t := range footer[4] {
+		typ, err := orcTestFields(t.([]byte))
+		if err != nil {
+			return nil, err
+		}
+		f.kinds = append(f.kinds, int(typ[1][0].(uint64)))
+		if i == 0 {
+			for _, name := range typ[3] {
+				f.names = append(f.names, string(name.([]byte)))
+			}
+		}
+	}
+	if len(footer[7]) != len(f.kinds) {
+		return nil, fmt.Errorf("expected statistics for %d columns, got: %d", len(f.kinds), len(footer[7]))
+	}
+
+	var contentLength uint64
+	for _, s := range footer[3] {
+		info, err := orcTestFields(s.([]byte))
+		if err != nil {
+			return nil, err
+		}
+		offset, dataLen, footLen, numRows := info[1][0].(uint64), info[3][0].(uint64), info[4][0].(uint64), int(info[5][0].(uint64))
+		contentLength = offset + dataLen + footLen
+		sfData, err := orcTestDecompress(compression, data[offset+dataLen:contentLength])
+		if err != nil {
+			return nil, err
+		}
+		sf, err := orcTestFields(sfData)
+		if err != nil {
+			return nil, err
+		}
+
+		// streams are laid out in footer order, grouped by column
+		streams := map[[2]uint64][]byte{}
+		pos := offset
+		for _, s := range sf[1] {
+			st, err := orcTestFields(s.([]byte))
+			if err != nil {
+				return nil, err
+			}
+			kind, col, length := st[1][0].(uint64), st[2][0].(uint64), st[3][0].(uint64)
+			if streams[[2]uint64{col, kind}], err = orcTestDecompress(compression, data[pos:pos+length]); err != nil {
+				return nil, err
+			}
+			pos += length
+		}
+		if pos != offset+dataLen {
+			return nil, fmt.Errorf("stream lengths don't sum to data length")
+		}
+
+		rows := make([][]interface{}, numRows)
+		for i := range rows {
+			rows[i] = make([]interface{}, len(f.names))
+		}
+		for c := 1; c < len(f.kinds); c++ {
+			enc, err := orcTestFields(sf[2][c].([]byte))
+			if err != nil {
+				return nil, err
+			}
+			vals, err := orcTestColumn(f.kinds[c], enc, streams, uint64(c), numRows)
+			if err != nil {
+				return nil, fmt.Errorf("column %d: %w", c, err)
+			}
+			for i, v := range vals {
+				rows[i][c-1] = v
+			}
+		}
+		f.rows = append(f.rows, rows...)
+	}
+	if f.stripes > 0 && footer[2][0].(uint64) != contentLength {
+		return nil, fmt.Errorf("content length mismatch")
+	}
+	if footer[6][0].(uint64) != uint64(len(f.rows)) {
+		return nil, fmt.Errorf("row count mismatch")
+	}
+	return f, nil
+}
+
+// orcTestColumn decodes the values of a column in a stripe
+func orcTestColumn(kind int, enc map[int][]interface{}, streams map[[2]uint64][]byte, col uint64, numRows int) ([]interface{}, error) {
+	present := make([]bool, numRows)
+	for i := range present {
+		present[i] = true
+	}
+	if p, ok := streams[[2]uint64{col, orcStreamPresent}]; ok {
+		var err error
+		if present, err = orcTestDecodeBooleans(p, numRows); err != nil {
+			return nil, err
+		}
+	}
+	n := 0
+	for _, p := range present {
+		if p {
+			n++
+		}
+	}
+
+	data := streams[[2]uint64{col, orcStreamData}]
+	var vals []interface{}
+	switch kind {
+	case orcTypeLong:
+		ints, err := orcTestDecodeInts(data, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range ints {
+			vals = append(vals, v)
+		}
+	case orcTypeDouble:
+		for i := 0; i+8 <= len(data); i += 8 {
+			vals = append(vals, math.Float64frombits(binary.LittleEndian.Uint64(data[i:])))
+		}
+	case orcTypeBoolean:
+		bs, err := orcTestDecodeBooleans(data, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range bs {
+			vals = append(vals, b)
+		}
+	case orcTypeString:
+		lengths, err := orcTestDecodeInts(streams[[2]uint64{col, orcStreamLength}], false)
+		if err != nil {
+			return nil, err
+		}
+		var strs []string
+		src := data
+		if enc[1][0].(uint64) == orcEncodingDictionaryV2 {
+			src = streams[[2]uint64{col, orcStreamDictionaryData}]
+		}
+		for _, l := range lengths {
+			strs = append(strs, string(src[:l]))
+			src = src[l:]
+		}
+		if enc[1][0].(uint64) == orcEncodingDictionaryV2 {
+			if int(enc[2][0].(uint64)) != len(strs) {
+				return nil, fmt.Errorf("dictionary size mismatch")
+			}
+			idxs, err := orcTestDecodeInts(data, false)
+			if err != nil {
+				return nil, err
+			}
+			for _, idx := range idxs {
+				vals = append(vals, strs[idx])
+			}
+		} else {
+			for _, s := range strs {
+				vals = append(vals, s)
+			}
+		}
+	}
+	if len(vals) != n {
+		return nil, fmt.Errorf("expected %d values, got: %d", n, len(vals))
+	}
+
+	res := make([]interface{}, numRows)
+	for i := range res {
+		if present[i] {
+			res[i], vals = vals[0], vals[1:]
+		}
+	}
+	return res, nil
+}
+
+// orcTestFields decodes a protobuf message into varint & length-delimited
+// field values by field number. packed fields are left as bytes
+func orcTestFields(data []byte) (map[int][]interface{}, error) {
+	fields := map[int][]interface{}{}
+	d := &protoDecoder{buf: data}
+	for !d.done() {
+		num, wire, err := d.tag()
+		if err != nil {
+			return nil, err
+		}
+		switch wire {
+		case protoVarint:
+			v, err := d.varint()
+			if err != nil {
+				return nil, err
+			}
+			fields[num] = append(fields[num], v)
+		case protoBytes:
+			b, err := d.bytes()
+			if err != nil {
+				return nil, err
+			}
+			fields[num] = append(fields[num], b)
+		default:
+			return nil, fmt.Errorf("unexpected wire type %d", wire)
+		}
+	}
+	return fields, nil
+}
+
+// orcTestDecompress joins the chunks of a compressed stream
+func orcTestDecompress(compression uint64, data []byte) ([]byte, error) {
+	if compression == orcCompressionNone {
+		return data, nil
+	}
+	var out []byte
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, fmt.Errorf("truncated chunk header")
+		}
+		h := int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+		n := h >> 1
+		chunk := data[3 : 3+n]
+		data = data[3+n:]
+		if h&1 == 1 {
+			out = append(out, chunk...)
+			continue
+		}
+		switch compression {
+		case orcCompressionZlib:
+			b, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(chunk)))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, b...)
+		case orcCompressionSnappy:
+			b, err := snappyDecode(chunk)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, b...)
+		}
+	}
+	return out, nil
+}
+
+// orcTestDecodeBooleans decodes n byte run length encoded booleans
+func orcTestDecodeBooleans(data []byte, n int) ([]bool, error) {
+	var bytes []byte
+	for i := 0; i < len(data); {
+		c := int8(data[i])
+		if c >= 0 {
+			if i+1 >= len(data) {
+				return nil, fmt.Errorf("truncated run")
+			}
+			for j := 0; j < int(c)+3; j++ {
+				bytes = append(bytes, data[i+1])
+			}
+			i += 2
+			continue
+		}
+		l := -int(c)
+		if i+1+l > len(data) {
+			return nil, fmt.Errorf("truncated literals")
+		}
+		bytes = append(bytes, data[i+1:i+1+l]...)
+		i += 1 + l
+	}
+	if len(bytes) != (n+7)/8 {
+		return nil, fmt.Errorf("expected %d bytes, got: %d", (n+7)/8, len(bytes))
+	}
+	bs := make([]bool, n)
+	for i := range bs {
+		bs[i] = bytes[i/8]&(0x80>>(i%8)) != 0
+	}
+	return bs, nil
+}
+
+// orcTestDecodeInts decodes integer run length encoding v2 SHORT_REPEAT,
+// DIRECT & DELTA runs
+func orcTestDecodeInts(data []byte, signed bool) ([]int64, error) {
+	widths := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 26, 28, 30, 32, 40, 48, 56, 64}
+	unzig := func(u uint64) int64 {
+		if signed {
+			return int64(u>>1) ^ -int64(u&1)
+		}
+		return int64(u)
+	}
+	unpack := func(b []byte, n, width int) ([]uint64, int, error) {
+		size := (n*width + 7) / 8
+		if size > len(b) {
+			return nil, 0, fmt.Errorf("truncated packed values")
+		}
+		us := make([]uint64, n)
+		for i := range us {
+			for j := 0; j < width; j++ {
+				bit := i*width + j
+				us[i] = us[i]<<1 | uint64(b[bit/8]>>(7-bit%8)&1)
+			}
+		}
+		return us, size, nil
+	}
+
+	var vals []int64
+	for i := 0; i < len(data); {
+		h := data[i]
+		switch h >> 6 {
+		case 0:
+			width := int(h>>3&7) + 1
+			var u uint64
+			for _, b := range data[i+1 : i+1+width] {
+				u = u<<8 | uint64(b)
+			}
+			for j := 0; j < int(h&7)+3; j++ {
+				vals = append(vals, unzig(u))
+			}
+			i += 1 + width
+		case 1:
+			width := widths[h>>1&0x1f]
+			n := (int(h&1)<<8 | int(data[i+1])) + 1
+			us, size, err := unpack(data[i+2:], n, width)
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range us {
+				vals = append(vals, unzig(u))
+			}
+			i += 2 + size
+		case 3:
+			code := int(h >> 1 & 0x1f)
+			n := (int(h&1)<<8 | int(data[i+1])) + 1
+			i += 2
+			var first int64
+			if signed {
+				v, m := binary.Varint(data[i:])
+				first, i = v, i+m
+			} else {
+				v, m := binary.Uvarint(data[i:])
+				first, i = int64(v), i+m
+			}
+			base, m := binary.Varint(data[i:])
+			i += m
+			vals = append(vals, first, first+base)
+			if code == 0 {
+				for j := 2; j < n; j++ {
+					vals = append(vals, vals[len(vals)-1]+base)
+				}
+				continue
+			}
+			us, size, err := unpack(data[i:], n-2, widths[code])
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range us {
+				if base < 0 {
+					vals = append(vals, vals[len(vals)-1]-int64(u))
+				} else {
+					vals = append(vals, vals[len(vals)-1]+int64(u))
+				}
+			}
+			i += size
+		default:
+			return nil, fmt.Errorf("unexpected PATCHED_BASE run")
+		}
+	}
+	return vals, nil
+}
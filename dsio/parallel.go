@@ -0,0 +1,174 @@
+package dsio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/qri-io/dataset/dstrace"
+)
+
+// ParallelConfig encapsulates configuration for ConvertParallel
+type ParallelConfig struct {
+	// Workers is the number of goroutines running Transform. default is the
+	// number of CPUs
+	Workers int
+	// Window is the maximum number of entries in flight between the reader
+	// and writer, bounding memory use. default is 64 entries per worker,
+	// windows smaller than Workers are raised to Workers
+	Window int
+	// Transform is called on each entry by a worker, & can modify or validate
+	// entries. Returning an error stops conversion
+	Transform func(Entry) (Entry, error)
+	// Budget, if set, bounds the bytes held by entries in flight. The budget
+	// can be shared with the reader & writer, see MemoryBudget
+	Budget *MemoryBudget
+}
+
+// sequenced is an entry tagged with its position in the source
+type sequenced struct {
+	seq  int
+	ent  Entry
+	err  error
+	size int64
+}
+
+// ConvertParallel copies all entries from r to w, running an optional
+// transform stage on a pool of workers between them. Reading & writing
+// each run on a single goroutine, and entries are written in the order
+// they're read. Conversion stops at the first error, ConvertParallel returns
+// once the reading & transform goroutines have stopped. Conversion is traced
+// if the context carries a dstrace.Tracer
+func ConvertParallel(ctx context.Context, r EntryReader, w EntryWriter, configs ...func(cfg *ParallelConfig)) (err error) {
+	cfg := &ParallelConfig{
+		Workers: runtime.NumCPU(),
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.Window == 0 {
+		cfg.Window = cfg.Workers * 64
+	} else if cfg.Window < cfg.Workers {
+		cfg.Window = cfg.Workers
+	}
+
+	attrs := append(formatAttrs(r.Structure(), w.Structure()), dstrace.Attr("workers", cfg.Workers))
+	ctx, span := dstrace.StartSpan(ctx, "dsio.ConvertParallel", attrs...)
+	// next is the sequence number of the next entry to write
+	next := 0
+	defer func() {
+		span.SetAttributes(dstrace.Attr("entries", next))
+		span.End(err)
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	var (
+		in    = make(chan sequenced, cfg.Workers)
+		out   = make(chan sequenced, cfg.Workers)
+		slots = make(chan struct{}, cfg.Window)
+		wg    sync.WaitGroup
+		// held counts budgeted bytes of entries read but not yet handed to
+		// the writer
+		held     int64
+		readDone = make(chan struct{})
+	)
+	defer func() {
+		cancel()
+		<-readDone
+		wg.Wait()
+		if cfg.Budget != nil {
+			cfg.Budget.Release(atomic.LoadInt64(&held))
+		}
+	}()
+
+	// decode
+	go func() {
+		defer close(readDone)
+		defer close(in)
+		for seq := 0; ; seq++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			ent, err := r.ReadEntry()
+			if err == io.EOF {
+				return
+			}
+			var size int64
+			if cfg.Budget != nil && err == nil {
+				size = cfg.Budget.clamp(EntrySize(ent))
+				if cfg.Budget.acquireStage(ctx, size, &held) != nil {
+					return
+				}
+			}
+			select {
+			case in <- sequenced{seq: seq, ent: ent, err: err, size: size}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// transform
+	wg.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go func() {
+			defer wg.Done()
+			for item := range in {
+				if item.err == nil && cfg.Transform != nil {
+					item.ent, item.err = cfg.Transform(item.ent)
+				}
+				select {
+				case out <- item:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	// encode, restoring source order
+	pending := map[int]sequenced{}
+	for item := range out {
+		pending[item.seq] = item
+		for {
+			item, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if item.err != nil {
+				log.Debug(item.err.Error())
+				return fmt.Errorf("entry %d: %w", item.seq, item.err)
+			}
+			// the entry's bytes are released as it's handed to the writer, a
+			// writer sharing the budget acquires its own
+			if cfg.Budget != nil {
+				cfg.Budget.releaseStage(item.size, &held)
+			}
+			if err := writeEntry(ctx, w, item.ent); err != nil {
+				log.Debug(err.Error())
+				return fmt.Errorf("error writing entry %d: %w", item.seq, err)
+			}
+			<-slots
+			next++
+		}
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,181 @@
+package dsio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dstrace"
+)
+
+func TestConvertParallel(t *testing.T) {
+	src := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	dst := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+
+	nums := make([]string, 500)
+	for i := range nums {
+		nums[i] = fmt.Sprintf("%d", i)
+	}
+	body := "[" + strings.Join(nums, ",") + "]"
+
+	r, err := NewEntryReader(src, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewEntryWriter(dst, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ConvertParallel(context.Background(), r, w, func(cfg *ParallelConfig) {
+		cfg.Workers = 8
+		cfg.Window = 16
+		cfg.Transform = func(ent Entry) (Entry, error) {
+			// finish out of order
+			n := ent.Value.(int)
+			time.Sleep(time.Duration(n%3) * time.Microsecond * 50)
+			ent.Value = n * 2
+			return ent, nil
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range nums {
+		nums[i] = fmt.Sprintf("%d", i*2)
+	}
+	expect := "[" + strings.Join(nums, ",") + "]"
+	if buf.String() != expect {
+		t.Errorf("output mismatch. expected: %.60s..., got: %.60s...", expect, buf.String())
+	}
+}
+
+func TestConvertParallelError(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewEntryReader(st, strings.NewReader("[1,2,3,4,5,6,7,8,9,10]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewEntryBuffer(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ConvertParallel(context.Background(), r, w, func(cfg *ParallelConfig) {
+		cfg.Workers = 4
+		cfg.Transform = func(ent Entry) (Entry, error) {
+			if ent.Value.(int) == 5 {
+				return ent, fmt.Errorf("five is invalid")
+			}
+			return ent, nil
+		}
+	})
+	if err == nil || err.Error() != "entry 4: five is invalid" {
+		t.Errorf("expected transform error, got: %v", err)
+	}
+}
+
+// countingEntries reads n entries, tracking the most entries read but not yet
+// written
+type countingEntries struct {
+	st            *dataset.Structure
+	n             int
+	read, written int64
+	maxInFlight   int64
+	mu            sync.Mutex
+}
+
+func (c *countingEntries) Structure() *dataset.Structure { return c.st }
+func (c *countingEntries) Close() error                  { return nil }
+
+func (c *countingEntries) ReadEntry() (Entry, error) {
+	read := atomic.AddInt64(&c.read, 1)
+	if read > int64(c.n) {
+		return Entry{}, io.EOF
+	}
+	c.mu.Lock()
+	if in := read - atomic.LoadInt64(&c.written); in > c.maxInFlight {
+		c.maxInFlight = in
+	}
+	c.mu.Unlock()
+	return Entry{Index: int(read - 1), Value: read}, nil
+}
+
+func (c *countingEntries) WriteEntry(ent Entry) error {
+	atomic.AddInt64(&c.written, 1)
+	return nil
+}
+
+func TestConvertParallelWindow(t *testing.T) {
+	c := &countingEntries{st: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}, n: 500}
+	err := ConvertParallel(context.Background(), c, c, func(cfg *ParallelConfig) {
+		cfg.Workers = 4
+		cfg.Window = 2
+		cfg.Transform = func(ent Entry) (Entry, error) {
+			time.Sleep(10 * time.Microsecond)
+			return ent, nil
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a window smaller than the worker count is raised to it
+	if c.maxInFlight > 4 {
+		t.Errorf("expected at most 4 entries in flight, got: %d", c.maxInFlight)
+	}
+	if c.written != 500 {
+		t.Errorf("expected 500 entries written, got: %d", c.written)
+	}
+}
+
+func TestConvertParallelErrorStopsWorkers(t *testing.T) {
+	c := &countingEntries{st: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}, n: 500}
+	var running int64
+	err := ConvertParallel(context.Background(), c, c, func(cfg *ParallelConfig) {
+		cfg.Workers = 8
+		cfg.Transform = func(ent Entry) (Entry, error) {
+			atomic.AddInt64(&running, 1)
+			defer atomic.AddInt64(&running, -1)
+			if ent.Index == 0 {
+				return ent, fmt.Errorf("invalid")
+			}
+			time.Sleep(time.Millisecond)
+			return ent, nil
+		}
+	})
+	if err == nil {
+		t.Fatal("expected transform error")
+	}
+	if n := atomic.LoadInt64(&running); n != 0 {
+		t.Errorf("expected workers to stop before returning, %d transforms running", n)
+	}
+}
+
+func TestConvertParallelTrace(t *testing.T) {
+	c := &countingEntries{st: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}, n: 100}
+	rec := &dstrace.Recorder{}
+	ctx := dstrace.WithTracer(context.Background(), rec)
+	if err := ConvertParallel(ctx, c, c, func(cfg *ParallelConfig) { cfg.Workers = 2 }); err != nil {
+		t.Fatal(err)
+	}
+	spans := rec.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got: %d", len(spans))
+	}
+	s := spans[0]
+	if s.Name != "dsio.ConvertParallel" || s.Attrs["entries"] != 100 || s.Attrs["workers"] != 2 || s.Attrs["format.source"] != "json" || !s.Ended {
+		t.Errorf("unexpected span: %#v", s)
+	}
+}
@@ -0,0 +1,1153 @@
+package dsio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/bits"
+
+	"github.com/qri-io/dataset"
+)
+
+// parquet support is limited to flat schemas: every column is a top-level
+// field, written as an optional column. see
+// https://github.com/apache/parquet-format for the file format
+
+var parquetMagic = []byte("PAR1")
+
+// parquet physical types
+const (
+	pqBoolean           = 0
+	pqInt32             = 1
+	pqInt64             = 2
+	pqInt96             = 3
+	pqFloat             = 4
+	pqDouble            = 5
+	pqByteArray         = 6
+	pqFixedLenByteArray = 7
+)
+
+// parquet field repetitions, converted types, encodings, page types & codecs
+const (
+	pqRequired = 0
+	pqRepeated = 2
+
+	pqConvertedUTF8 = 0
+	pqConvertedJSON = 19
+
+	pqEncodingPlain            = 0
+	pqEncodingPlainDict        = 2
+	pqEncodingRLE              = 3
+	pqEncodingDelta            = 5
+	pqEncodingRLEDictionary    = 8
+	pqPageData                 = 0
+	pqPageDictionary           = 2
+	pqPageDataV2               = 3
+	pqCodecUncompressed        = 0
+	pqCodecSnappy              = 1
+	pqCodecGzip                = 2
+	defaultParquetRowGroupSize = 10000
+)
+
+// parquetColumn describes a column of a flat parquet schema
+type parquetColumn struct {
+	name      string
+	typ       int64
+	converted int64
+	required  bool
+	// typeLength is the width of FIXED_LEN_BYTE_ARRAY values
+	typeLength int64
+	// codec & encoding are the compression codec & value encoding the
+	// column is written with
+	codec    int32
+	encoding string
+}
+
+// ParquetReader implements the EntryReader interface for the parquet data
+// format. The body is read into memory when the reader is created, and row
+// groups are decoded as they're reached. Entries are arrays of column values
+type ParquetReader struct {
+	st          *dataset.Structure
+	src         *TrackedReader
+	data        []byte
+	cols        []parquetColumn
+	rowGroups   []interface{}
+	rg          int
+	values      [][]interface{}
+	row         int
+	numRows     int
+	entriesRead int
+}
+
+var _ EntryReader = (*ParquetReader)(nil)
+
+// NewParquetReader creates a reader from a structure and read source
+func NewParquetReader(st *dataset.Structure, r io.Reader) (*ParquetReader, error) {
+	src := NewTrackedReader(r)
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error reading parquet file: %w", err)
+	}
+	rdr := &ParquetReader{st: st, src: src, data: data}
+	if err := rdr.readFooter(); err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	return rdr, nil
+}
+
+// readFooter decodes the file metadata at the end of the file
+func (r *ParquetReader) readFooter() error {
+	n := len(r.data)
+	if n < 12 || !bytes.Equal(r.data[:4], parquetMagic) || !bytes.Equal(r.data[n-4:], parquetMagic) {
+		return newKindError(ErrFormatMismatch, "not a parquet file")
+	}
+	size := int(binary.LittleEndian.Uint32(r.data[n-8:]))
+	if size > n-12 {
+		return newKindError(ErrFormatMismatch, "invalid parquet footer length")
+	}
+	tr := &thriftReader{data: r.data[n-8-size : n-8]}
+	meta, err := tr.readStruct()
+	if err != nil {
+		return fmt.Errorf("error reading parquet metadata: %w", err)
+	}
+
+	schema := meta.list(2)
+	if len(schema) == 0 {
+		return newKindError(ErrFormatMismatch, "parquet file has no schema")
+	}
+	for _, el := range schema[1:] {
+		se, _ := el.(tvalues)
+		if se.int(5) > 0 {
+			return newKindError(ErrBadSchema, "nested parquet schemas aren't supported")
+		}
+		col := parquetColumn{
+			name:       se.str(4),
+			typ:        se.int(1),
+			converted:  -1,
+			required:   se.int(3) == pqRequired,
+			typeLength: se.int(2),
+		}
+		if se.int(3) == pqRepeated {
+			return newKindError(ErrBadSchema, fmt.Sprintf("repeated parquet column %s isn't supported", col.name))
+		}
+		if se.has(6) {
+			col.converted = se.int(6)
+		}
+		r.cols = append(r.cols, col)
+	}
+	r.rowGroups = meta.list(4)
+	return nil
+}
+
+// Structure gives this reader's structure
+func (r *ParquetReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// Schema gives a tabular schema describing the columns of the parquet file
+func (r *ParquetReader) Schema() map[string]interface{} {
+	cols := make([]interface{}, len(r.cols))
+	for i, col := range r.cols {
+		var typ interface{} = "string"
+		switch col.typ {
+		case pqBoolean:
+			typ = "boolean"
+		case pqInt32, pqInt64:
+			typ = "integer"
+		case pqFloat, pqDouble:
+			typ = "number"
+		case pqByteArray:
+			if col.converted == pqConvertedJSON {
+				typ = []interface{}{"object", "array"}
+			}
+		}
+		cols[i] = map[string]interface{}{"title": col.name, "type": typ}
+	}
+	return map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":  "array",
+			"items": cols,
+		},
+	}
+}
+
+// ReadEntry reads one row from the reader
+func (r *ParquetReader) ReadEntry() (Entry, error) {
+	for r.row >= r.numRows {
+		if r.rg >= len(r.rowGroups) {
+			return Entry{}, io.EOF
+		}
+		if err := r.readRowGroup(); err != nil {
+			log.Debug(err.Error())
+			return Entry{}, parseError("parquet", r.entriesRead, err)
+		}
+	}
+
+	row := make([]interface{}, len(r.cols))
+	for i := range r.cols {
+		row[i] = r.values[i][r.row]
+	}
+	r.row++
+	ent := Entry{Index: r.entriesRead, Value: row}
+	r.entriesRead++
+	return ent, nil
+}
+
+// readRowGroup decodes the next row group into columns of values
+func (r *ParquetReader) readRowGroup() error {
+	rg, _ := r.rowGroups[r.rg].(tvalues)
+	r.rg++
+	r.row = 0
+	r.numRows = int(rg.int(3))
+	chunks := rg.list(1)
+	if len(chunks) != len(r.cols) {
+		return newKindError(ErrFormatMismatch, "parquet row group doesn't match schema")
+	}
+
+	r.values = make([][]interface{}, len(r.cols))
+	for i, c := range chunks {
+		chunk, _ := c.(tvalues)
+		meta := chunk.structVal(3)
+		if meta == nil {
+			return fmt.Errorf("parquet column %s: external column chunks aren't supported", r.cols[i].name)
+		}
+		vals, err := r.readColumnChunk(r.cols[i], meta)
+		if err != nil {
+			return fmt.Errorf("parquet column %s: %w", r.cols[i].name, err)
+		}
+		if len(vals) != r.numRows {
+			return fmt.Errorf("parquet column %s: expected %d values, got %d", r.cols[i].name, r.numRows, len(vals))
+		}
+		r.values[i] = vals
+	}
+	return nil
+}
+
+// readColumnChunk decodes the pages of a column chunk
+func (r *ParquetReader) readColumnChunk(col parquetColumn, meta tvalues) ([]interface{}, error) {
+	codec := meta.int(4)
+	numValues := meta.int(5)
+	start := meta.int(9)
+	if dict := meta.int(11); meta.has(11) && dict > 0 && dict < start {
+		start = dict
+	}
+	end := start + meta.int(7)
+	if start < 4 || end > int64(len(r.data)) || end < start {
+		return nil, errThriftCorrupt
+	}
+
+	var dict []interface{}
+	var vals []interface{}
+	for pos := start; int64(len(vals)) < numValues && pos < end; {
+		tr := &thriftReader{data: r.data[pos:end]}
+		header, err := tr.readStruct()
+		if err != nil {
+			return nil, err
+		}
+		pos += int64(tr.pos)
+		size := header.int(3)
+		if size < 0 || pos+size > end {
+			return nil, errThriftCorrupt
+		}
+		page, err := parquetDecompress(codec, r.data[pos:pos+size], header.int(2))
+		if err != nil {
+			return nil, err
+		}
+		pos += size
+
+		switch header.int(1) {
+		case pqPageDictionary:
+			dph := header.structVal(7)
+			if dict, err = parquetDecodePlain(col, page, int(dph.int(1))); err != nil {
+				return nil, err
+			}
+		case pqPageData:
+			dph := header.structVal(5)
+			pvals, err := parquetDecodeDataPage(col, page, int(dph.int(1)), dph.int(2), dict)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, pvals...)
+		case pqPageDataV2:
+			return nil, fmt.Errorf("data page v2 isn't supported")
+		}
+	}
+	return vals, nil
+}
+
+// parquetDecompress decompresses a page
+func parquetDecompress(codec int64, data []byte, size int64) ([]byte, error) {
+	switch codec {
+	case pqCodecUncompressed:
+		return data, nil
+	case pqCodecSnappy:
+		return snappyDecode(data)
+	case pqCodecGzip:
+		gzr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.ReadAll(io.LimitReader(gzr, size))
+	}
+	return nil, fmt.Errorf("unsupported compression codec: %d", codec)
+}
+
+// parquetDecodeDataPage decodes the values of a v1 data page, giving nil for
+// null values
+func parquetDecodeDataPage(col parquetColumn, page []byte, n int, encoding int64, dict []interface{}) ([]interface{}, error) {
+	present := n
+	var levels []int
+	if !col.required {
+		if len(page) < 4 {
+			return nil, errThriftCorrupt
+		}
+		size := int(binary.LittleEndian.Uint32(page))
+		if size > len(page)-4 {
+			return nil, errThriftCorrupt
+		}
+		var err error
+		if levels, err = parquetDecodeHybrid(page[4:4+size], 1, n); err != nil {
+			return nil, err
+		}
+		page = page[4+size:]
+		present = 0
+		for _, l := range levels {
+			present += l
+		}
+	}
+
+	var vals []interface{}
+	switch encoding {
+	case pqEncodingPlain:
+		var err error
+		if vals, err = parquetDecodePlain(col, page, present); err != nil {
+			return nil, err
+		}
+	case pqEncodingPlainDict, pqEncodingRLEDictionary:
+		if len(page) < 1 {
+			return nil, errThriftCorrupt
+		}
+		idxs, err := parquetDecodeHybrid(page[1:], int(page[0]), present)
+		if err != nil {
+			return nil, err
+		}
+		vals = make([]interface{}, present)
+		for i, idx := range idxs {
+			if idx >= len(dict) {
+				return nil, fmt.Errorf("dictionary index out of range")
+			}
+			vals[i] = dict[idx]
+		}
+	case pqEncodingDelta:
+		ints, err := parquetDecodeDelta(page, present)
+		if err != nil {
+			return nil, err
+		}
+		vals = make([]interface{}, len(ints))
+		for i, n := range ints {
+			switch col.typ {
+			case pqInt32:
+				vals[i] = int(int32(n))
+			case pqInt64:
+				vals[i] = int(n)
+			default:
+				return nil, fmt.Errorf("delta encoding isn't supported for physical type: %d", col.typ)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %d", encoding)
+	}
+
+	if levels == nil {
+		return vals, nil
+	}
+	out := make([]interface{}, n)
+	for i, l := range levels {
+		if l == 1 {
+			out[i], vals = vals[0], vals[1:]
+		}
+	}
+	return out, nil
+}
+
+// parquetDecodeHybrid decodes n values of the RLE / bit-packing hybrid
+// encoding used for levels & dictionary indexes
+func parquetDecodeHybrid(data []byte, bitWidth, n int) ([]int, error) {
+	if bitWidth > 32 {
+		return nil, errThriftCorrupt
+	}
+	var out []int
+	pos := 0
+	for len(out) < n {
+		h, k := binary.Uvarint(data[pos:])
+		if k <= 0 {
+			return nil, errThriftCorrupt
+		}
+		pos += k
+
+		if h&1 == 0 {
+			// run of a repeated value
+			count := h >> 1
+			if remain := uint64(n - len(out)); count > remain {
+				count = remain
+			}
+			width := (bitWidth + 7) / 8
+			if pos+width > len(data) {
+				return nil, errThriftCorrupt
+			}
+			v := 0
+			for b := 0; b < width; b++ {
+				v |= int(data[pos+b]) << (8 * b)
+			}
+			pos += width
+			for ; count > 0; count-- {
+				out = append(out, v)
+			}
+			continue
+		}
+
+		// groups of 8 bit-packed values
+		groups := h >> 1
+		if groups > uint64(len(data)) || pos+int(groups)*bitWidth > len(data) {
+			return nil, errThriftCorrupt
+		}
+		bits := data[pos : pos+int(groups)*bitWidth]
+		pos += len(bits)
+		for i := 0; i < int(groups)*8 && len(out) < n; i++ {
+			v := 0
+			for b := 0; b < bitWidth; b++ {
+				bit := i*bitWidth + b
+				v |= int(bits[bit/8]>>(bit%8)&1) << b
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// parquetDecodeDelta decodes n DELTA_BINARY_PACKED integers: a header
+// followed by blocks of bit-packed differences between consecutive values
+func parquetDecodeDelta(data []byte, n int) ([]int64, error) {
+	pos := 0
+	uvarint := func() uint64 {
+		v, k := binary.Uvarint(data[pos:])
+		if k <= 0 {
+			pos = -1
+			return 0
+		}
+		pos += k
+		return v
+	}
+	varint := func() int64 {
+		v, k := binary.Varint(data[pos:])
+		if k <= 0 {
+			pos = -1
+			return 0
+		}
+		pos += k
+		return v
+	}
+
+	blockSize := uvarint()
+	if pos < 0 {
+		return nil, errThriftCorrupt
+	}
+	miniblocks := uvarint()
+	if pos < 0 {
+		return nil, errThriftCorrupt
+	}
+	uvarint() // total value count, n is known from the page header
+	if pos < 0 {
+		return nil, errThriftCorrupt
+	}
+	prev := varint()
+	if pos < 0 || miniblocks == 0 || blockSize%miniblocks != 0 || blockSize > 1<<16 || (blockSize/miniblocks)%8 != 0 {
+		return nil, errThriftCorrupt
+	}
+	miniSize := int(blockSize / miniblocks)
+
+	out := make([]int64, 0, n)
+	if n > 0 {
+		out = append(out, prev)
+	}
+	for len(out) < n {
+		minDelta := varint()
+		if pos < 0 || pos+int(miniblocks) > len(data) {
+			return nil, errThriftCorrupt
+		}
+		widths := data[pos : pos+int(miniblocks)]
+		pos += int(miniblocks)
+		for m := 0; m < int(miniblocks) && len(out) < n; m++ {
+			width := int(widths[m])
+			size := miniSize * width / 8
+			if width > 64 || pos+size > len(data) {
+				return nil, errThriftCorrupt
+			}
+			packed := data[pos : pos+size]
+			pos += size
+			for j := 0; j < miniSize && len(out) < n; j++ {
+				var u uint64
+				for b := 0; b < width; b++ {
+					bit := j*width + b
+					u |= uint64(packed[bit/8]>>(bit%8)&1) << b
+				}
+				prev += minDelta + int64(u)
+				out = append(out, prev)
+			}
+		}
+	}
+	return out, nil
+}
+
+// parquetDecodePlain decodes n PLAIN encoded values
+func parquetDecodePlain(col parquetColumn, data []byte, n int) ([]interface{}, error) {
+	vals := make([]interface{}, 0, n)
+	width := map[int64]int{pqInt32: 4, pqInt64: 8, pqFloat: 4, pqDouble: 8, pqFixedLenByteArray: int(col.typeLength)}[col.typ]
+	if width > 0 && n > len(data)/width {
+		return nil, errThriftCorrupt
+	}
+
+	pos := 0
+	for i := 0; i < n; i++ {
+		switch col.typ {
+		case pqBoolean:
+			if i/8 >= len(data) {
+				return nil, errThriftCorrupt
+			}
+			vals = append(vals, data[i/8]>>(i%8)&1 == 1)
+		case pqInt32:
+			vals = append(vals, int(int32(binary.LittleEndian.Uint32(data[pos:]))))
+		case pqInt64:
+			vals = append(vals, int(int64(binary.LittleEndian.Uint64(data[pos:]))))
+		case pqFloat:
+			vals = append(vals, float64(math.Float32frombits(binary.LittleEndian.Uint32(data[pos:]))))
+		case pqDouble:
+			vals = append(vals, math.Float64frombits(binary.LittleEndian.Uint64(data[pos:])))
+		case pqFixedLenByteArray:
+			vals = append(vals, string(data[pos:pos+width]))
+		case pqByteArray:
+			if pos+4 > len(data) {
+				return nil, errThriftCorrupt
+			}
+			size := int(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+			if size > len(data)-pos {
+				return nil, errThriftCorrupt
+			}
+			v, err := parquetByteArrayValue(col, data[pos:pos+size])
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+			pos += size
+		default:
+			return nil, fmt.Errorf("unsupported physical type: %d", col.typ)
+		}
+		pos += width
+	}
+	return vals, nil
+}
+
+// parquetByteArrayValue converts a byte array to a string, decoding JSON
+// columns
+func parquetByteArrayValue(col parquetColumn, b []byte) (interface{}, error) {
+	if col.converted != pqConvertedJSON {
+		return string(b), nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid JSON value: %w", err)
+	}
+	return ndjsonValue(v), nil
+}
+
+// Close finalizes the reader, closing the source if it's an io.Closer
+func (r *ParquetReader) Close() error {
+	return r.src.Close()
+}
+
+// EntriesRead gives the number of entries read
+func (r *ParquetReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the size of the parquet file, which is read in full
+// when the reader is created
+func (r *ParquetReader) BytesProcessed() int64 {
+	return int64(r.src.BytesRead())
+}
+
+// ParquetWriter implements the EntryWriter interface for the parquet data
+// format. Entries must be arrays matching a tabular schema. Rows are buffered
+// & written a row group at a time, the file footer is written on Close
+type ParquetWriter struct {
+	st             *dataset.Structure
+	wr             *countingWriter
+	cols           []parquetColumn
+	rowGroupSize   int
+	rows           [][]interface{}
+	rowGroups      []interface{}
+	numRows        int64
+	entriesWritten int
+	closed         bool
+}
+
+var _ EntryWriter = (*ParquetWriter)(nil)
+
+// NewParquetWriter creates a writer from a structure and write destination.
+// Columns are typed from the structure's schema: integer columns are written
+// as INT64, number as DOUBLE, boolean as BOOLEAN & string as UTF8 byte arrays.
+// Columns of other types are written as JSON byte arrays. Codec & encoding
+// hints in ParquetOptions columns override the compression of a column, and
+// can dictionary encode non-boolean columns or delta encode integer columns
+func NewParquetWriter(st *dataset.Structure, w io.Writer) (*ParquetWriter, error) {
+	titles, types, err := terribleHackToGetHeaderRowAndTypes(st)
+	if err != nil || len(titles) == 0 {
+		err = newKindError(ErrBadSchema, "parquet requires a tabular schema with at least one column")
+		log.Debug(err.Error())
+		return nil, err
+	}
+
+	pw := &ParquetWriter{
+		st:           st,
+		wr:           &countingWriter{w: w},
+		rowGroupSize: defaultParquetRowGroupSize,
+	}
+	opts, err := dataset.NewParquetOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	if opts.RowGroupSize > 0 {
+		pw.rowGroupSize = opts.RowGroupSize
+	}
+
+	for i, title := range titles {
+		if title == "" {
+			title = dataset.AbstractColumnName(i)
+		}
+		col := parquetColumn{name: title, typ: pqByteArray, converted: pqConvertedJSON, codec: parquetCodec(opts.Compression)}
+		switch types[i] {
+		case "integer":
+			col.typ, col.converted = pqInt64, -1
+		case "number":
+			col.typ, col.converted = pqDouble, -1
+		case "boolean":
+			col.typ, col.converted = pqBoolean, -1
+		case "string":
+			col.converted = pqConvertedUTF8
+		}
+		pw.cols = append(pw.cols, col)
+	}
+
+	if err := pw.applyColumnCodecs(opts.Columns); err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	return pw, nil
+}
+
+// applyColumnCodecs sets column codecs & encodings from format config hints
+func (w *ParquetWriter) applyColumnCodecs(hints map[string]dataset.ColumnCodec) error {
+	for title, hint := range hints {
+		i := 0
+		for i < len(w.cols) && w.cols[i].name != title {
+			i++
+		}
+		if i == len(w.cols) {
+			return fmt.Errorf("parquet format config has hints for unknown column '%s'", title)
+		}
+		col := &w.cols[i]
+		if hint.Compression != "" {
+			col.codec = parquetCodec(hint.Compression)
+		}
+		switch hint.Encoding {
+		case dataset.ColumnEncodingDictionary:
+			if col.typ == pqBoolean {
+				return fmt.Errorf("column %s: dictionary encoding isn't supported for boolean columns", title)
+			}
+		case dataset.ColumnEncodingDelta:
+			if col.typ != pqInt64 {
+				return fmt.Errorf("column %s: delta encoding requires an integer column", title)
+			}
+		}
+		col.encoding = hint.Encoding
+	}
+	return nil
+}
+
+// parquetCodec gives the codec for a ParquetOptions compression name,
+// defaulting to snappy
+func parquetCodec(name string) int32 {
+	switch name {
+	case dataset.ParquetUncompressed:
+		return pqCodecUncompressed
+	case dataset.ParquetGzip:
+		return pqCodecGzip
+	}
+	return pqCodecSnappy
+}
+
+// Structure gives this writer's structure
+func (w *ParquetWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// WriteEntry buffers a row, writing a row group when RowGroupSize rows are
+// buffered
+func (w *ParquetWriter) WriteEntry(ent Entry) error {
+	arr, ok := ent.Value.([]interface{})
+	if !ok {
+		err := fmt.Errorf("expected array value to write parquet row. got: %T", ent.Value)
+		log.Debug(err.Error())
+		return err
+	}
+	if len(arr) > len(w.cols) {
+		err := fmt.Errorf("entry %d has %d values, schema has %d columns", ent.Index, len(arr), len(w.cols))
+		log.Debug(err.Error())
+		return err
+	}
+
+	row := make([]interface{}, len(w.cols))
+	for i, v := range arr {
+		pv, err := parquetValue(w.cols[i], v)
+		if err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("entry %d column %s: %w", ent.Index, w.cols[i].name, err)
+		}
+		row[i] = pv
+	}
+	w.rows = append(w.rows, row)
+	w.entriesWritten++
+
+	if len(w.rows) >= w.rowGroupSize {
+		return w.writeRowGroup()
+	}
+	return nil
+}
+
+// parquetValue converts a value to the go type written for a column: int64,
+// float64, bool or []byte
+func parquetValue(col parquetColumn, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch col.typ {
+	case pqInt64:
+		switch t := v.(type) {
+		case int:
+			return int64(t), nil
+		case int64:
+			return t, nil
+		case int32:
+			return int64(t), nil
+		case float64:
+			if t == math.Trunc(t) {
+				return int64(t), nil
+			}
+		}
+		return nil, fmt.Errorf("expected integer value, got %T", v)
+	case pqDouble:
+		switch t := v.(type) {
+		case float64:
+			return t, nil
+		case float32:
+			return float64(t), nil
+		case int:
+			return float64(t), nil
+		case int64:
+			return float64(t), nil
+		}
+		return nil, fmt.Errorf("expected number value, got %T", v)
+	case pqBoolean:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected boolean value, got %T", v)
+	}
+
+	if col.converted == pqConvertedUTF8 {
+		if s, ok := v.(string); ok {
+			return []byte(s), nil
+		}
+		return nil, fmt.Errorf("expected string value, got %T", v)
+	}
+	return json.Marshal(v)
+}
+
+// writeRowGroup writes buffered rows as a row group, one column chunk per
+// column. Dictionary encoded chunks are a dictionary page followed by a data
+// page, other chunks a single data page
+func (w *ParquetWriter) writeRowGroup() error {
+	if w.wr.n == 0 {
+		if _, err := w.wr.Write(parquetMagic); err != nil {
+			return err
+		}
+	}
+	if len(w.rows) == 0 {
+		return nil
+	}
+
+	var total int64
+	chunks := make([]interface{}, len(w.cols))
+	for i, col := range w.cols {
+		vals := make([]interface{}, 0, len(w.rows))
+		for _, row := range w.rows {
+			if row[i] != nil {
+				vals = append(vals, row[i])
+			}
+		}
+
+		offset := w.wr.n
+		var dictOffset interface{}
+		var size, written int64
+		encoding := int32(pqEncodingPlain)
+		page := w.encodeLevels(i)
+		switch col.encoding {
+		case dataset.ColumnEncodingDictionary:
+			dict, idxs := parquetDictionary(vals)
+			n, m, err := w.writePage(col.codec, pqPageDictionary, 7, tstruct{
+				{1, int32(len(dict))},
+				{2, int32(pqEncodingPlainDict)},
+			}, parquetEncodePlain(dict))
+			if err != nil {
+				return err
+			}
+			dictOffset, size, written = offset, n, m
+			encoding = pqEncodingPlainDict
+			bitWidth := 0
+			if len(dict) > 1 {
+				bitWidth = bits.Len(uint(len(dict) - 1))
+			}
+			page = append(page, byte(bitWidth))
+			page = append(page, parquetEncodeHybrid(idxs, bitWidth)...)
+		case dataset.ColumnEncodingDelta:
+			ints := make([]int64, len(vals))
+			for j, v := range vals {
+				ints[j] = v.(int64)
+			}
+			encoding = pqEncodingDelta
+			page = append(page, parquetEncodeDelta(ints)...)
+		default:
+			page = append(page, parquetEncodePlain(vals)...)
+		}
+
+		dataOffset := w.wr.n
+		n, m, err := w.writePage(col.codec, pqPageData, 5, tstruct{
+			{1, int32(len(w.rows))},
+			{2, encoding},
+			{3, int32(pqEncodingRLE)},
+			{4, int32(pqEncodingRLE)},
+		}, page)
+		if err != nil {
+			return err
+		}
+		size += n
+		written += m
+		total += size
+		chunks[i] = tstruct{
+			{2, offset},
+			{3, tstruct{
+				{1, int32(col.typ)},
+				{2, tlist{tcI32, []interface{}{encoding, int32(pqEncodingRLE)}}},
+				{3, tlist{tcBinary, []interface{}{col.name}}},
+				{4, col.codec},
+				{5, int64(len(w.rows))},
+				{6, size},
+				{7, written},
+				{9, dataOffset},
+				{11, dictOffset},
+			}},
+		}
+	}
+
+	w.rowGroups = append(w.rowGroups, tstruct{
+		{1, tlist{tcStruct, chunks}},
+		{2, total},
+		{3, int64(len(w.rows))},
+	})
+	w.numRows += int64(len(w.rows))
+	w.rows = w.rows[:0]
+	return nil
+}
+
+// writePage compresses & writes a page, giving the uncompressed & written
+// sizes of the page including it's header. header is the header specific to
+// the page type, written as field id of the page header
+func (w *ParquetWriter) writePage(codec int32, typ int32, id int16, header tstruct, page []byte) (int64, int64, error) {
+	compressed, err := parquetCompress(codec, page)
+	if err != nil {
+		log.Debug(err.Error())
+		return 0, 0, fmt.Errorf("error compressing parquet page: %w", err)
+	}
+	h := thriftEncode(tstruct{
+		{1, typ},
+		{2, int32(len(page))},
+		{3, int32(len(compressed))},
+		{id, header},
+	})
+	if _, err := w.wr.Write(h); err != nil {
+		return 0, 0, err
+	}
+	if _, err := w.wr.Write(compressed); err != nil {
+		return 0, 0, err
+	}
+	return int64(len(h) + len(page)), int64(len(h) + len(compressed)), nil
+}
+
+// encodeLevels encodes RLE definition levels of a column of the buffered
+// rows, marking which rows have values
+func (w *ParquetWriter) encodeLevels(col int) []byte {
+	levels := []byte{0, 0, 0, 0}
+	for i := 0; i < len(w.rows); {
+		present := w.rows[i][col] != nil
+		j := i + 1
+		for j < len(w.rows) && (w.rows[j][col] != nil) == present {
+			j++
+		}
+		levels = binary.AppendUvarint(levels, uint64(j-i)<<1)
+		if present {
+			levels = append(levels, 1)
+		} else {
+			levels = append(levels, 0)
+		}
+		i = j
+	}
+	binary.LittleEndian.PutUint32(levels, uint32(len(levels)-4))
+	return levels
+}
+
+// parquetEncodePlain PLAIN encodes values converted by parquetValue
+func parquetEncodePlain(vals []interface{}) []byte {
+	var buf, bits []byte
+	nbits := 0
+	for _, val := range vals {
+		switch v := val.(type) {
+		case int64:
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(v))
+		case float64:
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+		case bool:
+			if nbits%8 == 0 {
+				bits = append(bits, 0)
+			}
+			if v {
+				bits[nbits/8] |= 1 << (nbits % 8)
+			}
+			nbits++
+		case []byte:
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+			buf = append(buf, v...)
+		}
+	}
+	return append(buf, bits...)
+}
+
+// parquetDictionary gives the distinct values of a column in order of
+// appearance, and the index of each value in the dictionary
+func parquetDictionary(vals []interface{}) ([]interface{}, []int) {
+	var dict []interface{}
+	idxs := make([]int, len(vals))
+	seen := map[interface{}]int{}
+	for i, v := range vals {
+		key := v
+		if b, ok := v.([]byte); ok {
+			key = string(b)
+		}
+		idx, ok := seen[key]
+		if !ok {
+			idx = len(dict)
+			seen[key] = idx
+			dict = append(dict, v)
+		}
+		idxs[i] = idx
+	}
+	return dict, idxs
+}
+
+// parquetEncodeHybrid encodes values with the RLE / bit-packing hybrid
+// encoding, writing runs of 8 or more repeated values as RLE runs & other
+// values bit-packed in groups of 8
+func parquetEncodeHybrid(vals []int, bitWidth int) []byte {
+	var buf []byte
+	var literals []int
+	flush := func() {
+		if len(literals) == 0 {
+			return
+		}
+		// the last group is padded, readers stop at the value count
+		for len(literals)%8 != 0 {
+			literals = append(literals, 0)
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(literals)/8)<<1|1)
+		packed := make([]byte, len(literals)*bitWidth/8)
+		for i, v := range literals {
+			for b := 0; b < bitWidth; b++ {
+				if v>>b&1 == 1 {
+					bit := i*bitWidth + b
+					packed[bit/8] |= 1 << (bit % 8)
+				}
+			}
+		}
+		buf = append(buf, packed...)
+		literals = literals[:0]
+	}
+
+	for i := 0; i < len(vals); {
+		j := i + 1
+		for j < len(vals) && vals[j] == vals[i] {
+			j++
+		}
+		// runs can only start once literals fill whole groups
+		if j-i >= 8 && len(literals)%8 == 0 {
+			flush()
+			buf = binary.AppendUvarint(buf, uint64(j-i)<<1)
+			for b := 0; b < (bitWidth+7)/8; b++ {
+				buf = append(buf, byte(vals[i]>>(8*b)))
+			}
+			i = j
+			continue
+		}
+		literals = append(literals, vals[i])
+		i++
+	}
+	flush()
+	return buf
+}
+
+// parquetEncodeDelta encodes integers with DELTA_BINARY_PACKED, in blocks of
+// 128 differences split into 4 miniblocks, each bit-packed to the width of
+// it's largest difference from the block's minimum
+func parquetEncodeDelta(vals []int64) []byte {
+	const blockSize, miniblocks = 128, 4
+	const miniSize = blockSize / miniblocks
+
+	var first int64
+	if len(vals) > 0 {
+		first = vals[0]
+	}
+	buf := binary.AppendUvarint(nil, blockSize)
+	buf = binary.AppendUvarint(buf, miniblocks)
+	buf = binary.AppendUvarint(buf, uint64(len(vals)))
+	buf = binary.AppendVarint(buf, first)
+
+	for start := 1; start < len(vals); start += blockSize {
+		end := start + blockSize
+		if end > len(vals) {
+			end = len(vals)
+		}
+		// differences wrap on overflow, as readers sum them the same way
+		deltas := make([]int64, end-start)
+		minDelta := vals[start] - vals[start-1]
+		for i := range deltas {
+			deltas[i] = vals[start+i] - vals[start+i-1]
+			if deltas[i] < minDelta {
+				minDelta = deltas[i]
+			}
+		}
+		buf = binary.AppendVarint(buf, minDelta)
+
+		widths := make([]int, miniblocks)
+		for i, d := range deltas {
+			if n := bits.Len64(uint64(d - minDelta)); n > widths[i/miniSize] {
+				widths[i/miniSize] = n
+			}
+		}
+		for _, n := range widths {
+			buf = append(buf, byte(n))
+		}
+		// miniblocks past the last value are left out, the last miniblock
+		// with values is padded
+		for m := 0; m*miniSize < len(deltas); m++ {
+			width := widths[m]
+			packed := make([]byte, miniSize*width/8)
+			for j := 0; j < miniSize && m*miniSize+j < len(deltas); j++ {
+				u := uint64(deltas[m*miniSize+j] - minDelta)
+				for b := 0; b < width; b++ {
+					if u>>b&1 == 1 {
+						bit := j*width + b
+						packed[bit/8] |= 1 << (bit % 8)
+					}
+				}
+			}
+			buf = append(buf, packed...)
+		}
+	}
+	return buf
+}
+
+// parquetCompress compresses a page with a codec
+func parquetCompress(codec int32, page []byte) ([]byte, error) {
+	switch codec {
+	case pqCodecSnappy:
+		return snappyEncode(page), nil
+	case pqCodecGzip:
+		buf := &bytes.Buffer{}
+		gzw := gzip.NewWriter(buf)
+		if _, err := gzw.Write(page); err != nil {
+			return nil, err
+		}
+		if err := gzw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return page, nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *ParquetWriter) EntriesWritten() int {
+	return w.entriesWritten
+}
+
+// BytesProcessed gives the number of bytes written. rows are buffered until a
+// row group is written
+func (w *ParquetWriter) BytesProcessed() int64 {
+	return w.wr.n
+}
+
+// Close writes any buffered rows & the file footer. The destination is closed
+// if it's an io.Closer, wrap it with KeepWriterOpen to leave it open
+func (w *ParquetWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.writeRowGroup(); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing parquet row group: %w", err)
+	}
+
+	schema := []interface{}{tstruct{{4, "schema"}, {5, int32(len(w.cols))}}}
+	for _, col := range w.cols {
+		el := tstruct{{1, int32(col.typ)}, {3, int32(1)}, {4, col.name}}
+		if col.converted >= 0 {
+			el = append(el, tfield{6, int32(col.converted)})
+		}
+		schema = append(schema, el)
+	}
+	footer := thriftEncode(tstruct{
+		{1, int32(1)},
+		{2, tlist{tcStruct, schema}},
+		{3, w.numRows},
+		{4, tlist{tcStruct, w.rowGroups}},
+		{6, "qri-io/dataset"},
+	})
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	footer = append(footer, parquetMagic...)
+	if _, err := w.wr.Write(footer); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing parquet footer: %w", err)
+	}
+	return w.wr.Close()
+}
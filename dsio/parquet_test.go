@@ -0,0 +1,378 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func parquetTestStructure(opts map[string]interface{}) *dataset.Structure {
+	return &dataset.Structure{
+		Format:       "parquet",
+		FormatConfig: opts,
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "name", "type": "string"},
+					map[string]interface{}{"title": "count", "type": "integer"},
+					map[string]interface{}{"title": "ratio", "type": "number"},
+					map[string]interface{}{"title": "ok", "type": "boolean"},
+					map[string]interface{}{"title": "tags", "type": "array"},
+				},
+			},
+		},
+	}
+}
+
+var parquetTestRows = []interface{}{
+	[]interface{}{"alpha", 1, 0.5, true, []interface{}{"a", 1}},
+	[]interface{}{"beta", nil, 2, false, nil},
+	[]interface{}{nil, -40, nil, nil, map[string]interface{}{"k": 1.5}},
+	[]interface{}{"ラーメン", 1 << 40, -1.25, true, []interface{}{}},
+	[]interface{}{"alpha", 2},
+}
+
+var parquetTestExpect = []interface{}{
+	[]interface{}{"alpha", 1, 0.5, true, []interface{}{"a", 1}},
+	[]interface{}{"beta", nil, 2.0, false, nil},
+	[]interface{}{nil, -40, nil, nil, map[string]interface{}{"k": 1.5}},
+	[]interface{}{"ラーメン", 1 << 40, -1.25, true, []interface{}{}},
+	[]interface{}{"alpha", 2, nil, nil, nil},
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	cases := []map[string]interface{}{
+		nil,
+		{"compression": "uncompressed"},
+		{"compression": "gzip"},
+		{"compression": "snappy", "rowGroupSize": 2},
+		{"columns": map[string]interface{}{
+			"name":  map[string]interface{}{"encoding": "dictionary", "compression": "gzip"},
+			"count": map[string]interface{}{"encoding": "delta"},
+			"ratio": map[string]interface{}{"encoding": "dictionary", "compression": "uncompressed"},
+			"tags":  map[string]interface{}{"encoding": "plain"},
+		}},
+		{"rowGroupSize": 1, "columns": map[string]interface{}{
+			"name":  map[string]interface{}{"encoding": "dictionary"},
+			"count": map[string]interface{}{"encoding": "delta"},
+		}},
+	}
+
+	for i, opts := range cases {
+		st := parquetTestStructure(opts)
+		buf := &bytes.Buffer{}
+		w, err := NewEntryWriter(st, buf)
+		if err != nil {
+			t.Fatalf("case %d: %s", i, err)
+		}
+		for j, row := range parquetTestRows {
+			if err := w.WriteEntry(Entry{Index: j, Value: row}); err != nil {
+				t.Fatalf("case %d entry %d: %s", i, j, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("case %d: %s", i, err)
+		}
+		if w.(*ParquetWriter).BytesProcessed() != int64(buf.Len()) {
+			t.Errorf("case %d bytes processed mismatch. expected: %d, got: %d", i, buf.Len(), w.(*ParquetWriter).BytesProcessed())
+		}
+
+		size := buf.Len()
+		r, err := NewEntryReader(st, buf)
+		if err != nil {
+			t.Fatalf("case %d: %s", i, err)
+		}
+		for j, ex := range parquetTestExpect {
+			ent, err := r.ReadEntry()
+			if err != nil {
+				t.Fatalf("case %d entry %d: %s", i, j, err)
+			}
+			if ent.Index != j {
+				t.Errorf("case %d entry %d index mismatch. got: %d", i, j, ent.Index)
+			}
+			if !reflect.DeepEqual(ex, ent.Value) {
+				t.Errorf("case %d entry %d mismatch.\nexpected: %#v\ngot:      %#v", i, j, ex, ent.Value)
+			}
+		}
+		if _, err := r.ReadEntry(); err != io.EOF {
+			t.Errorf("case %d expected io.EOF, got: %v", i, err)
+		}
+		if got := r.(*ParquetReader).BytesProcessed(); got != int64(size) {
+			t.Errorf("case %d reader bytes processed mismatch. expected: %d, got: %d", i, size, got)
+		}
+	}
+}
+
+func TestParquetRowGroups(t *testing.T) {
+	st := parquetTestStructure(map[string]interface{}{"rowGroupSize": 3})
+	buf := &bytes.Buffer{}
+	w, err := NewParquetWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := w.WriteEntry(Entry{Index: i, Value: []interface{}{"row", i}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewParquetReader(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.rowGroups) != 4 {
+		t.Errorf("expected 4 row groups, got: %d", len(r.rowGroups))
+	}
+	count := 0
+	err = EachEntry(r, func(i int, ent Entry, err error) error {
+		if ent.Value.([]interface{})[1] != i {
+			t.Errorf("entry %d count mismatch. got: %v", i, ent.Value.([]interface{})[1])
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 10 {
+		t.Errorf("expected 10 entries, got: %d", count)
+	}
+}
+
+func TestParquetWriterErrors(t *testing.T) {
+	if _, err := NewParquetWriter(&dataset.Structure{Format: "parquet", Schema: dataset.BaseSchemaArray}, &bytes.Buffer{}); err == nil {
+		t.Error("expected error creating writer without a tabular schema")
+	}
+	if _, err := NewParquetWriter(parquetTestStructure(map[string]interface{}{"compression": "lzo"}), &bytes.Buffer{}); err == nil {
+		t.Error("expected error creating writer with an unknown codec")
+	}
+
+	hintCases := []struct {
+		cols map[string]interface{}
+		err  string
+	}{
+		{map[string]interface{}{"missing": map[string]interface{}{"encoding": "plain"}}, "parquet format config has hints for unknown column 'missing'"},
+		{map[string]interface{}{"ok": map[string]interface{}{"encoding": "dictionary"}}, "column ok: dictionary encoding isn't supported for boolean columns"},
+		{map[string]interface{}{"ratio": map[string]interface{}{"encoding": "delta"}}, "column ratio: delta encoding requires an integer column"},
+		{map[string]interface{}{"name": map[string]interface{}{"compression": "lzo"}}, "column name: unsupported parquet compression: lzo"},
+	}
+	for i, c := range hintCases {
+		_, err := NewParquetWriter(parquetTestStructure(map[string]interface{}{"columns": c.cols}), &bytes.Buffer{})
+		if err == nil || err.Error() != c.err {
+			t.Errorf("hint case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+
+	cases := []struct {
+		val interface{}
+		err string
+	}{
+		{map[string]interface{}{"a": 1}, "expected array value to write parquet row. got: map[string]interface {}"},
+		{[]interface{}{"a", 1, 1, true, nil, "extra"}, "entry 0 has 6 values, schema has 5 columns"},
+		{[]interface{}{1}, "entry 0 column name: expected string value, got int"},
+		{[]interface{}{"a", 1.5}, "entry 0 column count: expected integer value, got float64"},
+		{[]interface{}{"a", 1, "1"}, "entry 0 column ratio: expected number value, got string"},
+		{[]interface{}{"a", 1, 1, "true"}, "entry 0 column ok: expected boolean value, got string"},
+	}
+	for i, c := range cases {
+		w, err := NewParquetWriter(parquetTestStructure(nil), &bytes.Buffer{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = w.WriteEntry(Entry{Value: c.val})
+		if err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestParquetReaderErrors(t *testing.T) {
+	st := parquetTestStructure(nil)
+	buf := &bytes.Buffer{}
+	w, err := NewParquetWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteEntry(Entry{Value: []interface{}{"a", 1, 1.5, true, nil}})
+	w.Close()
+	valid := buf.Bytes()
+
+	corrupt := append([]byte{}, valid...)
+	// overwrite the start of the first page header
+	copy(corrupt[4:], []byte{0xff, 0xff, 0xff, 0xff})
+
+	cases := []struct {
+		data []byte
+		err  string
+	}{
+		{[]byte("a,b,c\n1,2,3\n"), "not a parquet file"},
+		{append([]byte("PAR1\xff\xff\xff\x00"), parquetMagic...), "invalid parquet footer length"},
+		{corrupt, ""},
+	}
+	for i, c := range cases {
+		r, err := NewParquetReader(st, bytes.NewReader(c.data))
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if _, err := r.ReadEntry(); err == nil {
+			t.Errorf("case %d expected error reading corrupt entry", i)
+		}
+	}
+}
+
+func TestParquetDecodeHybrid(t *testing.T) {
+	cases := []struct {
+		data     []byte
+		bitWidth int
+		n        int
+		expect   []int
+	}{
+		// run of 5 ones
+		{[]byte{5 << 1, 1}, 1, 5, []int{1, 1, 1, 1, 1}},
+		// one bit-packed group of 3 bit values 0-7
+		{[]byte{1<<1 | 1, 0x88, 0xC6, 0xFA}, 3, 8, []int{0, 1, 2, 3, 4, 5, 6, 7}},
+		// run followed by a partially used bit-packed group
+		{[]byte{2 << 1, 3, 1<<1 | 1, 0x1B, 0x00}, 2, 5, []int{3, 3, 3, 2, 1}},
+	}
+	for i, c := range cases {
+		got, err := parquetDecodeHybrid(c.data, c.bitWidth, c.n)
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(c.expect, got) {
+			t.Errorf("case %d mismatch. expected: %v, got: %v", i, c.expect, got)
+		}
+	}
+
+	if _, err := parquetDecodeHybrid([]byte{1<<1 | 1}, 3, 8); err == nil {
+		t.Error("expected error decoding truncated data")
+	}
+}
+
+func TestParquetColumnCodecsSize(t *testing.T) {
+	write := func(opts map[string]interface{}) []byte {
+		st := &dataset.Structure{
+			Format:       "parquet",
+			FormatConfig: opts,
+			Schema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "array",
+					"items": []interface{}{
+						map[string]interface{}{"title": "country", "type": "string"},
+						map[string]interface{}{"title": "timestamp", "type": "integer"},
+					},
+				},
+			},
+		}
+		buf := &bytes.Buffer{}
+		w, err := NewParquetWriter(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		countries := []string{"Deutschland", "Österreich", "Schweiz"}
+		for i := 0; i < 2000; i++ {
+			row := []interface{}{countries[i%len(countries)], 1577836800 + i*60 + i%7}
+			if err := w.WriteEntry(Entry{Index: i, Value: row}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := NewParquetReader(st, bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2000; i++ {
+			ent, err := r.ReadEntry()
+			if err != nil {
+				t.Fatal(err)
+			}
+			expect := []interface{}{countries[i%len(countries)], 1577836800 + i*60 + i%7}
+			if !reflect.DeepEqual(ent.Value, expect) {
+				t.Fatalf("entry %d mismatch. expected: %v, got: %v", i, expect, ent.Value)
+			}
+		}
+		return buf.Bytes()
+	}
+
+	plain := write(map[string]interface{}{"compression": "uncompressed"})
+	hinted := write(map[string]interface{}{"compression": "uncompressed", "columns": map[string]interface{}{
+		"country":   map[string]interface{}{"encoding": "dictionary"},
+		"timestamp": map[string]interface{}{"encoding": "delta"},
+	}})
+	if len(hinted)*4 > len(plain) {
+		t.Errorf("expected encoding hints to shrink output at least 4x. plain: %d bytes, hinted: %d bytes", len(plain), len(hinted))
+	}
+}
+
+func TestParquetDelta(t *testing.T) {
+	cases := [][]int64{
+		{},
+		{42},
+		{1, 2, 3, 4, 5},
+		{-5, 1 << 62, -1 << 63, 1<<63 - 1, 0, 0, 0},
+	}
+	var long []int64
+	for i := 0; i < 300; i++ {
+		long = append(long, int64(i*i)-1000)
+	}
+	cases = append(cases, long)
+
+	for i, vals := range cases {
+		got, err := parquetDecodeDelta(parquetEncodeDelta(vals), len(vals))
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if len(vals) == 0 && len(got) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(vals, got) {
+			t.Errorf("case %d mismatch. expected: %v, got: %v", i, vals, got)
+		}
+	}
+
+	if _, err := parquetDecodeDelta([]byte{128, 4}, 2); err == nil {
+		t.Error("expected error decoding truncated data")
+	}
+}
+
+func TestParquetEncodeHybrid(t *testing.T) {
+	cases := []struct {
+		vals     []int
+		bitWidth int
+	}{
+		{[]int{0, 0, 0}, 0},
+		{[]int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, 1},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 1}, 3},
+		{[]int{5, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 300}, 9},
+	}
+	for i, c := range cases {
+		got, err := parquetDecodeHybrid(parquetEncodeHybrid(c.vals, c.bitWidth), c.bitWidth, len(c.vals))
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(c.vals, got) {
+			t.Errorf("case %d mismatch. expected: %v, got: %v", i, c.vals, got)
+		}
+	}
+}
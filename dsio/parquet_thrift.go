@@ -0,0 +1,324 @@
+package dsio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// parquet file metadata & page headers are serialized with the thrift compact
+// protocol. tstruct & tlist model just enough of thrift to read & write them
+// without generated code. see
+// https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md
+
+// thrift compact protocol type ids
+const (
+	tcStop   = 0
+	tcTrue   = 1
+	tcFalse  = 2
+	tcByte   = 3
+	tcI16    = 4
+	tcI32    = 5
+	tcI64    = 6
+	tcDouble = 7
+	tcBinary = 8
+	tcList   = 9
+	tcSet    = 10
+	tcMap    = 11
+	tcStruct = 12
+
+	// thriftMaxDepth limits struct nesting when decoding, guarding against
+	// corrupt data
+	thriftMaxDepth = 32
+)
+
+// tfield is a thrift struct field. v is one of bool, int32, int64, string,
+// []byte, tstruct or tlist. fields with nil values aren't written
+type tfield struct {
+	id int16
+	v  interface{}
+}
+
+// tstruct is a thrift struct, in field id order
+type tstruct []tfield
+
+// tlist is a thrift list with elements of type elem
+type tlist struct {
+	elem  byte
+	items []interface{}
+}
+
+// tvalues is a decoded thrift struct, mapping field ids to values. integers
+// decode as int64, binary fields as []byte, lists as []interface{} & structs
+// as tvalues
+type tvalues map[int16]interface{}
+
+func (tv tvalues) int(id int16) int64 {
+	i, _ := tv[id].(int64)
+	return i
+}
+
+func (tv tvalues) has(id int16) bool {
+	_, ok := tv[id]
+	return ok
+}
+
+func (tv tvalues) str(id int16) string {
+	b, _ := tv[id].([]byte)
+	return string(b)
+}
+
+func (tv tvalues) structVal(id int16) tvalues {
+	s, _ := tv[id].(tvalues)
+	return s
+}
+
+func (tv tvalues) list(id int16) []interface{} {
+	l, _ := tv[id].([]interface{})
+	return l
+}
+
+// thriftEncode serializes a struct
+func thriftEncode(s tstruct) []byte {
+	return appendThriftStruct(nil, s)
+}
+
+func appendThriftStruct(buf []byte, s tstruct) []byte {
+	var last int16
+	for _, f := range s {
+		if f.v == nil {
+			continue
+		}
+		typ := thriftType(f.v)
+		if b, ok := f.v.(bool); ok && !b {
+			typ = tcFalse
+		}
+		if delta := f.id - last; delta > 0 && delta <= 15 {
+			buf = append(buf, byte(delta)<<4|typ)
+		} else {
+			buf = append(buf, typ)
+			buf = binary.AppendVarint(buf, int64(f.id))
+		}
+		last = f.id
+		if typ != tcTrue && typ != tcFalse {
+			buf = appendThriftValue(buf, f.v)
+		}
+	}
+	return append(buf, tcStop)
+}
+
+func thriftType(v interface{}) byte {
+	switch v.(type) {
+	case bool:
+		return tcTrue
+	case int32:
+		return tcI32
+	case int64:
+		return tcI64
+	case string, []byte:
+		return tcBinary
+	case tlist:
+		return tcList
+	case tstruct:
+		return tcStruct
+	}
+	panic(fmt.Sprintf("thrift: unsupported value type %T", v))
+}
+
+func appendThriftValue(buf []byte, v interface{}) []byte {
+	switch t := v.(type) {
+	case bool:
+		if t {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case int32:
+		return binary.AppendVarint(buf, int64(t))
+	case int64:
+		return binary.AppendVarint(buf, t)
+	case string:
+		buf = binary.AppendUvarint(buf, uint64(len(t)))
+		return append(buf, t...)
+	case []byte:
+		buf = binary.AppendUvarint(buf, uint64(len(t)))
+		return append(buf, t...)
+	case tlist:
+		if len(t.items) < 15 {
+			buf = append(buf, byte(len(t.items))<<4|t.elem)
+		} else {
+			buf = append(buf, 0xf0|t.elem)
+			buf = binary.AppendUvarint(buf, uint64(len(t.items)))
+		}
+		for _, item := range t.items {
+			buf = appendThriftValue(buf, item)
+		}
+		return buf
+	case tstruct:
+		return appendThriftStruct(buf, t)
+	}
+	panic(fmt.Sprintf("thrift: unsupported value type %T", v))
+}
+
+// thriftReader decodes thrift compact values from a byte slice
+type thriftReader struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+var errThriftCorrupt = fmt.Errorf("corrupt thrift data")
+
+func (r *thriftReader) readByte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, errThriftCorrupt
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *thriftReader) readUvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		return 0, errThriftCorrupt
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *thriftReader) readVarint() (int64, error) {
+	v, n := binary.Varint(r.data[r.pos:])
+	if n <= 0 {
+		return 0, errThriftCorrupt
+	}
+	r.pos += n
+	return v, nil
+}
+
+// readStruct decodes a struct, skipping map fields
+func (r *thriftReader) readStruct() (tvalues, error) {
+	if r.depth++; r.depth > thriftMaxDepth {
+		return nil, errThriftCorrupt
+	}
+	defer func() { r.depth-- }()
+
+	tv := tvalues{}
+	var last int16
+	for {
+		b, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == tcStop {
+			return tv, nil
+		}
+		typ := b & 0x0f
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			v, err := r.readVarint()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		last = id
+
+		switch typ {
+		case tcTrue:
+			tv[id] = true
+		case tcFalse:
+			tv[id] = false
+		default:
+			v, err := r.readValue(typ)
+			if err != nil {
+				return nil, err
+			}
+			if v != nil {
+				tv[id] = v
+			}
+		}
+	}
+}
+
+func (r *thriftReader) readValue(typ byte) (interface{}, error) {
+	switch typ {
+	case tcTrue, tcFalse:
+		// bools in collections are a full byte
+		b, err := r.readByte()
+		return b == tcTrue, err
+	case tcByte:
+		b, err := r.readByte()
+		return int64(int8(b)), err
+	case tcI16, tcI32, tcI64:
+		return r.readVarint()
+	case tcDouble:
+		if r.pos+8 > len(r.data) {
+			return nil, errThriftCorrupt
+		}
+		f := math.Float64frombits(binary.LittleEndian.Uint64(r.data[r.pos:]))
+		r.pos += 8
+		return f, nil
+	case tcBinary:
+		n, err := r.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(len(r.data)-r.pos) {
+			return nil, errThriftCorrupt
+		}
+		b := r.data[r.pos : r.pos+int(n)]
+		r.pos += int(n)
+		return b, nil
+	case tcList, tcSet:
+		h, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(h >> 4)
+		if size == 15 {
+			if size, err = r.readUvarint(); err != nil {
+				return nil, err
+			}
+		}
+		// every element takes at least a byte
+		if size > uint64(len(r.data)-r.pos) {
+			return nil, errThriftCorrupt
+		}
+		items := make([]interface{}, 0, size)
+		for i := uint64(0); i < size; i++ {
+			v, err := r.readValue(h & 0x0f)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case tcMap:
+		size, err := r.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return nil, nil
+		}
+		if size > uint64(len(r.data)-r.pos) {
+			return nil, errThriftCorrupt
+		}
+		kv, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		for i := uint64(0); i < size; i++ {
+			if _, err := r.readValue(kv >> 4); err != nil {
+				return nil, err
+			}
+			if _, err := r.readValue(kv & 0x0f); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case tcStruct:
+		return r.readStruct()
+	}
+	return nil, errThriftCorrupt
+}
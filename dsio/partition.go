@@ -0,0 +1,244 @@
+package dsio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/qri-io/dataset"
+)
+
+// PartitionManifest lists the shards of a body split by a structure's
+// partition
+type PartitionManifest struct {
+	// Format of the shards
+	Format string `json:"format"`
+	// Partition the body was split by
+	Partition dataset.Partition `json:"partition"`
+	// Shards in key order
+	Shards []*PartitionShard `json:"shards"`
+}
+
+// PartitionShard is a body file holding every entry with the same partition
+// key
+type PartitionShard struct {
+	// Key shared by the shard's entries. entries with a null partition value
+	// have an empty key
+	Key string `json:"key"`
+	// Entries is the number of entries in the shard
+	Entries int `json:"entries"`
+	// Length of the shard in bytes
+	Length int `json:"length"`
+	// Path to the stored shard
+	Path string `json:"path,omitempty"`
+}
+
+// PartitionBody reads a body, splitting it into shards by the structure's
+// partition. Each shard is a complete body encoded in the structure's format,
+// entries keep their body order within a shard. It returns the manifest &
+// shard data in key order
+func PartitionBody(st *dataset.Structure, r io.Reader) (*PartitionManifest, [][]byte, error) {
+	if st.Partition == nil {
+		return nil, nil, fmt.Errorf("structure has no partition")
+	}
+	if err := st.Partition.Validate(); err != nil {
+		return nil, nil, err
+	}
+	column, err := partitionColumn(st)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// shards are plain bodies, without chunk checksums of the whole body
+	shardSt := &dataset.Structure{}
+	shardSt.Assign(st)
+	shardSt.ChunkChecksums = nil
+
+	er, err := newEntryReader(st, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	type shard struct {
+		*PartitionShard
+		buf *bytes.Buffer
+		w   EntryWriter
+	}
+	shards := map[string]*shard{}
+	for i := 0; ; i++ {
+		ent, err := er.ReadEntry()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			log.Debug(err.Error())
+			return nil, nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+
+		var val interface{}
+		switch v := ent.Value.(type) {
+		case []interface{}:
+			if column < 0 {
+				return nil, nil, fmt.Errorf("entry %d: expected an object entry", i)
+			}
+			if column < len(v) {
+				val = v[column]
+			}
+		case map[string]interface{}:
+			val = v[st.Partition.Column]
+		default:
+			return nil, nil, fmt.Errorf("entry %d: can't partition %T entries", i, ent.Value)
+		}
+		key, err := st.Partition.Key(val)
+		if err != nil {
+			log.Debug(err.Error())
+			return nil, nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+
+		s, ok := shards[key]
+		if !ok {
+			s = &shard{PartitionShard: &PartitionShard{Key: key}, buf: &bytes.Buffer{}}
+			if s.w, err = newEntryWriter(shardSt, s.buf); err != nil {
+				return nil, nil, err
+			}
+			shards[key] = s
+		}
+		ent.Index = s.Entries
+		if err := s.w.WriteEntry(ent); err != nil {
+			log.Debug(err.Error())
+			return nil, nil, fmt.Errorf("writing partition %s: %w", key, err)
+		}
+		s.Entries++
+	}
+
+	m := &PartitionManifest{Format: st.Format, Partition: *st.Partition}
+	for _, s := range shards {
+		if err := s.w.Close(); err != nil {
+			log.Debug(err.Error())
+			return nil, nil, fmt.Errorf("writing partition %s: %w", s.Key, err)
+		}
+		s.Length = s.buf.Len()
+		m.Shards = append(m.Shards, s.PartitionShard)
+	}
+	sort.Slice(m.Shards, func(i, j int) bool { return m.Shards[i].Key < m.Shards[j].Key })
+	data := make([][]byte, len(m.Shards))
+	for i, s := range m.Shards {
+		data[i] = shards[s.Key].buf.Bytes()
+	}
+	return m, data, nil
+}
+
+// partitionColumn gives the index of the partition column in tabular
+// entries, -1 for bodies with object entries
+func partitionColumn(st *dataset.Structure) (int, error) {
+	return entryColumn(st, "partition", st.Partition.Column)
+}
+
+// entryColumn gives the index of a column in tabular entries, -1 for bodies
+// with object entries. use names the feature needing the column in errors
+func entryColumn(st *dataset.Structure, use, column string) (int, error) {
+	if items, ok := st.Schema["items"].(map[string]interface{}); ok && items["type"] == "object" || st.Schema["type"] == "object" {
+		return -1, nil
+	}
+	titles, _, err := terribleHackToGetHeaderRowAndTypes(st)
+	if err != nil {
+		return 0, fmt.Errorf("%s requires a tabular schema or object entries", use)
+	}
+	for i, title := range titles {
+		if title == column {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%s column %s isn't in the schema", use, column)
+}
+
+// Range gives the shards with keys between from & to inclusive. an empty
+// bound leaves that end of the range open
+func (m *PartitionManifest) Range(from, to string) []*PartitionShard {
+	var shards []*PartitionShard
+	for _, s := range m.Shards {
+		if (from == "" || s.Key >= from) && (to == "" || s.Key <= to) {
+			shards = append(shards, s)
+		}
+	}
+	return shards
+}
+
+// PartitionReader reads the entries of a list of shards in order, opening
+// each shard when it's reached. Entries are indexed from the start of the
+// first shard, and their provenance records the shard key & their row in
+// the shard
+type PartitionReader struct {
+	st      *dataset.Structure
+	shards  []*PartitionShard
+	open    func(shard *PartitionShard) (io.Reader, error)
+	cur     EntryReader
+	curKey  string
+	curRead int
+	next    int
+	read    int
+}
+
+var _ EntryReader = (*PartitionReader)(nil)
+
+// NewPartitionReader creates a reader of shards, open gives the data of a
+// shard
+func NewPartitionReader(st *dataset.Structure, shards []*PartitionShard, open func(shard *PartitionShard) (io.Reader, error)) *PartitionReader {
+	return &PartitionReader{st: st, shards: shards, open: open}
+}
+
+// Structure gives this reader's structure
+func (r *PartitionReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry reads one entry, moving on to the next shard at the end of each
+// shard
+func (r *PartitionReader) ReadEntry() (Entry, error) {
+	for {
+		if r.cur == nil {
+			if r.next >= len(r.shards) {
+				return Entry{}, io.EOF
+			}
+			shard := r.shards[r.next]
+			r.next++
+			data, err := r.open(shard)
+			if err != nil {
+				log.Debug(err.Error())
+				return Entry{}, fmt.Errorf("opening partition %s: %w", shard.Key, err)
+			}
+			if r.cur, err = newEntryReader(r.st, data); err != nil {
+				return Entry{}, fmt.Errorf("reading partition %s: %w", shard.Key, err)
+			}
+			r.curKey, r.curRead = shard.Key, 0
+		}
+
+		ent, err := r.cur.ReadEntry()
+		if err == io.EOF {
+			if err := r.cur.Close(); err != nil {
+				return Entry{}, err
+			}
+			r.cur = nil
+			continue
+		} else if err != nil {
+			return Entry{}, err
+		}
+		ent.Index = r.read
+		ent.Provenance = &Provenance{Row: r.curRead, Source: r.curKey}
+		r.read++
+		r.curRead++
+		return ent, nil
+	}
+}
+
+// EntriesRead gives the number of entries read
+func (r *PartitionReader) EntriesRead() int {
+	return r.read
+}
+
+// Close closes the shard being read
+func (r *PartitionReader) Close() error {
+	if r.cur != nil {
+		return r.cur.Close()
+	}
+	return nil
+}
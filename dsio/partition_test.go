@@ -0,0 +1,144 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestPartitionBody(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "ts", "type": "string"},
+					map[string]interface{}{"title": "n", "type": "integer"},
+				},
+			},
+		},
+		Partition: &dataset.Partition{Column: "ts", Granularity: dataset.PartitionDay},
+	}
+	body := `[["2020-01-02T10:00:00Z",1],["2020-01-01T23:00:00Z",2],[null,3],["2020-01-02T11:00:00Z",4]]`
+	m, data, err := PartitionBody(st, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Format != "json" || m.Partition.Column != "ts" {
+		t.Errorf("manifest mismatch: %v", m)
+	}
+	expect := []struct {
+		key  string
+		data string
+	}{
+		{"", `[[null,3]]`},
+		{"2020-01-01", `[["2020-01-01T23:00:00Z",2]]`},
+		{"2020-01-02", `[["2020-01-02T10:00:00Z",1],["2020-01-02T11:00:00Z",4]]`},
+	}
+	if len(m.Shards) != len(expect) {
+		t.Fatalf("expected %d shards, got: %d", len(expect), len(m.Shards))
+	}
+	for i, ex := range expect {
+		s := m.Shards[i]
+		if s.Key != ex.key {
+			t.Errorf("shard %d key mismatch. expected: '%s', got: '%s'", i, ex.key, s.Key)
+		}
+		if s.Length != len(data[i]) {
+			t.Errorf("shard %d length mismatch. expected: %d, got: %d", i, len(data[i]), s.Length)
+		}
+		if string(data[i]) != ex.data {
+			t.Errorf("shard %d data mismatch.\nexpected: %s\ngot:      %s", i, ex.data, data[i])
+		}
+	}
+	if m.Shards[2].Entries != 2 {
+		t.Errorf("expected 2 entries in the last shard, got: %d", m.Shards[2].Entries)
+	}
+
+	shards := m.Range("2020-01-01", "")
+	if len(shards) != 2 {
+		t.Fatalf("expected 2 shards in range, got: %d", len(shards))
+	}
+	open := func(shard *PartitionShard) (io.Reader, error) {
+		for i, s := range m.Shards {
+			if s == shard {
+				return bytes.NewReader(data[i]), nil
+			}
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+	r := NewPartitionReader(st, shards, open)
+	got := []interface{}{}
+	err = EachEntry(r, func(i int, ent Entry, err error) error {
+		if ent.Index != i {
+			t.Errorf("entry %d index mismatch. got: %d", i, ent.Index)
+		}
+		got = append(got, ent.Value.([]interface{})[1])
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]interface{}{2, 1, 4}, got) {
+		t.Errorf("entries mismatch. got: %v", got)
+	}
+	if r.EntriesRead() != 3 {
+		t.Errorf("expected 3 entries read, got: %d", r.EntriesRead())
+	}
+}
+
+func TestPartitionBodyObjects(t *testing.T) {
+	st := &dataset.Structure{
+		Format:    "ndjson",
+		Schema:    dataset.BaseSchemaArray,
+		Partition: &dataset.Partition{Column: "region"},
+	}
+	body := "{\"region\":\"b\",\"n\":1}\n{\"region\":\"a\",\"n\":2}\n{\"region\":\"b\",\"n\":3}\n"
+	m, data, err := PartitionBody(st, strings.NewReader(body))
+	if err == nil {
+		t.Fatalf("expected error partitioning without column definitions, got shards: %v %s", m, data)
+	}
+
+	st.Schema = map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}}
+	m, data, err = PartitionBody(st, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Shards) != 2 || m.Shards[0].Key != "a" || m.Shards[1].Key != "b" {
+		t.Fatalf("shards mismatch: %v", m.Shards)
+	}
+	if string(data[1]) != "{\"n\":1,\"region\":\"b\"}\n{\"n\":3,\"region\":\"b\"}\n" {
+		t.Errorf("shard data mismatch, got: %q", data[1])
+	}
+}
+
+func TestPartitionBodyErrors(t *testing.T) {
+	tabular := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":  "array",
+			"items": []interface{}{map[string]interface{}{"title": "a"}},
+		},
+	}
+	cases := []struct {
+		st   *dataset.Structure
+		body string
+		err  string
+	}{
+		{&dataset.Structure{Format: "json", Schema: tabular}, `[]`, "structure has no partition"},
+		{&dataset.Structure{Format: "json", Schema: tabular, Partition: &dataset.Partition{}}, `[]`, "partition column is required"},
+		{&dataset.Structure{Format: "json", Schema: tabular, Partition: &dataset.Partition{Column: "b"}}, `[]`, "partition column b isn't in the schema"},
+		{&dataset.Structure{Format: "json", Schema: tabular, Partition: &dataset.Partition{Column: "a", Granularity: "day"}}, `[["soon"]]`, "entry 0: invalid date or time 'soon'"},
+	}
+	for i, c := range cases {
+		_, _, err := PartitionBody(c.st, strings.NewReader(c.body))
+		if err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,598 @@
+package dsio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/vals"
+)
+
+// pgCopySignature is the fixed 11-byte header that opens a binary COPY stream
+var pgCopySignature = []byte("PGCOPY\n\377\r\n\000")
+
+// pgCopyMaxField caps the length of a binary COPY field at postgres' own 1GB
+// limit, guarding against corrupt length words
+const pgCopyMaxField = 1 << 30
+
+// PGCopyConfig encapsulates configuration for postgres COPY readers & writers
+type PGCopyConfig struct {
+	// Binary switches from the default text format to the binary COPY format
+	Binary bool
+	// Delimiter separates columns in text format. default is tab
+	Delimiter byte
+	// Null is the string representing a null value in text format.
+	// default is \N
+	Null string
+}
+
+func newPGCopyConfig(configs []func(cfg *PGCopyConfig)) *PGCopyConfig {
+	cfg := &PGCopyConfig{
+		Delimiter: '\t',
+		Null:      `\N`,
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	return cfg
+}
+
+// PGCopyReader implements the EntryReader interface for data in postgres
+// COPY text or binary format, as produced by "COPY ... TO STDOUT"
+type PGCopyReader struct {
+	cfg    *PGCopyConfig
+	st     *dataset.Structure
+	r      *bufio.Reader
+	src    *TrackedReader
+	types  []string
+	header bool
+	idx    int
+}
+
+var _ EntryReader = (*PGCopyReader)(nil)
+
+// NewPGCopyReader creates a reader from a structure and read source. The
+// structure schema should describe an array of arrays, column types are used
+// to decode values
+func NewPGCopyReader(st *dataset.Structure, r io.Reader, configs ...func(cfg *PGCopyConfig)) *PGCopyReader {
+	_, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+	src := NewTrackedReader(r)
+	return &PGCopyReader{
+		cfg:   newPGCopyConfig(configs),
+		st:    st,
+		r:     bufio.NewReader(src),
+		src:   src,
+		types: types,
+	}
+}
+
+// Structure gives this reader's structure
+func (r *PGCopyReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// EntriesRead gives the number of entries read
+func (r *PGCopyReader) EntriesRead() int {
+	return r.idx
+}
+
+// BytesProcessed gives the number of bytes parsed
+func (r *PGCopyReader) BytesProcessed() int64 {
+	return int64(r.src.BytesRead() - r.r.Buffered())
+}
+
+// ReadEntry reads one row of COPY data
+func (r *PGCopyReader) ReadEntry() (Entry, error) {
+	var (
+		row []interface{}
+		err error
+	)
+	if r.cfg.Binary {
+		row, err = r.readBinaryRow()
+	} else {
+		row, err = r.readTextRow()
+	}
+	if err != nil {
+		if err != io.EOF {
+			log.Debug(err.Error())
+		}
+		return Entry{}, err
+	}
+
+	ent := Entry{Index: r.idx, Value: row}
+	r.idx++
+	return ent, nil
+}
+
+func (r *PGCopyReader) columnType(i int) string {
+	if i < len(r.types) {
+		return r.types[i]
+	}
+	return "string"
+}
+
+func (r *PGCopyReader) readTextRow() ([]interface{}, error) {
+	line, err := r.r.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return nil, io.EOF
+	} else if err != nil && err != io.EOF {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	// end-of-data marker
+	if line == `\.` {
+		return nil, io.EOF
+	}
+
+	fields := strings.Split(line, string(r.cfg.Delimiter))
+	row := make([]interface{}, len(fields))
+	for i, f := range fields {
+		if f == r.cfg.Null {
+			row[i] = nil
+			continue
+		}
+		str, err := pgCopyUnescape(f)
+		if err != nil {
+			return nil, fmt.Errorf("row %d column %d: %w", r.idx, i, err)
+		}
+		row[i] = pgCopyDecodeText(r.columnType(i), str)
+	}
+	return row, nil
+}
+
+// pgCopyDecodeText casts a text value to a column type, leaving the value as
+// a string if casting fails
+func pgCopyDecodeText(t, str string) interface{} {
+	switch t {
+	case "number":
+		if num, err := vals.ParseNumber([]byte(str)); err == nil {
+			return num
+		}
+	case "integer":
+		if num, err := vals.ParseInteger([]byte(str)); err == nil {
+			return num
+		}
+	case "boolean":
+		switch str {
+		case "t":
+			return true
+		case "f":
+			return false
+		}
+		if b, err := vals.ParseBoolean([]byte(str)); err == nil {
+			return b
+		}
+	case "object":
+		v := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(str), &v); err == nil {
+			return v
+		}
+	case "array":
+		v := []interface{}{}
+		if err := json.Unmarshal([]byte(str), &v); err == nil {
+			return v
+		}
+	}
+	return str
+}
+
+// pgCopyUnescape reverses COPY text format backslash escaping
+func pgCopyUnescape(s string) (string, error) {
+	if strings.IndexByte(s, '\\') == -1 {
+		return s, nil
+	}
+
+	buf := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			buf = append(buf, s[i])
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", fmt.Errorf("trailing backslash")
+		}
+		switch c := s[i]; c {
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'v':
+			buf = append(buf, '\v')
+		case 'x':
+			j := i + 1
+			for j < len(s) && j < i+3 && isHexDigit(s[j]) {
+				j++
+			}
+			if j == i+1 {
+				buf = append(buf, 'x')
+				continue
+			}
+			n, _ := strconv.ParseUint(s[i+1:j], 16, 8)
+			buf = append(buf, byte(n))
+			i = j - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			j := i
+			for j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7' {
+				j++
+			}
+			n, _ := strconv.ParseUint(s[i:j], 8, 8)
+			buf = append(buf, byte(n))
+			i = j - 1
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return string(buf), nil
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func (r *PGCopyReader) readBinaryRow() ([]interface{}, error) {
+	if !r.header {
+		if err := r.readBinaryHeader(); err != nil {
+			return nil, err
+		}
+		r.header = true
+	}
+
+	var count int16
+	if err := binary.Read(r.r, binary.BigEndian, &count); err != nil {
+		return nil, err
+	}
+	// file trailer
+	if count == -1 {
+		return nil, io.EOF
+	}
+	if count < 0 {
+		return nil, fmt.Errorf("row %d: invalid field count: %d", r.idx, count)
+	}
+
+	row := make([]interface{}, count)
+	for i := range row {
+		var length int32
+		if err := binary.Read(r.r, binary.BigEndian, &length); err != nil {
+			return nil, err
+		}
+		if length == -1 {
+			row[i] = nil
+			continue
+		}
+		if length < -1 || length > pgCopyMaxField {
+			return nil, fmt.Errorf("row %d column %d: invalid field length: %d", r.idx, i, length)
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(r.r, data); err != nil {
+			return nil, err
+		}
+		v, err := pgCopyDecodeBinary(r.columnType(i), data)
+		if err != nil {
+			return nil, fmt.Errorf("row %d column %d: %w", r.idx, i, err)
+		}
+		row[i] = v
+	}
+	return row, nil
+}
+
+func (r *PGCopyReader) readBinaryHeader() error {
+	sig := make([]byte, len(pgCopySignature))
+	if _, err := io.ReadFull(r.r, sig); err != nil {
+		return err
+	}
+	if !bytes.Equal(sig, pgCopySignature) {
+		return fmt.Errorf("invalid binary COPY signature")
+	}
+	var flags, extLen int32
+	if err := binary.Read(r.r, binary.BigEndian, &flags); err != nil {
+		return err
+	}
+	if err := binary.Read(r.r, binary.BigEndian, &extLen); err != nil {
+		return err
+	}
+	_, err := r.r.Discard(int(extLen))
+	return err
+}
+
+// pgCopyDecodeBinary decodes a binary field using the postgres network
+// representation of the type that corresponds to a column's schema type
+func pgCopyDecodeBinary(t string, data []byte) (interface{}, error) {
+	switch t {
+	case "integer":
+		switch len(data) {
+		case 2:
+			return int64(int16(binary.BigEndian.Uint16(data))), nil
+		case 4:
+			return int64(int32(binary.BigEndian.Uint32(data))), nil
+		case 8:
+			return int64(binary.BigEndian.Uint64(data)), nil
+		}
+		return nil, fmt.Errorf("invalid integer field length: %d", len(data))
+	case "number":
+		switch len(data) {
+		case 4:
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), nil
+		case 8:
+			return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+		}
+		return nil, fmt.Errorf("invalid number field length: %d", len(data))
+	case "boolean":
+		if len(data) != 1 {
+			return nil, fmt.Errorf("invalid boolean field length: %d", len(data))
+		}
+		return data[0] != 0, nil
+	}
+	return pgCopyDecodeText(t, string(data)), nil
+}
+
+// Close finalizes the reader
+func (r *PGCopyReader) Close() error {
+	return nil
+}
+
+// PGCopyWriter implements the EntryWriter interface, writing entries in
+// postgres COPY text or binary format, suitable for "COPY ... FROM STDIN"
+type PGCopyWriter struct {
+	cfg         *PGCopyConfig
+	st          *dataset.Structure
+	w           *bufio.Writer
+	out         *countingWriter
+	types       []string
+	rowsWritten int
+	// headerWritten is set once the binary file header is written
+	headerWritten bool
+	// row buffers the entry being encoded, rows that fail to encode aren't
+	// written
+	row bytes.Buffer
+}
+
+var _ EntryWriter = (*PGCopyWriter)(nil)
+
+// NewPGCopyWriter creates a Writer from a structure and write destination
+func NewPGCopyWriter(st *dataset.Structure, w io.Writer, configs ...func(cfg *PGCopyConfig)) *PGCopyWriter {
+	_, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+	out := &countingWriter{w: w}
+	return &PGCopyWriter{
+		cfg:   newPGCopyConfig(configs),
+		st:    st,
+		w:     bufio.NewWriter(out),
+		out:   out,
+		types: types,
+	}
+}
+
+// Structure gives this writer's structure
+func (w *PGCopyWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// EntriesWritten gives the number of entries written
+func (w *PGCopyWriter) EntriesWritten() int {
+	return w.rowsWritten
+}
+
+// BytesProcessed gives the number of bytes flushed to the destination
+func (w *PGCopyWriter) BytesProcessed() int64 {
+	return w.out.n
+}
+
+// WriteEntry writes one row of COPY data
+func (w *PGCopyWriter) WriteEntry(ent Entry) error {
+	arr, ok := ent.Value.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected array value to write COPY row. got: %v", ent)
+	}
+
+	w.row.Reset()
+	var err error
+	if w.cfg.Binary {
+		err = w.writeBinaryRow(arr)
+	} else {
+		err = w.writeTextRow(arr)
+	}
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error encoding entry: %w", err)
+	}
+
+	if w.cfg.Binary && !w.headerWritten {
+		w.writeBinaryHeader()
+	}
+	if _, err := w.w.Write(w.row.Bytes()); err != nil {
+		return err
+	}
+	w.rowsWritten++
+	return nil
+}
+
+func (w *PGCopyWriter) writeTextRow(arr []interface{}) error {
+	for i, v := range arr {
+		if i > 0 {
+			w.row.WriteByte(w.cfg.Delimiter)
+		}
+		if v == nil {
+			w.row.WriteString(w.cfg.Null)
+			continue
+		}
+		str, err := pgCopyText(v)
+		if err != nil {
+			return err
+		}
+		w.row.WriteString(pgCopyEscape(str, w.cfg.Delimiter))
+	}
+	return w.row.WriteByte('\n')
+}
+
+// pgCopyText gives the postgres text representation of a value
+func pgCopyText(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case bool:
+		if x {
+			return "t", nil
+		}
+		return "f", nil
+	case int:
+		return strconv.Itoa(x), nil
+	case int64:
+		return strconv.FormatInt(x, 10), nil
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64), nil
+	case []interface{}, map[string]interface{}:
+		data, err := json.Marshal(x)
+		return string(data), err
+	default:
+		return "", fmt.Errorf("unrecognized encoding type: %#v", v)
+	}
+}
+
+// pgCopyEscape backslash-escapes characters that have special meaning in
+// COPY text format
+func pgCopyEscape(s string, delim byte) string {
+	if !strings.ContainsAny(s, "\\\n\r\t\b\f\v"+string(delim)) {
+		return s
+	}
+	buf := make([]byte, 0, len(s)+4)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			buf = append(buf, '\\', '\\')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		case '\b':
+			buf = append(buf, '\\', 'b')
+		case '\f':
+			buf = append(buf, '\\', 'f')
+		case '\v':
+			buf = append(buf, '\\', 'v')
+		default:
+			if c == delim {
+				buf = append(buf, '\\')
+			}
+			buf = append(buf, c)
+		}
+	}
+	return string(buf)
+}
+
+func (w *PGCopyWriter) writeBinaryRow(arr []interface{}) error {
+	if len(arr) > math.MaxInt16 {
+		return fmt.Errorf("row has %d fields, binary COPY rows are limited to %d", len(arr), math.MaxInt16)
+	}
+	binary.Write(&w.row, binary.BigEndian, int16(len(arr)))
+	for i, v := range arr {
+		data, err := w.binaryField(i, v)
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			binary.Write(&w.row, binary.BigEndian, int32(-1))
+			continue
+		}
+		binary.Write(&w.row, binary.BigEndian, int32(len(data)))
+		w.row.Write(data)
+	}
+	return nil
+}
+
+func (w *PGCopyWriter) writeBinaryHeader() {
+	w.w.Write(pgCopySignature)
+	// flags & header extension length
+	binary.Write(w.w, binary.BigEndian, int32(0))
+	binary.Write(w.w, binary.BigEndian, int32(0))
+	w.headerWritten = true
+}
+
+// binaryField encodes a value using the network representation of the
+// column's type: int8 for integers, float8 for numbers, bool & text for
+// everything else
+func (w *PGCopyWriter) binaryField(i int, v interface{}) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	t := "string"
+	if i < len(w.types) {
+		t = w.types[i]
+	}
+
+	switch t {
+	case "integer":
+		var n int64
+		switch x := v.(type) {
+		case int:
+			n = int64(x)
+		case int64:
+			n = x
+		case float64:
+			// float64 can't hold every int64, so bounds are exclusive
+			if x != math.Trunc(x) || x < math.MinInt64 || x >= math.MaxInt64 {
+				return nil, fmt.Errorf("column %d: %v is not an integer", i, v)
+			}
+			n = int64(x)
+		default:
+			return nil, fmt.Errorf("column %d: %v is not an integer", i, v)
+		}
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(n))
+		return data, nil
+	case "number":
+		var f float64
+		switch x := v.(type) {
+		case int:
+			f = float64(x)
+		case int64:
+			f = float64(x)
+		case float64:
+			f = x
+		default:
+			return nil, fmt.Errorf("column %d: %v is not a number", i, v)
+		}
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint64(data, math.Float64bits(f))
+		return data, nil
+	case "boolean":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("column %d: %v is not a boolean", i, v)
+		}
+		if b {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	}
+
+	str, err := pgCopyText(v)
+	return []byte(str), err
+}
+
+// Close finalizes the writer, writing the binary trailer if necessary and
+// flushing buffered data
+func (w *PGCopyWriter) Close() error {
+	if w.cfg.Binary {
+		if !w.headerWritten {
+			w.writeBinaryHeader()
+		}
+		binary.Write(w.w, binary.BigEndian, int16(-1))
+	}
+	return w.w.Flush()
+}
@@ -0,0 +1,168 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestPGCopyRoundTrip(t *testing.T) {
+	st := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "name", "type": "string"},
+					map[string]interface{}{"title": "score", "type": "number"},
+					map[string]interface{}{"title": "active", "type": "boolean"},
+				},
+			},
+		},
+	}
+
+	rows := [][]interface{}{
+		{int64(1), "plain", 1.5, true},
+		{int64(2), "tab\there\nnewline \\ slash", nil, false},
+		{int64(-3), nil, 0.25, nil},
+	}
+
+	cases := []struct {
+		binary bool
+	}{
+		{false},
+		{true},
+	}
+
+	for i, c := range cases {
+		config := func(cfg *PGCopyConfig) { cfg.Binary = c.binary }
+
+		buf := &bytes.Buffer{}
+		w := NewPGCopyWriter(st, buf, config)
+		for _, row := range rows {
+			if err := w.WriteEntry(Entry{Value: row}); err != nil {
+				t.Fatalf("case %d unexpected error writing: %s", i, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("case %d unexpected error closing: %s", i, err)
+		}
+
+		r := NewPGCopyReader(st, buf, config)
+		for j, row := range rows {
+			ent, err := r.ReadEntry()
+			if err != nil {
+				t.Fatalf("case %d row %d unexpected error reading: %s", i, j, err)
+			}
+			if !reflect.DeepEqual(row, ent.Value) {
+				t.Errorf("case %d row %d mismatch.\nexpected: %#v\ngot:      %#v", i, j, row, ent.Value)
+			}
+		}
+		if _, err := r.ReadEntry(); err != io.EOF {
+			t.Errorf("case %d expected EOF, got: %v", i, err)
+		}
+	}
+}
+
+func TestPGCopyText(t *testing.T) {
+	st := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "name", "type": "string"},
+				},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	w := NewPGCopyWriter(st, buf)
+	w.WriteEntry(Entry{Value: []interface{}{1, "a\tb"}})
+	w.WriteEntry(Entry{Value: []interface{}{2, nil}})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expect := "1\ta\\tb\n2\t\\N\n"
+	if buf.String() != expect {
+		t.Errorf("output mismatch.\nexpected: %q\ngot:      %q", expect, buf.String())
+	}
+
+	r := NewPGCopyReader(st, bytes.NewBufferString("7\t\\x41\\102c\n\\.\n8\tignored\n"))
+	ent, err := r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ent.Value, []interface{}{int64(7), "ABc"}) {
+		t.Errorf("unescape mismatch. got: %#v", ent.Value)
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected end-of-data marker to return EOF, got: %v", err)
+	}
+}
+
+func TestPGCopyBinaryErrors(t *testing.T) {
+	st := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "name", "type": "string"},
+				},
+			},
+		},
+	}
+	config := func(cfg *PGCopyConfig) { cfg.Binary = true }
+
+	buf := &bytes.Buffer{}
+	w := NewPGCopyWriter(st, buf, config)
+	// rows that fail to encode aren't written, & don't stop the header being
+	// written once
+	if err := w.WriteEntry(Entry{Value: []interface{}{1.5, "a"}}); err == nil {
+		t.Error("expected a fractional integer value to error")
+	}
+	if err := w.WriteEntry(Entry{Value: []interface{}{float64(2), "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r := NewPGCopyReader(st, buf, config)
+	ent, err := r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ent.Value, []interface{}{int64(2), "b"}) {
+		t.Errorf("row mismatch. got: %#v", ent.Value)
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected EOF, got: %v", err)
+	}
+
+	header := append(append([]byte{}, pgCopySignature...), 0, 0, 0, 0, 0, 0, 0, 0)
+	cases := []struct {
+		description string
+		row         []byte
+		err         string
+	}{
+		{"negative field count", []byte{0xff, 0xfe}, "row 0: invalid field count: -2"},
+		{"negative field length", []byte{0, 1, 0xff, 0xff, 0xff, 0xfe}, "row 0 column 0: invalid field length: -2"},
+		{"field too long", []byte{0, 1, 0x7f, 0xff, 0xff, 0xff}, "row 0 column 0: invalid field length: 2147483647"},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := NewPGCopyReader(st, bytes.NewReader(append(header, c.row...)), config)
+			if _, err := r.ReadEntry(); err == nil || err.Error() != c.err {
+				t.Errorf("error mismatch. expected: %s, got: %v", c.err, err)
+			}
+		})
+	}
+}
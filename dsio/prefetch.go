@@ -0,0 +1,172 @@
+package dsio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/qri-io/dataset"
+)
+
+// PrefetchConfig encapsulates configuration for a PrefetchReader
+type PrefetchConfig struct {
+	// Size is the number of entries decoded ahead of the consumer. default 128
+	Size int
+	// Budget, if set, bounds the bytes held by prefetched entries. The budget
+	// can be shared with later stages, see MemoryBudget
+	Budget *MemoryBudget
+}
+
+// prefetched is an entry or error read by the background goroutine & the
+// bytes of budget the entry holds
+type prefetched struct {
+	ent  Entry
+	err  error
+	size int64
+}
+
+// PrefetchReader wraps an EntryReader, decoding entries in a background
+// goroutine into a bounded buffer while the consumer processes earlier
+// entries. Decoding & I/O overlap with the consumer's work instead of
+// idling between calls to ReadEntry. The first error read, including
+// io.EOF, is returned by every later call to ReadEntry.
+//
+// The wrapped reader is read from the background goroutine, and mustn't be
+// used directly until the PrefetchReader is closed
+type PrefetchReader struct {
+	r      EntryReader
+	budget *MemoryBudget
+	queue  chan prefetched
+	done   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	read   int
+	err    error
+	// held counts budgeted bytes of buffered entries
+	held int64
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+var _ EntryReader = (*PrefetchReader)(nil)
+
+// NewPrefetchReader starts a background goroutine reading from r
+func NewPrefetchReader(r EntryReader, configs ...func(cfg *PrefetchConfig)) *PrefetchReader {
+	cfg := &PrefetchConfig{
+		Size: 128,
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	if cfg.Size < 0 {
+		cfg.Size = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pr := &PrefetchReader{
+		r:      r,
+		budget: cfg.Budget,
+		queue:  make(chan prefetched, cfg.Size),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	go pr.fill()
+	return pr
+}
+
+func (pr *PrefetchReader) fill() {
+	defer close(pr.done)
+	defer close(pr.queue)
+	for i := 0; ; i++ {
+		ent, err := pr.r.ReadEntry()
+		if err != nil && err != io.EOF {
+			log.Debug(err.Error())
+			err = fmt.Errorf("error reading entry %d: %w", i, err)
+		}
+		var size int64
+		if err == nil && pr.budget != nil {
+			size = pr.budget.clamp(EntrySize(ent))
+			if pr.budget.acquireStage(pr.ctx, size, &pr.held) != nil {
+				return
+			}
+		}
+
+		select {
+		case pr.queue <- prefetched{ent: ent, err: err, size: size}:
+		case <-pr.ctx.Done():
+			if pr.budget != nil {
+				pr.budget.releaseStage(size, &pr.held)
+			}
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Structure gives the structure of the underlying reader
+func (pr *PrefetchReader) Structure() *dataset.Structure {
+	return pr.r.Structure()
+}
+
+// ReadEntry gives the next prefetched entry, blocking until one is decoded
+func (pr *PrefetchReader) ReadEntry() (Entry, error) {
+	if pr.err != nil {
+		return Entry{}, pr.err
+	}
+	res, ok := <-pr.queue
+	if !ok {
+		pr.err = fmt.Errorf("read from closed reader")
+		return Entry{}, pr.err
+	}
+	if res.err != nil {
+		pr.err = res.err
+		return Entry{}, pr.err
+	}
+	if pr.budget != nil {
+		pr.budget.releaseStage(res.size, &pr.held)
+	}
+	pr.read++
+	return res.ent, nil
+}
+
+// EntriesRead gives the number of entries the consumer has read, excluding
+// prefetched entries
+func (pr *PrefetchReader) EntriesRead() int {
+	return pr.read
+}
+
+// Buffered gives the number of entries decoded but not yet read
+func (pr *PrefetchReader) Buffered() int {
+	return len(pr.queue)
+}
+
+// Cap gives the size of the buffer. When Buffered equals Cap, the background
+// goroutine waits for the consumer
+func (pr *PrefetchReader) Cap() int {
+	return cap(pr.queue)
+}
+
+// Close stops prefetching, discarding buffered entries, & closes the
+// underlying reader once the background goroutine has stopped. Close
+// waits for an in-progress read of the underlying reader to finish
+func (pr *PrefetchReader) Close() error {
+	pr.closeOnce.Do(func() {
+		pr.cancel()
+		<-pr.done
+		for res := range pr.queue {
+			if pr.budget != nil {
+				pr.budget.releaseStage(res.size, &pr.held)
+			}
+		}
+		if err := pr.r.Close(); err != nil {
+			log.Debug(err.Error())
+			pr.closeErr = err
+		}
+	})
+	return pr.closeErr
+}
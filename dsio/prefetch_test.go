@@ -0,0 +1,164 @@
+package dsio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+// seqReader reads count entries holding their index, failing with err
+// instead of io.EOF if err is set. A count below zero reads forever
+type seqReader struct {
+	count  int
+	err    error
+	read   int64
+	closed int32
+}
+
+func (r *seqReader) Structure() *dataset.Structure {
+	return &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+}
+
+func (r *seqReader) ReadEntry() (Entry, error) {
+	i := int(atomic.LoadInt64(&r.read))
+	if r.count >= 0 && i >= r.count {
+		if r.err != nil {
+			return Entry{}, r.err
+		}
+		return Entry{}, io.EOF
+	}
+	atomic.AddInt64(&r.read, 1)
+	return Entry{Index: i, Value: i}, nil
+}
+
+func (r *seqReader) Close() error {
+	atomic.AddInt32(&r.closed, 1)
+	return nil
+}
+
+func TestPrefetchReader(t *testing.T) {
+	src := &seqReader{count: 100}
+	r := NewPrefetchReader(src, func(cfg *PrefetchConfig) { cfg.Size = 4 })
+	if r.Cap() != 4 {
+		t.Errorf("expected cap 4, got: %d", r.Cap())
+	}
+
+	for i := 0; i < 100; i++ {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ent.Index != i || ent.Value != i {
+			t.Fatalf("entry %d mismatch: %v", i, ent)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := r.ReadEntry(); err != io.EOF {
+			t.Errorf("expected io.EOF, got: %v", err)
+		}
+	}
+	if r.EntriesRead() != 100 {
+		t.Errorf("expected 100 entries read, got: %d", r.EntriesRead())
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if src.closed != 1 {
+		t.Errorf("expected underlying reader to be closed once, closed: %d", src.closed)
+	}
+}
+
+func TestPrefetchReaderReadsAhead(t *testing.T) {
+	src := &seqReader{count: -1}
+	r := NewPrefetchReader(src, func(cfg *PrefetchConfig) { cfg.Size = 8 })
+
+	// the buffer fills without the consumer reading
+	deadline := time.Now().Add(5 * time.Second)
+	for r.Buffered() < r.Cap() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected buffer to fill, buffered: %d", r.Buffered())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// one more entry is read & waits for space in the buffer
+	if read := atomic.LoadInt64(&src.read); read > int64(r.Cap())+1 {
+		t.Errorf("expected reading to stop when the buffer is full, read %d entries", read)
+	}
+
+	if _, err := r.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err == nil || err.Error() != "read from closed reader" {
+		t.Errorf("expected read after close to error, got: %v", err)
+	}
+	if src.closed != 1 {
+		t.Errorf("expected underlying reader to be closed")
+	}
+}
+
+func TestPrefetchReaderError(t *testing.T) {
+	errDisk := fmt.Errorf("disk on fire")
+	r := NewPrefetchReader(&seqReader{count: 3, err: errDisk})
+	defer r.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := r.ReadEntry(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		_, err := r.ReadEntry()
+		if !errors.Is(err, errDisk) {
+			t.Fatalf("expected wrapped reader error, got: %v", err)
+		}
+		if !strings.Contains(err.Error(), "error reading entry 3") {
+			t.Errorf("expected error to locate the entry, got: %s", err)
+		}
+	}
+}
+
+func TestPrefetchReaderBudget(t *testing.T) {
+	budget := NewMemoryBudget(EntrySize(Entry{Index: 1, Value: 1}) * 3)
+	src := &seqReader{count: -1}
+	r := NewPrefetchReader(src, func(cfg *PrefetchConfig) {
+		cfg.Size = 16
+		cfg.Budget = budget
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for r.Buffered() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 3 buffered entries, buffered: %d", r.Buffered())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if r.Buffered() != 3 {
+		t.Errorf("expected budget to bound buffered entries to 3, got: %d", r.Buffered())
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := r.ReadEntry(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if budget.Used() != 0 {
+		t.Errorf("expected close to release the budget, used: %d", budget.Used())
+	}
+}
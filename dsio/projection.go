@@ -0,0 +1,303 @@
+package dsio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// ProjectionConfig configures a ProjectionReader
+type ProjectionConfig struct {
+	// Unnest emits each element of the array found at a single pointer as an
+	// entry, instead of emitting the array. Unnested entries are re-indexed
+	Unnest bool
+	// Strict makes pointers that don't resolve in an entry errors. By default
+	// missing values are null, and unnesting a missing array emits no entries
+	Strict bool
+}
+
+// ProjectionReader wraps a reader, extracting the parts of each entry
+// referred to by JSON Pointers (RFC 6901). Pointers are relative to entry
+// values, so with a body of API responses "/results" gives each response's
+// results. Projecting one pointer gives the value at that pointer, projecting
+// several gives array entries with one column per pointer, which makes
+// pointers like "/2" or "/name" select columns of a tabular body.
+//
+// Pointer tokens can be the column titles of tabular schemas, the projected
+// structure is derived from the source schema, see ProjectStructure
+type ProjectionReader struct {
+	Reader   EntryReader
+	st       *dataset.Structure
+	pointers []string
+	tokens   [][]string
+	cfg      ProjectionConfig
+	// pending holds unnested values that haven't been read
+	pending []interface{}
+	read    int
+}
+
+var _ EntryReader = (*ProjectionReader)(nil)
+
+// NewProjectionReader creates a reader that projects the entries of r by
+// pointers
+func NewProjectionReader(r EntryReader, pointers []string, configs ...func(cfg *ProjectionConfig)) (*ProjectionReader, error) {
+	cfg := &ProjectionConfig{}
+	for _, config := range configs {
+		config(cfg)
+	}
+	st, tokens, err := project(r.Structure(), pointers, cfg)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	return &ProjectionReader{Reader: r, st: st, pointers: pointers, tokens: tokens, cfg: *cfg}, nil
+}
+
+// ProjectStructure gives the structure of a body projected by pointers. The
+// schema of projected values is found by walking the source schema, values
+// without a schema are described by the empty schema, which allows any
+// value. Body-specific fields like checksum aren't copied
+func ProjectStructure(st *dataset.Structure, pointers []string, configs ...func(cfg *ProjectionConfig)) (*dataset.Structure, error) {
+	cfg := &ProjectionConfig{}
+	for _, config := range configs {
+		config(cfg)
+	}
+	pst, _, err := project(st, pointers, cfg)
+	return pst, err
+}
+
+// project builds the projected structure & the tokens of each pointer, with
+// column titles replaced by indexes
+func project(st *dataset.Structure, pointers []string, cfg *ProjectionConfig) (*dataset.Structure, [][]string, error) {
+	if len(pointers) == 0 {
+		return nil, nil, fmt.Errorf("projection requires at least one pointer")
+	}
+	if cfg.Unnest && len(pointers) > 1 {
+		return nil, nil, fmt.Errorf("unnesting requires exactly one pointer, got: %d", len(pointers))
+	}
+
+	entrySchema, _ := st.Schema["items"].(map[string]interface{})
+	tokens := make([][]string, len(pointers))
+	schemas := make([]map[string]interface{}, len(pointers))
+	titles := make([]string, len(pointers))
+	for i, ptr := range pointers {
+		toks, err := parsePointer(ptr)
+		if err != nil {
+			return nil, nil, err
+		}
+		titles[i] = "value"
+		if len(toks) > 0 {
+			titles[i] = toks[len(toks)-1]
+		}
+		schemas[i], tokens[i] = pointerSchema(entrySchema, toks)
+	}
+
+	var items map[string]interface{}
+	switch {
+	case cfg.Unnest:
+		items, _ = schemas[0]["items"].(map[string]interface{})
+	case len(pointers) == 1:
+		items = schemas[0]
+	default:
+		cols := make([]interface{}, len(pointers))
+		for i, sch := range schemas {
+			col := make(map[string]interface{}, len(sch)+1)
+			for k, v := range sch {
+				col[k] = v
+			}
+			if _, ok := col["title"]; !ok {
+				col["title"] = titles[i]
+			}
+			cols[i] = col
+		}
+		items = map[string]interface{}{"type": "array", "items": cols}
+	}
+	if items == nil {
+		items = map[string]interface{}{}
+	}
+
+	pst := &dataset.Structure{
+		Qri:          st.Qri,
+		Format:       st.Format,
+		FormatConfig: st.FormatConfig,
+		Compression:  st.Compression,
+		Encoding:     st.Encoding,
+		Schema:       map[string]interface{}{"type": "array", "items": items},
+	}
+	return pst, tokens, nil
+}
+
+// parsePointer splits a JSON Pointer into unescaped reference tokens. The
+// empty pointer refers to the whole value
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with '/'", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, tok := range tokens {
+		for j := 0; j < len(tok); j++ {
+			if tok[j] == '~' && (j+1 == len(tok) || tok[j+1] != '0' && tok[j+1] != '1') {
+				return nil, fmt.Errorf("invalid JSON pointer %q: '~' must be followed by '0' or '1'", ptr)
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// pointerSchema walks a schema along tokens, giving the schema of the value
+// they refer to, or nil if it's unknown. Tokens that are column titles of
+// tabular schemas are replaced with the column's index in the returned tokens
+func pointerSchema(sch map[string]interface{}, tokens []string) (map[string]interface{}, []string) {
+	resolved := make([]string, len(tokens))
+	copy(resolved, tokens)
+	for i, tok := range tokens {
+		if sch == nil {
+			break
+		}
+		if props, ok := sch["properties"].(map[string]interface{}); ok {
+			sch, _ = props[tok].(map[string]interface{})
+			continue
+		}
+		switch items := sch["items"].(type) {
+		case []interface{}:
+			idx, ok := pointerIndex(tok)
+			if !ok {
+				idx = -1
+				for j, it := range items {
+					if col, _ := it.(map[string]interface{}); col != nil && col["title"] == tok {
+						idx = j
+						resolved[i] = strconv.Itoa(j)
+						break
+					}
+				}
+			}
+			sch = nil
+			if idx >= 0 && idx < len(items) {
+				sch, _ = items[idx].(map[string]interface{})
+			}
+		case map[string]interface{}:
+			sch = items
+		default:
+			sch = nil
+		}
+	}
+	return sch, resolved
+}
+
+// pointerIndex parses an array index token, which can't have leading zeros
+func pointerIndex(tok string) (int, bool) {
+	if tok == "" || len(tok) > 1 && tok[0] == '0' {
+		return 0, false
+	}
+	for _, c := range tok {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	idx, err := strconv.Atoi(tok)
+	return idx, err == nil
+}
+
+// resolvePointer gives the value tokens refer to in v
+func resolvePointer(v interface{}, tokens []string) (interface{}, bool) {
+	for _, tok := range tokens {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			val, ok := t[tok]
+			if !ok {
+				return nil, false
+			}
+			v = val
+		case []interface{}:
+			idx, ok := pointerIndex(tok)
+			if !ok || idx >= len(t) {
+				return nil, false
+			}
+			v = t[idx]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// Structure gives the projected structure
+func (r *ProjectionReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry returns the next projected entry
+func (r *ProjectionReader) ReadEntry() (Entry, error) {
+	if r.cfg.Unnest {
+		return r.readUnnested()
+	}
+	ent, err := r.Reader.ReadEntry()
+	if err != nil {
+		return ent, err
+	}
+	if len(r.tokens) == 1 {
+		if ent.Value, err = r.resolve(ent, 0); err != nil {
+			return Entry{}, err
+		}
+	} else {
+		row := make([]interface{}, len(r.tokens))
+		for i := range r.tokens {
+			if row[i], err = r.resolve(ent, i); err != nil {
+				return Entry{}, err
+			}
+		}
+		ent.Value = row
+	}
+	r.read++
+	return ent, nil
+}
+
+// readUnnested returns the next element of the arrays found at the pointer
+func (r *ProjectionReader) readUnnested() (Entry, error) {
+	for len(r.pending) == 0 {
+		ent, err := r.Reader.ReadEntry()
+		if err != nil {
+			return ent, err
+		}
+		val, err := r.resolve(ent, 0)
+		if err != nil {
+			return Entry{}, err
+		}
+		switch t := val.(type) {
+		case nil:
+		case []interface{}:
+			r.pending = t
+		default:
+			return Entry{}, fmt.Errorf("entry %d: can't unnest %s, expected an array, got: %T", ent.Index, r.pointers[0], val)
+		}
+	}
+	ent := Entry{Index: r.read, Value: r.pending[0]}
+	r.pending = r.pending[1:]
+	r.read++
+	return ent, nil
+}
+
+// resolve gives the value of pointer i in an entry
+func (r *ProjectionReader) resolve(ent Entry, i int) (interface{}, error) {
+	val, ok := resolvePointer(ent.Value, r.tokens[i])
+	if !ok && r.cfg.Strict {
+		return nil, fmt.Errorf("entry %d: pointer %s doesn't resolve", ent.Index, r.pointers[i])
+	}
+	return val, nil
+}
+
+// EntriesRead gives the number of projected entries returned by the reader
+func (r *ProjectionReader) EntriesRead() int {
+	return r.read
+}
+
+// Close closes the wrapped reader
+func (r *ProjectionReader) Close() error {
+	return r.Reader.Close()
+}
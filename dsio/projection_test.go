@@ -0,0 +1,232 @@
+package dsio
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+var projectionResponses = &dataset.Structure{
+	Format: "json",
+	Schema: map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"page": map[string]interface{}{"type": "integer"},
+				"results": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"name": map[string]interface{}{"type": "string"},
+						},
+					},
+				},
+				"a/b": map[string]interface{}{"type": "string"},
+			},
+		},
+	},
+}
+
+const projectionResponsesBody = `[
+	{"page": 1, "results": [{"name": "a"}, {"name": "b"}], "a/b": "slash"},
+	{"page": 2, "results": []},
+	{"page": 3, "results": [{"name": "c"}]},
+	{"page": 4}
+]`
+
+func TestProjectionReader(t *testing.T) {
+	cases := []struct {
+		description string
+		pointers    []string
+		unnest      bool
+		schema      string
+		expect      []Entry
+	}{
+		{"single pointer", []string{"/page"}, false,
+			`{"items":{"type":"integer"},"type":"array"}`,
+			[]Entry{{Index: 0, Value: 1}, {Index: 1, Value: 2}, {Index: 2, Value: 3}, {Index: 3, Value: 4}},
+		},
+		{"escaped pointer", []string{"/a~1b"}, false,
+			`{"items":{"type":"string"},"type":"array"}`,
+			[]Entry{{Index: 0, Value: "slash"}, {Index: 1}, {Index: 2}, {Index: 3}},
+		},
+		{"unnest", []string{"/results"}, true,
+			`{"items":{"properties":{"name":{"type":"string"}},"type":"object"},"type":"array"}`,
+			[]Entry{
+				{Index: 0, Value: map[string]interface{}{"name": "a"}},
+				{Index: 1, Value: map[string]interface{}{"name": "b"}},
+				{Index: 2, Value: map[string]interface{}{"name": "c"}},
+			},
+		},
+		{"columns", []string{"/page", "/results/0/name"}, false,
+			`{"items":{"items":[{"title":"page","type":"integer"},{"title":"name","type":"string"}],"type":"array"},"type":"array"}`,
+			[]Entry{
+				{Index: 0, Value: []interface{}{1, "a"}},
+				{Index: 1, Value: []interface{}{2, nil}},
+				{Index: 2, Value: []interface{}{3, "c"}},
+				{Index: 3, Value: []interface{}{4, nil}},
+			},
+		},
+		{"unknown schema", []string{"/missing", ""}, false,
+			`{"items":{"items":[{"title":"missing"},{"properties":{"a/b":{"type":"string"},"page":{"type":"integer"},"results":{"items":{"properties":{"name":{"type":"string"}},"type":"object"},"type":"array"}},"title":"value","type":"object"}],"type":"array"},"type":"array"}`,
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		r, err := NewJSONReader(projectionResponses, strings.NewReader(projectionResponsesBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pr, err := NewProjectionReader(r, c.pointers, func(cfg *ProjectionConfig) {
+			cfg.Unnest = c.unnest
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", c.description, err)
+		}
+		data, _ := json.Marshal(pr.Structure().Schema)
+		if string(data) != c.schema {
+			t.Errorf("%s: schema mismatch.\nexpected: %s\ngot:      %s", c.description, c.schema, data)
+		}
+		if c.expect == nil {
+			continue
+		}
+		var got []Entry
+		for {
+			ent, err := pr.ReadEntry()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s: unexpected error: %s", c.description, err)
+			}
+			got = append(got, ent)
+		}
+		if !reflect.DeepEqual(c.expect, got) {
+			t.Errorf("%s: entries mismatch.\nexpected: %v\ngot:      %v", c.description, c.expect, got)
+		}
+		if pr.EntriesRead() != len(c.expect) {
+			t.Errorf("%s: expected %d entries read, got: %d", c.description, len(c.expect), pr.EntriesRead())
+		}
+		pr.Close()
+	}
+}
+
+func TestProjectionReaderColumnTitles(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+					map[string]interface{}{"title": "avg_age", "type": "number"},
+				},
+			},
+		},
+	}
+	r := NewCSVReader(st, strings.NewReader("toronto,40000000,55.5\nnew york,8500000,44.4\n"))
+	pr, err := NewProjectionReader(r, []string{"/avg_age", "/0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles, types, err := terribleHackToGetHeaderRowAndTypes(pr.Structure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(titles, []string{"avg_age", "city"}) || !reflect.DeepEqual(types, []string{"number", "string"}) {
+		t.Errorf("unexpected projected columns: %v %v", titles, types)
+	}
+	ent, err := pr.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ent.Value, []interface{}{55.5, "toronto"}) {
+		t.Errorf("unexpected projected entry: %v", ent.Value)
+	}
+}
+
+func TestProjectionReaderErrors(t *testing.T) {
+	cases := []struct {
+		description string
+		pointers    []string
+		configs     []func(cfg *ProjectionConfig)
+		err         string
+	}{
+		{"no pointers", nil, nil, "projection requires at least one pointer"},
+		{"relative pointer", []string{"page"}, nil, `invalid JSON pointer "page": must be empty or start with '/'`},
+		{"bad escape", []string{"/a~2"}, nil, `invalid JSON pointer "/a~2": '~' must be followed by '0' or '1'`},
+		{"unnest many", []string{"/a", "/b"}, []func(cfg *ProjectionConfig){func(cfg *ProjectionConfig) { cfg.Unnest = true }}, "unnesting requires exactly one pointer, got: 2"},
+	}
+	for _, c := range cases {
+		r, err := NewJSONReader(projectionResponses, strings.NewReader(projectionResponsesBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewProjectionReader(r, c.pointers, c.configs...); err == nil || err.Error() != c.err {
+			t.Errorf("%s: expected error: %q, got: %v", c.description, c.err, err)
+		}
+	}
+
+	readErr := func(pointer string, config func(cfg *ProjectionConfig)) error {
+		r, err := NewJSONReader(projectionResponses, strings.NewReader(projectionResponsesBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pr, err := NewProjectionReader(r, []string{pointer}, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for {
+			if _, err := pr.ReadEntry(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := readErr("/results", func(cfg *ProjectionConfig) { cfg.Strict = true }); err == nil || err.Error() != "entry 3: pointer /results doesn't resolve" {
+		t.Errorf("expected strict error, got: %v", err)
+	}
+	if err := readErr("/page", func(cfg *ProjectionConfig) { cfg.Unnest = true }); err == nil || err.Error() != "entry 0: can't unnest /page, expected an array, got: int" {
+		t.Errorf("expected unnest error, got: %v", err)
+	}
+}
+
+func TestResolvePointer(t *testing.T) {
+	v := map[string]interface{}{
+		"a":   []interface{}{"x", map[string]interface{}{"~b": true}},
+		"":    "empty key",
+		"c/d": 1,
+	}
+	cases := []struct {
+		pointer string
+		expect  interface{}
+		ok      bool
+	}{
+		{"", v, true},
+		{"/a/0", "x", true},
+		{"/a/1/~0b", true, true},
+		{"/c~1d", 1, true},
+		{"/", "empty key", true},
+		{"/a/01", nil, false},
+		{"/a/-", nil, false},
+		{"/a/2", nil, false},
+		{"/a/0/x", nil, false},
+	}
+	for _, c := range cases {
+		tokens, err := parsePointer(c.pointer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := resolvePointer(v, tokens)
+		if ok != c.ok || !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%q: expected %v %t, got: %v %t", c.pointer, c.expect, c.ok, got, ok)
+		}
+	}
+}
@@ -0,0 +1,631 @@
+package dsio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/qri-io/dataset"
+)
+
+// ProtobufReader implements the EntryReader interface for streams of
+// protocol buffer messages, each prefixed with it's length as a varint, the
+// framing of writeDelimitedTo & parseDelimitedFrom. Messages are decoded with
+// the message type named by the structure's ProtobufOptions.
+//
+// Messages are read as objects keyed by field name, or as arrays of the
+// fields named by column titles when the structure's schema is tabular.
+// Integers are read as int, floats & doubles as float64, strings & bytes as
+// string, enums as value names, repeated fields as arrays & map fields as
+// objects. Missing proto3 fields read as their default value
+type ProtobufReader struct {
+	st          *dataset.Structure
+	src         *TrackedReader
+	reader      *bufio.Reader
+	msg         *protoMsg
+	titles      []string
+	entriesRead int
+}
+
+var _ EntryReader = (*ProtobufReader)(nil)
+
+// NewProtobufReader creates a reader from a structure and read source
+func NewProtobufReader(st *dataset.Structure, r io.Reader) (*ProtobufReader, error) {
+	msg, err := protobufMessage(st)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	if st.Schema != nil {
+		if tlt, err := GetTopLevelType(st); err != nil {
+			return nil, err
+		} else if tlt != "array" {
+			return nil, newKindError(ErrBadSchema, "protobuf requires a top-level array schema")
+		}
+	}
+	src := NewTrackedReader(r)
+	pr := &ProtobufReader{
+		st:     st,
+		src:    src,
+		reader: bufio.NewReader(src),
+		msg:    msg,
+	}
+	if titles, _, err := terribleHackToGetHeaderRowAndTypes(st); err == nil {
+		pr.titles = titles
+	}
+	return pr, nil
+}
+
+// Structure gives this reader's structure
+func (r *ProtobufReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry reads the next message
+func (r *ProtobufReader) ReadEntry() (Entry, error) {
+	ent, err := r.readEntry()
+	return ent, parseError("protobuf", r.entriesRead, err)
+}
+
+func (r *ProtobufReader) readEntry() (Entry, error) {
+	size, err := binary.ReadUvarint(r.reader)
+	if err == io.EOF {
+		return Entry{}, io.EOF
+	} else if err != nil {
+		return Entry{}, fmt.Errorf("error reading message length: %w", errProtobufCorrupt)
+	}
+	if size > protoMaxMessageSize {
+		return Entry{}, fmt.Errorf("message of %d bytes exceeds the maximum size: %w", size, errProtobufCorrupt)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r.reader, data); err != nil {
+		return Entry{}, fmt.Errorf("error reading message: %w", errProtobufCorrupt)
+	}
+
+	obj, err := protoDecodeMessage(r.msg, &protoDecoder{buf: data}, false, 0)
+	if err != nil {
+		return Entry{}, err
+	}
+	ent := Entry{Index: r.entriesRead, Value: obj}
+	if r.titles != nil {
+		row := make([]interface{}, len(r.titles))
+		for i, title := range r.titles {
+			row[i] = obj[title]
+		}
+		ent.Value = row
+	}
+	r.entriesRead++
+	return ent, nil
+}
+
+// protoDecodeMessage decodes the fields of a message. groups are read up to
+// their end group tag
+func protoDecodeMessage(m *protoMsg, d *protoDecoder, group bool, depth int) (map[string]interface{}, error) {
+	if depth > protoMaxDepth {
+		return nil, fmt.Errorf("protobuf messages nested too deeply: %w", errProtobufCorrupt)
+	}
+	obj := map[string]interface{}{}
+	for {
+		if d.done() {
+			if group {
+				return nil, errProtobufCorrupt
+			}
+			break
+		}
+		num, wire, err := d.tag()
+		if err != nil {
+			return nil, err
+		}
+		if wire == protoEndGroup {
+			if !group {
+				return nil, errProtobufCorrupt
+			}
+			break
+		}
+		f := m.byNumber[num]
+		if f == nil {
+			if err := d.skip(wire, depth); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if f.repeated && f.packable() && wire == protoBytes {
+			// packed values, accepted whether or not the field is declared packed
+			b, err := d.bytes()
+			if err != nil {
+				return nil, err
+			}
+			arr, _ := obj[f.name].([]interface{})
+			pd := &protoDecoder{buf: b}
+			for !pd.done() {
+				v, err := protoDecodeValue(f, pd, depth)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+			obj[f.name] = arr
+			continue
+		}
+		if wire != f.wireType() {
+			return nil, fmt.Errorf("field %s has wire type %d, expected %d: %w", f.name, wire, f.wireType(), errProtobufCorrupt)
+		}
+		v, err := protoDecodeValue(f, d, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case f.repeated && f.message != nil && f.message.mapEntry:
+			entry, _ := v.(map[string]interface{})
+			entries, _ := obj[f.name].(map[string]interface{})
+			if entries == nil {
+				entries = map[string]interface{}{}
+			}
+			entries[fmt.Sprintf("%v", entry["key"])] = entry["value"]
+			obj[f.name] = entries
+		case f.repeated:
+			arr, _ := obj[f.name].([]interface{})
+			obj[f.name] = append(arr, v)
+		default:
+			obj[f.name] = v
+		}
+	}
+
+	for _, f := range m.fields {
+		if _, ok := obj[f.name]; ok {
+			continue
+		}
+		switch {
+		case f.repeated && f.message != nil && f.message.mapEntry:
+			obj[f.name] = map[string]interface{}{}
+		case f.repeated:
+			obj[f.name] = []interface{}{}
+		case !f.presence || m.mapEntry:
+			obj[f.name] = f.defaultValue()
+		}
+	}
+	return obj, nil
+}
+
+// protoDecodeValue decodes a single value of a field
+func protoDecodeValue(f *protoField, d *protoDecoder, depth int) (interface{}, error) {
+	switch f.typ {
+	case protoDouble:
+		v, err := d.fixed64()
+		return math.Float64frombits(v), err
+	case protoFloat:
+		v, err := d.fixed32()
+		return float64(math.Float32frombits(v)), err
+	case protoFixed64T:
+		v, err := d.fixed64()
+		return protoUintValue(v), err
+	case protoSfixed64:
+		v, err := d.fixed64()
+		return int(int64(v)), err
+	case protoFixed32T:
+		v, err := d.fixed32()
+		return int(v), err
+	case protoSfixed32:
+		v, err := d.fixed32()
+		return int(int32(v)), err
+	case protoString, protoBytesT:
+		b, err := d.bytes()
+		return string(b), err
+	case protoMessage:
+		b, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		return protoDecodeMessage(f.message, &protoDecoder{buf: b}, false, depth+1)
+	case protoGroup:
+		return protoDecodeMessage(f.message, d, true, depth+1)
+	}
+
+	v, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	switch f.typ {
+	case protoInt64:
+		return int(int64(v)), nil
+	case protoInt32:
+		return int(int32(v)), nil
+	case protoUint64:
+		return protoUintValue(v), nil
+	case protoUint32:
+		return int(uint32(v)), nil
+	case protoSint32:
+		return int(int32(uint32(v)>>1) ^ -int32(v&1)), nil
+	case protoSint64:
+		return int(int64(v>>1) ^ -int64(v&1)), nil
+	case protoBool:
+		return v != 0, nil
+	case protoEnum:
+		if name, ok := f.enum.names[int32(v)]; ok {
+			return name, nil
+		}
+		// unknown enum values are kept as numbers
+		return int(int32(v)), nil
+	}
+	return nil, fmt.Errorf("unsupported protobuf field type: %d", f.typ)
+}
+
+// protoUintValue reads unsigned 64 bit values as int when they fit
+func protoUintValue(v uint64) interface{} {
+	if v > math.MaxInt64 {
+		return v
+	}
+	return int(v)
+}
+
+// defaultValue gives the value of a missing field without presence
+func (f *protoField) defaultValue() interface{} {
+	switch f.typ {
+	case protoDouble, protoFloat:
+		return float64(0)
+	case protoBool:
+		return false
+	case protoString, protoBytesT:
+		return ""
+	case protoEnum:
+		if name, ok := f.enum.names[0]; ok {
+			return name
+		}
+		return 0
+	case protoMessage, protoGroup:
+		return nil
+	}
+	return 0
+}
+
+// Close finalizes the reader, closing the source if it's an io.Closer. wrap
+// the source with KeepOpen to leave it open
+func (r *ProtobufReader) Close() error {
+	return r.src.Close()
+}
+
+// EntriesRead gives the number of entries read
+func (r *ProtobufReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes parsed
+func (r *ProtobufReader) BytesProcessed() int64 {
+	return int64(r.src.BytesRead() - r.reader.Buffered())
+}
+
+// ProtobufWriter implements the EntryWriter interface for streams of
+// length-prefixed protocol buffer messages, encoding entries as the message
+// type named by the structure's ProtobufOptions. Object entries are encoded
+// by field name, array entries of tabular schemas by column title. Null
+// values aren't written
+type ProtobufWriter struct {
+	st             *dataset.Structure
+	wr             *countingWriter
+	msg            *protoMsg
+	columns        []*protoField
+	entriesWritten int
+}
+
+var _ EntryWriter = (*ProtobufWriter)(nil)
+
+// NewProtobufWriter creates a writer from a structure and write destination
+func NewProtobufWriter(st *dataset.Structure, w io.Writer) (*ProtobufWriter, error) {
+	msg, err := protobufMessage(st)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	pw := &ProtobufWriter{st: st, wr: &countingWriter{w: w}, msg: msg}
+	if titles, _, err := terribleHackToGetHeaderRowAndTypes(st); err == nil {
+		pw.columns = make([]*protoField, len(titles))
+		for i, title := range titles {
+			if pw.columns[i] = msg.byName[title]; pw.columns[i] == nil {
+				return nil, fmt.Errorf("column %s isn't a field of protobuf message %s", title, msg.name)
+			}
+		}
+	}
+	return pw, nil
+}
+
+// Structure gives this writer's structure
+func (w *ProtobufWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// WriteEntry encodes & writes one message
+func (w *ProtobufWriter) WriteEntry(ent Entry) error {
+	var obj map[string]interface{}
+	switch v := ent.Value.(type) {
+	case map[string]interface{}:
+		obj = v
+	case []interface{}:
+		if w.columns == nil {
+			return fmt.Errorf("expected object value to write protobuf message. got: %T", ent.Value)
+		}
+		if len(v) > len(w.columns) {
+			return fmt.Errorf("entry %d has %d values, schema has %d columns", ent.Index, len(v), len(w.columns))
+		}
+		obj = make(map[string]interface{}, len(v))
+		for i, val := range v {
+			obj[w.columns[i].name] = val
+		}
+	default:
+		return fmt.Errorf("expected object value to write protobuf message. got: %T", ent.Value)
+	}
+
+	data, err := protoEncodeMessage(nil, w.msg, obj, 0)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("entry %d: %w", ent.Index, err)
+	}
+	buf := protoAppendBytes(nil, data)
+	if _, err := w.wr.Write(buf); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing protobuf message: %w", err)
+	}
+	w.entriesWritten++
+	return nil
+}
+
+// protoEncodeMessage appends the fields of a message in field number order.
+// keys that aren't fields of the message are an error
+func protoEncodeMessage(buf []byte, m *protoMsg, obj map[string]interface{}, depth int) ([]byte, error) {
+	if depth > protoMaxDepth {
+		return nil, fmt.Errorf("protobuf messages nested too deeply")
+	}
+	for key := range obj {
+		if m.byName[key] == nil {
+			return nil, fmt.Errorf("%s isn't a field of message %s", key, m.name)
+		}
+	}
+	var err error
+	for _, f := range m.fields {
+		if buf, err = protoEncodeField(buf, f, obj[f.name], depth); err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.name, err)
+		}
+	}
+	return buf, nil
+}
+
+// protoEncodeField appends the tagged values of a field
+func protoEncodeField(buf []byte, f *protoField, v interface{}, depth int) ([]byte, error) {
+	if v == nil {
+		return buf, nil
+	}
+	if !f.repeated {
+		return protoEncodeTagged(buf, f, v, depth)
+	}
+
+	if f.message != nil && f.message.mapEntry {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object value, got %T", v)
+		}
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		keyField := f.message.byNumber[1]
+		for _, key := range keys {
+			entry := map[string]interface{}{"value": obj[key]}
+			if entry["key"], ok = protoMapKey(keyField, key); !ok {
+				return nil, fmt.Errorf("invalid map key %q", key)
+			}
+			data, err := protoEncodeMessage(nil, f.message, entry, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			buf = protoAppendTag(buf, f.number, protoBytes)
+			buf = protoAppendBytes(buf, data)
+		}
+		return buf, nil
+	}
+
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected array value, got %T", v)
+	}
+	if f.packed {
+		if len(arr) == 0 {
+			return buf, nil
+		}
+		var (
+			data []byte
+			err  error
+		)
+		for _, item := range arr {
+			if data, err = protoEncodeValue(data, f, item, depth); err != nil {
+				return nil, err
+			}
+		}
+		buf = protoAppendTag(buf, f.number, protoBytes)
+		return protoAppendBytes(buf, data), nil
+	}
+	var err error
+	for _, item := range arr {
+		if buf, err = protoEncodeTagged(buf, f, item, depth); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// protoMapKey converts an object key to the type of a map key field
+func protoMapKey(f *protoField, key string) (interface{}, bool) {
+	if f == nil {
+		return nil, false
+	}
+	switch f.typ {
+	case protoString:
+		return key, true
+	case protoBool:
+		b, err := strconv.ParseBool(key)
+		return b, err == nil
+	case protoUint64, protoFixed64T:
+		n, err := strconv.ParseUint(key, 10, 64)
+		return n, err == nil
+	default:
+		n, err := strconv.ParseInt(key, 10, 64)
+		return n, err == nil
+	}
+}
+
+// protoEncodeTagged appends a tag & single value
+func protoEncodeTagged(buf []byte, f *protoField, v interface{}, depth int) ([]byte, error) {
+	buf = protoAppendTag(buf, f.number, f.wireType())
+	buf, err := protoEncodeValue(buf, f, v, depth)
+	if err != nil {
+		return nil, err
+	}
+	if f.typ == protoGroup {
+		buf = protoAppendTag(buf, f.number, protoEndGroup)
+	}
+	return buf, nil
+}
+
+// protoEncodeValue appends a single value without a tag
+func protoEncodeValue(buf []byte, f *protoField, v interface{}, depth int) ([]byte, error) {
+	switch f.typ {
+	case protoString, protoBytesT:
+		switch s := v.(type) {
+		case string:
+			return protoAppendBytes(buf, []byte(s)), nil
+		case []byte:
+			return protoAppendBytes(buf, s), nil
+		}
+		return nil, fmt.Errorf("expected string value, got %T", v)
+	case protoMessage, protoGroup:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object value, got %T", v)
+		}
+		if f.typ == protoGroup {
+			return protoEncodeMessage(buf, f.message, obj, depth+1)
+		}
+		data, err := protoEncodeMessage(nil, f.message, obj, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return protoAppendBytes(buf, data), nil
+	case protoBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected boolean value, got %T", v)
+		}
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case protoDouble, protoFloat:
+		var n float64
+		switch t := v.(type) {
+		case float64:
+			n = t
+		case int:
+			n = float64(t)
+		case int64:
+			n = float64(t)
+		default:
+			return nil, fmt.Errorf("expected number value, got %T", v)
+		}
+		if f.typ == protoFloat {
+			return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(n))), nil
+		}
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(n)), nil
+	case protoEnum:
+		if s, ok := v.(string); ok {
+			n, ok := f.enum.numbers[s]
+			if !ok {
+				return nil, fmt.Errorf("%q isn't a value of enum %s", s, f.enum.name)
+			}
+			return binary.AppendUvarint(buf, uint64(int64(n))), nil
+		}
+	}
+
+	// integer types
+	var (
+		n        int64
+		u        uint64
+		unsigned bool
+	)
+	switch t := v.(type) {
+	case int:
+		n = int64(t)
+	case int64:
+		n = t
+	case uint64:
+		u, unsigned = t, true
+		if t <= math.MaxInt64 {
+			n, unsigned = int64(t), false
+		}
+	case float64:
+		if t != math.Trunc(t) || math.Abs(t) >= 1<<63 {
+			return nil, fmt.Errorf("expected integer value, got %v", t)
+		}
+		n = int64(t)
+	default:
+		return nil, fmt.Errorf("expected integer value, got %T", v)
+	}
+
+	switch f.typ {
+	case protoInt32, protoSint32, protoSfixed32, protoEnum:
+		if unsigned || n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, fmt.Errorf("value %v overflows int32", v)
+		}
+	case protoUint32, protoFixed32T:
+		if unsigned || n < 0 || n > math.MaxUint32 {
+			return nil, fmt.Errorf("value %v overflows uint32", v)
+		}
+	case protoUint64, protoFixed64T:
+		if !unsigned {
+			if n < 0 {
+				return nil, fmt.Errorf("value %v overflows uint64", v)
+			}
+			u = uint64(n)
+		}
+	default:
+		if unsigned {
+			return nil, fmt.Errorf("value %v overflows int64", v)
+		}
+	}
+
+	switch f.typ {
+	case protoSint32, protoSint64:
+		return binary.AppendUvarint(buf, uint64(n<<1^n>>63)), nil
+	case protoFixed32T, protoSfixed32:
+		return binary.LittleEndian.AppendUint32(buf, uint32(n)), nil
+	case protoSfixed64:
+		return binary.LittleEndian.AppendUint64(buf, uint64(n)), nil
+	case protoFixed64T:
+		return binary.LittleEndian.AppendUint64(buf, u), nil
+	case protoUint64:
+		return binary.AppendUvarint(buf, u), nil
+	}
+	// int32, int64, uint32 & enum numbers. negative values are sign-extended
+	// to 64 bits
+	return binary.AppendUvarint(buf, uint64(n)), nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *ProtobufWriter) EntriesWritten() int {
+	return w.entriesWritten
+}
+
+// BytesProcessed gives the number of bytes written
+func (w *ProtobufWriter) BytesProcessed() int64 {
+	return w.wr.n
+}
+
+// Close finalizes the writer. The destination is closed if it's an
+// io.Closer, wrap it with KeepWriterOpen to leave it open
+func (w *ProtobufWriter) Close() error {
+	return w.wr.Close()
+}
@@ -0,0 +1,660 @@
+package dsio
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// protobuf messages are encoded as a sequence of tagged fields. Message types
+// are read from a compiled FileDescriptorSet, itself a protobuf message. see
+// https://protobuf.dev/programming-guides/encoding/
+
+// protobuf wire types
+const (
+	protoVarint     = 0
+	protoFixed64    = 1
+	protoBytes      = 2
+	protoStartGroup = 3
+	protoEndGroup   = 4
+	protoFixed32    = 5
+)
+
+// protobuf field types, numbered as in FieldDescriptorProto.Type
+const (
+	protoDouble   = 1
+	protoFloat    = 2
+	protoInt64    = 3
+	protoUint64   = 4
+	protoInt32    = 5
+	protoFixed64T = 6
+	protoFixed32T = 7
+	protoBool     = 8
+	protoString   = 9
+	protoGroup    = 10
+	protoMessage  = 11
+	protoBytesT   = 12
+	protoUint32   = 13
+	protoEnum     = 14
+	protoSfixed32 = 15
+	protoSfixed64 = 16
+	protoSint32   = 17
+	protoSint64   = 18
+)
+
+const (
+	// protoMaxDepth caps the nesting of decoded messages
+	protoMaxDepth = 100
+	// protoMaxMessageSize caps the size of a single message
+	protoMaxMessageSize = 64 << 20
+)
+
+var errProtobufCorrupt = fmt.Errorf("corrupt protobuf data")
+
+// protoDecoder reads wire-format values from a buffer
+type protoDecoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *protoDecoder) done() bool {
+	return d.pos >= len(d.buf)
+}
+
+func (d *protoDecoder) varint() (uint64, error) {
+	v, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		return 0, errProtobufCorrupt
+	}
+	d.pos += n
+	return v, nil
+}
+
+func (d *protoDecoder) fixed32() (uint32, error) {
+	if len(d.buf)-d.pos < 4 {
+		return 0, errProtobufCorrupt
+	}
+	v := binary.LittleEndian.Uint32(d.buf[d.pos:])
+	d.pos += 4
+	return v, nil
+}
+
+func (d *protoDecoder) fixed64() (uint64, error) {
+	if len(d.buf)-d.pos < 8 {
+		return 0, errProtobufCorrupt
+	}
+	v := binary.LittleEndian.Uint64(d.buf[d.pos:])
+	d.pos += 8
+	return v, nil
+}
+
+// bytes reads a length-delimited value
+func (d *protoDecoder) bytes() ([]byte, error) {
+	n, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(d.buf)-d.pos) {
+		return nil, errProtobufCorrupt
+	}
+	b := d.buf[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// tag reads a field number & wire type
+func (d *protoDecoder) tag() (int, int, error) {
+	v, err := d.varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	num := v >> 3
+	if num == 0 || num > 1<<29-1 {
+		return 0, 0, errProtobufCorrupt
+	}
+	return int(num), int(v & 7), nil
+}
+
+// skip discards a value of an unknown field
+func (d *protoDecoder) skip(wire int, depth int) error {
+	if depth > protoMaxDepth {
+		return errProtobufCorrupt
+	}
+	var err error
+	switch wire {
+	case protoVarint:
+		_, err = d.varint()
+	case protoFixed64:
+		_, err = d.fixed64()
+	case protoBytes:
+		_, err = d.bytes()
+	case protoFixed32:
+		_, err = d.fixed32()
+	case protoStartGroup:
+		for {
+			_, w, err := d.tag()
+			if err != nil {
+				return err
+			}
+			if w == protoEndGroup {
+				return nil
+			}
+			if err := d.skip(w, depth+1); err != nil {
+				return err
+			}
+		}
+	default:
+		err = errProtobufCorrupt
+	}
+	return err
+}
+
+// protoAppendTag appends a field number & wire type
+func protoAppendTag(buf []byte, num, wire int) []byte {
+	return binary.AppendUvarint(buf, uint64(num)<<3|uint64(wire))
+}
+
+// protoAppendBytes appends a length-delimited value
+func protoAppendBytes(buf, b []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+// protoMsg is a message type read from a descriptor
+type protoMsg struct {
+	// name is the fully-qualified name, without a leading dot
+	name string
+	// fields are sorted by field number
+	fields   []*protoField
+	byNumber map[int]*protoField
+	byName   map[string]*protoField
+	// mapEntry marks the generated key/value messages of map fields
+	mapEntry bool
+}
+
+// protoField is a field of a message type
+type protoField struct {
+	name     string
+	number   int
+	typ      int
+	typeName string
+	repeated bool
+	// packed repeated scalars are written as one length-delimited value
+	packed bool
+	// presence is set for fields that record being unset. fields without
+	// presence read as their default value when missing
+	presence bool
+	message  *protoMsg
+	enum     *protoEnumType
+}
+
+// protoEnumType is an enum type read from a descriptor
+type protoEnumType struct {
+	name    string
+	values  []string
+	names   map[int32]string
+	numbers map[string]int32
+}
+
+// protoDescriptorSet parses a FileDescriptorSet into message types by name
+func protoDescriptorSet(data []byte) (map[string]*protoMsg, []string, error) {
+	msgs := map[string]*protoMsg{}
+	enums := map[string]*protoEnumType{}
+	// topLevel names messages declared at the top of a file, in order
+	var topLevel []string
+
+	d := &protoDecoder{buf: data}
+	for !d.done() {
+		num, wire, err := d.tag()
+		if err != nil {
+			return nil, nil, err
+		}
+		if num != 1 || wire != protoBytes {
+			if err := d.skip(wire, 0); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		file, err := d.bytes()
+		if err != nil {
+			return nil, nil, err
+		}
+		names, err := protoFileDescriptor(file, msgs, enums)
+		if err != nil {
+			return nil, nil, err
+		}
+		topLevel = append(topLevel, names...)
+	}
+
+	// resolve field types once every file is read, types can reference
+	// messages declared later or in other files
+	for _, m := range msgs {
+		for _, f := range m.fields {
+			name := strings.TrimPrefix(f.typeName, ".")
+			switch f.typ {
+			case protoMessage, protoGroup:
+				if f.message = msgs[name]; f.message == nil {
+					return nil, nil, fmt.Errorf("invalid protobuf descriptor: field %s.%s has unknown type %s", m.name, f.name, f.typeName)
+				}
+			case protoEnum:
+				if f.enum = enums[name]; f.enum == nil {
+					return nil, nil, fmt.Errorf("invalid protobuf descriptor: field %s.%s has unknown enum %s", m.name, f.name, f.typeName)
+				}
+			}
+		}
+	}
+	return msgs, topLevel, nil
+}
+
+// protoFileDescriptor reads the message & enum types of a
+// FileDescriptorProto, giving the names of top-level messages
+func protoFileDescriptor(data []byte, msgs map[string]*protoMsg, enums map[string]*protoEnumType) ([]string, error) {
+	var (
+		pkg               string
+		proto3            bool
+		msgData, enumData [][]byte
+	)
+	d := &protoDecoder{buf: data}
+	for !d.done() {
+		num, wire, err := d.tag()
+		if err != nil {
+			return nil, err
+		}
+		if wire != protoBytes {
+			if err := d.skip(wire, 0); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		b, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		switch num {
+		case 2:
+			pkg = string(b)
+		case 4:
+			msgData = append(msgData, b)
+		case 5:
+			enumData = append(enumData, b)
+		case 12:
+			proto3 = string(b) == "proto3"
+		}
+	}
+
+	prefix := ""
+	if pkg != "" {
+		prefix = pkg + "."
+	}
+	for _, b := range enumData {
+		if err := protoEnumDescriptor(b, prefix, enums); err != nil {
+			return nil, err
+		}
+	}
+	names := make([]string, 0, len(msgData))
+	for _, b := range msgData {
+		m, err := protoMessageDescriptor(b, prefix, proto3, msgs, enums)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, m.name)
+	}
+	return names, nil
+}
+
+// protoMessageDescriptor reads a DescriptorProto & it's nested types
+func protoMessageDescriptor(data []byte, prefix string, proto3 bool, msgs map[string]*protoMsg, enums map[string]*protoEnumType) (*protoMsg, error) {
+	m := &protoMsg{byNumber: map[int]*protoField{}, byName: map[string]*protoField{}}
+	var nested, nestedEnums [][]byte
+
+	d := &protoDecoder{buf: data}
+	for !d.done() {
+		num, wire, err := d.tag()
+		if err != nil {
+			return nil, err
+		}
+		if wire != protoBytes {
+			if err := d.skip(wire, 0); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		b, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		switch num {
+		case 1:
+			m.name = prefix + string(b)
+		case 2:
+			f, err := protoFieldDescriptor(b, proto3)
+			if err != nil {
+				return nil, err
+			}
+			m.fields = append(m.fields, f)
+		case 3:
+			nested = append(nested, b)
+		case 4:
+			nestedEnums = append(nestedEnums, b)
+		case 7:
+			// MessageOptions.map_entry
+			opts := &protoDecoder{buf: b}
+			for !opts.done() {
+				onum, owire, err := opts.tag()
+				if err != nil {
+					return nil, err
+				}
+				if onum == 7 && owire == protoVarint {
+					v, err := opts.varint()
+					if err != nil {
+						return nil, err
+					}
+					m.mapEntry = v != 0
+				} else if err := opts.skip(owire, 0); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if m.name == prefix {
+		return nil, fmt.Errorf("invalid protobuf descriptor: message without a name")
+	}
+
+	for _, b := range nestedEnums {
+		if err := protoEnumDescriptor(b, m.name+".", enums); err != nil {
+			return nil, err
+		}
+	}
+	for _, b := range nested {
+		if _, err := protoMessageDescriptor(b, m.name+".", proto3, msgs, enums); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(m.fields, func(i, j int) bool { return m.fields[i].number < m.fields[j].number })
+	for _, f := range m.fields {
+		if m.byNumber[f.number] != nil {
+			return nil, fmt.Errorf("invalid protobuf descriptor: message %s reuses field number %d", m.name, f.number)
+		}
+		m.byNumber[f.number] = f
+		m.byName[f.name] = f
+	}
+	msgs[m.name] = m
+	return m, nil
+}
+
+// protoFieldDescriptor reads a FieldDescriptorProto
+func protoFieldDescriptor(data []byte, proto3 bool) (*protoField, error) {
+	f := &protoField{}
+	var (
+		packed, packedSet bool
+		oneof, optional   bool
+	)
+	d := &protoDecoder{buf: data}
+	for !d.done() {
+		num, wire, err := d.tag()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case wire == protoVarint:
+			v, err := d.varint()
+			if err != nil {
+				return nil, err
+			}
+			switch num {
+			case 3:
+				f.number = int(v)
+			case 4:
+				f.repeated = v == 3
+			case 5:
+				f.typ = int(v)
+			case 9:
+				oneof = true
+			case 17:
+				optional = v != 0
+			}
+		case wire == protoBytes:
+			b, err := d.bytes()
+			if err != nil {
+				return nil, err
+			}
+			switch num {
+			case 1:
+				f.name = string(b)
+			case 6:
+				f.typeName = string(b)
+			case 8:
+				// FieldOptions.packed
+				opts := &protoDecoder{buf: b}
+				for !opts.done() {
+					onum, owire, err := opts.tag()
+					if err != nil {
+						return nil, err
+					}
+					if onum == 2 && owire == protoVarint {
+						v, err := opts.varint()
+						if err != nil {
+							return nil, err
+						}
+						packed, packedSet = v != 0, true
+					} else if err := opts.skip(owire, 0); err != nil {
+						return nil, err
+					}
+				}
+			}
+		default:
+			if err := d.skip(wire, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if f.name == "" || f.number <= 0 || f.typ < protoDouble || f.typ > protoSint64 {
+		return nil, fmt.Errorf("invalid protobuf descriptor: bad field %q", f.name)
+	}
+	if f.repeated && f.packable() {
+		// repeated scalars are packed by default in proto3
+		f.packed = packed || !packedSet && proto3
+	}
+	f.presence = !proto3 || optional || oneof || f.typ == protoMessage || f.typ == protoGroup
+	return f, nil
+}
+
+// protoEnumDescriptor reads an EnumDescriptorProto
+func protoEnumDescriptor(data []byte, prefix string, enums map[string]*protoEnumType) error {
+	e := &protoEnumType{names: map[int32]string{}, numbers: map[string]int32{}}
+	d := &protoDecoder{buf: data}
+	for !d.done() {
+		num, wire, err := d.tag()
+		if err != nil {
+			return err
+		}
+		if wire != protoBytes {
+			if err := d.skip(wire, 0); err != nil {
+				return err
+			}
+			continue
+		}
+		b, err := d.bytes()
+		if err != nil {
+			return err
+		}
+		switch num {
+		case 1:
+			e.name = prefix + string(b)
+		case 2:
+			// EnumValueDescriptorProto
+			var (
+				name   string
+				number int32
+			)
+			vd := &protoDecoder{buf: b}
+			for !vd.done() {
+				vnum, vwire, err := vd.tag()
+				if err != nil {
+					return err
+				}
+				switch {
+				case vnum == 1 && vwire == protoBytes:
+					nb, err := vd.bytes()
+					if err != nil {
+						return err
+					}
+					name = string(nb)
+				case vnum == 2 && vwire == protoVarint:
+					v, err := vd.varint()
+					if err != nil {
+						return err
+					}
+					number = int32(v)
+				default:
+					if err := vd.skip(vwire, 0); err != nil {
+						return err
+					}
+				}
+			}
+			e.values = append(e.values, name)
+			if _, ok := e.names[number]; !ok {
+				e.names[number] = name
+			}
+			e.numbers[name] = number
+		}
+	}
+	enums[e.name] = e
+	return nil
+}
+
+// packable reports whether a field's values can be packed
+func (f *protoField) packable() bool {
+	switch f.typ {
+	case protoString, protoBytesT, protoMessage, protoGroup:
+		return false
+	}
+	return true
+}
+
+// wireType gives the wire type of a single value of the field
+func (f *protoField) wireType() int {
+	switch f.typ {
+	case protoDouble, protoFixed64T, protoSfixed64:
+		return protoFixed64
+	case protoFloat, protoFixed32T, protoSfixed32:
+		return protoFixed32
+	case protoString, protoBytesT, protoMessage:
+		return protoBytes
+	case protoGroup:
+		return protoStartGroup
+	}
+	return protoVarint
+}
+
+// protobufMessage gives the message type named by a structure's
+// ProtobufOptions
+func protobufMessage(st *dataset.Structure) (*protoMsg, error) {
+	opts, err := dataset.NewProtobufOptions(st.FormatConfig)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Descriptor == "" {
+		return nil, fmt.Errorf("protobuf format config requires a descriptor")
+	}
+	data, err := base64.StdEncoding.DecodeString(opts.Descriptor)
+	if err != nil {
+		return nil, fmt.Errorf("descriptor must be base64-encoded: %w", err)
+	}
+	msgs, topLevel, err := protoDescriptorSet(data)
+	if err != nil {
+		if err == errProtobufCorrupt {
+			return nil, fmt.Errorf("invalid protobuf descriptor: %w", err)
+		}
+		return nil, err
+	}
+
+	if opts.Message == "" {
+		if len(topLevel) != 1 {
+			return nil, fmt.Errorf("protobuf descriptor defines %d messages, format config must name the message", len(topLevel))
+		}
+		return msgs[topLevel[0]], nil
+	}
+	m, ok := msgs[opts.Message]
+	if !ok {
+		return nil, fmt.Errorf("protobuf descriptor doesn't define message %s", opts.Message)
+	}
+	return m, nil
+}
+
+// ProtobufSchema derives a jsonschema from the message type named by a
+// structure's ProtobufOptions. Bodies are arrays of message objects, with
+// repeated fields as arrays, map fields as objects & enums as their value
+// names
+func ProtobufSchema(st *dataset.Structure) (map[string]interface{}, error) {
+	m, err := protobufMessage(st)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	return map[string]interface{}{
+		"type":  "array",
+		"items": m.schema(map[string]bool{}),
+	}, nil
+}
+
+// schema gives the jsonschema of a message. recursive message types are
+// described as objects at the point they recur
+func (m *protoMsg) schema(seen map[string]bool) map[string]interface{} {
+	if seen[m.name] {
+		return map[string]interface{}{"type": "object", "title": m.name}
+	}
+	seen[m.name] = true
+	defer delete(seen, m.name)
+
+	props := make(map[string]interface{}, len(m.fields))
+	for _, f := range m.fields {
+		props[f.name] = f.schema(seen)
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"title":      m.name,
+		"properties": props,
+	}
+}
+
+// schema gives the jsonschema of a field's values
+func (f *protoField) schema(seen map[string]bool) map[string]interface{} {
+	if f.repeated && f.message != nil && f.message.mapEntry {
+		sch := map[string]interface{}{"type": "object"}
+		if val := f.message.byNumber[2]; val != nil {
+			sch["additionalProperties"] = val.schema(seen)
+		}
+		return sch
+	}
+
+	var sch map[string]interface{}
+	switch f.typ {
+	case protoDouble, protoFloat:
+		sch = map[string]interface{}{"type": "number"}
+	case protoBool:
+		sch = map[string]interface{}{"type": "boolean"}
+	case protoString, protoBytesT:
+		sch = map[string]interface{}{"type": "string"}
+	case protoEnum:
+		enum := make([]interface{}, len(f.enum.values))
+		for i, v := range f.enum.values {
+			enum[i] = v
+		}
+		sch = map[string]interface{}{"type": "string", "enum": enum}
+	case protoMessage, protoGroup:
+		sch = f.message.schema(seen)
+	default:
+		sch = map[string]interface{}{"type": "integer"}
+	}
+	if f.repeated {
+		return map[string]interface{}{"type": "array", "items": sch}
+	}
+	return sch
+}
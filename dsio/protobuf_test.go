@@ -0,0 +1,345 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+// pbBytes encodes a length-delimited field from it's parts
+func pbBytes(num int, parts ...[]byte) []byte {
+	return protoAppendBytes(protoAppendTag(nil, num, protoBytes), bytes.Join(parts, nil))
+}
+
+func pbString(num int, s string) []byte {
+	return pbBytes(num, []byte(s))
+}
+
+func pbVarint(num int, v uint64) []byte {
+	return binary.AppendUvarint(protoAppendTag(nil, num, protoVarint), v)
+}
+
+// pbField encodes a FieldDescriptorProto
+func pbField(name string, number, label, typ int, typeName string) []byte {
+	parts := [][]byte{pbString(1, name), pbVarint(3, uint64(number)), pbVarint(4, uint64(label)), pbVarint(5, uint64(typ))}
+	if typeName != "" {
+		parts = append(parts, pbString(6, typeName))
+	}
+	return pbBytes(2, parts...)
+}
+
+// weatherDescriptor is a FileDescriptorSet for:
+//
+//	syntax = "proto3";
+//	package weather;
+//	enum Condition { CLEAR = 0; RAIN = 1; }
+//	message Reading {
+//	  message Location { float lat = 1; float lng = 2; }
+//	  string station = 1;
+//	  double temp = 2;
+//	  sint32 delta = 3;
+//	  repeated int32 samples = 4;
+//	  Condition condition = 5;
+//	  Location location = 6;
+//	  map<string, int64> counts = 7;
+//	  bool active = 8;
+//	}
+//	message Empty {}
+func weatherDescriptor() string {
+	location := pbBytes(3,
+		pbString(1, "Location"),
+		pbField("lat", 1, 1, protoFloat, ""),
+		pbField("lng", 2, 1, protoFloat, ""),
+	)
+	countsEntry := pbBytes(3,
+		pbString(1, "CountsEntry"),
+		pbField("key", 1, 1, protoString, ""),
+		pbField("value", 2, 1, protoInt64, ""),
+		pbBytes(7, pbVarint(7, 1)),
+	)
+	reading := pbBytes(4,
+		pbString(1, "Reading"),
+		pbField("station", 1, 1, protoString, ""),
+		pbField("temp", 2, 1, protoDouble, ""),
+		pbField("delta", 3, 1, protoSint32, ""),
+		pbField("samples", 4, 3, protoInt32, ""),
+		pbField("condition", 5, 1, protoEnum, ".weather.Condition"),
+		pbField("location", 6, 1, protoMessage, ".weather.Reading.Location"),
+		pbField("counts", 7, 3, protoMessage, ".weather.Reading.CountsEntry"),
+		pbField("active", 8, 1, protoBool, ""),
+		location,
+		countsEntry,
+	)
+	condition := pbBytes(5,
+		pbString(1, "Condition"),
+		pbBytes(2, pbString(1, "CLEAR"), pbVarint(2, 0)),
+		pbBytes(2, pbString(1, "RAIN"), pbVarint(2, 1)),
+	)
+	file := pbBytes(1,
+		pbString(1, "weather.proto"),
+		pbString(2, "weather"),
+		reading,
+		pbBytes(4, pbString(1, "Empty")),
+		condition,
+		pbString(12, "proto3"),
+	)
+	return base64.StdEncoding.EncodeToString(file)
+}
+
+func weatherStructure(schema map[string]interface{}) *dataset.Structure {
+	return &dataset.Structure{
+		Format:       "protobuf",
+		FormatConfig: map[string]interface{}{"descriptor": weatherDescriptor(), "message": "weather.Reading"},
+		Schema:       schema,
+	}
+}
+
+func TestProtobufSchema(t *testing.T) {
+	got, err := ProtobufSchema(weatherStructure(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	location := map[string]interface{}{
+		"type":  "object",
+		"title": "weather.Reading.Location",
+		"properties": map[string]interface{}{
+			"lat": map[string]interface{}{"type": "number"},
+			"lng": map[string]interface{}{"type": "number"},
+		},
+	}
+	expect := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":  "object",
+			"title": "weather.Reading",
+			"properties": map[string]interface{}{
+				"station":   map[string]interface{}{"type": "string"},
+				"temp":      map[string]interface{}{"type": "number"},
+				"delta":     map[string]interface{}{"type": "integer"},
+				"samples":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer"}},
+				"condition": map[string]interface{}{"type": "string", "enum": []interface{}{"CLEAR", "RAIN"}},
+				"location":  location,
+				"counts":    map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "integer"}},
+				"active":    map[string]interface{}{"type": "boolean"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("schema mismatch.\nexpected: %v\ngot:      %v", expect, got)
+	}
+
+	cases := []struct {
+		cfg map[string]interface{}
+		err string
+	}{
+		{map[string]interface{}{}, "protobuf format config requires a descriptor"},
+		{map[string]interface{}{"descriptor": weatherDescriptor()}, "protobuf descriptor defines 2 messages, format config must name the message"},
+		{map[string]interface{}{"descriptor": weatherDescriptor(), "message": "weather.Missing"}, "protobuf descriptor doesn't define message weather.Missing"},
+		{map[string]interface{}{"descriptor": "CgE="}, "invalid protobuf descriptor: corrupt protobuf data"},
+	}
+	for i, c := range cases {
+		_, err := ProtobufSchema(&dataset.Structure{Format: "protobuf", FormatConfig: c.cfg})
+		if err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestProtobufRoundTrip(t *testing.T) {
+	entries := []interface{}{
+		map[string]interface{}{
+			"station":   "north",
+			"temp":      21.5,
+			"delta":     -3,
+			"samples":   []interface{}{1, -2, 300},
+			"condition": "RAIN",
+			"location":  map[string]interface{}{"lat": 1.5, "lng": -2.25},
+			"counts":    map[string]interface{}{"a": 1, "b": 20},
+			"active":    true,
+		},
+		// missing fields read as their proto3 default, messages stay unset
+		map[string]interface{}{"station": "south"},
+	}
+	expect := []interface{}{
+		entries[0],
+		map[string]interface{}{
+			"station":   "south",
+			"temp":      float64(0),
+			"delta":     0,
+			"samples":   []interface{}{},
+			"condition": "CLEAR",
+			"counts":    map[string]interface{}{},
+			"active":    false,
+		},
+	}
+
+	st := weatherStructure(nil)
+	buf := &bytes.Buffer{}
+	w, err := NewEntryWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range entries {
+		if err := w.WriteEntry(Entry{Index: i, Value: v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.(*ProtobufWriter).BytesProcessed() != int64(buf.Len()) {
+		t.Errorf("bytes processed mismatch. expected: %d, got: %d", buf.Len(), w.(*ProtobufWriter).BytesProcessed())
+	}
+
+	r, err := NewEntryReader(st, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ex := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("entry %d unexpected error: %s", i, err)
+		}
+		if ent.Index != i {
+			t.Errorf("entry %d index mismatch, got: %d", i, ent.Index)
+		}
+		if !reflect.DeepEqual(ent.Value, ex) {
+			t.Errorf("entry %d mismatch.\nexpected: %#v\ngot:      %#v", i, ex, ent.Value)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+}
+
+func TestProtobufTabular(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "station", "type": "string"},
+				map[string]interface{}{"title": "location", "type": "object"},
+				map[string]interface{}{"title": "temp", "type": "number"},
+			},
+		},
+	}
+	st := weatherStructure(schema)
+	buf := &bytes.Buffer{}
+	w, err := NewProtobufWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := []interface{}{
+		[]interface{}{"east", nil, 4.0},
+		[]interface{}{"west", map[string]interface{}{"lat": 0.5}, float64(-1)},
+	}
+	for i, row := range rows {
+		if err := w.WriteEntry(Entry{Index: i, Value: row}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewProtobufReader(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []interface{}{
+		[]interface{}{"east", nil, 4.0},
+		[]interface{}{"west", map[string]interface{}{"lat": 0.5, "lng": float64(0)}, float64(-1)},
+	}
+	for i, ex := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ent.Value, ex) {
+			t.Errorf("entry %d mismatch.\nexpected: %#v\ngot:      %#v", i, ex, ent.Value)
+		}
+	}
+	if r.EntriesRead() != 2 {
+		t.Errorf("expected 2 entries read, got: %d", r.EntriesRead())
+	}
+
+	bad := weatherStructure(map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":  "array",
+			"items": []interface{}{map[string]interface{}{"title": "nope", "type": "string"}},
+		},
+	})
+	if _, err := NewProtobufWriter(bad, &bytes.Buffer{}); err == nil || err.Error() != "column nope isn't a field of protobuf message weather.Reading" {
+		t.Errorf("expected unknown column error, got: %v", err)
+	}
+}
+
+func TestProtobufReaderWireFormat(t *testing.T) {
+	// unpacked repeated values, an unknown field & an unknown enum value
+	msg := bytes.Join([][]byte{
+		pbString(1, "x"),
+		pbVarint(4, 7),
+		pbVarint(4, 8),
+		pbVarint(99, 1),
+		pbVarint(5, 9),
+	}, nil)
+	body := protoAppendBytes(nil, msg)
+	st := weatherStructure(nil)
+	r, err := NewProtobufReader(st, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := ent.Value.(map[string]interface{})
+	if !reflect.DeepEqual(obj["samples"], []interface{}{7, 8}) {
+		t.Errorf("expected unpacked samples, got: %v", obj["samples"])
+	}
+	if obj["condition"] != 9 {
+		t.Errorf("expected unknown enum number, got: %v", obj["condition"])
+	}
+
+	truncated := protoAppendBytes(nil, msg)[:len(msg)-1]
+	r, err = NewProtobufReader(st, bytes.NewReader(truncated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.ReadEntry()
+	var pe *ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, errProtobufCorrupt) {
+		t.Errorf("expected a corrupt data parse error, got: %v", err)
+	}
+}
+
+func TestProtobufWriterErrors(t *testing.T) {
+	cases := []struct {
+		val interface{}
+		err string
+	}{
+		{[]interface{}{"a"}, "expected object value to write protobuf message. got: []interface {}"},
+		{map[string]interface{}{"nope": 1}, "entry 0: nope isn't a field of message weather.Reading"},
+		{map[string]interface{}{"delta": int64(1 << 40)}, "entry 0: field delta: value 1099511627776 overflows int32"},
+		{map[string]interface{}{"condition": "SNOW"}, "entry 0: field condition: \"SNOW\" isn't a value of enum weather.Condition"},
+		{map[string]interface{}{"temp": "warm"}, "entry 0: field temp: expected number value, got string"},
+		{map[string]interface{}{"samples": []interface{}{1.5}}, "entry 0: field samples: expected integer value, got 1.5"},
+	}
+	for i, c := range cases {
+		w, err := NewProtobufWriter(weatherStructure(nil), &bytes.Buffer{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteEntry(Entry{Value: c.val}); err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package dsio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+// ProvenanceReader wraps an EntryReader, annotating entries with the
+// provenance of a single source. Entries that already carry provenance,
+// like entries read from partition shards, keep it, gaining only the fields
+// they're missing
+type ProvenanceReader struct {
+	r       EntryReader
+	source  string
+	fetched time.Time
+	read    int
+}
+
+var _ EntryReader = (*ProvenanceReader)(nil)
+
+// NewProvenanceReader wraps r, recording source & the time it was fetched on
+// each entry. fetched may be zero if it isn't known
+func NewProvenanceReader(r EntryReader, source string, fetched time.Time) *ProvenanceReader {
+	return &ProvenanceReader{r: r, source: source, fetched: fetched}
+}
+
+// Structure gives the structure of the wrapped reader
+func (pr *ProvenanceReader) Structure() *dataset.Structure {
+	return pr.r.Structure()
+}
+
+// ReadEntry reads an entry from the wrapped reader, annotating it's
+// provenance
+func (pr *ProvenanceReader) ReadEntry() (Entry, error) {
+	ent, err := pr.r.ReadEntry()
+	if err != nil {
+		return ent, err
+	}
+	if ent.Provenance == nil {
+		ent.Provenance = &Provenance{Row: pr.read}
+	} else {
+		p := *ent.Provenance
+		ent.Provenance = &p
+	}
+	if ent.Provenance.Source == "" {
+		ent.Provenance.Source = pr.source
+	}
+	if ent.Provenance.Fetched.IsZero() {
+		ent.Provenance.Fetched = pr.fetched
+	}
+	pr.read++
+	return ent, nil
+}
+
+// EntriesRead gives the number of entries read
+func (pr *ProvenanceReader) EntriesRead() int {
+	return pr.read
+}
+
+// Close closes the wrapped reader
+func (pr *ProvenanceReader) Close() error {
+	return pr.r.Close()
+}
+
+// ProvenanceConfig configures how a ProvenanceWriter preserves the
+// provenance of written entries
+type ProvenanceConfig struct {
+	// Columns adds row, source & fetched columns to written entries. Array
+	// entries gain trailing values, object entries gain keys
+	Columns bool
+	// Prefix starts the names of provenance columns, default "_" names
+	// columns "_row", "_source" & "_fetched"
+	Prefix string
+	// Sidecar, if set, receives a newline-delimited JSON record of the
+	// provenance of each written entry that has provenance. The sidecar isn't
+	// closed by the writer
+	Sidecar io.Writer
+}
+
+// columnNames gives the titles of provenance columns
+func (cfg *ProvenanceConfig) columnNames() []string {
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = "_"
+	}
+	return []string{prefix + "row", prefix + "source", prefix + "fetched"}
+}
+
+// ProvenanceStructure gives the structure of a body written with provenance
+// columns. Tabular schemas gain row, source & fetched columns, and object
+// item schemas gain properties. The structure is returned as-is when
+// cfg doesn't add columns. Body-specific fields like checksum aren't copied
+func ProvenanceStructure(st *dataset.Structure, cfg *ProvenanceConfig) (*dataset.Structure, error) {
+	pst := &dataset.Structure{
+		Qri:          st.Qri,
+		Format:       st.Format,
+		FormatConfig: st.FormatConfig,
+		Compression:  st.Compression,
+		Encoding:     st.Encoding,
+		Schema:       st.Schema,
+	}
+	if !cfg.Columns {
+		return pst, nil
+	}
+
+	names := cfg.columnNames()
+	types := []string{"integer", "string", "string"}
+	itemObj, ok := st.Schema["items"].(map[string]interface{})
+	if !ok {
+		return nil, newKindError(ErrBadSchema, "provenance columns require a schema with array or object items")
+	}
+	items := make(map[string]interface{}, len(itemObj))
+	for k, v := range itemObj {
+		items[k] = v
+	}
+
+	if cols, ok := itemObj["items"].([]interface{}); ok {
+		out := make([]interface{}, len(cols), len(cols)+len(names))
+		copy(out, cols)
+		for i, name := range names {
+			out = append(out, map[string]interface{}{"title": name, "type": types[i]})
+		}
+		items["items"] = out
+	} else if itemObj["type"] == "object" {
+		props := map[string]interface{}{}
+		if p, ok := itemObj["properties"].(map[string]interface{}); ok {
+			for k, v := range p {
+				props[k] = v
+			}
+		}
+		for i, name := range names {
+			props[name] = map[string]interface{}{"type": types[i]}
+		}
+		items["properties"] = props
+	} else {
+		return nil, newKindError(ErrBadSchema, "provenance columns require a schema with array or object items")
+	}
+
+	sch := make(map[string]interface{}, len(st.Schema))
+	for k, v := range st.Schema {
+		sch[k] = v
+	}
+	sch["items"] = items
+	pst.Schema = sch
+	return pst, nil
+}
+
+// ProvenanceWriter wraps an EntryWriter, preserving the provenance of
+// entries as extra columns, a sidecar of provenance records, or both. With
+// columns, the wrapped writer should be created with the structure given by
+// ProvenanceStructure
+type ProvenanceWriter struct {
+	w     EntryWriter
+	cfg   ProvenanceConfig
+	names []string
+	count int
+}
+
+var _ EntryWriter = (*ProvenanceWriter)(nil)
+
+// NewProvenanceWriter wraps w, preserving provenance as configured by cfg
+func NewProvenanceWriter(w EntryWriter, cfg *ProvenanceConfig) *ProvenanceWriter {
+	pw := &ProvenanceWriter{w: w, cfg: *cfg}
+	pw.names = pw.cfg.columnNames()
+	return pw
+}
+
+// provenanceRecord is a line of a provenance sidecar
+type provenanceRecord struct {
+	Index   int    `json:"index"`
+	Key     string `json:"key,omitempty"`
+	Row     int    `json:"row"`
+	Source  string `json:"source,omitempty"`
+	Fetched string `json:"fetched,omitempty"`
+}
+
+// Structure gives the wrapped writer's structure
+func (pw *ProvenanceWriter) Structure() *dataset.Structure {
+	return pw.w.Structure()
+}
+
+// WriteEntry writes an entry & it's provenance. Entries without provenance
+// are written with null provenance columns & no sidecar record
+func (pw *ProvenanceWriter) WriteEntry(ent Entry) error {
+	p := ent.Provenance
+	var fetched string
+	if p != nil && !p.Fetched.IsZero() {
+		fetched = p.Fetched.UTC().Format(time.RFC3339)
+	}
+
+	if pw.cfg.Columns {
+		vals := []interface{}{nil, nil, nil}
+		if p != nil {
+			vals[0] = p.Row
+			if p.Source != "" {
+				vals[1] = p.Source
+			}
+			if fetched != "" {
+				vals[2] = fetched
+			}
+		}
+		switch v := ent.Value.(type) {
+		case []interface{}:
+			row := make([]interface{}, len(v), len(v)+len(vals))
+			copy(row, v)
+			ent.Value = append(row, vals...)
+		case map[string]interface{}:
+			obj := make(map[string]interface{}, len(v)+len(vals))
+			for key, val := range v {
+				obj[key] = val
+			}
+			for i, name := range pw.names {
+				obj[name] = vals[i]
+			}
+			ent.Value = obj
+		default:
+			err := fmt.Errorf("entry %d: provenance columns require array or object entries, got: %T", ent.Index, ent.Value)
+			log.Debug(err.Error())
+			return err
+		}
+	}
+
+	if err := pw.w.WriteEntry(ent); err != nil {
+		return err
+	}
+	pw.count++
+
+	if pw.cfg.Sidecar != nil && p != nil {
+		data, err := json.Marshal(provenanceRecord{
+			Index:   ent.Index,
+			Key:     ent.Key,
+			Row:     p.Row,
+			Source:  p.Source,
+			Fetched: fetched,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := pw.cfg.Sidecar.Write(append(data, '\n')); err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("writing provenance of entry %d: %w", ent.Index, err)
+		}
+	}
+	return nil
+}
+
+// EntriesWritten gives the number of entries written
+func (pw *ProvenanceWriter) EntriesWritten() int {
+	return pw.count
+}
+
+// BytesProcessed gives the bytes written by the wrapped writer
+func (pw *ProvenanceWriter) BytesProcessed() int64 {
+	return bytesProcessed(pw.w)
+}
+
+// Close closes the wrapped writer
+func (pw *ProvenanceWriter) Close() error {
+	return pw.w.Close()
+}
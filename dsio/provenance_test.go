@@ -0,0 +1,188 @@
+package dsio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestProvenanceReader(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewEntryReader(st, strings.NewReader(`[1,2,3]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetched := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	pr := NewProvenanceReader(r, "https://example.com/body.json", fetched)
+
+	for i := 0; i < 3; i++ {
+		ent, err := pr.ReadEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := &Provenance{Row: i, Source: "https://example.com/body.json", Fetched: fetched}
+		if !reflect.DeepEqual(ent.Provenance, expect) {
+			t.Errorf("entry %d provenance mismatch. expected: %v, got: %v", i, expect, ent.Provenance)
+		}
+	}
+	if _, err := pr.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+	if pr.EntriesRead() != 3 {
+		t.Errorf("expected 3 entries read, got: %d", pr.EntriesRead())
+	}
+}
+
+func TestProvenanceReaderPartitions(t *testing.T) {
+	st := &dataset.Structure{Format: "ndjson", Schema: dataset.BaseSchemaArray}
+	shards := []*PartitionShard{{Key: "a"}, {Key: "b"}}
+	data := map[string]string{"a": "1\n2\n", "b": "3\n"}
+	open := func(shard *PartitionShard) (io.Reader, error) {
+		return strings.NewReader(data[shard.Key]), nil
+	}
+	fetched := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	r := NewProvenanceReader(NewPartitionReader(st, shards, open), "ignored", fetched)
+
+	expect := []Provenance{
+		{Row: 0, Source: "a", Fetched: fetched},
+		{Row: 1, Source: "a", Fetched: fetched},
+		{Row: 0, Source: "b", Fetched: fetched},
+	}
+	for i, p := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ent.Index != i || !reflect.DeepEqual(*ent.Provenance, p) {
+			t.Errorf("entry %d mismatch. expected: %v, got: %d %v", i, p, ent.Index, ent.Provenance)
+		}
+	}
+}
+
+func TestProvenanceWriter(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+				},
+			},
+		},
+	}
+	cfg := &ProvenanceConfig{Columns: true, Sidecar: &bytes.Buffer{}}
+	pst, err := ProvenanceStructure(st, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.Schema["items"].(map[string]interface{})["items"].([]interface{})) != 1 {
+		t.Errorf("expected source schema to be unchanged")
+	}
+
+	buf := &bytes.Buffer{}
+	w, err := NewEntryWriter(pst, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw := NewProvenanceWriter(w, cfg)
+	fetched := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	ents := []Entry{
+		{Index: 0, Value: []interface{}{"toronto"}, Provenance: &Provenance{Row: 4, Source: "cities.csv", Fetched: fetched}},
+		{Index: 1, Value: []interface{}{"berlin"}, Provenance: &Provenance{Row: 5}},
+		{Index: 2, Value: []interface{}{"lagos"}},
+	}
+	for _, ent := range ents {
+		if err := pw.WriteEntry(ent); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if pw.EntriesWritten() != 3 {
+		t.Errorf("expected 3 entries written, got: %d", pw.EntriesWritten())
+	}
+	if ents[0].Value.([]interface{})[0] != "toronto" || len(ents[0].Value.([]interface{})) != 1 {
+		t.Errorf("expected written entries to be unchanged")
+	}
+
+	expect := "city,_row,_source,_fetched\ntoronto,4,cities.csv,2020-01-02T03:04:05Z\nberlin,5,,\nlagos,,,\n"
+	if buf.String() != expect {
+		t.Errorf("body mismatch.\nexpected: %q\ngot:      %q", expect, buf.String())
+	}
+	sidecar := "{\"index\":0,\"row\":4,\"source\":\"cities.csv\",\"fetched\":\"2020-01-02T03:04:05Z\"}\n{\"index\":1,\"row\":5}\n"
+	if got := cfg.Sidecar.(*bytes.Buffer).String(); got != sidecar {
+		t.Errorf("sidecar mismatch.\nexpected: %q\ngot:      %q", sidecar, got)
+	}
+}
+
+func TestProvenanceWriterObjects(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "object", "properties": map[string]interface{}{"n": map[string]interface{}{"type": "integer"}}},
+		},
+	}
+	cfg := &ProvenanceConfig{Columns: true, Prefix: "src_"}
+	pst, err := ProvenanceStructure(st, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := pst.Schema["items"].(map[string]interface{})["properties"].(map[string]interface{})
+	for _, name := range []string{"n", "src_row", "src_source", "src_fetched"} {
+		if props[name] == nil {
+			t.Errorf("expected schema property %s", name)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	w, err := NewEntryWriter(pst, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw := NewProvenanceWriter(w, cfg)
+	if err := pw.WriteEntry(Entry{Value: map[string]interface{}{"n": 1}, Provenance: &Provenance{Row: 2, Source: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pw.WriteEntry(Entry{Index: 1, Value: "nope"}); err == nil || err.Error() != "entry 1: provenance columns require array or object entries, got: string" {
+		t.Errorf("expected scalar entry error, got: %v", err)
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expect := `[{"n":1,"src_fetched":null,"src_row":2,"src_source":"a"}]`
+	if buf.String() != expect {
+		t.Errorf("body mismatch.\nexpected: %s\ngot:      %s", expect, buf.String())
+	}
+
+	if _, err := ProvenanceStructure(&dataset.Structure{Schema: dataset.BaseSchemaObject}, cfg); err == nil {
+		t.Error("expected schema without items to error")
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) { return 0, fmt.Errorf("disk full") }
+
+func TestProvenanceWriterSidecarError(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	w, err := NewEntryWriter(st, &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw := NewProvenanceWriter(w, &ProvenanceConfig{Sidecar: failWriter{}})
+	err = pw.WriteEntry(Entry{Value: 1, Provenance: &Provenance{}})
+	if err == nil || err.Error() != "writing provenance of entry 0: disk full" {
+		t.Errorf("expected sidecar error, got: %v", err)
+	}
+}
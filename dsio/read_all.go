@@ -0,0 +1,111 @@
+package dsio
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ReadAll reads every entry of r. maxEntries caps the number of entries read
+// & maxBytes the bytes of the body processed, keeping huge bodies from
+// exhausting memory. zero disables a limit. Reading past a limit returns an
+// *ErrLimitExceeded error with the entries read before it. maxBytes needs a
+// reader that's a ByteCounter. r isn't closed
+func ReadAll(r EntryReader, maxEntries int, maxBytes int64) ([]Entry, error) {
+	if maxEntries < 0 || maxBytes < 0 {
+		return nil, fmt.Errorf("read limits can't be negative")
+	}
+	if _, ok := r.(ByteCounter); maxBytes > 0 && !ok {
+		return nil, fmt.Errorf("reader doesn't count bytes, can't limit bytes read")
+	}
+
+	size := maxBatchPrealloc
+	if maxEntries > 0 && maxEntries < size {
+		size = maxEntries
+	}
+	ents := make([]Entry, 0, size)
+	for {
+		ent, err := r.ReadEntry()
+		if err == io.EOF {
+			return ents, nil
+		} else if err != nil {
+			log.Debug(err.Error())
+			return ents, err
+		}
+		if maxEntries > 0 && len(ents) == maxEntries {
+			return ents, &ErrLimitExceeded{Limit: "entries", Max: int64(maxEntries)}
+		}
+		if maxBytes > 0 && bytesProcessed(r) > maxBytes {
+			return ents, &ErrLimitExceeded{Limit: "bytes", Max: maxBytes}
+		}
+		ents = append(ents, ent)
+	}
+}
+
+// ReadAllValue reads every entry of r into a native go value, a
+// []interface{} for array bodies & map[string]interface{} for object bodies.
+// Limits are as ReadAll, but no value is returned with a limit error
+func ReadAllValue(r EntryReader, maxEntries int, maxBytes int64) (interface{}, error) {
+	st := r.Structure()
+	if st == nil {
+		return nil, fmt.Errorf("reader has no structure")
+	}
+	tlt, err := GetTopLevelType(st)
+	if err != nil {
+		return nil, err
+	}
+	ents, err := ReadAll(r, maxEntries, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlt == "object" {
+		obj := make(map[string]interface{}, len(ents))
+		for _, ent := range ents {
+			obj[ent.Key] = ent.Value
+		}
+		return obj, nil
+	}
+	arr := make([]interface{}, len(ents))
+	for i, ent := range ents {
+		arr[i] = ent.Value
+	}
+	return arr, nil
+}
+
+// WriteAll writes v to w, stopping at the first error. v is a []Entry, or a
+// native go value as ReadAllValue gives: a []interface{} written as array
+// entries, or a map[string]interface{} written as object entries in sorted
+// key order. w isn't closed
+func WriteAll(w EntryWriter, v interface{}) error {
+	var ents []Entry
+	switch t := v.(type) {
+	case []Entry:
+		ents = t
+	case []interface{}:
+		ents = make([]Entry, len(t))
+		for i, val := range t {
+			ents[i] = Entry{Index: i, Value: val}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		ents = make([]Entry, len(keys))
+		for i, key := range keys {
+			ents[i] = Entry{Key: key, Value: t[key]}
+		}
+	default:
+		return fmt.Errorf("can't write %T, expected []Entry, []interface{} or map[string]interface{}", v)
+	}
+
+	for _, ent := range ents {
+		if err := w.WriteEntry(ent); err != nil {
+			log.Debug(err.Error())
+			return err
+		}
+	}
+	return nil
+}
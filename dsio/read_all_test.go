@@ -0,0 +1,155 @@
+package dsio
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestReadAll(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	body := `[1,"two",{"three":3},[4]]`
+
+	cases := []struct {
+		maxEntries int
+		maxBytes   int64
+		expect     int
+		limit      string
+	}{
+		{0, 0, 4, ""},
+		{4, int64(len(body)), 4, ""},
+		{2, 0, 2, "entries"},
+		{0, 5, 1, "bytes"},
+	}
+	for i, c := range cases {
+		r, err := NewJSONReader(st, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ents, err := ReadAll(r, c.maxEntries, c.maxBytes)
+		if len(ents) != c.expect {
+			t.Errorf("case %d expected %d entries, got: %d", i, c.expect, len(ents))
+		}
+		lerr := &ErrLimitExceeded{}
+		if c.limit == "" && err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+		} else if c.limit != "" && (!errors.As(err, &lerr) || lerr.Limit != c.limit) {
+			t.Errorf("case %d expected %s limit error, got: %v", i, c.limit, err)
+		}
+	}
+
+	r, err := NewJSONReader(st, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadAll(r, -1, 0); err == nil {
+		t.Error("expected negative limit to error")
+	}
+	if _, err := ReadAll(SafeReader(r), 0, 10); err == nil {
+		t.Error("expected byte limit on a reader that doesn't count bytes to error")
+	}
+}
+
+func TestReadAllByteLimit(t *testing.T) {
+	pb := &bytes.Buffer{}
+	w, err := NewEntryWriter(weatherStructure(nil), pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, station := range []string{"north", "south", "east"} {
+		if err := w.WriteEntry(Entry{Index: i, Value: map[string]interface{}{"station": station}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		st   *dataset.Structure
+		body string
+	}{
+		{&dataset.Structure{Format: "tsv", Schema: dataset.BaseSchemaArray}, "a\t1\nb\t2\nc\t3\n"},
+		{&dataset.Structure{Format: "yaml", Schema: dataset.BaseSchemaArray}, "- 1\n- two\n- 3\n"},
+		{&dataset.Structure{Format: "xml", FormatConfig: map[string]interface{}{"element": "station"}, Schema: dataset.BaseSchemaArray}, xmlFeed},
+		{&dataset.Structure{Format: "html", FormatConfig: map[string]interface{}{"selector": "#prices"}, Schema: htmlPriceSchema}, htmlPage},
+		{weatherStructure(nil), pb.String()},
+	}
+	for _, c := range cases {
+		r, err := NewEntryReader(c.st, strings.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadAll(r, 0, int64(len(c.body))); err != nil {
+			t.Errorf("%s: unexpected error: %s", c.st.Format, err)
+		}
+		if n := r.(ByteCounter).BytesProcessed(); n <= 0 || n > int64(len(c.body)) {
+			t.Errorf("%s: expected bytes processed within the body, got: %d of %d", c.st.Format, n, len(c.body))
+		}
+
+		r, err = NewEntryReader(c.st, strings.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lerr := &ErrLimitExceeded{}
+		if _, err := ReadAll(r, 0, 1); !errors.As(err, &lerr) || lerr.Limit != "bytes" {
+			t.Errorf("%s: expected bytes limit error, got: %v", c.st.Format, err)
+		}
+	}
+}
+
+func TestReadWriteAllValue(t *testing.T) {
+	cases := []struct {
+		schema map[string]interface{}
+		body   string
+		expect interface{}
+	}{
+		{dataset.BaseSchemaArray, `[1,"a",null]`, []interface{}{1, "a", nil}},
+		{dataset.BaseSchemaObject, `{"b":true,"a":[1]}`, map[string]interface{}{"a": []interface{}{1}, "b": true}},
+	}
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "json", Schema: c.schema}
+		r, err := NewJSONReader(st, strings.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadAllValue(r, 10, 0)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("case %d value mismatch. expected: %#v, got: %#v", i, c.expect, got)
+		}
+
+		buf := &bytes.Buffer{}
+		w, err := NewJSONWriter(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteAll(w, got); err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		r, err = NewJSONReader(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again, err := ReadAllValue(r, 0, 0); err != nil || !reflect.DeepEqual(again, c.expect) {
+			t.Errorf("case %d round trip mismatch. got: %#v, %v", i, again, err)
+		}
+	}
+
+	w, err := NewEntryBuffer(&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAll(w, "nope"); err == nil {
+		t.Error("expected writing an unsupported value to error")
+	}
+}
@@ -0,0 +1,308 @@
+package dsio
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+// Redaction actions a RedactionPolicy can apply to a column
+const (
+	// RedactDrop removes the column
+	RedactDrop = "drop"
+	// RedactHash replaces values with the hex sha256 hash of the policy salt
+	// followed by the value
+	RedactHash = "hash"
+	// RedactMask replaces the characters of values with '*'
+	RedactMask = "mask"
+	// RedactMonth generalizes dates to the month they fall in, written as
+	// "2006-01"
+	RedactMonth = "month"
+)
+
+// RedactionPolicy configures the columns a RedactingWriter anonymizes
+type RedactionPolicy struct {
+	// Columns maps column titles to a redaction action. For object bodies
+	// titles are the keys of entry values
+	Columns map[string]string
+	// Salt is prepended to values before hashing. salt is secret, and isn't
+	// recorded by Record
+	Salt string
+	// MaskKeep is a number of trailing characters masked values keep
+	MaskKeep int
+}
+
+// Validate checks the policy only uses known actions
+func (p *RedactionPolicy) Validate() error {
+	for col, action := range p.Columns {
+		switch action {
+		case RedactDrop, RedactHash, RedactMask, RedactMonth:
+		default:
+			return fmt.Errorf("invalid redaction action for column %s: %s", col, action)
+		}
+	}
+	if p.MaskKeep < 0 {
+		return fmt.Errorf("redaction maskKeep cannot be negative")
+	}
+	return nil
+}
+
+// Record adds the policy to meta under the "redaction" key, so derived
+// datasets describe how they were sanitized. The salt isn't recorded
+func (p *RedactionPolicy) Record(md *dataset.Meta) error {
+	cols := make(map[string]interface{}, len(p.Columns))
+	for col, action := range p.Columns {
+		cols[col] = action
+	}
+	rec := map[string]interface{}{"columns": cols}
+	if p.MaskKeep > 0 {
+		rec["maskKeep"] = p.MaskKeep
+	}
+	return md.SetArbitrary("redaction", rec)
+}
+
+// RedactStructure gives the structure of a body written through a policy.
+// dropped columns are removed from the schema, and the other redacted
+// columns become strings. Body-specific fields like checksum aren't copied
+func RedactStructure(st *dataset.Structure, p *RedactionPolicy) (*dataset.Structure, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	rst := &dataset.Structure{
+		Qri:          st.Qri,
+		Format:       st.Format,
+		FormatConfig: st.FormatConfig,
+		Compression:  st.Compression,
+		Encoding:     st.Encoding,
+		Schema:       st.Schema,
+	}
+
+	items, ok := schemaColumns(st)
+	if !ok {
+		return rst, nil
+	}
+	cols := make([]interface{}, 0, len(items))
+	for _, it := range items {
+		col, _ := it.(map[string]interface{})
+		title, _ := col["title"].(string)
+		action, redacted := p.Columns[title]
+		if !redacted {
+			cols = append(cols, it)
+			continue
+		}
+		if action == RedactDrop {
+			continue
+		}
+		rcol := make(map[string]interface{}, len(col))
+		for k, v := range col {
+			rcol[k] = v
+		}
+		rcol["type"] = "string"
+		cols = append(cols, rcol)
+	}
+
+	sch := make(map[string]interface{}, len(st.Schema))
+	for k, v := range st.Schema {
+		sch[k] = v
+	}
+	itemObj := make(map[string]interface{})
+	for k, v := range st.Schema["items"].(map[string]interface{}) {
+		itemObj[k] = v
+	}
+	itemObj["items"] = cols
+	sch["items"] = itemObj
+	rst.Schema = sch
+	return rst, nil
+}
+
+// schemaColumns gives the column definitions of a tabular schema
+func schemaColumns(st *dataset.Structure) ([]interface{}, bool) {
+	itemObj, ok := st.Schema["items"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	items, ok := itemObj["items"].([]interface{})
+	return items, ok
+}
+
+// RedactingWriter wraps an EntryWriter, anonymizing entry values according
+// to a RedactionPolicy before they're written. The wrapped writer should be
+// created with the structure given by RedactStructure
+type RedactingWriter struct {
+	w      EntryWriter
+	policy *RedactionPolicy
+	// actions holds the redaction action for each column of array entries
+	actions []string
+	count   int
+}
+
+var _ EntryWriter = (*RedactingWriter)(nil)
+
+// NewRedactingWriter wraps w, redacting entries with the structure src. Every
+// column in the policy must be a column title of src if src has a tabular
+// schema
+func NewRedactingWriter(w EntryWriter, src *dataset.Structure, p *RedactionPolicy) (*RedactingWriter, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	rw := &RedactingWriter{w: w, policy: p}
+
+	titles, _, err := terribleHackToGetHeaderRowAndTypes(src)
+	if err != nil {
+		return rw, nil
+	}
+	rw.actions = make([]string, len(titles))
+	found := 0
+	for i, title := range titles {
+		if action, ok := p.Columns[title]; ok {
+			rw.actions[i] = action
+			found++
+		}
+	}
+	if found < len(p.Columns) {
+		var missing []string
+		for col := range p.Columns {
+			if !containsString(titles, col) {
+				missing = append(missing, col)
+			}
+		}
+		sort.Strings(missing)
+		return nil, newKindError(ErrBadSchema, fmt.Sprintf("redaction policy columns aren't in the schema: %v", missing))
+	}
+	return rw, nil
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Structure gives the wrapped writer's structure
+func (rw *RedactingWriter) Structure() *dataset.Structure {
+	return rw.w.Structure()
+}
+
+// WriteEntry redacts an entry & writes it to the wrapped writer
+func (rw *RedactingWriter) WriteEntry(ent Entry) error {
+	switch v := ent.Value.(type) {
+	case []interface{}:
+		row := make([]interface{}, 0, len(v))
+		for i, val := range v {
+			action := ""
+			if i < len(rw.actions) {
+				action = rw.actions[i]
+			}
+			if action == RedactDrop {
+				continue
+			}
+			rv, err := rw.redact(action, val)
+			if err != nil {
+				return fmt.Errorf("entry %d column %d: %w", ent.Index, i, err)
+			}
+			row = append(row, rv)
+		}
+		ent.Value = row
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			action := rw.policy.Columns[key]
+			if action == RedactDrop {
+				continue
+			}
+			rv, err := rw.redact(action, val)
+			if err != nil {
+				return fmt.Errorf("entry %d key %s: %w", ent.Index, key, err)
+			}
+			obj[key] = rv
+		}
+		ent.Value = obj
+	}
+
+	if err := rw.w.WriteEntry(ent); err != nil {
+		return err
+	}
+	rw.count++
+	return nil
+}
+
+// redact applies an action to a value. null values are left as null
+func (rw *RedactingWriter) redact(action string, v interface{}) (interface{}, error) {
+	if action == "" || v == nil {
+		return v, nil
+	}
+	switch action {
+	case RedactHash:
+		sum := sha256.Sum256([]byte(rw.policy.Salt + redactString(v)))
+		return hex.EncodeToString(sum[:]), nil
+	case RedactMask:
+		rs := []rune(redactString(v))
+		for i := 0; i < len(rs)-rw.policy.MaskKeep; i++ {
+			rs[i] = '*'
+		}
+		return string(rs), nil
+	case RedactMonth:
+		t, err := redactTime(v)
+		if err != nil {
+			return nil, err
+		}
+		return t.Format("2006-01"), nil
+	}
+	return v, nil
+}
+
+// redactString gives the string form of a value that's hashed or masked
+func redactString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case time.Time:
+		return t.Format(time.RFC3339)
+	case []interface{}, map[string]interface{}:
+		data, _ := json.Marshal(t)
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// redactLayouts are the date formats strings can be generalized from
+var redactLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02", "2006-01"}
+
+// redactTime reads a date value
+func redactTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		for _, layout := range redactLayouts {
+			if ts, err := time.Parse(layout, t); err == nil {
+				return ts, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't generalize %v to a month, expected a date", v)
+}
+
+// EntriesWritten gives the number of entries written
+func (rw *RedactingWriter) EntriesWritten() int {
+	return rw.count
+}
+
+// BytesProcessed gives the bytes written by the wrapped writer
+func (rw *RedactingWriter) BytesProcessed() int64 {
+	return bytesProcessed(rw.w)
+}
+
+// Close closes the wrapped writer
+func (rw *RedactingWriter) Close() error {
+	return rw.w.Close()
+}
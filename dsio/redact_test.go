@@ -0,0 +1,140 @@
+package dsio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+var redactStructure = &dataset.Structure{
+	Format: "csv",
+	Schema: map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "name", "type": "string"},
+				map[string]interface{}{"title": "email", "type": "string"},
+				map[string]interface{}{"title": "phone", "type": "string"},
+				map[string]interface{}{"title": "born", "type": "string"},
+				map[string]interface{}{"title": "visits", "type": "integer"},
+			},
+		},
+	},
+}
+
+func TestRedactingWriter(t *testing.T) {
+	p := &RedactionPolicy{
+		Columns: map[string]string{
+			"name":  RedactDrop,
+			"email": RedactHash,
+			"phone": RedactMask,
+			"born":  RedactMonth,
+		},
+		Salt:     "pepper",
+		MaskKeep: 2,
+	}
+
+	dst, err := RedactStructure(redactStructure, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles, types, _ := terribleHackToGetHeaderRowAndTypes(dst)
+	if len(titles) != 4 || titles[0] != "email" || types[0] != "string" || types[3] != "integer" {
+		t.Errorf("unexpected redacted schema columns: %v %v", titles, types)
+	}
+	if orig, _ := schemaColumns(redactStructure); len(orig) != 5 {
+		t.Error("RedactStructure modified the source schema")
+	}
+
+	buf := &bytes.Buffer{}
+	rw, err := NewRedactingWriter(NewCSVWriter(dst, buf), redactStructure, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.WriteEntry(Entry{Value: []interface{}{"ada", "ada@example.com", "555-1234", "1815-12-10", 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.WriteEntry(Entry{Value: []interface{}{"bob", nil, nil, nil, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256([]byte("pepperada@example.com"))
+	expect := hex.EncodeToString(sum[:]) + ",******34,1815-12,3\n,,,1\n"
+	if buf.String() != expect {
+		t.Errorf("output mismatch. expected: %q, got: %q", expect, buf.String())
+	}
+	if rw.EntriesWritten() != 2 {
+		t.Errorf("expected 2 entries written, got: %d", rw.EntriesWritten())
+	}
+
+	if err := rw.WriteEntry(Entry{Value: []interface{}{"x", "x", "x", "someday", 1}}); err == nil {
+		t.Error("expected error generalizing a non-date value")
+	}
+}
+
+func TestRedactingWriterObjects(t *testing.T) {
+	p := &RedactionPolicy{Columns: map[string]string{"ssn": RedactDrop, "name": RedactMask}}
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject}
+	buf := &bytes.Buffer{}
+	w, err := NewJSONWriter(st, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rw, err := NewRedactingWriter(w, st, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.WriteEntry(Entry{Key: "a", Value: map[string]interface{}{"ssn": "123", "name": "ada"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"a":{"name":"***"}}`
+	if buf.String() != expect {
+		t.Errorf("output mismatch. expected: %s, got: %s", expect, buf.String())
+	}
+}
+
+func TestRedactionPolicyErrors(t *testing.T) {
+	cases := []struct {
+		p   *RedactionPolicy
+		err string
+	}{
+		{&RedactionPolicy{Columns: map[string]string{"name": "shred"}}, "invalid redaction action for column name: shred"},
+		{&RedactionPolicy{MaskKeep: -1}, "redaction maskKeep cannot be negative"},
+		{&RedactionPolicy{Columns: map[string]string{"zip": RedactDrop}}, "redaction policy columns aren't in the schema: [zip]"},
+	}
+
+	for i, c := range cases {
+		_, err := NewRedactingWriter(&EntryBuffer{}, redactStructure, c.p)
+		if err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestRedactionPolicyRecord(t *testing.T) {
+	p := &RedactionPolicy{Columns: map[string]string{"email": RedactHash}, Salt: "secret"}
+	md := &dataset.Meta{}
+	if err := p.Record(md); err != nil {
+		t.Fatal(err)
+	}
+	rec, ok := md.Meta()["redaction"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected redaction meta, got: %v", md.Meta())
+	}
+	if rec["columns"].(map[string]interface{})["email"] != RedactHash {
+		t.Errorf("unexpected recorded columns: %v", rec["columns"])
+	}
+	if _, ok := rec["salt"]; ok {
+		t.Error("salt shouldn't be recorded")
+	}
+}
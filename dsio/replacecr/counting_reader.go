@@ -0,0 +1,76 @@
+package replacecr
+
+import (
+	"bufio"
+	"io"
+)
+
+// CountingReader replaces solo \r characters with \r\n like Reader, but keeps
+// track of where line feeds were added so offsets in the replaced stream can
+// be mapped back to offsets in the source
+type CountingReader struct {
+	src io.Reader
+	rdr *bufio.Reader
+	// pending is set when a \n is owed but didn't fit in the last Read
+	pending bool
+	// written is the number of bytes returned so far
+	written int64
+	// added holds the output offsets of added \n characters that haven't been
+	// passed by a call to SourceOffset yet
+	added []int64
+	// dropped counts added characters before the last mapped offset
+	dropped int64
+}
+
+// NewCountingReader wraps data in a CountingReader
+func NewCountingReader(data io.Reader) *CountingReader {
+	return &CountingReader{src: data, rdr: bufio.NewReader(data)}
+}
+
+// Close closes the source reader if it's an io.Closer. calling Close more
+// than once has no effect
+func (c *CountingReader) Close() error {
+	cl, ok := c.src.(io.Closer)
+	if !ok {
+		return nil
+	}
+	c.src = nil
+	return cl.Close()
+}
+
+// Read implements the io.Reader interface
+func (c *CountingReader) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if c.pending {
+			p[n] = '\n'
+			c.added = append(c.added, c.written)
+			c.pending = false
+		} else {
+			var b byte
+			if b, err = c.rdr.ReadByte(); err != nil {
+				return
+			}
+			p[n] = b
+			if b == '\r' {
+				if pk, err := c.rdr.Peek(1); (err == nil && pk[0] != '\n') || err == io.EOF {
+					c.pending = true
+				}
+			}
+		}
+		c.written++
+		n++
+	}
+	return
+}
+
+// SourceOffset maps off, an offset in the replaced stream, to the matching
+// offset in the source stream. calls must pass non-decreasing offsets
+func (c *CountingReader) SourceOffset(off int64) int64 {
+	i := 0
+	for i < len(c.added) && c.added[i] < off {
+		i++
+	}
+	c.dropped += int64(i)
+	c.added = c.added[i:]
+	return off - c.dropped
+}
@@ -0,0 +1,38 @@
+package replacecr
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCountingReader(t *testing.T) {
+	input := []byte("foo\r\rbar\r\nbaz\r\r")
+	expect := []byte("foo\r\n\r\nbar\r\nbaz\r\n\r\n")
+
+	// read one byte at a time to exercise owed line feeds
+	r := NewCountingReader(bytes.NewReader(input))
+	got, err := ioutil.ReadAll(iotest.OneByteReader(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expect, got) {
+		t.Errorf("byte mismatch. expected:\n%q\ngot:\n%q", expect, got)
+	}
+
+	cases := []struct {
+		off, expect int64
+	}{
+		{0, 0},
+		{5, 4},
+		{7, 5},
+		{12, 10},
+		{19, 15},
+	}
+	for _, c := range cases {
+		if got := r.SourceOffset(c.off); got != c.expect {
+			t.Errorf("offset %d: expected source offset %d, got: %d", c.off, c.expect, got)
+		}
+	}
+}
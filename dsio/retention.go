@@ -0,0 +1,103 @@
+package dsio
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+// RetentionReader wraps a reader, skipping entries expired by a retention
+// policy. Array entries are re-indexed to close the gaps left by dropped
+// entries
+type RetentionReader struct {
+	Reader  EntryReader
+	policy  *dataset.Retention
+	column  int
+	cutoff  time.Time
+	read    int
+	dropped int
+}
+
+var _ EntryReader = (*RetentionReader)(nil)
+
+// NewRetentionReader creates a reader that drops entries of r that have
+// expired by now under retention policy p
+func NewRetentionReader(r EntryReader, p *dataset.Retention, now time.Time) (*RetentionReader, error) {
+	if p == nil {
+		return nil, fmt.Errorf("retention policy is required")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	cutoff, err := p.Cutoff(now)
+	if err != nil {
+		return nil, err
+	}
+	column, err := entryColumn(r.Structure(), "retention", p.Column)
+	if err != nil {
+		return nil, err
+	}
+	return &RetentionReader{Reader: r, policy: p, column: column, cutoff: cutoff}, nil
+}
+
+// Structure returns the wrapped reader's structure
+func (r *RetentionReader) Structure() *dataset.Structure {
+	return r.Reader.Structure()
+}
+
+// ReadEntry returns the next entry that hasn't expired
+func (r *RetentionReader) ReadEntry() (Entry, error) {
+	for {
+		ent, err := r.Reader.ReadEntry()
+		if err != nil {
+			return ent, err
+		}
+
+		var val interface{}
+		switch v := ent.Value.(type) {
+		case []interface{}:
+			if r.column < 0 {
+				return ent, fmt.Errorf("entry %d: expected an object entry", r.read+r.dropped)
+			}
+			if r.column < len(v) {
+				val = v[r.column]
+			}
+		case map[string]interface{}:
+			val = v[r.policy.Column]
+		default:
+			return ent, fmt.Errorf("entry %d: can't apply retention to %T entries", r.read+r.dropped, ent.Value)
+		}
+
+		expired, err := r.policy.Expired(val, r.cutoff)
+		if err != nil {
+			log.Debug(err.Error())
+			return ent, fmt.Errorf("entry %d: %w", r.read+r.dropped, err)
+		}
+		if expired {
+			r.dropped++
+			continue
+		}
+		if ent.Key == "" {
+			ent.Index = r.read
+		}
+		r.read++
+		return ent, nil
+	}
+}
+
+// EntriesRead gives the number of entries returned by the reader, excluding
+// dropped entries
+func (r *RetentionReader) EntriesRead() int {
+	return r.read
+}
+
+// Dropped gives the number of expired entries skipped so far
+func (r *RetentionReader) Dropped() int {
+	return r.dropped
+}
+
+// Close closes the wrapped reader
+func (r *RetentionReader) Close() error {
+	return r.Reader.Close()
+}
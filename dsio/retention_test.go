@@ -0,0 +1,125 @@
+package dsio
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestRetentionReader(t *testing.T) {
+	now := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
+	policy := &dataset.Retention{Column: "ts", MaxAge: "P30D"}
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "n", "type": "integer"},
+					map[string]interface{}{"title": "ts", "type": "string"},
+				},
+			},
+		},
+	}
+	body := `[[1,"2020-01-01"],[2,"2020-03-15"],[3,null],[4,"2020-02-29T23:00:00Z"],[5,"2020-03-30T12:00:00Z"]]`
+	r, err := NewEntryReader(st, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr, err := NewRetentionReader(r, policy, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []Entry
+	for {
+		ent, err := rr.ReadEntry()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, ent)
+	}
+	expect := []Entry{
+		{Index: 0, Value: []interface{}{2, "2020-03-15"}},
+		{Index: 1, Value: []interface{}{3, nil}},
+		{Index: 2, Value: []interface{}{5, "2020-03-30T12:00:00Z"}},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("entries mismatch.\nexpected: %v\ngot:      %v", expect, got)
+	}
+	if rr.Dropped() != 2 {
+		t.Errorf("expected 2 dropped entries, got: %d", rr.Dropped())
+	}
+	if rr.EntriesRead() != 3 {
+		t.Errorf("expected 3 entries read, got: %d", rr.EntriesRead())
+	}
+}
+
+func TestRetentionReaderObjects(t *testing.T) {
+	now := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject}
+	body := `{"a":{"ts":"2020-01-01"},"b":{"ts":"2020-03-30"}}`
+	r, err := NewEntryReader(st, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr, err := NewRetentionReader(r, &dataset.Retention{Column: "ts", MaxAge: "P1M"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := rr.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent.Key != "b" {
+		t.Errorf("expected entry b, got: %s", ent.Key)
+	}
+	if _, err := rr.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+}
+
+func TestRetentionReaderErrors(t *testing.T) {
+	now := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": []interface{}{map[string]interface{}{"title": "ts", "type": "string"}},
+			},
+		},
+	}
+	cases := []struct {
+		policy *dataset.Retention
+		body   string
+		err    string
+	}{
+		{nil, `[]`, "retention policy is required"},
+		{&dataset.Retention{Column: "ts", MaxAge: "90 days"}, `[]`, "invalid retention maxAge '90 days': must be an ISO 8601 duration like P90D"},
+		{&dataset.Retention{Column: "created", MaxAge: "P1D"}, `[]`, "retention column created isn't in the schema"},
+		{&dataset.Retention{Column: "ts", MaxAge: "P1D"}, `[["last week"]]`, "entry 0: retention column ts: invalid date or time 'last week'"},
+	}
+
+	for i, c := range cases {
+		r, err := NewEntryReader(st, strings.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr, err := NewRetentionReader(r, c.policy, now)
+		if err == nil {
+			_, err = rr.ReadEntry()
+		}
+		if err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
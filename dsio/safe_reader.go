@@ -0,0 +1,86 @@
+package dsio
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dslog"
+)
+
+// PanicError is returned by a SafeReader when the underlying reader panics
+type PanicError struct {
+	// Value is the value passed to panic
+	Value interface{}
+	// Index is the index of the entry being read when the panic occurred
+	Index int
+	// Stack is the stack trace of the panicking goroutine. It's left out of
+	// the error message, which is a single line
+	Stack []byte
+}
+
+// Error implements the error interface
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("reader panicked reading entry %d: %v", e.Index, e.Value)
+}
+
+// safeReader recovers panics from an underlying reader
+type safeReader struct {
+	r    EntryReader
+	read int
+	err  *PanicError
+}
+
+// SafeReader wraps r, converting panics in r into a *PanicError. Once r has
+// panicked all further reads return the same error, as the state of r is
+// unknown
+func SafeReader(r EntryReader) EntryReader {
+	return &safeReader{r: r}
+}
+
+// Structure gives the structure of the underlying reader
+func (sr *safeReader) Structure() *dataset.Structure {
+	defer sr.recover(nil)
+	return sr.r.Structure()
+}
+
+// ReadEntry reads one entry from the underlying reader
+func (sr *safeReader) ReadEntry() (ent Entry, err error) {
+	if sr.err != nil {
+		return Entry{}, sr.err
+	}
+	defer sr.recover(&err)
+	ent, err = sr.r.ReadEntry()
+	if err == nil {
+		sr.read++
+	}
+	return ent, err
+}
+
+// EntriesRead gives the number of entries read
+func (sr *safeReader) EntriesRead() int {
+	return sr.read
+}
+
+// BytesProcessed gives the bytes processed by the underlying reader
+func (sr *safeReader) BytesProcessed() int64 {
+	return bytesProcessed(sr.r)
+}
+
+// Close closes the underlying reader
+func (sr *safeReader) Close() (err error) {
+	defer sr.recover(&err)
+	return sr.r.Close()
+}
+
+func (sr *safeReader) recover(err *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	sr.err = &PanicError{Value: v, Index: sr.read, Stack: debug.Stack()}
+	log.Debugw(sr.err.Error(), dslog.F("stack", string(sr.err.Stack)))
+	if err != nil {
+		*err = sr.err
+	}
+}
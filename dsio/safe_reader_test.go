@@ -0,0 +1,58 @@
+package dsio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+type panickingReader struct {
+	EntryReader
+	after int
+}
+
+func (r *panickingReader) ReadEntry() (Entry, error) {
+	if r.after == 0 {
+		var values []interface{}
+		return Entry{Value: values[3]}, nil
+	}
+	r.after--
+	return r.EntryReader.ReadEntry()
+}
+
+func TestSafeReader(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	er, err := NewEntryReader(st, strings.NewReader(`[1,2,3,4]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := SafeReader(&panickingReader{EntryReader: er, after: 2})
+	for i := 0; i < 2; i++ {
+		if _, err := r.ReadEntry(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = r.ReadEntry()
+	perr, ok := err.(*PanicError)
+	if !ok {
+		t.Fatalf("expected *PanicError, got: %#v", err)
+	}
+	if perr.Index != 2 {
+		t.Errorf("expected panic at index 2, got: %d", perr.Index)
+	}
+	if strings.Contains(perr.Error(), "\n") {
+		t.Errorf("expected a single line error message, got: %q", perr.Error())
+	}
+	if !strings.Contains(string(perr.Stack), "panickingReader") {
+		t.Errorf("expected stack to include the panicking reader. got:\n%s", perr.Stack)
+	}
+	if _, err := r.ReadEntry(); err != perr {
+		t.Errorf("expected reads after a panic to return the same error, got: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,672 @@
+package dsio
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/qri-io/dataset"
+)
+
+const (
+	// shpFileCode opens the header of every .shp file
+	shpFileCode = 9994
+	// shpHeaderSize is the length of .shp file headers
+	shpHeaderSize = 100
+	// shpMaxRecord caps the content length of a shape record, guarding
+	// against corrupt headers
+	shpMaxRecord = 1 << 28
+)
+
+// esri shape types
+const (
+	shpNull        = 0
+	shpPoint       = 1
+	shpPolyLine    = 3
+	shpPolygon     = 5
+	shpMultiPoint  = 8
+	shpPointZ      = 11
+	shpPolyLineZ   = 13
+	shpPolygonZ    = 15
+	shpMultiPointZ = 18
+	shpPointM      = 21
+	shpPolyLineM   = 23
+	shpPolygonM    = 25
+	shpMultiPointM = 28
+)
+
+// ShapefileReader implements the EntryReader interface for ESRI shapefiles,
+// reading each shape record with it's dBASE attributes. Like GeoJSONReader,
+// entry values are objects with a "geometry" key holding a GeoJSON geometry
+// object (nil for null shapes), and a "properties" key holding the record's
+// attributes, or nil when there's no .dbf file.
+//
+// Polygons follow the GeoJSON right-hand rule: each clockwise shapefile ring
+// starts a polygon with the counterclockwise rings that follow it as holes.
+// Z values are read as a third coordinate, M values are dropped. Records
+// marked deleted in the .dbf file are skipped. Shapefile bodies must have a
+// top-level array schema
+type ShapefileReader struct {
+	st *dataset.Structure
+	// src is the source a registered reader was created with, nil for pair
+	// readers
+	src    *TrackedReader
+	shpSrc *TrackedReader
+	shp    *bufio.Reader
+	dbf    *dbfReader
+	// closers are closed with the reader
+	closers     []io.Closer
+	record      int
+	entriesRead int
+}
+
+var _ EntryReader = (*ShapefileReader)(nil)
+
+// NewShapefileReader creates a reader from a structure and read source. The
+// source is either a zip archive holding a .shp & .dbf file pair, which is
+// read in full before the reader is returned, or a bare .shp file, which is
+// streamed & read without attributes
+func NewShapefileReader(st *dataset.Structure, r io.Reader) (*ShapefileReader, error) {
+	if err := checkShapefileSchema(st); err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+
+	src := NewTrackedReader(r)
+	br := bufio.NewReader(src)
+	magic, _ := br.Peek(4)
+	if !bytes.Equal(magic, []byte("PK\x03\x04")) {
+		rdr, err := newShapefileReader(st, br, nil)
+		if err != nil {
+			return nil, err
+		}
+		rdr.src = src
+		return rdr, nil
+	}
+
+	data, err := ioutil.ReadAll(br)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error reading shapefile archive: %w", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("not a shapefile archive: %s", err))
+	}
+
+	var shpFile *zip.File
+	for _, f := range zr.File {
+		if strings.EqualFold(path.Ext(f.Name), ".shp") {
+			if shpFile != nil {
+				return nil, newKindError(ErrFormatMismatch, "shapefile archive has more than one .shp file")
+			}
+			shpFile = f
+		}
+	}
+	if shpFile == nil {
+		return nil, newKindError(ErrFormatMismatch, "shapefile archive has no .shp file")
+	}
+	base := strings.TrimSuffix(shpFile.Name, path.Ext(shpFile.Name))
+	var dbfFile *zip.File
+	for _, f := range zr.File {
+		if strings.EqualFold(f.Name, base+".dbf") {
+			dbfFile = f
+		}
+	}
+	if dbfFile == nil {
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("shapefile archive has no .dbf file for %s", shpFile.Name))
+	}
+
+	shp, err := shpFile.Open()
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error reading %s: %w", shpFile.Name, err)
+	}
+	dbf, err := dbfFile.Open()
+	if err != nil {
+		shp.Close()
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error reading %s: %w", dbfFile.Name, err)
+	}
+	rdr, err := newShapefileReader(st, shp, dbf)
+	if err != nil {
+		shp.Close()
+		dbf.Close()
+		return nil, err
+	}
+	rdr.src = src
+	rdr.closers = append(rdr.closers, shp, dbf)
+	return rdr, nil
+}
+
+// NewShapefilePairReader creates a reader from the .shp & .dbf files of a
+// shapefile, streaming both. dbf may be nil to read shapes without
+// attributes. Sources that are io.Closers are closed with the reader
+func NewShapefilePairReader(st *dataset.Structure, shp, dbf io.Reader) (*ShapefileReader, error) {
+	if err := checkShapefileSchema(st); err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	rdr, err := newShapefileReader(st, shp, dbf)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range []io.Reader{shp, dbf} {
+		if c, ok := r.(io.Closer); ok {
+			rdr.closers = append(rdr.closers, c)
+		}
+	}
+	return rdr, nil
+}
+
+// checkShapefileSchema errors if a structure doesn't describe a shapefile
+// body
+func checkShapefileSchema(st *dataset.Structure) error {
+	if st.Schema == nil {
+		return newKindError(ErrBadSchema, "schema required for shapefiles")
+	}
+	tlt, err := GetTopLevelType(st)
+	if err != nil {
+		return err
+	}
+	if tlt != "array" {
+		return newKindError(ErrBadSchema, "shapefiles require a top-level array schema")
+	}
+	return nil
+}
+
+func newShapefileReader(st *dataset.Structure, shp, dbf io.Reader) (*ShapefileReader, error) {
+	shpSrc := NewTrackedReader(shp)
+	rdr := &ShapefileReader{st: st, shpSrc: shpSrc, shp: bufio.NewReader(shpSrc)}
+
+	header := make([]byte, shpHeaderSize)
+	if _, err := io.ReadFull(rdr.shp, header); err != nil {
+		log.Debug(err.Error())
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("not a shapefile: reading header: %s", err))
+	}
+	if binary.BigEndian.Uint32(header) != shpFileCode {
+		return nil, newKindError(ErrFormatMismatch, "not a shapefile: bad file code")
+	}
+
+	if dbf != nil {
+		var err error
+		if rdr.dbf, err = newDBFReader(dbf); err != nil {
+			log.Debug(err.Error())
+			return nil, err
+		}
+	}
+	return rdr, nil
+}
+
+// Structure gives this reader's structure
+func (r *ShapefileReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// Schema gives a JSON schema for the reader's entries, describing the
+// attribute columns of the .dbf file
+func (r *ShapefileReader) Schema() map[string]interface{} {
+	properties := map[string]interface{}{}
+	if r.dbf != nil {
+		for _, f := range r.dbf.fields {
+			properties[f.name] = map[string]interface{}{"type": f.jsonType()}
+		}
+	}
+	return map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"geometry": map[string]interface{}{"type": []interface{}{"null", "object"}},
+				"properties": map[string]interface{}{
+					"type":       []interface{}{"null", "object"},
+					"properties": properties,
+				},
+			},
+			"required": []interface{}{"geometry", "properties"},
+		},
+	}
+}
+
+// ReadEntry reads one shape record
+func (r *ShapefileReader) ReadEntry() (Entry, error) {
+	ent, err := r.readEntry()
+	return ent, parseError("shapefile", r.entriesRead, err)
+}
+
+func (r *ShapefileReader) readEntry() (Entry, error) {
+	for {
+		geom, err := r.readShape()
+		if err == io.EOF {
+			if r.dbf != nil && r.dbf.read < r.dbf.count {
+				return Entry{}, newKindError(ErrFormatMismatch, fmt.Sprintf("dbf file has %d records, shapefile has %d", r.dbf.count, r.record))
+			}
+			return Entry{}, io.EOF
+		} else if err != nil {
+			return Entry{}, err
+		}
+
+		val := map[string]interface{}{"geometry": nil, "properties": nil}
+		if geom != nil {
+			val["geometry"] = geom
+		}
+		if r.dbf != nil {
+			props, deleted, err := r.dbf.readRecord()
+			if err == io.EOF {
+				return Entry{}, newKindError(ErrFormatMismatch, fmt.Sprintf("dbf file has %d records, shapefile has more", r.dbf.count))
+			} else if err != nil {
+				return Entry{}, err
+			}
+			if deleted {
+				continue
+			}
+			val["properties"] = props
+		}
+
+		ent := Entry{Index: r.entriesRead, Value: val}
+		r.entriesRead++
+		return ent, nil
+	}
+}
+
+// readShape reads a shape record, returning a GeoJSON geometry object
+func (r *ShapefileReader) readShape() (map[string]interface{}, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r.shp, header); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("reading shape record %d: %w", r.record+1, err)
+	}
+	r.record++
+	size := int64(binary.BigEndian.Uint32(header[4:])) * 2
+	if size > shpMaxRecord {
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("shape record %d is too large: %d bytes", r.record, size))
+	}
+	content := make([]byte, size)
+	if _, err := io.ReadFull(r.shp, content); err != nil {
+		return nil, fmt.Errorf("reading shape record %d: %w", r.record, err)
+	}
+	geom, err := shpGeometry(content)
+	if err != nil {
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("shape record %d: %s", r.record, err))
+	}
+	return geom, nil
+}
+
+// shpGeometry decodes the content of a shape record
+func shpGeometry(content []byte) (map[string]interface{}, error) {
+	d := &shpDecoder{buf: content}
+	shapeType := d.readInt()
+	switch shapeType {
+	case shpNull:
+		return nil, d.err
+	case shpPoint, shpPointM, shpPointZ:
+		x, y := d.readFloat(), d.readFloat()
+		coord := []interface{}{x, y}
+		if shapeType == shpPointZ {
+			coord = append(coord, d.readFloat())
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
+		return map[string]interface{}{"type": "Point", "coordinates": coord}, nil
+	case shpMultiPoint, shpMultiPointM, shpMultiPointZ:
+		d.skip(32) // bounding box
+		n := d.count()
+		points := d.points(n)
+		if shapeType == shpMultiPointZ {
+			d.readZ(points)
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
+		return map[string]interface{}{"type": "MultiPoint", "coordinates": points}, nil
+	case shpPolyLine, shpPolyLineM, shpPolyLineZ, shpPolygon, shpPolygonM, shpPolygonZ:
+		d.skip(32) // bounding box
+		numParts, numPoints := d.count(), d.count()
+		starts := make([]int, numParts)
+		for i := range starts {
+			starts[i] = int(d.readInt())
+		}
+		points := d.points(numPoints)
+		if shapeType == shpPolyLineZ || shapeType == shpPolygonZ {
+			d.readZ(points)
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
+		parts, err := shpParts(starts, points)
+		if err != nil {
+			return nil, err
+		}
+		if shapeType == shpPolyLine || shapeType == shpPolyLineM || shapeType == shpPolyLineZ {
+			if len(parts) == 1 {
+				return map[string]interface{}{"type": "LineString", "coordinates": parts[0]}, nil
+			}
+			lines := make([]interface{}, len(parts))
+			for i, p := range parts {
+				lines[i] = p
+			}
+			return map[string]interface{}{"type": "MultiLineString", "coordinates": lines}, nil
+		}
+		return shpPolygonGeometry(parts), nil
+	}
+	return nil, fmt.Errorf("unsupported shape type: %d", shapeType)
+}
+
+// shpParts splits points into parts by their start indexes
+func shpParts(starts []int, points []interface{}) ([][]interface{}, error) {
+	parts := make([][]interface{}, len(starts))
+	for i, start := range starts {
+		end := len(points)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		if start < 0 || start > end || end > len(points) {
+			return nil, fmt.Errorf("invalid part index: %d", start)
+		}
+		parts[i] = points[start:end]
+	}
+	return parts, nil
+}
+
+// shpPolygonGeometry groups shapefile rings into polygons. clockwise rings
+// are outer rings, counterclockwise rings are holes of the outer ring before
+// them. rings are reversed to follow the GeoJSON right-hand rule
+func shpPolygonGeometry(rings [][]interface{}) map[string]interface{} {
+	polygons := []interface{}{}
+	var current []interface{}
+	for _, ring := range rings {
+		reversed := make([]interface{}, len(ring))
+		for i, pt := range ring {
+			reversed[len(ring)-1-i] = pt
+		}
+		if shpClockwise(ring) || current == nil {
+			if current != nil {
+				polygons = append(polygons, current)
+			}
+			current = []interface{}{reversed}
+			continue
+		}
+		current = append(current, reversed)
+	}
+	if current != nil {
+		polygons = append(polygons, current)
+	}
+	if len(polygons) == 1 {
+		return map[string]interface{}{"type": "Polygon", "coordinates": polygons[0]}
+	}
+	return map[string]interface{}{"type": "MultiPolygon", "coordinates": polygons}
+}
+
+// shpClockwise reports whether a ring winds clockwise, by the sign of it's
+// area
+func shpClockwise(ring []interface{}) bool {
+	var sum float64
+	for i := 0; i+1 < len(ring); i++ {
+		a, b := ring[i].([]interface{}), ring[i+1].([]interface{})
+		sum += (b[0].(float64) - a[0].(float64)) * (b[1].(float64) + a[1].(float64))
+	}
+	return sum > 0
+}
+
+// shpDecoder reads little-endian values from shape record content, recording
+// the first error
+type shpDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *shpDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > len(d.buf) {
+		d.err = fmt.Errorf("unexpected end of record")
+		d.buf = nil
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *shpDecoder) skip(n int) { d.next(n) }
+
+func (d *shpDecoder) readInt() int32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.LittleEndian.Uint32(b))
+}
+
+func (d *shpDecoder) readFloat() float64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
+
+// count reads a length, checking the record is long enough to hold it
+func (d *shpDecoder) count() int {
+	n := int(d.readInt())
+	if d.err == nil && (n < 0 || n > len(d.buf)) {
+		d.err = fmt.Errorf("invalid count: %d", n)
+	}
+	if d.err != nil {
+		return 0
+	}
+	return n
+}
+
+// points reads n x, y coordinates
+func (d *shpDecoder) points(n int) []interface{} {
+	points := make([]interface{}, n)
+	for i := range points {
+		points[i] = []interface{}{d.readFloat(), d.readFloat()}
+	}
+	return points
+}
+
+// readZ reads the z range & values that follow the points of a Z shape,
+// adding them to the points' coordinates
+func (d *shpDecoder) readZ(points []interface{}) {
+	d.skip(16) // z range
+	for i, pt := range points {
+		points[i] = append(pt.([]interface{}), d.readFloat())
+	}
+}
+
+// dbfField is a column of a dBASE file
+type dbfField struct {
+	name     string
+	kind     byte
+	length   int
+	decimals int
+}
+
+// jsonType gives the JSON schema type of a field's values
+func (f dbfField) jsonType() interface{} {
+	switch f.kind {
+	case 'N':
+		if f.decimals == 0 {
+			return []interface{}{"integer", "null"}
+		}
+		return []interface{}{"number", "null"}
+	case 'F':
+		return []interface{}{"number", "null"}
+	case 'L':
+		return []interface{}{"boolean", "null"}
+	case 'D':
+		return []interface{}{"string", "null"}
+	}
+	return "string"
+}
+
+// dbfReader reads the records of a dBASE III file, the attribute table of a
+// shapefile
+type dbfReader struct {
+	r      *bufio.Reader
+	fields []dbfField
+	count  int
+	read   int
+	record []byte
+}
+
+func newDBFReader(r io.Reader) (*dbfReader, error) {
+	d := &dbfReader{r: bufio.NewReader(r)}
+	header := make([]byte, 32)
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("not a dbf file: reading header: %s", err))
+	}
+	d.count = int(binary.LittleEndian.Uint32(header[4:]))
+	headerLen := int(binary.LittleEndian.Uint16(header[8:]))
+	recordLen := int(binary.LittleEndian.Uint16(header[10:]))
+	if headerLen < 33 || recordLen < 1 {
+		return nil, newKindError(ErrFormatMismatch, "not a dbf file: invalid header")
+	}
+
+	rest := make([]byte, headerLen-32)
+	if _, err := io.ReadFull(d.r, rest); err != nil {
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("not a dbf file: reading fields: %s", err))
+	}
+	width := 1
+	for i := 0; i+32 <= len(rest) && rest[i] != 0x0D; i += 32 {
+		desc := rest[i : i+32]
+		name := desc[:11]
+		if j := bytes.IndexByte(name, 0); j >= 0 {
+			name = name[:j]
+		}
+		f := dbfField{name: string(bytes.TrimSpace(name)), kind: desc[11], length: int(desc[16]), decimals: int(desc[17])}
+		d.fields = append(d.fields, f)
+		width += f.length
+	}
+	if width > recordLen {
+		return nil, newKindError(ErrFormatMismatch, "not a dbf file: fields are wider than records")
+	}
+	d.record = make([]byte, recordLen)
+	return d, nil
+}
+
+// readRecord reads the next record's attributes, reporting whether the
+// record is marked deleted
+func (d *dbfReader) readRecord() (map[string]interface{}, bool, error) {
+	if d.read >= d.count {
+		return nil, false, io.EOF
+	}
+	if _, err := io.ReadFull(d.r, d.record); err != nil {
+		return nil, false, fmt.Errorf("reading dbf record %d: %w", d.read+1, err)
+	}
+	d.read++
+	if d.record[0] == '*' {
+		return nil, true, nil
+	}
+
+	props := make(map[string]interface{}, len(d.fields))
+	pos := 1
+	for _, f := range d.fields {
+		val, err := f.value(d.record[pos : pos+f.length])
+		if err != nil {
+			return nil, false, newKindError(ErrFormatMismatch, fmt.Sprintf("dbf record %d, field %s: %s", d.read, f.name, err))
+		}
+		props[f.name] = val
+		pos += f.length
+	}
+	return props, false, nil
+}
+
+// value parses a field value. blank numbers, booleans & dates are nil
+func (f dbfField) value(raw []byte) (interface{}, error) {
+	switch f.kind {
+	case 'N', 'F':
+		s := strings.TrimSpace(string(raw))
+		if s == "" || strings.Trim(s, "*") == "" {
+			return nil, nil
+		}
+		if f.kind == 'N' && f.decimals == 0 {
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return int(i), nil
+			}
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number: %q", s)
+		}
+		return n, nil
+	case 'L':
+		switch strings.TrimSpace(string(raw)) {
+		case "Y", "y", "T", "t":
+			return true, nil
+		case "N", "n", "F", "f":
+			return false, nil
+		}
+		return nil, nil
+	case 'D':
+		s := strings.TrimSpace(string(raw))
+		if s == "" || strings.Trim(s, "0") == "" {
+			return nil, nil
+		}
+		if len(s) != 8 {
+			return nil, fmt.Errorf("invalid date: %q", s)
+		}
+		return s[:4] + "-" + s[4:6] + "-" + s[6:], nil
+	}
+	return dbfString(bytes.TrimRight(raw, "\x00 ")), nil
+}
+
+// dbfString decodes dBASE text. text that isn't UTF-8 is read as latin-1,
+// the most common code page of dbf files
+func dbfString(b []byte) string {
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// EntriesRead gives the number of entries read
+func (r *ShapefileReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes read from the source, or from
+// the .shp file of pair readers
+func (r *ShapefileReader) BytesProcessed() int64 {
+	if r.src != nil {
+		return int64(r.src.BytesRead())
+	}
+	return int64(r.shpSrc.BytesRead())
+}
+
+// Close finalizes the reader, closing the source if it's an io.Closer. wrap
+// the source with KeepOpen to leave it open
+func (r *ShapefileReader) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	if r.src != nil {
+		if cerr := r.src.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
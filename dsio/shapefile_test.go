@@ -0,0 +1,335 @@
+package dsio
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+var shapefileStructure = &dataset.Structure{
+	Format: "shapefile",
+	Schema: dataset.BaseSchemaArray,
+}
+
+// shpTestFile builds a .shp file from the content of shape records
+func shpTestFile(records ...[]byte) []byte {
+	buf := make([]byte, shpHeaderSize)
+	binary.BigEndian.PutUint32(buf, shpFileCode)
+	binary.LittleEndian.PutUint32(buf[28:], 1000)
+	for i, rec := range records {
+		header := make([]byte, 8)
+		binary.BigEndian.PutUint32(header, uint32(i+1))
+		binary.BigEndian.PutUint32(header[4:], uint32(len(rec)/2))
+		buf = append(buf, header...)
+		buf = append(buf, rec...)
+	}
+	binary.BigEndian.PutUint32(buf[24:], uint32(len(buf)/2))
+	return buf
+}
+
+// shpTestRecord builds shape record content from a shape type & values.
+// ints are written as int32, floats as float64
+func shpTestRecord(shapeType int32, vals ...interface{}) []byte {
+	buf := binary.LittleEndian.AppendUint32(nil, uint32(shapeType))
+	for _, v := range vals {
+		switch n := v.(type) {
+		case int:
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(n))
+		case float64:
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(n))
+		}
+	}
+	return buf
+}
+
+// dbfTestFile builds a dBASE III file. fields are name, type, length &
+// decimal count. records start with their deletion flag
+func dbfTestFile(fields []dbfField, records ...string) []byte {
+	recordLen := 1
+	for _, f := range fields {
+		recordLen += f.length
+	}
+	headerLen := 32 + 32*len(fields) + 1
+	buf := make([]byte, 32)
+	buf[0] = 0x03
+	binary.LittleEndian.PutUint32(buf[4:], uint32(len(records)))
+	binary.LittleEndian.PutUint16(buf[8:], uint16(headerLen))
+	binary.LittleEndian.PutUint16(buf[10:], uint16(recordLen))
+	for _, f := range fields {
+		desc := make([]byte, 32)
+		copy(desc, f.name)
+		desc[11] = f.kind
+		desc[16] = byte(f.length)
+		desc[17] = byte(f.decimals)
+		buf = append(buf, desc...)
+	}
+	buf = append(buf, 0x0D)
+	for _, rec := range records {
+		buf = append(buf, rec...)
+	}
+	return append(buf, 0x1A)
+}
+
+// dbfTestRecord formats a record of dbfTestFields
+func dbfTestRecord(flag, name, pop, area, ok, day string) string {
+	return fmt.Sprintf("%s%-8s%5s%6s%1s%8s", flag, name, pop, area, ok, day)
+}
+
+var dbfTestFields = []dbfField{
+	{name: "NAME", kind: 'C', length: 8},
+	{name: "POP", kind: 'N', length: 5},
+	{name: "AREA", kind: 'N', length: 6, decimals: 2},
+	{name: "OK", kind: 'L', length: 1},
+	{name: "DAY", kind: 'D', length: 8},
+}
+
+func shapefileTestData() (shp, dbf []byte) {
+	box := []interface{}{0.0, 0.0, 10.0, 10.0}
+	shp = shpTestFile(
+		shpTestRecord(shpPoint, 1.5, 2.5),
+		shpTestRecord(shpNull),
+		// deleted in the dbf file
+		shpTestRecord(shpPoint, 0.0, 0.0),
+		shpTestRecord(shpPolyLine, append(box, 2, 4, 0, 2,
+			0.0, 0.0, 1.0, 1.0,
+			5.0, 5.0, 6.0, 6.0)...),
+		// a clockwise outer ring with a counterclockwise hole
+		shpTestRecord(shpPolygon, append(box, 2, 10, 0, 5,
+			0.0, 0.0, 0.0, 10.0, 10.0, 10.0, 10.0, 0.0, 0.0, 0.0,
+			2.0, 2.0, 4.0, 2.0, 4.0, 4.0, 2.0, 4.0, 2.0, 2.0)...),
+		// two clockwise outer rings
+		shpTestRecord(shpPolygon, append(box, 2, 8, 0, 4,
+			0.0, 0.0, 0.0, 1.0, 1.0, 0.0, 0.0, 0.0,
+			5.0, 5.0, 5.0, 6.0, 6.0, 5.0, 5.0, 5.0)...),
+		shpTestRecord(shpMultiPointZ, append(box, 2,
+			1.0, 2.0, 3.0, 4.0,
+			0.0, 9.0, 7.0, 9.0)...),
+	)
+	dbf = dbfTestFile(dbfTestFields,
+		dbfTestRecord(" ", "dinagat", "12", "1.50", "T", "20240131"),
+		dbfTestRecord(" ", "null", "", "******", "?", ""),
+		dbfTestRecord("*", "deleted", "0", "0.00", "F", "20240101"),
+		dbfTestRecord(" ", "line", "-3", "12.00", "n", "00000000"),
+		dbfTestRecord(" ", "caf\xe9", "5", "0.25", "Y", "19991231"),
+		dbfTestRecord(" ", "two", "2", "2.00", "F", ""),
+		dbfTestRecord(" ", "multi", "7", "7.00", "t", "20000229"),
+	)
+	return shp, dbf
+}
+
+func TestShapefileReader(t *testing.T) {
+	shp, dbf := shapefileTestData()
+	r, err := NewShapefilePairReader(shapefileStructure, bytes.NewReader(shp), bytes.NewReader(dbf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pt := func(x, y float64) []interface{} { return []interface{}{x, y} }
+	expect := []interface{}{
+		map[string]interface{}{
+			"geometry":   map[string]interface{}{"type": "Point", "coordinates": pt(1.5, 2.5)},
+			"properties": map[string]interface{}{"NAME": "dinagat", "POP": 12, "AREA": 1.5, "OK": true, "DAY": "2024-01-31"},
+		},
+		map[string]interface{}{
+			"geometry":   nil,
+			"properties": map[string]interface{}{"NAME": "null", "POP": nil, "AREA": nil, "OK": nil, "DAY": nil},
+		},
+		map[string]interface{}{
+			"geometry": map[string]interface{}{"type": "MultiLineString", "coordinates": []interface{}{
+				[]interface{}{pt(0, 0), pt(1, 1)},
+				[]interface{}{pt(5, 5), pt(6, 6)},
+			}},
+			"properties": map[string]interface{}{"NAME": "line", "POP": -3, "AREA": 12.0, "OK": false, "DAY": nil},
+		},
+		map[string]interface{}{
+			"geometry": map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{
+				[]interface{}{pt(0, 0), pt(10, 0), pt(10, 10), pt(0, 10), pt(0, 0)},
+				[]interface{}{pt(2, 2), pt(2, 4), pt(4, 4), pt(4, 2), pt(2, 2)},
+			}},
+			"properties": map[string]interface{}{"NAME": "café", "POP": 5, "AREA": 0.25, "OK": true, "DAY": "1999-12-31"},
+		},
+		map[string]interface{}{
+			"geometry": map[string]interface{}{"type": "MultiPolygon", "coordinates": []interface{}{
+				[]interface{}{[]interface{}{pt(0, 0), pt(1, 0), pt(0, 1), pt(0, 0)}},
+				[]interface{}{[]interface{}{pt(5, 5), pt(6, 5), pt(5, 6), pt(5, 5)}},
+			}},
+			"properties": map[string]interface{}{"NAME": "two", "POP": 2, "AREA": 2.0, "OK": false, "DAY": nil},
+		},
+		map[string]interface{}{
+			"geometry": map[string]interface{}{"type": "MultiPoint", "coordinates": []interface{}{
+				[]interface{}{1.0, 2.0, 7.0},
+				[]interface{}{3.0, 4.0, 9.0},
+			}},
+			"properties": map[string]interface{}{"NAME": "multi", "POP": 7, "AREA": 7.0, "OK": true, "DAY": "2000-02-29"},
+		},
+	}
+	for i, ex := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("entry %d unexpected error: %s", i, err)
+		}
+		if ent.Index != i {
+			t.Errorf("entry %d index mismatch, got: %d", i, ent.Index)
+		}
+		if !reflect.DeepEqual(ent.Value, ex) {
+			t.Errorf("entry %d mismatch.\nexpected: %#v\ngot:      %#v", i, ex, ent.Value)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+	if r.BytesProcessed() != int64(len(shp)) {
+		t.Errorf("expected %d bytes processed, got: %d", len(shp), r.BytesProcessed())
+	}
+
+	schema := r.Schema()
+	props := schema["items"].(map[string]interface{})["properties"].(map[string]interface{})["properties"].(map[string]interface{})["properties"]
+	expectProps := map[string]interface{}{
+		"NAME": map[string]interface{}{"type": "string"},
+		"POP":  map[string]interface{}{"type": []interface{}{"integer", "null"}},
+		"AREA": map[string]interface{}{"type": []interface{}{"number", "null"}},
+		"OK":   map[string]interface{}{"type": []interface{}{"boolean", "null"}},
+		"DAY":  map[string]interface{}{"type": []interface{}{"string", "null"}},
+	}
+	if !reflect.DeepEqual(props, expectProps) {
+		t.Errorf("schema properties mismatch.\nexpected: %#v\ngot:      %#v", expectProps, props)
+	}
+}
+
+func TestShapefileReaderArchive(t *testing.T) {
+	shp, dbf := shapefileTestData()
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, data := range map[string][]byte{"roads/roads.shp": shp, "roads/roads.dbf": dbf, "roads/roads.prj": []byte("GEOGCS[]")} {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	size := buf.Len()
+	r, err := NewEntryReader(shapefileStructure, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	if err := EachEntry(r, func(int, Entry, error) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 6 {
+		t.Errorf("expected 6 entries, got: %d", count)
+	}
+	if r.(*ShapefileReader).BytesProcessed() != int64(size) {
+		t.Errorf("expected %d bytes processed, got: %d", size, r.(*ShapefileReader).BytesProcessed())
+	}
+	if err := r.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShapefileReaderBareShp(t *testing.T) {
+	shp, _ := shapefileTestData()
+	r, err := NewEntryReader(shapefileStructure, bytes.NewReader(shp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := r.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"geometry":   map[string]interface{}{"type": "Point", "coordinates": []interface{}{1.5, 2.5}},
+		"properties": nil,
+	}
+	if !reflect.DeepEqual(ent.Value, expect) {
+		t.Errorf("entry mismatch. expected: %#v, got: %#v", expect, ent.Value)
+	}
+	entries, err := ReadEntries(r, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// without a dbf file there are no deleted records to skip
+	if len(entries) != 6 {
+		t.Errorf("expected 6 more entries, got: %d", len(entries))
+	}
+}
+
+func TestShapefileReaderErrors(t *testing.T) {
+	shp, dbf := shapefileTestData()
+	truncated := shpTestFile(shpTestRecord(shpPoint, 1.0))
+	badCount := shpTestFile(shpTestRecord(shpPolyLine, 0.0, 0.0, 1.0, 1.0, 5))
+	unsupported := shpTestFile(shpTestRecord(31))
+	oneRecord := dbfTestFile(dbfTestFields, dbfTestRecord(" ", "dinagat", "12", "1.50", "T", "20240131"))
+	badNumber := dbfTestFile(dbfTestFields, dbfTestRecord(" ", "dinagat", "1x", "1.50", "T", "20240131"))
+
+	archive := func(names ...string) []byte {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		for _, name := range names {
+			f, _ := zw.Create(name)
+			f.Write(shp)
+		}
+		zw.Close()
+		return buf.Bytes()
+	}
+
+	cases := []struct {
+		st       *dataset.Structure
+		shp, dbf []byte
+		err      string
+	}{
+		{&dataset.Structure{Format: "shapefile"}, shp, nil, "schema required for shapefiles"},
+		{&dataset.Structure{Format: "shapefile", Schema: dataset.BaseSchemaObject}, shp, nil, "shapefiles require a top-level array schema"},
+		{shapefileStructure, []byte("nope"), nil, "not a shapefile: reading header"},
+		{shapefileStructure, make([]byte, 100), nil, "not a shapefile: bad file code"},
+		{shapefileStructure, shp, []byte("short"), "not a dbf file: reading header"},
+		{shapefileStructure, truncated, nil, "shape record 1: unexpected end of record"},
+		{shapefileStructure, badCount, nil, "shape record 1: invalid count: 5"},
+		{shapefileStructure, unsupported, nil, "shape record 1: unsupported shape type: 31"},
+		{shapefileStructure, shp, oneRecord, "dbf file has 1 records, shapefile has more"},
+		{shapefileStructure, shpTestFile(shpTestRecord(shpNull)), dbf, "dbf file has 7 records, shapefile has 1"},
+		{shapefileStructure, shp, badNumber, "dbf record 1, field POP: invalid number: \"1x\""},
+		{shapefileStructure, archive("a.shp", "b.shp"), nil, "shapefile archive has more than one .shp file"},
+		{shapefileStructure, archive("a.txt"), nil, "shapefile archive has no .shp file"},
+		{shapefileStructure, archive("a.shp"), nil, "shapefile archive has no .dbf file for a.shp"},
+	}
+
+	for i, c := range cases {
+		var (
+			r   *ShapefileReader
+			err error
+		)
+		if c.dbf != nil {
+			r, err = NewShapefilePairReader(c.st, bytes.NewReader(c.shp), bytes.NewReader(c.dbf))
+		} else {
+			r, err = NewShapefileReader(c.st, bytes.NewReader(c.shp))
+		}
+		if err == nil {
+			for err == nil {
+				_, err = r.ReadEntry()
+			}
+		}
+		if err == nil || err == io.EOF || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+
+	if _, err := NewEntryWriter(shapefileStructure, io.Discard); err == nil || err.Error() != "shapefile is a read-only format" {
+		t.Errorf("expected read-only error, got: %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package dsio
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// snappy block format encoding & decoding, as used by parquet column chunks.
+// see https://github.com/google/snappy/blob/main/format_description.txt
+
+const (
+	snappyTagLiteral = 0x00
+	snappyTagCopy1   = 0x01
+	snappyTagCopy2   = 0x02
+	snappyTagCopy4   = 0x03
+
+	// snappyMaxOffset is the largest offset copies are encoded with, keeping
+	// copies to the 2-byte offset form
+	snappyMaxOffset = 1<<16 - 1
+	snappyHashBits  = 14
+)
+
+// snappyEncode compresses src as a snappy block
+func snappyEncode(src []byte) []byte {
+	dst := make([]byte, binary.MaxVarintLen64, len(src)+len(src)/6+binary.MaxVarintLen64)
+	dst = dst[:binary.PutUvarint(dst, uint64(len(src)))]
+	if len(src) < 4 {
+		return snappyLiteral(dst, src)
+	}
+
+	var table [1 << snappyHashBits]int32
+	hash := func(u uint32) uint32 { return (u * 0x1e35a7bd) >> (32 - snappyHashBits) }
+	// lit marks the start of bytes that haven't been emitted
+	lit := 0
+	for i := 0; i+4 <= len(src); {
+		u := binary.LittleEndian.Uint32(src[i:])
+		h := hash(u)
+		cand := int(table[h]) - 1
+		table[h] = int32(i + 1)
+		if cand < 0 || i-cand > snappyMaxOffset || binary.LittleEndian.Uint32(src[cand:]) != u {
+			i++
+			continue
+		}
+
+		dst = snappyLiteral(dst, src[lit:i])
+		length := 4
+		for i+length < len(src) && src[cand+length] == src[i+length] {
+			length++
+		}
+		dst = snappyCopy(dst, i-cand, length)
+		i += length
+		lit = i
+	}
+	return snappyLiteral(dst, src[lit:])
+}
+
+// snappyLiteral appends a literal element
+func snappyLiteral(dst, lit []byte) []byte {
+	if len(lit) == 0 {
+		return dst
+	}
+	n := len(lit) - 1
+	switch {
+	case n < 60:
+		dst = append(dst, byte(n)<<2|snappyTagLiteral)
+	case n < 1<<8:
+		dst = append(dst, 60<<2|snappyTagLiteral, byte(n))
+	case n < 1<<16:
+		dst = append(dst, 61<<2|snappyTagLiteral, byte(n), byte(n>>8))
+	case n < 1<<24:
+		dst = append(dst, 62<<2|snappyTagLiteral, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		dst = append(dst, 63<<2|snappyTagLiteral, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(dst, lit...)
+}
+
+// snappyCopy appends copy elements for a match, splitting matches longer
+// than the 64 bytes a copy can hold
+func snappyCopy(dst []byte, offset, length int) []byte {
+	for length > 0 {
+		n := length
+		if n > 64 {
+			n = 64
+			// leave at least 4 bytes, the shortest match the encoder finds
+			if length-n < 4 {
+				n = length - 4
+			}
+		}
+		dst = append(dst, byte(n-1)<<2|snappyTagCopy2, byte(offset), byte(offset>>8))
+		length -= n
+	}
+	return dst
+}
+
+// snappyDecode decompresses a snappy block
+func snappyDecode(src []byte) ([]byte, error) {
+	dlen, n := binary.Uvarint(src)
+	if n <= 0 || dlen > 1<<32 {
+		return nil, fmt.Errorf("snappy: invalid block header")
+	}
+	src = src[n:]
+	dst := make([]byte, 0, dlen)
+
+	for len(src) > 0 {
+		tag := src[0]
+		var length, offset int
+		switch tag & 0x03 {
+		case snappyTagLiteral:
+			length = int(tag >> 2)
+			src = src[1:]
+			if length >= 60 {
+				nb := length - 59
+				if len(src) < nb {
+					return nil, fmt.Errorf("snappy: corrupt literal")
+				}
+				length = 0
+				for i := nb - 1; i >= 0; i-- {
+					length = length<<8 | int(src[i])
+				}
+				src = src[nb:]
+			}
+			length++
+			if length > len(src) || len(dst)+length > int(dlen) {
+				return nil, fmt.Errorf("snappy: corrupt literal")
+			}
+			dst = append(dst, src[:length]...)
+			src = src[length:]
+			continue
+		case snappyTagCopy1:
+			if len(src) < 2 {
+				return nil, fmt.Errorf("snappy: corrupt copy")
+			}
+			length = 4 + int(tag>>2)&0x07
+			offset = int(tag>>5)<<8 | int(src[1])
+			src = src[2:]
+		case snappyTagCopy2:
+			if len(src) < 3 {
+				return nil, fmt.Errorf("snappy: corrupt copy")
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[1:]))
+			src = src[3:]
+		case snappyTagCopy4:
+			if len(src) < 5 {
+				return nil, fmt.Errorf("snappy: corrupt copy")
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[1:]))
+			src = src[5:]
+		}
+		if offset <= 0 || offset > len(dst) || len(dst)+length > int(dlen) {
+			return nil, fmt.Errorf("snappy: corrupt copy")
+		}
+		// copies can overlap their own output, so copy byte by byte
+		start := len(dst) - offset
+		for i := 0; i < length; i++ {
+			dst = append(dst, dst[start+i])
+		}
+	}
+	if len(dst) != int(dlen) {
+		return nil, fmt.Errorf("snappy: decoded length mismatch")
+	}
+	return dst, nil
+}
@@ -0,0 +1,63 @@
+package dsio
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestSnappyRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	random := make([]byte, 100000)
+	rnd.Read(random)
+
+	cases := [][]byte{
+		{},
+		[]byte("a"),
+		[]byte("abcd"),
+		bytes.Repeat([]byte("a"), 1000),
+		bytes.Repeat([]byte("hello, parquet! "), 5000),
+		random,
+		append(bytes.Repeat([]byte("xyz"), 30000), random[:70000]...),
+	}
+	for i, c := range cases {
+		enc := snappyEncode(c)
+		dec, err := snappyDecode(enc)
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if !bytes.Equal(c, dec) {
+			t.Errorf("case %d round trip mismatch", i)
+		}
+	}
+
+	if enc := snappyEncode(bytes.Repeat([]byte("a"), 1000)); len(enc) > 100 {
+		t.Errorf("expected repeated data to compress, got %d bytes", len(enc))
+	}
+}
+
+func TestSnappyDecode(t *testing.T) {
+	cases := []struct {
+		data   []byte
+		expect string
+		err    string
+	}{
+		{[]byte{0x04, 0x0c, 'a', 'b', 'c', 'd'}, "abcd", ""},
+		{[]byte{0x08, 0x0c, 'a', 'b', 'c', 'd', 0x01, 0x04}, "abcdabcd", ""},
+		{[]byte{0x08, 0x04, 'a', 'b', 0x09, 0x02}, "abababab", ""},
+		{[]byte{0x08, 0x0c, 'a', 'b', 'c', 'd', 0x01, 0x09}, "", "snappy: corrupt copy"},
+		{[]byte{0x08, 0x0c, 'a', 'b'}, "", "snappy: corrupt literal"},
+		{[]byte{}, "", "snappy: invalid block header"},
+	}
+	for i, c := range cases {
+		got, err := snappyDecode(c.data)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if string(got) != c.expect {
+			t.Errorf("case %d mismatch. expected: %q, got: %q", i, c.expect, got)
+		}
+	}
+}
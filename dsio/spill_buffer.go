@@ -0,0 +1,229 @@
+package dsio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/qri-io/dataset"
+	"github.com/ugorji/go/codec"
+)
+
+// SpillConfig encapsulates configuration for a SpillBuffer
+type SpillConfig struct {
+	// MemEntries is the number of entries held in memory before later
+	// entries spill to disk. default 10000
+	MemEntries int
+	// Dir is the directory spill files are created in. default is the
+	// system temp directory
+	Dir string
+}
+
+// SpillBuffer is an EntryWriter & EntryReader that holds the first entries
+// written in memory, spilling the rest to a temporary file as an
+// indefinite-length CBOR array. Buffers are written in full, closed, then
+// read as many times as needed with Rewind, which makes two-pass algorithms
+// like sorting or computing stats before writing possible on bodies larger
+// than memory.
+//
+// Spilled entries are read back as CBORReader reads them, with integers as
+// int. Remove deletes the spill file, buffers that have spilled must be
+// removed to release disk space
+type SpillBuffer struct {
+	st  *dataset.Structure
+	cfg SpillConfig
+	mem []Entry
+
+	file    *os.File
+	bw      *bufio.Writer
+	enc     *codec.Encoder
+	spilled int
+	closed  bool
+
+	// pos is the read position in memory entries
+	pos  int
+	rdr  *CBORReader
+	read int
+}
+
+var (
+	_ EntryWriter = (*SpillBuffer)(nil)
+	_ EntryReader = (*SpillBuffer)(nil)
+)
+
+// NewSpillBuffer creates an empty spill buffer. st is the structure of the
+// buffered entries, entries are neither validated against it nor encoded in
+// it's format
+func NewSpillBuffer(st *dataset.Structure, configs ...func(cfg *SpillConfig)) *SpillBuffer {
+	cfg := &SpillConfig{
+		MemEntries: 10000,
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	if cfg.MemEntries < 0 {
+		cfg.MemEntries = 0
+	}
+	return &SpillBuffer{st: st, cfg: *cfg}
+}
+
+// Structure gives the structure of buffered entries
+func (b *SpillBuffer) Structure() *dataset.Structure {
+	return b.st
+}
+
+// WriteEntry adds an entry to the buffer, spilling it to disk once the buffer
+// holds MemEntries entries in memory
+func (b *SpillBuffer) WriteEntry(ent Entry) error {
+	if b.closed {
+		return fmt.Errorf("write to closed buffer")
+	}
+	if len(b.mem) < b.cfg.MemEntries {
+		b.mem = append(b.mem, ent)
+		return nil
+	}
+
+	if b.file == nil {
+		f, err := os.CreateTemp(b.cfg.Dir, "dsio-spill-*.cbor")
+		if err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("error creating spill file: %w", err)
+		}
+		b.file = f
+		b.bw = bufio.NewWriter(f)
+		b.enc = codec.NewEncoder(b.bw, &codec.CborHandle{TimeRFC3339: true})
+		b.bw.WriteByte(cborBdIndefiniteArray)
+	}
+	if err := b.enc.Encode([]interface{}{ent.Index, ent.Key, ent.Value}); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error spilling entry %d: %w", ent.Index, err)
+	}
+	b.spilled++
+	return nil
+}
+
+// EntriesWritten gives the number of entries written to the buffer
+func (b *SpillBuffer) EntriesWritten() int {
+	return len(b.mem) + b.spilled
+}
+
+// Spilled gives the number of entries written to disk
+func (b *SpillBuffer) Spilled() int {
+	return b.spilled
+}
+
+// Close finishes writing, readying the buffer for reading. Closing a closed
+// buffer does nothing, use Remove to delete the spill file
+func (b *SpillBuffer) Close() error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	if b.file == nil {
+		return nil
+	}
+	b.bw.WriteByte(cborBdBreak)
+	if err := b.bw.Flush(); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing spill file: %w", err)
+	}
+	return nil
+}
+
+// ReadEntry reads the next buffered entry. Buffers must be closed before
+// they're read
+func (b *SpillBuffer) ReadEntry() (Entry, error) {
+	if !b.closed {
+		return Entry{}, fmt.Errorf("spill buffer must be closed before reading")
+	}
+	if b.pos < len(b.mem) {
+		ent := b.mem[b.pos]
+		b.pos++
+		b.read++
+		return ent, nil
+	}
+	if b.file == nil {
+		return Entry{}, io.EOF
+	}
+
+	if b.rdr == nil {
+		if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+			log.Debug(err.Error())
+			return Entry{}, fmt.Errorf("error reading spill file: %w", err)
+		}
+		st := &dataset.Structure{Format: dataset.CBORDataFormat.String(), Schema: dataset.BaseSchemaArray}
+		rdr, err := NewCBORReader(st, b.file)
+		if err != nil {
+			return Entry{}, err
+		}
+		b.rdr = rdr
+	}
+	spilled, err := b.rdr.ReadEntry()
+	if err != nil {
+		if err != io.EOF {
+			log.Debug(err.Error())
+			err = fmt.Errorf("error reading spill file: %w", err)
+		}
+		return Entry{}, err
+	}
+	tuple, ok := spilled.Value.([]interface{})
+	if !ok || len(tuple) != 3 {
+		return Entry{}, fmt.Errorf("error reading spill file: invalid entry %d", spilled.Index)
+	}
+	index, _ := tuple[0].(int64)
+	key, _ := tuple[1].(string)
+	b.read++
+	return Entry{Index: int(index), Key: key, Value: spillValue(tuple[2])}, nil
+}
+
+// spillValue converts integers decoded from a spill file to int
+func spillValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case int64:
+		return int(t)
+	case []interface{}:
+		for i, val := range t {
+			t[i] = spillValue(val)
+		}
+	case map[string]interface{}:
+		for key, val := range t {
+			t[key] = spillValue(val)
+		}
+	}
+	return v
+}
+
+// EntriesRead gives the number of entries read since the buffer was closed
+// or last rewound
+func (b *SpillBuffer) EntriesRead() int {
+	return b.read
+}
+
+// Rewind resets reading to the first entry of the buffer
+func (b *SpillBuffer) Rewind() {
+	b.pos = 0
+	b.rdr = nil
+	b.read = 0
+}
+
+// Remove deletes the buffer's spill file & drops entries held in memory.
+// Removed buffers are empty
+func (b *SpillBuffer) Remove() error {
+	b.closed = true
+	b.mem = nil
+	b.Rewind()
+	if b.file == nil {
+		return nil
+	}
+	name := b.file.Name()
+	err := b.file.Close()
+	b.file, b.bw, b.enc, b.spilled = nil, nil, nil, 0
+	if rerr := os.Remove(name); rerr != nil && err == nil {
+		err = rerr
+	}
+	if err != nil {
+		log.Debug(err.Error())
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package dsio
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestSpillBuffer(t *testing.T) {
+	dir := t.TempDir()
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	b := NewSpillBuffer(st, func(cfg *SpillConfig) {
+		cfg.MemEntries = 2
+		cfg.Dir = dir
+	})
+
+	fetched := time.Date(2024, 1, 31, 12, 30, 0, 0, time.UTC)
+	entries := []Entry{
+		{Index: 0, Value: []interface{}{1, "a"}},
+		{Index: 1, Value: map[string]interface{}{"in": "memory"}},
+		{Index: 2, Value: []interface{}{-1, -25, -70000, 1 << 40, 0.5, true, false, nil}},
+		{Index: 3, Key: "k", Value: map[string]interface{}{"nested": []interface{}{map[string]interface{}{"a": 1}}}},
+		{Index: 4, Value: strings.Repeat("long ", 2000)},
+		{Index: 5, Value: fetched},
+		{Index: 6, Value: []byte("raw")},
+	}
+	for _, ent := range entries {
+		if err := b.WriteEntry(ent); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if b.EntriesWritten() != len(entries) {
+		t.Errorf("expected %d entries written, got: %d", len(entries), b.EntriesWritten())
+	}
+	if b.Spilled() != 5 {
+		t.Errorf("expected 5 spilled entries, got: %d", b.Spilled())
+	}
+	if _, err := b.ReadEntry(); err == nil || err.Error() != "spill buffer must be closed before reading" {
+		t.Errorf("expected read before close error, got: %v", err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.WriteEntry(Entry{}); err == nil {
+		t.Error("expected write after close to error")
+	}
+
+	// read twice, as two-pass algorithms do
+	for pass := 0; pass < 2; pass++ {
+		for i, ex := range entries {
+			ent, err := b.ReadEntry()
+			if err != nil {
+				t.Fatalf("pass %d entry %d unexpected error: %s", pass, i, err)
+			}
+			if !reflect.DeepEqual(ent, ex) {
+				t.Errorf("pass %d entry %d mismatch.\nexpected: %#v\ngot:      %#v", pass, i, ex, ent)
+			}
+		}
+		if _, err := b.ReadEntry(); err != io.EOF {
+			t.Errorf("pass %d expected io.EOF, got: %v", pass, err)
+		}
+		if b.EntriesRead() != len(entries) {
+			t.Errorf("pass %d expected %d entries read, got: %d", pass, len(entries), b.EntriesRead())
+		}
+		b.Rewind()
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected one spill file, got: %d", len(files))
+	}
+	if err := b.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ = os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expected remove to delete spill file, found: %d files", len(files))
+	}
+	if _, err := b.ReadEntry(); err != io.EOF {
+		t.Errorf("expected removed buffer to be empty, got: %v", err)
+	}
+}
+
+func TestSpillBufferInMemory(t *testing.T) {
+	dir := t.TempDir()
+	b := NewSpillBuffer(&dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}, func(cfg *SpillConfig) {
+		cfg.Dir = dir
+	})
+	for i := 0; i < 10; i++ {
+		if err := b.WriteEntry(Entry{Index: i, Value: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadEntries(b, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 10 {
+		t.Errorf("expected 10 entries, got: %d", len(entries))
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expected no spill file, found: %d files", len(files))
+	}
+	if err := b.Remove(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpillBufferCreateError(t *testing.T) {
+	b := NewSpillBuffer(&dataset.Structure{}, func(cfg *SpillConfig) {
+		cfg.MemEntries = 0
+		cfg.Dir = "/path/that/does/not/exist"
+	})
+	if err := b.WriteEntry(Entry{Value: 1}); err == nil || !strings.HasPrefix(err.Error(), "error creating spill file") {
+		t.Errorf("expected create error, got: %v", err)
+	}
+}
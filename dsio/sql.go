@@ -0,0 +1,182 @@
+package dsio
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/vals"
+)
+
+// SQLRowsReader implements the EntryReader interface for the results of a
+// database/sql query. Each result row is read as an array entry
+type SQLRowsReader struct {
+	st    *dataset.Structure
+	rows  *sql.Rows
+	types []string
+	idx   int
+}
+
+var _ EntryReader = (*SQLRowsReader)(nil)
+
+// NewSQLRowsReader creates an EntryReader from a set of sql rows, mapping
+// result column types to a schema. The returned structure has no data format,
+// callers should set one before using the structure to write a body
+func NewSQLRowsReader(rows *sql.Rows) (EntryReader, *dataset.Structure, error) {
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, nil, fmt.Errorf("reading column types: %w", err)
+	}
+
+	types := make([]string, len(cts))
+	items := make([]interface{}, len(cts))
+	for i, ct := range cts {
+		types[i] = sqlColumnType(ct)
+		items[i] = map[string]interface{}{
+			"title": ct.Name(),
+			"type":  types[i],
+		}
+	}
+
+	st := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": items,
+			},
+		},
+	}
+
+	return &SQLRowsReader{
+		st:    st,
+		rows:  rows,
+		types: types,
+	}, st, nil
+}
+
+// sqlColumnType maps a sql column type to a json schema type, prefering the
+// database type name & falling back to the go type a driver scans into
+func sqlColumnType(ct *sql.ColumnType) string {
+	if name := ct.DatabaseTypeName(); name != "" {
+		return sqlTypeNameType(name)
+	}
+
+	if t := ct.ScanType(); t != nil {
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return "integer"
+		case reflect.Float32, reflect.Float64:
+			return "number"
+		case reflect.Bool:
+			return "boolean"
+		}
+	}
+	return "string"
+}
+
+// sqlTypeNameType maps a database type name to a json schema type. Size &
+// precision arguments & trailing modifiers are trimmed, so "INT(11) UNSIGNED"
+// & "DOUBLE PRECISION" match on INT & DOUBLE. Names must match exactly:
+// INTERVAL & POINT aren't integers. Unknown names are strings
+func sqlTypeNameType(name string) string {
+	name = strings.ToUpper(name)
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	if fields := strings.Fields(name); len(fields) > 0 {
+		name = fields[0]
+	}
+	switch name {
+	case "INT", "INTEGER", "BIGINT", "SMALLINT", "TINYINT", "MEDIUMINT",
+		"INT2", "INT4", "INT8", "SERIAL", "SMALLSERIAL", "BIGSERIAL":
+		return "integer"
+	case "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "REAL", "NUMERIC", "DECIMAL":
+		return "number"
+	case "BOOL", "BOOLEAN":
+		return "boolean"
+	}
+	return "string"
+}
+
+// Structure gives this reader's structure
+func (r *SQLRowsReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// EntriesRead gives the number of rows read
+func (r *SQLRowsReader) EntriesRead() int {
+	return r.idx
+}
+
+// ReadEntry reads one row from the result set
+func (r *SQLRowsReader) ReadEntry() (Entry, error) {
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			log.Debug(err.Error())
+			return Entry{}, err
+		}
+		return Entry{}, io.EOF
+	}
+
+	row := make([]interface{}, len(r.types))
+	ptrs := make([]interface{}, len(r.types))
+	for i := range row {
+		ptrs[i] = &row[i]
+	}
+	if err := r.rows.Scan(ptrs...); err != nil {
+		log.Debug(err.Error())
+		return Entry{}, fmt.Errorf("scanning row %d: %w", r.idx, err)
+	}
+
+	for i, v := range row {
+		row[i] = r.decode(r.types[i], v)
+	}
+
+	ent := Entry{Index: r.idx, Value: row}
+	r.idx++
+	return ent, nil
+}
+
+// decode converts driver values to the native go types used by other readers.
+// drivers commonly return numeric columns as raw bytes, which are parsed using
+// the column type. If parsing fails values are left as a string
+func (r *SQLRowsReader) decode(t string, v interface{}) interface{} {
+	switch x := v.(type) {
+	case []byte:
+		str := string(x)
+		switch t {
+		case "integer":
+			if num, err := vals.ParseInteger(x); err == nil {
+				return num
+			}
+		case "number":
+			if num, err := vals.ParseNumber(x); err == nil {
+				return num
+			}
+		case "boolean":
+			if b, err := vals.ParseBoolean(x); err == nil {
+				return b
+			}
+		}
+		return str
+	case int32:
+		return int64(x)
+	case float32:
+		return float64(x)
+	case time.Time:
+		return x.Format(time.RFC3339)
+	}
+	return v
+}
+
+// Close finalizes the reader, closing the underlying rows
+func (r *SQLRowsReader) Close() error {
+	return r.rows.Close()
+}
@@ -0,0 +1,180 @@
+package dsio
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+)
+
+// fakeDriver is a minimal database/sql driver for testing. Queries return
+// fakeResult, execs are recorded to fakeExecs. Commits fail with
+// fakeCommitErr when it's set
+type fakeDriver struct{}
+
+var (
+	fakeMu        sync.Mutex
+	fakeResult    *fakeRows
+	fakeExecs     []string
+	fakeCommitErr error
+)
+
+func init() {
+	sql.Register("dsio_fake", fakeDriver{})
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	if fakeCommitErr != nil {
+		return fakeCommitErr
+	}
+	fakeExecs = append(fakeExecs, "COMMIT")
+	return nil
+}
+
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct{ query string }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	fakeExecs = append(fakeExecs, fmt.Sprintf("%s %v", s.query, args))
+	return driver.RowsAffected(1), nil
+}
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	r := *fakeResult
+	return &r, nil
+}
+
+type fakeRows struct {
+	cols  []string
+	types []string
+	data  [][]driver.Value
+	i     int
+}
+
+func (r *fakeRows) Columns() []string                       { return r.cols }
+func (r *fakeRows) ColumnTypeDatabaseTypeName(i int) string { return r.types[i] }
+func (r *fakeRows) Close() error                            { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.i])
+	r.i++
+	return nil
+}
+
+func TestSQLRowsReader(t *testing.T) {
+	fakeResult = &fakeRows{
+		cols:  []string{"id", "name", "score", "active"},
+		types: []string{"BIGINT", "VARCHAR", "DECIMAL", "BOOL"},
+		data: [][]driver.Value{
+			{int64(1), []byte("a"), []byte("1.5"), true},
+			{int64(2), []byte("b"), nil, false},
+		},
+	}
+
+	db, err := sql.Open("dsio_fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT * FROM things")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, st, err := NewSQLRowsReader(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	titles, types, err := terribleHackToGetHeaderRowAndTypes(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectTitles := []string{"id", "name", "score", "active"}
+	expectTypes := []string{"integer", "string", "number", "boolean"}
+	for i := range expectTitles {
+		if titles[i] != expectTitles[i] {
+			t.Errorf("title %d mismatch. expected: %s, got: %s", i, expectTitles[i], titles[i])
+		}
+		if types[i] != expectTypes[i] {
+			t.Errorf("type %d mismatch. expected: %s, got: %s", i, expectTypes[i], types[i])
+		}
+	}
+
+	expect := [][]interface{}{
+		{int64(1), "a", 1.5, true},
+		{int64(2), "b", nil, false},
+	}
+	for i, row := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("row %d unexpected error: %s", i, err)
+		}
+		arr, ok := ent.Value.([]interface{})
+		if !ok {
+			t.Fatalf("row %d expected []interface{} value. got: %T", i, ent.Value)
+		}
+		for j, v := range row {
+			if arr[j] != v {
+				t.Errorf("row %d col %d mismatch. expected: %#v, got: %#v", i, j, v, arr[j])
+			}
+		}
+	}
+
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected EOF after last row, got: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSQLTypeNameType(t *testing.T) {
+	cases := []struct {
+		name, expect string
+	}{
+		{"INT", "integer"},
+		{"integer", "integer"},
+		{"BIGINT", "integer"},
+		{"INT(11) UNSIGNED", "integer"},
+		{"TINYINT(1)", "integer"},
+		{"INT8", "integer"},
+		{"BIGSERIAL", "integer"},
+		{"INTERVAL", "string"},
+		{"POINT", "string"},
+		{"_INT4", "string"},
+		{"DOUBLE PRECISION", "number"},
+		{"DECIMAL(10,2)", "number"},
+		{"FLOAT8", "number"},
+		{"BOOLEAN", "boolean"},
+		{"VARCHAR(255)", "string"},
+	}
+	for _, c := range cases {
+		if got := sqlTypeNameType(c.name); got != c.expect {
+			t.Errorf("%s: expected %s, got: %s", c.name, c.expect, got)
+		}
+	}
+}
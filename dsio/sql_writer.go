@@ -0,0 +1,288 @@
+package dsio
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// SQLDialect specifies the flavor of SQL a SQLWriter will emit
+type SQLDialect int
+
+const (
+	// SQLDialectSQLite uses "?" placeholders & sqlite column types
+	SQLDialectSQLite SQLDialect = iota
+	// SQLDialectPostgres uses numbered "$1" placeholders & postgres column types
+	SQLDialectPostgres
+	// SQLDialectMySQL uses "?" placeholders, backtick-quoted identifiers, and
+	// mysql column types
+	SQLDialectMySQL
+)
+
+// placeholder gives the bind parameter for the zero-indexed i-th argument
+func (d SQLDialect) placeholder(i int) string {
+	if d == SQLDialectPostgres {
+		return "$" + strconv.Itoa(i+1)
+	}
+	return "?"
+}
+
+// quote escapes an identifier like a table or column name
+func (d SQLDialect) quote(ident string) string {
+	if d == SQLDialectMySQL {
+		return "`" + strings.Replace(ident, "`", "``", -1) + "`"
+	}
+	return `"` + strings.Replace(ident, `"`, `""`, -1) + `"`
+}
+
+// columnType maps a json schema type to a column type
+func (d SQLDialect) columnType(t string) string {
+	switch t {
+	case "integer":
+		return "BIGINT"
+	case "number":
+		switch d {
+		case SQLDialectPostgres:
+			return "DOUBLE PRECISION"
+		case SQLDialectMySQL:
+			return "DOUBLE"
+		default:
+			return "REAL"
+		}
+	case "boolean":
+		return "BOOLEAN"
+	default:
+		return "TEXT"
+	}
+}
+
+// SQLWriterConfig encapsulates configuration for SQLWriter
+type SQLWriterConfig struct {
+	// Dialect of SQL to write, default is SQLDialectSQLite
+	Dialect SQLDialect
+	// BatchSize is the number of entries written per transaction. default 1000
+	BatchSize int
+	// CreateTable will create the destination table from the writer's structure
+	// if it doesn't exist. default true
+	CreateTable bool
+	// Budget, if set, bounds the bytes held by buffered rows. batches are
+	// flushed early when the budget is spent, so writes never wait for a
+	// budget shared with earlier stages, see MemoryBudget
+	Budget *MemoryBudget
+}
+
+// SQLWriter implements the EntryWriter interface, inserting entries as rows in
+// a database table. Rows are buffered & written in batched transactions
+type SQLWriter struct {
+	cfg         *SQLWriterConfig
+	st          *dataset.Structure
+	db          *sql.DB
+	table       string
+	titles      []string
+	insert      string
+	batch       [][]interface{}
+	batchSize   int64
+	rowsWritten int
+	accepted    int
+}
+
+var _ EntryWriter = (*SQLWriter)(nil)
+
+// NewSQLWriter creates an EntryWriter that inserts into table. The writer's
+// structure must have a schema describing an array of arrays with column
+// titles, like the schemas used for CSV data
+func NewSQLWriter(st *dataset.Structure, db *sql.DB, table string, configs ...func(cfg *SQLWriterConfig)) (*SQLWriter, error) {
+	cfg := &SQLWriterConfig{
+		BatchSize:   1000,
+		CreateTable: true,
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 1
+	}
+
+	titles, types, err := terribleHackToGetHeaderRowAndTypes(st)
+	if err != nil {
+		return nil, newKindError(ErrBadSchema, "sql writer requires a schema with column definitions")
+	}
+	for i, t := range titles {
+		if t == "" {
+			titles[i] = dataset.AbstractColumnName(i)
+		}
+	}
+
+	w := &SQLWriter{
+		cfg:    cfg,
+		st:     st,
+		db:     db,
+		table:  table,
+		titles: titles,
+	}
+
+	cols := make([]string, len(titles))
+	params := make([]string, len(titles))
+	for i, t := range titles {
+		cols[i] = cfg.Dialect.quote(t)
+		params[i] = cfg.Dialect.placeholder(i)
+	}
+	w.insert = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", cfg.Dialect.quote(table), strings.Join(cols, ", "), strings.Join(params, ", "))
+
+	if cfg.CreateTable {
+		defs := make([]string, len(titles))
+		for i := range titles {
+			defs[i] = cols[i] + " " + cfg.Dialect.columnType(types[i])
+		}
+		create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", cfg.Dialect.quote(table), strings.Join(defs, ", "))
+		if _, err := db.Exec(create); err != nil {
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("creating table %s: %w", table, err)
+		}
+	}
+
+	return w, nil
+}
+
+// Structure gives this writer's structure
+func (w *SQLWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// EntriesWritten gives the number of entries written, including buffered rows
+// that haven't been inserted yet
+func (w *SQLWriter) EntriesWritten() int {
+	return w.accepted
+}
+
+// WriteEntry buffers one row for insertion, flushing a batch to the database
+// when the buffer is full
+func (w *SQLWriter) WriteEntry(ent Entry) error {
+	return w.WriteEntryContext(context.Background(), ent)
+}
+
+// WriteEntryContext is WriteEntry, running any batch the entry flushes with
+// ctx. ConvertParallel & CopyContext write with their context
+func (w *SQLWriter) WriteEntryContext(ctx context.Context, ent Entry) error {
+	row, err := w.rowArgs(ent)
+	if err != nil {
+		return err
+	}
+	if w.cfg.Budget != nil {
+		size := w.cfg.Budget.clamp(EntrySize(ent))
+		if !w.cfg.Budget.TryAcquire(size) {
+			// flushing releases all of this writer's bytes, it then holds
+			// nothing & acquires without waiting
+			if err := w.flush(ctx); err != nil {
+				return err
+			}
+			if err := w.cfg.Budget.acquireStage(ctx, size, &w.batchSize); err != nil {
+				return err
+			}
+		} else {
+			w.batchSize += size
+		}
+	}
+	w.batch = append(w.batch, row)
+	w.accepted++
+	if len(w.batch) >= w.cfg.BatchSize {
+		return w.flush(ctx)
+	}
+	return nil
+}
+
+// rowArgs converts an entry value to a slice of driver arguments. entries can
+// be arrays of column values, or objects keyed by column title
+func (w *SQLWriter) rowArgs(ent Entry) ([]interface{}, error) {
+	row := make([]interface{}, len(w.titles))
+	switch v := ent.Value.(type) {
+	case []interface{}:
+		if len(v) > len(row) {
+			return nil, fmt.Errorf("entry %d has %d values, table has %d columns", ent.Index, len(v), len(row))
+		}
+		copy(row, v)
+	case map[string]interface{}:
+		for i, t := range w.titles {
+			row[i] = v[t]
+		}
+	default:
+		return nil, fmt.Errorf("expected array or object value to write sql row. got: %v", ent)
+	}
+
+	for i, v := range row {
+		switch x := v.(type) {
+		case int:
+			row[i] = int64(x)
+		case []interface{}, map[string]interface{}:
+			data, err := json.Marshal(x)
+			if err != nil {
+				return nil, err
+			}
+			row[i] = string(data)
+		}
+	}
+	return row, nil
+}
+
+// flush writes all buffered rows in a single transaction. The batch is
+// cleared whether or not the transaction commits, rows in a failed batch
+// aren't written & aren't counted
+func (w *SQLWriter) flush(ctx context.Context) error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+	n := len(w.batch)
+	defer func() {
+		w.batch = w.batch[:0]
+		if w.cfg.Budget != nil {
+			w.cfg.Budget.releaseStage(w.batchSize, &w.batchSize)
+		}
+	}()
+
+	if err := w.insertBatch(ctx); err != nil {
+		w.accepted -= n
+		return err
+	}
+	w.rowsWritten += n
+	return nil
+}
+
+// insertBatch inserts buffered rows & commits the transaction
+func (w *SQLWriter) insertBatch(ctx context.Context) error {
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, w.insert)
+	if err != nil {
+		tx.Rollback()
+		log.Debug(err.Error())
+		return fmt.Errorf("preparing insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, row := range w.batch {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			tx.Rollback()
+			log.Debug(err.Error())
+			return fmt.Errorf("inserting row %d: %w", w.rowsWritten+i, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("committing rows: %w", err)
+	}
+	return nil
+}
+
+// Close flushes any buffered rows to the database. Close does not close the
+// underlying database handle
+func (w *SQLWriter) Close() error {
+	return w.flush(context.Background())
+}
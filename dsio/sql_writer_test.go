@@ -0,0 +1,143 @@
+package dsio
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestSQLWriter(t *testing.T) {
+	st := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "name", "type": "string"},
+					map[string]interface{}{"title": "tags", "type": "array"},
+				},
+			},
+		},
+	}
+
+	db, err := sql.Open("dsio_fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cases := []struct {
+		dialect SQLDialect
+		expect  []string
+	}{
+		{SQLDialectPostgres, []string{
+			`CREATE TABLE IF NOT EXISTS "things" ("id" BIGINT, "name" TEXT, "tags" TEXT) []`,
+			`INSERT INTO "things" ("id", "name", "tags") VALUES ($1, $2, $3) [1 a ["x"]]`,
+			`INSERT INTO "things" ("id", "name", "tags") VALUES ($1, $2, $3) [2 b <nil>]`,
+			`COMMIT`,
+			`INSERT INTO "things" ("id", "name", "tags") VALUES ($1, $2, $3) [3 c <nil>]`,
+			`COMMIT`,
+		}},
+		{SQLDialectMySQL, []string{
+			"CREATE TABLE IF NOT EXISTS `things` (`id` BIGINT, `name` TEXT, `tags` TEXT) []",
+			"INSERT INTO `things` (`id`, `name`, `tags`) VALUES (?, ?, ?) [1 a [\"x\"]]",
+			"INSERT INTO `things` (`id`, `name`, `tags`) VALUES (?, ?, ?) [2 b <nil>]",
+			"COMMIT",
+			"INSERT INTO `things` (`id`, `name`, `tags`) VALUES (?, ?, ?) [3 c <nil>]",
+			"COMMIT",
+		}},
+	}
+
+	for i, c := range cases {
+		fakeExecs = nil
+		w, err := NewSQLWriter(st, db, "things", func(cfg *SQLWriterConfig) {
+			cfg.Dialect = c.dialect
+			cfg.BatchSize = 2
+		})
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+
+		ents := []Entry{
+			{Value: []interface{}{1, "a", []interface{}{"x"}}},
+			{Value: map[string]interface{}{"id": 2, "name": "b"}},
+			{Value: []interface{}{3, "c"}},
+		}
+		for _, ent := range ents {
+			if err := w.WriteEntry(ent); err != nil {
+				t.Fatalf("case %d unexpected error writing entry: %s", i, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("case %d unexpected error closing: %s", i, err)
+		}
+
+		if len(fakeExecs) != len(c.expect) {
+			t.Fatalf("case %d exec count mismatch. expected: %d, got: %d\n%v", i, len(c.expect), len(fakeExecs), fakeExecs)
+		}
+		for j, e := range c.expect {
+			if fakeExecs[j] != e {
+				t.Errorf("case %d exec %d mismatch.\nexpected: %s\ngot:      %s", i, j, e, fakeExecs[j])
+			}
+		}
+	}
+}
+
+func TestSQLWriterCommitError(t *testing.T) {
+	st := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+				},
+			},
+		},
+	}
+	db, err := sql.Open("dsio_fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	budget := NewMemoryBudget(1 << 20)
+	w, err := NewSQLWriter(st, db, "things", func(cfg *SQLWriterConfig) {
+		cfg.BatchSize = 2
+		cfg.Budget = budget
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fakeCommitErr = fmt.Errorf("disk full")
+	defer func() { fakeCommitErr = nil }()
+	if err := w.WriteEntry(Entry{Value: []interface{}{1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Value: []interface{}{2}}); err == nil {
+		t.Fatal("expected a failed commit to error")
+	}
+	// rows of the failed batch are dropped & not counted
+	if w.EntriesWritten() != 0 || w.rowsWritten != 0 || len(w.batch) != 0 {
+		t.Errorf("expected failed batch to be cleared. entries: %d, rows: %d, buffered: %d", w.EntriesWritten(), w.rowsWritten, len(w.batch))
+	}
+	if budget.Used() != 0 {
+		t.Errorf("expected failed batch to release its budget, %d bytes held", budget.Used())
+	}
+
+	fakeCommitErr = nil
+	fakeExecs = nil
+	if err := w.WriteEntry(Entry{Value: []interface{}{3}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.EntriesWritten() != 1 || w.rowsWritten != 1 || len(fakeExecs) != 2 {
+		t.Errorf("expected one row written. entries: %d, rows: %d, execs: %v", w.EntriesWritten(), w.rowsWritten, fakeExecs)
+	}
+}
@@ -1,10 +1,12 @@
 package dsio
 
 import (
+	"context"
 	"fmt"
 	"io"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dstrace"
 )
 
 // PagedReader wraps a reader, starting reads from offset, and only reads limit number of entries
@@ -12,6 +14,7 @@ type PagedReader struct {
 	Reader EntryReader
 	Limit  int
 	Offset int
+	read   int
 }
 
 var _ EntryReader = (*PagedReader)(nil)
@@ -21,20 +24,38 @@ func (r *PagedReader) Structure() *dataset.Structure {
 	return r.Reader.Structure()
 }
 
-// ReadEntry returns an entry, taking offset and limit into account
+// ReadEntry returns an entry, taking offset and limit into account. Entries
+// before the offset are skipped without decoding them if the wrapped reader
+// is an EntrySkipper
 func (r *PagedReader) ReadEntry() (Entry, error) {
-	for r.Offset > 0 {
-		_, err := r.Reader.ReadEntry()
+	if r.Offset > 0 {
+		n, err := SkipEntries(r.Reader, r.Offset)
+		r.Offset -= n
 		if err != nil {
 			return Entry{}, err
 		}
-		r.Offset--
 	}
 	if r.Limit == 0 {
 		return Entry{}, io.EOF
 	}
 	r.Limit--
-	return r.Reader.ReadEntry()
+	ent, err := r.Reader.ReadEntry()
+	if err == nil {
+		r.read++
+	}
+	return ent, err
+}
+
+// EntriesRead gives the number of entries returned by the reader, excluding
+// skipped entries
+func (r *PagedReader) EntriesRead() int {
+	return r.read
+}
+
+// BytesProcessed gives the bytes processed by the wrapped reader, including
+// bytes of skipped entries
+func (r *PagedReader) BytesProcessed() int64 {
+	return bytesProcessed(r.Reader)
 }
 
 // Close finalizes the writer, indicating no more records
@@ -45,17 +66,61 @@ func (r *PagedReader) Close() error {
 
 // Copy reads all entries from the reader and writes them to the writer
 func Copy(reader EntryReader, writer EntryWriter) error {
+	return CopyContext(context.Background(), reader, writer)
+}
+
+// CopyContext is Copy with a context. Copying stops if the context is
+// cancelled, and is traced if the context carries a dstrace.Tracer
+func CopyContext(ctx context.Context, reader EntryReader, writer EntryWriter) (err error) {
+	ctx, span := dstrace.StartSpan(ctx, "dsio.Copy", formatAttrs(reader.Structure(), writer.Structure())...)
+	entries := 0
+	defer func() {
+		span.SetAttributes(dstrace.Attr("entries", entries))
+		span.End(err)
+	}()
+
 	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
 		val, err := reader.ReadEntry()
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			return fmt.Errorf("row iteration error: %s", err.Error())
+			return fmt.Errorf("row iteration error: %w", err)
 		}
-		if err := writer.WriteEntry(val); err != nil {
-			return fmt.Errorf("error writing value to buffer: %s", err.Error())
+		if err := writeEntry(ctx, writer, val); err != nil {
+			return fmt.Errorf("error writing value to buffer: %w", err)
 		}
+		entries++
 	}
 	return nil
 }
+
+// contextWriter is implemented by writers that can block waiting for a memory
+// budget or queue space. WriteEntryContext stops waiting when ctx is done
+type contextWriter interface {
+	WriteEntryContext(ctx context.Context, ent Entry) error
+}
+
+// writeEntry writes ent to w, passing ctx to writers that wait on it
+func writeEntry(ctx context.Context, w EntryWriter, ent Entry) error {
+	if cw, ok := w.(contextWriter); ok {
+		return cw.WriteEntryContext(ctx, ent)
+	}
+	return w.WriteEntry(ent)
+}
+
+// formatAttrs describes the source & destination formats of a copy or
+// conversion
+func formatAttrs(src, dst *dataset.Structure) []dstrace.Attribute {
+	var attrs []dstrace.Attribute
+	if src != nil {
+		attrs = append(attrs, dstrace.Attr("format.source", src.Format))
+	}
+	if dst != nil {
+		attrs = append(attrs, dstrace.Attr("format.destination", dst.Format))
+	}
+	return attrs
+}
@@ -2,10 +2,12 @@ package dsio
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dstrace"
 )
 
 func TestCopyJSONToJSON(t *testing.T) {
@@ -158,3 +160,34 @@ func TestCopyJSONToJSONPagingRunsOut(t *testing.T) {
 		t.Errorf("Copy limited due to paging did not succeed: %v <> %v", str, expected)
 	}
 }
+
+func TestCopyContext(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewEntryReader(st, strings.NewReader(`[1,2,3]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewEntryBuffer(&dataset.Structure{Format: "cbor", Schema: dataset.BaseSchemaArray})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := &dstrace.Recorder{}
+	if err := CopyContext(dstrace.WithTracer(context.Background(), rec), r, w); err != nil {
+		t.Fatal(err)
+	}
+	spans := rec.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got: %d", len(spans))
+	}
+	if spans[0].Attrs["entries"] != 3 || spans[0].Attrs["format.source"] != "json" || spans[0].Attrs["format.destination"] != "cbor" {
+		t.Errorf("unexpected span attributes: %v", spans[0].Attrs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r, _ = NewEntryReader(st, strings.NewReader(`[1,2,3]`))
+	if err := CopyContext(ctx, r, w); err != context.Canceled {
+		t.Errorf("expected cancelled copy to return context.Canceled, got: %v", err)
+	}
+}
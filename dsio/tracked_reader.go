@@ -4,8 +4,9 @@ import "io"
 
 // TrackedReader wraps a reader, keeping an internal count of the bytes read
 type TrackedReader struct {
-	read int
-	r    io.Reader
+	read   int
+	r      io.Reader
+	closed bool
 }
 
 // NewTrackedReader creates a new tracked reader
@@ -20,6 +21,16 @@ func (tr *TrackedReader) Read(p []byte) (n int, err error) {
 	return
 }
 
+// Close closes the underlying reader if it's an io.Closer. calling Close more
+// than once has no effect
+func (tr *TrackedReader) Close() error {
+	if tr.closed {
+		return nil
+	}
+	tr.closed = true
+	return closeUnderlying(tr.r)
+}
+
 // BytesRead gives the total number of bytes read from the underlying reader
 func (tr *TrackedReader) BytesRead() int {
 	return tr.read
@@ -0,0 +1,360 @@
+package dsio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// TSVReader implements the EntryReader interface for tab separated values.
+// Blank lines are skipped
+type TSVReader struct {
+	st     *dataset.Structure
+	src    *TrackedReader
+	escape string
+	// lines reads backslash & none escaped bodies, csvr reads quoted bodies
+	lines    *bufio.Reader
+	csvr     *csv.Reader
+	variadic bool
+	// fields is the number of fields records must have, set by the first
+	// record
+	fields      int
+	line        int
+	readHeader  bool
+	entriesRead int
+	fieldDecoder
+	// span is the source position of the last record read, see rawSpan
+	span [2]int64
+}
+
+var _ EntryReader = (*TSVReader)(nil)
+
+// NewTSVReader creates a reader from a structure and read source
+func NewTSVReader(st *dataset.Structure, r io.Reader) (*TSVReader, error) {
+	opts, err := dataset.NewTSVOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	_, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+
+	src := NewTrackedReader(r)
+	tr := &TSVReader{
+		st:           st,
+		src:          src,
+		escape:       tsvEscape(opts),
+		variadic:     opts.VariadicFields,
+		fieldDecoder: fieldDecoder{types: types, bools: newBooleanTokens(opts.BooleanTokens)},
+	}
+	if tr.escape == dataset.TSVEscapeQuote {
+		tr.csvr = csv.NewReader(src)
+		tr.csvr.Comma = '\t'
+		tr.csvr.ReuseRecord = true
+		if opts.VariadicFields {
+			tr.csvr.FieldsPerRecord = -1
+		}
+	} else {
+		tr.lines = bufio.NewReader(src)
+	}
+	return tr, nil
+}
+
+// tsvEscape gives the escape style of options, defaulting to backslash
+func tsvEscape(opts *dataset.TSVOptions) string {
+	if opts.Escape == "" {
+		return dataset.TSVEscapeBackslash
+	}
+	return opts.Escape
+}
+
+// Structure gives this reader's structure
+func (r *TSVReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry reads one TSV record from the reader
+func (r *TSVReader) ReadEntry() (Entry, error) {
+	ent, err := r.readEntry()
+	return ent, parseError("tsv", r.entriesRead, err)
+}
+
+func (r *TSVReader) readEntry() (Entry, error) {
+	if !r.readHeader {
+		r.readHeader = true
+		if HasHeaderRow(r.st) {
+			if _, err := r.readRecord(); err != nil {
+				return Entry{}, err
+			}
+		}
+	}
+
+	fields, err := r.readRecord()
+	if err != nil {
+		if err != io.EOF {
+			log.Debug(err.Error())
+		}
+		return Entry{}, err
+	}
+	value, err := r.decode(fields)
+	if err != nil {
+		log.Debug(err.Error())
+		return Entry{}, err
+	}
+
+	ent := Entry{Index: r.entriesRead, Value: value}
+	r.entriesRead++
+	return ent, nil
+}
+
+// readRecord reads the fields of the next line
+func (r *TSVReader) readRecord() ([]string, error) {
+	if r.csvr != nil {
+		start := r.csvr.InputOffset()
+		rec, err := r.csvr.Read()
+		r.span = [2]int64{start, r.csvr.InputOffset()}
+		return rec, err
+	}
+
+	for {
+		start := r.lineOffset()
+		line, err := r.lines.ReadString('\n')
+		r.span = [2]int64{start, r.lineOffset()}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line == "" {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			r.line++
+			continue
+		}
+		r.line++
+
+		fields := strings.Split(line, "\t")
+		if r.escape == dataset.TSVEscapeBackslash {
+			for i, f := range fields {
+				fields[i] = unescapeTSV(f)
+			}
+		}
+		if !r.variadic {
+			if r.fields == 0 {
+				r.fields = len(fields)
+			} else if len(fields) != r.fields {
+				return nil, &csv.ParseError{StartLine: r.line, Line: r.line, Err: csv.ErrFieldCount}
+			}
+		}
+		return fields, nil
+	}
+}
+
+// lineOffset gives the source position of line reading
+func (r *TSVReader) lineOffset() int64 {
+	return int64(r.src.BytesRead() - r.lines.Buffered())
+}
+
+// rawSpan gives the source position of the last record read
+func (r *TSVReader) rawSpan() (start, end int64) {
+	return r.span[0], r.span[1]
+}
+
+// unescapeTSV replaces backslash escape sequences in a field. unknown
+// sequences are left as-is
+func unescapeTSV(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	b := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case '\\':
+			b.WriteByte('\\')
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// EntriesRead gives the number of entries read
+func (r *TSVReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes parsed
+func (r *TSVReader) BytesProcessed() int64 {
+	if r.csvr != nil {
+		return r.csvr.InputOffset()
+	}
+	return r.lineOffset()
+}
+
+// Close finalizes the reader
+func (r *TSVReader) Close() error {
+	return r.src.Close()
+}
+
+// tsvEscaper escapes fields written with backslash escapes
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// TSVWriter implements the EntryWriter interface for tab separated values
+type TSVWriter struct {
+	st     *dataset.Structure
+	out    *countingWriter
+	escape string
+	// lines writes backslash & none escaped bodies, csvw writes quoted bodies
+	lines       *bufio.Writer
+	csvw        *csv.Writer
+	titles      []string
+	header      bool
+	rowsWritten int
+	bools       *booleanTokens
+}
+
+var _ EntryWriter = (*TSVWriter)(nil)
+
+// NewTSVWriter creates a writer from a structure and write destination
+func NewTSVWriter(st *dataset.Structure, w io.Writer) (*TSVWriter, error) {
+	opts, err := dataset.NewTSVOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+
+	out := &countingWriter{w: w}
+	tw := &TSVWriter{
+		st:     st,
+		out:    out,
+		escape: tsvEscape(opts),
+		bools:  newBooleanTokens(opts.BooleanTokens),
+	}
+	if tw.escape == dataset.TSVEscapeQuote {
+		tw.csvw = csv.NewWriter(out)
+		tw.csvw.Comma = '\t'
+	} else {
+		tw.lines = bufio.NewWriter(out)
+	}
+
+	if opts.HeaderRow {
+		titles, err := CSVHeaderTitles(st)
+		if err != nil {
+			// without schema titles the header is named from the first entry
+			tw.header = true
+		} else {
+			tw.titles = titles
+			if err := tw.writeRecord(titles); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tw, nil
+}
+
+// Structure gives this writer's structure
+func (w *TSVWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// Titles gives the header row titles written by this writer, nil if no
+// header row has been written
+func (w *TSVWriter) Titles() []string {
+	return w.titles
+}
+
+// WriteEntry writes one TSV record to the writer
+func (w *TSVWriter) WriteEntry(ent Entry) error {
+	arr, ok := ent.Value.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected array value to write tsv row. got: %v", ent)
+	}
+	strs, err := encode(arr, nil, nil, w.bools)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error encoding entry: %w", err)
+	}
+	if w.header {
+		w.header = false
+		w.titles = make([]string, len(strs))
+		for i := range w.titles {
+			w.titles[i] = dataset.AbstractColumnName(i)
+		}
+		if err := w.writeRecord(w.titles); err != nil {
+			return err
+		}
+	}
+	if err := w.writeRecord(strs); err != nil {
+		return err
+	}
+	w.rowsWritten++
+	return nil
+}
+
+func (w *TSVWriter) writeRecord(fields []string) error {
+	if w.csvw != nil {
+		return w.csvw.Write(fields)
+	}
+
+	if w.escape == dataset.TSVEscapeNone {
+		for i, f := range fields {
+			if strings.ContainsAny(f, "\t\r\n") {
+				return fmt.Errorf("field %d contains a tab or line break, which tsv without escapes can't represent", i)
+			}
+		}
+	}
+	for i, f := range fields {
+		if i > 0 {
+			if err := w.lines.WriteByte('\t'); err != nil {
+				return err
+			}
+		}
+		if w.escape == dataset.TSVEscapeBackslash {
+			f = tsvEscaper.Replace(f)
+		}
+		if _, err := w.lines.WriteString(f); err != nil {
+			return err
+		}
+	}
+	return w.lines.WriteByte('\n')
+}
+
+// EntriesWritten gives the number of entries written
+func (w *TSVWriter) EntriesWritten() int {
+	return w.rowsWritten
+}
+
+// BytesProcessed gives the number of bytes flushed to the destination
+func (w *TSVWriter) BytesProcessed() int64 {
+	return w.out.n
+}
+
+// Close finalizes the writer, indicating no more records will be written
+func (w *TSVWriter) Close() error {
+	var err error
+	if w.csvw != nil {
+		w.csvw.Flush()
+		err = w.csvw.Error()
+	} else {
+		err = w.lines.Flush()
+	}
+	if cerr := w.out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func tsvStructure(cfg map[string]interface{}) *dataset.Structure {
+	return &dataset.Structure{
+		Format:       "tsv",
+		FormatConfig: cfg,
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "name", "type": "string"},
+					map[string]interface{}{"title": "count", "type": "integer"},
+					map[string]interface{}{"title": "ok", "type": "boolean"},
+				},
+			},
+		},
+	}
+}
+
+func TestTSVFormatReader(t *testing.T) {
+	cases := []struct {
+		cfg  map[string]interface{}
+		data string
+	}{
+		{map[string]interface{}{"headerRow": true}, "name\tcount\tok\na\\tb\t1\ttrue\r\n\nline\\nbreak \\\\ \\x\t2\tfalse"},
+		{map[string]interface{}{"escape": "quote"}, "\"a\tb\"\t1\ttrue\n\"line\nbreak \\\\ \\x\"\t2\tfalse\n"},
+		{map[string]interface{}{"escape": "none"}, "a\\tb\t1\ttrue\nline\\nbreak \\\\ \\x\t2\tfalse\n"},
+	}
+	expect := [][]interface{}{
+		{[]interface{}{"a\tb", int64(1), true}, []interface{}{"line\nbreak \\ \\x", int64(2), false}},
+		{[]interface{}{"a\tb", int64(1), true}, []interface{}{"line\nbreak \\\\ \\x", int64(2), false}},
+		{[]interface{}{"a\\tb", int64(1), true}, []interface{}{"line\\nbreak \\\\ \\x", int64(2), false}},
+	}
+
+	for i, c := range cases {
+		r, err := NewEntryReader(tsvStructure(c.cfg), strings.NewReader(c.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, ex := range expect[i] {
+			ent, err := r.ReadEntry()
+			if err != nil {
+				t.Fatalf("case %d entry %d unexpected error: %s", i, j, err)
+			}
+			if ent.Index != j {
+				t.Errorf("case %d entry %d index mismatch, got: %d", i, j, ent.Index)
+			}
+			if !reflect.DeepEqual(ent.Value, ex) {
+				t.Errorf("case %d entry %d mismatch. expected: %#v, got: %#v", i, j, ex, ent.Value)
+			}
+		}
+		if _, err := r.ReadEntry(); err != io.EOF {
+			t.Errorf("case %d expected io.EOF, got: %v", i, err)
+		}
+	}
+}
+
+func TestTSVFormatReaderErrors(t *testing.T) {
+	if _, err := NewTSVReader(tsvStructure(map[string]interface{}{"escape": "nope"}), strings.NewReader("")); err == nil {
+		t.Error("expected an invalid escape to error")
+	}
+
+	r, err := NewTSVReader(tsvStructure(nil), strings.NewReader("a\t1\ttrue\nb\t2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err == nil || !strings.Contains(err.Error(), "wrong number of fields") {
+		t.Errorf("expected a field count error, got: %v", err)
+	}
+
+	ents := readAllEntries(t, tsvStructure(map[string]interface{}{"variadicFields": true}), []byte("a\t1\ttrue\nb\t2\n"))
+	if len(ents) != 2 {
+		t.Errorf("expected 2 entries, got: %d", len(ents))
+	}
+}
+
+func TestTSVFormatWriter(t *testing.T) {
+	rows := []interface{}{
+		[]interface{}{"a\tb", int64(1), true},
+		[]interface{}{"line\nbreak \\", 2, nil},
+	}
+	cases := []struct {
+		cfg    map[string]interface{}
+		expect string
+	}{
+		{map[string]interface{}{"headerRow": true}, "name\tcount\tok\na\\tb\t1\ttrue\nline\\nbreak \\\\\t2\t\n"},
+		{map[string]interface{}{"escape": "quote"}, "\"a\tb\"\t1\ttrue\n\"line\nbreak \\\"\t2\t\n"},
+	}
+
+	for i, c := range cases {
+		st := tsvStructure(c.cfg)
+		buf := &bytes.Buffer{}
+		w, err := NewEntryWriter(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, row := range rows {
+			if err := w.WriteEntry(Entry{Value: row}); err != nil {
+				t.Fatalf("case %d: %s", i, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != c.expect {
+			t.Errorf("case %d output mismatch. expected:\n%q\ngot:\n%q", i, c.expect, buf.String())
+		}
+
+		// round trip
+		ents := readAllEntries(t, st, buf.Bytes())
+		if len(ents) != len(rows) || ents[0].Value.([]interface{})[0] != "a\tb" || ents[1].Value.([]interface{})[0] != "line\nbreak \\" {
+			t.Errorf("case %d round trip mismatch, got: %v", i, ents)
+		}
+	}
+
+	w, err := NewTSVWriter(tsvStructure(map[string]interface{}{"escape": "none"}), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Value: rows[0]}); err == nil {
+		t.Error("expected writing a tab without escapes to error")
+	}
+}
@@ -0,0 +1,55 @@
+package dsio
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+)
+
+// WriteFormat writes the entries of body to w encoded as format, converting
+// a stored body on the fly so one representation can serve requests for any
+// supported format. st is the structure of the stored body, opts configures
+// the output format & may be nil. body is read to the end but not closed
+func WriteFormat(st *dataset.Structure, body EntryReader, format dataset.DataFormat, opts dataset.FormatConfig, w io.Writer) error {
+	if opts != nil && opts.Format() != format {
+		return fmt.Errorf("format config for %s can't configure %s output", opts.Format(), format)
+	}
+
+	out := &dataset.Structure{
+		Format: format.String(),
+		Schema: st.Schema,
+	}
+	if opts != nil {
+		out.FormatConfig = opts.Map()
+	}
+
+	wr, err := NewEntryWriter(out, KeepWriterOpen(w))
+	if err != nil {
+		return err
+	}
+	if err := Copy(body, wr); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("converting body to %s: %w", format, err)
+	}
+	if err := wr.Close(); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("converting body to %s: %w", format, err)
+	}
+	return nil
+}
+
+// WriteDatasetFormat writes the body of ds, read from body, to w encoded as
+// format. The dataset's default configuration for format is used, with
+// options set in opts taking precedence. opts may be nil
+func WriteDatasetFormat(ds *dataset.Dataset, body EntryReader, format dataset.DataFormat, opts dataset.FormatConfig, w io.Writer) error {
+	if ds.Structure == nil {
+		return fmt.Errorf("dataset structure is required")
+	}
+	cfg, err := ds.OutputFormatConfig(format, opts)
+	if err != nil {
+		log.Debug(err.Error())
+		return err
+	}
+	return WriteFormat(ds.Structure, body, format, cfg, w)
+}
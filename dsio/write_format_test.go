@@ -0,0 +1,144 @@
+package dsio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestWriteFormat(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+				},
+			},
+		},
+	}
+	body := "city,pop\ntoronto,50000\nnew york,8500000\n"
+
+	cases := []struct {
+		format dataset.DataFormat
+		opts   dataset.FormatConfig
+		expect string
+	}{
+		{dataset.JSONDataFormat, nil, `[["toronto",50000],["new york",8500000]]`},
+		{dataset.NDJSONDataFormat, nil, "[\"toronto\",50000]\n[\"new york\",8500000]\n"},
+		{dataset.CSVDataFormat, &dataset.CSVOptions{HeaderRow: true}, "city,pop\ntoronto,50000\nnew york,8500000\n"},
+		{dataset.CSVDataFormat, nil, "toronto,50000\nnew york,8500000\n"},
+		{dataset.TSVDataFormat, &dataset.TSVOptions{HeaderRow: true}, "city\tpop\ntoronto\t50000\nnew york\t8500000\n"},
+	}
+
+	for i, c := range cases {
+		r, err := NewEntryReader(st, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := &bytes.Buffer{}
+		if err := WriteFormat(st, r, c.format, c.opts, buf); err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if buf.String() != c.expect {
+			t.Errorf("case %d output mismatch.\nexpected: %q\ngot:      %q", i, c.expect, buf.String())
+		}
+	}
+
+	// binary formats round trip through their readers
+	r, err := NewEntryReader(st, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := WriteFormat(st, r, dataset.CBORDataFormat, nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	ents := readAllEntries(t, &dataset.Structure{Format: "cbor", Schema: st.Schema}, buf.Bytes())
+	if len(ents) != 2 {
+		t.Errorf("expected 2 cbor entries, got: %d", len(ents))
+	}
+}
+
+func TestWriteFormatErrors(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := NewEntryReader(st, strings.NewReader(`[1]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WriteFormat(st, r, dataset.JSONDataFormat, &dataset.CSVOptions{}, &bytes.Buffer{})
+	if err == nil || err.Error() != "format config for csv can't configure json output" {
+		t.Errorf("expected a config mismatch error, got: %v", err)
+	}
+	if err := WriteFormat(st, r, dataset.UnknownDataFormat, nil, &bytes.Buffer{}); err == nil {
+		t.Error("expected an unknown format to error")
+	}
+}
+
+func TestWriteDatasetFormat(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+				},
+			},
+		},
+	}
+	ds := &dataset.Dataset{
+		Structure: st,
+		FormatDefaults: map[string]map[string]interface{}{
+			"csv": {"headerRow": true, "separator": ";"},
+		},
+	}
+	body := `[["toronto",50000],["new york",8500000]]`
+
+	cases := []struct {
+		format dataset.DataFormat
+		opts   dataset.FormatConfig
+		expect string
+	}{
+		{dataset.CSVDataFormat, nil, "city;pop\ntoronto;50000\nnew york;8500000\n"},
+		{dataset.CSVDataFormat, &dataset.CSVOptions{Separator: '|'}, "city|pop\ntoronto|50000\nnew york|8500000\n"},
+		{dataset.TSVDataFormat, nil, "toronto\t50000\nnew york\t8500000\n"},
+	}
+
+	for i, c := range cases {
+		r, err := NewEntryReader(st, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := &bytes.Buffer{}
+		if err := WriteDatasetFormat(ds, r, c.format, c.opts, buf); err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if buf.String() != c.expect {
+			t.Errorf("case %d output mismatch.\nexpected: %q\ngot:      %q", i, c.expect, buf.String())
+		}
+	}
+
+	ds.FormatDefaults["csv"] = map[string]interface{}{"separator": "too long"}
+	r, err := NewEntryReader(st, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WriteDatasetFormat(ds, r, dataset.CSVDataFormat, nil, &bytes.Buffer{})
+	if err == nil || err.Error() != "formatDefaults.csv: separator must be a single character" {
+		t.Errorf("expected invalid default error, got: %v", err)
+	}
+	if err := WriteDatasetFormat(&dataset.Dataset{}, r, dataset.CSVDataFormat, nil, &bytes.Buffer{}); err == nil {
+		t.Error("expected a dataset without structure to error")
+	}
+}
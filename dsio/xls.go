@@ -0,0 +1,686 @@
+package dsio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/qri-io/dataset"
+)
+
+// BIFF8 record types read by XLSReader
+const (
+	biffFormula    = 0x0006
+	biffEOF        = 0x000A
+	biffDate1904   = 0x0022
+	biffFilePass   = 0x002F
+	biffContinue   = 0x003C
+	biffBoundSheet = 0x0085
+	biffMulRK      = 0x00BD
+	biffXF         = 0x00E0
+	biffSST        = 0x00FC
+	biffLabelSST   = 0x00FD
+	biffNumber     = 0x0203
+	biffLabel      = 0x0204
+	biffBoolErr    = 0x0205
+	biffString     = 0x0207
+	biffRK         = 0x027E
+	biffFormat     = 0x041E
+	biffBOF        = 0x0809
+)
+
+const (
+	// biffVersion8 is the BOF version of excel 97-2003 workbooks
+	biffVersion8 = 0x0600
+	// xlsMaxColumns is the width of BIFF8 sheets
+	xlsMaxColumns = 256
+)
+
+// errBIFFTruncated reports records or strings that end early
+var errBIFFTruncated = fmt.Errorf("xls workbook is truncated")
+
+// XLSReader implements the EntryReader interface for legacy binary excel
+// spreadsheets (.xls files written by excel 97-2003), reading rows of one
+// sheet as arrays of cell values. It's configured with XLSXOptions, reading
+// the sheet named by SheetName or at SheetIndex, or the first sheet of the
+// workbook if neither is given. There's no xls writer, use xlsx to write
+// spreadsheets.
+//
+// Cell values are read as strings & cast to the types of the structure's
+// schema like XLSXReader. Numbers formatted as dates are read as ISO 8601
+// dates & times, formula cells give their cached result. Blank rows & cells
+// that trail a sheet's content are dropped
+type XLSReader struct {
+	st    *dataset.Structure
+	src   *TrackedReader
+	types []string
+	bools *booleanTokens
+	rows  [][]string
+	idx   int
+}
+
+var _ EntryReader = (*XLSReader)(nil)
+
+// NewXLSReader creates a reader from a structure and read source. The source
+// is read in full & the sheet parsed before the reader is returned
+func NewXLSReader(st *dataset.Structure, r io.Reader) (*XLSReader, error) {
+	fcg, err := dataset.NewXLSXOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	opts := fcg.(*dataset.XLSXOptions)
+	_, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+
+	src := NewTrackedReader(r)
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error reading xls file: %w", err)
+	}
+	cf, err := openCFB(data)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("not an xls file: %s", err))
+	}
+
+	stream, ok, err := cf.stream("Workbook")
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("error reading xls workbook: %w", err)
+	}
+	if !ok {
+		if _, ok, _ := cf.stream("Book"); ok {
+			return nil, fmt.Errorf("xls workbook predates excel 97, only BIFF8 workbooks are supported")
+		}
+		return nil, newKindError(ErrFormatMismatch, "not an xls file: missing workbook stream")
+	}
+
+	wb, err := parseXLSWorkbook(stream)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	sheet, err := wb.sheet(opts)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	rows, err := wb.readSheet(sheet)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+
+	return &XLSReader{st: st, src: src, types: types, bools: newBooleanTokens(opts.BooleanTokens), rows: rows}, nil
+}
+
+// Structure gives this reader's structure
+func (r *XLSReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// EntriesRead gives the number of entries read
+func (r *XLSReader) EntriesRead() int {
+	return r.idx
+}
+
+// BytesProcessed gives the size of the xls file, which is read in full when
+// the reader is created
+func (r *XLSReader) BytesProcessed() int64 {
+	return int64(r.src.BytesRead())
+}
+
+// ReadEntry reads one row of the sheet
+func (r *XLSReader) ReadEntry() (Entry, error) {
+	if r.idx >= len(r.rows) {
+		return Entry{}, io.EOF
+	}
+	ent := Entry{Index: r.idx, Value: decodeSheetCells(r.types, r.rows[r.idx], r.bools)}
+	r.idx++
+	return ent, nil
+}
+
+// Close finalizes the reader
+func (r *XLSReader) Close() error {
+	return r.src.Close()
+}
+
+// xlsWorkbook holds the workbook globals of a BIFF8 workbook stream
+type xlsWorkbook struct {
+	data     []byte
+	sheets   []xlsSheet
+	sst      []string
+	date1904 bool
+	// xfFormats gives the number format of each cell format (XF) record,
+	// formats holds custom number format codes
+	xfFormats []uint16
+	formats   map[uint16]string
+}
+
+// xlsSheet is a worksheet listed in the workbook globals, pos is the offset
+// of the sheet's BOF record in the workbook stream
+type xlsSheet struct {
+	name string
+	pos  uint32
+}
+
+// parseXLSWorkbook reads the workbook globals substream
+func parseXLSWorkbook(data []byte) (*xlsWorkbook, error) {
+	r := &biffReader{data: data}
+	typ, segs, err := r.next()
+	if err != nil || typ != biffBOF || len(segs[0]) < 4 {
+		return nil, newKindError(ErrFormatMismatch, "not an xls file: workbook stream has no BOF record")
+	}
+	if le16(segs[0]) != biffVersion8 {
+		return nil, fmt.Errorf("xls workbook predates excel 97, only BIFF8 workbooks are supported")
+	}
+
+	wb := &xlsWorkbook{data: data, formats: map[uint16]string{}}
+	for {
+		typ, segs, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		body := segs[0]
+		switch typ {
+		case biffEOF:
+			return wb, nil
+		case biffFilePass:
+			return nil, fmt.Errorf("encrypted xls workbooks aren't supported")
+		case biffDate1904:
+			wb.date1904 = len(body) >= 2 && le16(body) == 1
+		case biffXF:
+			if len(body) >= 4 {
+				wb.xfFormats = append(wb.xfFormats, le16(body[2:]))
+			}
+		case biffFormat:
+			if len(body) < 2 {
+				return nil, errBIFFTruncated
+			}
+			code, err := newBIFFSegments(segs, 2).unicodeString(2)
+			if err != nil {
+				return nil, err
+			}
+			wb.formats[le16(body)] = code
+		case biffBoundSheet:
+			// only worksheets are read, skipping charts & macro sheets
+			if len(body) < 8 || body[5] != 0 {
+				continue
+			}
+			name, err := newBIFFSegments(segs, 6).unicodeString(1)
+			if err != nil {
+				return nil, err
+			}
+			wb.sheets = append(wb.sheets, xlsSheet{name: name, pos: le32(body)})
+		case biffSST:
+			if wb.sst, err = readSST(segs); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// sheet finds the sheet to read by name or index, defaulting to the first
+// sheet
+func (wb *xlsWorkbook) sheet(opts *dataset.XLSXOptions) (xlsSheet, error) {
+	if i := opts.SheetIndex; i != nil {
+		if *i >= len(wb.sheets) {
+			return xlsSheet{}, fmt.Errorf("xls workbook has no sheet %d", *i)
+		}
+		return wb.sheets[*i], nil
+	}
+	name := opts.SheetName
+	for _, sh := range wb.sheets {
+		if name == "" || sh.name == name {
+			return sh, nil
+		}
+	}
+	if name != "" {
+		return xlsSheet{}, fmt.Errorf("xls workbook has no sheet named '%s'", name)
+	}
+	return xlsSheet{}, fmt.Errorf("xls workbook has no sheets")
+}
+
+// readSheet reads the cells of a worksheet substream into rows of strings,
+// without trailing blank cells & rows
+func (wb *xlsWorkbook) readSheet(sheet xlsSheet) ([][]string, error) {
+	r := &biffReader{data: wb.data, pos: int(sheet.pos)}
+	if typ, _, err := r.next(); err != nil || typ != biffBOF {
+		return nil, fmt.Errorf("invalid xls sheet '%s': missing BOF record", sheet.name)
+	}
+
+	rows := [][]string{}
+	set := func(row, col int, v string) {
+		if col >= xlsMaxColumns {
+			return
+		}
+		for len(rows) <= row {
+			rows = append(rows, nil)
+		}
+		for len(rows[row]) <= col {
+			rows[row] = append(rows[row], "")
+		}
+		rows[row][col] = v
+	}
+
+	// depth tracks substreams embedded in the sheet, like charts.
+	// formula gives the cell of a formula whose string result is stored in
+	// the STRING record that follows it
+	depth := 0
+	formula := [2]int{-1, -1}
+	for {
+		typ, segs, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		body := segs[0]
+		if typ == biffBOF {
+			depth++
+			continue
+		} else if typ == biffEOF {
+			if depth == 0 {
+				break
+			}
+			depth--
+			continue
+		} else if depth > 0 {
+			continue
+		} else if typ == biffString {
+			if formula[0] >= 0 {
+				s, err := newBIFFSegments(segs, 0).unicodeString(2)
+				if err != nil {
+					return nil, err
+				}
+				set(formula[0], formula[1], s)
+				formula = [2]int{-1, -1}
+			}
+			continue
+		}
+
+		if len(body) < 6 {
+			continue
+		}
+		row, col, ixfe := int(le16(body)), int(le16(body[2:])), le16(body[4:])
+		switch typ {
+		case biffNumber:
+			if len(body) >= 14 {
+				set(row, col, wb.number(ixfe, math.Float64frombits(binary.LittleEndian.Uint64(body[6:]))))
+			}
+		case biffRK:
+			if len(body) >= 10 {
+				set(row, col, wb.number(ixfe, xlsRK(le32(body[6:]))))
+			}
+		case biffMulRK:
+			// MULRK cells are 6 bytes each, followed by the last column
+			for i := 0; 4+i*6+6 <= len(body)-2; i++ {
+				cell := body[4+i*6:]
+				set(row, col+i, wb.number(le16(cell), xlsRK(le32(cell[2:]))))
+			}
+		case biffLabelSST:
+			if len(body) >= 10 {
+				if isst := int(le32(body[6:])); isst < len(wb.sst) {
+					set(row, col, wb.sst[isst])
+				}
+			}
+		case biffLabel:
+			s, err := newBIFFSegments(segs, 6).unicodeString(2)
+			if err != nil {
+				return nil, err
+			}
+			set(row, col, s)
+		case biffBoolErr:
+			if len(body) >= 8 {
+				set(row, col, xlsBoolErr(body[6], body[7]))
+			}
+		case biffFormula:
+			if len(body) < 14 {
+				continue
+			}
+			res := body[6:14]
+			if le16(res[6:]) != 0xFFFF {
+				set(row, col, wb.number(ixfe, math.Float64frombits(binary.LittleEndian.Uint64(res))))
+				continue
+			}
+			switch res[0] {
+			case 0:
+				formula = [2]int{row, col}
+			case 1, 2:
+				set(row, col, xlsBoolErr(res[2], res[0]-1))
+			}
+		}
+	}
+
+	for i, row := range rows {
+		for len(row) > 0 && row[len(row)-1] == "" {
+			row = row[:len(row)-1]
+		}
+		rows[i] = row
+	}
+	for len(rows) > 0 && len(rows[len(rows)-1]) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	return rows, nil
+}
+
+// number formats a numeric cell value, giving numbers formatted as dates as
+// ISO 8601 dates & times
+func (wb *xlsWorkbook) number(ixfe uint16, f float64) string {
+	if int(ixfe) < len(wb.xfFormats) && wb.isDateFormat(wb.xfFormats[ixfe]) {
+		if s, ok := excelDate(f, wb.date1904); ok {
+			return s
+		}
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// isDateFormat reports whether a number format displays dates or times
+func (wb *xlsWorkbook) isDateFormat(ifmt uint16) bool {
+	return excelDateFormat(int(ifmt), wb.formats[ifmt])
+}
+
+// excelDateFormat reports whether a number format displays dates or times,
+// given the format's id & custom format code. xls & xlsx workbooks share
+// number formats
+func excelDateFormat(id int, code string) bool {
+	// built-in date & time formats
+	if (id >= 14 && id <= 22) || (id >= 45 && id <= 47) {
+		return true
+	}
+
+	// look for date & time tokens in the first section of the format code,
+	// outside of quoted text, escapes & bracketed colors or locales
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; c {
+		case ';':
+			return false
+		case '"':
+			if end := strings.IndexByte(code[i+1:], '"'); end >= 0 {
+				i += end + 1
+			} else {
+				return false
+			}
+		case '[':
+			end := strings.IndexByte(code[i:], ']')
+			if end < 0 {
+				return false
+			}
+			// elapsed times like [h]:mm are dates, [Red] & [$€-407] aren't
+			if tok := strings.ToLower(code[i+1 : i+end]); tok != "" && strings.Trim(tok, "hms") == "" {
+				return true
+			}
+			i += end
+		case '\\', '_', '*':
+			i++
+		default:
+			switch c | 0x20 {
+			case 'd', 'm', 'y', 'h', 's':
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// excelDate converts an excel date serial number to an ISO 8601 string.
+// Serials without a fractional day are dates, serials under one day are times
+func excelDate(serial float64, date1904 bool) (string, bool) {
+	// the largest serial is 9999-12-31
+	if serial < 0 || serial >= 2958466 {
+		return "", false
+	}
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	days := math.Floor(serial)
+	secs := math.Round((serial - days) * 86400)
+	if !date1904 && days > 0 && days < 61 {
+		// excel counts a february 29th in 1900, which didn't exist
+		days++
+	}
+	t := base.AddDate(0, 0, int(days)).Add(time.Duration(secs) * time.Second)
+
+	switch {
+	case !date1904 && days == 0:
+		return t.Format("15:04:05"), true
+	case secs == 0:
+		return t.Format("2006-01-02"), true
+	default:
+		return t.Format("2006-01-02T15:04:05"), true
+	}
+}
+
+// xlsRK decodes an RK number, a compressed 30 bit float or integer with an
+// optional division by 100
+func xlsRK(rk uint32) float64 {
+	var f float64
+	if rk&0x02 != 0 {
+		f = float64(int32(rk) >> 2)
+	} else {
+		f = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		f /= 100
+	}
+	return f
+}
+
+// xlsErrors are the displayed values of cell error codes
+var xlsErrors = map[byte]string{
+	0x00: "#NULL!",
+	0x07: "#DIV/0!",
+	0x0F: "#VALUE!",
+	0x17: "#REF!",
+	0x1D: "#NAME?",
+	0x24: "#NUM!",
+	0x2A: "#N/A",
+}
+
+// xlsBoolErr formats a boolean or error cell value
+func xlsBoolErr(v, isErr byte) string {
+	if isErr != 0 {
+		return xlsErrors[v]
+	}
+	if v != 0 {
+		return "true"
+	}
+	return "false"
+}
+
+// readSST reads the shared string table, the strings LABELSST cells index
+func readSST(segs [][]byte) ([]string, error) {
+	if len(segs[0]) < 8 {
+		return nil, errBIFFTruncated
+	}
+	n := int(le32(segs[0][4:]))
+	s := newBIFFSegments(segs, 8)
+	// each string takes at least 3 bytes, which bounds a bogus count
+	sst := make([]string, 0, min(n, s.remaining()/3))
+	for i := 0; i < n; i++ {
+		str, err := s.richString()
+		if err != nil {
+			return nil, fmt.Errorf("invalid xls shared string table: %w", err)
+		}
+		sst = append(sst, str)
+	}
+	return sst, nil
+}
+
+// biffReader iterates the records of a BIFF stream
+type biffReader struct {
+	data []byte
+	pos  int
+}
+
+// next reads a record, giving its body & the bodies of any CONTINUE records
+// that follow it as segments
+func (r *biffReader) next() (uint16, [][]byte, error) {
+	typ, body, err := r.record()
+	if err != nil {
+		return 0, nil, err
+	}
+	segs := [][]byte{body}
+	for r.pos+4 <= len(r.data) && le16(r.data[r.pos:]) == biffContinue {
+		_, cont, err := r.record()
+		if err != nil {
+			return 0, nil, err
+		}
+		segs = append(segs, cont)
+	}
+	return typ, segs, nil
+}
+
+func (r *biffReader) record() (uint16, []byte, error) {
+	if r.pos+4 > len(r.data) {
+		return 0, nil, errBIFFTruncated
+	}
+	typ, n := le16(r.data[r.pos:]), int(le16(r.data[r.pos+2:]))
+	if r.pos+4+n > len(r.data) {
+		return 0, nil, errBIFFTruncated
+	}
+	body := r.data[r.pos+4 : r.pos+4+n]
+	r.pos += 4 + n
+	return typ, body, nil
+}
+
+// biffSegments reads data continued over CONTINUE records. Strings split by
+// a record boundary resume with an option flags byte, as the next record may
+// store characters with a different width
+type biffSegments struct {
+	segs [][]byte
+	i    int
+	pos  int
+}
+
+func newBIFFSegments(segs [][]byte, offset int) *biffSegments {
+	s := &biffSegments{segs: segs, pos: offset}
+	s.advance()
+	return s
+}
+
+// advance moves past exhausted segments
+func (s *biffSegments) advance() {
+	for s.i < len(s.segs) && s.pos >= len(s.segs[s.i]) {
+		s.i++
+		s.pos = 0
+	}
+}
+
+func (s *biffSegments) remaining() int {
+	n := 0
+	for i := s.i; i < len(s.segs); i++ {
+		n += len(s.segs[i])
+	}
+	return n - s.pos
+}
+
+// read reads n bytes, crossing segments as needed
+func (s *biffSegments) read(n int) ([]byte, error) {
+	if n > s.remaining() {
+		return nil, errBIFFTruncated
+	}
+	b := make([]byte, 0, n)
+	for len(b) < n {
+		seg := s.segs[s.i][s.pos:]
+		take := min(n-len(b), len(seg))
+		b = append(b, seg[:take]...)
+		s.pos += take
+		s.advance()
+	}
+	return b, nil
+}
+
+// chars reads cch characters, single bytes if high isn't set & UTF-16 code
+// units otherwise
+func (s *biffSegments) chars(cch int, high bool) (string, error) {
+	u := make([]uint16, 0, min(cch, s.remaining()))
+	seg := s.i
+	for len(u) < cch {
+		if s.i >= len(s.segs) {
+			return "", errBIFFTruncated
+		}
+		if s.i != seg {
+			seg = s.i
+			high = s.segs[s.i][s.pos]&0x01 != 0
+			s.pos++
+			s.advance()
+			continue
+		}
+		if high {
+			b, err := s.read(2)
+			if err != nil {
+				return "", err
+			}
+			u = append(u, le16(b))
+		} else {
+			b, err := s.read(1)
+			if err != nil {
+				return "", err
+			}
+			u = append(u, uint16(b[0]))
+		}
+	}
+	return string(utf16.Decode(u)), nil
+}
+
+// unicodeString reads a string with a character count of countSize bytes,
+// an option flags byte & characters
+func (s *biffSegments) unicodeString(countSize int) (string, error) {
+	h, err := s.read(countSize + 1)
+	if err != nil {
+		return "", err
+	}
+	cch := int(h[0])
+	if countSize == 2 {
+		cch = int(le16(h))
+	}
+	return s.chars(cch, h[countSize]&0x01 != 0)
+}
+
+// richString reads a shared string table string, skipping formatting runs &
+// phonetic data
+func (s *biffSegments) richString() (string, error) {
+	h, err := s.read(3)
+	if err != nil {
+		return "", err
+	}
+	cch, flags := int(le16(h)), h[2]
+	skip := 0
+	if flags&0x08 != 0 {
+		b, err := s.read(2)
+		if err != nil {
+			return "", err
+		}
+		skip += 4 * int(le16(b))
+	}
+	if flags&0x04 != 0 {
+		b, err := s.read(4)
+		if err != nil {
+			return "", err
+		}
+		skip += int(le32(b))
+	}
+	str, err := s.chars(cch, flags&0x01 != 0)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.read(skip); err != nil {
+		return "", err
+	}
+	return str, nil
+}
+
+func le16(b []byte) uint16 {
+	return binary.LittleEndian.Uint16(b)
+}
+
+func le32(b []byte) uint32 {
+	return binary.LittleEndian.Uint32(b)
+}
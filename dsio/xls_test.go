@@ -0,0 +1,409 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/qri-io/dataset"
+)
+
+// cfbTestFile packs a stream into a version 3 compound file. Streams under
+// 4096 bytes are stored in the mini stream
+func cfbTestFile(name string, stream []byte) []byte {
+	const ss = 512
+	le := binary.LittleEndian
+	mini := len(stream) < 4096
+	data := append([]byte{}, stream...)
+	if mini {
+		for len(data)%64 != 0 {
+			data = append(data, 0)
+		}
+	}
+	nData := (len(data) + ss - 1) / ss
+
+	// sector 0 holds the FAT, 1 the directory, 2 the mini FAT & data follows
+	fat := make([]uint32, ss/4)
+	for i := range fat {
+		fat[i] = 0xFFFFFFFF
+	}
+	fat[0], fat[1], fat[2] = 0xFFFFFFFD, cfbEndOfChain, cfbEndOfChain
+	for i := 0; i < nData; i++ {
+		fat[3+i] = uint32(4 + i)
+	}
+	fat[2+nData] = cfbEndOfChain
+	miniFAT := make([]uint32, ss/4)
+	for i := range miniFAT {
+		miniFAT[i] = 0xFFFFFFFF
+	}
+	if mini {
+		n := len(data) / 64
+		for i := 0; i < n; i++ {
+			miniFAT[i] = uint32(i + 1)
+		}
+		miniFAT[n-1] = cfbEndOfChain
+	}
+
+	entry := func(name string, typ byte, child, start uint32, size int) []byte {
+		e := make([]byte, cfbDirSize)
+		u := utf16.Encode([]rune(name))
+		for i, c := range u {
+			le.PutUint16(e[i*2:], c)
+		}
+		if name != "" {
+			le.PutUint16(e[0x40:], uint16(len(u)+1)*2)
+		}
+		e[0x42], e[0x43] = typ, 1
+		le.PutUint32(e[0x44:], 0xFFFFFFFF)
+		le.PutUint32(e[0x48:], 0xFFFFFFFF)
+		le.PutUint32(e[0x4C:], child)
+		le.PutUint32(e[0x74:], start)
+		le.PutUint64(e[0x78:], uint64(size))
+		return e
+	}
+	dir := &bytes.Buffer{}
+	if mini {
+		dir.Write(entry("Root Entry", 5, 1, 3, len(data)))
+		dir.Write(entry(name, cfbStreamEntry, 0xFFFFFFFF, 0, len(stream)))
+	} else {
+		dir.Write(entry("Root Entry", 5, 1, cfbEndOfChain, 0))
+		dir.Write(entry(name, cfbStreamEntry, 0xFFFFFFFF, 3, len(stream)))
+	}
+	dir.Write(entry("", 0, 0xFFFFFFFF, 0, 0))
+	dir.Write(entry("", 0, 0xFFFFFFFF, 0, 0))
+
+	header := make([]byte, cfbHeaderSize)
+	copy(header, cfbSignature)
+	le.PutUint16(header[0x18:], 0x3E)
+	le.PutUint16(header[0x1A:], 3)
+	le.PutUint16(header[0x1C:], 0xFFFE)
+	le.PutUint16(header[0x1E:], 9)
+	le.PutUint16(header[0x20:], 6)
+	le.PutUint32(header[0x2C:], 1)
+	le.PutUint32(header[0x30:], 1)
+	le.PutUint32(header[0x38:], 4096)
+	le.PutUint32(header[0x3C:], cfbEndOfChain)
+	if mini {
+		le.PutUint32(header[0x3C:], 2)
+		le.PutUint32(header[0x40:], 1)
+	}
+	le.PutUint32(header[0x44:], cfbEndOfChain)
+	for i := 0; i < 109; i++ {
+		le.PutUint32(header[0x4C+i*4:], 0xFFFFFFFF)
+	}
+	le.PutUint32(header[0x4C:], 0)
+
+	buf := bytes.NewBuffer(header)
+	for _, w := range fat {
+		binary.Write(buf, le, w)
+	}
+	buf.Write(dir.Bytes())
+	for _, w := range miniFAT {
+		binary.Write(buf, le, w)
+	}
+	buf.Write(data)
+	buf.Write(make([]byte, nData*ss-len(data)))
+	return buf.Bytes()
+}
+
+// biffRec encodes a BIFF record, fields are concatenated into the body
+func biffRec(typ uint16, fields ...interface{}) []byte {
+	body := &bytes.Buffer{}
+	for _, f := range fields {
+		switch v := f.(type) {
+		case []byte:
+			body.Write(v)
+		case string:
+			body.WriteString(v)
+		default:
+			binary.Write(body, binary.LittleEndian, v)
+		}
+	}
+	rec := make([]byte, 4, 4+body.Len())
+	binary.LittleEndian.PutUint16(rec, typ)
+	binary.LittleEndian.PutUint16(rec[2:], uint16(body.Len()))
+	return append(rec, body.Bytes()...)
+}
+
+// xlsTestStr encodes a string with a two byte character count, using single
+// byte characters if possible
+func xlsTestStr(s string) []byte {
+	u := utf16.Encode([]rune(s))
+	b := binary.LittleEndian.AppendUint16(nil, uint16(len(u)))
+	if strings.IndexFunc(s, func(r rune) bool { return r > 0xFF }) < 0 {
+		b = append(b, 0)
+		for _, c := range u {
+			b = append(b, byte(c))
+		}
+		return b
+	}
+	b = append(b, 1)
+	for _, c := range u {
+		b = binary.LittleEndian.AppendUint16(b, c)
+	}
+	return b
+}
+
+// xlsTestCell encodes the row, column & cell format of a cell record
+func xlsTestCell(row, col, ixfe uint16) []byte {
+	return binary.LittleEndian.AppendUint16(binary.LittleEndian.AppendUint16(binary.LittleEndian.AppendUint16(nil, row), col), ixfe)
+}
+
+type xlsTestSheet struct {
+	name    string
+	dt      byte
+	records [][]byte
+}
+
+// xlsTestWorkbook assembles a workbook stream, listing sheets in the globals
+func xlsTestWorkbook(version uint16, globals [][]byte, sheets []xlsTestSheet) []byte {
+	bof := func(dt uint16) []byte {
+		return biffRec(biffBOF, version, dt, uint16(0), uint16(0), uint32(0), uint32(0))
+	}
+	boundSheets := func(pos []uint32) []byte {
+		b := []byte{}
+		for i, sh := range sheets {
+			b = append(b, biffRec(biffBoundSheet, pos[i], byte(0), sh.dt, byte(len(sh.name)), byte(0), sh.name)...)
+		}
+		return b
+	}
+	head := bytes.Join(append([][]byte{bof(5)}, globals...), nil)
+	eof := biffRec(biffEOF)
+
+	pos := make([]uint32, len(sheets))
+	streams := []byte{}
+	offset := len(head) + len(boundSheets(pos)) + len(eof)
+	for i, sh := range sheets {
+		pos[i] = uint32(offset + len(streams))
+		streams = append(streams, bof(0x10)...)
+		streams = append(streams, bytes.Join(sh.records, nil)...)
+		streams = append(streams, eof...)
+	}
+	return bytes.Join([][]byte{head, boundSheets(pos), eof, streams}, nil)
+}
+
+func xlsTestFile() []byte {
+	sst := [][]byte{
+		xlsTestStr("city"), xlsTestStr("budget"), xlsTestStr("open"), xlsTestStr("when"), xlsTestStr("Genève"),
+		// a rich string with two formatting runs & phonetic data
+		append([]byte{3, 0, 0x0C, 2, 0, 4, 0, 0, 0, 'Z', 'o', 0xEB}, make([]byte, 12)...),
+	}
+	sstBody := bytes.Join(sst, nil)
+	// a long string split over a CONTINUE record, resuming with UTF-16
+	// characters
+	longHead := append([]byte{52, 0, 0}, strings.Repeat("ab", 20)...)
+	longTail := []byte{1}
+	for _, c := range utf16.Encode([]rune("ü€" + strings.Repeat("c", 10))) {
+		longTail = binary.LittleEndian.AppendUint16(longTail, c)
+	}
+
+	globals := [][]byte{
+		biffRec(biffFormat, uint16(164), xlsTestStr(`yyyy\-mm\-dd`)),
+		biffRec(biffFormat, uint16(165), xlsTestStr(`0.00 "days"`)),
+		biffRec(biffFormat, uint16(166), xlsTestStr(`[Red]#,##0;[Blue]\-#,##0`)),
+		biffRec(biffXF, uint16(0), uint16(0), make([]byte, 16)),
+		biffRec(biffXF, uint16(0), uint16(14), make([]byte, 16)),
+		biffRec(biffXF, uint16(0), uint16(164), make([]byte, 16)),
+		biffRec(biffXF, uint16(0), uint16(165), make([]byte, 16)),
+		biffRec(biffXF, uint16(0), uint16(22), make([]byte, 16)),
+		biffRec(biffXF, uint16(0), uint16(166), make([]byte, 16)),
+		append(biffRec(biffSST, uint32(7), uint32(7), sstBody, longHead), biffRec(biffContinue, longTail)...),
+	}
+
+	rk := func(v uint32, ixfe uint16) []byte {
+		return binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint16(nil, ixfe), v)
+	}
+	formula := func(row, col, ixfe uint16, res []byte) []byte {
+		return biffRec(biffFormula, xlsTestCell(row, col, ixfe), res, uint16(0), uint32(0), uint16(0))
+	}
+	sheets := []xlsTestSheet{
+		{name: "Chart", dt: 2, records: [][]byte{biffRec(biffLabel, xlsTestCell(0, 0, 0), xlsTestStr("chart"))}},
+		{name: "Notes", records: [][]byte{biffRec(biffLabel, xlsTestCell(0, 0, 0), xlsTestStr("not data"))}},
+		{name: "Data", records: [][]byte{
+			biffRec(biffLabelSST, xlsTestCell(0, 0, 0), uint32(0)),
+			biffRec(biffLabelSST, xlsTestCell(0, 1, 0), uint32(1)),
+			biffRec(biffLabelSST, xlsTestCell(0, 2, 0), uint32(2)),
+			biffRec(biffLabelSST, xlsTestCell(0, 3, 0), uint32(3)),
+			biffRec(biffLabelSST, xlsTestCell(1, 0, 0), uint32(4)),
+			biffRec(biffNumber, xlsTestCell(1, 1, 0), 1234.5),
+			biffRec(biffBoolErr, xlsTestCell(1, 2, 0), byte(1), byte(0)),
+			biffRec(biffNumber, xlsTestCell(1, 3, 1), 43891.0),
+			// row 2 is blank
+			biffRec(biffMulRK, xlsTestCell(3, 0, 0)[:4], rk(42<<2|2, 0), rk(0x3FD00000, 0), rk(1234<<2|3, 5), uint16(2)),
+			biffRec(biffRK, xlsTestCell(3, 3, 2), uint32(43891<<2|2)),
+			formula(4, 0, 3, binary.LittleEndian.AppendUint64(nil, math.Float64bits(3))),
+			formula(4, 1, 0, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+			biffRec(biffString, xlsTestStr("sum")),
+			formula(4, 2, 0, []byte{1, 0, 1, 0, 0, 0, 0xFF, 0xFF}),
+			formula(4, 3, 0, []byte{2, 0, 0x07, 0, 0, 0, 0xFF, 0xFF}),
+			// an embedded chart substream
+			biffRec(biffBOF, uint16(biffVersion8), uint16(0x20), make([]byte, 12)),
+			biffRec(biffNumber, xlsTestCell(9, 9, 0), 1.0),
+			biffRec(biffEOF),
+			biffRec(biffLabelSST, xlsTestCell(5, 0, 0), uint32(6)),
+			biffRec(biffLabelSST, xlsTestCell(5, 1, 0), uint32(5)),
+			biffRec(biffNumber, xlsTestCell(5, 2, 4), 43891.5),
+			biffRec(biffNumber, xlsTestCell(5, 3, 1), 0.75),
+			biffRec(biffNumber, xlsTestCell(5, 4, 5), 12.0),
+			formula(5, 5, 0, []byte{3, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+			formula(7, 0, 0, []byte{3, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+		}},
+	}
+	return cfbTestFile("Workbook", xlsTestWorkbook(biffVersion8, globals, sheets))
+}
+
+func TestXLSReader(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "xls",
+		FormatConfig: map[string]interface{}{"sheetName": "Data"},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "budget", "type": "number"},
+					map[string]interface{}{"title": "open", "type": "boolean"},
+					map[string]interface{}{"title": "when", "type": "string"},
+					map[string]interface{}{"title": "count", "type": "integer"},
+					map[string]interface{}{"title": "extra", "type": "string"},
+				},
+			},
+		},
+	}
+
+	data := xlsTestFile()
+	rdr, err := NewEntryReader(st, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := odsValues(t, rdr)
+
+	long := strings.Repeat("ab", 20) + "ü€" + strings.Repeat("c", 10)
+	expect := []interface{}{
+		[]interface{}{"city", "budget", "open", "when"},
+		[]interface{}{"Genève", 1234.5, true, "2020-03-01"},
+		[]interface{}{},
+		[]interface{}{"42", 0.25, "12.34", "2020-03-01"},
+		[]interface{}{"3", "sum", true, "#DIV/0!"},
+		[]interface{}{long, "Zoë", "2020-03-01T12:00:00", "18:00:00", int64(12)},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("result mismatch.\nexpected: %#v\ngot:      %#v", expect, got)
+	}
+	if rdr.(*XLSReader).EntriesRead() != len(expect) {
+		t.Errorf("expected %d entries read, got %d", len(expect), rdr.(*XLSReader).EntriesRead())
+	}
+	if rdr.(*XLSReader).BytesProcessed() != int64(len(data)) {
+		t.Errorf("expected %d bytes processed, got %d", len(data), rdr.(*XLSReader).BytesProcessed())
+	}
+	if err := rdr.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestXLSReaderLarge(t *testing.T) {
+	// workbooks of 4096 bytes & up are stored in regular sectors
+	records := [][]byte{}
+	for i := 0; i < 500; i++ {
+		records = append(records, biffRec(biffNumber, xlsTestCell(uint16(i), 0, 0), float64(i)))
+	}
+	stream := xlsTestWorkbook(biffVersion8, nil, []xlsTestSheet{{name: "Sheet1", records: records}})
+	if len(stream) < 4096 {
+		t.Fatalf("expected a stream of 4096 bytes or more, got %d", len(stream))
+	}
+
+	st := &dataset.Structure{Format: "xls", Schema: dataset.BaseSchemaArray}
+	rdr, err := NewXLSReader(st, bytes.NewReader(cfbTestFile("Workbook", stream)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := odsValues(t, rdr)
+	if len(got) != 500 {
+		t.Fatalf("expected 500 entries, got %d", len(got))
+	}
+	if !reflect.DeepEqual(got[499], []interface{}{"499"}) {
+		t.Errorf("last entry mismatch. expected: [499], got: %v", got[499])
+	}
+}
+
+func TestXLSReaderSheets(t *testing.T) {
+	data := xlsTestFile()
+	st := &dataset.Structure{Format: "xls", Schema: dataset.BaseSchemaArray}
+	rdr, err := NewXLSReader(st, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := odsValues(t, rdr)
+	expect := []interface{}{[]interface{}{"not data"}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected the first worksheet to be read by default. expected: %v, got: %v", expect, got)
+	}
+
+	st = &dataset.Structure{Format: "xls", FormatConfig: map[string]interface{}{"sheetIndex": 0}, Schema: dataset.BaseSchemaArray}
+	if rdr, err = NewXLSReader(st, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if got := odsValues(t, rdr); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected sheet 0 to be the first worksheet. expected: %v, got: %v", expect, got)
+	}
+
+	biff5 := xlsTestWorkbook(0x0500, nil, []xlsTestSheet{{name: "Sheet1"}})
+	encrypted := xlsTestWorkbook(biffVersion8, [][]byte{biffRec(biffFilePass, make([]byte, 54))}, nil)
+	errCases := []struct {
+		data []byte
+		opts map[string]interface{}
+		err  string
+	}{
+		{data, map[string]interface{}{"sheetName": "Chart"}, "xls workbook has no sheet named 'Chart'"},
+		{data, map[string]interface{}{"sheetIndex": 5}, "xls workbook has no sheet 5"},
+		{cfbTestFile("Workbook", xlsTestWorkbook(biffVersion8, nil, nil)), nil, "xls workbook has no sheets"},
+		{[]byte("city,budget"), nil, "not an xls file: missing compound file signature"},
+		{cfbTestFile("Other", biff5), nil, "not an xls file: missing workbook stream"},
+		{cfbTestFile("Book", biff5), nil, "xls workbook predates excel 97, only BIFF8 workbooks are supported"},
+		{cfbTestFile("Workbook", biff5), nil, "xls workbook predates excel 97, only BIFF8 workbooks are supported"},
+		{cfbTestFile("Workbook", encrypted), nil, "encrypted xls workbooks aren't supported"},
+		{cfbTestFile("Workbook", xlsTestWorkbook(biffVersion8, nil, []xlsTestSheet{{name: "Sheet1"}})[:30]), nil, "xls workbook is truncated"},
+		{data, map[string]interface{}{"sheetName": 1}, "invalid sheetName value: 1"},
+	}
+	for i, c := range errCases {
+		st := &dataset.Structure{Format: "xls", FormatConfig: c.opts, Schema: dataset.BaseSchemaArray}
+		if _, err := NewXLSReader(st, bytes.NewReader(c.data)); err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+
+	if _, err := NewEntryWriter(st, &bytes.Buffer{}); err == nil {
+		t.Error("expected creating an xls writer to error")
+	}
+}
+
+func TestXLSDateFormat(t *testing.T) {
+	cases := []struct {
+		code   string
+		expect bool
+	}{
+		{"General", false},
+		{"0.00", false},
+		{`0.00 "days"`, false},
+		{`#,##0 [$€-407]`, false},
+		{`[Red]#,##0;[Blue]\-#,##0`, false},
+		{`0.00E+00`, false},
+		{`\d0`, false},
+		{"yyyy-mm-dd", true},
+		{"DD/MM/YYYY", true},
+		{"[h]:mm", true},
+		{`[$-409]mmmm d, yyyy`, true},
+		{`0;"d"yy`, false},
+	}
+	for i, c := range cases {
+		wb := &xlsWorkbook{formats: map[uint16]string{200: c.code}}
+		if got := wb.isDateFormat(200); got != c.expect {
+			t.Errorf("case %d %q: expected %t, got %t", i, c.code, c.expect, got)
+		}
+	}
+}
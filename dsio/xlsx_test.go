@@ -1,8 +1,16 @@
 package dsio
 
 import (
+	"archive/zip"
 	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/qri-io/dataset"
@@ -69,6 +77,318 @@ func TestXLSXReader(t *testing.T) {
 	}
 }
 
+// xlsxFile packs a workbook with sheets "Notes" & "Data" into an xlsx
+// archive. parts overrides or adds archive entries
+func xlsxFile(t *testing.T, data string, parts map[string]string) []byte {
+	files := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Notes" sheetId="1" r:id="rId1"/><sheet name="Data" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/data.xml"/>
+<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>
+<Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`,
+		"xl/worksheets/sheet1.xml": xlsxSheetXML(`<row r="1"><c r="A1" t="inlineStr"><is><t>not data</t></is></c></row>`),
+		"xl/worksheets/data.xml":   xlsxSheetXML(data),
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="3" uniqueCount="3">
+<si><t>city</t></si>
+<si><r><t>Gen</t></r><r><rPr><b/></rPr><t>ève</t></r><rPh sb="0" eb="1"><t>ジュネーブ</t></rPh></si>
+<si><t xml:space="preserve"> a &amp; b </t></si>
+</sst>`,
+		"xl/styles.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="2"><numFmt numFmtId="164" formatCode="&quot;day&quot; 0.00"/><numFmt numFmtId="165" formatCode="yyyy-mm-dd hh:mm"/></numFmts>
+<cellXfs count="4"><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/><xf numFmtId="165"/></cellXfs>
+</styleSheet>`,
+	}
+	for name, content := range parts {
+		files[name] = content
+	}
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(f, content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// xlsxSheetXML wraps rows in worksheet xml
+func xlsxSheetXML(rows string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><dimension ref="A1:D9"/><sheetData>` + rows + `</sheetData><pageMargins left="0.7"/></worksheet>`
+}
+
+const xlsxTestRows = `
+<row r="1" spans="1:3"><c r="A1" t="s"><v>0</v></c><c r="B1" t="inlineStr"><is><t>budget</t></is></c><c r="C1" t="str"><f>"open"</f><v>open</v></c></row>
+<row r="2"><c r="A2" t="s"><v>1</v></c><c r="B2"><v>1234.5</v></c><c r="C2" t="b"><v>1</v></c><c r="D2" s="1"/></row>
+<row r="3" customHeight="1"/>
+<row r="5"><c r="A5" t="s"><v>2</v></c><c r="C5" s="1"><v>43891</v></c><c r="D5" t="e"><v>#DIV/0!</v></c></row>
+<row><c s="2"><v>43891</v></c><c t="n" s="3"><v>43891.5</v></c><c t="b"><v>0</v></c></row>
+<row r="7"><c r="B7" s="1"/></row>
+<row r="8"/>
+`
+
+func TestXLSXReaderCells(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "xlsx",
+		FormatConfig: map[string]interface{}{"sheetName": "Data"},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "budget", "type": "number"},
+					map[string]interface{}{"title": "open", "type": "boolean"},
+					map[string]interface{}{"title": "extra", "type": "string"},
+				},
+			},
+		},
+	}
+
+	data := xlsxFile(t, xlsxTestRows, nil)
+	rdr, err := NewEntryReader(st, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := odsValues(t, rdr)
+	expect := []interface{}{
+		[]interface{}{"city", "budget", "open"},
+		[]interface{}{"Genève", 1234.5, true},
+		[]interface{}{},
+		[]interface{}{},
+		[]interface{}{" a & b ", "", "2020-03-01", "#DIV/0!"},
+		[]interface{}{"43891", "2020-03-01T12:00:00", false},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("result mismatch.\nexpected: %#v\ngot:      %#v", expect, got)
+	}
+	if rdr.(*XLSXReader).EntriesRead() != len(expect) {
+		t.Errorf("expected %d entries read, got %d", len(expect), rdr.(*XLSXReader).EntriesRead())
+	}
+	if rdr.(*XLSXReader).BytesProcessed() != int64(len(data)) {
+		t.Errorf("expected %d bytes processed, got %d", len(data), rdr.(*XLSXReader).BytesProcessed())
+	}
+	if err := rdr.Close(); err != nil {
+		t.Error(err)
+	}
+
+	// native booleans are written with the structure's boolean tokens
+	st.FormatConfig = map[string]interface{}{"sheetName": "Data", "trueTokens": []interface{}{"Y"}, "falseTokens": []interface{}{"N"}}
+	rdr, err = NewEntryReader(st, bytes.NewReader(xlsxFile(t, `<row><c t="b"><v>1</v></c><c t="b"><v>0</v></c><c t="inlineStr"><is><t>n</t></is></c></row>`, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.Schema = dataset.BaseSchemaArray
+	got = odsValues(t, rdr)
+	expect = []interface{}{[]interface{}{"Y", "N", false}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("boolean mismatch. expected: %#v, got: %#v", expect, got)
+	}
+
+	// the 1904 date system counts days from 1904-01-01
+	parts := map[string]string{"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<workbookPr date1904="1"/><sheets><sheet name="Sheet1" sheetId="1" r:id="rId2"/></sheets></workbook>`}
+	rdr, err = NewEntryReader(&dataset.Structure{Format: "xlsx", Schema: dataset.BaseSchemaArray}, bytes.NewReader(xlsxFile(t, `<row><c s="1"><v>1</v></c></row>`, parts)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = odsValues(t, rdr)
+	expect = []interface{}{[]interface{}{"1904-01-02"}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("1904 date mismatch. expected: %#v, got: %#v", expect, got)
+	}
+}
+
+// xlsxStream hides the seeking methods of a reader, so XLSXReader copies it
+// to a temporary file
+type xlsxStream struct{ io.Reader }
+
+func TestXLSXReaderStreaming(t *testing.T) {
+	const rows = 20000
+	buf := &strings.Builder{}
+	for i := 1; i <= rows; i++ {
+		fmt.Fprintf(buf, `<row r="%d"><c r="A%d"><v>%d</v></c><c r="B%d" t="s"><v>2</v></c></row>`, i, i, i, i)
+	}
+	data := xlsxFile(t, buf.String(), nil)
+	st := &dataset.Structure{Format: "xlsx", FormatConfig: map[string]interface{}{"sheetName": "Data"}, Schema: dataset.BaseSchemaArray}
+
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	for _, src := range []io.Reader{bytes.NewReader(data), xlsxStream{bytes.NewReader(data)}} {
+		rdr, err := NewXLSXReader(st, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for ent, err := rdr.ReadEntry(); err != io.EOF; ent, err = rdr.ReadEntry() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			count++
+			if row := ent.Value.([]interface{}); row[0] != strconv.Itoa(count) || row[1] != " a & b " {
+				t.Fatalf("row %d mismatch, got: %v", count, row)
+			}
+		}
+		if count != rows {
+			t.Errorf("expected %d rows, got: %d", rows, count)
+		}
+		if rdr.BytesProcessed() != int64(len(data)) {
+			t.Errorf("expected %d bytes processed, got %d", len(data), rdr.BytesProcessed())
+		}
+		if err := rdr.Close(); err != nil {
+			t.Error(err)
+		}
+		if files, _ := filepath.Glob(filepath.Join(tmp, "*")); len(files) != 0 {
+			t.Errorf("expected temp files to be removed on close, found: %v", files)
+		}
+	}
+}
+
+func TestXLSXReaderErrors(t *testing.T) {
+	data := xlsxFile(t, xlsxTestRows, nil)
+	cases := []struct {
+		data []byte
+		opts map[string]interface{}
+		err  string
+	}{
+		{data, map[string]interface{}{"sheetName": "Missing"}, "xlsx workbook has no sheet named 'Missing'"},
+		{data, nil, "xlsx workbook has no sheet named 'Sheet1'"},
+		{data, map[string]interface{}{"sheetIndex": 2}, "xlsx workbook has no sheet 2"},
+		{data, map[string]interface{}{"sheetIndex": "1"}, "invalid sheetIndex value: 1"},
+		{[]byte("city,budget"), nil, "not an xlsx file: zip: not a valid zip file"},
+		{odsFile(t, ""), nil, "not an xlsx file: missing xl/workbook.xml"},
+		{xlsxFile(t, "", map[string]string{"xl/_rels/workbook.xml.rels": "<Relationships/>"}), map[string]interface{}{"sheetName": "Data"}, "xlsx workbook is missing sheet 'Data'"},
+	}
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "xlsx", FormatConfig: c.opts, Schema: dataset.BaseSchemaArray}
+		if _, err := NewXLSXReader(st, bytes.NewReader(c.data)); err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+	if _, err := NewXLSXReader(&dataset.Structure{Format: "xlsx"}, strings.NewReader("city,budget")); !errors.Is(err, ErrFormatMismatch) {
+		t.Errorf("expected ErrFormatMismatch, got: %v", err)
+	}
+
+	readCases := []struct {
+		rows string
+		err  string
+	}{
+		{`<row><c t="s"><v>3</v></c></row>`, "invalid xlsx shared string index '3'"},
+		{`<row r="2"/><row r="1"/>`, "invalid xlsx row number '1'"},
+		{`<row><c r="B1"/><c r="A1"/></row>`, "invalid xlsx cell reference 'A1'"},
+		{`<row><c r="1"/></row>`, "invalid xlsx cell reference '1'"},
+	}
+	st := &dataset.Structure{Format: "xlsx", FormatConfig: map[string]interface{}{"sheetName": "Data"}, Schema: dataset.BaseSchemaArray}
+	for i, c := range readCases {
+		rdr, err := NewXLSXReader(st, bytes.NewReader(xlsxFile(t, c.rows, nil)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rdr.ReadEntry(); err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+
+	parts := map[string]string{"xl/worksheets/data.xml": `<worksheet><sheetData><row><c><v>1</v></c></row><row><c>`}
+	rdr, err := NewXLSXReader(st, bytes.NewReader(xlsxFile(t, "", parts)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rdr.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rdr.ReadEntry(); err == nil || err.Error() != "error reading xlsx sheet: XML syntax error on line 1: unexpected EOF" {
+		t.Errorf("expected truncated sheet error, got: %v", err)
+	}
+}
+
+func TestXLSXWorkbook(t *testing.T) {
+	src := &closeCounter{Buffer: bytes.NewBuffer(xlsxFile(t, xlsxTestRows, nil))}
+	wb, err := OpenXLSXWorkbook(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := wb.Sheets(); !reflect.DeepEqual(got, []string{"Notes", "Data"}) {
+		t.Errorf("sheets mismatch, got: %v", got)
+	}
+
+	// sheet options are ignored, readers can be read together
+	st := &dataset.Structure{Format: "xlsx", FormatConfig: map[string]interface{}{"sheetName": "Missing"}, Schema: dataset.BaseSchemaArray}
+	notes, err := wb.SheetReader(st, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := wb.SheetReader(st, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := data.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := []interface{}{"city", "budget", "open"}; !reflect.DeepEqual(ent.Value, expect) {
+		t.Errorf("data sheet mismatch. expected: %v, got: %v", expect, ent.Value)
+	}
+	if got := odsValues(t, notes); !reflect.DeepEqual(got, []interface{}{[]interface{}{"not data"}}) {
+		t.Errorf("notes sheet mismatch, got: %v", got)
+	}
+	if err := notes.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if src.closed != 0 {
+		t.Error("expected closing a sheet reader not to close the workbook")
+	}
+	if got := odsValues(t, data); len(got) != 5 {
+		t.Errorf("expected 5 more data rows, got: %v", got)
+	}
+	data.Close()
+
+	if _, err := wb.SheetReader(st, 2); err == nil || err.Error() != "xlsx workbook has no sheet 2" {
+		t.Errorf("expected missing sheet error, got: %v", err)
+	}
+	if err := wb.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if src.closed != 1 {
+		t.Errorf("expected closing the workbook to close it's source, got %d closes", src.closed)
+	}
+
+	// the sheetIndex option selects a sheet by position
+	st.FormatConfig = map[string]interface{}{"sheetIndex": 0}
+	rdr, err := NewXLSXReader(st, bytes.NewReader(xlsxFile(t, xlsxTestRows, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := odsValues(t, rdr); !reflect.DeepEqual(got, []interface{}{[]interface{}{"not data"}}) {
+		t.Errorf("sheetIndex 0 mismatch, got: %v", got)
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		if got, ok := xlsxColumn(ColIndexToLetters(i) + "12"); !ok || got != i {
+			t.Errorf("column %d: got %d %t", i, got, ok)
+		}
+	}
+	if _, ok := xlsxColumn("XFE1"); ok {
+		t.Error("expected columns past XFD to be invalid")
+	}
+}
+
 func TestColIndexToLetters(t *testing.T) {
 	cases := []struct {
 		in     int
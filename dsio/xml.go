@@ -0,0 +1,194 @@
+package dsio
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// XMLReader implements the EntryReader interface for XML documents, reading
+// each element named by the structure's XMLOptions as an entry. The
+// document is streamed, only one entry element is held in memory at a time.
+//
+// Attributes & child elements become fields of the entry. Elements with only
+// text are read as strings, elements with attributes or children as
+// objects, and repeated child elements as arrays. Tabular schemas pick
+// fields by column title, casting text to the column's type. XML bodies must
+// have a top-level array schema
+type XMLReader struct {
+	st          *dataset.Structure
+	src         *TrackedReader
+	dec         *xml.Decoder
+	element     string
+	attrPrefix  string
+	textField   string
+	titles      []string
+	decoder     *fieldDecoder
+	entriesRead int
+}
+
+var _ EntryReader = (*XMLReader)(nil)
+
+// NewXMLReader creates a reader from a structure and read source
+func NewXMLReader(st *dataset.Structure, r io.Reader) (*XMLReader, error) {
+	if st.Schema == nil {
+		err := newKindError(ErrBadSchema, "schema required for XML reader")
+		log.Debug(err.Error())
+		return nil, err
+	}
+	if tlt, err := GetTopLevelType(st); err != nil {
+		return nil, err
+	} else if tlt != "array" {
+		return nil, newKindError(ErrBadSchema, "XML requires a top-level array schema")
+	}
+	opts, err := dataset.NewXMLOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	if opts.Element == "" {
+		return nil, fmt.Errorf("xml format config must name the entry element")
+	}
+
+	src := NewTrackedReader(r)
+	xr := &XMLReader{
+		st:         st,
+		src:        src,
+		dec:        xml.NewDecoder(src),
+		element:    opts.Element,
+		attrPrefix: opts.AttributePrefix,
+		textField:  opts.TextField,
+	}
+	if xr.textField == "" {
+		xr.textField = "#text"
+	}
+	if titles, types, err := terribleHackToGetHeaderRowAndTypes(st); err == nil {
+		xr.titles = titles
+		xr.decoder = &fieldDecoder{types: types}
+	}
+	return xr, nil
+}
+
+// Structure gives this reader's structure
+func (r *XMLReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// xmlNode is a generic element, decoded with all attributes & children
+type xmlNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []xmlNode  `xml:",any"`
+}
+
+// ReadEntry reads the next entry element
+func (r *XMLReader) ReadEntry() (Entry, error) {
+	ent, err := r.readEntry()
+	return ent, parseError("xml", r.entriesRead, err)
+}
+
+func (r *XMLReader) readEntry() (Entry, error) {
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				return Entry{}, io.EOF
+			}
+			return Entry{}, newKindError(ErrFormatMismatch, fmt.Sprintf("invalid XML: %s", err))
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != r.element {
+			continue
+		}
+
+		node := xmlNode{}
+		if err := r.dec.DecodeElement(&node, &start); err != nil {
+			return Entry{}, newKindError(ErrFormatMismatch, fmt.Sprintf("invalid XML: %s", err))
+		}
+		val := r.nodeValue(node)
+		if r.titles != nil {
+			if val, err = r.row(val); err != nil {
+				return Entry{}, err
+			}
+		}
+		ent := Entry{Index: r.entriesRead, Value: val}
+		r.entriesRead++
+		return ent, nil
+	}
+}
+
+// nodeValue converts an element to a string or object
+func (r *XMLReader) nodeValue(n xmlNode) interface{} {
+	text := strings.TrimSpace(n.Text)
+	if len(n.Attrs) == 0 && len(n.Children) == 0 {
+		return text
+	}
+
+	obj := map[string]interface{}{}
+	for _, attr := range n.Attrs {
+		obj[r.attrPrefix+attr.Name.Local] = attr.Value
+	}
+	for _, child := range n.Children {
+		name := child.XMLName.Local
+		val := r.nodeValue(child)
+		switch existing := obj[name].(type) {
+		case nil:
+			obj[name] = val
+		case []interface{}:
+			// attributes are strings, so slices are always repeated children
+			obj[name] = append(existing, val)
+		default:
+			obj[name] = []interface{}{existing, val}
+		}
+	}
+	if text != "" {
+		obj[r.textField] = text
+	}
+	return obj
+}
+
+// row picks the columns of a tabular schema from an entry element's fields,
+// casting text to column types. missing fields are null
+func (r *XMLReader) row(val interface{}) ([]interface{}, error) {
+	obj, _ := val.(map[string]interface{})
+	texts := make([]string, len(r.titles))
+	for i, title := range r.titles {
+		if s, ok := obj[title].(string); ok {
+			texts[i] = s
+		}
+	}
+	row, err := r.decoder.decode(texts)
+	if err != nil {
+		return nil, err
+	}
+	for i, title := range r.titles {
+		switch v := obj[title].(type) {
+		case nil:
+			row[i] = nil
+		case string:
+		default:
+			row[i] = v
+		}
+	}
+	return row, nil
+}
+
+// Close finalizes the reader, closing the source if it's an io.Closer. wrap
+// the source with KeepOpen to leave it open
+func (r *XMLReader) Close() error {
+	return r.src.Close()
+}
+
+// EntriesRead gives the number of entries read
+func (r *XMLReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes parsed
+func (r *XMLReader) BytesProcessed() int64 {
+	return r.dec.InputOffset()
+}
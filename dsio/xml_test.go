@@ -0,0 +1,128 @@
+package dsio
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+const xmlFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed>
+  <title>stations</title>
+  <stations>
+    <station id="1" active="true">
+      <name>North</name>
+      <elevation>120</elevation>
+      <tag>coastal</tag>
+    </station>
+    <station id="2">
+      <name lang="en">South</name>
+      <tag>inland</tag>
+      <tag>high</tag>
+    </station>
+  </stations>
+</feed>`
+
+func TestXMLReaderObjects(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "xml",
+		FormatConfig: map[string]interface{}{"element": "station", "attributePrefix": "@"},
+		Schema:       dataset.BaseSchemaArray,
+	}
+	r, err := NewEntryReader(st, strings.NewReader(xmlFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []interface{}{
+		map[string]interface{}{"@id": "1", "@active": "true", "name": "North", "elevation": "120", "tag": "coastal"},
+		map[string]interface{}{"@id": "2", "name": map[string]interface{}{"@lang": "en", "#text": "South"}, "tag": []interface{}{"inland", "high"}},
+	}
+	for i, ex := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("entry %d unexpected error: %s", i, err)
+		}
+		if ent.Index != i {
+			t.Errorf("entry %d index mismatch, got: %d", i, ent.Index)
+		}
+		if !reflect.DeepEqual(ent.Value, ex) {
+			t.Errorf("entry %d mismatch.\nexpected: %#v\ngot:      %#v", i, ex, ent.Value)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+}
+
+func TestXMLReaderTabular(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "xml",
+		FormatConfig: map[string]interface{}{"element": "station", "textField": "value"},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "active", "type": "boolean"},
+					map[string]interface{}{"title": "elevation", "type": "integer"},
+					map[string]interface{}{"title": "name", "type": "string"},
+				},
+			},
+		},
+	}
+	r, err := NewEntryReader(st, strings.NewReader(xmlFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []interface{}{
+		[]interface{}{int64(1), true, int64(120), "North"},
+		[]interface{}{int64(2), nil, nil, map[string]interface{}{"lang": "en", "value": "South"}},
+	}
+	for i, ex := range expect {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatalf("entry %d unexpected error: %s", i, err)
+		}
+		if !reflect.DeepEqual(ent.Value, ex) {
+			t.Errorf("entry %d mismatch.\nexpected: %#v\ngot:      %#v", i, ex, ent.Value)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+}
+
+func TestXMLReaderErrors(t *testing.T) {
+	cases := []struct {
+		st  *dataset.Structure
+		err string
+	}{
+		{&dataset.Structure{Format: "xml", FormatConfig: map[string]interface{}{"element": "a"}}, "schema required for XML reader"},
+		{&dataset.Structure{Format: "xml", FormatConfig: map[string]interface{}{"element": "a"}, Schema: dataset.BaseSchemaObject}, "XML requires a top-level array schema"},
+		{&dataset.Structure{Format: "xml", Schema: dataset.BaseSchemaArray}, "xml format config must name the entry element"},
+		{&dataset.Structure{Format: "xml", FormatConfig: map[string]interface{}{"element": 1}, Schema: dataset.BaseSchemaArray}, "invalid element value: 1"},
+	}
+	for i, c := range cases {
+		if _, err := NewXMLReader(c.st, strings.NewReader("")); err == nil || err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+
+	st := &dataset.Structure{Format: "xml", FormatConfig: map[string]interface{}{"element": "a"}, Schema: dataset.BaseSchemaArray}
+	r, err := NewXMLReader(st, strings.NewReader("<root><a>1</a><a>2</b></root>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadEntry(); err == nil || !strings.Contains(err.Error(), "invalid XML") {
+		t.Errorf("expected an invalid XML error, got: %v", err)
+	}
+}
@@ -1,57 +1,313 @@
 package dsio
 
 import (
+	"archive/zip"
+	"bufio"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
+	"os"
+	"path"
 	"strconv"
+	"strings"
 
 	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/vals"
 )
 
-// XLSXReader implements the RowReader interface for the XLSX data format
+const (
+	// xlsxMaxColumns is the widest sheet excel supports
+	xlsxMaxColumns = 1 << 14
+	// xlsxMaxRows is the longest sheet excel supports
+	xlsxMaxRows = 1 << 20
+)
+
+// XLSXReader implements the EntryReader interface for the XLSX data format,
+// reading rows of one sheet as arrays of cell values. It's configured with
+// XLSXOptions, reading the sheet named by SheetName or at SheetIndex,
+// defaulting to "Sheet1". Use OpenXLSXWorkbook to read the other sheets of a
+// workbook.
+//
+// Rows are streamed from the sheet's xml as they're read, so sheets of any
+// length are read in constant memory. Only the workbook's shared string
+// table & cell formats are loaded up front.
+//
+// Like ODSReader, numeric cells are read from their stored value, with
+// numbers formatted as dates read as ISO 8601 dates & times. Blank rows &
+// cells that trail a sheet's content are dropped
 type XLSXReader struct {
-	err       error
-	st        *dataset.Structure
-	sheetName string
-	file      *excelize.File
-	r         *excelize.Rows
-	idx       int
-	types     []string
+	err   error
+	st    *dataset.Structure
+	wb    *XLSXWorkbook
+	idx   int
+	types []string
+	bools *booleanTokens
+	sheet io.ReadCloser
+	dec   *xml.Decoder
+	// ownsWorkbook is set for readers that close their workbook on Close
+	ownsWorkbook bool
+	// rowNum is the sheet row number of the last row read. blank counts
+	// blank rows that haven't been read, blank rows are only read if a row
+	// with content follows, which is held in row
+	rowNum int
+	blank  int
+	row    []string
 }
 
+var _ EntryReader = (*XLSXReader)(nil)
+
 // NewXLSXReader creates a reader from a structure and read source
 func NewXLSXReader(st *dataset.Structure, r io.Reader) (*XLSXReader, error) {
-	// TODO - handle error
-	_, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+	fcg, err := dataset.NewXLSXOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	opts := fcg.(*dataset.XLSXOptions)
 
-	rdr := &XLSXReader{
-		st:    st,
-		types: types,
+	wb, err := OpenXLSXWorkbook(r)
+	if err != nil {
+		return nil, err
+	}
+	i, err := wb.selectSheet(opts)
+	if err == nil {
+		var rdr *XLSXReader
+		if rdr, err = wb.sheetReader(st, i, opts); err == nil {
+			rdr.ownsWorkbook = true
+			return rdr, nil
+		}
 	}
+	log.Debug(err.Error())
+	wb.removeTemp()
+	return nil, err
+}
+
+// XLSXWorkbook is an open xlsx file, for reading any number of it's sheets.
+// xlsx files are zip archives that need random access: sources that are an
+// io.ReaderAt & io.Seeker like *os.File are read in place, all others are
+// copied to a temporary file that's removed on Close
+type XLSXWorkbook struct {
+	src    *TrackedReader
+	size   int64
+	tmp    *os.File
+	sheets []xlsxSheet
+	sst    []string
+	// dateStyles marks cell formats that display numbers as dates
+	dateStyles []bool
+	date1904   bool
+}
 
-	// xlsxr := xlsx.NewReader(ReplaceSoloCarriageReturns(r))
-	rdr.file, rdr.err = excelize.OpenReader(r)
-	if rdr.err != nil {
-		return rdr, rdr.err
+// xlsxSheet is a sheet listed in the workbook & the archive entry of it's
+// xml, part is nil if the workbook doesn't include the sheet
+type xlsxSheet struct {
+	name string
+	part *zip.File
+}
+
+// OpenXLSXWorkbook opens an xlsx file, loading the list of sheets, the shared
+// string table & cell formats
+func OpenXLSXWorkbook(r io.Reader) (*XLSXWorkbook, error) {
+	wb := &XLSXWorkbook{src: NewTrackedReader(r)}
+	ra, err := wb.readerAt(r)
+	if err != nil {
+		log.Debug(err.Error())
+		wb.removeTemp()
+		return nil, err
+	}
+	zr, err := zip.NewReader(ra, wb.size)
+	if err != nil {
+		log.Debug(err.Error())
+		wb.removeTemp()
+		return nil, newKindError(ErrFormatMismatch, fmt.Sprintf("not an xlsx file: %s", err))
 	}
+	if err := wb.load(zr); err != nil {
+		log.Debug(err.Error())
+		wb.removeTemp()
+		return nil, err
+	}
+	return wb, nil
+}
 
-	if fcg, err := dataset.ParseFormatConfigMap(dataset.XLSXDataFormat, st.FormatConfig); err == nil {
-		if opts, ok := fcg.(*dataset.XLSXOptions); ok {
-			rdr.sheetName = opts.SheetName
+// readerAt gives random access to the xlsx file from the source's current
+// position, copying sources that can't seek to a temporary file
+func (wb *XLSXWorkbook) readerAt(src io.Reader) (io.ReaderAt, error) {
+	if ra, ok := src.(interface {
+		io.ReaderAt
+		io.Seeker
+	}); ok {
+		start, err := ra.Seek(0, io.SeekCurrent)
+		if err == nil {
+			var end int64
+			if end, err = ra.Seek(0, io.SeekEnd); err == nil {
+				wb.size = end - start
+				return io.NewSectionReader(ra, start, wb.size), nil
+			}
+		}
+	}
+
+	f, err := os.CreateTemp("", "dsio-xlsx-*.xlsx")
+	if err != nil {
+		return nil, fmt.Errorf("error creating xlsx temp file: %w", err)
+	}
+	wb.tmp = f
+	if wb.size, err = io.Copy(f, wb.src); err != nil {
+		return nil, fmt.Errorf("error reading xlsx file: %w", err)
+	}
+	return f, nil
+}
+
+// load reads the workbook's sheets, shared strings & cell formats
+func (wb *XLSXWorkbook) load(zr *zip.Reader) error {
+	parts := map[string]*zip.File{}
+	for _, f := range zr.File {
+		parts[f.Name] = f
+	}
+
+	book := &xlsxWorkbookPart{}
+	if f, ok := parts["xl/workbook.xml"]; ok {
+		if err := readXLSXPart(f, book); err != nil {
+			return err
+		}
+	} else {
+		return newKindError(ErrFormatMismatch, "not an xlsx file: missing xl/workbook.xml")
+	}
+	rels := &xlsxRelsPart{}
+	if f, ok := parts["xl/_rels/workbook.xml.rels"]; ok {
+		if err := readXLSXPart(f, rels); err != nil {
+			return err
+		}
+	}
+	if book.WorkbookPr.Date1904 != "" {
+		wb.date1904, _ = strconv.ParseBool(book.WorkbookPr.Date1904)
+	}
+
+	for _, s := range book.Sheets {
+		sheet := xlsxSheet{name: s.Name}
+		for _, a := range s.Attrs {
+			// relationship ids are namespaced, usually with an r prefix
+			if a.Name.Local != "id" || a.Name.Space == "" {
+				continue
+			}
+			for _, rel := range rels.Relationships {
+				if rel.ID == a.Value {
+					sheet.part = parts[xlsxPartPath(rel.Target)]
+				}
+			}
+		}
+		wb.sheets = append(wb.sheets, sheet)
+	}
+
+	if f, ok := parts[rels.partPath("sharedStrings", "xl/sharedStrings.xml")]; ok {
+		sst, err := readXLSXSharedStrings(f)
+		if err != nil {
+			return err
 		}
+		wb.sst = sst
 	}
-	if rdr.sheetName == "" {
-		rdr.sheetName = "Sheet1"
+	if f, ok := parts[rels.partPath("styles", "xl/styles.xml")]; ok {
+		styles := &xlsxStylesPart{}
+		if err := readXLSXPart(f, styles); err != nil {
+			return err
+		}
+		codes := map[int]string{}
+		for _, nf := range styles.NumFmts {
+			codes[nf.ID] = nf.Code
+		}
+		wb.dateStyles = make([]bool, len(styles.CellXfs))
+		for i, xf := range styles.CellXfs {
+			wb.dateStyles[i] = excelDateFormat(xf.NumFmtID, codes[xf.NumFmtID])
+		}
 	}
+	return nil
+}
 
-	if rdr.err == nil {
-		rdr.r, rdr.err = rdr.file.Rows(rdr.sheetName)
+// Sheets gives the names of the workbook's sheets in workbook order
+func (wb *XLSXWorkbook) Sheets() []string {
+	names := make([]string, len(wb.sheets))
+	for i, s := range wb.sheets {
+		names[i] = s.name
+	}
+	return names
+}
+
+// SheetReader creates a reader for the sheet at index i of Sheets, casting
+// cells to the types of st's schema. Sheet options in st's format config are
+// ignored. Readers can be used at the same time, closing a reader doesn't
+// close the workbook
+func (wb *XLSXWorkbook) SheetReader(st *dataset.Structure, i int) (*XLSXReader, error) {
+	fcg, err := dataset.NewXLSXOptions(st.FormatConfig)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	rdr, err := wb.sheetReader(st, i, fcg.(*dataset.XLSXOptions))
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
 	}
+	return rdr, nil
+}
 
-	return rdr, rdr.err
+// selectSheet gives the index of the sheet set by options
+func (wb *XLSXWorkbook) selectSheet(opts *dataset.XLSXOptions) (int, error) {
+	if opts.SheetIndex != nil {
+		return *opts.SheetIndex, nil
+	}
+	name := opts.SheetName
+	if name == "" {
+		name = "Sheet1"
+	}
+	for i, s := range wb.sheets {
+		if s.name == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("xlsx workbook has no sheet named '%s'", name)
+}
+
+func (wb *XLSXWorkbook) sheetReader(st *dataset.Structure, i int, opts *dataset.XLSXOptions) (*XLSXReader, error) {
+	if i < 0 || i >= len(wb.sheets) {
+		return nil, fmt.Errorf("xlsx workbook has no sheet %d", i)
+	}
+	if wb.sheets[i].part == nil {
+		return nil, fmt.Errorf("xlsx workbook is missing sheet '%s'", wb.sheets[i].name)
+	}
+	rc, err := wb.sheets[i].part.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error reading xlsx sheet: %w", err)
+	}
+
+	// TODO - handle error
+	_, types, _ := terribleHackToGetHeaderRowAndTypes(st)
+	return &XLSXReader{
+		st:    st,
+		wb:    wb,
+		types: types,
+		bools: newBooleanTokens(opts.BooleanTokens),
+		sheet: rc,
+		dec:   xml.NewDecoder(bufio.NewReader(rc)),
+	}, nil
+}
+
+// Close closes the workbook's source, removing any temporary copy of it
+func (wb *XLSXWorkbook) Close() error {
+	err := wb.removeTemp()
+	if cerr := wb.src.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (wb *XLSXWorkbook) removeTemp() error {
+	if wb.tmp == nil {
+		return nil
+	}
+	wb.tmp.Close()
+	err := os.Remove(wb.tmp.Name())
+	wb.tmp = nil
+	return err
 }
 
 // Structure gives this reader's structure
@@ -59,34 +315,371 @@ func (r *XLSXReader) Structure() *dataset.Structure {
 	return r.st
 }
 
-// ReadEntry reads one XLSX record from the reader
+// EntriesRead gives the number of entries read
+func (r *XLSXReader) EntriesRead() int {
+	return r.idx
+}
+
+// BytesProcessed gives the size of the xlsx file
+func (r *XLSXReader) BytesProcessed() int64 {
+	return r.wb.size
+}
+
+// ReadEntry reads one row of the sheet
 func (r *XLSXReader) ReadEntry() (Entry, error) {
-	if r.err != nil {
-		return Entry{}, r.err
+	for r.err == nil {
+		if r.row != nil && r.blank > 0 {
+			r.blank--
+			return r.entry([]interface{}{}), nil
+		}
+		if r.row != nil {
+			row := r.row
+			r.row = nil
+			return r.entry(decodeSheetCells(r.types, row, r.bools)), nil
+		}
+
+		cells, err := r.readRow()
+		if err != nil {
+			r.err = err
+			break
+		}
+		if len(cells) == 0 {
+			r.blank++
+			continue
+		}
+		r.row = cells
 	}
-	if !r.r.Next() {
-		return Entry{}, io.EOF
+	return Entry{}, r.err
+}
+
+func (r *XLSXReader) entry(v []interface{}) Entry {
+	ent := Entry{Index: r.idx, Value: v}
+	r.idx++
+	return ent
+}
+
+// readRow reads the next row of the sheet, giving cell values without
+// trailing blank cells. readRow returns io.EOF at the end of the sheet
+func (r *XLSXReader) readRow() ([]string, error) {
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return nil, xlsxReadError(err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "worksheet", "sheetData":
+				continue
+			case "row":
+				return r.readCells(t)
+			}
+			if err := r.dec.Skip(); err != nil {
+				return nil, xlsxReadError(err)
+			}
+		case xml.EndElement:
+			if t.Name.Local == "sheetData" {
+				return nil, io.EOF
+			}
+		}
+	}
+}
+
+// readCells reads the cells of a row element. Rows left out of the sheet
+// before the row are counted as blank
+func (r *XLSXReader) readCells(row xml.StartElement) ([]string, error) {
+	num := r.rowNum + 1
+	if s := xlsxAttr(row, "r"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= r.rowNum || n > xlsxMaxRows {
+			return nil, fmt.Errorf("invalid xlsx row number '%s'", s)
+		}
+		num = n
+	}
+	r.blank += num - r.rowNum - 1
+	r.rowNum = num
+
+	var cells []string
+	next := 0
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return nil, xlsxReadError(err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "c" {
+				if err := r.dec.Skip(); err != nil {
+					return nil, xlsxReadError(err)
+				}
+				continue
+			}
+			col := next
+			if ref := xlsxAttr(t, "r"); ref != "" {
+				var ok bool
+				if col, ok = xlsxColumn(ref); !ok || col < next {
+					return nil, fmt.Errorf("invalid xlsx cell reference '%s'", ref)
+				}
+			}
+			next = col + 1
+
+			val, err := r.readCell(t)
+			if err != nil {
+				return nil, err
+			}
+			// blank cells are only kept if a cell with content follows
+			if val == "" {
+				continue
+			}
+			for len(cells) < col {
+				cells = append(cells, "")
+			}
+			cells = append(cells, val)
+		case xml.EndElement:
+			return cells, nil
+		}
 	}
-	cols, err := r.r.Columns()
+}
+
+// readCell gives the value of a cell element. Strings are read from the
+// shared string table or the cell's inline text, booleans are written with
+// the reader's boolean tokens
+func (r *XLSXReader) readCell(c xml.StartElement) (string, error) {
+	var v string
+	for done := false; !done; {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return "", xlsxReadError(err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "v", "is":
+				// values are plain text, inline strings have the shape of
+				// shared strings
+				if v, err = readXLSXText(r.dec, t.Name.Local == "v"); err != nil {
+					return "", xlsxReadError(err)
+				}
+			default:
+				if err := r.dec.Skip(); err != nil {
+					return "", xlsxReadError(err)
+				}
+			}
+		case xml.EndElement:
+			done = true
+		}
+	}
+	if v == "" {
+		return "", nil
+	}
+
+	switch xlsxAttr(c, "t") {
+	case "s":
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 || i >= len(r.wb.sst) {
+			return "", fmt.Errorf("invalid xlsx shared string index '%s'", v)
+		}
+		return r.wb.sst[i], nil
+	case "b":
+		return r.bools.format(v == "1"), nil
+	case "", "n":
+		s, err := strconv.Atoi(xlsxAttr(c, "s"))
+		if err != nil || s < 0 || s >= len(r.wb.dateStyles) || !r.wb.dateStyles[s] {
+			break
+		}
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			if d, ok := excelDate(f, r.wb.date1904); ok {
+				return d, nil
+			}
+		}
+	}
+	return v, nil
+}
+
+// Close finalizes the reader, indicating no more records will be read.
+// Readers created with NewXLSXReader close their source
+func (r *XLSXReader) Close() error {
+	var err error
+	if r.sheet != nil {
+		err = r.sheet.Close()
+		r.sheet = nil
+	}
+	if r.ownsWorkbook {
+		if cerr := r.wb.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// xlsxWorkbookPart is the part of xl/workbook.xml read by XLSXReader
+type xlsxWorkbookPart struct {
+	WorkbookPr struct {
+		Date1904 string `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name  string     `xml:"name,attr"`
+		Attrs []xml.Attr `xml:",any,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelsPart lists the parts related to a workbook
+type xlsxRelsPart struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// partPath gives the path of the first related part of a type, falling back
+// to the part's usual path
+func (rels *xlsxRelsPart) partPath(typ, fallback string) string {
+	for _, rel := range rels.Relationships {
+		if strings.HasSuffix(rel.Type, "/"+typ) {
+			return xlsxPartPath(rel.Target)
+		}
+	}
+	return fallback
+}
+
+// xlsxStylesPart is the part of xl/styles.xml read by XLSXReader
+type xlsxStylesPart struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+// xlsxPartPath resolves a relationship target, which is relative to the xl
+// directory unless it starts with a slash
+func xlsxPartPath(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return target[1:]
+	}
+	return path.Join("xl", target)
+}
+
+// readXLSXPart decodes a workbook part into v
+func readXLSXPart(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
 	if err != nil {
-		return Entry{}, err
+		return fmt.Errorf("error reading xlsx %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(bufio.NewReader(rc)).Decode(v); err != nil {
+		return fmt.Errorf("error reading xlsx %s: %w", f.Name, err)
 	}
-	vals, err := r.decode(cols)
+	return nil
+}
+
+// readXLSXSharedStrings reads a workbook's shared string table
+func readXLSXSharedStrings(f *zip.File) ([]string, error) {
+	rc, err := f.Open()
 	if err != nil {
-		return Entry{}, err
+		return nil, fmt.Errorf("error reading xlsx %s: %w", f.Name, err)
 	}
-	ent := Entry{Index: r.idx, Value: vals}
-	r.idx++
+	defer rc.Close()
+
+	var sst []string
+	dec := xml.NewDecoder(bufio.NewReader(rc))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return sst, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading xlsx %s: %w", f.Name, err)
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "si" {
+			s, err := readXLSXText(dec, false)
+			if err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return nil, fmt.Errorf("error reading xlsx %s: %w", f.Name, err)
+			}
+			sst = append(sst, s)
+		}
+	}
+}
+
+// readXLSXText reads the text of an element up to it's end. Plain elements
+// give all of their text, string items give the text of their t elements
+// including rich text runs, but not phonetic runs
+func readXLSXText(dec *xml.Decoder, plain bool) (string, error) {
+	buf := &strings.Builder{}
+	depth, inText := 0, plain
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "rPh" {
+				if err := dec.Skip(); err != nil {
+					return "", err
+				}
+				continue
+			}
+			depth++
+			inText = plain || t.Name.Local == "t"
+		case xml.EndElement:
+			if depth == 0 {
+				return buf.String(), nil
+			}
+			depth--
+			inText = plain
+		case xml.CharData:
+			if inText {
+				buf.Write(t)
+			}
+		}
+	}
+}
 
-	return ent, nil
+// xlsxColumn gives the zero based column of a cell reference like "AB12"
+func xlsxColumn(ref string) (int, bool) {
+	col, i := 0, 0
+	for ; i < len(ref); i++ {
+		c := ref[i] | 0x20
+		if c < 'a' || c > 'z' {
+			break
+		}
+		if col = col*26 + int(c-'a') + 1; col > xlsxMaxColumns {
+			return 0, false
+		}
+	}
+	return col - 1, i > 0
+}
+
+// xlsxAttr gives the value of an element's unprefixed attribute, or an empty
+// string
+func xlsxAttr(se xml.StartElement, local string) string {
+	for _, a := range se.Attr {
+		if a.Name.Space == "" && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
 }
 
-// decode uses specified types from structure's schema to cast xlsx string values to their
-// intended types. If casting fails because the data is invalid, it's left as a string instead
-// of causing an error.
-func (r *XLSXReader) decode(strings []string) ([]interface{}, error) {
+func xlsxReadError(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	log.Debug(err.Error())
+	return fmt.Errorf("error reading xlsx sheet: %w", err)
+}
+
+// decodeSheetCells uses specified types from structure's schema to cast spreadsheet string
+// values to their intended types. If casting fails because the data is invalid, it's left as a
+// string instead of causing an error. Boolean cells are read with bools.
+func decodeSheetCells(types, strings []string, bools *booleanTokens) []interface{} {
 	vs := make([]interface{}, len(strings))
-	types := r.types
 	if len(types) < len(strings) {
 		// TODO - fix. for now is types fails to parse we just assume all types
 		// are strings
@@ -108,7 +701,7 @@ func (r *XLSXReader) decode(strings []string) ([]interface{}, error) {
 				vs[i] = num
 			}
 		case "boolean":
-			if b, err := vals.ParseBoolean([]byte(str)); err == nil {
+			if b, ok := bools.parse(str); ok {
 				vs[i] = b
 			}
 		case "object":
@@ -126,12 +719,7 @@ func (r *XLSXReader) decode(strings []string) ([]interface{}, error) {
 		}
 	}
 
-	return vs, nil
-}
-
-// Close finalizes the writer, indicating no more records will be read
-func (r *XLSXReader) Close() error {
-	return nil
+	return vs
 }
 
 // XLSXWriter implements the RowWriter interface for
@@ -141,7 +729,7 @@ type XLSXWriter struct {
 	sheetName   string
 	f           *excelize.File
 	st          *dataset.Structure
-	w           io.Writer
+	w           *countingWriter
 	types       []string
 }
 
@@ -154,7 +742,7 @@ func NewXLSXWriter(st *dataset.Structure, w io.Writer) (*XLSXWriter, error) {
 		st:    st,
 		f:     excelize.NewFile(),
 		types: types,
-		w:     w,
+		w:     &countingWriter{w: w},
 	}
 
 	if fcg, err := dataset.ParseFormatConfigMap(dataset.XLSXDataFormat, st.FormatConfig); err == nil {
@@ -180,13 +768,24 @@ func (w *XLSXWriter) Structure() *dataset.Structure {
 	return w.st
 }
 
+// EntriesWritten gives the number of entries written
+func (w *XLSXWriter) EntriesWritten() int {
+	return w.rowsWritten
+}
+
+// BytesProcessed gives the number of bytes written. XLSXWriter writes the file
+// on Close, so this is zero until the writer is closed
+func (w *XLSXWriter) BytesProcessed() int64 {
+	return w.w.n
+}
+
 // WriteEntry writes one XLSX record to the writer
 func (w *XLSXWriter) WriteEntry(ent Entry) error {
 	if arr, ok := ent.Value.([]interface{}); ok {
 		strs, err := encodeStrings(arr)
 		if err != nil {
 			log.Debug(err.Error())
-			return fmt.Errorf("error encoding entry: %s", err.Error())
+			return fmt.Errorf("error encoding entry: %w", err)
 		}
 		for i, str := range strs {
 			w.f.SetCellValue(w.sheetName, w.axis(i), str)
@@ -205,6 +804,9 @@ func (w *XLSXWriter) axis(colIDx int) string {
 // will be written
 func (w *XLSXWriter) Close() error {
 	_, err := w.f.WriteTo(w.w)
+	if cerr := w.w.Close(); err == nil {
+		err = cerr
+	}
 	return err
 }
 
@@ -0,0 +1,230 @@
+package dsio
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/qri-io/dataset"
+	"gopkg.in/yaml.v2"
+)
+
+// YAMLReader implements the EntryReader interface for YAML documents. The
+// document's top-level sequence or mapping must match the structure's
+// top-level type, the way JSON bodies do. YAML documents are decoded whole
+// on the first read, object entries are read in key order
+type YAMLReader struct {
+	st          *dataset.Structure
+	src         *TrackedReader
+	tlt         string
+	loaded      bool
+	entries     []Entry
+	entriesRead int
+}
+
+var _ EntryReader = (*YAMLReader)(nil)
+
+// NewYAMLReader creates a reader from a structure and read source
+func NewYAMLReader(st *dataset.Structure, r io.Reader) (*YAMLReader, error) {
+	if st.Schema == nil {
+		err := newKindError(ErrBadSchema, "schema required for YAML reader")
+		log.Debug(err.Error())
+		return nil, err
+	}
+	tlt, err := GetTopLevelType(st)
+	if err != nil {
+		return nil, err
+	}
+	return &YAMLReader{st: st, src: NewTrackedReader(r), tlt: tlt}, nil
+}
+
+// Structure gives this reader's structure
+func (r *YAMLReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry reads one entry from the document
+func (r *YAMLReader) ReadEntry() (Entry, error) {
+	if !r.loaded {
+		if err := r.load(); err != nil {
+			return Entry{}, parseError("yaml", r.entriesRead, err)
+		}
+	}
+	if r.entriesRead == len(r.entries) {
+		return Entry{}, io.EOF
+	}
+	ent := r.entries[r.entriesRead]
+	r.entriesRead++
+	return ent, nil
+}
+
+// load decodes the document into entries
+func (r *YAMLReader) load() error {
+	r.loaded = true
+	var doc interface{}
+	if err := yaml.NewDecoder(r.src).Decode(&doc); err != nil {
+		// an empty document is an empty body
+		if err == io.EOF {
+			return nil
+		}
+		return newKindError(ErrFormatMismatch, fmt.Sprintf("invalid YAML: %s", err))
+	}
+
+	switch v := yamlValue(doc).(type) {
+	case nil:
+	case []interface{}:
+		if r.tlt != "array" {
+			return newKindError(ErrFormatMismatch, "Expected: top-level mapping")
+		}
+		r.entries = make([]Entry, len(v))
+		for i, val := range v {
+			r.entries[i] = Entry{Index: i, Value: val}
+		}
+	case map[string]interface{}:
+		if r.tlt != "object" {
+			return newKindError(ErrFormatMismatch, "Expected: top-level sequence")
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		r.entries = make([]Entry, len(keys))
+		for i, key := range keys {
+			r.entries[i] = Entry{Key: key, Value: v[key]}
+		}
+	default:
+		return newKindError(ErrFormatMismatch, fmt.Sprintf("Expected: top-level sequence or mapping, got %T", v))
+	}
+	return nil
+}
+
+// yamlValue converts decoded YAML mappings, which can have keys of any type,
+// to map[string]interface{}
+func yamlValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[fmt.Sprintf("%v", key)] = yamlValue(val)
+		}
+		return m
+	case map[string]interface{}:
+		for key, val := range t {
+			t[key] = yamlValue(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = yamlValue(val)
+		}
+	}
+	return v
+}
+
+// Close finalizes the reader, closing the source if it's an io.Closer. wrap
+// the source with KeepOpen to leave it open
+func (r *YAMLReader) Close() error {
+	return r.src.Close()
+}
+
+// EntriesRead gives the number of entries read
+func (r *YAMLReader) EntriesRead() int {
+	return r.entriesRead
+}
+
+// BytesProcessed gives the number of bytes parsed. The document is parsed
+// whole by the first read, so all bytes count once reading starts
+func (r *YAMLReader) BytesProcessed() int64 {
+	return int64(r.src.BytesRead())
+}
+
+// YAMLWriter implements the EntryWriter interface for YAML documents,
+// writing array bodies as a sequence & object bodies as a mapping. Entries
+// are buffered & the document is written on Close
+type YAMLWriter struct {
+	st             *dataset.Structure
+	wr             *countingWriter
+	tlt            string
+	array          []interface{}
+	object         map[string]interface{}
+	entriesWritten int
+}
+
+var _ EntryWriter = (*YAMLWriter)(nil)
+
+// NewYAMLWriter creates a writer from a structure and write destination
+func NewYAMLWriter(st *dataset.Structure, w io.Writer) (*YAMLWriter, error) {
+	if st.Schema == nil {
+		err := newKindError(ErrBadSchema, "schema required for YAML writer")
+		log.Debug(err.Error())
+		return nil, err
+	}
+	tlt, err := GetTopLevelType(st)
+	if err != nil {
+		return nil, err
+	}
+	return &YAMLWriter{
+		st:     st,
+		wr:     &countingWriter{w: w},
+		tlt:    tlt,
+		array:  []interface{}{},
+		object: map[string]interface{}{},
+	}, nil
+}
+
+// Structure gives this writer's structure
+func (w *YAMLWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// WriteEntry adds an entry to the document
+func (w *YAMLWriter) WriteEntry(ent Entry) error {
+	if w.tlt == "array" {
+		w.array = append(w.array, ent.Value)
+		w.entriesWritten++
+		return nil
+	}
+
+	if err := dataset.ValidObjectKey(ent.Key); err != nil {
+		log.Debug(err.Error())
+		return err
+	} else if _, ok := w.object[ent.Key]; ok {
+		log.Debugf(`key already written: "%s"`, ent.Key)
+		return fmt.Errorf(`key already written: "%s"`, ent.Key)
+	}
+	w.object[ent.Key] = ent.Value
+	w.entriesWritten++
+	return nil
+}
+
+// EntriesWritten gives the number of entries written
+func (w *YAMLWriter) EntriesWritten() int {
+	return w.entriesWritten
+}
+
+// BytesProcessed gives the number of bytes written
+func (w *YAMLWriter) BytesProcessed() int64 {
+	return w.wr.n
+}
+
+// Close writes the document. The destination is closed if it's an
+// io.Closer, wrap it with KeepWriterOpen to leave it open
+func (w *YAMLWriter) Close() error {
+	if w.wr.closed {
+		return nil
+	}
+	var doc interface{} = w.array
+	if w.tlt == "object" {
+		doc = w.object
+	}
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error encoding yaml: %w", err)
+	}
+	if _, err := w.wr.Write(data); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("error writing yaml: %w", err)
+	}
+	return w.wr.Close()
+}
@@ -0,0 +1,125 @@
+package dsio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestYAMLReader(t *testing.T) {
+	cases := []struct {
+		schema map[string]interface{}
+		data   string
+		keys   []string
+		values []string
+	}{
+		{dataset.BaseSchemaArray, `["a", 2, true, null, {"b": ["c"]}]`, []string{"", "", "", "", ""}, []string{"a", "2", "true", "<nil>", "map[b:[c]]"}},
+		{dataset.BaseSchemaObject, `{"z": 1, "a": "b", "m": [1]}`, []string{"a", "m", "z"}, []string{"b", "[1]", "1"}},
+		{dataset.BaseSchemaArray, ``, nil, nil},
+	}
+
+	for i, c := range cases {
+		r, err := NewEntryReader(&dataset.Structure{Format: "yaml", Schema: c.schema}, strings.NewReader(c.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := range c.keys {
+			ent, err := r.ReadEntry()
+			if err != nil {
+				t.Fatalf("case %d entry %d unexpected error: %s", i, j, err)
+			}
+			if ent.Key != c.keys[j] {
+				t.Errorf("case %d entry %d key mismatch. expected: '%s', got: '%s'", i, j, c.keys[j], ent.Key)
+			}
+			if c.keys[j] == "" && ent.Index != j {
+				t.Errorf("case %d entry %d index mismatch, got: %d", i, j, ent.Index)
+			}
+			if got := fmt.Sprint(ent.Value); got != c.values[j] {
+				t.Errorf("case %d entry %d value mismatch. expected: %s, got: %s", i, j, c.values[j], got)
+			}
+		}
+		if _, err := r.ReadEntry(); err != io.EOF {
+			t.Errorf("case %d expected io.EOF, got: %v", i, err)
+		}
+	}
+}
+
+func TestYAMLReaderErrors(t *testing.T) {
+	cases := []struct {
+		schema map[string]interface{}
+		data   string
+		err    string
+	}{
+		{dataset.BaseSchemaArray, `{"a": 1}`, "Expected: top-level sequence"},
+		{dataset.BaseSchemaObject, `["a"]`, "Expected: top-level mapping"},
+		{dataset.BaseSchemaArray, `"a"`, "Expected: top-level sequence or mapping, got string"},
+	}
+
+	for i, c := range cases {
+		r, err := NewYAMLReader(&dataset.Structure{Format: "yaml", Schema: c.schema}, strings.NewReader(c.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.ReadEntry(); err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+
+	if _, err := NewYAMLReader(&dataset.Structure{Format: "yaml"}, strings.NewReader("")); err == nil {
+		t.Error("expected a missing schema to error")
+	}
+}
+
+func TestYAMLWriter(t *testing.T) {
+	cases := []struct {
+		schema  map[string]interface{}
+		entries []Entry
+	}{
+		{dataset.BaseSchemaArray, []Entry{{Index: 0, Value: "a"}, {Index: 1, Value: map[string]interface{}{"b": []interface{}{"c"}}}}},
+		{dataset.BaseSchemaObject, []Entry{{Key: "a", Value: "b"}, {Key: "c", Value: true}}},
+		{dataset.BaseSchemaArray, nil},
+	}
+
+	for i, c := range cases {
+		st := &dataset.Structure{Format: "yaml", Schema: c.schema}
+		buf := &bytes.Buffer{}
+		w, err := NewEntryWriter(st, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ent := range c.entries {
+			if err := w.WriteEntry(ent); err != nil {
+				t.Fatalf("case %d: %s", i, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// round trip
+		got := readAllEntries(t, st, buf.Bytes())
+		if len(got) != len(c.entries) {
+			t.Fatalf("case %d expected %d entries, got: %d", i, len(c.entries), len(got))
+		}
+		for j, ent := range c.entries {
+			if got[j].Key != ent.Key || fmt.Sprint(got[j].Value) != fmt.Sprint(ent.Value) {
+				t.Errorf("case %d entry %d mismatch. expected: %v, got: %v", i, j, ent, got[j])
+			}
+		}
+	}
+
+	w, err := NewYAMLWriter(&dataset.Structure{Format: "yaml", Schema: dataset.BaseSchemaObject}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Key: "a", Value: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(Entry{Key: "a", Value: 2}); err == nil {
+		t.Error("expected writing a duplicate key to error")
+	}
+}
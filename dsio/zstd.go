@@ -0,0 +1,997 @@
+package dsio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// zstandard frame decoding, see RFC 8878. dictionaries aren't supported
+
+const (
+	zstdMagic = 0xFD2FB528
+	// skippable frames have magic numbers 0x184D2A50 to 0x184D2A5F
+	zstdSkippableMask  = 0xFFFFFFF0
+	zstdSkippableMagic = 0x184D2A50
+	// zstdMaxWindow caps the window size of frames, bounding the memory
+	// decoding uses. 128MB is the default limit of the reference decoder
+	zstdMaxWindow = 1 << 27
+	// zstdMaxBlock is the largest size a block decompresses to
+	zstdMaxBlock = 128 * 1024
+)
+
+// zstdReader decompresses a stream of zstandard frames
+type zstdReader struct {
+	r *bufio.Reader
+	// hist holds decoded bytes, both output that hasn't been read & the
+	// window that matches copy from. out is the position of the first
+	// unread byte
+	hist []byte
+	out  int
+	err  error
+
+	// frame state
+	inFrame  bool
+	window   int
+	checksum bool
+	digest   *xxh64
+	size     int64
+	decoded  int64
+	rep      [3]int
+	huff     *zstdHuffTable
+	tables   [3]*fseTable
+}
+
+// newZstdReader creates a reader decompressing zstandard data from r
+func newZstdReader(r io.Reader) *zstdReader {
+	return &zstdReader{r: bufio.NewReader(r)}
+}
+
+// Read implements the io.Reader interface
+func (z *zstdReader) Read(p []byte) (int, error) {
+	for z.out == len(z.hist) {
+		if z.err != nil {
+			return 0, z.err
+		}
+		z.err = z.next()
+	}
+	n := copy(p, z.hist[z.out:])
+	z.out += n
+	return n, nil
+}
+
+// next decodes the next block, starting a frame if needed
+func (z *zstdReader) next() error {
+	if !z.inFrame {
+		return z.readFrameHeader()
+	}
+	// drop history outside the window once it's twice the window size
+	if len(z.hist) > 2*z.window {
+		n := copy(z.hist, z.hist[len(z.hist)-z.window:])
+		z.hist = z.hist[:n]
+		z.out = n
+	}
+
+	var header [3]byte
+	if _, err := io.ReadFull(z.r, header[:]); err != nil {
+		return zstdCorrupt("reading block header: %s", err)
+	}
+	h := uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16
+	last := h&1 == 1
+	size := int(h >> 3)
+	start := len(z.hist)
+
+	switch (h >> 1) & 3 {
+	case 0: // raw
+		if size > zstdMaxBlock {
+			return zstdCorrupt("block size %d exceeds maximum", size)
+		}
+		z.hist = append(z.hist, make([]byte, size)...)
+		if _, err := io.ReadFull(z.r, z.hist[start:]); err != nil {
+			return zstdCorrupt("reading raw block: %s", err)
+		}
+	case 1: // rle
+		if size > zstdMaxBlock {
+			return zstdCorrupt("block size %d exceeds maximum", size)
+		}
+		b, err := z.r.ReadByte()
+		if err != nil {
+			return zstdCorrupt("reading rle block: %s", err)
+		}
+		for i := 0; i < size; i++ {
+			z.hist = append(z.hist, b)
+		}
+	case 2: // compressed
+		if size > zstdMaxBlock {
+			return zstdCorrupt("block size %d exceeds maximum", size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(z.r, data); err != nil {
+			return zstdCorrupt("reading compressed block: %s", err)
+		}
+		if err := z.decodeBlock(data); err != nil {
+			return err
+		}
+		if len(z.hist)-start > zstdMaxBlock {
+			return zstdCorrupt("block decompresses to more than %d bytes", zstdMaxBlock)
+		}
+	default:
+		return zstdCorrupt("reserved block type")
+	}
+
+	z.decoded += int64(len(z.hist) - start)
+	if z.checksum {
+		z.digest.Write(z.hist[start:])
+	}
+	if last {
+		return z.finishFrame()
+	}
+	return nil
+}
+
+// readFrameHeader starts a frame, skipping skippable frames. io.EOF is only
+// returned at the end of a frame
+func (z *zstdReader) readFrameHeader() error {
+	for {
+		var magic [4]byte
+		if _, err := io.ReadFull(z.r, magic[:]); err != nil {
+			if err == io.EOF {
+				return io.EOF
+			}
+			return zstdCorrupt("reading frame magic: %s", err)
+		}
+		m := binary.LittleEndian.Uint32(magic[:])
+		if m&zstdSkippableMask == zstdSkippableMagic {
+			var size [4]byte
+			if _, err := io.ReadFull(z.r, size[:]); err != nil {
+				return zstdCorrupt("reading skippable frame: %s", err)
+			}
+			if _, err := io.CopyN(io.Discard, z.r, int64(binary.LittleEndian.Uint32(size[:]))); err != nil {
+				return zstdCorrupt("reading skippable frame: %s", err)
+			}
+			continue
+		}
+		if m != zstdMagic {
+			return zstdCorrupt("invalid magic number")
+		}
+		break
+	}
+
+	desc, err := z.r.ReadByte()
+	if err != nil {
+		return zstdCorrupt("reading frame header: %s", err)
+	}
+	if desc&0x08 != 0 {
+		return zstdCorrupt("reserved frame header bit set")
+	}
+	singleSegment := desc&0x20 != 0
+	z.checksum = desc&0x04 != 0
+	dictSize := []int{0, 1, 2, 4}[desc&3]
+	sizeSize := []int{0, 2, 4, 8}[desc>>6]
+	if sizeSize == 0 && singleSegment {
+		sizeSize = 1
+	}
+
+	z.window = 0
+	if !singleSegment {
+		wd, err := z.r.ReadByte()
+		if err != nil {
+			return zstdCorrupt("reading window descriptor: %s", err)
+		}
+		exp := uint(wd>>3) + 10
+		if exp > 41 {
+			return zstdCorrupt("invalid window size")
+		}
+		base := uint64(1) << exp
+		size := base + base/8*uint64(wd&7)
+		if size > zstdMaxWindow {
+			return fmt.Errorf("zstd window size %d exceeds limit of %d", size, zstdMaxWindow)
+		}
+		z.window = int(size)
+	}
+
+	var buf [8]byte
+	if _, err := io.ReadFull(z.r, buf[:dictSize]); err != nil {
+		return zstdCorrupt("reading dictionary id: %s", err)
+	}
+	if binary.LittleEndian.Uint32(append(buf[:dictSize:dictSize], 0, 0, 0, 0)) != 0 {
+		return fmt.Errorf("zstd dictionaries aren't supported")
+	}
+	z.size = -1
+	if sizeSize > 0 {
+		buf = [8]byte{}
+		if _, err := io.ReadFull(z.r, buf[:sizeSize]); err != nil {
+			return zstdCorrupt("reading frame content size: %s", err)
+		}
+		z.size = int64(binary.LittleEndian.Uint64(buf[:]))
+		if sizeSize == 2 {
+			z.size += 256
+		}
+	}
+	if singleSegment {
+		if z.size > zstdMaxWindow {
+			return fmt.Errorf("zstd window size %d exceeds limit of %d", z.size, zstdMaxWindow)
+		}
+		z.window = int(z.size)
+	}
+
+	// frames are independent, matches can't reach into earlier frames
+	z.hist = z.hist[:0]
+	z.out = 0
+	z.inFrame = true
+	z.decoded = 0
+	z.rep = [3]int{1, 4, 8}
+	z.huff = nil
+	z.tables = [3]*fseTable{}
+	if z.checksum {
+		z.digest = newXXH64()
+	}
+	return nil
+}
+
+// finishFrame checks the content size & checksum of a decoded frame
+func (z *zstdReader) finishFrame() error {
+	z.inFrame = false
+	if z.size >= 0 && z.decoded != z.size {
+		return zstdCorrupt("frame decoded to %d bytes, expected %d", z.decoded, z.size)
+	}
+	if z.checksum {
+		var sum [4]byte
+		if _, err := io.ReadFull(z.r, sum[:]); err != nil {
+			return zstdCorrupt("reading checksum: %s", err)
+		}
+		if binary.LittleEndian.Uint32(sum[:]) != uint32(z.digest.Sum64()) {
+			return zstdCorrupt("checksum mismatch")
+		}
+	}
+	return nil
+}
+
+// decodeBlock decodes a compressed block, appending to hist
+func (z *zstdReader) decodeBlock(data []byte) error {
+	literals, n, err := z.decodeLiterals(data)
+	if err != nil {
+		return err
+	}
+	data = data[n:]
+
+	if len(data) == 0 {
+		return zstdCorrupt("missing sequences section")
+	}
+	var nbSeq int
+	switch b0 := int(data[0]); {
+	case b0 < 128:
+		nbSeq, data = b0, data[1:]
+	case b0 < 255:
+		if len(data) < 2 {
+			return zstdCorrupt("truncated sequences header")
+		}
+		nbSeq, data = (b0-128)<<8+int(data[1]), data[2:]
+	default:
+		if len(data) < 3 {
+			return zstdCorrupt("truncated sequences header")
+		}
+		nbSeq, data = int(data[1])+int(data[2])<<8+0x7F00, data[3:]
+	}
+	if nbSeq == 0 {
+		z.hist = append(z.hist, literals...)
+		return nil
+	}
+
+	if len(data) == 0 {
+		return zstdCorrupt("missing symbol compression modes")
+	}
+	modes := data[0]
+	if modes&3 != 0 {
+		return zstdCorrupt("reserved symbol compression mode bits set")
+	}
+	data = data[1:]
+	for i, kind := range []int{zstdLiteralLengths, zstdOffsets, zstdMatchLengths} {
+		mode := modes >> (6 - 2*uint(i)) & 3
+		if data, err = z.readSequenceTable(kind, mode, data); err != nil {
+			return err
+		}
+	}
+	return z.executeSequences(literals, nbSeq, data)
+}
+
+// symbol kinds of sequences, indexes of zstdReader.tables
+const (
+	zstdLiteralLengths = iota
+	zstdOffsets
+	zstdMatchLengths
+)
+
+// readSequenceTable reads the decoding table of one sequence symbol kind
+func (z *zstdReader) readSequenceTable(kind int, mode byte, data []byte) ([]byte, error) {
+	switch mode {
+	case 0: // predefined
+		z.tables[kind] = zstdPredefinedTables[kind]
+	case 1: // rle
+		if len(data) == 0 {
+			return nil, zstdCorrupt("missing rle symbol")
+		}
+		if int(data[0]) > zstdMaxSymbols[kind] {
+			return nil, zstdCorrupt("invalid rle symbol %d", data[0])
+		}
+		z.tables[kind] = &fseTable{accuracyLog: 0, symbols: []uint8{data[0]}, numBits: []uint8{0}, base: []uint16{0}}
+		data = data[1:]
+	case 2: // fse compressed
+		t, n, err := readFSETable(data, zstdMaxAccuracy[kind], zstdMaxSymbols[kind])
+		if err != nil {
+			return nil, err
+		}
+		z.tables[kind] = t
+		data = data[n:]
+	case 3: // repeat
+		if z.tables[kind] == nil {
+			return nil, zstdCorrupt("repeat mode without a previous table")
+		}
+	}
+	return data, nil
+}
+
+// executeSequences decodes sequences from a bitstream & executes them,
+// appending literals & matches to hist
+func (z *zstdReader) executeSequences(literals []byte, nbSeq int, data []byte) error {
+	br, err := newBackwardBitReader(data)
+	if err != nil {
+		return err
+	}
+	ll, of, ml := z.tables[zstdLiteralLengths], z.tables[zstdOffsets], z.tables[zstdMatchLengths]
+	llState := int(br.read(ll.accuracyLog))
+	ofState := int(br.read(of.accuracyLog))
+	mlState := int(br.read(ml.accuracyLog))
+
+	for i := 0; i < nbSeq; i++ {
+		ofCode := int(of.symbols[ofState])
+		mlCode := int(ml.symbols[mlState])
+		llCode := int(ll.symbols[llState])
+		if ofCode > 31 {
+			return zstdCorrupt("invalid offset code %d", ofCode)
+		}
+
+		offsetValue := 1<<uint(ofCode) + int(br.read(uint(ofCode)))
+		matchLen := int(zstdMatchLengthBase[mlCode]) + int(br.read(uint(zstdMatchLengthBits[mlCode])))
+		litLen := int(zstdLiteralLengthBase[llCode]) + int(br.read(uint(zstdLiteralLengthBits[llCode])))
+
+		// resolve repeat offsets
+		var offset int
+		if offsetValue > 3 {
+			offset = offsetValue - 3
+			z.rep = [3]int{offset, z.rep[0], z.rep[1]}
+		} else {
+			idx := offsetValue - 1
+			if litLen == 0 {
+				idx++
+			}
+			if idx == 0 {
+				offset = z.rep[0]
+			} else {
+				if idx < 3 {
+					offset = z.rep[idx]
+				} else {
+					offset = z.rep[0] - 1
+				}
+				if idx > 1 {
+					z.rep[2] = z.rep[1]
+				}
+				z.rep[1] = z.rep[0]
+				z.rep[0] = offset
+			}
+		}
+
+		if i+1 < nbSeq {
+			llState = int(ll.base[llState]) + int(br.read(uint(ll.numBits[llState])))
+			mlState = int(ml.base[mlState]) + int(br.read(uint(ml.numBits[mlState])))
+			ofState = int(of.base[ofState]) + int(br.read(uint(of.numBits[ofState])))
+		}
+
+		if litLen > len(literals) {
+			return zstdCorrupt("literal length %d exceeds literals", litLen)
+		}
+		z.hist = append(z.hist, literals[:litLen]...)
+		literals = literals[litLen:]
+		if offset <= 0 || offset > len(z.hist) || offset > z.window && z.window > 0 {
+			return zstdCorrupt("invalid match offset %d", offset)
+		}
+		if len(z.hist)+matchLen-z.out > zstdMaxBlock+z.window {
+			return zstdCorrupt("block decompresses to more than %d bytes", zstdMaxBlock)
+		}
+		// matches can overlap the bytes they produce, copy byte by byte
+		pos := len(z.hist) - offset
+		for j := 0; j < matchLen; j++ {
+			z.hist = append(z.hist, z.hist[pos+j])
+		}
+	}
+	if br.overflowed() || br.pos != 0 {
+		return zstdCorrupt("sequence bitstream not fully consumed")
+	}
+	z.hist = append(z.hist, literals...)
+	return nil
+}
+
+// decodeLiterals decodes the literals section of a block, returning the
+// literals & the size of the section
+func (z *zstdReader) decodeLiterals(data []byte) ([]byte, int, error) {
+	if len(data) == 0 {
+		return nil, 0, zstdCorrupt("missing literals section")
+	}
+	litType := data[0] & 3
+	sizeFormat := (data[0] >> 2) & 3
+
+	if litType < 2 {
+		var size, n int
+		switch sizeFormat {
+		case 0, 2:
+			size, n = int(data[0]>>3), 1
+		case 1:
+			if len(data) < 2 {
+				return nil, 0, zstdCorrupt("truncated literals header")
+			}
+			size, n = int(data[0]>>4)+int(data[1])<<4, 2
+		case 3:
+			if len(data) < 3 {
+				return nil, 0, zstdCorrupt("truncated literals header")
+			}
+			size, n = int(data[0]>>4)+int(data[1])<<4+int(data[2])<<12, 3
+		}
+		if size > zstdMaxBlock {
+			return nil, 0, zstdCorrupt("literals size %d exceeds maximum", size)
+		}
+		if litType == 0 {
+			if len(data) < n+size {
+				return nil, 0, zstdCorrupt("truncated raw literals")
+			}
+			return data[n : n+size], n + size, nil
+		}
+		if len(data) < n+1 {
+			return nil, 0, zstdCorrupt("truncated rle literals")
+		}
+		lits := make([]byte, size)
+		for i := range lits {
+			lits[i] = data[n]
+		}
+		return lits, n + 1, nil
+	}
+
+	// compressed & treeless literals
+	var headerSize, sizeBits, streams int
+	switch sizeFormat {
+	case 0:
+		headerSize, sizeBits, streams = 3, 10, 1
+	case 1:
+		headerSize, sizeBits, streams = 3, 10, 4
+	case 2:
+		headerSize, sizeBits, streams = 4, 14, 4
+	case 3:
+		headerSize, sizeBits, streams = 5, 18, 4
+	}
+	if len(data) < headerSize {
+		return nil, 0, zstdCorrupt("truncated literals header")
+	}
+	var h uint64
+	for i := headerSize - 1; i >= 0; i-- {
+		h = h<<8 | uint64(data[i])
+	}
+	mask := uint64(1)<<uint(sizeBits) - 1
+	regenerated := int(h >> 4 & mask)
+	compressed := int(h >> (4 + uint(sizeBits)) & mask)
+	if regenerated > zstdMaxBlock {
+		return nil, 0, zstdCorrupt("literals size %d exceeds maximum", regenerated)
+	}
+	if len(data) < headerSize+compressed {
+		return nil, 0, zstdCorrupt("truncated compressed literals")
+	}
+	src := data[headerSize : headerSize+compressed]
+
+	if litType == 2 {
+		table, n, err := readHuffTable(src)
+		if err != nil {
+			return nil, 0, err
+		}
+		z.huff = table
+		src = src[n:]
+	} else if z.huff == nil {
+		return nil, 0, zstdCorrupt("treeless literals without a previous huffman table")
+	}
+
+	lits := make([]byte, regenerated)
+	if streams == 1 {
+		if err := z.huff.decode(src, lits); err != nil {
+			return nil, 0, err
+		}
+		return lits, headerSize + compressed, nil
+	}
+
+	if len(src) < 6 {
+		return nil, 0, zstdCorrupt("truncated literals jump table")
+	}
+	sizes := [4]int{
+		int(binary.LittleEndian.Uint16(src)),
+		int(binary.LittleEndian.Uint16(src[2:])),
+		int(binary.LittleEndian.Uint16(src[4:])),
+	}
+	src = src[6:]
+	sizes[3] = len(src) - sizes[0] - sizes[1] - sizes[2]
+	if sizes[3] < 0 {
+		return nil, 0, zstdCorrupt("invalid literals jump table")
+	}
+	segment := (regenerated + 3) / 4
+	if 3*segment > regenerated {
+		return nil, 0, zstdCorrupt("invalid literals size %d for 4 streams", regenerated)
+	}
+	for i, size := range sizes {
+		end := (i + 1) * segment
+		if i == 3 {
+			end = regenerated
+		}
+		if err := z.huff.decode(src[:size], lits[i*segment:end]); err != nil {
+			return nil, 0, err
+		}
+		src = src[size:]
+	}
+	return lits, headerSize + compressed, nil
+}
+
+// zstdHuffTable decodes huffman coded literals
+type zstdHuffTable struct {
+	maxBits uint
+	symbols []uint8
+	numBits []uint8
+}
+
+// readHuffTable reads a huffman tree description, returning the table & the
+// size of the description
+func readHuffTable(data []byte) (*zstdHuffTable, int, error) {
+	if len(data) == 0 {
+		return nil, 0, zstdCorrupt("missing huffman tree description")
+	}
+	var (
+		weights []uint8
+		n       int
+	)
+	if header := int(data[0]); header < 128 {
+		// fse compressed weights
+		n = 1 + header
+		if len(data) < n {
+			return nil, 0, zstdCorrupt("truncated huffman weights")
+		}
+		t, tn, err := readFSETable(data[1:n], 6, 255)
+		if err != nil {
+			return nil, 0, err
+		}
+		br, err := newBackwardBitReader(data[1+tn : n])
+		if err != nil {
+			return nil, 0, err
+		}
+		s1, s2 := int(br.read(t.accuracyLog)), int(br.read(t.accuracyLog))
+		// decode until the stream overflows, alternating states
+		for {
+			if len(weights) > 255 {
+				return nil, 0, zstdCorrupt("too many huffman weights")
+			}
+			weights = append(weights, t.symbols[s1])
+			s1 = int(t.base[s1]) + int(br.read(uint(t.numBits[s1])))
+			if br.overflowed() {
+				weights = append(weights, t.symbols[s2])
+				break
+			}
+			weights = append(weights, t.symbols[s2])
+			s2 = int(t.base[s2]) + int(br.read(uint(t.numBits[s2])))
+			if br.overflowed() {
+				weights = append(weights, t.symbols[s1])
+				break
+			}
+		}
+	} else {
+		// 4 bit weights
+		count := header - 127
+		n = 1 + (count+1)/2
+		if len(data) < n {
+			return nil, 0, zstdCorrupt("truncated huffman weights")
+		}
+		for i := 0; i < count; i++ {
+			b := data[1+i/2]
+			if i%2 == 0 {
+				weights = append(weights, b>>4)
+			} else {
+				weights = append(weights, b&0xf)
+			}
+		}
+	}
+	if len(weights) > 255 {
+		return nil, 0, zstdCorrupt("too many huffman weights")
+	}
+
+	// the weight of the last symbol is implied by the others
+	var sum int
+	for _, w := range weights {
+		if w > 11 {
+			return nil, 0, zstdCorrupt("invalid huffman weight %d", w)
+		}
+		if w > 0 {
+			sum += 1 << (w - 1)
+		}
+	}
+	if sum == 0 {
+		return nil, 0, zstdCorrupt("invalid huffman weights")
+	}
+	maxBits := uint(bits.Len(uint(sum)))
+	left := 1<<maxBits - sum
+	if left&(left-1) != 0 || maxBits > 11 {
+		return nil, 0, zstdCorrupt("invalid huffman weights")
+	}
+	weights = append(weights, uint8(bits.Len(uint(left))))
+
+	// fill the table in order of weight, then symbol
+	var rankCount [13]int
+	for _, w := range weights {
+		rankCount[w]++
+	}
+	var rankStart [13]int
+	next := 0
+	for w := 1; w <= int(maxBits); w++ {
+		rankStart[w] = next
+		next += rankCount[w] << uint(w-1)
+	}
+	t := &zstdHuffTable{
+		maxBits: maxBits,
+		symbols: make([]uint8, 1<<maxBits),
+		numBits: make([]uint8, 1<<maxBits),
+	}
+	for s, w := range weights {
+		if w == 0 {
+			continue
+		}
+		length := 1 << (w - 1)
+		start := rankStart[w]
+		for i := start; i < start+length; i++ {
+			t.symbols[i] = uint8(s)
+			t.numBits[i] = uint8(maxBits + 1 - uint(w))
+		}
+		rankStart[w] += length
+	}
+	return t, n, nil
+}
+
+// decode decodes a huffman coded stream, filling out
+func (t *zstdHuffTable) decode(data []byte, out []byte) error {
+	br, err := newBackwardBitReader(data)
+	if err != nil {
+		return err
+	}
+	for i := range out {
+		idx := br.peek(t.maxBits)
+		out[i] = t.symbols[idx]
+		br.pos -= int(t.numBits[idx])
+	}
+	if br.pos != 0 {
+		return zstdCorrupt("huffman stream not fully consumed")
+	}
+	return nil
+}
+
+// fseTable is a finite state entropy decoding table
+type fseTable struct {
+	accuracyLog uint
+	symbols     []uint8
+	numBits     []uint8
+	base        []uint16
+}
+
+// readFSETable reads an FSE table description, returning the table & the
+// number of bytes read
+func readFSETable(data []byte, maxAccuracy uint, maxSymbol int) (*fseTable, int, error) {
+	br := &forwardBitReader{b: data}
+	accuracyLog := uint(br.read(4)) + 5
+	if accuracyLog > maxAccuracy {
+		return nil, 0, zstdCorrupt("fse accuracy log %d exceeds maximum", accuracyLog)
+	}
+
+	remaining := 1 << accuracyLog
+	var probs []int
+	for remaining > 0 && len(probs) <= maxSymbol {
+		nbits := uint(bits.Len(uint(remaining + 1)))
+		val := int(br.read(nbits))
+		lowerMask := 1<<(nbits-1) - 1
+		threshold := 1<<nbits - 1 - (remaining + 1)
+		if val&lowerMask < threshold {
+			br.pos--
+			val &= lowerMask
+		} else if val > lowerMask {
+			val -= threshold
+		}
+		prob := val - 1
+		if prob < 0 {
+			remaining += prob
+		} else {
+			remaining -= prob
+		}
+		probs = append(probs, prob)
+		if prob == 0 {
+			for {
+				repeat := int(br.read(2))
+				for i := 0; i < repeat && len(probs) <= maxSymbol; i++ {
+					probs = append(probs, 0)
+				}
+				if repeat != 3 {
+					break
+				}
+			}
+		}
+		if br.pos > 8*len(data) {
+			return nil, 0, zstdCorrupt("truncated fse table description")
+		}
+	}
+	if remaining != 0 || len(probs) > maxSymbol+1 {
+		return nil, 0, zstdCorrupt("invalid fse table description")
+	}
+	t, err := newFSETable(probs, accuracyLog)
+	return t, (br.pos + 7) / 8, err
+}
+
+// newFSETable builds a decoding table from normalized probabilities, where
+// -1 is a "less than one" probability
+func newFSETable(probs []int, accuracyLog uint) (*fseTable, error) {
+	size := 1 << accuracyLog
+	t := &fseTable{
+		accuracyLog: accuracyLog,
+		symbols:     make([]uint8, size),
+		numBits:     make([]uint8, size),
+		base:        make([]uint16, size),
+	}
+	next := make([]int, len(probs))
+	high := size
+	for s, p := range probs {
+		if p == -1 {
+			high--
+			t.symbols[high] = uint8(s)
+			next[s] = 1
+		}
+	}
+	step := size>>1 + size>>3 + 3
+	pos := 0
+	for s, p := range probs {
+		if p <= 0 {
+			continue
+		}
+		next[s] = p
+		for i := 0; i < p; i++ {
+			t.symbols[pos] = uint8(s)
+			for pos = (pos + step) & (size - 1); pos >= high; pos = (pos + step) & (size - 1) {
+			}
+		}
+	}
+	if pos != 0 {
+		return nil, zstdCorrupt("invalid fse probabilities")
+	}
+	for i := 0; i < size; i++ {
+		s := t.symbols[i]
+		desc := next[s]
+		next[s]++
+		nb := accuracyLog - uint(bits.Len(uint(desc))-1)
+		t.numBits[i] = uint8(nb)
+		t.base[i] = uint16(desc<<nb - size)
+	}
+	return t, nil
+}
+
+// forwardBitReader reads bits from the lowest bit of the first byte up
+type forwardBitReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *forwardBitReader) read(n uint) uint64 {
+	var v uint64
+	for i := uint(0); i < n; i++ {
+		byteIdx := r.pos >> 3
+		if byteIdx < len(r.b) && r.b[byteIdx]>>(uint(r.pos)&7)&1 == 1 {
+			v |= 1 << i
+		}
+		r.pos++
+	}
+	return v
+}
+
+// backwardBitReader reads bits from the end of a stream towards it's start,
+// where the highest set bit of the last byte marks the start of data. Reads
+// past the start of the stream give zero bits
+type backwardBitReader struct {
+	b []byte
+	// pos is the number of unread bits, negative after reading past the
+	// start of the stream
+	pos int
+}
+
+func newBackwardBitReader(b []byte) (*backwardBitReader, error) {
+	if len(b) == 0 || b[len(b)-1] == 0 {
+		return nil, zstdCorrupt("invalid bitstream padding")
+	}
+	return &backwardBitReader{b: b, pos: (len(b)-1)*8 + bits.Len8(b[len(b)-1]) - 1}, nil
+}
+
+// peek gives the next n bits without consuming them
+func (r *backwardBitReader) peek(n uint) uint64 {
+	if n == 0 {
+		return 0
+	}
+	start := r.pos - int(n)
+	var v uint64
+	// gather the bytes covering [start, pos)
+	for i := (r.pos - 1) >> 3; i >= 0 && i >= start>>3; i-- {
+		v = v<<8 | uint64(r.b[i])
+	}
+	lowByte := start >> 3
+	if start < 0 {
+		lowByte = 0
+		// missing bits below the stream are zeros
+		v <<= uint(-start)
+		start = 0
+	}
+	v >>= uint(start - lowByte*8)
+	return v & (1<<n - 1)
+}
+
+func (r *backwardBitReader) read(n uint) uint64 {
+	v := r.peek(n)
+	r.pos -= int(n)
+	return v
+}
+
+func (r *backwardBitReader) overflowed() bool {
+	return r.pos < 0
+}
+
+func zstdCorrupt(format string, args ...interface{}) error {
+	return fmt.Errorf("corrupt zstd data: "+format, args...)
+}
+
+// sequence code tables
+var (
+	zstdMaxAccuracy = [3]uint{9, 8, 9}
+	zstdMaxSymbols  = [3]int{35, 31, 52}
+
+	zstdLiteralLengthBase = [36]uint32{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		16, 18, 20, 22, 24, 28, 32, 40, 48, 64, 128, 256, 512, 1024, 2048, 4096,
+		8192, 16384, 32768, 65536,
+	}
+	zstdLiteralLengthBits = [36]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 6, 7, 8, 9, 10, 11, 12,
+		13, 14, 15, 16,
+	}
+	zstdMatchLengthBase = [53]uint32{
+		3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18,
+		19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34,
+		35, 37, 39, 41, 43, 47, 51, 59, 67, 83, 99, 131, 259, 515, 1027, 2051,
+		4099, 8195, 16387, 32771, 65539,
+	}
+	zstdMatchLengthBits = [53]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 7, 8, 9, 10, 11,
+		12, 13, 14, 15, 16,
+	}
+
+	zstdPredefinedTables = [3]*fseTable{
+		zstdMustFSETable([]int{
+			4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
+			2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+			-1, -1, -1, -1,
+		}, 6),
+		zstdMustFSETable([]int{
+			1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+			1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+		}, 5),
+		zstdMustFSETable([]int{
+			1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+			1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+			1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
+			-1, -1, -1, -1, -1,
+		}, 6),
+	}
+)
+
+func zstdMustFSETable(probs []int, accuracyLog uint) *fseTable {
+	t, err := newFSETable(probs, accuracyLog)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// xxh64 computes XXH64 hashes with a seed of zero, as used by zstandard frame
+// checksums
+type xxh64 struct {
+	v     [4]uint64
+	total uint64
+	buf   [32]byte
+	n     int
+}
+
+const (
+	xxhPrime1 uint64 = 11400714785074694791
+	xxhPrime2 uint64 = 14029467366897019727
+	xxhPrime3 uint64 = 1609587929392839161
+	xxhPrime4 uint64 = 9650029242287828579
+	xxhPrime5 uint64 = 2870177450012600261
+)
+
+func newXXH64() *xxh64 {
+	p1 := xxhPrime1
+	return &xxh64{v: [4]uint64{p1 + xxhPrime2, xxhPrime2, 0, -p1}}
+}
+
+func xxhRound(acc, lane uint64) uint64 {
+	return bits.RotateLeft64(acc+lane*xxhPrime2, 31) * xxhPrime1
+}
+
+func xxhMerge(acc, val uint64) uint64 {
+	return (acc^xxhRound(0, val))*xxhPrime1 + xxhPrime4
+}
+
+// Write adds p to the hash
+func (x *xxh64) Write(p []byte) {
+	x.total += uint64(len(p))
+	if x.n > 0 {
+		c := copy(x.buf[x.n:], p)
+		x.n += c
+		p = p[c:]
+		if x.n < 32 {
+			return
+		}
+		x.stripe(x.buf[:])
+		x.n = 0
+	}
+	for ; len(p) >= 32; p = p[32:] {
+		x.stripe(p)
+	}
+	x.n = copy(x.buf[:], p)
+}
+
+func (x *xxh64) stripe(p []byte) {
+	for i := range x.v {
+		x.v[i] = xxhRound(x.v[i], binary.LittleEndian.Uint64(p[8*i:]))
+	}
+}
+
+// Sum64 gives the hash of the data written so far
+func (x *xxh64) Sum64() uint64 {
+	var h uint64
+	if x.total >= 32 {
+		h = bits.RotateLeft64(x.v[0], 1) + bits.RotateLeft64(x.v[1], 7) +
+			bits.RotateLeft64(x.v[2], 12) + bits.RotateLeft64(x.v[3], 18)
+		for _, v := range x.v {
+			h = xxhMerge(h, v)
+		}
+	} else {
+		h = xxhPrime5
+	}
+	h += x.total
+
+	p := x.buf[:x.n]
+	for ; len(p) >= 8; p = p[8:] {
+		h ^= xxhRound(0, binary.LittleEndian.Uint64(p))
+		h = bits.RotateLeft64(h, 27)*xxhPrime1 + xxhPrime4
+	}
+	if len(p) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(p)) * xxhPrime1
+		h = bits.RotateLeft64(h, 23)*xxhPrime2 + xxhPrime3
+		p = p[4:]
+	}
+	for _, b := range p {
+		h ^= uint64(b) * xxhPrime5
+		h = bits.RotateLeft64(h, 11) * xxhPrime1
+	}
+	h ^= h >> 33
+	h *= xxhPrime2
+	h ^= h >> 29
+	h *= xxhPrime3
+	h ^= h >> 32
+	return h
+}
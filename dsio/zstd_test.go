@@ -0,0 +1,120 @@
+package dsio
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestZstdReader(t *testing.T) {
+	cases := []struct {
+		description string
+		input       string
+		expect      string
+	}{
+		{"raw block with checksum", "28b52ffd0458410000612c620a312c320a35e7cace", "a,b\n1,2\n"},
+		{"rle literals, repeated matches", "28b52ffda0e09304005400001061610100fbff39c00202001061039f0461", strings.Repeat("a", 300000)},
+		{"skippable frame", "502a4d180300000001020328b52ffd0458410000612c620a312c320a35e7cace", "a,b\n1,2\n"},
+		{"concatenated frames", "28b52ffd0458410000612c620a312c320a35e7cace28b52ffd0458410000612c620a312c320a35e7cace", "a,b\n1,2\na,b\n1,2\n"},
+		{"empty stream", "", ""},
+	}
+	for _, c := range cases {
+		data, err := hex.DecodeString(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(newZstdReader(bytes.NewReader(data)))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.description, err)
+			continue
+		}
+		if string(got) != c.expect {
+			t.Errorf("%s: expected %d bytes, got %d", c.description, len(c.expect), len(got))
+		}
+	}
+}
+
+func TestZstdReaderFiles(t *testing.T) {
+	cases := []struct {
+		compressed, expect string
+	}{
+		{"testdata/movies/body.csv.zst", "testdata/movies/body.csv"},
+		{"testdata/movies/body.json.zst", "testdata/movies/body.json"},
+	}
+	for _, c := range cases {
+		expect, err := os.ReadFile(c.expect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(c.compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(newZstdReader(f))
+		f.Close()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.compressed, err)
+			continue
+		}
+		if !bytes.Equal(got, expect) {
+			t.Errorf("%s: decompressed bytes mismatch", c.compressed)
+		}
+	}
+}
+
+func TestZstdReaderErrors(t *testing.T) {
+	cases := []struct {
+		description string
+		input       string
+		err         string
+	}{
+		{"bad magic", "28b52ffe00", "corrupt zstd data: invalid magic number"},
+		{"truncated magic", "28b5", "corrupt zstd data: reading frame magic: unexpected EOF"},
+		{"checksum mismatch", "28b52ffd0458410000612c620a312c320a35e7cacf", "corrupt zstd data: checksum mismatch"},
+		{"truncated block", "28b52ffd0458410000612c62", "corrupt zstd data: reading raw block: unexpected EOF"},
+		{"content size mismatch", "28b52ffd2409410000612c620a312c320a", "corrupt zstd data: frame decoded to 8 bytes, expected 9"},
+		{"reserved block type", "28b52ffd0058070000", "corrupt zstd data: reserved block type"},
+		{"dictionary", "28b52ffd0158014100000100", "zstd dictionaries aren't supported"},
+		{"window too large", "28b52ffd00f8", "zstd window size 2199023255552 exceeds limit of 134217728"},
+	}
+	for _, c := range cases {
+		data, err := hex.DecodeString(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = io.ReadAll(newZstdReader(bytes.NewReader(data)))
+		if err == nil || err.Error() != c.err {
+			t.Errorf("%s: expected error: %q, got: %v", c.description, c.err, err)
+		}
+	}
+}
+
+func TestXXH64(t *testing.T) {
+	cases := []struct {
+		input  string
+		expect uint64
+	}{
+		{"", 0xEF46DB3751D8E999},
+		{"a", 0xD24EC4F1A98C6E5B},
+		{"abc", 0x44BC2CF5AD770999},
+		{strings.Repeat("0123456789", 10), 0xF80E7B96315AFFFA},
+	}
+	for _, c := range cases {
+		x := newXXH64()
+		x.Write([]byte(c.input))
+		if got := x.Sum64(); got != c.expect {
+			t.Errorf("%q: expected %x, got %x", c.input, c.expect, got)
+		}
+		// writing in pieces gives the same hash
+		x = newXXH64()
+		for i := 0; i < len(c.input); i += 7 {
+			x.Write([]byte(c.input[i:min(i+7, len(c.input))]))
+		}
+		if got := x.Sum64(); got != c.expect {
+			t.Errorf("%q in pieces: expected %x, got %x", c.input, c.expect, got)
+		}
+	}
+}
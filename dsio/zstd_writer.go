@@ -0,0 +1,319 @@
+package dsio
+
+import (
+	"encoding/binary"
+	"io"
+	"math/bits"
+	"sort"
+)
+
+// zstandard compression. zstdWriter finds matches with a single hash table,
+// keeps literals uncompressed & codes sequences with the predefined FSE
+// tables, trading ratio for a small encoder. Repetitive bodies like JSON
+// still compress well
+
+const (
+	// zstdWriterWindowLog sets the window matches are found in, 1MB
+	zstdWriterWindowLog = 20
+	zstdWriterWindow    = 1 << zstdWriterWindowLog
+	zstdHashLog         = 16
+	zstdMinMatch        = 4
+	// zstdMaxMatch is the longest match length sequences can code
+	zstdMaxMatch = 65539 + 1<<16 - 1
+)
+
+// zstdWriter compresses bytes written to it into a single zstandard frame
+type zstdWriter struct {
+	w   io.Writer
+	err error
+	// hist holds the window of already compressed bytes followed by bytes
+	// that haven't been compressed. pending is the position of the first
+	// uncompressed byte
+	hist    []byte
+	pending int
+	table   []int32
+	digest  *xxh64
+	started bool
+	closed  bool
+}
+
+// newZstdWriter creates a writer compressing to w
+func newZstdWriter(w io.Writer) *zstdWriter {
+	return &zstdWriter{w: w, table: make([]int32, 1<<zstdHashLog), digest: newXXH64()}
+}
+
+// Write implements the io.Writer interface
+func (z *zstdWriter) Write(p []byte) (int, error) {
+	if z.err != nil {
+		return 0, z.err
+	}
+	z.digest.Write(p)
+	z.hist = append(z.hist, p...)
+	for len(z.hist)-z.pending > zstdMaxBlock {
+		if z.err = z.writeBlock(z.pending+zstdMaxBlock, false); z.err != nil {
+			return 0, z.err
+		}
+	}
+	return len(p), nil
+}
+
+// Close writes the last block & the frame checksum. The destination isn't
+// closed
+func (z *zstdWriter) Close() error {
+	if z.closed {
+		return z.err
+	}
+	z.closed = true
+	if z.err != nil {
+		return z.err
+	}
+	if z.err = z.writeBlock(len(z.hist), true); z.err != nil {
+		return z.err
+	}
+	var sum [4]byte
+	binary.LittleEndian.PutUint32(sum[:], uint32(z.digest.Sum64()))
+	_, z.err = z.w.Write(sum[:])
+	return z.err
+}
+
+// writeBlock compresses hist[pending:end] into a block
+func (z *zstdWriter) writeBlock(end int, last bool) error {
+	var out []byte
+	if !z.started {
+		// magic, a descriptor with only the checksum flag, & the window
+		out = binary.LittleEndian.AppendUint32(out, zstdMagic)
+		out = append(out, 0x04, (zstdWriterWindowLog-10)<<3)
+		z.started = true
+	}
+
+	src := z.hist[z.pending:end]
+	block := z.compressBlock(z.pending, end)
+	var header uint32
+	if last {
+		header = 1
+	}
+	switch {
+	case len(src) == 0:
+		out = append(out, byte(header), 0, 0)
+	case block != nil && len(block) < len(src):
+		header |= 2<<1 | uint32(len(block))<<3
+		out = append(out, byte(header), byte(header>>8), byte(header>>16))
+		out = append(out, block...)
+	default:
+		header |= uint32(len(src)) << 3
+		out = append(out, byte(header), byte(header>>8), byte(header>>16))
+		out = append(out, src...)
+	}
+	if _, err := z.w.Write(out); err != nil {
+		return err
+	}
+
+	z.pending = end
+	// keep one window of history once it's twice the window size
+	if z.pending > 2*zstdWriterWindow {
+		shift := z.pending - zstdWriterWindow
+		n := copy(z.hist, z.hist[shift:])
+		z.hist = z.hist[:n]
+		z.pending -= shift
+		for i, pos := range z.table {
+			if int(pos)-shift > 0 {
+				z.table[i] = pos - int32(shift)
+			} else {
+				z.table[i] = 0
+			}
+		}
+	}
+	return nil
+}
+
+// zstdSequence is a run of literals followed by a match
+type zstdSequence struct {
+	litLen, matchLen, offset int
+}
+
+func zstdHash(b []byte) uint32 {
+	return binary.LittleEndian.Uint32(b) * 2654435761 >> (32 - zstdHashLog)
+}
+
+// compressBlock compresses hist[start:end], returning nil if no matches are
+// found. Matches can reach back into the window before start
+func (z *zstdWriter) compressBlock(start, end int) []byte {
+	var seqs []zstdSequence
+	hist := z.hist[:end]
+	litStart := start
+	for i := start; i+zstdMinMatch <= end; {
+		h := zstdHash(hist[i:])
+		// table positions are offset by one, zero is empty
+		cand := int(z.table[h]) - 1
+		z.table[h] = int32(i + 1)
+		if cand < 0 || i-cand > zstdWriterWindow || binary.LittleEndian.Uint32(hist[cand:]) != binary.LittleEndian.Uint32(hist[i:]) {
+			i++
+			continue
+		}
+		n := zstdMinMatch
+		for i+n < end && n < zstdMaxMatch && hist[cand+n] == hist[i+n] {
+			n++
+		}
+		// extend backwards into pending literals
+		for i > litStart && cand > 0 && n < zstdMaxMatch && hist[cand-1] == hist[i-1] {
+			i--
+			cand--
+			n++
+		}
+		seqs = append(seqs, zstdSequence{litLen: i - litStart, matchLen: n, offset: i - cand})
+		i += n
+		litStart = i
+		if i-2 > cand && i-2+zstdMinMatch <= end {
+			z.table[zstdHash(hist[i-2:])] = int32(i - 2 + 1)
+		}
+	}
+	if len(seqs) == 0 {
+		return nil
+	}
+
+	// literals section, raw literals
+	var lits []byte
+	pos := start
+	for _, s := range seqs {
+		lits = append(lits, hist[pos:pos+s.litLen]...)
+		pos += s.litLen + s.matchLen
+	}
+	lits = append(lits, hist[pos:end]...)
+
+	var out []byte
+	switch n := len(lits); {
+	case n < 32:
+		out = append(out, byte(n<<3))
+	case n < 4096:
+		out = append(out, byte(n&0xf<<4|1<<2), byte(n>>4))
+	default:
+		out = append(out, byte(n&0xf<<4|3<<2), byte(n>>4), byte(n>>12))
+	}
+	out = append(out, lits...)
+
+	// sequences section header, all predefined tables
+	switch n := len(seqs); {
+	case n < 128:
+		out = append(out, byte(n))
+	case n < 0x7F00:
+		out = append(out, byte(n>>8+128), byte(n))
+	default:
+		out = append(out, 255, byte(n-0x7F00), byte((n-0x7F00)>>8))
+	}
+	out = append(out, 0)
+	return zstdEncodeSequences(out, seqs)
+}
+
+// zstdSequenceCodes gives the literal length, match length & offset codes of
+// a sequence, with extra bits & their counts
+func zstdSequenceCodes(s zstdSequence) (codes [3]int, extra [3]uint64, nbits [3]uint) {
+	ll := zstdBaseCode(zstdLiteralLengthBase[:], uint32(s.litLen))
+	codes[zstdLiteralLengths] = ll
+	extra[zstdLiteralLengths] = uint64(uint32(s.litLen) - zstdLiteralLengthBase[ll])
+	nbits[zstdLiteralLengths] = uint(zstdLiteralLengthBits[ll])
+
+	ml := zstdBaseCode(zstdMatchLengthBase[:], uint32(s.matchLen))
+	codes[zstdMatchLengths] = ml
+	extra[zstdMatchLengths] = uint64(uint32(s.matchLen) - zstdMatchLengthBase[ml])
+	nbits[zstdMatchLengths] = uint(zstdMatchLengthBits[ml])
+
+	// offsets are never coded as repeats, offset values are offset + 3
+	ov := uint64(s.offset + 3)
+	of := bits.Len64(ov) - 1
+	codes[zstdOffsets] = of
+	extra[zstdOffsets] = ov - 1<<uint(of)
+	nbits[zstdOffsets] = uint(of)
+	return codes, extra, nbits
+}
+
+// zstdBaseCode gives the largest code with a baseline no greater than v
+func zstdBaseCode(base []uint32, v uint32) int {
+	return sort.Search(len(base), func(i int) bool { return base[i] > v }) - 1
+}
+
+// zstdEncodeSequences appends the sequences bitstream to out. Sequences are
+// coded in reverse so the decoder reads them forwards
+func zstdEncodeSequences(out []byte, seqs []zstdSequence) []byte {
+	bw := &zstdBitWriter{out: out}
+	var states [3]int
+	// decoders read extra bits in offset, match, literal order
+	extraOrder := [3]int{zstdLiteralLengths, zstdMatchLengths, zstdOffsets}
+
+	for i := len(seqs) - 1; i >= 0; i-- {
+		codes, extra, nbits := zstdSequenceCodes(seqs[i])
+		if i == len(seqs)-1 {
+			for kind, code := range codes {
+				states[kind] = int(zstdPredefinedEncoders[kind][code][0])
+			}
+		} else {
+			// state updates are read in literal, match, offset order
+			for _, kind := range [3]int{zstdOffsets, zstdMatchLengths, zstdLiteralLengths} {
+				t := zstdPredefinedTables[kind]
+				next := states[kind]
+				cur := int(zstdPredefinedEncoders[kind][codes[kind]][next])
+				bw.write(uint64(next-int(t.base[cur])), uint(t.numBits[cur]))
+				states[kind] = cur
+			}
+		}
+		for _, kind := range extraOrder {
+			bw.write(extra[kind], nbits[kind])
+		}
+	}
+	// initial states are read in literal, offset, match order
+	for _, kind := range [3]int{zstdMatchLengths, zstdOffsets, zstdLiteralLengths} {
+		bw.write(uint64(states[kind]), zstdPredefinedTables[kind].accuracyLog)
+	}
+	bw.write(1, 1)
+	return bw.flush()
+}
+
+// zstdBitWriter writes bits from the lowest bit of each byte up, the reverse
+// of backwardBitReader
+type zstdBitWriter struct {
+	out []byte
+	acc uint64
+	n   uint
+}
+
+func (w *zstdBitWriter) write(v uint64, n uint) {
+	w.acc |= v << w.n
+	w.n += n
+	for w.n >= 8 {
+		w.out = append(w.out, byte(w.acc))
+		w.acc >>= 8
+		w.n -= 8
+	}
+}
+
+func (w *zstdBitWriter) flush() []byte {
+	if w.n > 0 {
+		w.out = append(w.out, byte(w.acc))
+	}
+	return w.out
+}
+
+// zstdPredefinedEncoders maps a symbol & the state after it's decoded to the
+// state that decodes it, for each predefined table
+var zstdPredefinedEncoders = [3][][]uint16{
+	zstdFSEEncoder(zstdPredefinedTables[0]),
+	zstdFSEEncoder(zstdPredefinedTables[1]),
+	zstdFSEEncoder(zstdPredefinedTables[2]),
+}
+
+// zstdFSEEncoder inverts a decoding table. Decoding state s gives symbol
+// symbols[s], & moves to a state in [base[s], base[s] + 1<<numBits[s]). The
+// states of each symbol cover every next state once
+func zstdFSEEncoder(t *fseTable) [][]uint16 {
+	size := 1 << t.accuracyLog
+	enc := make([][]uint16, 256)
+	for s := 0; s < size; s++ {
+		sym := t.symbols[s]
+		if enc[sym] == nil {
+			enc[sym] = make([]uint16, size)
+		}
+		for next := int(t.base[s]); next < int(t.base[s])+1<<t.numBits[s]; next++ {
+			enc[sym][next] = uint16(s)
+		}
+	}
+	return enc
+}
@@ -0,0 +1,68 @@
+package dsio
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestZstdWriter(t *testing.T) {
+	movies, err := os.ReadFile("testdata/movies/body.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	random := make([]byte, 300000)
+	rand.New(rand.NewSource(1)).Read(random)
+	// longer than two windows, with matches far back in the window
+	long := bytes.Repeat(append(random[:200000:200000], movies...), 12)
+
+	cases := []struct {
+		description string
+		data        []byte
+	}{
+		{"empty", nil},
+		{"short", []byte("a,b\n1,2\n")},
+		{"repeated", []byte(strings.Repeat("a", 300000))},
+		{"json", movies},
+		{"incompressible", random},
+		{"long", long},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		zw := newZstdWriter(buf)
+		// write in pieces that don't line up with blocks
+		for i := 0; i < len(c.data); i += 70001 {
+			if _, err := zw.Write(c.data[i:min(i+70001, len(c.data))]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if c.description == "json" && buf.Len() > len(c.data)/3 {
+			t.Errorf("%s: expected at least 3x compression, got %d -> %d bytes", c.description, len(c.data), buf.Len())
+		}
+		got, err := io.ReadAll(newZstdReader(buf))
+		if err != nil {
+			t.Errorf("%s: unexpected error decompressing: %s", c.description, err)
+			continue
+		}
+		if !bytes.Equal(got, c.data) {
+			t.Errorf("%s: round trip mismatch. expected %d bytes, got %d", c.description, len(c.data), len(got))
+		}
+	}
+}
+
+func TestZstdWriterErrors(t *testing.T) {
+	zw := newZstdWriter(failWriter{})
+	zw.Write([]byte("data"))
+	if err := zw.Close(); err == nil || err.Error() != "disk full" {
+		t.Errorf("expected write error, got: %v", err)
+	}
+	if _, err := zw.Write([]byte("more")); err == nil || err.Error() != "disk full" {
+		t.Errorf("expected write after error to fail, got: %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+// Package dslog defines the logging interface used by dataset packages.
+// Packages that log expose a SetLogger function, and log nothing until a
+// logger is set. The Logger interface is small enough that adapters to
+// structured logging libraries are a few lines
+package dslog
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level is the severity of a log message
+type Level int
+
+const (
+	// LevelDebug is for detailed diagnostic messages
+	LevelDebug Level = iota
+	// LevelInfo is for routine messages
+	LevelInfo
+	// LevelWarn is for unexpected, recoverable conditions
+	LevelWarn
+	// LevelError is for failures
+	LevelError
+)
+
+// String implements the fmt.Stringer interface
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// Field is a key-value pair attached to a log message, like a dataset path
+// or entry index
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F creates a Field
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Logger writes log messages
+type Logger interface {
+	Log(level Level, msg string, fields ...Field)
+}
+
+// Nop is a Logger that discards all messages
+var Nop Logger = nopLogger{}
+
+type nopLogger struct{}
+
+func (nopLogger) Log(Level, string, ...Field) {}
+
+// With returns a logger that adds fields to every message logged with l
+func With(l Logger, fields ...Field) Logger {
+	return withLogger{l: l, fields: fields}
+}
+
+type withLogger struct {
+	l      Logger
+	fields []Field
+}
+
+func (w withLogger) Log(level Level, msg string, fields ...Field) {
+	w.l.Log(level, msg, append(append([]Field(nil), w.fields...), fields...)...)
+}
+
+// NewWriterLogger creates a logger that writes messages at or above min to w,
+// one per line, formatted as: level msg key=value key=value
+func NewWriterLogger(w io.Writer, min Level) Logger {
+	return &writerLogger{w: w, min: min}
+}
+
+type writerLogger struct {
+	lk  sync.Mutex
+	w   io.Writer
+	min Level
+}
+
+func (wl *writerLogger) Log(level Level, msg string, fields ...Field) {
+	if level < wl.min {
+		return
+	}
+	b := &strings.Builder{}
+	b.WriteString(level.String())
+	b.WriteByte(' ')
+	b.WriteString(msg)
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	for _, f := range fields {
+		fmt.Fprintf(b, " %s=%v", f.Key, f.Value)
+	}
+	b.WriteByte('\n')
+
+	wl.lk.Lock()
+	defer wl.lk.Unlock()
+	io.WriteString(wl.w, b.String())
+}
+
+// Package is the logger a package logs with. Every message carries a "pkg"
+// field with the package name. Package loggers discard messages until
+// SetLogger is called. Debug, Debugf & Error mirror the go-log methods
+// packages used before dslog
+type Package struct {
+	name string
+	l    atomic.Value
+}
+
+type loggerBox struct{ Logger }
+
+// NewPackage creates a package logger
+func NewPackage(name string) *Package {
+	p := &Package{name: name}
+	p.SetLogger(nil)
+	return p
+}
+
+// SetLogger sets the destination for log messages. nil discards messages
+func (p *Package) SetLogger(l Logger) {
+	if l == nil {
+		l = Nop
+	}
+	p.l.Store(loggerBox{l})
+}
+
+// Log writes a message with fields
+func (p *Package) Log(level Level, msg string, fields ...Field) {
+	l := p.l.Load().(loggerBox).Logger
+	if l == Nop {
+		return
+	}
+	l.Log(level, msg, append([]Field{F("pkg", p.name)}, fields...)...)
+}
+
+// Debug logs a debug message
+func (p *Package) Debug(args ...interface{}) {
+	p.Log(LevelDebug, fmt.Sprint(args...))
+}
+
+// Debugf logs a formatted debug message
+func (p *Package) Debugf(format string, args ...interface{}) {
+	p.Log(LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Debugw logs a debug message with fields
+func (p *Package) Debugw(msg string, fields ...Field) {
+	p.Log(LevelDebug, msg, fields...)
+}
+
+// Error logs an error message
+func (p *Package) Error(args ...interface{}) {
+	p.Log(LevelError, fmt.Sprint(args...))
+}
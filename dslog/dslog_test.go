@@ -0,0 +1,37 @@
+package dslog
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPackage(t *testing.T) {
+	buf := &bytes.Buffer{}
+	p := NewPackage("dsio")
+
+	p.Debug("discarded")
+	p.SetLogger(With(NewWriterLogger(buf, LevelDebug), F("path", "/ipfs/Qm")))
+	p.Debugw("read failed", F("index", 4))
+	p.Debugf("key already written: %q", "a")
+	p.Error("bad")
+	p.SetLogger(nil)
+	p.Debug("discarded")
+
+	expect := `debug read failed index=4 path=/ipfs/Qm pkg=dsio
+debug key already written: "a" path=/ipfs/Qm pkg=dsio
+error bad path=/ipfs/Qm pkg=dsio
+`
+	if buf.String() != expect {
+		t.Errorf("output mismatch.\nexpected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+}
+
+func TestWriterLoggerLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := NewWriterLogger(buf, LevelWarn)
+	l.Log(LevelInfo, "skipped")
+	l.Log(LevelWarn, "kept")
+	if buf.String() != "warn kept\n" {
+		t.Errorf("expected only warn message, got: %q", buf.String())
+	}
+}
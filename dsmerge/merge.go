@@ -0,0 +1,636 @@
+// Package dsmerge combines two versions of a dataset that share a common
+// ancestor. Components are merged field by field & bodies entry by entry,
+// changes made by only one version are kept, changes both versions made
+// differently are reported as conflicts
+package dsmerge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/dslog"
+	"github.com/qri-io/qfs"
+)
+
+var log = dslog.NewPackage("dsmerge")
+
+// SetLogger sets the logger for package dsmerge. By default nothing is logged
+func SetLogger(l dslog.Logger) {
+	log.SetLogger(l)
+}
+
+// Config configures a merge
+type Config struct {
+	// PrimaryKey lists the columns identifying the entries of array bodies,
+	// by tabular column title or object entry property. It's required to
+	// merge array bodies both versions changed. entries of object bodies are
+	// identified by their key
+	PrimaryKey []string
+}
+
+// Conflict is a value both versions changed differently. A nil value is
+// missing from that version
+type Conflict struct {
+	// Path is a JSON pointer to the value, eg: "/meta/title", body entries
+	// are addressed by key, eg: "/body/id_1/2" for the third column of the
+	// entry with primary key "id_1"
+	Path   string
+	Base   interface{}
+	Ours   interface{}
+	Theirs interface{}
+}
+
+// String gives a one-line description of the conflict
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: ours %v, theirs %v", c.Path, describe(c.Ours), describe(c.Theirs))
+}
+
+// structureComputedFields are set when a dataset is saved & never merged
+var structureComputedFields = []string{"checksum", "chunkChecksums", "depth", "entries", "errCount", "length", "path", "qri"}
+
+// Merge combines ours & theirs, two versions of a dataset with the common
+// ancestor base. Bodies must be loaded, either as a body file or a Body
+// value. Conflicting values keep ours in the merged dataset, which has no
+// commit & the previous path of ours
+func Merge(base, ours, theirs *dataset.Dataset, configs ...func(cfg *Config)) (*dataset.Dataset, []Conflict, error) {
+	if base == nil || ours == nil || theirs == nil {
+		return nil, nil, fmt.Errorf("merge requires base, ours & theirs datasets")
+	}
+	cfg := &Config{}
+	for _, opt := range configs {
+		opt(cfg)
+	}
+
+	m := &merger{cfg: cfg}
+	ds := &dataset.Dataset{
+		Qri:          ours.Qri,
+		Peername:     ours.Peername,
+		ProfileID:    ours.ProfileID,
+		Name:         ours.Name,
+		PreviousPath: ours.Path,
+	}
+
+	var err error
+	if ds.Meta, err = m.meta(base.Meta, ours.Meta, theirs.Meta); err != nil {
+		return nil, nil, err
+	}
+	if ds.Structure, err = m.structure(base.Structure, ours.Structure, theirs.Structure); err != nil {
+		return nil, nil, err
+	}
+	if ds.Transform, err = m.transform(base.Transform, ours.Transform, theirs.Transform); err != nil {
+		return nil, nil, err
+	}
+	if ds.Viz, err = m.viz(base.Viz, ours.Viz, theirs.Viz); err != nil {
+		return nil, nil, err
+	}
+	if err = m.body(ds, base, ours, theirs); err != nil {
+		return nil, nil, err
+	}
+	return ds, m.conflicts, nil
+}
+
+// merger accumulates conflicts
+type merger struct {
+	cfg       *Config
+	conflicts []Conflict
+}
+
+// absentValue marks a value missing from a version
+type absentValue struct{}
+
+var absent = absentValue{}
+
+func (m *merger) meta(base, ours, theirs *dataset.Meta) (*dataset.Meta, error) {
+	v, err := m.component("/meta", objectOf(base), objectOf(ours), objectOf(theirs), "path", "qri")
+	if v == nil || err != nil {
+		return nil, err
+	}
+	md := &dataset.Meta{}
+	if err := md.UnmarshalJSON(v); err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("meta: %w", err)
+	}
+	return md, nil
+}
+
+func (m *merger) structure(base, ours, theirs *dataset.Structure) (*dataset.Structure, error) {
+	v, err := m.component("/structure", objectOf(base), objectOf(ours), objectOf(theirs), structureComputedFields...)
+	if v == nil || err != nil {
+		return nil, err
+	}
+	st := &dataset.Structure{}
+	if err := st.UnmarshalJSON(v); err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("structure: %w", err)
+	}
+	return st, nil
+}
+
+func (m *merger) transform(base, ours, theirs *dataset.Transform) (*dataset.Transform, error) {
+	pick, err := m.whole("/transform", base, ours, theirs)
+	if err != nil {
+		return nil, err
+	}
+	return []*dataset.Transform{base, ours, theirs}[pick], nil
+}
+
+func (m *merger) viz(base, ours, theirs *dataset.Viz) (*dataset.Viz, error) {
+	pick, err := m.whole("/viz", base, ours, theirs)
+	if err != nil {
+		return nil, err
+	}
+	return []*dataset.Viz{base, ours, theirs}[pick], nil
+}
+
+// jsonObject is implemented by components that always marshal to an object
+type jsonObject interface {
+	MarshalJSONObject() ([]byte, error)
+}
+
+// objectOf gives the component to marshal, nil components are missing
+func objectOf(c jsonObject) interface{} {
+	switch x := c.(type) {
+	case *dataset.Meta:
+		if x == nil {
+			return nil
+		}
+	case *dataset.Structure:
+		if x == nil {
+			return nil
+		}
+	}
+	return c
+}
+
+// component merges a component property by property, dropping the skipped
+// properties. It returns the merged component's JSON, nil if the component
+// is missing from the merge
+func (m *merger) component(path string, base, ours, theirs interface{}, skip ...string) ([]byte, error) {
+	var vals [3]interface{}
+	for i, c := range []interface{}{base, ours, theirs} {
+		vals[i] = absent
+		if c == nil {
+			continue
+		}
+		data, err := c.(jsonObject).MarshalJSONObject()
+		if err != nil {
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		obj := map[string]interface{}{}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, key := range skip {
+			delete(obj, key)
+		}
+		vals[i] = obj
+	}
+
+	v := m.value(path, vals[0], vals[1], vals[2], false)
+	if v == absent {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// whole merges a component as a single value, giving the index of the
+// version to use: 0 for base, 1 for ours & 2 for theirs
+func (m *merger) whole(path string, base, ours, theirs json.Marshaler) (int, error) {
+	var vals [3]interface{}
+	for i, c := range []json.Marshaler{base, ours, theirs} {
+		vals[i] = absent
+		if isNil(c) {
+			continue
+		}
+		data, err := c.MarshalJSON()
+		if err != nil {
+			log.Debug(err.Error())
+			return 0, fmt.Errorf("%s: %w", path, err)
+		}
+		vals[i] = json.RawMessage(data)
+	}
+
+	switch {
+	case equal(vals[1], vals[2]), equal(vals[0], vals[2]):
+		return 1, nil
+	case equal(vals[0], vals[1]):
+		return 2, nil
+	}
+	m.conflict(path, vals[0], vals[1], vals[2])
+	return 1, nil
+}
+
+func isNil(c json.Marshaler) bool {
+	switch x := c.(type) {
+	case nil:
+		return true
+	case *dataset.Transform:
+		return x == nil
+	case *dataset.Viz:
+		return x == nil
+	}
+	return false
+}
+
+// value merges a single value. objects are merged property by property,
+// with elements set arrays of the same length are merged element by element
+func (m *merger) value(path string, base, ours, theirs interface{}, elements bool) interface{} {
+	switch {
+	case equal(ours, theirs), equal(base, theirs):
+		return ours
+	case equal(base, ours):
+		return theirs
+	}
+
+	b, bok := base.(map[string]interface{})
+	o, ook := ours.(map[string]interface{})
+	t, tok := theirs.(map[string]interface{})
+	if bok && ook && tok {
+		return m.object(path, b, o, t, elements)
+	}
+	if elements {
+		b, bok := base.([]interface{})
+		o, ook := ours.([]interface{})
+		t, tok := theirs.([]interface{})
+		if bok && ook && tok && len(b) == len(o) && len(o) == len(t) {
+			merged := make([]interface{}, len(o))
+			for i := range o {
+				merged[i] = m.value(fmt.Sprintf("%s/%d", path, i), b[i], o[i], t[i], elements)
+			}
+			return merged
+		}
+	}
+
+	m.conflict(path, base, ours, theirs)
+	return ours
+}
+
+func (m *merger) object(path string, base, ours, theirs map[string]interface{}, elements bool) map[string]interface{} {
+	keys := map[string]bool{}
+	for _, obj := range []map[string]interface{}{base, ours, theirs} {
+		for key := range obj {
+			keys[key] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	merged := map[string]interface{}{}
+	for _, key := range sorted {
+		v := m.value(path+"/"+escapePointer(key), lookup(base, key), lookup(ours, key), lookup(theirs, key), elements)
+		if v != absent {
+			merged[key] = v
+		}
+	}
+	return merged
+}
+
+func (m *merger) conflict(path string, base, ours, theirs interface{}) {
+	m.conflicts = append(m.conflicts, Conflict{
+		Path:   path,
+		Base:   present(base),
+		Ours:   present(ours),
+		Theirs: present(theirs),
+	})
+}
+
+// body merges the bodies of three versions into ds
+func (m *merger) body(ds, base, ours, theirs *dataset.Dataset) error {
+	versions := []*dataset.Dataset{base, ours, theirs}
+	loaded := 0
+	for _, v := range versions {
+		if v.BodyFile() != nil || v.Body != nil {
+			loaded++
+		}
+	}
+	if loaded == 0 {
+		return nil
+	}
+	if loaded < len(versions) {
+		return fmt.Errorf("merging bodies requires the body of every version")
+	}
+
+	var bodies [3]*keyedBody
+	for i, v := range versions {
+		b, err := m.readBody(v)
+		if err != nil {
+			return fmt.Errorf("%s body: %w", []string{"base", "ours", "theirs"}[i], err)
+		}
+		bodies[i] = b
+	}
+	b, o, t := bodies[0], bodies[1], bodies[2]
+	if o.object != t.object || o.object != b.object {
+		return fmt.Errorf("can't merge object & array bodies")
+	}
+
+	var merged []dsio.Entry
+	switch {
+	case o.equal(t), b.equal(t):
+		merged = o.entries
+	case b.equal(o):
+		merged = t.entries
+	default:
+		var err error
+		if merged, err = m.entries(b, o, t); err != nil {
+			return err
+		}
+	}
+
+	if ds.Structure == nil {
+		return fmt.Errorf("merging bodies requires a structure")
+	}
+	buf := &bytes.Buffer{}
+	w, err := dsio.NewEntryWriter(ds.Structure, buf)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("writing body: %w", err)
+	}
+	for i, ent := range merged {
+		ent.Index = i
+		if err := w.WriteEntry(ent); err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("writing body: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("writing body: %w", err)
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body."+ds.Structure.Format, buf.Bytes()))
+	return nil
+}
+
+// entries merges bodies entry by entry. merged entries are in the order of
+// ours, followed by entries only theirs added
+func (m *merger) entries(base, ours, theirs *keyedBody) ([]dsio.Entry, error) {
+	if !ours.object && len(m.cfg.PrimaryKey) == 0 {
+		return nil, fmt.Errorf("merging array bodies both versions changed requires a primary key")
+	}
+
+	keys := append([]string{}, ours.keys...)
+	for _, key := range theirs.keys {
+		if _, ok := ours.values[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	var merged []dsio.Entry
+	for _, key := range keys {
+		label := ours.labels[key]
+		if label == "" {
+			label = theirs.labels[key]
+		}
+		v := m.value("/body/"+escapePointer(label), lookup(base.values, key), lookup(ours.values, key), lookup(theirs.values, key), true)
+		if v == absent {
+			continue
+		}
+		ent := dsio.Entry{Value: v}
+		if ours.object {
+			ent.Key = key
+		}
+		merged = append(merged, ent)
+	}
+	return merged, nil
+}
+
+// keyedBody is a body with entries identified by key
+type keyedBody struct {
+	object  bool
+	entries []dsio.Entry
+	keys    []string
+	values  map[string]interface{}
+	labels  map[string]string
+}
+
+func (m *merger) readBody(ds *dataset.Dataset) (*keyedBody, error) {
+	if ds.Structure == nil {
+		return nil, fmt.Errorf("structure is required")
+	}
+
+	entries, err := bodyEntries(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &keyedBody{
+		object:  ds.Structure.Schema["type"] == "object",
+		entries: entries,
+		values:  map[string]interface{}{},
+		labels:  map[string]string{},
+	}
+	var columns []int
+	if !b.object && len(m.cfg.PrimaryKey) > 0 {
+		if columns, err = primaryKeyColumns(ds.Structure, m.cfg.PrimaryKey); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, ent := range entries {
+		key, label := ent.Key, ent.Key
+		if !b.object {
+			if len(m.cfg.PrimaryKey) == 0 {
+				continue
+			}
+			if key, label, err = m.entryKey(ent.Value, columns); err != nil {
+				return nil, fmt.Errorf("entry %d: %w", i, err)
+			}
+		}
+		if _, ok := b.values[key]; ok {
+			return nil, fmt.Errorf("entry %d: duplicate key %s", i, label)
+		}
+		b.keys = append(b.keys, key)
+		b.values[key] = ent.Value
+		b.labels[key] = label
+	}
+	return b, nil
+}
+
+// bodyEntries reads the entries of a body file, or a Body value. entries of
+// object Body values are sorted by key
+func bodyEntries(ds *dataset.Dataset) ([]dsio.Entry, error) {
+	var entries []dsio.Entry
+	switch body := ds.Body.(type) {
+	case map[string]interface{}:
+		if ds.BodyFile() != nil {
+			break
+		}
+		for key, v := range body {
+			entries = append(entries, dsio.Entry{Key: key, Value: v})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+		return entries, nil
+	case []interface{}:
+		if ds.BodyFile() != nil {
+			break
+		}
+		for i, v := range body {
+			entries = append(entries, dsio.Entry{Index: i, Value: v})
+		}
+		return entries, nil
+	}
+	if ds.BodyFile() == nil {
+		return nil, fmt.Errorf("can't read body of type %T", ds.Body)
+	}
+
+	r, err := dsio.NewEntryReader(ds.Structure, ds.BodyFile())
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+	defer r.Close()
+	for i := 0; ; i++ {
+		ent, err := r.ReadEntry()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		entries = append(entries, ent)
+	}
+	return entries, nil
+}
+
+// entryKey gives the primary key of an entry as a string to compare keys
+// with & a label for conflict paths
+func (m *merger) entryKey(v interface{}, columns []int) (key, label string, err error) {
+	vals := make([]interface{}, len(m.cfg.PrimaryKey))
+	for i, name := range m.cfg.PrimaryKey {
+		switch x := v.(type) {
+		case []interface{}:
+			if columns == nil {
+				return "", "", fmt.Errorf("expected an object entry")
+			}
+			if columns[i] < len(x) {
+				vals[i] = x[columns[i]]
+			}
+		case map[string]interface{}:
+			vals[i] = x[name]
+		default:
+			return "", "", fmt.Errorf("can't key %T entries", v)
+		}
+		if vals[i] == nil {
+			return "", "", fmt.Errorf("primary key %s is null", name)
+		}
+	}
+
+	data, err := json.Marshal(vals)
+	if err != nil {
+		log.Debug(err.Error())
+		return "", "", err
+	}
+	labels := make([]string, len(vals))
+	for i, v := range vals {
+		labels[i] = fmt.Sprintf("%v", v)
+	}
+	return string(data), strings.Join(labels, ","), nil
+}
+
+// primaryKeyColumns gives the index of each primary key column in tabular
+// entries, nil for bodies with object entries
+func primaryKeyColumns(st *dataset.Structure, primaryKey []string) ([]int, error) {
+	if items, ok := st.Schema["items"].(map[string]interface{}); ok && items["type"] == "object" {
+		return nil, nil
+	}
+	titles := map[string]int{}
+	if items, ok := st.Schema["items"].(map[string]interface{}); ok {
+		if cols, ok := items["items"].([]interface{}); ok {
+			for i, c := range cols {
+				if col, ok := c.(map[string]interface{}); ok {
+					if title, ok := col["title"].(string); ok {
+						titles[title] = i
+					}
+				}
+			}
+		}
+	}
+	columns := make([]int, len(primaryKey))
+	for i, name := range primaryKey {
+		idx, ok := titles[name]
+		if !ok {
+			return nil, fmt.Errorf("primary key column %s isn't in the schema", name)
+		}
+		columns[i] = idx
+	}
+	return columns, nil
+}
+
+// equal reports whether bodies have the same entries in the same order
+func (b *keyedBody) equal(other *keyedBody) bool {
+	if len(b.entries) != len(other.entries) {
+		return false
+	}
+	for i, ent := range b.entries {
+		if ent.Key != other.entries[i].Key || !equal(ent.Value, other.entries[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// equal compares values by their JSON encoding, so numbers of different go
+// types are equal
+func equal(a, b interface{}) bool {
+	_, aAbsent := a.(absentValue)
+	_, bAbsent := b.(absentValue)
+	if aAbsent || bAbsent {
+		return aAbsent && bAbsent
+	}
+	ad, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bd, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ad, bd)
+}
+
+func lookup(obj map[string]interface{}, key string) interface{} {
+	if v, ok := obj[key]; ok {
+		return v
+	}
+	return absent
+}
+
+// present converts absent values to nil
+func present(v interface{}) interface{} {
+	if v == absent {
+		return nil
+	}
+	if raw, ok := v.(json.RawMessage); ok {
+		var x interface{}
+		if err := json.Unmarshal(raw, &x); err == nil {
+			return x
+		}
+	}
+	return v
+}
+
+func describe(v interface{}) string {
+	if v == nil {
+		return "removed"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// escapePointer escapes a JSON pointer reference token
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,227 @@
+package dsmerge
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/dataset/dstest"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+var csvSchema = map[string]interface{}{
+	"type": "array",
+	"items": map[string]interface{}{
+		"type": "array",
+		"items": []interface{}{
+			map[string]interface{}{"title": "id", "type": "string"},
+			map[string]interface{}{"title": "name", "type": "string"},
+			map[string]interface{}{"title": "count", "type": "integer"},
+		},
+	},
+}
+
+func csvVersion(title, body string) *dataset.Dataset {
+	ds := &dataset.Dataset{
+		Meta: &dataset.Meta{Title: title},
+		Structure: &dataset.Structure{
+			Format:       "csv",
+			FormatConfig: map[string]interface{}{"headerRow": true},
+			Schema:       csvSchema,
+		},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte(body)))
+	return ds
+}
+
+func bodyString(t *testing.T, ds *dataset.Dataset) string {
+	data, err := ioutil.ReadAll(ds.BodyFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMerge(t *testing.T) {
+	base := csvVersion("title", "id,name,count\na,apple,1\nb,banana,2\nc,cherry,3\n")
+	ours := csvVersion("title", "id,name,count\na,apple,10\nb,banana,2\nd,date,4\n")
+	theirs := csvVersion("new title", "id,name,count\na,Apple,1\nb,banana,2\nc,cherry,3\ne,elderberry,5\n")
+	theirs.Meta.Keywords = []string{"fruit"}
+
+	ds, conflicts, err := Merge(base, ours, theirs, func(cfg *Config) {
+		cfg.PrimaryKey = []string{"id"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got: %v", conflicts)
+	}
+	if ds.Meta.Title != "new title" {
+		t.Errorf("title mismatch. expected: 'new title', got: '%s'", ds.Meta.Title)
+	}
+	if !reflect.DeepEqual([]string{"fruit"}, ds.Meta.Keywords) {
+		t.Errorf("keywords mismatch. expected: [fruit], got: %v", ds.Meta.Keywords)
+	}
+	expect := "id,name,count\na,Apple,10\nb,banana,2\nd,date,4\ne,elderberry,5\n"
+	if got := bodyString(t, ds); got != expect {
+		t.Errorf("body mismatch. expected:\n%s\ngot:\n%s", expect, got)
+	}
+}
+
+func TestMergeConflicts(t *testing.T) {
+	base := csvVersion("title", "id,name,count\na,apple,1\nb,banana,2\n")
+	ours := csvVersion("our title", "id,name,count\na,apple,10\nb,banana,2\nc,cherry,3\n")
+	theirs := csvVersion("their title", "id,name,count\na,apple,11\nc,cantaloupe,3\n")
+
+	ds, conflicts, err := Merge(base, ours, theirs, func(cfg *Config) {
+		cfg.PrimaryKey = []string{"id"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []Conflict{
+		{Path: "/meta/title", Base: "title", Ours: "our title", Theirs: "their title"},
+		{Path: "/body/a/2", Base: int64(1), Ours: int64(10), Theirs: int64(11)},
+		// entries both versions added conflict as a whole
+		{Path: "/body/c", Ours: []interface{}{"c", "cherry", int64(3)}, Theirs: []interface{}{"c", "cantaloupe", int64(3)}},
+	}
+	if !reflect.DeepEqual(expect, conflicts) {
+		t.Errorf("conflicts mismatch. expected:\n%#v\ngot:\n%#v", expect, conflicts)
+	}
+
+	// conflicts keep ours, theirs deleted b
+	if ds.Meta.Title != "our title" {
+		t.Errorf("title mismatch. expected: 'our title', got: '%s'", ds.Meta.Title)
+	}
+	expectBody := "id,name,count\na,apple,10\nc,cherry,3\n"
+	if got := bodyString(t, ds); got != expectBody {
+		t.Errorf("body mismatch. expected:\n%s\ngot:\n%s", expectBody, got)
+	}
+
+	if s := conflicts[0].String(); s != `/meta/title: ours "our title", theirs "their title"` {
+		t.Errorf("string mismatch, got: %s", s)
+	}
+}
+
+func TestMergeObjectBody(t *testing.T) {
+	version := func(body interface{}) *dataset.Dataset {
+		return &dataset.Dataset{
+			Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject},
+			Body:      body,
+		}
+	}
+	base := version(map[string]interface{}{"a": 1, "b": map[string]interface{}{"x": 1, "y": 2}})
+	ours := version(map[string]interface{}{"a": 1, "b": map[string]interface{}{"x": 5, "y": 2}})
+	theirs := version(map[string]interface{}{"b": map[string]interface{}{"x": 1, "y": 6}, "c": true})
+
+	ds, conflicts, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got: %v", conflicts)
+	}
+	var got interface{}
+	if err := json.Unmarshal([]byte(bodyString(t, ds)), &got); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{"b": map[string]interface{}{"x": float64(5), "y": float64(6)}, "c": true}
+	if !reflect.DeepEqual(expect, got) {
+		t.Errorf("body mismatch. expected: %v, got: %v", expect, got)
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	base := csvVersion("title", "id,name,count\na,apple,1\n")
+	ours := csvVersion("title", "id,name,count\na,apple,2\n")
+	theirs := csvVersion("title", "id,name,count\na,apple,3\n")
+
+	if _, _, err := Merge(nil, ours, theirs); err == nil {
+		t.Error("expected missing base to error")
+	}
+	if _, _, err := Merge(base, ours, theirs); err == nil {
+		t.Error("expected merging array bodies without a primary key to error")
+	}
+
+	base = csvVersion("title", "id,name,count\na,apple,1\n")
+	ours = csvVersion("title", "id,name,count\na,apple,2\n")
+	theirs = csvVersion("title", "id,name,count\na,apple,3\n")
+	if _, _, err := Merge(base, ours, theirs, func(cfg *Config) { cfg.PrimaryKey = []string{"missing"} }); err == nil {
+		t.Error("expected a missing primary key column to error")
+	}
+
+	base = csvVersion("title", "id,name,count\na,apple,1\n")
+	ours = csvVersion("title", "id,name,count\na,apple,2\na,avocado,2\n")
+	theirs = csvVersion("title", "id,name,count\na,apple,3\n")
+	if _, _, err := Merge(base, ours, theirs, func(cfg *Config) { cfg.PrimaryKey = []string{"id"} }); err == nil {
+		t.Error("expected a duplicate key to error")
+	}
+
+	base = csvVersion("title", "id,name,count\na,apple,1\n")
+	ours = csvVersion("title", "id,name,count\na,apple,2\n")
+	theirs = &dataset.Dataset{Structure: ours.Structure}
+	if _, _, err := Merge(base, ours, theirs); err == nil {
+		t.Error("expected a missing body to error")
+	}
+}
+
+func TestMergeVersions(t *testing.T) {
+	store := cafs.NewMapstore()
+	save := func(title, body string, prev *dataset.Dataset) *dataset.Dataset {
+		ds := csvVersion(title, body)
+		ds.Commit = &dataset.Commit{Title: title}
+		if prev != nil {
+			ds.PreviousPath = prev.Path
+		}
+		path, err := dsfs.CreateDataset(store, ds, prev, dstest.PrivKey, true, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds, err = dsfs.LoadDataset(store, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ds
+	}
+
+	base := save("base", "id,name,count\na,apple,1\n", nil)
+	ours := save("ours", "id,name,count\na,apple,1\nb,banana,2\n", base)
+	ours = save("ours again", "id,name,count\na,apple,5\nb,banana,2\n", ours)
+	theirs := save("theirs", "id,name,count\na,apple,1\nc,cherry,3\n", base)
+
+	ancestor, err := CommonAncestor(store, ours.Path, theirs.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ancestor != base.Path {
+		t.Errorf("ancestor mismatch. expected: %s, got: %s", base.Path, ancestor)
+	}
+
+	ds, conflicts, err := MergeVersions(store, ours.Path, theirs.Path, func(cfg *Config) {
+		cfg.PrimaryKey = []string{"id"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Path != "/meta/title" {
+		t.Errorf("expected a title conflict, got: %v", conflicts)
+	}
+	if ds.PreviousPath != ours.Path {
+		t.Errorf("previous path mismatch. expected: %s, got: %s", ours.Path, ds.PreviousPath)
+	}
+	expect := "id,name,count\na,apple,5\nb,banana,2\nc,cherry,3\n"
+	if got := bodyString(t, ds); got != expect {
+		t.Errorf("body mismatch. expected:\n%s\ngot:\n%s", expect, got)
+	}
+
+	unrelated := save("unrelated", "id,name,count\n", nil)
+	if _, err := CommonAncestor(store, ours.Path, unrelated.Path); err == nil {
+		t.Error("expected unrelated versions to error")
+	}
+}
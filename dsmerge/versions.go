@@ -0,0 +1,102 @@
+package dsmerge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// maxHistory caps the number of versions walked looking for an ancestor
+const maxHistory = 10000
+
+// CommonAncestor gives the path of the most recent version in the histories
+// of both a & b, following each version's PreviousPath. a version is in its
+// own history
+func CommonAncestor(store cafs.Filestore, a, b string) (string, error) {
+	history := map[string]bool{}
+	if err := walkHistory(store, a, func(path string) bool {
+		history[path] = true
+		return true
+	}); err != nil {
+		return "", err
+	}
+
+	ancestor := ""
+	if err := walkHistory(store, b, func(path string) bool {
+		if history[path] {
+			ancestor = path
+			return false
+		}
+		return true
+	}); err != nil {
+		return "", err
+	}
+	if ancestor == "" {
+		return "", fmt.Errorf("%s & %s don't share a common ancestor", a, b)
+	}
+	return ancestor, nil
+}
+
+// walkHistory calls fn with the path of each version from path back to the
+// first version, stopping when fn returns false
+func walkHistory(store cafs.Filestore, path string, fn func(path string) bool) error {
+	for i := 0; path != ""; i++ {
+		if i == maxHistory {
+			return fmt.Errorf("history is longer than %d versions", maxHistory)
+		}
+		p, err := historyPath(path)
+		if err != nil {
+			return err
+		}
+		if !fn(p) {
+			return nil
+		}
+		ds, err := dsfs.LoadDatasetRefs(store, p)
+		if err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("loading %s: %w", p, err)
+		}
+		path = ds.PreviousPath
+	}
+	return nil
+}
+
+// historyPath normalizes a version path for comparison
+func historyPath(path string) (string, error) {
+	p, err := dataset.NormalizeStorePath(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(p, "/"+dsfs.PackageFileDataset.Filename()), nil
+}
+
+// MergeVersions merges two stored versions of a dataset, using their common
+// ancestor as the base
+func MergeVersions(store cafs.Filestore, ours, theirs string, configs ...func(cfg *Config)) (*dataset.Dataset, []Conflict, error) {
+	base, err := CommonAncestor(store, ours, theirs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var versions [3]*dataset.Dataset
+	for i, path := range []string{base, ours, theirs} {
+		ds, err := dsfs.LoadDataset(store, path)
+		if err != nil {
+			log.Debug(err.Error())
+			return nil, nil, fmt.Errorf("loading %s: %w", path, err)
+		}
+		if ds.BodyPath != "" {
+			f, err := dsfs.LoadBody(store, ds)
+			if err != nil {
+				log.Debug(err.Error())
+				return nil, nil, fmt.Errorf("loading %s body: %w", path, err)
+			}
+			ds.SetBodyFile(f)
+		}
+		versions[i] = ds
+	}
+	return Merge(versions[0], versions[1], versions[2], configs...)
+}
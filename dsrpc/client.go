@@ -0,0 +1,132 @@
+package dsrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"google.golang.org/grpc"
+)
+
+// Client calls a remote Entries service
+type Client struct {
+	conn grpc.ClientConnInterface
+}
+
+// NewClient creates a client from a grpc connection
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{conn: conn}
+}
+
+// callOpts selects the json codec for all calls
+var callOpts = []grpc.CallOption{grpc.CallContentSubtype(codecName)}
+
+// Structure fetches the structure of a remote dataset body
+func (c *Client) Structure(ctx context.Context, ref string) (*dataset.Structure, error) {
+	res := &StructureMessage{}
+	if err := c.conn.Invoke(ctx, structureMethod, &EntriesRequest{Ref: ref}, res, callOpts...); err != nil {
+		return nil, err
+	}
+	return res.Structure, nil
+}
+
+// EntryReader opens a stream of entries from a remote dataset body. Limit &
+// offset page the body, a limit of zero reads all entries
+func (c *Client) EntryReader(ctx context.Context, ref string, limit, offset int) (dsio.EntryReader, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[0], readEntriesMethod, callOpts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := stream.SendMsg(&EntriesRequest{Ref: ref, Limit: limit, Offset: offset}); err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	first := &EntryMessage{}
+	if err := stream.RecvMsg(first); err != nil {
+		cancel()
+		return nil, err
+	}
+	if first.Structure == nil {
+		cancel()
+		return nil, fmt.Errorf("expected first message to carry a structure")
+	}
+
+	return &streamReader{st: first.Structure, stream: stream, cancel: cancel}, nil
+}
+
+// streamReader adapts a ReadEntries stream to the EntryReader interface
+type streamReader struct {
+	st     *dataset.Structure
+	stream grpc.ClientStream
+	cancel context.CancelFunc
+}
+
+// Structure gives this reader's structure
+func (r *streamReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry receives one entry
+func (r *streamReader) ReadEntry() (dsio.Entry, error) {
+	msg := &EntryMessage{}
+	if err := r.stream.RecvMsg(msg); err != nil {
+		if err != io.EOF {
+			log.Debug(err.Error())
+		}
+		return dsio.Entry{}, err
+	}
+	return dsio.Entry{Index: msg.Index, Key: msg.Key, Value: msg.Value}, nil
+}
+
+// Close cancels the stream
+func (r *streamReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+// EntryWriter opens a stream that writes entries to a remote dataset body
+func (c *Client) EntryWriter(ctx context.Context, ref string, st *dataset.Structure) (dsio.EntryWriter, error) {
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[1], writeEntriesMethod, callOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(&EntryMessage{Ref: ref, Structure: st}); err != nil {
+		return nil, err
+	}
+	return &streamWriter{st: st, stream: stream}, nil
+}
+
+// streamWriter adapts a WriteEntries stream to the EntryWriter interface
+type streamWriter struct {
+	st     *dataset.Structure
+	stream grpc.ClientStream
+}
+
+// Structure gives this writer's structure
+func (w *streamWriter) Structure() *dataset.Structure {
+	return w.st
+}
+
+// WriteEntry sends one entry
+func (w *streamWriter) WriteEntry(ent dsio.Entry) error {
+	return w.stream.SendMsg(&EntryMessage{Index: ent.Index, Key: ent.Key, Value: ent.Value})
+}
+
+// Close finishes the stream, waiting for the server to confirm all entries
+// were written
+func (w *streamWriter) Close() error {
+	if err := w.stream.CloseSend(); err != nil {
+		return err
+	}
+	res := &WriteResult{}
+	return w.stream.RecvMsg(res)
+}
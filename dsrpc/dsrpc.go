@@ -0,0 +1,116 @@
+// Package dsrpc defines a gRPC service for streaming dataset structures &
+// body entries between processes, backed by dsio readers & writers. The
+// service contract is described in entries.proto. FlightServer serves bodies
+// as apache arrow flight streams
+package dsrpc
+
+import (
+	"bytes"
+	"encoding/json"
+
+	logger "github.com/ipfs/go-log"
+	"github.com/qri-io/dataset"
+	"google.golang.org/grpc/encoding"
+)
+
+var log = logger.Logger("dsrpc")
+
+// ServiceName is the fully-qualified name of the Entries service
+const ServiceName = "dsrpc.Entries"
+
+const (
+	structureMethod    = "/" + ServiceName + "/Structure"
+	readEntriesMethod  = "/" + ServiceName + "/ReadEntries"
+	writeEntriesMethod = "/" + ServiceName + "/WriteEntries"
+)
+
+// EntriesRequest asks for the body of the dataset at Ref
+type EntriesRequest struct {
+	Ref    string `json:"ref,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+}
+
+// StructureMessage carries a body structure
+type StructureMessage struct {
+	Structure *dataset.Structure `json:"structure,omitempty"`
+}
+
+// EntryMessage carries a single entry. The first message in a stream carries
+// a structure in place of an entry
+type EntryMessage struct {
+	Ref       string             `json:"ref,omitempty"`
+	Structure *dataset.Structure `json:"structure,omitempty"`
+	Index     int                `json:"index,omitempty"`
+	Key       string             `json:"key,omitempty"`
+	Value     interface{}        `json:"value,omitempty"`
+}
+
+// _entryMessage is a private struct for marshaling into & out of
+type _entryMessage EntryMessage
+
+// UnmarshalJSON decodes an EntryMessage. Whole numbers in values are decoded
+// as int64 & others as float64, so integers aren't turned into floats
+func (m *EntryMessage) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	msg := _entryMessage{}
+	if err := dec.Decode(&msg); err != nil {
+		return err
+	}
+	msg.Value = entryValue(msg.Value)
+	*m = EntryMessage(msg)
+	return nil
+}
+
+// entryValue replaces json.Number values decoded with UseNumber
+func entryValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n
+		}
+		f, _ := t.Float64()
+		return f
+	case []interface{}:
+		for i, val := range t {
+			t[i] = entryValue(val)
+		}
+	case map[string]interface{}:
+		for key, val := range t {
+			t[key] = entryValue(val)
+		}
+	}
+	return v
+}
+
+// WriteResult reports the number of entries written
+type WriteResult struct {
+	Entries int `json:"entries,omitempty"`
+}
+
+// codecName is the content-subtype Entries service messages are exchanged
+// under, see jsonCodec
+const codecName = "dsrpc-json"
+
+func init() {
+	// registered codecs are selected per call by content-subtype, leaving
+	// other services on a server to the default protobuf codec
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec encodes messages with encoding/json, which lets the service run
+// without generated protobuf types. Clients select it per call, see callOpts
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
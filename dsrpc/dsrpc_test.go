@@ -0,0 +1,230 @@
+package dsrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// memStore is a Source & Sink backed by in-memory entries
+type memStore struct {
+	st      *dataset.Structure
+	entries []dsio.Entry
+}
+
+func (m *memStore) OpenEntryReader(ctx context.Context, ref string) (dsio.EntryReader, error) {
+	if ref != "me/data" {
+		return nil, fmt.Errorf("not found")
+	}
+	return &sliceReader{st: m.st, entries: m.entries}, nil
+}
+
+func (m *memStore) OpenEntryWriter(ctx context.Context, ref string, st *dataset.Structure) (dsio.EntryWriter, error) {
+	m.st = st
+	m.entries = nil
+	return &sliceWriter{m}, nil
+}
+
+type sliceReader struct {
+	st      *dataset.Structure
+	entries []dsio.Entry
+}
+
+func (r *sliceReader) Structure() *dataset.Structure { return r.st }
+func (r *sliceReader) Close() error                  { return nil }
+func (r *sliceReader) ReadEntry() (dsio.Entry, error) {
+	if len(r.entries) == 0 {
+		return dsio.Entry{}, io.EOF
+	}
+	ent := r.entries[0]
+	r.entries = r.entries[1:]
+	return ent, nil
+}
+
+type sliceWriter struct{ m *memStore }
+
+func (w *sliceWriter) Structure() *dataset.Structure { return w.m.st }
+func (w *sliceWriter) Close() error                  { return nil }
+func (w *sliceWriter) WriteEntry(ent dsio.Entry) error {
+	w.m.entries = append(w.m.entries, ent)
+	return nil
+}
+
+// loopbackConn connects a client directly to a server, encoding every message
+// with the json codec
+type loopbackConn struct {
+	srv *Server
+}
+
+func (c loopbackConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	data, err := jsonCodec{}.Marshal(args)
+	if err != nil {
+		return err
+	}
+	dec := func(v interface{}) error { return jsonCodec{}.Unmarshal(data, v) }
+	res, err := serviceDesc.Methods[0].Handler(c.srv, ctx, dec, nil)
+	if err != nil {
+		return err
+	}
+	out, err := jsonCodec{}.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return jsonCodec{}.Unmarshal(out, reply)
+}
+
+func (c loopbackConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	toServer := make(chan []byte, 16)
+	toClient := make(chan []byte, 16)
+	done := make(chan error, 1)
+
+	go func() {
+		err := desc.Handler(c.srv, &pipeStream{ctx: ctx, in: toServer, out: toClient})
+		close(toClient)
+		done <- err
+	}()
+
+	return &pipeStream{ctx: ctx, in: toClient, out: toServer, done: done}, nil
+}
+
+type pipeStream struct {
+	ctx  context.Context
+	in   chan []byte
+	out  chan []byte
+	done chan error
+}
+
+func (s *pipeStream) SetHeader(metadata.MD) error  { return nil }
+func (s *pipeStream) SendHeader(metadata.MD) error { return nil }
+func (s *pipeStream) SetTrailer(metadata.MD)       {}
+func (s *pipeStream) Header() (metadata.MD, error) { return nil, nil }
+func (s *pipeStream) Trailer() metadata.MD         { return nil }
+func (s *pipeStream) Context() context.Context     { return s.ctx }
+func (s *pipeStream) CloseSend() error             { close(s.out); return nil }
+func (s *pipeStream) SendMsg(m interface{}) error {
+	data, err := jsonCodec{}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	s.out <- data
+	return nil
+}
+func (s *pipeStream) RecvMsg(m interface{}) error {
+	data, ok := <-s.in
+	if !ok {
+		if s.done != nil {
+			if err := <-s.done; err != nil {
+				return err
+			}
+		}
+		return io.EOF
+	}
+	return jsonCodec{}.Unmarshal(data, m)
+}
+
+func TestClientServer(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	store := &memStore{
+		st: st,
+		entries: []dsio.Entry{
+			{Index: 0, Value: "a"},
+			{Index: 1, Value: int64(2)},
+			{Index: 2, Value: map[string]interface{}{"c": []interface{}{int64(1), 1.5}}},
+		},
+	}
+	c := NewClient(loopbackConn{&Server{Source: store, Sink: store}})
+	ctx := context.Background()
+
+	got, err := c.Structure(ctx, "me/data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Format != "json" {
+		t.Errorf("structure format mismatch. got: %s", got.Format)
+	}
+	if _, err := c.Structure(ctx, "me/missing"); err == nil {
+		t.Error("expected missing ref to error")
+	}
+
+	r, err := c.EntryReader(ctx, "me/data", 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range store.entries[1:] {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expect, ent) {
+			t.Errorf("entry mismatch. expected: %v, got: %v", expect, ent)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected EOF, got: %v", err)
+	}
+	r.Close()
+
+	w, err := c.EntryWriter(ctx, "me/copy", st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := w.WriteEntry(dsio.Entry{Index: i, Value: float64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.entries) != 3 || store.entries[2].Value != int64(2) {
+		t.Errorf("unexpected written entries: %v", store.entries)
+	}
+}
+
+func TestGRPCServer(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	store := &memStore{st: st, entries: []dsio.Entry{{Index: 0, Value: int64(1)}, {Index: 1, Value: "b"}}}
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	(&Server{Source: store}).Register(srv)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	r, err := NewClient(conn).EntryReader(context.Background(), "me/data", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	for _, expect := range store.entries {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expect, ent) {
+			t.Errorf("entry mismatch. expected: %v, got: %v", expect, ent)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected EOF, got: %v", err)
+	}
+}
@@ -0,0 +1,344 @@
+package dsrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// FlightServiceName is the fully-qualified name of the arrow flight service
+const FlightServiceName = "arrow.flight.protocol.FlightService"
+
+const (
+	getFlightInfoMethod = "/" + FlightServiceName + "/GetFlightInfo"
+	getSchemaMethod     = "/" + FlightServiceName + "/GetSchema"
+)
+
+// flightServer is the handler type of the flight service
+type flightServer interface {
+	GetFlightInfo(ctx context.Context, desc *FlightDescriptor) (*FlightInfo, error)
+	GetSchema(ctx context.Context, desc *FlightDescriptor) (*SchemaResult, error)
+	DoGet(ctx context.Context, ticket *Ticket, send func(*FlightData) error) error
+}
+
+var _ flightServer = (*FlightServer)(nil)
+
+// FlightServer serves dataset bodies from a Source as apache arrow flight
+// streams, see https://arrow.apache.org/docs/format/Flight.html. Bodies are
+// sent as arrow record batches encoded with dsio.ArrowWriter, and must be
+// tabular, see dsio.ArrowSchema.
+//
+// Descriptors name a dataset ref, either as a path of ref segments
+// ("me", "data") or as a command holding the ref ("me/data"). Tickets hold
+// a ref. GetFlightInfo, GetSchema & DoGet are implemented, other flight
+// methods respond as unimplemented. Messages are exchanged in the protobuf
+// encoding of Flight.proto with grpc's default codec, so the service can share
+// a server with any other
+type FlightServer struct {
+	Source Source
+}
+
+// Register adds the flight service to a grpc server
+func (s *FlightServer) Register(r grpc.ServiceRegistrar) {
+	r.RegisterService(&flightServiceDesc, s)
+}
+
+// GetFlightInfo describes the body named by desc. The body is read from a
+// single endpoint on this server. The number of records is given when the
+// body structure records it, the number of bytes isn't known
+func (s *FlightServer) GetFlightInfo(ctx context.Context, desc *FlightDescriptor) (*FlightInfo, error) {
+	ref, err := desc.ref()
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.structure(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := dsio.ArrowSchemaMessage(arrowStructure(st))
+	if err != nil {
+		return nil, err
+	}
+	info := &FlightInfo{
+		Schema:           schema,
+		FlightDescriptor: desc,
+		Endpoints:        []*FlightEndpoint{{Ticket: &Ticket{Ticket: []byte(ref)}}},
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}
+	if st.Entries > 0 {
+		info.TotalRecords = int64(st.Entries)
+	}
+	return info, nil
+}
+
+// GetSchema gives the arrow schema of the body named by desc
+func (s *FlightServer) GetSchema(ctx context.Context, desc *FlightDescriptor) (*SchemaResult, error) {
+	ref, err := desc.ref()
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.structure(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := dsio.ArrowSchemaMessage(arrowStructure(st))
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaResult{Schema: schema}, nil
+}
+
+// DoGet streams the body at the ticket's ref to send as arrow messages: a
+// schema, then record batches & any dictionary batches they use
+func (s *FlightServer) DoGet(ctx context.Context, ticket *Ticket, send func(*FlightData) error) error {
+	if s.Source == nil {
+		return fmt.Errorf("server does not support reading entries")
+	}
+	r, err := s.Source.OpenEntryReader(ctx, string(ticket.Ticket))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := dsio.NewArrowMessageWriter(arrowStructure(r.Structure()), func(msg dsio.ArrowMessage) error {
+		return send(&FlightData{DataHeader: msg.Header, DataBody: msg.Body})
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ent, err := r.ReadEntry()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			log.Debug(err.Error())
+			return fmt.Errorf("reading entry: %s", err.Error())
+		}
+		if err := w.WriteEntry(ent); err != nil {
+			log.Debug(err.Error())
+			return fmt.Errorf("writing entry %d: %s", ent.Index, err.Error())
+		}
+	}
+	return w.Close()
+}
+
+// structure reads the structure of the body at ref
+func (s *FlightServer) structure(ctx context.Context, ref string) (*dataset.Structure, error) {
+	if s.Source == nil {
+		return nil, fmt.Errorf("server does not support reading entries")
+	}
+	r, err := s.Source.OpenEntryReader(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return r.Structure(), nil
+}
+
+// arrowStructure gives the structure bodies are streamed with. Format config
+// is only kept for arrow bodies, so arrow column hints carry over
+func arrowStructure(st *dataset.Structure) *dataset.Structure {
+	ast := &dataset.Structure{Format: dataset.ArrowDataFormat.String(), Schema: st.Schema}
+	if st.Format == ast.Format {
+		ast.FormatConfig = st.FormatConfig
+	}
+	return ast
+}
+
+// FlightDescriptorType enumerates the ways a flight descriptor names a flight
+type FlightDescriptorType int32
+
+const (
+	// FlightDescriptorUnknown is the zero descriptor type
+	FlightDescriptorUnknown FlightDescriptorType = iota
+	// FlightDescriptorPath names a flight with path segments
+	FlightDescriptorPath
+	// FlightDescriptorCmd names a flight with an opaque command
+	FlightDescriptorCmd
+)
+
+// Flight messages are hand-written protobuf messages with the field numbers
+// of Flight.proto, encoded by grpc's default codec from their struct tags
+
+// FlightDescriptor names a flight
+type FlightDescriptor struct {
+	Type FlightDescriptorType `protobuf:"varint,1,opt,name=type,proto3"`
+	Cmd  []byte               `protobuf:"bytes,2,opt,name=cmd,proto3"`
+	Path []string             `protobuf:"bytes,3,rep,name=path,proto3"`
+}
+
+// ref gives the dataset ref a descriptor names
+func (d *FlightDescriptor) ref() (string, error) {
+	switch d.Type {
+	case FlightDescriptorPath:
+		return strings.Join(d.Path, "/"), nil
+	case FlightDescriptorCmd:
+		return string(d.Cmd), nil
+	}
+	return "", fmt.Errorf("unsupported flight descriptor type: %d", d.Type)
+}
+
+// Ticket identifies a stream to read with DoGet
+type Ticket struct {
+	Ticket []byte `protobuf:"bytes,1,opt,name=ticket,proto3"`
+}
+
+// Location is the uri of a flight service
+type Location struct {
+	URI string `protobuf:"bytes,1,opt,name=uri,proto3"`
+}
+
+// FlightEndpoint is a location a stream can be read from. An endpoint without
+// locations is read from the server that described it
+type FlightEndpoint struct {
+	Ticket    *Ticket     `protobuf:"bytes,1,opt,name=ticket,proto3"`
+	Locations []*Location `protobuf:"bytes,2,rep,name=location,proto3"`
+}
+
+// FlightInfo describes a flight. Schema is an encapsulated arrow schema
+// message, unknown totals are -1
+type FlightInfo struct {
+	Schema           []byte            `protobuf:"bytes,1,opt,name=schema,proto3"`
+	FlightDescriptor *FlightDescriptor `protobuf:"bytes,2,opt,name=flight_descriptor,proto3"`
+	Endpoints        []*FlightEndpoint `protobuf:"bytes,3,rep,name=endpoint,proto3"`
+	TotalRecords     int64             `protobuf:"varint,4,opt,name=total_records,proto3"`
+	TotalBytes       int64             `protobuf:"varint,5,opt,name=total_bytes,proto3"`
+}
+
+// SchemaResult carries an encapsulated arrow schema message
+type SchemaResult struct {
+	Schema []byte `protobuf:"bytes,1,opt,name=schema,proto3"`
+}
+
+// FlightData carries one arrow IPC message: a flatbuffer message header &
+// body
+type FlightData struct {
+	FlightDescriptor *FlightDescriptor `protobuf:"bytes,1,opt,name=flight_descriptor,proto3"`
+	DataHeader       []byte            `protobuf:"bytes,2,opt,name=data_header,proto3"`
+	AppMetadata      []byte            `protobuf:"bytes,3,opt,name=app_metadata,proto3"`
+	DataBody         []byte            `protobuf:"bytes,1000,opt,name=data_body,proto3"`
+}
+
+var (
+	_ protoadapt.MessageV1 = (*FlightDescriptor)(nil)
+	_ protoadapt.MessageV1 = (*Ticket)(nil)
+	_ protoadapt.MessageV1 = (*Location)(nil)
+	_ protoadapt.MessageV1 = (*FlightEndpoint)(nil)
+	_ protoadapt.MessageV1 = (*FlightInfo)(nil)
+	_ protoadapt.MessageV1 = (*SchemaResult)(nil)
+	_ protoadapt.MessageV1 = (*FlightData)(nil)
+)
+
+// messageString formats a flight message in the protobuf text format
+func messageString(m protoadapt.MessageV1) string {
+	return prototext.MarshalOptions{}.Format(protoadapt.MessageV2Of(m))
+}
+
+func (d *FlightDescriptor) Reset()         { *d = FlightDescriptor{} }
+func (d *FlightDescriptor) String() string { return messageString(d) }
+func (*FlightDescriptor) ProtoMessage()    {}
+
+func (t *Ticket) Reset()         { *t = Ticket{} }
+func (t *Ticket) String() string { return messageString(t) }
+func (*Ticket) ProtoMessage()    {}
+
+func (l *Location) Reset()         { *l = Location{} }
+func (l *Location) String() string { return messageString(l) }
+func (*Location) ProtoMessage()    {}
+
+func (e *FlightEndpoint) Reset()         { *e = FlightEndpoint{} }
+func (e *FlightEndpoint) String() string { return messageString(e) }
+func (*FlightEndpoint) ProtoMessage()    {}
+
+func (i *FlightInfo) Reset()         { *i = FlightInfo{} }
+func (i *FlightInfo) String() string { return messageString(i) }
+func (*FlightInfo) ProtoMessage()    {}
+
+func (r *SchemaResult) Reset()         { *r = SchemaResult{} }
+func (r *SchemaResult) String() string { return messageString(r) }
+func (*SchemaResult) ProtoMessage()    {}
+
+func (d *FlightData) Reset()         { *d = FlightData{} }
+func (d *FlightData) String() string { return messageString(d) }
+func (*FlightData) ProtoMessage()    {}
+
+var flightServiceDesc = grpc.ServiceDesc{
+	ServiceName: FlightServiceName,
+	HandlerType: (*flightServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFlightInfo",
+			Handler:    getFlightInfoHandler,
+		},
+		{
+			MethodName: "GetSchema",
+			Handler:    getSchemaHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DoGet",
+			Handler:       doGetHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "Flight.proto",
+}
+
+func getFlightInfoHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	desc := &FlightDescriptor{}
+	if err := dec(desc); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(flightServer).GetFlightInfo(ctx, desc)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: getFlightInfoMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(flightServer).GetFlightInfo(ctx, req.(*FlightDescriptor))
+	}
+	return interceptor(ctx, desc, info, handler)
+}
+
+func getSchemaHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	desc := &FlightDescriptor{}
+	if err := dec(desc); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(flightServer).GetSchema(ctx, desc)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: getSchemaMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(flightServer).GetSchema(ctx, req.(*FlightDescriptor))
+	}
+	return interceptor(ctx, desc, info, handler)
+}
+
+func doGetHandler(srv interface{}, stream grpc.ServerStream) error {
+	ticket := &Ticket{}
+	if err := stream.RecvMsg(ticket); err != nil {
+		return err
+	}
+	return srv.(flightServer).DoGet(stream.Context(), ticket, func(data *FlightData) error {
+		return stream.SendMsg(data)
+	})
+}
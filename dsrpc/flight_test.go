@@ -0,0 +1,204 @@
+package dsrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+func TestFlightServer(t *testing.T) {
+	st := &dataset.Structure{
+		Format:  "csv",
+		Entries: 3,
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "name", "type": "string"},
+				},
+			},
+		},
+	}
+	store := &memStore{st: st, entries: []dsio.Entry{
+		{Index: 0, Value: []interface{}{1, "a"}},
+		{Index: 1, Value: []interface{}{2, nil}},
+		{Index: 2, Value: []interface{}{3, "c"}},
+	}}
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	(&FlightServer{Source: store}).Register(srv)
+	(&Server{Source: store}).Register(srv)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+
+	schema, err := dsio.ArrowSchemaMessage(arrowStructure(st))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	desc := &FlightDescriptor{Type: FlightDescriptorPath, Path: []string{"me", "data"}}
+	info := &FlightInfo{}
+	if err := conn.Invoke(ctx, getFlightInfoMethod, desc, info); err != nil {
+		t.Fatal(err)
+	}
+	expect := &FlightInfo{
+		Schema:           schema,
+		FlightDescriptor: desc,
+		Endpoints:        []*FlightEndpoint{{Ticket: &Ticket{Ticket: []byte("me/data")}}},
+		TotalRecords:     3,
+		TotalBytes:       -1,
+	}
+	if !reflect.DeepEqual(expect, info) {
+		t.Errorf("flight info mismatch. expected: %v, got: %v", expect, info)
+	}
+
+	res := &SchemaResult{}
+	if err := conn.Invoke(ctx, getSchemaMethod, &FlightDescriptor{Type: FlightDescriptorCmd, Cmd: []byte("me/data")}, res); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(schema, res.Schema) {
+		t.Error("schema mismatch")
+	}
+	if err := conn.Invoke(ctx, getSchemaMethod, &FlightDescriptor{Type: FlightDescriptorCmd, Cmd: []byte("me/missing")}, res); err == nil {
+		t.Error("expected missing ref to error")
+	}
+
+	stream, err := conn.NewStream(ctx, &flightServiceDesc.Streams[0], "/"+FlightServiceName+"/DoGet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.SendMsg(info.Endpoints[0].Ticket); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	// frame received messages as an IPC stream
+	buf := &bytes.Buffer{}
+	for {
+		data := &FlightData{}
+		if err := stream.RecvMsg(data); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		binary.Write(buf, binary.LittleEndian, uint32(0xffffffff))
+		binary.Write(buf, binary.LittleEndian, uint32(len(data.DataHeader)))
+		buf.Write(data.DataHeader)
+		buf.Write(data.DataBody)
+	}
+	binary.Write(buf, binary.LittleEndian, uint64(0xffffffff))
+
+	r, err := dsio.NewArrowReader(arrowStructure(st), buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ent := range store.entries {
+		got, err := r.ReadEntry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ent.Value, got.Value) {
+			t.Errorf("entry %d mismatch. expected: %v, got: %v", ent.Index, ent.Value, got.Value)
+		}
+	}
+	if _, err := r.ReadEntry(); err != io.EOF {
+		t.Errorf("expected EOF, got: %v", err)
+	}
+
+	// entries are served alongside flights
+	er, err := NewClient(conn).EntryReader(ctx, "me/data", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer er.Close()
+	if ent, err := er.ReadEntry(); err != nil || !reflect.DeepEqual(ent.Value, []interface{}{int64(1), "a"}) {
+		t.Errorf("expected first entry, got: %v, %v", ent.Value, err)
+	}
+}
+
+func TestFlightMessages(t *testing.T) {
+	msgs := []protoadapt.MessageV1{
+		&FlightDescriptor{Type: FlightDescriptorPath, Path: []string{"me", "data"}},
+		&FlightDescriptor{Type: FlightDescriptorCmd, Cmd: []byte("me/data")},
+		&FlightInfo{
+			Schema:       []byte("schema"),
+			Endpoints:    []*FlightEndpoint{{Ticket: &Ticket{Ticket: []byte("t")}, Locations: []*Location{{URI: "grpc://localhost:8815"}}}},
+			TotalRecords: -1,
+			TotalBytes:   1 << 40,
+		},
+		&FlightData{DataHeader: []byte("header"), DataBody: []byte("body")},
+	}
+	for _, msg := range msgs {
+		data, err := proto.Marshal(protoadapt.MessageV2Of(msg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := reflect.New(reflect.TypeOf(msg).Elem()).Interface().(protoadapt.MessageV1)
+		if err := proto.Unmarshal(data, protoadapt.MessageV2Of(got)); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(protoadapt.MessageV2Of(msg), protoadapt.MessageV2Of(got)) {
+			t.Errorf("round trip mismatch. expected: %v, got: %v", msg, got)
+		}
+	}
+
+	// field numbers follow Flight.proto
+	cases := []struct {
+		msg    protoadapt.MessageV1
+		expect []byte
+	}{
+		{&Ticket{Ticket: []byte("abc")}, []byte{0x0a, 3, 'a', 'b', 'c'}},
+		{&FlightDescriptor{Type: FlightDescriptorCmd, Cmd: []byte("x")}, []byte{0x08, 2, 0x12, 1, 'x'}},
+		{&FlightDescriptor{Type: FlightDescriptorPath, Path: []string{"a", "b"}}, []byte{0x08, 1, 0x1a, 1, 'a', 0x1a, 1, 'b'}},
+		{&FlightEndpoint{Locations: []*Location{{URI: "u"}}}, []byte{0x12, 3, 0x0a, 1, 'u'}},
+		{&FlightInfo{TotalRecords: 1, TotalBytes: 2}, []byte{0x20, 1, 0x28, 2}},
+		{&FlightData{DataHeader: []byte("h"), DataBody: []byte("b")}, []byte{0x12, 1, 'h', 0xc2, 0x3e, 1, 'b'}},
+	}
+	for i, c := range cases {
+		got, err := proto.Marshal(protoadapt.MessageV2Of(c.msg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(c.expect, got) {
+			t.Errorf("case %d encoding mismatch. expected: %x, got: %x", i, c.expect, got)
+		}
+	}
+
+	// unknown field 4 is skipped
+	data := &FlightData{}
+	if err := proto.Unmarshal([]byte{0x20, 1, 0xc2, 0x3e, 1, 'x'}, protoadapt.MessageV2Of(data)); err != nil {
+		t.Fatal(err)
+	}
+	if string(data.DataBody) != "x" {
+		t.Errorf("expected body 'x', got: %q", data.DataBody)
+	}
+	if err := proto.Unmarshal([]byte{0x12, 5, 'x'}, protoadapt.MessageV2Of(data)); err == nil {
+		t.Error("expected an error decoding a truncated message")
+	}
+}
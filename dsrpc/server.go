@@ -0,0 +1,198 @@
+package dsrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"google.golang.org/grpc"
+)
+
+// Source opens entry readers for dataset references
+type Source interface {
+	OpenEntryReader(ctx context.Context, ref string) (dsio.EntryReader, error)
+}
+
+// Sink opens entry writers for dataset references
+type Sink interface {
+	OpenEntryWriter(ctx context.Context, ref string, st *dataset.Structure) (dsio.EntryWriter, error)
+}
+
+// entriesServer is the handler type of the Entries service. grpc requires
+// handler types be interfaces
+type entriesServer interface {
+	Structure(ctx context.Context, req *EntriesRequest) (*StructureMessage, error)
+	ReadEntries(ctx context.Context, req *EntriesRequest, send func(*EntryMessage) error) error
+	WriteEntries(ctx context.Context, recv func() (*EntryMessage, error)) (*WriteResult, error)
+}
+
+var _ entriesServer = (*Server)(nil)
+
+// Server implements the Entries service. A nil Source or Sink disables reads
+// or writes respectively
+type Server struct {
+	Source Source
+	Sink   Sink
+}
+
+// Register adds the Entries service to a grpc server
+func (s *Server) Register(r grpc.ServiceRegistrar) {
+	r.RegisterService(&serviceDesc, s)
+}
+
+// Structure returns the structure of the body at req.Ref
+func (s *Server) Structure(ctx context.Context, req *EntriesRequest) (*StructureMessage, error) {
+	if s.Source == nil {
+		return nil, fmt.Errorf("server does not support reading entries")
+	}
+	r, err := s.Source.OpenEntryReader(ctx, req.Ref)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return &StructureMessage{Structure: r.Structure()}, nil
+}
+
+// ReadEntries streams entries from the body at req.Ref to send
+func (s *Server) ReadEntries(ctx context.Context, req *EntriesRequest, send func(*EntryMessage) error) error {
+	if s.Source == nil {
+		return fmt.Errorf("server does not support reading entries")
+	}
+	r, err := s.Source.OpenEntryReader(ctx, req.Ref)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := send(&EntryMessage{Ref: req.Ref, Structure: r.Structure()}); err != nil {
+		return err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	pr := &dsio.PagedReader{Reader: r, Limit: limit, Offset: req.Offset}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ent, err := pr.ReadEntry()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			log.Debug(err.Error())
+			return fmt.Errorf("reading entry: %s", err.Error())
+		}
+		if err := send(&EntryMessage{Index: ent.Index, Key: ent.Key, Value: ent.Value}); err != nil {
+			return err
+		}
+	}
+}
+
+// WriteEntries writes entries produced by recv to a sink, until recv returns
+// io.EOF. The first message must carry a ref & structure
+func (s *Server) WriteEntries(ctx context.Context, recv func() (*EntryMessage, error)) (*WriteResult, error) {
+	if s.Sink == nil {
+		return nil, fmt.Errorf("server does not support writing entries")
+	}
+	first, err := recv()
+	if err != nil {
+		return nil, err
+	}
+	if first.Ref == "" || first.Structure == nil {
+		return nil, fmt.Errorf("first message must specify ref & structure")
+	}
+
+	w, err := s.Sink.OpenEntryWriter(ctx, first.Ref, first.Structure)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &WriteResult{}
+	for {
+		msg, err := recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			w.Close()
+			return nil, err
+		}
+		if err := w.WriteEntry(dsio.Entry{Index: msg.Index, Key: msg.Key, Value: msg.Value}); err != nil {
+			w.Close()
+			log.Debug(err.Error())
+			return nil, fmt.Errorf("writing entry %d: %s", res.Entries, err.Error())
+		}
+		res.Entries++
+	}
+	return res, w.Close()
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*entriesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Structure",
+			Handler:    structureHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadEntries",
+			Handler:       readEntriesHandler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WriteEntries",
+			Handler:       writeEntriesHandler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "entries.proto",
+}
+
+func structureHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &EntriesRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(entriesServer).Structure(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: structureMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(entriesServer).Structure(ctx, req.(*EntriesRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func readEntriesHandler(srv interface{}, stream grpc.ServerStream) error {
+	req := &EntriesRequest{}
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	return srv.(entriesServer).ReadEntries(stream.Context(), req, func(msg *EntryMessage) error {
+		return stream.SendMsg(msg)
+	})
+}
+
+func writeEntriesHandler(srv interface{}, stream grpc.ServerStream) error {
+	res, err := srv.(entriesServer).WriteEntries(stream.Context(), func() (*EntryMessage, error) {
+		msg := &EntryMessage{}
+		if err := stream.RecvMsg(msg); err != nil {
+			return nil, err
+		}
+		return msg, nil
+	})
+	if err != nil {
+		return err
+	}
+	return stream.SendMsg(res)
+}
@@ -0,0 +1,63 @@
+// Package dstrace provides opt-in tracing spans for dataset operations.
+// Tracing is enabled by attaching a Tracer to a context with WithTracer,
+// operations run with a context that has no tracer create no-op spans.
+// The Tracer & Span interfaces are shaped after OpenTelemetry so an adapter
+// to an OpenTelemetry tracer is a thin wrapper
+package dstrace
+
+import "context"
+
+// Attribute is a key-value pair describing a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr creates an Attribute
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Tracer starts spans
+type Tracer interface {
+	// Start creates a span & a child context that carries it
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a single traced operation
+type Span interface {
+	// SetAttributes adds attributes to the span
+	SetAttributes(attrs ...Attribute)
+	// End completes the span, recording err if it isn't nil
+	End(err error)
+}
+
+type tracerKey struct{}
+
+// WithTracer returns a context that traces operations with t
+func WithTracer(ctx context.Context, t Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// TracerFromContext returns the tracer attached to ctx, or nil
+func TracerFromContext(ctx context.Context) Tracer {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(tracerKey{}).(Tracer)
+	return t
+}
+
+// StartSpan starts a span with the tracer attached to ctx. If ctx has no
+// tracer the returned span does nothing
+func StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	if t := TracerFromContext(ctx); t != nil {
+		return t.Start(ctx, name, attrs...)
+	}
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) End(error)                  {}
@@ -0,0 +1,62 @@
+package dstrace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestStartSpan(t *testing.T) {
+	// no tracer is a no-op
+	_, span := StartSpan(context.Background(), "noop")
+	span.SetAttributes(Attr("a", 1))
+	span.End(nil)
+
+	rec := &Recorder{}
+	ctx := WithTracer(context.Background(), rec)
+	childCtx, span := StartSpan(ctx, "op", Attr("path", "/a"))
+	span.SetAttributes(Attr("entries", 2))
+	_, child := StartSpan(childCtx, "child")
+	child.End(nil)
+	span.End(fmt.Errorf("oh no"))
+
+	spans := rec.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got: %d", len(spans))
+	}
+	s := spans[0]
+	if s.Name != "op" || s.Attrs["path"] != "/a" || s.Attrs["entries"] != 2 || !s.Ended || s.Err == nil || s.Parent != nil {
+		t.Errorf("unexpected span: %#v", s)
+	}
+	// spans started from a span's context are its children
+	if spans[1].Name != "child" || spans[1].Parent != s {
+		t.Errorf("unexpected child span: %#v", spans[1])
+	}
+}
+
+func TestRecorderConcurrentSpans(t *testing.T) {
+	rec := &Recorder{}
+	ctx := WithTracer(context.Background(), rec)
+	_, span := StartSpan(ctx, "op")
+	before := rec.Spans()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			span.SetAttributes(Attr("i", i))
+		}
+		span.End(nil)
+	}()
+	for i := 0; i < 100; i++ {
+		rec.Spans()
+	}
+	<-done
+
+	if before[0].Ended || len(before[0].Attrs) != 0 {
+		t.Errorf("expected snapshots not to change, got: %#v", before[0])
+	}
+	if s := rec.Spans()[0]; !s.Ended || s.Attrs["i"] != 99 {
+		t.Errorf("unexpected span: %#v", s)
+	}
+}
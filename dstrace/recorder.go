@@ -0,0 +1,89 @@
+package dstrace
+
+import (
+	"context"
+	"sync"
+)
+
+// Recorder is a Tracer that keeps finished spans in memory, useful for tests
+// & debugging
+type Recorder struct {
+	lk    sync.Mutex
+	spans []*recordedSpan
+}
+
+// RecordedSpan is a snapshot of a span captured by a Recorder. Parent is the
+// span carried by the context the span was started with, if any
+type RecordedSpan struct {
+	Name   string
+	Attrs  map[string]interface{}
+	Err    error
+	Ended  bool
+	Parent *RecordedSpan
+}
+
+// recordedSpan is a span being recorded. spans can be ended from other
+// goroutines than the one reading the recorder, so fields are guarded by the
+// recorder's lock
+type recordedSpan struct {
+	r      *Recorder
+	name   string
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+	parent *recordedSpan
+}
+
+type recordedSpanKey struct{}
+
+// Start implements the Tracer interface. The returned context carries the
+// new span, making it the parent of spans started from that context
+func (r *Recorder) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	s := &recordedSpan{r: r, name: name, attrs: map[string]interface{}{}}
+	s.parent, _ = ctx.Value(recordedSpanKey{}).(*recordedSpan)
+	s.SetAttributes(attrs...)
+	r.lk.Lock()
+	r.spans = append(r.spans, s)
+	r.lk.Unlock()
+	return context.WithValue(ctx, recordedSpanKey{}, s), s
+}
+
+// Spans returns snapshots of all spans started by the recorder, in start
+// order. Snapshots don't change as spans are ended
+func (r *Recorder) Spans() []*RecordedSpan {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	snapshots := make(map[*recordedSpan]*RecordedSpan, len(r.spans))
+	spans := make([]*RecordedSpan, len(r.spans))
+	for i, s := range r.spans {
+		attrs := make(map[string]interface{}, len(s.attrs))
+		for k, v := range s.attrs {
+			attrs[k] = v
+		}
+		spans[i] = &RecordedSpan{Name: s.name, Attrs: attrs, Err: s.err, Ended: s.ended}
+		snapshots[s] = spans[i]
+	}
+	for i, s := range r.spans {
+		if s.parent != nil {
+			spans[i].Parent = snapshots[s.parent]
+		}
+	}
+	return spans
+}
+
+// SetAttributes implements the Span interface
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	s.r.lk.Lock()
+	defer s.r.lk.Unlock()
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+// End implements the Span interface
+func (s *recordedSpan) End(err error) {
+	s.r.lk.Lock()
+	defer s.r.lk.Unlock()
+	s.err = err
+	s.ended = true
+}
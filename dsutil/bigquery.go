@@ -0,0 +1,247 @@
+package dsutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// BigQueryField is a column in a BigQuery table schema, as accepted by
+// "bq load --schema"
+type BigQueryField struct {
+	Name   string          `json:"name"`
+	Type   string          `json:"type"`
+	Mode   string          `json:"mode"`
+	Fields []BigQueryField `json:"fields,omitempty"`
+}
+
+// BigQuerySchema derives a BigQuery table schema from a structure. The
+// structure must describe an array of arrays with column definitions, or an
+// array of objects with properties. Arrays map to REPEATED fields, objects
+// with properties map to RECORD fields. Values BigQuery can't represent, like
+// nested arrays, are exported as JSON-encoded STRING fields. Column names are
+// sanitized to letters, numbers & underscores, names that sanitize to the same
+// BigQuery column are an error
+func BigQuerySchema(st *dataset.Structure) ([]BigQueryField, error) {
+	cols, err := bigQueryColumns(st)
+	if err != nil {
+		return nil, err
+	}
+	return bigQueryFields(cols)
+}
+
+// WriteBigQueryExport writes all entries in r to w as newline-delimited JSON
+// objects that match the schema produced by BigQuerySchema
+func WriteBigQueryExport(r dsio.EntryReader, w io.Writer) error {
+	cols, err := bigQueryColumns(r.Structure())
+	if err != nil {
+		return err
+	}
+	fields, err := bigQueryFields(cols)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	return dsio.EachEntry(r, func(i int, ent dsio.Entry, err error) error {
+		if err != nil {
+			return err
+		}
+		row := map[string]interface{}{}
+		switch v := ent.Value.(type) {
+		case []interface{}:
+			for j, val := range v {
+				if j < len(cols) && val != nil {
+					row[fields[j].Name] = bigQueryValue(fields[j], val)
+				}
+			}
+		case map[string]interface{}:
+			for j, c := range cols {
+				if val, ok := v[c.name]; ok && val != nil {
+					row[fields[j].Name] = bigQueryValue(fields[j], val)
+				}
+			}
+		default:
+			return fmt.Errorf("entry %d: expected array or object row, got: %T", i, ent.Value)
+		}
+		return enc.Encode(row)
+	})
+}
+
+type bigQueryColumn struct {
+	name   string
+	schema map[string]interface{}
+}
+
+func bigQueryColumns(st *dataset.Structure) ([]bigQueryColumn, error) {
+	if st == nil || st.Schema == nil || st.Schema["type"] != "array" {
+		return nil, fmt.Errorf("bigquery export requires a schema with a top-level array type")
+	}
+	items, _ := st.Schema["items"].(map[string]interface{})
+	switch items["type"] {
+	case "array":
+		defs, ok := items["items"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("bigquery export requires column definitions")
+		}
+		cols := make([]bigQueryColumn, len(defs))
+		for i, d := range defs {
+			sch, _ := d.(map[string]interface{})
+			name, _ := sch["title"].(string)
+			if name == "" {
+				name = dataset.AbstractColumnName(i)
+			}
+			cols[i] = bigQueryColumn{name: name, schema: sch}
+		}
+		return cols, nil
+	case "object":
+		props, ok := items["properties"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("bigquery export requires object properties")
+		}
+		return bigQueryPropertyColumns(props), nil
+	}
+	return nil, fmt.Errorf("bigquery export requires array or object items")
+}
+
+// bigQueryPropertyColumns gives the columns of object properties, sorted by
+// name
+func bigQueryPropertyColumns(props map[string]interface{}) []bigQueryColumn {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	cols := make([]bigQueryColumn, len(keys))
+	for i, k := range keys {
+		sch, _ := props[k].(map[string]interface{})
+		cols[i] = bigQueryColumn{name: k, schema: sch}
+	}
+	return cols
+}
+
+// bigQueryFields gives the fields of columns. BigQuery column names are case
+// insensitive, so columns whose sanitized names differ only in case collide
+func bigQueryFields(cols []bigQueryColumn) ([]BigQueryField, error) {
+	fields := make([]BigQueryField, len(cols))
+	names := map[string]string{}
+	for i, c := range cols {
+		f, err := bigQueryField(c.name, c.schema)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(f.Name)
+		if prev, ok := names[key]; ok {
+			return nil, fmt.Errorf("columns %q and %q both export as bigquery column %s", prev, c.name, f.Name)
+		}
+		names[key] = c.name
+		fields[i] = f
+	}
+	return fields, nil
+}
+
+var invalidBigQueryChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// bigQueryName sanitizes a column name to letters, numbers & underscores,
+// starting with a letter or underscore
+func bigQueryName(name string) string {
+	name = invalidBigQueryChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+func bigQueryField(name string, sch map[string]interface{}) (BigQueryField, error) {
+	f := BigQueryField{Name: bigQueryName(name), Mode: "NULLABLE"}
+	switch sch["type"] {
+	case "integer":
+		f.Type = "INTEGER"
+	case "number":
+		f.Type = "FLOAT"
+	case "boolean":
+		f.Type = "BOOLEAN"
+	case "array":
+		items, _ := sch["items"].(map[string]interface{})
+		// bigquery doesn't support arrays of arrays
+		if items == nil || items["type"] == "array" {
+			f.Type = "STRING"
+			return f, nil
+		}
+		item, err := bigQueryField(name, items)
+		if err != nil {
+			return item, err
+		}
+		item.Mode = "REPEATED"
+		return item, nil
+	case "object":
+		props, ok := sch["properties"].(map[string]interface{})
+		if !ok || len(props) == 0 {
+			f.Type = "STRING"
+			return f, nil
+		}
+		f.Type = "RECORD"
+		fields, err := bigQueryFields(bigQueryPropertyColumns(props))
+		if err != nil {
+			return f, fmt.Errorf("%s: %w", name, err)
+		}
+		f.Fields = fields
+	default:
+		f.Type = "STRING"
+	}
+	return f, nil
+}
+
+// bigQueryValue shapes a value to match a field, renaming record properties &
+// JSON-encoding complex values stored in STRING fields
+func bigQueryValue(f BigQueryField, v interface{}) interface{} {
+	if f.Mode == "REPEATED" {
+		arr, ok := v.([]interface{})
+		if !ok {
+			return []interface{}{bigQueryScalar(f, v)}
+		}
+		out := make([]interface{}, 0, len(arr))
+		for _, x := range arr {
+			if x != nil {
+				out = append(out, bigQueryScalar(f, x))
+			}
+		}
+		return out
+	}
+	return bigQueryScalar(f, v)
+}
+
+func bigQueryScalar(f BigQueryField, v interface{}) interface{} {
+	switch f.Type {
+	case "RECORD":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		rec := map[string]interface{}{}
+		for k, val := range obj {
+			for _, sub := range f.Fields {
+				if sub.Name == bigQueryName(k) && val != nil {
+					rec[sub.Name] = bigQueryValue(sub, val)
+				}
+			}
+		}
+		return rec
+	case "STRING":
+		switch v.(type) {
+		case []interface{}, map[string]interface{}:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil
+			}
+			return string(data)
+		}
+	}
+	return v
+}
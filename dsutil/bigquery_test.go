@@ -0,0 +1,112 @@
+package dsutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+func TestBigQueryExport(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "unit price", "type": "number"},
+					map[string]interface{}{"title": "tags", "type": "array", "items": map[string]interface{}{"type": "string"}},
+					map[string]interface{}{"title": "loc", "type": "object", "properties": map[string]interface{}{
+						"lat": map[string]interface{}{"type": "number"},
+					}},
+					map[string]interface{}{"title": "grid", "type": "array", "items": map[string]interface{}{"type": "array"}},
+				},
+			},
+		},
+	}
+
+	fields, err := BigQuerySchema(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(fields)
+	expect := `[{"name":"id","type":"INTEGER","mode":"NULLABLE"},{"name":"unit_price","type":"FLOAT","mode":"NULLABLE"},{"name":"tags","type":"STRING","mode":"REPEATED"},{"name":"loc","type":"RECORD","mode":"NULLABLE","fields":[{"name":"lat","type":"FLOAT","mode":"NULLABLE"}]},{"name":"grid","type":"STRING","mode":"NULLABLE"}]`
+	if string(data) != expect {
+		t.Errorf("schema mismatch.\nexpected: %s\ngot:      %s", expect, data)
+	}
+
+	r, err := dsio.NewEntryReader(st, bytes.NewBufferString(`[[1,2.5,["a","b"],{"lat":1.5},[[1],[2]]],[2,null,null,null,null]]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := WriteBigQueryExport(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	expectBody := `{"grid":"[[1],[2]]","id":1,"loc":{"lat":1.5},"tags":["a","b"],"unit_price":2.5}
+{"id":2}
+`
+	if buf.String() != expectBody {
+		t.Errorf("body mismatch.\nexpected: %s\ngot:      %s", expectBody, buf.String())
+	}
+
+	if _, err := BigQuerySchema(&dataset.Structure{Schema: dataset.BaseSchemaObject}); err == nil {
+		t.Error("expected object schema to error")
+	}
+}
+
+func TestBigQueryNameCollisions(t *testing.T) {
+	columns := func(titles ...string) *dataset.Structure {
+		items := make([]interface{}, len(titles))
+		for i, title := range titles {
+			items[i] = map[string]interface{}{"title": title, "type": "string"}
+		}
+		return &dataset.Structure{Format: "json", Schema: map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "array", "items": items},
+		}}
+	}
+	properties := func(props ...string) *dataset.Structure {
+		p := map[string]interface{}{}
+		for _, name := range props {
+			p[name] = map[string]interface{}{"type": "string"}
+		}
+		return &dataset.Structure{Format: "json", Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+				"rec": map[string]interface{}{"type": "object", "properties": p},
+			}},
+		}}
+	}
+
+	cases := []struct {
+		st  *dataset.Structure
+		err string
+	}{
+		{columns("a-b", "a_c"), ""},
+		{columns("a-b", "a_b"), `columns "a-b" and "a_b" both export as bigquery column a_b`},
+		{columns("1x", "_1x"), `columns "1x" and "_1x" both export as bigquery column _1x`},
+		{columns("Name", "name"), `columns "Name" and "name" both export as bigquery column name`},
+		{properties("a b", "a.b"), `rec: columns "a b" and "a.b" both export as bigquery column a_b`},
+	}
+	for i, c := range cases {
+		_, err := BigQuerySchema(c.st)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+		if c.err == "" {
+			continue
+		}
+		r, err := dsio.NewEntryReader(c.st, bytes.NewBufferString(`[]`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteBigQueryExport(r, &bytes.Buffer{}); err == nil || err.Error() != c.err {
+			t.Errorf("case %d export error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
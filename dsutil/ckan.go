@@ -0,0 +1,286 @@
+package dsutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// CKANPackage is a dataset in the CKAN action API "package" format
+type CKANPackage struct {
+	ID              string         `json:"id,omitempty"`
+	Name            string         `json:"name"`
+	Title           string         `json:"title,omitempty"`
+	Notes           string         `json:"notes,omitempty"`
+	URL             string         `json:"url,omitempty"`
+	Version         string         `json:"version,omitempty"`
+	LicenseID       string         `json:"license_id,omitempty"`
+	Author          string         `json:"author,omitempty"`
+	AuthorEmail     string         `json:"author_email,omitempty"`
+	Maintainer      string         `json:"maintainer,omitempty"`
+	MaintainerEmail string         `json:"maintainer_email,omitempty"`
+	OwnerOrg        string         `json:"owner_org,omitempty"`
+	Tags            []CKANTag      `json:"tags,omitempty"`
+	Groups          []CKANGroup    `json:"groups,omitempty"`
+	Extras          []CKANExtra    `json:"extras,omitempty"`
+	Resources       []CKANResource `json:"resources,omitempty"`
+}
+
+// CKANTag is a package keyword
+type CKANTag struct {
+	Name string `json:"name"`
+}
+
+// CKANGroup is a package category
+type CKANGroup struct {
+	Name string `json:"name"`
+}
+
+// CKANExtra is an arbitrary package key-value pair
+type CKANExtra struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// CKANResource is a file or link that belongs to a package
+type CKANResource struct {
+	URL         string `json:"url"`
+	Name        string `json:"name,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// meta fields with no CKAN equivalent are stored as extras using these keys
+var ckanExtraKeys = []string{"accessURL", "accrualPeriodicity", "identifier", "language", "licenseURL", "readmeURL"}
+
+var invalidCKANNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// CKANName converts a string to a valid CKAN package name: lowercase
+// alphanumeric characters, "-" and "_", between 2 & 100 characters
+func CKANName(s string) string {
+	name := invalidCKANNameChars.ReplaceAllString(strings.ToLower(s), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	for len(name) < 2 {
+		name += "_"
+	}
+	return name
+}
+
+// ToCKANPackage maps a dataset to a CKAN package. The dataset body becomes a
+// single resource pointing at Meta.DownloadURL, or BodyPath if no download
+// url is set
+func ToCKANPackage(ds *dataset.Dataset) (*CKANPackage, error) {
+	md := ds.Meta
+	if md == nil {
+		md = &dataset.Meta{}
+	}
+
+	name := ds.Name
+	if name == "" {
+		name = md.Title
+	}
+	if name == "" {
+		return nil, fmt.Errorf("dataset name or meta title is required to create a CKAN package")
+	}
+
+	pkg := &CKANPackage{
+		Name:    CKANName(name),
+		Title:   md.Title,
+		Notes:   md.Description,
+		URL:     md.HomeURL,
+		Version: md.Version,
+	}
+	if md.License != nil {
+		pkg.LicenseID = md.License.Type
+	}
+	if len(md.Contributors) > 0 && md.Contributors[0] != nil {
+		pkg.Author = md.Contributors[0].Fullname
+		pkg.AuthorEmail = md.Contributors[0].Email
+	}
+	if len(md.Contributors) > 1 && md.Contributors[1] != nil {
+		pkg.Maintainer = md.Contributors[1].Fullname
+		pkg.MaintainerEmail = md.Contributors[1].Email
+	}
+	for _, kw := range md.Keywords {
+		pkg.Tags = append(pkg.Tags, CKANTag{Name: kw})
+	}
+	for _, th := range md.Theme {
+		pkg.Groups = append(pkg.Groups, CKANGroup{Name: CKANName(th)})
+	}
+
+	extras := map[string]string{
+		"accessURL":          md.AccessURL,
+		"accrualPeriodicity": md.AccrualPeriodicity,
+		"identifier":         md.Identifier,
+		"language":           strings.Join(md.Language, ","),
+		"readmeURL":          md.ReadmeURL,
+	}
+	if md.License != nil {
+		extras["licenseURL"] = md.License.URL
+	}
+	for _, key := range ckanExtraKeys {
+		if extras[key] != "" {
+			pkg.Extras = append(pkg.Extras, CKANExtra{Key: key, Value: extras[key]})
+		}
+	}
+
+	bodyURL := md.DownloadURL
+	if bodyURL == "" {
+		bodyURL = ds.BodyPath
+	}
+	if bodyURL != "" {
+		res := CKANResource{URL: bodyURL, Name: "body"}
+		if ds.Structure != nil {
+			res.Format = strings.ToUpper(ds.Structure.Format)
+		}
+		pkg.Resources = append(pkg.Resources, res)
+	}
+
+	return pkg, nil
+}
+
+// FromCKANPackage maps a CKAN package to a dataset. The first resource is
+// used as the dataset body download url
+func FromCKANPackage(pkg *CKANPackage) *dataset.Dataset {
+	md := &dataset.Meta{
+		Title:       pkg.Title,
+		Description: pkg.Notes,
+		HomeURL:     pkg.URL,
+		Version:     pkg.Version,
+		Identifier:  pkg.ID,
+	}
+	if pkg.LicenseID != "" {
+		md.License = &dataset.License{Type: pkg.LicenseID}
+	}
+	if pkg.Author != "" || pkg.AuthorEmail != "" {
+		md.Contributors = append(md.Contributors, &dataset.User{Fullname: pkg.Author, Email: pkg.AuthorEmail})
+	}
+	if pkg.Maintainer != "" || pkg.MaintainerEmail != "" {
+		md.Contributors = append(md.Contributors, &dataset.User{Fullname: pkg.Maintainer, Email: pkg.MaintainerEmail})
+	}
+	for _, t := range pkg.Tags {
+		md.Keywords = append(md.Keywords, t.Name)
+	}
+	for _, g := range pkg.Groups {
+		md.Theme = append(md.Theme, g.Name)
+	}
+
+	for _, e := range pkg.Extras {
+		switch e.Key {
+		case "accessURL":
+			md.AccessURL = e.Value
+		case "accrualPeriodicity":
+			md.AccrualPeriodicity = e.Value
+		case "identifier":
+			md.Identifier = e.Value
+		case "language":
+			md.Language = strings.Split(e.Value, ",")
+		case "licenseURL":
+			if md.License == nil {
+				md.License = &dataset.License{}
+			}
+			md.License.URL = e.Value
+		case "readmeURL":
+			md.ReadmeURL = e.Value
+		default:
+			md.SetArbitrary(e.Key, e.Value)
+		}
+	}
+
+	if len(pkg.Resources) > 0 {
+		md.DownloadURL = pkg.Resources[0].URL
+	}
+
+	ds := &dataset.Dataset{
+		Name: pkg.Name,
+		Meta: md,
+	}
+	if len(pkg.Resources) > 0 && pkg.Resources[0].Format != "" {
+		ds.Structure = &dataset.Structure{Format: strings.ToLower(pkg.Resources[0].Format)}
+	}
+	return ds
+}
+
+// CKANClient calls the action API of a CKAN portal
+type CKANClient struct {
+	// BaseURL of the portal, eg: https://demo.ckan.org
+	BaseURL string
+	// APIKey authorizes write actions
+	APIKey string
+	// Client to make requests with. default is http.DefaultClient
+	Client *http.Client
+}
+
+// GetPackage fetches a package by name or id
+func (c *CKANClient) GetPackage(id string) (*CKANPackage, error) {
+	pkg := &CKANPackage{}
+	err := c.call("GET", "package_show?id="+url.QueryEscape(id), nil, pkg)
+	return pkg, err
+}
+
+// CreatePackage publishes a new package, returning the package as stored
+func (c *CKANClient) CreatePackage(pkg *CKANPackage) (*CKANPackage, error) {
+	res := &CKANPackage{}
+	err := c.call("POST", "package_create", pkg, res)
+	return res, err
+}
+
+// UpdatePackage replaces an existing package, returning the package as stored
+func (c *CKANClient) UpdatePackage(pkg *CKANPackage) (*CKANPackage, error) {
+	res := &CKANPackage{}
+	err := c.call("POST", "package_update", pkg, res)
+	return res, err
+}
+
+// call performs a CKAN action, decoding the result field of the response
+func (c *CKANClient) call(method, action string, body, result interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.BaseURL, "/")+"/api/3/action/"+action, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", c.APIKey)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("calling CKAN %s: %s", action, err.Error())
+	}
+	defer res.Body.Close()
+
+	env := struct {
+		Success bool            `json:"success"`
+		Result  json.RawMessage `json:"result"`
+		Error   struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&env); err != nil {
+		return fmt.Errorf("decoding CKAN %s response (%s): %s", action, res.Status, err.Error())
+	}
+	if !env.Success {
+		return fmt.Errorf("CKAN %s failed: %s", action, env.Error.Message)
+	}
+	return json.Unmarshal(env.Result, result)
+}
@@ -0,0 +1,112 @@
+package dsutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestCKANPackage(t *testing.T) {
+	ds := &dataset.Dataset{
+		Name: "City Budget 2018",
+		Meta: &dataset.Meta{
+			Title:              "City Budget",
+			Description:        "line items",
+			HomeURL:            "https://city.gov",
+			DownloadURL:        "https://city.gov/budget.csv",
+			AccrualPeriodicity: "R/P1Y",
+			Keywords:           []string{"budget", "finance"},
+			Theme:              []string{"Government Spending"},
+			License:            &dataset.License{Type: "cc-by", URL: "https://creativecommons.org/licenses/by/4.0/"},
+			Contributors:       []*dataset.User{{Fullname: "Clerk", Email: "clerk@city.gov"}},
+		},
+		Structure: &dataset.Structure{Format: "csv"},
+	}
+
+	pkg, err := ToCKANPackage(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Name != "city-budget-2018" {
+		t.Errorf("name mismatch. got: %s", pkg.Name)
+	}
+	if len(pkg.Resources) != 1 || pkg.Resources[0].Format != "CSV" || pkg.Resources[0].URL != ds.Meta.DownloadURL {
+		t.Errorf("unexpected resources: %v", pkg.Resources)
+	}
+	if len(pkg.Groups) != 1 || pkg.Groups[0].Name != "government-spending" {
+		t.Errorf("unexpected groups: %v", pkg.Groups)
+	}
+
+	got := FromCKANPackage(pkg)
+	if got.Name != pkg.Name {
+		t.Errorf("name mismatch. got: %s", got.Name)
+	}
+	if got.Structure.Format != "csv" {
+		t.Errorf("format mismatch. got: %s", got.Structure.Format)
+	}
+	md := got.Meta
+	if md.Title != ds.Meta.Title || md.Description != ds.Meta.Description || md.DownloadURL != ds.Meta.DownloadURL ||
+		md.AccrualPeriodicity != ds.Meta.AccrualPeriodicity || !reflect.DeepEqual(md.Keywords, ds.Meta.Keywords) ||
+		!reflect.DeepEqual(md.License, ds.Meta.License) || md.Contributors[0].Email != "clerk@city.gov" {
+		t.Errorf("meta round trip mismatch. got: %#v", md)
+	}
+
+	if _, err := ToCKANPackage(&dataset.Dataset{}); err == nil {
+		t.Error("expected dataset without name or title to error")
+	}
+}
+
+func TestCKANClient(t *testing.T) {
+	var created *CKANPackage
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/3/action/package_create":
+			if r.Header.Get("Authorization") != "key" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"success":false,"error":{"message":"Authorization Error"}}`))
+				return
+			}
+			created = &CKANPackage{}
+			json.NewDecoder(r.Body).Decode(created)
+			created.ID = "abc"
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "result": created})
+		case "/api/3/action/package_show":
+			if created == nil || r.URL.Query().Get("id") != created.Name {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"success":false,"error":{"message":"Not found"}}`))
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "result": created})
+		}
+	}))
+	defer s.Close()
+
+	c := &CKANClient{BaseURL: s.URL}
+	if _, err := c.CreatePackage(&CKANPackage{Name: "budget"}); err == nil {
+		t.Error("expected unauthorized create to error")
+	}
+
+	c.APIKey = "key"
+	pkg, err := c.CreatePackage(&CKANPackage{Name: "budget", Title: "Budget"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.ID != "abc" {
+		t.Errorf("expected created package id. got: %q", pkg.ID)
+	}
+
+	pkg, err = c.GetPackage("budget")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Title != "Budget" {
+		t.Errorf("title mismatch. got: %s", pkg.Title)
+	}
+	if _, err := c.GetPackage("missing"); err == nil {
+		t.Error("expected missing package to error")
+	}
+}
@@ -53,5 +53,34 @@ func WriteDir(store cafs.Filestore, ds *dataset.Dataset, path string) error {
 		return err
 	}
 
+	for _, name := range namedBodyNames(ds) {
+		if err := writeNamedBody(store, ds, name, path); err != nil {
+			log.Debug(err.Error())
+			return err
+		}
+	}
+
 	return nil
 }
+
+// writeNamedBody copies a named body into a directory
+func writeNamedBody(store cafs.Filestore, ds *dataset.Dataset, name, dir string) error {
+	path := filepath.Join(dir, filepath.FromSlash(namedBodyFilepath(name, ds.Bodies[name])))
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	src, err := dsfs.LoadNamedBody(store, ds, name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,253 @@
+package dsutil
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+// ErrFetchNotResumable is returned by reads from a fetched body that fail
+// partway through when the rest of the body can't be requested: the server
+// didn't honor the Range request, or the body changed since it was first
+// requested. The bytes already read can't be trusted to be part of the same
+// body, so fetching must restart from the beginning
+var ErrFetchNotResumable = errors.New("fetched body can't be resumed")
+
+// FetchConfig encapsulates configuration for fetching a body over HTTP
+type FetchConfig struct {
+	// Client to make requests with. default is http.DefaultClient
+	Client *http.Client
+	// MaxRetries is the number of times a failed request, or a response body
+	// that fails mid-read, will be retried. default 3, negative values are
+	// treated as zero
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubling with each
+	// subsequent attempt. default 500ms
+	Backoff time.Duration
+	// ETag from a previous fetch, sent as If-None-Match
+	ETag string
+	// LastModified from a previous fetch, sent as If-Modified-Since
+	LastModified string
+}
+
+// FetchInfo is metadata about a fetched body, suitable for recording the
+// provenance of imported data
+type FetchInfo struct {
+	URL             string
+	StatusCode      int
+	ETag            string
+	LastModified    string
+	ContentType     string
+	ContentEncoding string
+	ContentLength   int64
+	// NotModified is true when a conditional request matched the cached
+	// ETag or LastModified. The returned body will be empty
+	NotModified bool
+	// Attempts is the number of requests made, including resumed reads
+	Attempts  int
+	FetchedAt time.Time
+}
+
+// FetchURL returns the HTTP location of a dataset body, using BodyPath if it's
+// a url & falling back to Meta.DownloadURL
+func FetchURL(ds *dataset.Dataset) (string, error) {
+	if strings.HasPrefix(ds.BodyPath, "http://") || strings.HasPrefix(ds.BodyPath, "https://") {
+		return ds.BodyPath, nil
+	}
+	if ds.Meta != nil && ds.Meta.DownloadURL != "" {
+		return ds.Meta.DownloadURL, nil
+	}
+	return "", fmt.Errorf("dataset has no body url")
+}
+
+// FetchBody fetches a dataset's body over HTTP. See FetchURL for how the
+// location is resolved
+func FetchBody(ds *dataset.Dataset, configs ...func(cfg *FetchConfig)) (io.ReadCloser, *FetchInfo, error) {
+	url, err := FetchURL(ds)
+	if err != nil {
+		return nil, nil, err
+	}
+	return Fetch(url, configs...)
+}
+
+// Fetch requests url, retrying failures with exponential backoff. Reads that
+// fail partway through the response are resumed with Range requests, or fail
+// with ErrFetchNotResumable if the server can't send the rest of the same
+// body. gzip & deflate content encodings are decoded, the returned reader
+// yields decoded bytes
+func Fetch(url string, configs ...func(cfg *FetchConfig)) (io.ReadCloser, *FetchInfo, error) {
+	cfg := &FetchConfig{
+		Client:     http.DefaultClient,
+		MaxRetries: 3,
+		Backoff:    time.Millisecond * 500,
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+
+	f := &fetcher{
+		cfg:  cfg,
+		info: &FetchInfo{URL: url},
+	}
+	res, err := f.request(0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f.info.StatusCode = res.StatusCode
+	f.info.FetchedAt = time.Now()
+	if res.StatusCode == http.StatusNotModified {
+		res.Body.Close()
+		f.info.NotModified = true
+		f.info.ETag = cfg.ETag
+		f.info.LastModified = cfg.LastModified
+		return ioutil.NopCloser(strings.NewReader("")), f.info, nil
+	}
+
+	f.info.ETag = res.Header.Get("ETag")
+	f.info.LastModified = res.Header.Get("Last-Modified")
+	f.info.ContentType = res.Header.Get("Content-Type")
+	f.info.ContentEncoding = res.Header.Get("Content-Encoding")
+	f.info.ContentLength = res.ContentLength
+	f.body = res.Body
+
+	var rdr io.ReadCloser = f
+	switch strings.ToLower(f.info.ContentEncoding) {
+	case "gzip", "x-gzip":
+		gzr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("reading gzip body: %s", err.Error())
+		}
+		rdr = &decodedBody{Reader: gzr, raw: f}
+	case "deflate":
+		rdr = &decodedBody{Reader: flate.NewReader(f), raw: f}
+	}
+
+	return rdr, f.info, nil
+}
+
+// fetcher reads a response body, resuming from the last byte read when a read
+// fails
+type fetcher struct {
+	cfg     *FetchConfig
+	info    *FetchInfo
+	body    io.ReadCloser
+	read    int64
+	retries int
+}
+
+// request makes a GET request, retrying connection errors & retryable status
+// codes. A non-zero offset requests the remainder of the body from offset
+func (f *fetcher) request(offset int64) (*http.Response, error) {
+	var lastErr error
+	for ; f.retries <= f.cfg.MaxRetries; f.retries++ {
+		if lastErr != nil {
+			time.Sleep(f.cfg.Backoff << uint(f.retries-1))
+		}
+		f.info.Attempts++
+
+		req, err := http.NewRequest("GET", f.info.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			if f.info.ETag != "" {
+				req.Header.Set("If-Range", f.info.ETag)
+			}
+		} else {
+			if f.cfg.ETag != "" {
+				req.Header.Set("If-None-Match", f.cfg.ETag)
+			}
+			if f.cfg.LastModified != "" {
+				req.Header.Set("If-Modified-Since", f.cfg.LastModified)
+			}
+		}
+
+		res, err := f.cfg.Client.Do(req)
+		if err != nil {
+			log.Debug(err.Error())
+			lastErr = err
+			continue
+		}
+		if res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests {
+			res.Body.Close()
+			lastErr = fmt.Errorf("server responded with status: %s", res.Status)
+			continue
+		}
+		if res.StatusCode >= 400 {
+			res.Body.Close()
+			return nil, fmt.Errorf("fetching %s: %s", f.info.URL, res.Status)
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("fetching %s failed after %d attempts: %s", f.info.URL, f.info.Attempts, lastErr.Error())
+}
+
+// resume re-requests the body from the current read position
+func (f *fetcher) resume() error {
+	f.body.Close()
+	f.retries++
+	res, err := f.request(f.read)
+	if err != nil {
+		return err
+	}
+	f.body = res.Body
+
+	// a complete body is sent when the server ignores the range header, or
+	// the ETag sent with If-Range no longer matches. Either way the new body
+	// may not start with the bytes already read
+	if res.StatusCode != http.StatusPartialContent {
+		if f.info.ETag != "" {
+			return fmt.Errorf("%w: %s changed since it was first requested", ErrFetchNotResumable, f.info.URL)
+		}
+		return fmt.Errorf("%w: %s responded to a range request with status: %s", ErrFetchNotResumable, f.info.URL, res.Status)
+	}
+	return nil
+}
+
+// Read implements the io.Reader interface
+func (f *fetcher) Read(p []byte) (int, error) {
+	n, err := f.body.Read(p)
+	f.read += int64(n)
+	if err != nil && err != io.EOF {
+		log.Debug(err.Error())
+		if f.retries >= f.cfg.MaxRetries {
+			return n, err
+		}
+		if rerr := f.resume(); rerr != nil {
+			return n, rerr
+		}
+		return n, nil
+	}
+	return n, err
+}
+
+// Close closes the response body
+func (f *fetcher) Close() error {
+	return f.body.Close()
+}
+
+// decodedBody wraps a decompressing reader, closing the raw body on Close
+type decodedBody struct {
+	io.Reader
+	raw io.Closer
+}
+
+// Close closes the underlying response body
+func (d *decodedBody) Close() error {
+	return d.raw.Close()
+}
@@ -0,0 +1,177 @@
+package dsutil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestFetch(t *testing.T) {
+	body := "a,b,c\n1,2,3\n4,5,6\n"
+	requests := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/flaky":
+			if requests == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Write([]byte(body))
+		case "/gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte(body))
+			gz.Close()
+		case "/partial":
+			w.Header().Set("ETag", `"v1"`)
+			if rng := r.Header.Get("Range"); rng != "" {
+				var start int
+				fmt.Sscanf(rng, "bytes=%d-", &start)
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(body)-1, len(body)))
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write([]byte(body[start:]))
+				return
+			}
+			// promise the full body, send half, then drop the connection
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(body)))
+			w.Write([]byte(body[:5]))
+			w.(http.Flusher).Flush()
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	fast := func(cfg *FetchConfig) { cfg.Backoff = time.Millisecond }
+
+	rdr, info, err := Fetch(s.URL+"/flaky", fast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadAll(rdr)
+	rdr.Close()
+	if string(data) != body {
+		t.Errorf("body mismatch. got: %q", data)
+	}
+	if info.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got: %d", info.Attempts)
+	}
+
+	_, info, err = Fetch(s.URL+"/flaky", fast, func(cfg *FetchConfig) { cfg.ETag = info.ETag })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.NotModified {
+		t.Error("expected conditional request to report not modified")
+	}
+
+	rdr, info, err = Fetch(s.URL+"/gzip", fast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = ioutil.ReadAll(rdr)
+	if string(data) != body {
+		t.Errorf("gzip body mismatch. got: %q", data)
+	}
+	if info.ContentEncoding != "gzip" {
+		t.Errorf("expected gzip content encoding, got: %q", info.ContentEncoding)
+	}
+
+	rdr, info, err = Fetch(s.URL+"/partial", fast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = ioutil.ReadAll(rdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != body {
+		t.Errorf("resumed body mismatch. got: %q", data)
+	}
+
+	if _, _, err := Fetch(s.URL+"/missing", fast); err == nil {
+		t.Error("expected not found to error")
+	}
+
+	ds := &dataset.Dataset{Meta: &dataset.Meta{DownloadURL: s.URL + "/gzip"}}
+	rdr, _, err = FetchBody(ds, fast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = ioutil.ReadAll(rdr)
+	if !bytes.Equal(data, []byte(body)) {
+		t.Errorf("FetchBody mismatch. got: %q", data)
+	}
+
+	if _, _, err := FetchBody(&dataset.Dataset{BodyPath: "/ipfs/QmFoo"}); err == nil || !strings.Contains(err.Error(), "no body url") {
+		t.Errorf("expected missing url error, got: %v", err)
+	}
+}
+
+func TestFetchNotResumable(t *testing.T) {
+	body := "a,b,c\n1,2,3\n4,5,6\n"
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/changed":
+			if r.Header.Get("Range") != "" {
+				// If-Range no longer matches, the new version is sent whole
+				w.Header().Set("ETag", `"v2"`)
+				w.Write([]byte(strings.ToUpper(body)))
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+		case "/unranged":
+			if r.Header.Get("Range") != "" {
+				w.Write([]byte(body))
+				return
+			}
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(body)))
+		w.Write([]byte(body[:5]))
+		w.(http.Flusher).Flush()
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer s.Close()
+
+	fast := func(cfg *FetchConfig) { cfg.Backoff = time.Millisecond }
+
+	for _, path := range []string{"/changed", "/unranged"} {
+		rdr, _, err := Fetch(s.URL+path, fast)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ioutil.ReadAll(rdr); !errors.Is(err, ErrFetchNotResumable) {
+			t.Errorf("%s: expected ErrFetchNotResumable, got: %v", path, err)
+		}
+		rdr.Close()
+	}
+
+	_, info, err := Fetch(s.URL+"/unavailable", fast, func(cfg *FetchConfig) { cfg.MaxRetries = -1 })
+	if err == nil {
+		t.Error("expected unavailable server to error")
+	}
+	if info != nil {
+		t.Errorf("expected nil info, got: %v", info)
+	}
+}
@@ -0,0 +1,387 @@
+package dsutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// DatasetFS is a read-only fs.FS of a stored dataset's components, laid out
+// like a zip archive from WriteZipArchive:
+//
+//	dataset.json          the dataset document
+//	body.<format>         the body
+//	bodies/<name>.<fmt>   named bodies
+//	transform.star        the transform script
+//	viz.html              the viz template
+//	index.html            the rendered viz
+//
+// Files stream from the store when read. Seeking backwards, as
+// http.FileServer does after sniffing content types, holds small files in
+// memory. File sizes come from structure lengths where they're recorded &
+// are measured by reading otherwise. File modification times are the commit
+// timestamp
+type DatasetFS struct {
+	store   cafs.Filestore
+	modTime time.Time
+	// files maps file paths to functions that fetch the file's contents
+	files map[string]func() (qfs.File, error)
+	// dirs maps directory paths to the sorted names of their entries
+	dirs map[string][]string
+
+	mu    sync.Mutex
+	sizes map[string]int64
+}
+
+var _ fs.FS = (*DatasetFS)(nil)
+
+// NewDatasetFS creates a filesystem of a dataset's components. ds must be
+// loaded from store, with component references resolved
+func NewDatasetFS(store cafs.Filestore, ds *dataset.Dataset) (*DatasetFS, error) {
+	if ds.Structure == nil {
+		return nil, fmt.Errorf("dataset has no structure")
+	}
+	dsdata, err := json.MarshalIndent(ds, "", "  ")
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, err
+	}
+
+	fsys := &DatasetFS{
+		store: store,
+		files: map[string]func() (qfs.File, error){},
+		dirs:  map[string][]string{},
+		sizes: map[string]int64{},
+	}
+	if ds.Commit != nil {
+		fsys.modTime = ds.Commit.Timestamp
+	}
+
+	fsys.add(dsfs.PackageFileDataset.String(), func() (qfs.File, error) {
+		return qfs.NewMemfileBytes(dsfs.PackageFileDataset.String(), dsdata), nil
+	})
+	fsys.sizes[dsfs.PackageFileDataset.String()] = int64(len(dsdata))
+	if ds.BodyPath != "" {
+		name := fmt.Sprintf("body.%s", ds.Structure.Format)
+		fsys.add(name, func() (qfs.File, error) {
+			return dsfs.LoadBody(store, ds)
+		})
+		if ds.Structure.Length > 0 {
+			fsys.sizes[name] = int64(ds.Structure.Length)
+		}
+	}
+	for _, name := range namedBodyNames(ds) {
+		name, nb := name, ds.Bodies[name]
+		bodyPath := namedBodyFilepath(name, nb)
+		fsys.add(bodyPath, func() (qfs.File, error) {
+			return dsfs.LoadNamedBody(store, ds, name)
+		})
+		if nb.Structure.Length > 0 {
+			fsys.sizes[bodyPath] = int64(nb.Structure.Length)
+		}
+	}
+	if ds.Transform != nil && ds.Transform.ScriptPath != "" {
+		fsys.addPath("transform.star", ds.Transform.ScriptPath)
+	}
+	if ds.Viz != nil {
+		if ds.Viz.ScriptPath != "" {
+			fsys.addPath("viz.html", ds.Viz.ScriptPath)
+		}
+		if ds.Viz.RenderedPath != "" {
+			fsys.addPath("index.html", ds.Viz.RenderedPath)
+		}
+	}
+
+	for _, names := range fsys.dirs {
+		sort.Strings(names)
+	}
+	return fsys, nil
+}
+
+// add registers a file & the directories that lead to it
+func (fsys *DatasetFS) add(name string, open func() (qfs.File, error)) {
+	fsys.files[name] = open
+	for {
+		dir := path.Dir(name)
+		if _, ok := fsys.dirs[dir]; ok {
+			fsys.dirs[dir] = append(fsys.dirs[dir], path.Base(name))
+			return
+		}
+		fsys.dirs[dir] = []string{path.Base(name)}
+		if dir == "." {
+			return
+		}
+		name = dir
+	}
+}
+
+// addPath registers a file read from a store path
+func (fsys *DatasetFS) addPath(name, storePath string) {
+	fsys.add(name, func() (qfs.File, error) {
+		return fsys.store.Get(storePath)
+	})
+}
+
+// Open implements the fs.FS interface
+func (fsys *DatasetFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if names, ok := fsys.dirs[name]; ok {
+		return &datasetDir{fsys: fsys, name: name, names: names}, nil
+	}
+
+	open, ok := fsys.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	f, err := open()
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &datasetFile{fsys: fsys, name: name, open: open, file: f}, nil
+}
+
+// size gives the length of a file, reading the file to measure it the first
+// time the length isn't known
+func (fsys *DatasetFS) size(name string) (int64, error) {
+	fsys.mu.Lock()
+	size, ok := fsys.sizes[name]
+	fsys.mu.Unlock()
+	if ok {
+		return size, nil
+	}
+
+	open, ok := fsys.files[name]
+	if !ok {
+		return 0, fs.ErrNotExist
+	}
+	f, err := open()
+	if err != nil {
+		log.Debug(err.Error())
+		return 0, err
+	}
+	size, err = io.Copy(ioutil.Discard, f)
+	f.Close()
+	if err != nil {
+		log.Debug(err.Error())
+		return 0, err
+	}
+	fsys.setSize(name, size)
+	return size, nil
+}
+
+// setSize records the length of a file
+func (fsys *DatasetFS) setSize(name string, size int64) {
+	fsys.mu.Lock()
+	fsys.sizes[name] = size
+	fsys.mu.Unlock()
+}
+
+// stat gives the info of a directory entry
+func (fsys *DatasetFS) stat(name string) (fs.FileInfo, error) {
+	if _, ok := fsys.dirs[name]; ok {
+		return &datasetFileInfo{name: path.Base(name), dir: true, modTime: fsys.modTime}, nil
+	}
+	size, err := fsys.size(name)
+	if err != nil {
+		return nil, err
+	}
+	return &datasetFileInfo{name: path.Base(name), size: size, modTime: fsys.modTime}, nil
+}
+
+// maxSeekBufferSize is the largest file a datasetFile reads into memory to
+// seek backwards in. larger files are fetched again & read up to the offset
+const maxSeekBufferSize = 32 << 20
+
+// datasetFile is an open file of a DatasetFS. Reads stream from the store,
+// seeking only reads ahead until a seek moves backwards
+type datasetFile struct {
+	fsys *DatasetFS
+	name string
+	open func() (qfs.File, error)
+	// file is the stream read from, read is the number of bytes read from it
+	file qfs.File
+	read int64
+	// offset is where the next read starts, seeks only move the offset
+	offset int64
+	// buf holds the whole file once a seek has needed it
+	buf *bytes.Reader
+}
+
+// Read implements the fs.File interface
+func (f *datasetFile) Read(p []byte) (int, error) {
+	if f.buf != nil {
+		return f.buf.Read(p)
+	}
+	if f.offset != f.read {
+		if err := f.move(); err != nil {
+			return 0, err
+		}
+		if f.buf != nil {
+			return f.buf.Read(p)
+		}
+	}
+	n, err := f.file.Read(p)
+	f.read += int64(n)
+	f.offset = f.read
+	if errors.Is(err, io.EOF) {
+		f.fsys.setSize(f.name, f.read)
+	}
+	return n, err
+}
+
+// Seek implements the io.Seeker interface
+func (f *datasetFile) Seek(offset int64, whence int) (int64, error) {
+	if f.buf != nil {
+		return f.buf.Seek(offset, whence)
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		size, err := f.fsys.size(f.name)
+		if err != nil {
+			return 0, &fs.PathError{Op: "seek", Path: f.name, Err: err}
+		}
+		offset += size
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fmt.Errorf("invalid whence: %d", whence)}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fmt.Errorf("negative position")}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// move brings the stream to the offset. moving forward discards bytes,
+// moving backward buffers small files & fetches large ones again
+func (f *datasetFile) move() error {
+	if f.offset < f.read {
+		size, err := f.fsys.size(f.name)
+		if err != nil {
+			return &fs.PathError{Op: "read", Path: f.name, Err: err}
+		}
+		file, err := f.open()
+		if err != nil {
+			log.Debug(err.Error())
+			return &fs.PathError{Op: "read", Path: f.name, Err: err}
+		}
+		f.file.Close()
+		f.file, f.read = file, 0
+
+		if size <= maxSeekBufferSize {
+			data, err := ioutil.ReadAll(f.file)
+			if err != nil {
+				log.Debug(err.Error())
+				return &fs.PathError{Op: "read", Path: f.name, Err: err}
+			}
+			f.buf = bytes.NewReader(data)
+			_, err = f.buf.Seek(f.offset, io.SeekStart)
+			return err
+		}
+	}
+	n, err := io.CopyN(ioutil.Discard, f.file, f.offset-f.read)
+	f.read += n
+	if errors.Is(err, io.EOF) {
+		// reading past the end leaves reads at the end
+		f.offset = f.read
+		return nil
+	}
+	return err
+}
+
+// Stat implements the fs.File interface
+func (f *datasetFile) Stat() (fs.FileInfo, error) {
+	return f.fsys.stat(f.name)
+}
+
+// Close implements the fs.File interface
+func (f *datasetFile) Close() error {
+	return f.file.Close()
+}
+
+// datasetDir is an open directory of a DatasetFS
+type datasetDir struct {
+	fsys  *DatasetFS
+	name  string
+	names []string
+	// read is the number of entries ReadDir has given
+	read int
+}
+
+// Stat implements the fs.File interface
+func (d *datasetDir) Stat() (fs.FileInfo, error) {
+	return d.fsys.stat(d.name)
+}
+
+// Read implements the fs.File interface, reading a directory is an error
+func (d *datasetDir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fmt.Errorf("is a directory")}
+}
+
+// Close implements the fs.File interface
+func (d *datasetDir) Close() error {
+	return nil
+}
+
+// ReadDir implements the fs.ReadDirFile interface
+func (d *datasetDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	names := d.names[d.read:]
+	if n > 0 {
+		if len(names) == 0 {
+			return nil, io.EOF
+		}
+		if len(names) > n {
+			names = names[:n]
+		}
+	}
+	entries := make([]fs.DirEntry, len(names))
+	for i, name := range names {
+		info, err := d.fsys.stat(path.Join(d.name, name))
+		if err != nil {
+			return entries[:i], &fs.PathError{Op: "readdir", Path: d.name, Err: err}
+		}
+		entries[i] = fs.FileInfoToDirEntry(info)
+		d.read++
+	}
+	return entries, nil
+}
+
+// datasetFileInfo describes a file or directory of a DatasetFS
+type datasetFileInfo struct {
+	name    string
+	size    int64
+	dir     bool
+	modTime time.Time
+}
+
+func (fi *datasetFileInfo) Name() string       { return fi.name }
+func (fi *datasetFileInfo) Size() int64        { return fi.size }
+func (fi *datasetFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *datasetFileInfo) IsDir() bool        { return fi.dir }
+func (fi *datasetFileInfo) Sys() interface{}   { return nil }
+
+// Mode gives read-only permissions
+func (fi *datasetFileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
@@ -0,0 +1,137 @@
+package dsutil
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qfs"
+)
+
+func TestDatasetFS(t *testing.T) {
+	store, names, err := testStoreWithVizAndTransform()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := dsfs.LoadDataset(store, names["movies"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys, err := NewDatasetFS(store, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fstest.TestFS(fsys, "dataset.json", "body.csv", "transform.star", "viz.html", "index.html"); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{
+		"body.csv":       "movie\nup\nthe incredibles",
+		"transform.star": "def transform(ds):\nreturn ds\n",
+		"viz.html":       "<html>template</html>\n",
+	}
+	for name, content := range expect {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s content mismatch. expected: %q, got: %q", name, content, string(data))
+		}
+	}
+
+	ents, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ents) != 5 {
+		t.Errorf("expected 5 root entries, got: %d", len(ents))
+	}
+
+	f, err := fsys.Open("body.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != int64(ds.Structure.Length) {
+		t.Errorf("body size mismatch. expected: %d, got: %d", ds.Structure.Length, info.Size())
+	}
+	if _, err := ioutil.ReadAll(f); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.(io.Seeker).Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadAll(f); err != nil || string(data) != "up\nthe incredibles" {
+		t.Errorf("expected to read from the seek offset, got: %q %v", string(data), err)
+	}
+	f.Close()
+
+	if _, err := fsys.Open("readme.md"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, got: %v", err)
+	}
+	if _, err := fsys.Open("/dataset.json"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("expected invalid path error, got: %v", err)
+	}
+
+	s := httptest.NewServer(http.FileServer(http.FS(fsys)))
+	defer s.Close()
+	res, err := http.Get(s.URL + "/body.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK || string(data) != expect["body.csv"] {
+		t.Errorf("expected body.csv to be served, got: %d %q", res.StatusCode, string(data))
+	}
+}
+
+func TestDatasetFSNamedBodies(t *testing.T) {
+	store, names, err := testStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := dsfs.LoadDataset(store, names["movies"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	codebook := &dataset.NamedBody{Structure: &dataset.Structure{Format: "csv", Schema: dataset.BaseSchemaArray}}
+	if codebook.Path, err = store.Put(qfs.NewMemfileBytes("codebook.csv", []byte("a,b\n")), false); err != nil {
+		t.Fatal(err)
+	}
+	ds.Bodies = map[string]*dataset.NamedBody{"codebook": codebook}
+
+	fsys, err := NewDatasetFS(store, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fstest.TestFS(fsys, "dataset.json", "body.csv", "bodies/codebook.csv"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := fs.ReadFile(fsys, "bodies/codebook.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "a,b\n" {
+		t.Errorf("codebook content mismatch, got: %q", string(data))
+	}
+
+	if _, err := NewDatasetFS(store, &dataset.Dataset{}); err == nil {
+		t.Error("expected an error for a dataset without a structure")
+	}
+}
@@ -0,0 +1,311 @@
+package dsutil
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qri-io/qfs"
+)
+
+// ObjectStoreConfig encapsulates credentials & endpoints for object storage
+type ObjectStoreConfig struct {
+	// Client to make requests with. default is http.DefaultClient
+	Client *http.Client
+
+	// AWS credentials for s3:// urls. default region is us-east-1
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+	AWSRegion          string
+	// S3Endpoint overrides the default AWS endpoint, for s3-compatible stores.
+	// requests to a custom endpoint use path-style addressing
+	S3Endpoint string
+
+	// GCSToken is an OAuth2 access token for gs:// urls
+	GCSToken string
+	// GCSEndpoint overrides the default https://storage.googleapis.com
+	GCSEndpoint string
+}
+
+// ObjectStoreEnv sets credentials & endpoints from environment variables,
+// leaving fields unchanged for unset variables. AWS values are read from the
+// standard AWS variables, gs:// urls use the access token in
+// GOOGLE_OAUTH_ACCESS_TOKEN & the endpoint in STORAGE_EMULATOR_HOST
+func ObjectStoreEnv(cfg *ObjectStoreConfig) {
+	env := func(field *string, names ...string) {
+		for _, name := range names {
+			if v := os.Getenv(name); v != "" {
+				*field = v
+				return
+			}
+		}
+	}
+	env(&cfg.AWSAccessKeyID, "AWS_ACCESS_KEY_ID")
+	env(&cfg.AWSSecretAccessKey, "AWS_SECRET_ACCESS_KEY")
+	env(&cfg.AWSSessionToken, "AWS_SESSION_TOKEN")
+	env(&cfg.AWSRegion, "AWS_REGION", "AWS_DEFAULT_REGION")
+	env(&cfg.S3Endpoint, "AWS_ENDPOINT_URL_S3")
+	env(&cfg.GCSToken, "GOOGLE_OAUTH_ACCESS_TOKEN")
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		if !strings.HasPrefix(host, "http") {
+			host = "http://" + host
+		}
+		cfg.GCSEndpoint = host
+	}
+}
+
+// ObjectStore streams bodies to & from cloud object storage, addressed by
+// s3://bucket/key and gs://bucket/key urls. Objects are read with GetObject &
+// written with PutObject. ObjectStore isn't a filestore: it implements the
+// read-only qfs.PathResolver interface, so object urls can be read wherever
+// paths are resolved, and returned readers can be passed directly to detect
+// for importing
+type ObjectStore struct {
+	cfg *ObjectStoreConfig
+}
+
+var _ qfs.PathResolver = (*ObjectStore)(nil)
+
+// NewObjectStore creates an ObjectStore, configured by config funcs like
+// ObjectStoreEnv
+func NewObjectStore(configs ...func(cfg *ObjectStoreConfig)) *ObjectStore {
+	cfg := &ObjectStoreConfig{
+		Client:    http.DefaultClient,
+		AWSRegion: "us-east-1",
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	return &ObjectStore{cfg: cfg}
+}
+
+// NewObjectStoreFromEnv creates an ObjectStore with credentials read from
+// environment variables, see ObjectStoreEnv
+func NewObjectStoreFromEnv() *ObjectStore {
+	return NewObjectStore(ObjectStoreEnv)
+}
+
+// IsObjectStoreURL returns true for s3:// & gs:// urls
+func IsObjectStoreURL(url string) bool {
+	return strings.HasPrefix(url, "s3://") || strings.HasPrefix(url, "gs://")
+}
+
+// GetObject opens a stream to the object at url
+func (s *ObjectStore) GetObject(url string) (io.ReadCloser, error) {
+	req, err := s.request("GET", url, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client().Do(req)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil, fmt.Errorf("getting %s: %s", url, err.Error())
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("getting %s: %s", url, res.Status)
+	}
+	return res.Body, nil
+}
+
+// PutObject streams r to the object at url. Object stores require a content
+// length up front, if size is negative r is buffered in memory to determine it
+func (s *ObjectStore) PutObject(url string, r io.Reader, size int64) error {
+	if size < 0 {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+		size = int64(len(data))
+	}
+
+	req, err := s.request("PUT", url, r, size)
+	if err != nil {
+		return err
+	}
+	res, err := s.client().Do(req)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("putting %s: %s", url, err.Error())
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return fmt.Errorf("putting %s: %s", url, res.Status)
+	}
+	return nil
+}
+
+// Get opens the object at an object store url, implementing the
+// qfs.PathResolver interface
+func (s *ObjectStore) Get(path string) (qfs.File, error) {
+	rc, err := s.GetObject(path)
+	if err != nil {
+		return nil, err
+	}
+	return qfs.NewMemfileReader(filepath.Base(path), rc), nil
+}
+
+func (s *ObjectStore) client() *http.Client {
+	if s.cfg.Client != nil {
+		return s.cfg.Client
+	}
+	return http.DefaultClient
+}
+
+// region gives the AWS region of s3:// urls
+func (s *ObjectStore) region() string {
+	if s.cfg.AWSRegion != "" {
+		return s.cfg.AWSRegion
+	}
+	return "us-east-1"
+}
+
+// splitObjectURL breaks an object store url into scheme, bucket & key
+func splitObjectURL(url string) (scheme, bucket, key string, err error) {
+	parts := strings.SplitN(url, "://", 2)
+	if len(parts) != 2 || (parts[0] != "s3" && parts[0] != "gs") {
+		return "", "", "", fmt.Errorf("unsupported object store url: %s", url)
+	}
+	path := strings.SplitN(parts[1], "/", 2)
+	if len(path) != 2 || path[0] == "" || path[1] == "" {
+		return "", "", "", fmt.Errorf("object store url must have a bucket & key: %s", url)
+	}
+	return parts[0], path[0], path[1], nil
+}
+
+// request builds an authenticated request for an object store url
+func (s *ObjectStore) request(method, url string, body io.Reader, size int64) (*http.Request, error) {
+	scheme, bucket, key, err := splitObjectURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheme == "gs" {
+		endpoint := s.cfg.GCSEndpoint
+		if endpoint == "" {
+			endpoint = "https://storage.googleapis.com"
+		}
+		req, err := http.NewRequest(method, fmt.Sprintf("%s/%s/%s", endpoint, bucket, awsURIEncode(key, false)), body)
+		if err != nil {
+			return nil, err
+		}
+		req.ContentLength = size
+		if s.cfg.GCSToken != "" {
+			req.Header.Set("Authorization", "Bearer "+s.cfg.GCSToken)
+		}
+		return req, nil
+	}
+
+	var endpoint string
+	if s.cfg.S3Endpoint != "" {
+		endpoint = fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.cfg.S3Endpoint, "/"), bucket, awsURIEncode(key, false))
+	} else {
+		endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, s.region(), awsURIEncode(key, false))
+	}
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	if s.cfg.AWSAccessKeyID != "" {
+		s.signV4(req, time.Now().UTC())
+	}
+	return req, nil
+}
+
+// signV4 adds AWS signature version 4 authorization headers to req. Payloads
+// are sent unsigned so bodies can be streamed without hashing them first
+func (s *ObjectStore) signV4(req *http.Request, t time.Time) {
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	region := s.region()
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+	if s.cfg.AWSSessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.AWSSessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k := range req.Header {
+		lk := strings.ToLower(k)
+		if strings.HasPrefix(lk, "x-amz-") {
+			headers[lk] = strings.TrimSpace(req.Header.Get(k))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := ""
+	for _, k := range names {
+		canonicalHeaders += k + ":" + headers[k] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsURIEncode(req.URL.Path, false),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, "s3", "aws4_request"}, "/")
+	reqHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(reqHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.AWSSecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AWSAccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// awsURIEncode percent-encodes s per the AWS signing rules, leaving "/"
+// unencoded unless encodeSlash is true
+func awsURIEncode(s string, encodeSlash bool) string {
+	buf := &bytes.Buffer{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~':
+			buf.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			buf.WriteByte(c)
+		default:
+			fmt.Fprintf(buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
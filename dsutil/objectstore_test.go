@@ -0,0 +1,99 @@
+package dsutil
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestObjectStore(t *testing.T) {
+	objects := map[string][]byte{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=key/") && auth != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case "PUT":
+			data, _ := ioutil.ReadAll(r.Body)
+			objects[r.URL.Path] = data
+		case "GET":
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		}
+	}))
+	defer s.Close()
+
+	store := NewObjectStore(func(cfg *ObjectStoreConfig) {
+		cfg.AWSAccessKeyID = "key"
+		cfg.AWSSecretAccessKey = "secret"
+		cfg.AWSRegion = "us-west-2"
+		cfg.S3Endpoint = s.URL
+		cfg.GCSToken = "token"
+		cfg.GCSEndpoint = s.URL
+	})
+
+	for _, url := range []string{"s3://bucket/path/body.csv", "gs://bucket/other body.csv"} {
+		if err := store.PutObject(url, strings.NewReader("a,b\n1,2\n"), -1); err != nil {
+			t.Fatalf("%s put error: %s", url, err)
+		}
+		f, err := store.Get(url)
+		if err != nil {
+			t.Fatalf("%s get error: %s", url, err)
+		}
+		data, _ := ioutil.ReadAll(f)
+		if !bytes.Equal(data, []byte("a,b\n1,2\n")) {
+			t.Errorf("%s data mismatch. got: %q", url, data)
+		}
+	}
+
+	if _, err := store.GetObject("s3://bucket/missing"); err == nil {
+		t.Error("expected missing object to error")
+	}
+	if _, err := store.GetObject("ftp://bucket/key"); err == nil {
+		t.Error("expected unsupported scheme to error")
+	}
+	if _, err := store.GetObject("s3://bucket"); err == nil {
+		t.Error("expected missing key to error")
+	}
+}
+
+func TestObjectStoreEnv(t *testing.T) {
+	for _, key := range []string{"AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_REGION", "AWS_ENDPOINT_URL_S3", "GOOGLE_OAUTH_ACCESS_TOKEN"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_DEFAULT_REGION", "eu-west-1")
+	t.Setenv("STORAGE_EMULATOR_HOST", "localhost:4443")
+
+	// config funcs after ObjectStoreEnv override the environment
+	store := NewObjectStore(ObjectStoreEnv, func(cfg *ObjectStoreConfig) {
+		cfg.AWSAccessKeyID = "configured"
+		cfg.GCSToken = "token"
+	})
+	expect := &ObjectStoreConfig{
+		Client:         http.DefaultClient,
+		AWSAccessKeyID: "configured",
+		AWSRegion:      "eu-west-1",
+		GCSToken:       "token",
+		GCSEndpoint:    "http://localhost:4443",
+	}
+	if !reflect.DeepEqual(expect, store.cfg) {
+		t.Errorf("config mismatch. expected: %+v, got: %+v", expect, store.cfg)
+	}
+
+	// unset variables keep defaults
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	if region := NewObjectStoreFromEnv().region(); region != "us-east-1" {
+		t.Errorf("expected default region, got: %s", region)
+	}
+}
@@ -0,0 +1,242 @@
+package dsutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// SocrataConfig encapsulates configuration for importing from a Socrata
+// (SODA API) portal
+type SocrataConfig struct {
+	// Client to make requests with. default is http.DefaultClient
+	Client *http.Client
+	// AppToken is sent as X-App-Token to raise request throttling limits
+	AppToken string
+	// PageSize is the number of rows requested per page. default 1000
+	PageSize int
+}
+
+type socrataView struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Category    string          `json:"category"`
+	Tags        []string        `json:"tags"`
+	License     *socrataLicense `json:"license"`
+	Columns     []socrataColumn `json:"columns"`
+}
+
+type socrataLicense struct {
+	Name      string `json:"name"`
+	TermsLink string `json:"termsLink"`
+}
+
+type socrataColumn struct {
+	Name         string `json:"name"`
+	FieldName    string `json:"fieldName"`
+	DataTypeName string `json:"dataTypeName"`
+	Description  string `json:"description"`
+}
+
+// socrataType maps a socrata column data type to a json schema type
+func socrataType(t string) string {
+	switch t {
+	case "number", "money", "percent", "double":
+		return "number"
+	case "checkbox":
+		return "boolean"
+	case "point", "location", "line", "polygon", "multipoint", "multiline", "multipolygon":
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+// SocrataReader implements the dsio.EntryReader interface, paging through the
+// rows of a socrata dataset. Each row is read as an array entry
+type SocrataReader struct {
+	cfg     *SocrataConfig
+	st      *dataset.Structure
+	rowsURL string
+	cols    []socrataColumn
+	types   []string
+	page    []map[string]interface{}
+	offset  int
+	idx     int
+	done    bool
+}
+
+var _ dsio.EntryReader = (*SocrataReader)(nil)
+
+// NewSocrataReader fetches the metadata of socrata dataset id from a portal
+// like https://data.cityofnewyork.us, returning a reader for the dataset rows
+// and a dataset with meta & structure translated from socrata metadata
+func NewSocrataReader(portal, id string, configs ...func(cfg *SocrataConfig)) (*SocrataReader, *dataset.Dataset, error) {
+	cfg := &SocrataConfig{
+		Client:   http.DefaultClient,
+		PageSize: 1000,
+	}
+	for _, config := range configs {
+		config(cfg)
+	}
+	portal = strings.TrimSuffix(portal, "/")
+
+	view := &socrataView{}
+	if err := cfg.get(fmt.Sprintf("%s/api/views/%s.json", portal, url.PathEscape(id)), view); err != nil {
+		return nil, nil, err
+	}
+
+	r := &SocrataReader{
+		cfg:     cfg,
+		rowsURL: fmt.Sprintf("%s/resource/%s.json", portal, url.PathEscape(id)),
+	}
+	items := []interface{}{}
+	for _, c := range view.Columns {
+		// skip system fields like :id & :created_at
+		if strings.HasPrefix(c.FieldName, ":") {
+			continue
+		}
+		t := socrataType(c.DataTypeName)
+		r.cols = append(r.cols, c)
+		r.types = append(r.types, t)
+		col := map[string]interface{}{"title": c.FieldName, "type": t}
+		if c.Description != "" {
+			col["description"] = c.Description
+		}
+		items = append(items, col)
+	}
+	r.st = &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": items,
+			},
+		},
+	}
+
+	md := &dataset.Meta{
+		Title:       view.Name,
+		Description: view.Description,
+		Identifier:  view.ID,
+		Keywords:    view.Tags,
+		AccessURL:   fmt.Sprintf("%s/d/%s", portal, view.ID),
+		DownloadURL: r.rowsURL,
+	}
+	if view.Category != "" {
+		md.Theme = []string{view.Category}
+	}
+	if view.License != nil {
+		md.License = &dataset.License{Type: view.License.Name, URL: view.License.TermsLink}
+	}
+
+	return r, &dataset.Dataset{Meta: md, Structure: r.st}, nil
+}
+
+// Structure gives this reader's structure
+func (r *SocrataReader) Structure() *dataset.Structure {
+	return r.st
+}
+
+// ReadEntry reads one row, fetching the next page of rows as needed
+func (r *SocrataReader) ReadEntry() (dsio.Entry, error) {
+	if len(r.page) == 0 {
+		if r.done {
+			return dsio.Entry{}, io.EOF
+		}
+		if err := r.nextPage(); err != nil {
+			return dsio.Entry{}, err
+		}
+		if len(r.page) == 0 {
+			r.done = true
+			return dsio.Entry{}, io.EOF
+		}
+	}
+
+	obj := r.page[0]
+	r.page = r.page[1:]
+	row := make([]interface{}, len(r.cols))
+	for i, c := range r.cols {
+		row[i] = socrataValue(r.types[i], obj[c.FieldName])
+	}
+
+	ent := dsio.Entry{Index: r.idx, Value: row}
+	r.idx++
+	return ent, nil
+}
+
+func (r *SocrataReader) nextPage() error {
+	q := url.Values{}
+	q.Set("$limit", strconv.Itoa(r.cfg.PageSize))
+	q.Set("$offset", strconv.Itoa(r.offset))
+	q.Set("$order", ":id")
+
+	r.page = nil
+	if err := r.cfg.get(r.rowsURL+"?"+q.Encode(), &r.page); err != nil {
+		return err
+	}
+	r.offset += len(r.page)
+	// a short page is the last page
+	if len(r.page) < r.cfg.PageSize {
+		r.done = true
+	}
+	return nil
+}
+
+// socrataValue converts SODA json values, which encode numbers as strings, to
+// native types
+func socrataValue(t string, v interface{}) interface{} {
+	str, ok := v.(string)
+	if !ok {
+		return v
+	}
+	switch t {
+	case "number":
+		if f, err := strconv.ParseFloat(str, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(str); err == nil {
+			return b
+		}
+	}
+	return str
+}
+
+// Close finalizes the reader
+func (r *SocrataReader) Close() error {
+	return nil
+}
+
+func (cfg *SocrataConfig) get(u string, v interface{}) error {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if cfg.AppToken != "" {
+		req.Header.Set("X-App-Token", cfg.AppToken)
+	}
+	res, err := cfg.Client.Do(req)
+	if err != nil {
+		log.Debug(err.Error())
+		return fmt.Errorf("fetching %s: %s", u, err.Error())
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching %s: %s", u, res.Status)
+	}
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding %s: %s", u, err.Error())
+	}
+	return nil
+}
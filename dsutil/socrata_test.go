@@ -0,0 +1,88 @@
+package dsutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/qri-io/dataset/dsio"
+)
+
+func TestSocrataReader(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "a", "amount": "1.5", "paid": true},
+		{"name": "b", "amount": "2"},
+		{"name": "c", "amount": "3", "paid": false},
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-App-Token") != "tok" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/views/abcd-1234.json":
+			w.Write([]byte(`{
+				"id": "abcd-1234",
+				"name": "Payments",
+				"description": "city payments",
+				"category": "Finance",
+				"tags": ["money"],
+				"columns": [
+					{"fieldName": ":id", "dataTypeName": "meta_data"},
+					{"fieldName": "name", "dataTypeName": "text"},
+					{"fieldName": "amount", "dataTypeName": "money"},
+					{"fieldName": "paid", "dataTypeName": "checkbox"}
+				]
+			}`))
+		case "/resource/abcd-1234.json":
+			q := r.URL.Query()
+			limit, _ := strconv.Atoi(q.Get("$limit"))
+			offset, _ := strconv.Atoi(q.Get("$offset"))
+			end := offset + limit
+			if end > len(rows) {
+				end = len(rows)
+			}
+			json.NewEncoder(w).Encode(rows[offset:end])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	config := func(cfg *SocrataConfig) {
+		cfg.AppToken = "tok"
+		cfg.PageSize = 2
+	}
+	r, ds, err := NewSocrataReader(s.URL, "abcd-1234", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.Meta.Title != "Payments" || ds.Meta.Theme[0] != "Finance" {
+		t.Errorf("unexpected meta: %#v", ds.Meta)
+	}
+
+	expect := []interface{}{
+		[]interface{}{"a", 1.5, true},
+		[]interface{}{"b", 2.0, nil},
+		[]interface{}{"c", 3.0, false},
+	}
+	got := []interface{}{}
+	err = dsio.EachEntry(r, func(i int, ent dsio.Entry, err error) error {
+		got = append(got, ent.Value)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expect, got) {
+		t.Errorf("rows mismatch.\nexpected: %v\ngot:      %v", expect, got)
+	}
+
+	if _, _, err := NewSocrataReader(s.URL, "missing", config); err == nil {
+		t.Error("expected missing dataset to error")
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"sort"
 	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qfs"
 	"github.com/qri-io/qfs/cafs"
 )
 
@@ -123,9 +125,48 @@ func WriteZipArchive(store cafs.Filestore, ds *dataset.Dataset, format string, r
 		log.Debug(err.Error())
 		return err
 	}
+
+	// Named bodies
+	for _, name := range namedBodyNames(ds) {
+		nb := ds.Bodies[name]
+		dst, err := zw.Create(namedBodyFilepath(name, nb))
+		if err != nil {
+			log.Debug(err.Error())
+			return err
+		}
+		src, err := dsfs.LoadNamedBody(store, ds, name)
+		if err != nil {
+			log.Debug(err.Error())
+			return err
+		}
+		_, err = io.Copy(dst, src)
+		src.Close()
+		if err != nil {
+			log.Debug(err.Error())
+			return err
+		}
+	}
 	return zw.Close()
 }
 
+// namedBodyNames gives the names of a dataset's named bodies in sorted order
+func namedBodyNames(ds *dataset.Dataset) []string {
+	names := make([]string, 0, len(ds.Bodies))
+	for name, nb := range ds.Bodies {
+		if nb != nil && nb.Structure != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// namedBodyFilepath gives the path of a named body in zip archives &
+// directories
+func namedBodyFilepath(name string, nb *dataset.NamedBody) string {
+	return fmt.Sprintf("bodies/%s.%s", name, nb.Structure.Format)
+}
+
 // UnzipDatasetBytes is a convenince wrapper for UnzipDataset
 func UnzipDatasetBytes(zipData []byte, ds *dataset.Dataset) error {
 	return UnzipDataset(bytes.NewReader(zipData), int64(len(zipData)), ds)
@@ -165,6 +206,14 @@ func UnzipDataset(r io.ReaderAt, size int64, ds *dataset.Dataset) error {
 		ds.BodyPath = ""
 	}
 
+	for _, name := range namedBodyNames(ds) {
+		nb := ds.Bodies[name]
+		if bodyData, ok := contents[namedBodyFilepath(name, nb)]; ok {
+			nb.SetBodyFile(qfs.NewMemfileBytes(fmt.Sprintf("%s.%s", name, nb.Structure.Format), bodyData))
+			nb.Path = ""
+		}
+	}
+
 	if tfScriptData, ok := contents["transform.star"]; ok {
 		if ds.Transform == nil {
 			ds.Transform = &dataset.Transform{}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
 )
 
 func TestWriteZipArchive(t *testing.T) {
@@ -153,3 +155,62 @@ func TestUnzipGetContents(t *testing.T) {
 		t.Errorf("contents length mismatch. expected: %d, got: %d", expectLen, len(res))
 	}
 }
+
+func TestZipNamedBodies(t *testing.T) {
+	ds := &dataset.Dataset{
+		Structure: &dataset.Structure{Format: "csv", Schema: dataset.BaseSchemaArray},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("movies.csv", []byte("movie\nup\n")))
+	codebook := &dataset.NamedBody{Structure: &dataset.Structure{Format: "csv", Schema: dataset.BaseSchemaArray}}
+	codebook.SetBodyFile(qfs.NewMemfileBytes("codebook.csv", []byte("column,description\nmovie,title\n")))
+	ds.Bodies = map[string]*dataset.NamedBody{"codebook": codebook}
+
+	store := cafs.NewMapstore()
+	path, err := dsfs.WriteDataset(store, ds, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err = dsfs.LoadDataset(store, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err = WriteZipArchive(store, ds, "json", "peer/ref@a/ipfs/b", buf); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &dataset.Dataset{}
+	if err := UnzipDatasetBytes(buf.Bytes(), got); err != nil {
+		t.Fatal(err)
+	}
+	nb := got.Bodies["codebook"]
+	if nb == nil || nb.BodyFile() == nil {
+		t.Fatalf("expected unzipped codebook body file, got: %v", got.Bodies)
+	}
+	if nb.Path != "" {
+		t.Errorf("expected unzipped body path to be cleared, got: %s", nb.Path)
+	}
+	data, err := ioutil.ReadAll(nb.BodyFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "column,description\nmovie,title\n" {
+		t.Errorf("codebook body mismatch, got: %q", data)
+	}
+
+	dir, err := ioutil.TempDir("", "named_bodies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := WriteDir(store, ds, dir); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = ioutil.ReadFile(filepath.Join(dir, "bodies", "codebook.csv")); err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "column,description\nmovie,title\n" {
+		t.Errorf("codebook file mismatch, got: %q", data)
+	}
+}
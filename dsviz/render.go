@@ -3,6 +3,7 @@ package dsviz
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -96,7 +97,7 @@ func readEntries(reader dsio.EntryReader) (interface{}, error) {
 	for i := 0; ; i++ {
 		val, err := reader.ReadEntry()
 		if err != nil {
-			if err.Error() == "EOF" {
+			if errors.Is(err, io.EOF) {
 				break
 			}
 			return nil, err
@@ -0,0 +1,129 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// EmptyPolicy determines how document fields set to empty values are
+// encoded. Empty values are the ones the json "omitempty" option drops:
+// "", 0, false, and nil or zero-length pointers, maps & slices. Fields
+// without omitempty are always encoded as-is
+type EmptyPolicy int
+
+const (
+	// OmitEmpty drops empty fields. MarshalJSON uses OmitEmpty, so hashes of
+	// documents don't change when fields are added to document definitions.
+	// Use OmitEmpty for canonical encoding
+	OmitEmpty EmptyPolicy = iota
+	// NullEmpty encodes empty fields as null, showing every field while
+	// marking which weren't set
+	NullEmpty
+	// KeepEmpty encodes empty fields with their value, "" for strings, 0 for
+	// numbers & null for unset references
+	KeepEmpty
+)
+
+// String implements the stringer interface for EmptyPolicy
+func (p EmptyPolicy) String() string {
+	switch p {
+	case NullEmpty:
+		return "null"
+	case KeepEmpty:
+		return "keep"
+	default:
+		return "omit"
+	}
+}
+
+// marshalFields encodes the exported fields of a struct as a json object in
+// declaration order, writing empty omitempty fields according to p. values
+// replaces the encoded value of fields by json name, fields named in skip
+// aren't encoded. With OmitEmpty & no values the output matches json.Marshal
+func marshalFields(v interface{}, p EmptyPolicy, values map[string]interface{}, skip ...string) ([]byte, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	written := 0
+fields:
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, opts := f.Name, ""
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			parts := strings.SplitN(tag, ",", 2)
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			if len(parts) == 2 {
+				opts = parts[1]
+			}
+		}
+		for _, s := range skip {
+			if s == name {
+				continue fields
+			}
+		}
+
+		fv := rv.Field(i)
+		var val interface{}
+		if x, ok := values[name]; ok {
+			val = x
+		} else if strings.Contains(opts, "omitempty") && isEmptyValue(fv) {
+			switch p {
+			case OmitEmpty:
+				continue
+			case NullEmpty:
+				val = nil
+			default:
+				val = fv.Interface()
+			}
+		} else {
+			val = fv.Interface()
+		}
+
+		data, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+		if written > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(data)
+		written++
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// isEmptyValue reports whether omitempty drops a value, following
+// encoding/json
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestEmptyPolicyString(t *testing.T) {
+	cases := []struct {
+		p      EmptyPolicy
+		expect string
+	}{
+		{OmitEmpty, "omit"},
+		{NullEmpty, "null"},
+		{KeepEmpty, "keep"},
+	}
+	for i, c := range cases {
+		if got := c.p.String(); got != c.expect {
+			t.Errorf("case %d mismatch. expected: %s, got: %s", i, c.expect, got)
+		}
+	}
+}
+
+func TestStructureMarshalJSONPolicy(t *testing.T) {
+	st := Structure{Format: "csv", Entries: 2, Path: "/map/QmStructure", Schema: BaseSchemaArray}
+	cases := []struct {
+		p      EmptyPolicy
+		expect string
+	}{
+		{OmitEmpty, `{"errCount":0,"entries":2,"format":"csv","qri":"st:0","schema":{"type":"array"}}`},
+		{NullEmpty, `{"checksum":null,"chunkChecksums":null,"compression":null,"depth":null,"encoding":null,"errCount":0,"entries":2,"format":"csv","formatConfig":null,"length":null,"partition":null,"qri":"st:0","schema":{"type":"array"}}`},
+		{KeepEmpty, `{"checksum":"","chunkChecksums":null,"compression":"","depth":0,"encoding":"","errCount":0,"entries":2,"format":"csv","formatConfig":null,"length":0,"partition":null,"qri":"st:0","schema":{"type":"array"}}`},
+	}
+	for _, c := range cases {
+		data, err := st.MarshalJSONPolicy(c.p)
+		if err != nil {
+			t.Fatalf("%s policy unexpected error: %s", c.p, err)
+		}
+		if string(data) != c.expect {
+			t.Errorf("%s policy mismatch.\nexpected: %s\ngot:      %s", c.p, c.expect, data)
+		}
+	}
+
+	ref := Structure{Path: "/map/QmStructure"}
+	for _, p := range []EmptyPolicy{OmitEmpty, NullEmpty, KeepEmpty} {
+		data, err := ref.MarshalJSONPolicy(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `"/map/QmStructure"` {
+			t.Errorf("%s policy: expected path reference, got: %s", p, data)
+		}
+	}
+}
+
+func TestDatasetMarshalJSONPolicy(t *testing.T) {
+	ds := &Dataset{
+		Qri:       KindDataset.String(),
+		Meta:      &Meta{Qri: KindMeta.String(), Title: "policies"},
+		Structure: &Structure{Qri: KindStructure.String(), Format: "json", Schema: BaseSchemaObject},
+	}
+
+	data, err := ds.MarshalJSONPolicy(NullEmpty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]interface{}{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"bodyPath", "commit", "numVersions", "viz"} {
+		if val, ok := got[key]; !ok || val != nil {
+			t.Errorf("expected null %s, got: %v", key, val)
+		}
+	}
+	if got["qri"] != "ds:0" {
+		t.Errorf("expected qri kind, got: %v", got["qri"])
+	}
+	st, _ := got["structure"].(map[string]interface{})
+	if val, ok := st["compression"]; !ok || val != nil {
+		t.Errorf("expected structure fields to follow the policy, got: %v", st)
+	}
+
+	data, err = ds.MarshalJSONPolicy(KeepEmpty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = map[string]interface{}{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["bodyPath"] != "" || got["numVersions"] != float64(0) || got["commit"] != nil {
+		t.Errorf("expected empty values to be kept, got: %s", data)
+	}
+	decoded := &Dataset{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("decoding kept empty fields: %s", err)
+	}
+	if err := CompareDatasets(ds, decoded); err != nil {
+		t.Errorf("round trip mismatch: %s", err)
+	}
+}
+
+func TestMarshalJSONPolicyOmitMatchesMarshalJSON(t *testing.T) {
+	docs := []*Dataset{
+		{Path: "/map/QmPath"},
+		{Meta: &Meta{Title: "a"}, BodyPath: "/map/QmBody", NumVersions: 2},
+		{Structure: &Structure{Format: "csv", FormatConfig: map[string]interface{}{"headerRow": true}, Schema: BaseSchemaArray, Entries: 4, Length: 20}},
+		{Structure: &Structure{Path: "/map/QmStructure"}, Commit: &Commit{Title: "init"}},
+	}
+	for i, ds := range docs {
+		got, err := ds.MarshalJSONPolicy(OmitEmpty)
+		if err != nil {
+			t.Fatalf("case %d unexpected error: %s", i, err)
+		}
+		expect, err := ds.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, expect) {
+			t.Errorf("case %d mismatch.\nexpected: %s\ngot:      %s", i, expect, got)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package dataset
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// shapeField is a column of a structure's shape
+type shapeField struct {
+	name string
+	typ  string
+}
+
+// shape gives the normalized top-level type & columns of a structure's
+// schema. columns come from the items of tabular schemas or the properties
+// of object entries, sorted by name
+func (s *Structure) shape() (string, []shapeField) {
+	if s.Schema == nil {
+		return "", nil
+	}
+	tlt, _ := s.Schema["type"].(string)
+
+	var fields []shapeField
+	items, _ := s.Schema["items"].(map[string]interface{})
+	if cols, ok := items["items"].([]interface{}); ok {
+		for _, c := range cols {
+			if col, ok := c.(map[string]interface{}); ok {
+				title, _ := col["title"].(string)
+				fields = append(fields, shapeField{normalizeColumnName(title), schemaType(col["type"])})
+			}
+		}
+	} else if props, ok := items["properties"].(map[string]interface{}); ok {
+		for name, p := range props {
+			prop, _ := p.(map[string]interface{})
+			fields = append(fields, shapeField{normalizeColumnName(name), schemaType(prop["type"])})
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].name == fields[j].name {
+			return fields[i].typ < fields[j].typ
+		}
+		return fields[i].name < fields[j].name
+	})
+	return tlt, fields
+}
+
+// normalizeColumnName lowercases a column name, dropping everything but
+// letters & digits, so "First Name", "first_name" & "FirstName" match
+func normalizeColumnName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// schemaType normalizes a jsonschema type value. lists of types are sorted,
+// with "null" dropped, so nullable columns match their non-null counterparts
+func schemaType(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []interface{}:
+		var types []string
+		for _, x := range t {
+			if s, ok := x.(string); ok && s != "null" {
+				types = append(types, s)
+			}
+		}
+		sort.Strings(types)
+		return strings.Join(types, "|")
+	default:
+		return ""
+	}
+}
+
+// Fingerprint gives a hash of a structure's shape: the top-level type &
+// normalized name & type of each column. Column order, casing & punctuation
+// in names, descriptions & format are ignored, so structures with the same
+// shape share a fingerprint regardless of how they're encoded
+func (s *Structure) Fingerprint() (string, error) {
+	tlt, fields := s.shape()
+	b := &strings.Builder{}
+	b.WriteString(tlt)
+	for _, f := range fields {
+		b.WriteString("\n")
+		b.WriteString(f.name)
+		b.WriteString(":")
+		b.WriteString(f.typ)
+	}
+	return HashBytes([]byte(b.String()))
+}
+
+// Similarity scores how alike the shapes of two structures are, from 0 for
+// structures with different top-level types or no columns in common to 1 for
+// structures with the same fingerprint. columns that match by name but not
+// type count half
+func (s *Structure) Similarity(other *Structure) float64 {
+	tltA, a := s.shape()
+	tltB, b := other.shape()
+	if tltA != tltB {
+		return 0
+	}
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	types := map[string][]string{}
+	for _, f := range b {
+		types[f.name] = append(types[f.name], f.typ)
+	}
+	score, matched := 0.0, 0
+	for _, f := range a {
+		candidates := types[f.name]
+		if len(candidates) == 0 {
+			continue
+		}
+		// prefer a candidate with the same type
+		pick := 0
+		for i, typ := range candidates {
+			if typ == f.typ {
+				pick = i
+				break
+			}
+		}
+		if candidates[pick] == f.typ {
+			score++
+		} else {
+			score += 0.5
+		}
+		matched++
+		types[f.name] = append(candidates[:pick], candidates[pick+1:]...)
+	}
+	return score / float64(len(a)+len(b)-matched)
+}
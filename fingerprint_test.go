@@ -0,0 +1,88 @@
+package dataset
+
+import (
+	"testing"
+)
+
+func tabularStructure(format string, cols ...[2]interface{}) *Structure {
+	items := make([]interface{}, len(cols))
+	for i, c := range cols {
+		items[i] = map[string]interface{}{"title": c[0], "type": c[1]}
+	}
+	return &Structure{
+		Format: format,
+		Schema: map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "array", "items": items},
+		},
+	}
+}
+
+func TestStructureFingerprint(t *testing.T) {
+	a := tabularStructure("csv", [2]interface{}{"First Name", "string"}, [2]interface{}{"age", "integer"})
+	b := tabularStructure("json", [2]interface{}{"AGE", "integer"}, [2]interface{}{"first_name", []interface{}{"string", "null"}})
+	b.Schema["items"].(map[string]interface{})["items"].([]interface{})[0].(map[string]interface{})["description"] = "age in years"
+	c := tabularStructure("csv", [2]interface{}{"first name", "string"}, [2]interface{}{"age", "number"})
+	objects := &Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"age":       map[string]interface{}{"type": "integer"},
+					"firstName": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	}
+
+	fa, err := a.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb, _ := b.Fingerprint()
+	fc, _ := c.Fingerprint()
+	fo, _ := objects.Fingerprint()
+	if fa != fb {
+		t.Errorf("expected structures differing only by casing, order, format & description to share a fingerprint")
+	}
+	if fa == fc {
+		t.Errorf("expected a changed column type to change the fingerprint")
+	}
+	if fa != fo {
+		t.Errorf("expected object entries with the same properties to share a fingerprint")
+	}
+	if fe, _ := (&Structure{Schema: BaseSchemaArray}).Fingerprint(); fe == fa {
+		t.Errorf("expected a structure without columns to have a different fingerprint")
+	}
+}
+
+func TestStructureSimilarity(t *testing.T) {
+	base := tabularStructure("csv", [2]interface{}{"name", "string"}, [2]interface{}{"age", "integer"}, [2]interface{}{"city", "string"}, [2]interface{}{"zip", "string"})
+	cases := []struct {
+		other  *Structure
+		expect float64
+	}{
+		{tabularStructure("json", [2]interface{}{"Zip", "string"}, [2]interface{}{"City", "string"}, [2]interface{}{"Age", "integer"}, [2]interface{}{"Name", "string"}), 1},
+		{tabularStructure("csv", [2]interface{}{"name", "string"}, [2]interface{}{"age", "integer"}), 0.5},
+		{tabularStructure("csv", [2]interface{}{"name", "string"}, [2]interface{}{"age", "string"}, [2]interface{}{"city", "string"}, [2]interface{}{"zip", "string"}), 0.875},
+		{tabularStructure("csv", [2]interface{}{"name", "string"}, [2]interface{}{"age", "integer"}, [2]interface{}{"city", "string"}, [2]interface{}{"country", "string"}), 0.6},
+		{tabularStructure("csv", [2]interface{}{"x", "string"}), 0},
+		{&Structure{Schema: BaseSchemaObject}, 0},
+	}
+
+	for i, c := range cases {
+		if got := base.Similarity(c.other); got != c.expect {
+			t.Errorf("case %d similarity mismatch. expected: %f, got: %f", i, c.expect, got)
+		}
+		if got := c.other.Similarity(base); got != c.expect {
+			t.Errorf("case %d expected similarity to be symmetric. expected: %f, got: %f", i, c.expect, got)
+		}
+	}
+
+	empty := &Structure{Schema: BaseSchemaArray}
+	if got := empty.Similarity(&Structure{Schema: BaseSchemaArray}); got != 1 {
+		t.Errorf("expected structures without columns to be identical, got: %f", got)
+	}
+}
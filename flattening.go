@@ -0,0 +1,169 @@
+package dataset
+
+import (
+	"fmt"
+)
+
+// FlattenConfigKey is the FormatConfig key flattened bodies record their
+// Flattening under
+const FlattenConfigKey = "flatten"
+
+// Flattening records how nested entries were flattened into columns, so a
+// flattened body can be turned back into nested entries. Each column holds
+// the value found at a path in an entry. Arrays are either spread across
+// indexed columns or exploded, writing one row per element
+type Flattening struct {
+	// Separator joins path elements into column titles
+	Separator string `json:"separator,omitempty"`
+	// Explode is a JSON Pointer to the exploded array, empty if no array is
+	// exploded
+	Explode string `json:"explode,omitempty"`
+	// EntryColumn titles the first column of exploded bodies, which holds
+	// the index of the entry a row came from
+	EntryColumn string `json:"entryColumn,omitempty"`
+	// Columns lists flattened columns in order, not including EntryColumn
+	Columns []FlattenColumn `json:"columns"`
+}
+
+// FlattenColumn maps a column of a flattened body to a nested value
+type FlattenColumn struct {
+	// Title is the column title
+	Title string `json:"title"`
+	// Path is the object keys (strings) & array indexes (ints) leading to
+	// the value. Paths of element columns are relative to an element of the
+	// exploded array
+	Path []interface{} `json:"path"`
+	// Element marks columns holding values of exploded array elements
+	Element bool `json:"element,omitempty"`
+}
+
+// ParseFlattening reads the Flattening recorded in a format config, giving
+// nil if there isn't one
+func ParseFlattening(formatConfig map[string]interface{}) (*Flattening, error) {
+	switch v := formatConfig[FlattenConfigKey].(type) {
+	case nil:
+		return nil, nil
+	case *Flattening:
+		return v, nil
+	case map[string]interface{}:
+		return parseFlattening(v)
+	default:
+		return nil, fmt.Errorf("invalid flatten value: %v", v)
+	}
+}
+
+func parseFlattening(opts map[string]interface{}) (*Flattening, error) {
+	f := &Flattening{}
+	for _, key := range []string{"separator", "explode", "entryColumn"} {
+		if opts[key] == nil {
+			continue
+		}
+		s, ok := opts[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid flatten %s value: %v", key, opts[key])
+		}
+		switch key {
+		case "separator":
+			f.Separator = s
+		case "explode":
+			f.Explode = s
+		case "entryColumn":
+			f.EntryColumn = s
+		}
+	}
+	if f.Explode != "" && f.EntryColumn == "" {
+		return nil, fmt.Errorf("flattenings that explode an array require an entryColumn")
+	}
+
+	cols, ok := opts["columns"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid flatten columns value: %v", opts["columns"])
+	}
+	f.Columns = make([]FlattenColumn, len(cols))
+	for i, c := range cols {
+		col, ok := c.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("flatten column %d: invalid column value: %v", i, c)
+		}
+		if f.Columns[i].Title, ok = col["title"].(string); !ok {
+			return nil, fmt.Errorf("flatten column %d: invalid title value: %v", i, col["title"])
+		}
+		if col["element"] != nil {
+			if f.Columns[i].Element, ok = col["element"].(bool); !ok {
+				return nil, fmt.Errorf("flatten column %d: invalid element value: %v", i, col["element"])
+			}
+			if f.Columns[i].Element && f.Explode == "" {
+				return nil, fmt.Errorf("flatten column %d: element columns require an exploded array", i)
+			}
+		}
+		path, err := parseFlattenPath(col["path"])
+		if err != nil {
+			return nil, fmt.Errorf("flatten column %d: %w", i, err)
+		}
+		f.Columns[i].Path = path
+	}
+	return f, nil
+}
+
+// parseFlattenPath reads a path list. Indexes decoded from JSON are floats,
+// and are converted back to ints
+func parseFlattenPath(v interface{}) ([]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	elems, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid path value: %v", v)
+	}
+	path := make([]interface{}, len(elems))
+	for i, e := range elems {
+		switch t := e.(type) {
+		case string:
+			path[i] = t
+		case int:
+			path[i] = t
+		case int64:
+			path[i] = int(t)
+		case float64:
+			if t != float64(int(t)) {
+				return nil, fmt.Errorf("invalid path index: %v", t)
+			}
+			path[i] = int(t)
+		default:
+			return nil, fmt.Errorf("invalid path element: %v", e)
+		}
+		if n, ok := path[i].(int); ok && n < 0 {
+			return nil, fmt.Errorf("invalid path index: %d", n)
+		}
+	}
+	return path, nil
+}
+
+// Map structures a Flattening as a map of string keys to values, for
+// recording in FormatConfig
+func (f *Flattening) Map() map[string]interface{} {
+	if f == nil {
+		return nil
+	}
+	cols := make([]interface{}, len(f.Columns))
+	for i, c := range f.Columns {
+		path := make([]interface{}, len(c.Path))
+		copy(path, c.Path)
+		col := map[string]interface{}{"title": c.Title, "path": path}
+		if c.Element {
+			col["element"] = true
+		}
+		cols[i] = col
+	}
+	opt := map[string]interface{}{"columns": cols}
+	if f.Separator != "" {
+		opt["separator"] = f.Separator
+	}
+	if f.Explode != "" {
+		opt["explode"] = f.Explode
+	}
+	if f.EntryColumn != "" {
+		opt["entryColumn"] = f.EntryColumn
+	}
+	return opt
+}
@@ -0,0 +1,69 @@
+package dataset
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseFlattening(t *testing.T) {
+	f := &Flattening{
+		Separator:   ".",
+		Explode:     "/orders",
+		EntryColumn: "_entry",
+		Columns: []FlattenColumn{
+			{Title: "user.tags.0", Path: []interface{}{"user", "tags", 0}},
+			{Title: "orders.sku", Path: []interface{}{"sku"}, Element: true},
+			{Title: "orders", Path: []interface{}{}, Element: true},
+		},
+	}
+
+	// round trip through json, which decodes indexes as floats
+	data, err := json.Marshal(map[string]interface{}{FlattenConfigKey: f.Map()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := map[string]interface{}{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseFlattening(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f, got) {
+		t.Errorf("flattening mismatch.\nexpected: %#v\ngot:      %#v", f, got)
+	}
+
+	if got, err := ParseFlattening(map[string]interface{}{"headerRow": true}); got != nil || err != nil {
+		t.Errorf("expected no flattening, got: %v %v", got, err)
+	}
+	if got, err := ParseFlattening(map[string]interface{}{FlattenConfigKey: f}); got != f || err != nil {
+		t.Errorf("expected flattening to pass through, got: %v %v", got, err)
+	}
+}
+
+func TestParseFlatteningErrors(t *testing.T) {
+	cases := []struct {
+		opts interface{}
+		err  string
+	}{
+		{"nope", "invalid flatten value: nope"},
+		{map[string]interface{}{"separator": 1, "columns": []interface{}{}}, "invalid flatten separator value: 1"},
+		{map[string]interface{}{"explode": "/a", "columns": []interface{}{}}, "flattenings that explode an array require an entryColumn"},
+		{map[string]interface{}{}, "invalid flatten columns value: <nil>"},
+		{map[string]interface{}{"columns": []interface{}{"a"}}, "flatten column 0: invalid column value: a"},
+		{map[string]interface{}{"columns": []interface{}{map[string]interface{}{"path": []interface{}{}}}}, "flatten column 0: invalid title value: <nil>"},
+		{map[string]interface{}{"columns": []interface{}{map[string]interface{}{"title": "a", "element": true}}}, "flatten column 0: element columns require an exploded array"},
+		{map[string]interface{}{"columns": []interface{}{map[string]interface{}{"title": "a", "path": "a"}}}, "flatten column 0: invalid path value: a"},
+		{map[string]interface{}{"columns": []interface{}{map[string]interface{}{"title": "a", "path": []interface{}{1.5}}}}, "flatten column 0: invalid path index: 1.5"},
+		{map[string]interface{}{"columns": []interface{}{map[string]interface{}{"title": "a", "path": []interface{}{-1}}}}, "flatten column 0: invalid path index: -1"},
+		{map[string]interface{}{"columns": []interface{}{map[string]interface{}{"title": "a", "path": []interface{}{true}}}}, "flatten column 0: invalid path element: true"},
+	}
+	for i, c := range cases {
+		_, err := ParseFlattening(map[string]interface{}{FlattenConfigKey: c.opts})
+		if err == nil || err.Error() != c.err {
+			t.Errorf("case %d: expected error: %q, got: %v", i, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package dataset
+
+import (
+	"fmt"
+)
+
+// FormatDefault gives the output configuration a dataset declares for a data
+// format, nil if it doesn't declare one
+func (ds *Dataset) FormatDefault(f DataFormat) (FormatConfig, error) {
+	opts, ok := ds.FormatDefaults[f.String()]
+	if !ok {
+		return nil, nil
+	}
+	cfg, err := ParseFormatConfigMap(f, opts)
+	if err != nil {
+		return nil, fmt.Errorf("formatDefaults.%s: %w", f, err)
+	}
+	return cfg, nil
+}
+
+// SetFormatDefault records cfg as the dataset's output configuration for
+// cfg's format, replacing any existing default for the format. A nil cfg
+// is ignored
+func (ds *Dataset) SetFormatDefault(cfg FormatConfig) {
+	if cfg == nil {
+		return
+	}
+	if ds.FormatDefaults == nil {
+		ds.FormatDefaults = map[string]map[string]interface{}{}
+	}
+	ds.FormatDefaults[cfg.Format().String()] = cfg.Map()
+}
+
+// OutputFormatConfig resolves the configuration to write a dataset's body
+// as a data format. Options set in cfg override the dataset's default for
+// the format option by option. Options are merged by their map
+// representation, so cfg can't reset a default to the zero value. cfg may be
+// nil, and the result is nil when neither configures the format
+func (ds *Dataset) OutputFormatConfig(f DataFormat, cfg FormatConfig) (FormatConfig, error) {
+	if cfg != nil && cfg.Format() != f {
+		return nil, fmt.Errorf("format config for %s can't configure %s output", cfg.Format(), f)
+	}
+	defaults, ok := ds.FormatDefaults[f.String()]
+	if !ok {
+		return cfg, nil
+	}
+
+	opts := make(map[string]interface{}, len(defaults))
+	for key, val := range defaults {
+		opts[key] = val
+	}
+	if cfg != nil {
+		for key, val := range cfg.Map() {
+			opts[key] = val
+		}
+	}
+	merged, err := ParseFormatConfigMap(f, opts)
+	if err != nil {
+		return nil, fmt.Errorf("formatDefaults.%s: %w", f, err)
+	}
+	return merged, nil
+}
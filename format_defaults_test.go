@@ -0,0 +1,79 @@
+package dataset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatDefault(t *testing.T) {
+	ds := &Dataset{FormatDefaults: map[string]map[string]interface{}{
+		"csv":  {"headerRow": true, "separator": ";"},
+		"json": {"escapeHTML": "yes"},
+	}}
+
+	cfg, err := ds.FormatDefault(CSVDataFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg, &CSVOptions{HeaderRow: true, Separator: ';'}) {
+		t.Errorf("csv default mismatch: %v", cfg)
+	}
+	if cfg, err := ds.FormatDefault(TSVDataFormat); cfg != nil || err != nil {
+		t.Errorf("expected no tsv default, got: %v, %v", cfg, err)
+	}
+	if _, err := ds.FormatDefault(JSONDataFormat); err == nil || err.Error() != "formatDefaults.json: invalid escapeHTML value: yes" {
+		t.Errorf("expected invalid default error, got: %v", err)
+	}
+}
+
+func TestSetFormatDefault(t *testing.T) {
+	ds := &Dataset{}
+	ds.SetFormatDefault(nil)
+	if ds.FormatDefaults != nil {
+		t.Errorf("expected a nil config to be ignored")
+	}
+
+	ds.SetFormatDefault(&CSVOptions{HeaderRow: true, Separator: ';'})
+	cfg, err := ds.FormatDefault(CSVDataFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg, &CSVOptions{HeaderRow: true, Separator: ';'}) {
+		t.Errorf("csv default mismatch: %v", cfg)
+	}
+}
+
+func TestOutputFormatConfig(t *testing.T) {
+	ds := &Dataset{FormatDefaults: map[string]map[string]interface{}{
+		"csv": {"headerRow": true, "separator": ";"},
+	}}
+
+	cases := []struct {
+		format DataFormat
+		cfg    FormatConfig
+		expect FormatConfig
+		err    string
+	}{
+		{CSVDataFormat, nil, &CSVOptions{HeaderRow: true, Separator: ';'}, ""},
+		{CSVDataFormat, &CSVOptions{Separator: '\t', LazyQuotes: true}, &CSVOptions{HeaderRow: true, LazyQuotes: true, Separator: '\t'}, ""},
+		{TSVDataFormat, nil, nil, ""},
+		{TSVDataFormat, &TSVOptions{HeaderRow: true}, &TSVOptions{HeaderRow: true}, ""},
+		{JSONDataFormat, &CSVOptions{}, nil, "format config for csv can't configure json output"},
+	}
+
+	for i, c := range cases {
+		got, err := ds.OutputFormatConfig(c.format, c.cfg)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("case %d result mismatch. expected: %v, got: %v", i, c.expect, got)
+		}
+	}
+
+	// defaults aren't modified by overrides
+	if !reflect.DeepEqual(ds.FormatDefaults["csv"], map[string]interface{}{"headerRow": true, "separator": ";"}) {
+		t.Errorf("expected defaults to be unchanged, got: %v", ds.FormatDefaults["csv"])
+	}
+}
@@ -53,6 +53,9 @@ type Meta struct {
 	// path to dataset readme file, not part of the DCAT spec, but a common
 	// convention in software dev
 	ReadmeURL string `json:"readmeURL,omitempty"`
+	// Retention is a policy for how long body entries are kept, not part of
+	// the DCAT spec
+	Retention *Retention `json:"retention,omitempty"`
 	// Title of this dataset
 	Title string `json:"title,omitempty"`
 	// "Category" for
@@ -81,6 +84,7 @@ func (md *Meta) IsEmpty() bool {
 		md.Language == nil &&
 		md.License == nil &&
 		md.ReadmeURL == "" &&
+		md.Retention == nil &&
 		md.Title == "" &&
 		md.Theme == nil &&
 		md.Version == ""
@@ -217,6 +221,9 @@ func (md *Meta) Set(key string, val interface{}) (err error) {
 	case "license":
 		md.License = &License{}
 		err = md.License.Decode(val)
+	case "retention":
+		md.Retention = &Retention{}
+		err = md.Retention.Decode(val)
 
 	// everything else
 	default:
@@ -292,6 +299,9 @@ func (md *Meta) Assign(metas ...*Meta) {
 		if m.ReadmeURL != "" {
 			md.ReadmeURL = m.ReadmeURL
 		}
+		if m.Retention != nil {
+			md.Retention = m.Retention
+		}
 		if m.Theme != nil {
 			md.Theme = m.Theme
 		}
@@ -305,7 +315,8 @@ func (md *Meta) Assign(metas ...*Meta) {
 }
 
 // MarshalJSON uses a map to combine meta & standard fields.
-// Marshalling a map[string]interface{} automatically alpha-sorts the keys.
+// Marshalling a map[string]interface{} automatically alpha-sorts the keys,
+// including keys of nested arbitrary metadata, so output is deterministic
 func (md *Meta) MarshalJSON() ([]byte, error) {
 	// if we're dealing with an empty object that has a path specified
 	// marshal to a string instead
@@ -319,7 +330,11 @@ func (md *Meta) MarshalJSON() ([]byte, error) {
 // MarshalJSONObject always marshals to a json Object, even if meta is empty or
 // a reference
 func (md *Meta) MarshalJSONObject() ([]byte, error) {
-	data := md.Meta()
+	// copy arbitrary metadata so spec fields don't leak into it
+	data := make(map[string]interface{}, len(md.meta))
+	for key, val := range md.meta {
+		data[key] = val
+	}
 
 	data["qri"] = KindMeta.String()
 
@@ -356,6 +371,9 @@ func (md *Meta) MarshalJSONObject() ([]byte, error) {
 	if md.ReadmeURL != "" {
 		data["readmeURL"] = md.ReadmeURL
 	}
+	if md.Retention != nil {
+		data["retention"] = md.Retention
+	}
 	if md.Theme != nil {
 		data["theme"] = md.Theme
 	}
@@ -378,8 +396,7 @@ type _metadata Meta
 // UnmarshalJSON implements json.Unmarshaller
 func (md *Meta) UnmarshalJSON(data []byte) error {
 	// first check to see if this is a valid path ref
-	var path string
-	if err := json.Unmarshal(data, &path); err == nil {
+	if path, ok := unmarshalPathRef(data); ok {
 		*md = Meta{Path: path}
 		return nil
 	}
@@ -394,29 +411,7 @@ func (md *Meta) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("error unmarshaling dataset metadata: %s", err)
 	}
 
-	for _, f := range []string{
-		"accessURL",
-		"accrualPeriodicity",
-		"citations",
-		"contributors",
-		"data",
-		"description",
-		"downloadURL",
-		"homeURL",
-		"identifier",
-		"image",
-		"keyword",
-		"path",
-		"qri",
-		"language",
-		"length",
-		"license",
-		"readmeURL",
-		"theme",
-		"timestamp",
-		"title",
-		"version",
-	} {
+	for _, f := range reservedMetaKeys {
 		delete(meta, f)
 	}
 
@@ -0,0 +1,190 @@
+package dataset
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrMetaKeyNotFound is the error for reading a key that isn't set in
+// arbitrary metadata
+var ErrMetaKeyNotFound = fmt.Errorf("meta key not found")
+
+// reservedMetaKeys are keys that can't hold arbitrary metadata, either
+// because they're spec fields or they're dropped when decoding meta
+var reservedMetaKeys = []string{
+	"accessURL",
+	"accrualPeriodicity",
+	"citations",
+	"contributors",
+	"data",
+	"description",
+	"downloadURL",
+	"homeURL",
+	"identifier",
+	"image",
+	"keyword",
+	"path",
+	"qri",
+	"language",
+	"length",
+	"license",
+	"readmeURL",
+	"retention",
+	"theme",
+	"timestamp",
+	"title",
+	"version",
+}
+
+// ValidateMetaKey checks a dot-separated path can address arbitrary
+// metadata. The first key of the path can't be a reserved spec field, which
+// are set with Meta.Set
+func ValidateMetaKey(path string) error {
+	keys := strings.Split(path, ".")
+	for _, key := range keys {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid meta key '%s': keys can't be empty", path)
+		}
+	}
+	for _, reserved := range reservedMetaKeys {
+		if strings.EqualFold(strings.TrimSpace(keys[0]), reserved) {
+			return fmt.Errorf("invalid meta key '%s': %s is a reserved meta field", path, reserved)
+		}
+	}
+	return nil
+}
+
+// Get reads the arbitrary metadata value at a dot-separated path like
+// "publisher.name", returning ErrMetaKeyNotFound if no value is set
+func (md *Meta) Get(path string) (interface{}, error) {
+	if err := ValidateMetaKey(path); err != nil {
+		return nil, err
+	}
+	var val interface{} = md.meta
+	for _, key := range strings.Split(path, ".") {
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMetaKeyNotFound, path)
+		}
+		if val, ok = m[key]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMetaKeyNotFound, path)
+		}
+	}
+	return val, nil
+}
+
+// GetString reads a string from arbitrary metadata
+func (md *Meta) GetString(path string) (string, error) {
+	val, err := md.Get(path)
+	if err != nil {
+		return "", err
+	}
+	s, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("meta key %s: expected a string, got %T", path, val)
+	}
+	return s, nil
+}
+
+// GetNumber reads a number from arbitrary metadata
+func (md *Meta) GetNumber(path string) (float64, error) {
+	val, err := md.Get(path)
+	if err != nil {
+		return 0, err
+	}
+	switch x := val.(type) {
+	case float64:
+		return x, nil
+	case float32:
+		return float64(x), nil
+	case int:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case json.Number:
+		return x.Float64()
+	default:
+		return 0, fmt.Errorf("meta key %s: expected a number, got %T", path, val)
+	}
+}
+
+// GetTime reads a date or time from arbitrary metadata. times are stored as
+// strings, RFC 3339 & calendar dates like "2020-03-14" are accepted
+func (md *Meta) GetTime(path string) (time.Time, error) {
+	val, err := md.Get(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch x := val.(type) {
+	case time.Time:
+		return x, nil
+	case string:
+		t, err := parseTimestamp(x)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("meta key %s: %w", path, err)
+		}
+		return t, nil
+	default:
+		return time.Time{}, fmt.Errorf("meta key %s: expected a date or time string, got %T", path, val)
+	}
+}
+
+// SetPath writes a value to arbitrary metadata at a dot-separated path,
+// creating objects for missing parent keys. values should be json.Unmarshal
+// types
+func (md *Meta) SetPath(path string, val interface{}) error {
+	if err := ValidateMetaKey(path); err != nil {
+		return err
+	}
+	keys := strings.Split(path, ".")
+	m := md.Meta()
+	for i, key := range keys[:len(keys)-1] {
+		switch x := m[key].(type) {
+		case map[string]interface{}:
+			m = x
+		case nil:
+			child := map[string]interface{}{}
+			m[key] = child
+			m = child
+		default:
+			return fmt.Errorf("meta key %s: %s is a %T, not an object", path, strings.Join(keys[:i+1], "."), x)
+		}
+	}
+	m[keys[len(keys)-1]] = val
+	return nil
+}
+
+// SetString writes a string to arbitrary metadata
+func (md *Meta) SetString(path, s string) error {
+	return md.SetPath(path, s)
+}
+
+// SetNumber writes a number to arbitrary metadata
+func (md *Meta) SetNumber(path string, n float64) error {
+	return md.SetPath(path, n)
+}
+
+// SetTime writes a time to arbitrary metadata as an RFC 3339 string in UTC
+func (md *Meta) SetTime(path string, t time.Time) error {
+	return md.SetPath(path, t.UTC().Format(time.RFC3339Nano))
+}
+
+// Delete removes the arbitrary metadata value at a dot-separated path
+func (md *Meta) Delete(path string) error {
+	if err := ValidateMetaKey(path); err != nil {
+		return err
+	}
+	keys := strings.Split(path, ".")
+	m := md.meta
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := m[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		m = child
+	}
+	delete(m, keys[len(keys)-1])
+	return nil
+}
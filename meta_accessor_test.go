@@ -0,0 +1,121 @@
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateMetaKey(t *testing.T) {
+	cases := []struct {
+		path string
+		err  string
+	}{
+		{"publisher", ""},
+		{"publisher.name", ""},
+		{"titles", ""},
+		{"", "invalid meta key '': keys can't be empty"},
+		{"a..b", "invalid meta key 'a..b': keys can't be empty"},
+		{"title", "invalid meta key 'title': title is a reserved meta field"},
+		{"AccessUrl.x", "invalid meta key 'AccessUrl.x': accessURL is a reserved meta field"},
+		{"qri", "invalid meta key 'qri': qri is a reserved meta field"},
+	}
+	for i, c := range cases {
+		err := ValidateMetaKey(c.path)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestMetaAccessors(t *testing.T) {
+	md := &Meta{Title: "accessors"}
+	ts := time.Date(2020, 3, 14, 15, 9, 26, 0, time.FixedZone("", 3600))
+	if err := md.SetString("publisher.name", "qri"); err != nil {
+		t.Fatal(err)
+	}
+	if err := md.SetNumber("publisher.founded", 2017); err != nil {
+		t.Fatal(err)
+	}
+	if err := md.SetTime("reviewed", ts); err != nil {
+		t.Fatal(err)
+	}
+	if err := md.SetPath("tags", []interface{}{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err := md.GetString("publisher.name"); err != nil || s != "qri" {
+		t.Errorf("expected publisher.name to be qri, got: %s, %v", s, err)
+	}
+	if n, err := md.GetNumber("publisher.founded"); err != nil || n != 2017 {
+		t.Errorf("expected publisher.founded to be 2017, got: %f, %v", n, err)
+	}
+	if got, err := md.GetTime("reviewed"); err != nil || !got.Equal(ts) {
+		t.Errorf("expected reviewed to be %s, got: %s, %v", ts, got, err)
+	}
+	if s, _ := md.GetString("reviewed"); s != "2020-03-14T14:09:26Z" {
+		t.Errorf("expected times to be stored as UTC RFC 3339, got: %s", s)
+	}
+
+	if _, err := md.Get("publisher.missing"); !errors.Is(err, ErrMetaKeyNotFound) {
+		t.Errorf("expected ErrMetaKeyNotFound, got: %v", err)
+	}
+	if _, err := md.Get("tags.first"); !errors.Is(err, ErrMetaKeyNotFound) {
+		t.Errorf("expected ErrMetaKeyNotFound reading through a non-object, got: %v", err)
+	}
+	if _, err := md.GetNumber("publisher.name"); err == nil || err.Error() != "meta key publisher.name: expected a number, got string" {
+		t.Errorf("expected a type error, got: %v", err)
+	}
+	if _, err := md.GetTime("publisher.name"); err == nil || err.Error() != "meta key publisher.name: invalid date or time 'qri'" {
+		t.Errorf("expected a time parsing error, got: %v", err)
+	}
+	if err := md.SetString("tags.first", "a"); err == nil || err.Error() != "meta key tags.first: tags is a []interface {}, not an object" {
+		t.Errorf("expected setting through a non-object to error, got: %v", err)
+	}
+	if err := md.SetString("title", "nope"); err == nil {
+		t.Error("expected setting a reserved key to error")
+	}
+	if md.Title != "accessors" {
+		t.Errorf("expected title to be unchanged, got: %s", md.Title)
+	}
+
+	if err := md.Delete("publisher.founded"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := md.Get("publisher.founded"); !errors.Is(err, ErrMetaKeyNotFound) {
+		t.Errorf("expected deleted key to be missing, got: %v", err)
+	}
+}
+
+func TestMetaAccessorsJSON(t *testing.T) {
+	md := &Meta{Title: "round trip"}
+	md.SetString("z.b", "b")
+	md.SetString("z.a", "a")
+	md.SetNumber("count", 3)
+
+	expect := `{"count":3,"qri":"md:0","title":"round trip","z":{"a":"a","b":"b"}}`
+	for i := 0; i < 3; i++ {
+		data, err := json.Marshal(md)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expect {
+			t.Errorf("marshal %d mismatch.\nexpected: %s\ngot:      %s", i, expect, string(data))
+		}
+	}
+	if _, ok := md.Meta()["title"]; ok {
+		t.Error("expected marshaling not to write spec fields into arbitrary metadata")
+	}
+
+	got := &Meta{}
+	if err := json.Unmarshal([]byte(expect), got); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := got.GetString("z.b"); err != nil || s != "b" {
+		t.Errorf("expected z.b to survive a round trip, got: %s, %v", s, err)
+	}
+	if n, err := got.GetNumber("count"); err != nil || n != 3 {
+		t.Errorf("expected count to survive a round trip, got: %f, %v", n, err)
+	}
+}
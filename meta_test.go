@@ -28,6 +28,7 @@ func TestMetaAssign(t *testing.T) {
 		{&Meta{Contributors: []*User{{Email: "foo"}}}},
 		{&Meta{Language: []string{"stuff"}}},
 		{&Meta{Theme: []string{"stuff"}}},
+		{&Meta{Retention: &Retention{Column: "created", MaxAge: "P30D"}}},
 		{&Meta{meta: map[string]interface{}{"foo": "bar"}}},
 	}
 
@@ -133,6 +134,13 @@ func TestMetaSet(t *testing.T) {
 			"url":  "bar",
 		}, "", &Meta{License: &License{Type: "foo", URL: "bar"}}},
 
+		{"retention", 0, "expected map[string]interface{}", nil},
+		{"retention", map[string]interface{}{"column": 0}, "type must be a string", nil},
+		{"retention", map[string]interface{}{
+			"column": "created",
+			"maxAge": "P30D",
+		}, "", &Meta{Retention: &Retention{Column: "created", MaxAge: "P30D"}}},
+
 		{"@id", "foo", "", &Meta{meta: map[string]interface{}{"@id": "foo"}}},
 	}
 
@@ -159,6 +167,7 @@ func TestMetaMarshalJSON(t *testing.T) {
 		err error
 	}{
 		{&Meta{}, []byte(`{"qri":"md:0"}`), nil},
+		{&Meta{Retention: &Retention{Column: "created", MaxAge: "P30D"}}, []byte(`{"qri":"md:0","retention":{"column":"created","maxAge":"P30D"}}`), nil},
 		{AirportCodes.Meta, []byte(`{"citations":[{"name":"Our Airports","url":"http://ourairports.com/data/"}],"homeURL":"http://www.ourairports.com/","license":{"type":"PDDL-1.0"},"qri":"md:0","title":"Airport Codes"}`), nil},
 		{Hours.Meta, []byte(`{"accessURL":"https://example.com/not/a/url","downloadURL":"https://example.com/not/a/url","qri":"md:0","readmeURL":"/ipfs/notahash","title":"hours"}`), nil},
 	}
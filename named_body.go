@@ -0,0 +1,69 @@
+package dataset
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/qri-io/qfs"
+)
+
+// NamedBody is an additional body file published with a dataset, like an
+// errata or codebook table that accompanies the main body. Named bodies are
+// keyed by name in Dataset.Bodies, and each has it's own structure
+type NamedBody struct {
+	// body file reader, doesn't serialize
+	file qfs.File
+	// Path is the path to the body file
+	Path string `json:"path,omitempty"`
+	// Structure of the body file
+	Structure *Structure `json:"structure,omitempty"`
+}
+
+// SetBodyFile assigns the body file
+func (nb *NamedBody) SetBodyFile(file qfs.File) {
+	nb.file = file
+}
+
+// BodyFile exposes the body file if one is set
+func (nb *NamedBody) BodyFile() qfs.File {
+	return nb.file
+}
+
+// bodyNameRegex matches valid named body names, which are used in filenames
+var bodyNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+// ValidBodyName checks a name can be used to key a named body. names may only
+// contain letters, numbers, underscores & dashes
+func ValidBodyName(name string) error {
+	if !bodyNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid body name '%s': names may only contain letters, numbers, underscores & dashes", name)
+	}
+	return nil
+}
+
+// CompareNamedBodies checks if all named bodies of two datasets are equal,
+// returning an error on the first, nil if equal
+func CompareNamedBodies(a, b map[string]*NamedBody) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("length: %d != %d", len(a), len(b))
+	}
+	for name, ab := range a {
+		bb, ok := b[name]
+		if !ok {
+			return fmt.Errorf("%s: missing", name)
+		}
+		if ab == nil || bb == nil {
+			if ab != bb {
+				return fmt.Errorf("%s: nil mismatch", name)
+			}
+			continue
+		}
+		if ab.Path != bb.Path {
+			return fmt.Errorf("%s Path: %s != %s", name, ab.Path, bb.Path)
+		}
+		if err := CompareStructures(ab.Structure, bb.Structure); err != nil {
+			return fmt.Errorf("%s Structure: %s", name, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package dataset
+
+import (
+	"testing"
+)
+
+func TestValidBodyName(t *testing.T) {
+	cases := []struct {
+		name string
+		err  string
+	}{
+		{"errata", ""},
+		{"code_book-2", ""},
+		{"", "invalid body name '': names may only contain letters, numbers, underscores & dashes"},
+		{"../body", "invalid body name '../body': names may only contain letters, numbers, underscores & dashes"},
+		{"with space", "invalid body name 'with space': names may only contain letters, numbers, underscores & dashes"},
+	}
+
+	for i, c := range cases {
+		err := ValidBodyName(c.name)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestCompareNamedBodies(t *testing.T) {
+	cases := []struct {
+		a, b map[string]*NamedBody
+		err  string
+	}{
+		{nil, nil, ""},
+		{nil, map[string]*NamedBody{}, ""},
+		{map[string]*NamedBody{"a": {Path: "/a"}}, nil, "length: 1 != 0"},
+		{map[string]*NamedBody{"a": {Path: "/a"}}, map[string]*NamedBody{"b": {Path: "/a"}}, "a: missing"},
+		{map[string]*NamedBody{"a": {Path: "/a"}}, map[string]*NamedBody{"a": {Path: "/b"}}, "a Path: /a != /b"},
+		{map[string]*NamedBody{"a": {Structure: &Structure{Format: "csv"}}}, map[string]*NamedBody{"a": {Structure: &Structure{Format: "json"}}}, "a Structure: Format: csv != json"},
+		{map[string]*NamedBody{"a": {Path: "/a", Structure: &Structure{Format: "csv"}}}, map[string]*NamedBody{"a": {Path: "/a", Structure: &Structure{Format: "csv"}}}, ""},
+	}
+
+	for i, c := range cases {
+		err := CompareNamedBodies(c.a, c.b)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package dataset
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxDatasetNameLength is the longest a dataset name can be
+const MaxDatasetNameLength = 144
+
+// datasetNameRegex matches valid dataset names
+var datasetNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_\-]*$`)
+
+// ValidDatasetName checks a name can be used to reference a dataset. names
+// must start with a letter, contain only letters, numbers, underscores &
+// dashes, and be no more than 144 characters
+func ValidDatasetName(name string) error {
+	if name == "" {
+		return fmt.Errorf("dataset name cannot be empty")
+	}
+	if len(name) > MaxDatasetNameLength {
+		return fmt.Errorf("invalid dataset name '%s': names can be at most %d characters", name, MaxDatasetNameLength)
+	}
+	if !datasetNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid dataset name '%s': names must start with a letter and contain only letters, numbers, underscores & dashes", name)
+	}
+	return nil
+}
+
+// NormalizeDatasetName converts a string to a valid dataset name. names are
+// lowercased, runs of other characters become a single underscore and
+// leading characters that aren't letters are dropped. It errors if no letters
+// remain
+func NormalizeDatasetName(s string) (string, error) {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'):
+			if b.Len() == 0 && !unicode.IsLetter(r) {
+				continue
+			}
+			if sep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			sep = false
+			b.WriteRune(r)
+		default:
+			sep = true
+		}
+	}
+	name := b.String()
+	if len(name) > MaxDatasetNameLength {
+		name = name[:MaxDatasetNameLength]
+	}
+	if name == "" {
+		return "", fmt.Errorf("cannot make a dataset name from '%s': no letters", s)
+	}
+	return name, nil
+}
+
+// ValidObjectKey checks a string can key an entry of a body with an object
+// top-level type. keys must be non-empty, valid UTF-8 so they round-trip as
+// JSON object keys
+func ValidObjectKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("entry key cannot be empty")
+	}
+	if !utf8.ValidString(key) {
+		return fmt.Errorf("invalid entry key %q: keys must be valid UTF-8", key)
+	}
+	return nil
+}
+
+// ValidStorePath checks a path can address a file in a store, like
+// "/ipfs/QmHash/dataset.json". paths can't be empty, contain whitespace or
+// control characters, or have "." or ".." segments
+func ValidStorePath(p string) error {
+	if p == "" {
+		return fmt.Errorf("path cannot be empty")
+	}
+	for _, r := range p {
+		if unicode.IsSpace(r) || unicode.IsControl(r) || r == utf8.RuneError {
+			return fmt.Errorf("invalid path %q: paths can't contain whitespace or control characters", p)
+		}
+	}
+	for _, seg := range strings.Split(p, "/") {
+		if seg == "." || seg == ".." {
+			return fmt.Errorf("invalid path %q: paths can't contain relative segments", p)
+		}
+	}
+	return nil
+}
+
+// NormalizeStorePath trims surrounding whitespace from a path & removes
+// duplicate & trailing slashes, returning an error if the result isn't a
+// valid store path
+func NormalizeStorePath(p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if err := ValidStorePath(p); err != nil {
+		return "", err
+	}
+	return path.Clean(p), nil
+}
+
+// unmarshalPathRef decodes a component serialized as a path string. ok is
+// false if data isn't a string. paths are kept as-is so documents round trip,
+// they're checked by validate & when loaded from a store
+func unmarshalPathRef(data []byte) (p string, ok bool) {
+	if err := json.Unmarshal(data, &p); err != nil {
+		return "", false
+	}
+	return p, true
+}
@@ -0,0 +1,118 @@
+package dataset
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidDatasetName(t *testing.T) {
+	cases := []struct {
+		name string
+		err  string
+	}{
+		{"airport_codes", ""},
+		{"World-Bank-2019", ""},
+		{"a", ""},
+		{strings.Repeat("a", 144), ""},
+		{"", "dataset name cannot be empty"},
+		{"2019_data", "invalid dataset name '2019_data': names must start with a letter and contain only letters, numbers, underscores & dashes"},
+		{"my data", "invalid dataset name 'my data': names must start with a letter and contain only letters, numbers, underscores & dashes"},
+		{"données", "invalid dataset name 'données': names must start with a letter and contain only letters, numbers, underscores & dashes"},
+		{strings.Repeat("a", 145), "invalid dataset name '" + strings.Repeat("a", 145) + "': names can be at most 144 characters"},
+	}
+	for i, c := range cases {
+		err := ValidDatasetName(c.name)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestNormalizeDatasetName(t *testing.T) {
+	cases := []struct {
+		in, expect, err string
+	}{
+		{"airport_codes", "airport_codes", ""},
+		{"  Airport Codes (2019)  ", "airport_codes_2019", ""},
+		{"2019 World-Bank data!", "world-bank_data", ""},
+		{"données publiques", "donn_es_publiques", ""},
+		{strings.Repeat("ab", 100), strings.Repeat("ab", 72), ""},
+		{"", "", "cannot make a dataset name from '': no letters"},
+		{"1234 !!", "", "cannot make a dataset name from '1234 !!': no letters"},
+	}
+	for i, c := range cases {
+		got, err := NormalizeDatasetName(c.in)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("case %d mismatch. expected: %q, got: %q", i, c.expect, got)
+		}
+		if c.err == "" {
+			if err := ValidDatasetName(got); err != nil {
+				t.Errorf("case %d normalized name isn't valid: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidObjectKey(t *testing.T) {
+	cases := []struct {
+		key string
+		err string
+	}{
+		{"a", ""},
+		{"with spaces & ünicode", ""},
+		{"", "entry key cannot be empty"},
+		{"bad\xff", `invalid entry key "bad\xff": keys must be valid UTF-8`},
+	}
+	for i, c := range cases {
+		err := ValidObjectKey(c.key)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestNormalizeStorePath(t *testing.T) {
+	cases := []struct {
+		in, expect, err string
+	}{
+		{"/ipfs/QmHash", "/ipfs/QmHash", ""},
+		{" /ipfs//QmHash/dataset.json/ ", "/ipfs/QmHash/dataset.json", ""},
+		{"QmHash", "QmHash", ""},
+		{"", "", "path cannot be empty"},
+		{"/ipfs/Qm Hash", "", `invalid path "/ipfs/Qm Hash": paths can't contain whitespace or control characters`},
+		{"/ipfs/QmHash/../QmOther", "", `invalid path "/ipfs/QmHash/../QmOther": paths can't contain relative segments`},
+		{"./QmHash", "", `invalid path "./QmHash": paths can't contain relative segments`},
+	}
+	for i, c := range cases {
+		got, err := NormalizeStorePath(c.in)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("case %d mismatch. expected: %q, got: %q", i, c.expect, got)
+		}
+	}
+}
+
+func TestUnmarshalPathRefs(t *testing.T) {
+	// path references are decoded as-is, validate checks them
+	for _, p := range []string{"/map//QmHash/", "/map/../QmHash", " /map/QmHash", ""} {
+		data, _ := json.Marshal(p)
+		for _, v := range []interface{}{&Dataset{}, &Commit{}, &Meta{}, &Structure{}, &Transform{}, &TransformResource{}, &Viz{}} {
+			if err := json.Unmarshal(data, v); err != nil {
+				t.Errorf("%T unmarshaling %q: unexpected error: %s", v, p, err)
+				continue
+			}
+			if got := reflect.ValueOf(v).Elem().FieldByName("Path").String(); got != p {
+				t.Errorf("%T path mismatch. expected: %q, got: %q", v, p, got)
+			}
+		}
+	}
+}
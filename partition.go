@@ -0,0 +1,107 @@
+package dataset
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Partition granularities truncate date & time values to a period
+const (
+	PartitionYear  = "year"
+	PartitionMonth = "month"
+	PartitionDay   = "day"
+	PartitionHour  = "hour"
+)
+
+// partitionLayouts maps granularities to the layout of their keys. keys of
+// each layout sort in time order
+var partitionLayouts = map[string]string{
+	PartitionYear:  "2006",
+	PartitionMonth: "2006-01",
+	PartitionDay:   "2006-01-02",
+	PartitionHour:  "2006-01-02T15",
+}
+
+// timestampLayouts are the layouts date & time strings are parsed with
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Partition configures a body to be stored as shards of entries with the same
+// partition key. Keys are the value of a column, optionally truncated to a
+// period of time. Shards are ordered by key, comparing keys as strings
+type Partition struct {
+	// Column is the title of the tabular column, or the property of object
+	// entries, to partition by
+	Column string `json:"column"`
+	// Granularity truncates date & time values of the column to a "year",
+	// "month", "day" or "hour". values are used as-is when empty
+	Granularity string `json:"granularity,omitempty"`
+}
+
+// Validate checks a partition is configured correctly
+func (p *Partition) Validate() error {
+	if p.Column == "" {
+		return fmt.Errorf("partition column is required")
+	}
+	if _, ok := partitionLayouts[p.Granularity]; p.Granularity != "" && !ok {
+		return fmt.Errorf("invalid partition granularity '%s': must be one of year, month, day or hour", p.Granularity)
+	}
+	return nil
+}
+
+// Key gives the partition key of a column value. null values have an empty
+// key. With a granularity values must be date & time strings, which are
+// converted to UTC & truncated, eg: "2020-03-14T15:09:26Z" has the day key
+// "2020-03-14"
+func (p *Partition) Key(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	if p.Granularity != "" {
+		layout, ok := partitionLayouts[p.Granularity]
+		if !ok {
+			return "", fmt.Errorf("invalid partition granularity '%s'", p.Granularity)
+		}
+		var t time.Time
+		switch x := v.(type) {
+		case time.Time:
+			t = x
+		case string:
+			var err error
+			if t, err = parseTimestamp(x); err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("partition column %s: expected a date or time string, got %T", p.Column, v)
+		}
+		return t.UTC().Format(layout), nil
+	}
+
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case int:
+		return strconv.Itoa(x), nil
+	case int64:
+		return strconv.FormatInt(x, 10), nil
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	}
+	return "", fmt.Errorf("partition column %s: can't partition by %T values", p.Column, v)
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date or time '%s'", s)
+}
@@ -0,0 +1,61 @@
+package dataset
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionValidate(t *testing.T) {
+	cases := []struct {
+		p   *Partition
+		err string
+	}{
+		{&Partition{Column: "day"}, ""},
+		{&Partition{Column: "day", Granularity: PartitionHour}, ""},
+		{&Partition{}, "partition column is required"},
+		{&Partition{Column: "day", Granularity: "week"}, "invalid partition granularity 'week': must be one of year, month, day or hour"},
+	}
+	for i, c := range cases {
+		err := c.p.Validate()
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestPartitionKey(t *testing.T) {
+	cases := []struct {
+		granularity string
+		val         interface{}
+		expect      string
+		err         string
+	}{
+		{"", "north", "north", ""},
+		{"", 12, "12", ""},
+		{"", int64(-3), "-3", ""},
+		{"", 1.5, "1.5", ""},
+		{"", true, "true", ""},
+		{"", nil, "", ""},
+		{"", []interface{}{}, "", "partition column c: can't partition by []interface {} values"},
+		{PartitionYear, "2020-03-14", "2020", ""},
+		{PartitionMonth, "2020-03-14T15:09:26Z", "2020-03", ""},
+		{PartitionDay, "2020-03-14T23:30:00-05:00", "2020-03-15", ""},
+		{PartitionDay, "2020-03-14 08:00:00", "2020-03-14", ""},
+		{PartitionHour, time.Date(2020, 3, 14, 15, 9, 0, 0, time.UTC), "2020-03-14T15", ""},
+		{PartitionDay, nil, "", ""},
+		{PartitionDay, "yesterday", "", "invalid date or time 'yesterday'"},
+		{PartitionDay, 20200314, "", "partition column c: expected a date or time string, got int"},
+		{"week", "2020-03-14", "", "invalid partition granularity 'week'"},
+	}
+	for i, c := range cases {
+		p := &Partition{Column: "c", Granularity: c.granularity}
+		got, err := p.Key(c.val)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("case %d key mismatch. expected: '%s', got: '%s'", i, c.expect, got)
+		}
+	}
+}
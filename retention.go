@@ -0,0 +1,91 @@
+package dataset
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Retention is a policy for how long body entries are kept, for datasets
+// subject to data-retention rules. An entry expires once the timestamp in
+// it's retention column is older than the policy's maximum age
+type Retention struct {
+	// Column is the title of the tabular column, or the property of object
+	// entries, holding each entry's date or time
+	Column string `json:"column"`
+	// MaxAge is how long entries are kept as an ISO 8601 duration, eg: "P90D"
+	// or "P1Y6M". years, months & days are calendar periods
+	MaxAge string `json:"maxAge"`
+}
+
+// isoDuration matches ISO 8601 durations with whole number components
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// Validate checks a retention policy is configured correctly
+func (r *Retention) Validate() error {
+	if r.Column == "" {
+		return fmt.Errorf("retention column is required")
+	}
+	_, err := r.Cutoff(time.Time{})
+	return err
+}
+
+// Cutoff gives the time entries expire before, MaxAge before now
+func (r *Retention) Cutoff(now time.Time) (time.Time, error) {
+	m := isoDuration.FindStringSubmatch(r.MaxAge)
+	if m == nil || r.MaxAge == "P" || r.MaxAge[len(r.MaxAge)-1] == 'T' {
+		return time.Time{}, fmt.Errorf("invalid retention maxAge '%s': must be an ISO 8601 duration like P90D", r.MaxAge)
+	}
+	n := make([]int, len(m)-1)
+	for i, s := range m[1:] {
+		if s == "" {
+			continue
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid retention maxAge '%s': %w", r.MaxAge, err)
+		}
+		n[i] = v
+	}
+
+	years, months, weeks, days, hours, minutes, seconds := n[0], n[1], n[2], n[3], n[4], n[5], n[6]
+	cutoff := now.AddDate(-years, -months, -(weeks*7 + days))
+	return cutoff.Add(-(time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)), nil
+}
+
+// Expired reports whether an entry with the retention column value v has
+// expired by cutoff. Values must be date & time strings or time.Time, null
+// values never expire
+func (r *Retention) Expired(v interface{}, cutoff time.Time) (bool, error) {
+	var t time.Time
+	switch x := v.(type) {
+	case nil:
+		return false, nil
+	case time.Time:
+		t = x
+	case string:
+		var err error
+		if t, err = parseTimestamp(x); err != nil {
+			return false, fmt.Errorf("retention column %s: %w", r.Column, err)
+		}
+	default:
+		return false, fmt.Errorf("retention column %s: expected a date or time string, got %T", r.Column, v)
+	}
+	return t.Before(cutoff), nil
+}
+
+// Decode reads json.Umarshal-style data into a Retention
+func (r *Retention) Decode(val interface{}) (err error) {
+	msi, ok := val.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected map[string]interface{}")
+	}
+	if r.Column, err = strVal(msi["column"]); err != nil {
+		return
+	}
+	if r.MaxAge, err = strVal(msi["maxAge"]); err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,84 @@
+package dataset
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionValidate(t *testing.T) {
+	cases := []struct {
+		r   Retention
+		err string
+	}{
+		{Retention{Column: "created", MaxAge: "P90D"}, ""},
+		{Retention{Column: "created", MaxAge: "P1Y2M3W4DT5H6M7S"}, ""},
+		{Retention{Column: "created", MaxAge: "PT12H"}, ""},
+		{Retention{MaxAge: "P90D"}, "retention column is required"},
+		{Retention{Column: "created"}, "invalid retention maxAge '': must be an ISO 8601 duration like P90D"},
+		{Retention{Column: "created", MaxAge: "P"}, "invalid retention maxAge 'P': must be an ISO 8601 duration like P90D"},
+		{Retention{Column: "created", MaxAge: "P1DT"}, "invalid retention maxAge 'P1DT': must be an ISO 8601 duration like P90D"},
+		{Retention{Column: "created", MaxAge: "90 days"}, "invalid retention maxAge '90 days': must be an ISO 8601 duration like P90D"},
+	}
+
+	for i, c := range cases {
+		err := c.r.Validate()
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+		}
+	}
+}
+
+func TestRetentionCutoff(t *testing.T) {
+	now := time.Date(2020, 3, 31, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		maxAge string
+		expect time.Time
+	}{
+		{"P90D", time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{"P1Y", time.Date(2019, 3, 31, 12, 0, 0, 0, time.UTC)},
+		{"P1W", time.Date(2020, 3, 24, 12, 0, 0, 0, time.UTC)},
+		{"PT1H30M", time.Date(2020, 3, 31, 10, 30, 0, 0, time.UTC)},
+	}
+
+	for i, c := range cases {
+		r := &Retention{Column: "created", MaxAge: c.maxAge}
+		got, err := r.Cutoff(now)
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if !got.Equal(c.expect) {
+			t.Errorf("case %d cutoff mismatch. expected: %s, got: %s", i, c.expect, got)
+		}
+	}
+}
+
+func TestRetentionExpired(t *testing.T) {
+	r := &Retention{Column: "created", MaxAge: "P1D"}
+	cutoff := time.Date(2020, 3, 14, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		v      interface{}
+		expect bool
+		err    string
+	}{
+		{"2020-03-13", true, ""},
+		{"2020-03-14", false, ""},
+		{"2020-03-13T23:59:59Z", true, ""},
+		{"2020-03-14T01:00:00+02:00", true, ""},
+		{time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC), false, ""},
+		{nil, false, ""},
+		{"yesterday", false, "retention column created: invalid date or time 'yesterday'"},
+		{1584144000, false, "retention column created: expected a date or time string, got int"},
+	}
+
+	for i, c := range cases {
+		got, err := r.Expired(c.v, cutoff)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%v'", i, c.err, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("case %d expected expired to be %t", i, c.expect)
+		}
+	}
+}
@@ -31,8 +31,13 @@ type Structure struct {
 	// file this structure points to. This is different from IPFS
 	// hashes, which are calculated after breaking the file into blocks
 	Checksum string `json:"checksum,omitempty"`
+	// ChunkChecksums optionally records checksums of fixed-size chunks of the
+	// data file, localizing corruption to a chunk
+	ChunkChecksums *ChunkChecksums `json:"chunkChecksums,omitempty"`
 	// Compression specifies any compression on the source data,
-	// if empty assume no compression
+	// if empty assume no compression. dsio reads "gzip", "bzip2", and "zstd"
+	// bodies, and writes "gzip" and "zstd" bodies. Other values, like "tar",
+	// are read & written unchanged
 	Compression string `json:"compression,omitempty"`
 	// Maximum nesting level of composite types in the dataset. eg: depth 1 == [], depth 2 == [[]]
 	Depth int `json:"depth,omitempty"`
@@ -54,6 +59,9 @@ type Structure struct {
 	// Length is the length of the data object in bytes.
 	// must always match & be present
 	Length int `json:"length,omitempty"`
+	// Partition optionally splits the body into shards of entries that share a
+	// partition key, so ranges of partitions can be read on their own
+	Partition *Partition `json:"partition,omitempty"`
 	// location of this structure, transient
 	Path string `json:"path,omitempty"`
 	// Qri should always be KindStructure
@@ -140,46 +148,38 @@ type _structure Structure
 
 // MarshalJSON satisfies the json.Marshaler interface
 func (s Structure) MarshalJSON() (data []byte, err error) {
+	return s.MarshalJSONPolicy(OmitEmpty)
+}
+
+// MarshalJSONPolicy encodes a structure, writing empty fields according to
+// p. Structures that only have a path encode as the path string
+func (s Structure) MarshalJSONPolicy(p EmptyPolicy) ([]byte, error) {
 	if s.Path != "" && s.Encoding == "" && s.Schema == nil {
 		return json.Marshal(s.Path)
 	}
 
-	return s.MarshalJSONObject()
+	return s.marshalObject(p)
 }
 
 // MarshalJSONObject always marshals to a json Object, even if meta is empty or a reference
 func (s Structure) MarshalJSONObject() ([]byte, error) {
+	return s.marshalObject(OmitEmpty)
+}
+
+// marshalObject encodes all fields but the transient path as a json object
+func (s Structure) marshalObject(p EmptyPolicy) ([]byte, error) {
 	kind := s.Qri
 	if kind == "" {
 		kind = KindStructure.String()
 	}
 
-	var opt map[string]interface{}
-	if s.FormatConfig != nil {
-		opt = s.FormatConfig
-	}
-
-	return json.Marshal(&_structure{
-		Checksum:     s.Checksum,
-		Compression:  s.Compression,
-		Depth:        s.Depth,
-		Encoding:     s.Encoding,
-		Entries:      s.Entries,
-		ErrCount:     s.ErrCount,
-		Format:       s.Format,
-		FormatConfig: opt,
-		Length:       s.Length,
-		Qri:          kind,
-		Schema:       s.Schema,
-	})
+	return marshalFields(_structure(s), p, map[string]interface{}{"qri": kind}, "path")
 }
 
 // UnmarshalJSON satisfies the json.Unmarshaler interface
 func (s *Structure) UnmarshalJSON(data []byte) (err error) {
-	var str string
-
-	if err := json.Unmarshal(data, &str); err == nil {
-		*s = Structure{Path: str}
+	if path, ok := unmarshalPathRef(data); ok {
+		*s = Structure{Path: path}
 		return nil
 	}
 
@@ -195,6 +195,7 @@ func (s *Structure) UnmarshalJSON(data []byte) (err error) {
 // IsEmpty checks to see if structure has any fields other than the internal path
 func (s *Structure) IsEmpty() bool {
 	return s.Checksum == "" &&
+		s.ChunkChecksums == nil &&
 		s.Compression == "" &&
 		s.Depth == 0 &&
 		s.Encoding == "" &&
@@ -203,6 +204,7 @@ func (s *Structure) IsEmpty() bool {
 		s.Format == "" &&
 		s.FormatConfig == nil &&
 		s.Length == 0 &&
+		s.Partition == nil &&
 		s.Schema == nil
 }
 
@@ -220,6 +222,9 @@ func (s *Structure) Assign(structures ...*Structure) {
 		if st.Checksum != "" {
 			s.Checksum = st.Checksum
 		}
+		if st.ChunkChecksums != nil {
+			s.ChunkChecksums = st.ChunkChecksums
+		}
 		if st.Compression != "" {
 			s.Compression = st.Compression
 		}
@@ -247,6 +252,9 @@ func (s *Structure) Assign(structures ...*Structure) {
 		if st.Length != 0 {
 			s.Length = st.Length
 		}
+		if st.Partition != nil {
+			s.Partition = st.Partition
+		}
 		// TODO - fix me
 		if st.Schema != nil {
 			// if s.Schema == nil {
@@ -258,6 +266,20 @@ func (s *Structure) Assign(structures ...*Structure) {
 	}
 }
 
+// ChunkChecksumSHA256 is the only supported chunk checksum algorithm
+const ChunkChecksumSHA256 = "sha256"
+
+// ChunkChecksums are checksums of consecutive fixed-size chunks of a data
+// file. The last chunk may be shorter than ChunkSize
+type ChunkChecksums struct {
+	// Algorithm used to calculate checksums, default is sha256
+	Algorithm string `json:"algorithm,omitempty"`
+	// ChunkSize is the length of each chunk in bytes
+	ChunkSize int `json:"chunkSize"`
+	// Sums are hex-encoded checksums of each chunk, in order
+	Sums []string `json:"sums,omitempty"`
+}
+
 // UnmarshalStructure tries to extract a structure type from an empty
 // interface. Pairs nicely with datastore.Get() from github.com/ipfs/go-datastore
 func UnmarshalStructure(v interface{}) (*Structure, error) {
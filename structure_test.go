@@ -73,6 +73,7 @@ func TestStructureIsEmpty(t *testing.T) {
 		st *Structure
 	}{
 		{&Structure{Checksum: "a"}},
+		{&Structure{ChunkChecksums: &ChunkChecksums{ChunkSize: 1}}},
 		{&Structure{Compression: compression.Tar.String()}},
 		{&Structure{Depth: 1}},
 		{&Structure{Encoding: "a"}},
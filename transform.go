@@ -96,9 +96,8 @@ type transformResource TransformResource
 // UnmarshalJSON implements json.Unmarshaler, allowing both string and object
 // representations
 func (r *TransformResource) UnmarshalJSON(data []byte) error {
-	var s string
-	if err := json.Unmarshal(data, &s); err == nil {
-		*r = TransformResource{Path: s}
+	if path, ok := unmarshalPathRef(data); ok {
+		*r = TransformResource{Path: path}
 		return nil
 	}
 
@@ -203,9 +202,16 @@ func (q Transform) MarshalJSONObject() ([]byte, error) {
 	if kind == "" {
 		kind = KindTransform.String()
 	}
+	// canonical config values keep hashes of equivalent configs the same.
+	// values of other types marshal as they encode to JSON, validate.Transform
+	// is strict about them
+	cfg, err := canonicalConfig(q.Config, true)
+	if err != nil {
+		return nil, fmt.Errorf("transform config: %w", err)
+	}
 
 	return json.Marshal(&_transform{
-		Config:        q.Config,
+		Config:        cfg,
 		Path:          q.Path,
 		Qri:           kind,
 		Resources:     q.Resources,
@@ -218,9 +224,8 @@ func (q Transform) MarshalJSONObject() ([]byte, error) {
 
 // UnmarshalJSON satisfies the json.Unmarshaler interface
 func (q *Transform) UnmarshalJSON(data []byte) error {
-	var s string
-	if err := json.Unmarshal(data, &s); err == nil {
-		*q = Transform{Path: s}
+	if path, ok := unmarshalPathRef(data); ok {
+		*q = Transform{Path: path}
 		return nil
 	}
 
@@ -20,6 +20,13 @@ func Dataset(ds *dataset.Dataset) error {
 	// 	}
 	// }
 
+	if ds.Name != "" {
+		if err := dataset.ValidDatasetName(ds.Name); err != nil {
+			log.Debug(err.Error())
+			return err
+		}
+	}
+
 	if ds.Commit == nil {
 		err := fmt.Errorf("commit is required")
 		log.Debug(err.Error())
@@ -36,6 +43,70 @@ func Dataset(ds *dataset.Dataset) error {
 	} else if err := Structure(ds.Structure); err != nil {
 		return fmt.Errorf("structure: %s", err.Error())
 	}
+	if err := Meta(ds.Meta); err != nil {
+		return fmt.Errorf("meta: %w", err)
+	}
+	if err := Transform(ds.Transform); err != nil {
+		return fmt.Errorf("transform: %w", err)
+	}
+	if ds.Viz != nil {
+		if err := refPath(ds.Viz.Path); err != nil {
+			return fmt.Errorf("viz: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// refPath checks the path of a component reference is a valid store path.
+// paths are optional
+func refPath(p string) error {
+	if p == "" {
+		return nil
+	}
+	return dataset.ValidStorePath(p)
+}
+
+// Meta checks that dataset metadata is valid for use
+// returning the first error encountered, nil if valid
+func Meta(md *dataset.Meta) error {
+	if md == nil {
+		return nil
+	}
+	if err := refPath(md.Path); err != nil {
+		return err
+	}
+
+	if md.Retention != nil {
+		if err := md.Retention.Validate(); err != nil {
+			return fmt.Errorf("retention: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Transform checks that a transform's config can be canonicalized for
+// hashing, returning the first error encountered, nil if valid
+func Transform(q *dataset.Transform) error {
+	if q == nil {
+		return nil
+	}
+	if err := refPath(q.Path); err != nil {
+		return err
+	}
+	for name, r := range q.Resources {
+		if r == nil {
+			continue
+		}
+		if err := refPath(r.Path); err != nil {
+			return fmt.Errorf("resource %s: %w", name, err)
+		}
+	}
+
+	if _, err := dataset.CanonicalConfig(q.Config); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -46,6 +117,9 @@ func Commit(cm *dataset.Commit) error {
 	if cm == nil {
 		return nil
 	}
+	if err := refPath(cm.Path); err != nil {
+		return err
+	}
 
 	if cm.Title == "" {
 		// return fmt.Errorf("title is required")
@@ -63,6 +137,9 @@ func Structure(s *dataset.Structure) error {
 	if s == nil {
 		return nil
 	}
+	if err := refPath(s.Path); err != nil {
+		return err
+	}
 
 	df := s.DataFormat()
 	if df == dataset.UnknownDataFormat {
@@ -77,6 +154,12 @@ func Structure(s *dataset.Structure) error {
 		return fmt.Errorf("schema: %s", err.Error())
 	}
 
+	if s.Partition != nil {
+		if err := s.Partition.Validate(); err != nil {
+			return fmt.Errorf("partition: %w", err)
+		}
+	}
+
 	return nil
 }
 
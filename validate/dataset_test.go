@@ -23,6 +23,14 @@ func TestDataset(t *testing.T) {
 		{&dataset.Dataset{Commit: cm, Structure: &dataset.Structure{}}, "structure: format is required"},
 		// {&dataset.Dataset{Commit: cm, Abstract: &dataset.Dataset{Metadata: &dataset.Metadata{}}}, "abstract field is not an abstract dataset. Metadata: nil: <not nil> != <nil>"},
 		{&dataset.Dataset{Commit: cm, Structure: st}, ""},
+		{&dataset.Dataset{Name: "airport_codes", Commit: cm, Structure: st}, ""},
+		{&dataset.Dataset{Name: "airport codes", Commit: cm, Structure: st}, "invalid dataset name 'airport codes': names must start with a letter and contain only letters, numbers, underscores & dashes"},
+		{&dataset.Dataset{Commit: cm, Structure: st, Meta: &dataset.Meta{Retention: &dataset.Retention{Column: "created"}}}, "meta: retention: invalid retention maxAge '': must be an ISO 8601 duration like P90D"},
+		{&dataset.Dataset{Commit: cm, Structure: st, Transform: &dataset.Transform{Config: map[string]interface{}{"": true}}}, "transform: config: config keys can't be empty"},
+		{&dataset.Dataset{Commit: &dataset.Commit{Path: "/map/../QmHash"}, Structure: st}, `commit: invalid path "/map/../QmHash": paths can't contain relative segments`},
+		{&dataset.Dataset{Commit: cm, Structure: st, Meta: &dataset.Meta{Path: "/map/Qm Hash"}}, `meta: invalid path "/map/Qm Hash": paths can't contain whitespace or control characters`},
+		{&dataset.Dataset{Commit: cm, Structure: st, Viz: &dataset.Viz{Path: "/map/./QmHash"}}, `viz: invalid path "/map/./QmHash": paths can't contain relative segments`},
+		{&dataset.Dataset{Commit: cm, Structure: st, Transform: &dataset.Transform{Resources: map[string]*dataset.TransformResource{"a": {Path: "../a"}}}}, `transform: resource a: invalid path "../a": paths can't contain relative segments`},
 	}
 
 	for i, c := range cases {
@@ -54,6 +62,26 @@ func TestCommit(t *testing.T) {
 	}
 }
 
+func TestMeta(t *testing.T) {
+	cases := []struct {
+		md  *dataset.Meta
+		err string
+	}{
+		{nil, ""},
+		{&dataset.Meta{}, ""},
+		{&dataset.Meta{Retention: &dataset.Retention{Column: "created", MaxAge: "P1Y"}}, ""},
+		{&dataset.Meta{Retention: &dataset.Retention{MaxAge: "P1Y"}}, "retention: retention column is required"},
+	}
+
+	for i, c := range cases {
+		err := Meta(c.md)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+			continue
+		}
+	}
+}
+
 func TestStructure(t *testing.T) {
 	cases := []struct {
 		st  *dataset.Structure
@@ -64,6 +92,7 @@ func TestStructure(t *testing.T) {
 		{&dataset.Structure{Format: "csv"}, "csv data format requires a schema"},
 		// {&dataset.Structure{Format: "csv"}, "schema: fields are required"},
 		{&dataset.Structure{Format: "json", Schema: map[string]interface{}{"type": "array"}}, ""},
+		{&dataset.Structure{Format: "json", Schema: map[string]interface{}{"type": "array"}, Partition: &dataset.Partition{}}, "partition: partition column is required"},
 	}
 
 	for i, c := range cases {
@@ -131,3 +160,22 @@ func TestStructure(t *testing.T) {
 // 	}
 // 	return fields
 // }
+
+func TestTransform(t *testing.T) {
+	cases := []struct {
+		q   *dataset.Transform
+		err string
+	}{
+		{nil, ""},
+		{&dataset.Transform{}, ""},
+		{&dataset.Transform{Config: map[string]interface{}{"limit": 10, "opts": map[string]interface{}{"a": 1.5}}}, ""},
+		{&dataset.Transform{Config: map[string]interface{}{"opts": map[string]interface{}{"a": func() {}}}}, "config.opts.a: unsupported config value type func()"},
+	}
+
+	for i, c := range cases {
+		err := Transform(c.q)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case %d error mismatch. expected: '%s', got: '%s'", i, c.err, err)
+		}
+	}
+}
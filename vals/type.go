@@ -218,14 +218,146 @@ func ParseString(value []byte) (string, error) {
 
 // ParseNumber converts raw bytes to a float64 value
 func ParseNumber(value []byte) (float64, error) {
+	if f, ok := parseFloatFast(value); ok {
+		return f, nil
+	}
 	return strconv.ParseFloat(string(value), 64)
 }
 
 // ParseInteger converts raw bytes to a int64 value
 func ParseInteger(value []byte) (int64, error) {
+	if i, ok := parseIntFast(value); ok {
+		return i, nil
+	}
 	return strconv.ParseInt(string(value), 10, 64)
 }
 
+// float64pow10 are the powers of ten that are exactly representable as a
+// float64
+var float64pow10 = [...]float64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11,
+	1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22,
+}
+
+// parseFloatFast parses common decimal numbers directly from bytes without
+// allocating. When both the mantissa and power of ten are exactly
+// representable a single multiplication or division gives a correctly
+// rounded result. ok is false for anything else, which callers should hand
+// to strconv
+func parseFloatFast(b []byte) (f float64, ok bool) {
+	i := 0
+	neg := false
+	if i < len(b) && (b[i] == '-' || b[i] == '+') {
+		neg = b[i] == '-'
+		i++
+	}
+
+	var (
+		mant     uint64
+		digits   int
+		exp      int
+		sawDigit bool
+	)
+	for ; i < len(b) && b[i] >= '0' && b[i] <= '9'; i++ {
+		sawDigit = true
+		if mant == 0 && b[i] == '0' {
+			continue
+		}
+		// 15 digits always fits in the 53 bit float64 mantissa
+		if digits == 15 {
+			return 0, false
+		}
+		mant = mant*10 + uint64(b[i]-'0')
+		digits++
+	}
+	if i < len(b) && b[i] == '.' {
+		i++
+		for ; i < len(b) && b[i] >= '0' && b[i] <= '9'; i++ {
+			sawDigit = true
+			if mant == 0 && b[i] == '0' {
+				exp--
+				continue
+			}
+			if digits == 15 {
+				return 0, false
+			}
+			mant = mant*10 + uint64(b[i]-'0')
+			digits++
+			exp--
+		}
+	}
+	if !sawDigit {
+		return 0, false
+	}
+
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		expNeg := false
+		if i < len(b) && (b[i] == '-' || b[i] == '+') {
+			expNeg = b[i] == '-'
+			i++
+		}
+		if i == len(b) {
+			return 0, false
+		}
+		e := 0
+		for ; i < len(b) && b[i] >= '0' && b[i] <= '9'; i++ {
+			if e > 1000 {
+				return 0, false
+			}
+			e = e*10 + int(b[i]-'0')
+		}
+		if expNeg {
+			e = -e
+		}
+		exp += e
+	}
+	if i != len(b) {
+		return 0, false
+	}
+
+	if mant != 0 {
+		if exp < -22 || exp > 22 {
+			return 0, false
+		}
+		f = float64(mant)
+		if exp < 0 {
+			f /= float64pow10[-exp]
+		} else {
+			f *= float64pow10[exp]
+		}
+	}
+	if neg {
+		f = -f
+	}
+	return f, true
+}
+
+// parseIntFast parses base 10 integers of up to 18 digits, which can't
+// overflow an int64, directly from bytes. ok is false for anything else
+func parseIntFast(b []byte) (n int64, ok bool) {
+	i := 0
+	neg := false
+	if i < len(b) && (b[i] == '-' || b[i] == '+') {
+		neg = b[i] == '-'
+		i++
+	}
+	if i == len(b) || len(b)-i > 18 {
+		return 0, false
+	}
+	for ; i < len(b); i++ {
+		d := b[i] - '0'
+		if d > 9 {
+			return 0, false
+		}
+		n = n*10 + int64(d)
+	}
+	if neg {
+		n = -n
+	}
+	return n, true
+}
+
 // ParseBoolean converts raw bytes to a bool value
 func ParseBoolean(value []byte) (bool, error) {
 	return strconv.ParseBool(string(value))
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/qri-io/compare"
 	"math"
+	"strconv"
 	"testing"
 )
 
@@ -223,6 +224,50 @@ func TestParseInteger(t *testing.T) {
 	}
 }
 
+func TestParseNumberFastPath(t *testing.T) {
+	inputs := []string{
+		"0", "-0", "+0", "0.0", "1", "-1", "1.5", ".5", "5.", "0.001", "-0.5e3", "1E22",
+		"1e23", "1e-22", "1e-23", "123456789012345", "1234567890123456", "0.1234567890123456",
+		"3.14159265358979", "9007199254740993", "000123.4500", "1e", "1e+", "-", ".", "",
+		"1.2.3", "1x", "inf", "NaN", "0x1p-2", "1_000", "1e400", "0.000000000000000000000000001",
+	}
+	for _, in := range inputs {
+		expect, expectErr := strconv.ParseFloat(in, 64)
+		got, err := ParseNumber([]byte(in))
+		if (err == nil) != (expectErr == nil) {
+			t.Errorf("%q error mismatch. expected: %v, got: %v", in, expectErr, err)
+			continue
+		}
+		if math.Float64bits(got) != math.Float64bits(expect) && !(math.IsNaN(got) && math.IsNaN(expect)) {
+			t.Errorf("%q value mismatch. expected: %v, got: %v", in, expect, got)
+		}
+	}
+}
+
+func TestParseIntegerFastPath(t *testing.T) {
+	inputs := []string{
+		"0", "-0", "+7", "-", "+", "", "123456789012345678", "-123456789012345678",
+		"1234567890123456789", "99999999999999999999", "12a", " 1", "1.0",
+	}
+	for _, in := range inputs {
+		expect, expectErr := strconv.ParseInt(in, 10, 64)
+		got, err := ParseInteger([]byte(in))
+		if (err == nil) != (expectErr == nil) || got != expect {
+			t.Errorf("%q mismatch. expected: %d (%v), got: %d (%v)", in, expect, expectErr, got, err)
+		}
+	}
+}
+
+func BenchmarkParseNumber(b *testing.B) {
+	inputs := [][]byte{[]byte("12345"), []byte("678.901"), []byte("-0.5e3"), []byte("3.14159265358979")}
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		for _, in := range inputs {
+			ParseNumber(in)
+		}
+	}
+}
+
 func TestParseBoolean(t *testing.T) {
 	cases := []struct {
 		input  []byte
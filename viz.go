@@ -158,9 +158,8 @@ func (v *Viz) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON satisfies the json.Unmarshaler interface
 func (v *Viz) UnmarshalJSON(data []byte) error {
-	var s string
-	if err := json.Unmarshal(data, &s); err == nil {
-		*v = Viz{Path: s}
+	if path, ok := unmarshalPathRef(data); ok {
+		*v = Viz{Path: path}
 		return nil
 	}
 